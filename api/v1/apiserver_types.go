@@ -18,13 +18,333 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // APIServerSpec defines the desired state of Tigera API server.
 type APIServerSpec struct {
+	// Audit configures the audit logging of the Tigera API server. This is only supported for Calico Enterprise.
+	// +optional
+	Audit *APIServerAudit `json:"audit,omitempty"`
+
+	// Replicas is the number of replicas of the API server deployment. When omitted, the ControlPlaneReplicas of the
+	// Installation is used. It is ignored when Autoscaling is set.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Autoscaling configures a HorizontalPodAutoscaler that scales the API server deployment with its CPU utilization.
+	// +optional
+	Autoscaling *APIServerAutoscaling `json:"autoscaling,omitempty"`
+
+	// DisabledResources lists the resources of the projectcalico.org/v3 API group that are not served by the API server,
+	// e.g. uisettings and uisettingsgroups on clusters without the Tigera Manager. The resources are given by their
+	// plural name. The remaining resources of the group are still served.
+	// +optional
+	DisabledResources []APIServerResource `json:"disabledResources,omitempty"`
+
+	// HostNetwork configures whether the API server pods run in the host network namespace. Enable it on clusters
+	// where the Kubernetes API server cannot reach pod IPs, e.g. hosted control planes without Calico. The API server
+	// pods are always host networked on EKS with the Calico CNI.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	HostNetwork *APIServerHostNetworkOption `json:"hostNetwork,omitempty"`
+
+	// RequestHandling configures how many requests the API server serves concurrently and for how long.
+	// +optional
+	RequestHandling *APIServerRequestHandling `json:"requestHandling,omitempty"`
+
+	// ComponentResources can be used to customize the resource requirements of the containers of the API server pods.
+	// The QueryServer container is only present for Calico Enterprise.
+	// +optional
+	ComponentResources []APIServerComponentResource `json:"componentResources,omitempty"`
+
+	// QueryServerLogLevel is the log level of the query server. This is only supported for Calico Enterprise.
+	// Default: Info
+	// +optional
+	// +kubebuilder:validation:Enum=Error;Warning;Info;Debug
+	QueryServerLogLevel *QueryServerLogLevel `json:"queryServerLogLevel,omitempty"`
+
+	// TopologySpreadConstraints describes how the API server pods are spread across topology domains, e.g. zones. When
+	// the label selector of a constraint is omitted, it selects the API server pods.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// PodDisruptionBudget configures a PodDisruptionBudget that limits the voluntary disruptions of the API server
+	// pods, e.g. while nodes are drained during upgrades.
+	// +optional
+	PodDisruptionBudget *APIServerPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	// CertificateIssuer configures how the serving certificate of the API server is issued. When omitted, the operator
+	// issues a self-signed certificate. A certificate provided by the user in the tigera-operator namespace takes
+	// precedence over the self-signed and operator CA issued ones. It cannot be combined with the CertificateManagement
+	// of the Installation.
+	// +optional
+	CertificateIssuer *APIServerCertificateIssuer `json:"certificateIssuer,omitempty"`
+
+	// BaselinePolicy configures whether the operator bootstraps a baseline policy set once the API server is
+	// available: a platform tier allowing kube-system, DNS and metrics scraping, and a default deny of the workloads
+	// in the other namespaces than kube-system, calico-system and the tigera- ones, see BaselineDefaultDeny. The
+	// policies are only created when the platform tier doesn't exist, and are managed by the user afterwards. This
+	// is only supported for Calico Enterprise.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	BaselinePolicy *BaselinePolicyType `json:"baselinePolicy,omitempty"`
+
+	// BaselineDefaultDeny configures whether the baseline policy set includes the default deny of the workloads.
+	// Since the default deny cuts the traffic of the running workloads which no policy allows, it is only included
+	// when the baseline policy is bootstrapped on a new install, i.e. before the API server was ever ready, unless
+	// it is set explicitly.
+	// Default: Enabled on a new install, Disabled otherwise
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	BaselineDefaultDeny *BaselinePolicyType `json:"baselineDefaultDeny,omitempty"`
+
+	// NetworkSets are named sets of CIDRs, e.g. corporate ranges or proxies, which the operator renders as
+	// GlobalNetworkSets once the API server is available, so that the policies can select them by their labels. The
+	// GlobalNetworkSets of the sets removed from the list are deleted.
+	// +optional
+	NetworkSets []NetworkSet `json:"networkSets,omitempty"`
+}
+
+// NetworkSet is a named set of CIDRs rendered as a GlobalNetworkSet.
+type NetworkSet struct {
+	// Name is the name of the GlobalNetworkSet.
+	Name string `json:"name"`
+
+	// Labels are set on the GlobalNetworkSet. Policies select the set by these labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Nets are the CIDRs of the set.
+	Nets []string `json:"nets"`
+}
+
+// BaselinePolicyType specifies whether the baseline policy set is bootstrapped.
+// One of: Enabled, Disabled
+type BaselinePolicyType string
+
+const (
+	BaselinePolicyEnabled  BaselinePolicyType = "Enabled"
+	BaselinePolicyDisabled BaselinePolicyType = "Disabled"
+)
+
+// APIServerCertificateIssuer configures the issuer of the serving certificate of the API server. The CA bundle of the
+// APIService is updated to the CA of the issuer.
+type APIServerCertificateIssuer struct {
+	// Type is the kind of issuer. With OperatorCA, the certificate is signed by a CA the operator keeps in the
+	// tigera-operator-ca Secret of the tigera-operator namespace. With CertManager, a cert-manager Certificate is
+	// created in the tigera-operator namespace.
+	// +kubebuilder:validation:Enum=SelfSigned;OperatorCA;CertManager
+	Type CertificateIssuerType `json:"type"`
+
+	// CertManagerIssuerRef refers to the cert-manager issuer signing the certificate. Required when Type is CertManager.
+	// +optional
+	CertManagerIssuerRef *CertManagerIssuerReference `json:"certManagerIssuerRef,omitempty"`
+}
+
+type CertificateIssuerType string
+
+const (
+	CertificateIssuerSelfSigned  CertificateIssuerType = "SelfSigned"
+	CertificateIssuerOperatorCA  CertificateIssuerType = "OperatorCA"
+	CertificateIssuerCertManager CertificateIssuerType = "CertManager"
+)
+
+// CertManagerIssuerReference refers to a cert-manager Issuer in the tigera-operator namespace or a ClusterIssuer.
+type CertManagerIssuerReference struct {
+	// Name of the issuer.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind of the issuer.
+	// Default: Issuer
+	// +optional
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the issuer. Set it for issuers of external cert-manager signers.
+	// Default: cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// APIServerPodDisruptionBudget configures the PodDisruptionBudget of the API server pods. At most one of MinAvailable
+// and MaxUnavailable can be set. When neither is set, at most one API server pod is unavailable at a time.
+type APIServerPodDisruptionBudget struct {
+	// MinAvailable is the number or percentage of API server pods that must remain available.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of API server pods that can be unavailable.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+type APIServerComponentName string
+
+const (
+	ComponentNameAPIServer   APIServerComponentName = "APIServer"
+	ComponentNameQueryServer APIServerComponentName = "QueryServer"
+)
+
+// The APIServerComponentResource struct associates a ResourceRequirements with a container of the API server pods
+// by name.
+type APIServerComponentResource struct {
+	// ComponentName is an enum which identifies the container
+	// +kubebuilder:validation:Enum=APIServer;QueryServer
+	ComponentName APIServerComponentName `json:"componentName"`
+	// ResourceRequirements allows customization of limits and requests for compute resources such as cpu and memory.
+	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements"`
+}
+
+type QueryServerLogLevel string
+
+const (
+	QueryServerLogLevelError   QueryServerLogLevel = "Error"
+	QueryServerLogLevelWarning QueryServerLogLevel = "Warning"
+	QueryServerLogLevelInfo    QueryServerLogLevel = "Info"
+	QueryServerLogLevelDebug   QueryServerLogLevel = "Debug"
+)
+
+// APIServerRequestHandling configures the limits the API server applies to the requests it serves, so that heavy
+// automation cannot starve interactive requests.
+type APIServerRequestHandling struct {
+	// RequestTimeout is the duration after which the API server times out a request. Watch requests are not affected.
+	// Default: 60s
+	// +optional
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// MaxRequestsInFlight is the maximum number of non-mutating requests served concurrently. When priority and
+	// fairness is enabled, it is added to MaxMutatingRequestsInFlight to form the total concurrency limit.
+	// Default: 400
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRequestsInFlight *int32 `json:"maxRequestsInFlight,omitempty"`
+
+	// MaxMutatingRequestsInFlight is the maximum number of mutating requests served concurrently.
+	// Default: 200
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxMutatingRequestsInFlight *int32 `json:"maxMutatingRequestsInFlight,omitempty"`
+
+	// PriorityAndFairness configures whether requests are classified with the FlowSchemas and
+	// PriorityLevelConfigurations of the cluster instead of the plain in-flight limits. When omitted, the default of
+	// the API server is kept.
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	PriorityAndFairness *PriorityAndFairnessOption `json:"priorityAndFairness,omitempty"`
+}
+
+type PriorityAndFairnessOption string
+
+const (
+	PriorityAndFairnessEnabled  PriorityAndFairnessOption = "Enabled"
+	PriorityAndFairnessDisabled PriorityAndFairnessOption = "Disabled"
+)
+
+type APIServerHostNetworkOption string
+
+const (
+	APIServerHostNetworkEnabled  APIServerHostNetworkOption = "Enabled"
+	APIServerHostNetworkDisabled APIServerHostNetworkOption = "Disabled"
+)
+
+// APIServerResource is the plural name of a resource of the projectcalico.org/v3 API group.
+// +kubebuilder:validation:Pattern=`^[a-z]+$`
+type APIServerResource string
+
+// APIServerAutoscaling configures the HorizontalPodAutoscaler of the API server deployment.
+type APIServerAutoscaling struct {
+	// MinReplicas is the lower limit for the number of replicas the API server deployment is scaled down to.
+	// Default: 1
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas the API server deployment is scaled up to. It cannot be
+	// less than MinReplicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilizationPercentage is the average CPU utilization of the API server pods, relative to their CPU
+	// requests, that the autoscaler aims for.
+	// Default: 80
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+}
+
+// APIServerAudit configures which requests to the Tigera API server are audited and where the audit events are sent.
+type APIServerAudit struct {
+	// PolicyConfigMapName is the name of a ConfigMap in the tigera-operator namespace holding the audit policy under the
+	// policy.yaml key. When omitted, changes to policy, tier and network set resources are audited.
+	// +optional
+	PolicyConfigMapName string `json:"policyConfigMapName,omitempty"`
+
+	// Log configures the log file the audit events are written to.
+	// +optional
+	Log *APIServerAuditLog `json:"log,omitempty"`
+
+	// Webhook configures a webhook backend the audit events are sent to, in addition to the log file.
+	// +optional
+	Webhook *APIServerAuditWebhook `json:"webhook,omitempty"`
+}
+
+// APIServerAuditLog configures the audit log file of the Tigera API server.
+type APIServerAuditLog struct {
+	// Path is the absolute path of the audit log file on the host. Its directory is mounted in the API server pods, so
+	// it should be a dedicated directory, which can't be the root, a top-level directory, /var/log or a system
+	// directory such as /etc. Note that the audit logs are only collected by fluentd from the default path.
+	// Default: /var/log/calico/audit/tsee-audit.log
+	// +kubebuilder:validation:Pattern=`^/.+`
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// MaxAge is the maximum number of days to retain rotated audit log files.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxAge *int32 `json:"maxAge,omitempty"`
+
+	// MaxBackups is the maximum number of rotated audit log files to retain.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxBackups *int32 `json:"maxBackups,omitempty"`
+
+	// MaxSize is the maximum size in megabytes of the audit log file before it is rotated.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxSize *int32 `json:"maxSize,omitempty"`
 }
 
+// APIServerAuditWebhook configures the webhook backend of the audit logging of the Tigera API server.
+type APIServerAuditWebhook struct {
+	// ConfigSecretName is the name of a Secret in the tigera-operator namespace holding, under the config key, the
+	// kubeconfig file that describes the webhook backend.
+	ConfigSecretName string `json:"configSecretName"`
+
+	// Mode is the strategy for sending audit events to the webhook backend.
+	// Default: batch
+	// +kubebuilder:validation:Enum=batch;blocking;blocking-strict
+	// +optional
+	Mode *AuditWebhookMode `json:"mode,omitempty"`
+}
+
+// AuditWebhookMode is the strategy for sending audit events to a webhook backend. Valid options are: batch, blocking,
+// blocking-strict
+type AuditWebhookMode string
+
+const (
+	AuditWebhookModeBatch          AuditWebhookMode = "batch"
+	AuditWebhookModeBlocking       AuditWebhookMode = "blocking"
+	AuditWebhookModeBlockingStrict AuditWebhookMode = "blocking-strict"
+)
+
 // APIServerStatus defines the observed state of Tigera API server.
 type APIServerStatus struct {
 	// State provides user-readable status.
@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DataplaneRestartComponent is the dataplane restarted on the nodes.
+// One of: VPP, CalicoNode
+// +kubebuilder:validation:Enum=VPP;CalicoNode
+type DataplaneRestartComponent string
+
+const (
+	DataplaneRestartVPP        DataplaneRestartComponent = "VPP"
+	DataplaneRestartCalicoNode DataplaneRestartComponent = "CalicoNode"
+)
+
+// DataplaneRestartPhase is the progress of a restart.
+type DataplaneRestartPhase string
+
+const (
+	DataplaneRestartRunning   DataplaneRestartPhase = "Running"
+	DataplaneRestartCompleted DataplaneRestartPhase = "Completed"
+	DataplaneRestartFailed    DataplaneRestartPhase = "Failed"
+)
+
+// NodeRestartState is the progress of the restart of a node.
+type NodeRestartState string
+
+const (
+	// NodeRestartPending nodes are waiting for their turn.
+	NodeRestartPending NodeRestartState = "Pending"
+	// NodeRestartRestarting nodes had their pod deleted, and wait for the new pod to be ready.
+	NodeRestartRestarting NodeRestartState = "Restarting"
+	// NodeRestartRestarted nodes have a ready pod created after the restart.
+	NodeRestartRestarted NodeRestartState = "Restarted"
+	// NodeRestartSkipped nodes had no pod to restart when their turn came, or were deleted before their new pod was
+	// ready.
+	NodeRestartSkipped NodeRestartState = "Skipped"
+	// NodeRestartFailed nodes didn't have a ready pod within the timeout of the restart.
+	NodeRestartFailed NodeRestartState = "Failed"
+)
+
+// DataplaneRestartSpec defines the desired state of DataplaneRestart
+type DataplaneRestartSpec struct {
+	// Component is the dataplane restarted: VPP restarts the calico-vpp-node pods, which run VPP and the VPP agent,
+	// and CalicoNode the calico-node pods.
+	// Default: VPP
+	// +optional
+	Component *DataplaneRestartComponent `json:"component,omitempty"`
+
+	// NodeSelector selects the nodes whose dataplane is restarted, among the nodes running a pod of the component
+	// when the restart is created. If omitted, all of them are restarted.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Reason records why the dataplane is restarted, e.g. the reference of the troubleshooting ticket. It is logged
+	// by the operator along with the restart of each node.
+	// +kubebuilder:validation:MinLength=1
+	Reason string `json:"reason"`
+
+	// RateLimit limits how fast the nodes are restarted.
+	// +optional
+	RateLimit *DataplaneRestartRateLimit `json:"rateLimit,omitempty"`
+
+	// TimeoutSeconds is the time the new pod of a restarted node has to be ready. A node whose pod isn't ready in time
+	// fails the restart: the nodes pending aren't restarted.
+	// Default: 600
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// DataplaneRestartRateLimit limits how fast the nodes are restarted. A node restarting counts as unavailable until
+// its new pod is ready, so that a dataplane which doesn't come back halts the restart of the other nodes.
+type DataplaneRestartRateLimit struct {
+	// MaxUnavailable is the number of nodes restarting at once.
+	// Default: 1
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+
+	// IntervalSeconds is the time between the restart of a node and the restart of the next one.
+	// Default: 30
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	IntervalSeconds *int32 `json:"intervalSeconds,omitempty"`
+}
+
+// DataplaneRestartStatus defines the observed state of DataplaneRestart
+type DataplaneRestartStatus struct {
+	// Phase is Running until all the nodes are restarted, then Completed, or Failed when the new pod of a node wasn't
+	// ready within the timeout.
+	// +optional
+	Phase DataplaneRestartPhase `json:"phase,omitempty"`
+
+	// StartTime is when the operator selected the nodes to restart.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the last node was restarted, or when the restart failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Nodes is the progress of the restart of each selected node.
+	// +optional
+	Nodes []NodeRestartStatus `json:"nodes,omitempty"`
+}
+
+// NodeRestartStatus is the progress of the restart of the dataplane of a node.
+type NodeRestartStatus struct {
+	// Node is the name of the node.
+	Node string `json:"node"`
+
+	// State is one of Pending, Restarting, Restarted, Skipped or Failed.
+	State NodeRestartState `json:"state"`
+
+	// Pod is the name of the pod deleted to restart the dataplane of the node.
+	// +optional
+	Pod string `json:"pod,omitempty"`
+
+	// RestartTime is when the pod was deleted.
+	// +optional
+	RestartTime *metav1.Time `json:"restartTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.spec.reason`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+
+// DataplaneRestart restarts the dataplane of the selected nodes, one node after the other, by deleting their
+// calico-vpp-node or calico-node pod and waiting for the new pod to be ready. It is an audited alternative to deleting
+// the pods by hand: the restart is recorded with its reason, and the progress of each node in the status. A restart
+// isn't repeated; create a new DataplaneRestart to restart the nodes again.
+type DataplaneRestart struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DataplaneRestartSpec   `json:"spec,omitempty"`
+	Status DataplaneRestartStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DataplaneRestartList contains a list of DataplaneRestart
+type DataplaneRestartList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DataplaneRestart `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DataplaneRestart{}, &DataplaneRestartList{})
+}
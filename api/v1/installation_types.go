@@ -112,11 +112,13 @@ type InstallationSpec struct {
 
 	// NodeMetricsPort specifies which port calico/node serves prometheus metrics on. By default, metrics are not enabled.
 	// If specified, this overrides any FelixConfiguration resources which may exist. If omitted, then
-	// prometheus metrics may still be configured through FelixConfiguration.
+	// prometheus metrics may still be configured through FelixConfiguration. If specified, the felix metrics are
+	// exposed through the calico-felix-metrics Service in the calico-system namespace.
 	// +optional
 	NodeMetricsPort *int32 `json:"nodeMetricsPort,omitempty"`
 
 	// TyphaMetricsPort specifies which port calico/typha serves prometheus metrics on. By default, metrics are not enabled.
+	// If specified, the metrics are exposed through the calico-typha-metrics Service in the calico-system namespace.
 	// +optional
 	TyphaMetricsPort *int32 `json:"typhaMetricsPort,omitempty"`
 
@@ -126,16 +128,67 @@ type InstallationSpec struct {
 	// +optional
 	FlexVolumePath string `json:"flexVolumePath,omitempty"`
 
+	// PolicySyncDriver selects the volume driver providing the policy sync API of calico-node to the pods, e.g. to
+	// Dikastes. FlexVolume installs the flexvolume driver at the FlexVolumePath. CSI deploys the csi.tigera.io CSI
+	// driver instead, for the platforms where flexvolume is disabled. Both deploys the two drivers, so that the pods
+	// using the flexvolume driver keep working while they move to the CSI driver.
+	// Default: FlexVolume
+	// +optional
+	// +kubebuilder:validation:Enum=FlexVolume;CSI;Both
+	PolicySyncDriver *PolicySyncDriverType `json:"policySyncDriver,omitempty"`
+
+	// KubeletVolumePluginPath optionally specifies the root directory of the kubelet, in which the CSI driver is
+	// registered. Only used when the CSI driver is deployed.
+	// Default: /var/lib/kubelet
+	// +optional
+	KubeletVolumePluginPath string `json:"kubeletVolumePluginPath,omitempty"`
+
 	// NodeUpdateStrategy can be used to customize the desired update strategy, such as the MaxUnavailable
 	// field.
 	// +optional
 	NodeUpdateStrategy appsv1.DaemonSetUpdateStrategy `json:"nodeUpdateStrategy,omitempty"`
 
+	// NodeCanaryRollout enables canary rollouts of calico-node. The calico-node pods of the canary nodes are updated
+	// first, and the pods of the other nodes are only updated once the canaries are healthy. The rollout halts when
+	// an updated pod keeps restarting or does not become ready. When set, only the MaxUnavailable field of the
+	// NodeUpdateStrategy is used.
+	// +optional
+	NodeCanaryRollout *NodeCanaryRollout `json:"nodeCanaryRollout,omitempty"`
+
+	// DataplaneMigration migrates the nodes from the BPF to the VPP dataplane one at a time, once the LinuxDataplane
+	// of the CalicoNetwork is changed to VPP. Each node is tainted, optionally drained, and labeled so that the VPP
+	// dataplane is scheduled there, before its calico-node pod is updated. The taint is removed once the updated
+	// pod is ready and the connectivity test, run from the node, succeeded; the migration halts with the node tainted
+	// when the pod does not become ready or the test fails.
+	// +optional
+	DataplaneMigration *DataplaneMigration `json:"dataplaneMigration,omitempty"`
+
+	// NodeShutdown configures the shutdown of the calico-node pods, so that the connections are drained and the
+	// routes withdrawn before a pod is killed, e.g. with the VPP dataplane.
+	// +optional
+	NodeShutdown *NodeShutdown `json:"nodeShutdown,omitempty"`
+
+	// DataplaneSync tunes how often Felix resyncs the dataplane, e.g. for very large policy sets.
+	// +optional
+	DataplaneSync *DataplaneSync `json:"dataplaneSync,omitempty"`
+
 	// ComponentResources can be used to customize the resource requirements for each component.
 	// Node, Typha, and KubeControllers are supported for installations.
 	// +optional
 	ComponentResources []ComponentResource `json:"componentResources,omitempty"`
 
+	// NamespaceQuotas renders a ResourceQuota and a LimitRange in the calico-system namespace, so that the Calico
+	// components can always be scheduled there. The ResourceQuota is scoped to the priority classes of the Calico
+	// components, which is required where their pods are limited by the admission configuration. The LimitRange
+	// defaults both the requests and the limits of the containers without any to the largest requests of the
+	// ComponentResources, which is required where the resources of the namespace are limited by another
+	// ResourceQuota. The LimitRange is not rendered without ComponentResources. Both are deleted when disabled,
+	// except for the ResourceQuota on GKE.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	NamespaceQuotas *NamespaceQuotasType `json:"namespaceQuotas,omitempty"`
+
 	// CertificateManagement configures pods to submit a CertificateSigningRequest to the certificates.k8s.io/v1beta1 API in order
 	// to obtain TLS certificates. This feature requires that you bring your own CSR signing and approval process, otherwise
 	// pods will be stuck during initialization.
@@ -143,8 +196,326 @@ type InstallationSpec struct {
 	CertificateManagement *CertificateManagement `json:"certificateManagement,omitempty"`
 
 	// NonPrivileged configures Calico to be run in non-privileged containers as non-root users where possible.
+	// When enabled, calico-node, its init containers and the containers of the VPP dataplane only get the capabilities
+	// they need instead of running privileged, so that it can be deployed where privileged containers are not allowed.
 	// +optional
 	NonPrivileged *NonPrivilegedType `json:"nonPrivileged,omitempty"`
+
+	// Hardening hardens the containers of the components other than the dataplane, e.g. to pass the CIS benchmark
+	// scans: their root filesystem is read-only, with emptyDir volumes mounted on /tmp and the directories they write
+	// to, they drop the capabilities they don't add, they can't escalate their privileges and, unless they read host
+	// paths, they run as non-root users. The pods of the dataplane, which run privileged, in the host network or as
+	// root, are left unchanged.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	Hardening *HardeningType `json:"hardening,omitempty"`
+
+	// NodeInit configures the node-init init container of calico-node, which prepares the hosts before calico-node
+	// starts. It sets the kernel parameters needed by Calico and, with the VPP dataplane, reserves the hugepages
+	// and loads the kernel modules needed by VPP, which RHCOS nodes lack by default.
+	// +optional
+	NodeInit *NodeInit `json:"nodeInit,omitempty"`
+
+	// KubeControllers configures calico-kube-controllers. When set, the operator manages the default
+	// KubeControllersConfiguration from it, and changes made to the settings it manages are reverted.
+	// +optional
+	KubeControllers *KubeControllers `json:"kubeControllers,omitempty"`
+
+	// UsageReporting controls the usage reporting of the installed components. When disabled, calico-node
+	// doesn't report its version and the size of the cluster to projectcalico.org, and Kibana doesn't send
+	// telemetry to Elastic.
+	// Default: Enabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	UsageReporting *UsageReportingType `json:"usageReporting,omitempty"`
+
+	// KubernetesServiceEndpoint is the endpoint of the Kubernetes API server used by calico-node, typha and
+	// calico-kube-controllers, e.g. a VIP of a hosted control plane which is reachable before the dataplane is up.
+	// When set, it takes precedence over the kubernetes-services-endpoint ConfigMap for these components.
+	// +optional
+	KubernetesServiceEndpoint *KubernetesServiceEndpoint `json:"kubernetesServiceEndpoint,omitempty"`
+
+	// ConnectivityTest configures the test of the dataplane connectivity run by the operator, on demand or after
+	// the upgrades of the operator. Its result is written to the status of the Installation.
+	// +optional
+	ConnectivityTest *ConnectivityTest `json:"connectivityTest,omitempty"`
+
+	// RollbackTo rolls the Installation back to a known-good revision. The operator keeps the last revisions of the
+	// spec of the Installation which were rendered and became available, in the installation-revisions ConfigMap of
+	// the tigera-operator namespace. When RollbackTo is set, the operator replaces the spec with the spec of the
+	// revision, which clears RollbackTo. For example, the previous revision is re-applied with:
+	// kubectl patch installation default --type merge -p '{"spec":{"rollbackTo":{}}}'
+	// or with the rollback command of the operator:
+	// kubectl exec -n tigera-operator deploy/tigera-operator -- operator rollback
+	// +optional
+	RollbackTo *InstallationRollback `json:"rollbackTo,omitempty"`
+
+	// RolloutWatchdog marks the components Degraded when the rollout of one of their DaemonSets or Deployments
+	// doesn't progress, and optionally pauses their changes until the stall is acknowledged. The rollouts are not
+	// watched when not set.
+	// +optional
+	RolloutWatchdog *RolloutWatchdog `json:"rolloutWatchdog,omitempty"`
+}
+
+// RolloutWatchdog configures the watchdog of the rollouts of the DaemonSets and Deployments of the components.
+type RolloutWatchdog struct {
+	// StallTimeout is how long a rollout can go without progress before its component is marked Degraded, with the
+	// pods of the rollout which are not ready.
+	// Default: 10m
+	// +optional
+	StallTimeout *metav1.Duration `json:"stallTimeout,omitempty"`
+
+	// PauseOnStall pauses the changes of the components with a stalled rollout, until the stall is acknowledged
+	// with StallsAcknowledgedAt.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	PauseOnStall *RolloutPauseOnStallType `json:"pauseOnStall,omitempty"`
+
+	// StallsAcknowledgedAt acknowledges the stalls of the rollouts which stalled before this time, which resumes
+	// the changes of their components and gives them another StallTimeout to progress. For example:
+	// kubectl patch installation default --type merge -p "{\"spec\":{\"rolloutWatchdog\":{\"stallsAcknowledgedAt\":\"$(date -u +%FT%TZ)\"}}}"
+	// +optional
+	StallsAcknowledgedAt *metav1.Time `json:"stallsAcknowledgedAt,omitempty"`
+}
+
+// RolloutPauseOnStallType specifies whether the changes of the components with a stalled rollout are paused.
+//
+// One of: Enabled, Disabled
+type RolloutPauseOnStallType string
+
+const (
+	RolloutPauseOnStallEnabled  RolloutPauseOnStallType = "Enabled"
+	RolloutPauseOnStallDisabled RolloutPauseOnStallType = "Disabled"
+)
+
+// InstallationRollback is the revision an Installation is rolled back to.
+type InstallationRollback struct {
+	// Revision is the revision to roll back to, as reported in status.revision. If omitted or 0, the Installation is
+	// rolled back to the current revision if its spec was changed since, or else to the revision before it.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// KubernetesServiceEndpoint is an endpoint of the Kubernetes API server.
+type KubernetesServiceEndpoint struct {
+	// Host is the IP address or the DNS name of the Kubernetes API server.
+	Host string `json:"host"`
+
+	// Port is the port of the Kubernetes API server.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+}
+
+// ConnectivityTest configures the dataplane connectivity test. The test runs in a Job of the calico-system namespace
+// once the components are available. It checks the connectivity from a pod to the pods of a test Deployment spread
+// over a sample of the nodes, to their Service, the resolution of the name of the Service, and optionally the
+// connectivity to an external endpoint.
+type ConnectivityTest struct {
+	// Trigger runs the test when it changes. Set it to a new value, e.g. the current time, to run the test on
+	// demand.
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+
+	// AfterUpgrade runs the test once the components are available after an upgrade of the operator.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	AfterUpgrade *ConnectivityTestAfterUpgradeType `json:"afterUpgrade,omitempty"`
+
+	// Nodes is the number of nodes over which the pods of the test Deployment are spread.
+	// Default: 3
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Nodes *int32 `json:"nodes,omitempty"`
+
+	// ExternalEndpoint is the host:port of an endpoint outside of the cluster to which the test connects to check
+	// the external egress. The external egress is not checked when not set.
+	// +optional
+	ExternalEndpoint string `json:"externalEndpoint,omitempty"`
+}
+
+// ConnectivityTestAfterUpgradeType specifies whether the connectivity test runs after the upgrades of the operator.
+//
+// One of: Enabled, Disabled
+type ConnectivityTestAfterUpgradeType string
+
+const (
+	ConnectivityTestAfterUpgradeEnabled  ConnectivityTestAfterUpgradeType = "Enabled"
+	ConnectivityTestAfterUpgradeDisabled ConnectivityTestAfterUpgradeType = "Disabled"
+)
+
+// KubeControllers configures calico-kube-controllers.
+type KubeControllers struct {
+	// EnabledControllers are the controllers run by calico-kube-controllers. The Service and FederatedServices
+	// controllers are only supported for Calico Enterprise.
+	// Default: Node, plus Service and FederatedServices for Calico Enterprise
+	// +optional
+	EnabledControllers []KubeControllerType `json:"enabledControllers,omitempty"`
+
+	// Node configures the node controller.
+	// +optional
+	Node *KubeControllersNode `json:"node,omitempty"`
+}
+
+// KubeControllersNode configures the node controller of calico-kube-controllers.
+type KubeControllersNode struct {
+	// ReconcilerPeriod is the period at which the node controller reconciles with the Calico datastore.
+	// Default: 5m
+	// +optional
+	ReconcilerPeriod *metav1.Duration `json:"reconcilerPeriod,omitempty"`
+
+	// SyncLabels controls whether the labels of the Kubernetes nodes are copied to the Calico nodes.
+	// Default: Enabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	SyncLabels *NodeSyncLabelsType `json:"syncLabels,omitempty"`
+
+	// HostEndpointAutoCreate controls whether a host endpoint is created automatically for every node.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	HostEndpointAutoCreate *HostEndpointAutoCreateType `json:"hostEndpointAutoCreate,omitempty"`
+
+	// LeakGracePeriod is the period after which an IP address which is allocated to no pod is considered leaked
+	// and released. Set to 0 to disable the IP address garbage collection.
+	// Default: 15m
+	// +optional
+	LeakGracePeriod *metav1.Duration `json:"leakGracePeriod,omitempty"`
+}
+
+// NodeInit configures the node-init init container of calico-node.
+type NodeInit struct {
+	// State enables or disables the node-init init container.
+	// Default: Enabled on OpenShift, Disabled otherwise
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	State *NodeInitState `json:"state,omitempty"`
+
+	// Sysctls are extra kernel parameters set on the hosts by node-init. A parameter set here overrides the value
+	// that node-init sets by default for the same key.
+	// +optional
+	Sysctls []Sysctl `json:"sysctls,omitempty"`
+}
+
+// Sysctl is a kernel parameter set on the hosts.
+type Sysctl struct {
+	// Key is the name of the kernel parameter, e.g. net.ipv4.ip_forward.
+	Key string `json:"key"`
+
+	// Value is the value of the kernel parameter.
+	Value string `json:"value"`
+}
+
+// NodeCanaryRollout configures the canary rollouts of calico-node.
+type NodeCanaryRollout struct {
+	// NodeSelector selects the canary nodes. If not set, the canary nodes are the given Percentage of the nodes.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Percentage is the percentage of the nodes that are canaries when no NodeSelector is set.
+	// Default: 10
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percentage *int32 `json:"percentage,omitempty"`
+
+	// SoakSeconds is how long the updated calico-node pods of the canary nodes must have been ready before the
+	// other nodes are updated.
+	// Default: 300
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	SoakSeconds *int32 `json:"soakSeconds,omitempty"`
+
+	// MaxRestarts is the number of restarts of a container of an updated calico-node pod that are tolerated. The
+	// rollout halts when a container restarts more often.
+	// Default: 3
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRestarts *int32 `json:"maxRestarts,omitempty"`
+
+	// ProgressDeadlineSeconds is how long an updated calico-node pod may stay unready before the rollout halts.
+	// Default: 600
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// DataplaneMigrationDrain controls whether the nodes are drained while they are migrated to the VPP dataplane.
+// One of: Enabled, Disabled
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type DataplaneMigrationDrain string
+
+const (
+	DataplaneMigrationDrainEnabled  DataplaneMigrationDrain = "Enabled"
+	DataplaneMigrationDrainDisabled DataplaneMigrationDrain = "Disabled"
+)
+
+// DataplaneMigration configures the migration of the nodes from the BPF to the VPP dataplane.
+type DataplaneMigration struct {
+	// DrainNodes controls whether the pods of a node, except those of daemonsets, are evicted before it is migrated.
+	// The evictions respect the PodDisruptionBudgets.
+	// Default: Disabled
+	// +optional
+	DrainNodes *DataplaneMigrationDrain `json:"drainNodes,omitempty"`
+
+	// ProgressDeadlineSeconds is how long the updated calico-node pod of a migrated node may stay unready before the
+	// migration halts.
+	// Default: 600
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// NodeShutdown configures the shutdown of the calico-node pods. The preStop hook of calico-node waits for
+// DrainSeconds and runs the DrainCommand before shutting calico-node down.
+type NodeShutdown struct {
+	// TerminationGracePeriodSeconds is the time given to a calico-node or calico-vpp-node pod to shut down, including
+	// its preStop hook. It must be larger than DrainSeconds. It takes precedence over the ShutdownGracePeriodSeconds of
+	// the BGP graceful restart. The calico-vpp-node pods get the default grace period of Kubernetes otherwise.
+	// Default: 5
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// DrainSeconds is the time the preStop hook waits for the connections of the node to drain before calico-node, or
+	// the VPP agent with the VPP dataplane, shuts down.
+	// Default: 0
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	DrainSeconds *int32 `json:"drainSeconds,omitempty"`
+
+	// DrainCommand is a shell command run by the preStop hook after DrainSeconds, e.g. to withdraw the routes of the
+	// node. It runs in the calico-node container, or in the agent container of calico-vpp-node with the VPP dataplane.
+	// The container shuts down even if the command fails.
+	// +optional
+	DrainCommand string `json:"drainCommand,omitempty"`
+}
+
+// DataplaneSync tunes the resync of the dataplane by Felix. The fields which are not set keep the defaults of Felix.
+// Neither Felix nor the VPP agent reads a batch size for the programming of the policies or the routes, so the batching
+// can't be tuned, and the VPP agent, which gets the policies and the routes from Felix, has no setting of its own.
+type DataplaneSync struct {
+	// RouteRefreshIntervalSeconds is the interval at which Felix resyncs the routes of the dataplane to correct any
+	// drift. 0 disables the resync.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	RouteRefreshIntervalSeconds *int32 `json:"routeRefreshIntervalSeconds,omitempty"`
+
+	// IptablesRefreshIntervalSeconds is the interval at which Felix resyncs the iptables rules of the dataplane to
+	// correct any drift. 0 disables the resync. It is not used by the VPP dataplane.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	IptablesRefreshIntervalSeconds *int32 `json:"iptablesRefreshIntervalSeconds,omitempty"`
+
+	// IpsetsRefreshIntervalSeconds is the interval at which Felix resyncs the IP sets of the dataplane to correct any
+	// drift. 0 disables the resync. It is not used by the VPP dataplane.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	IpsetsRefreshIntervalSeconds *int32 `json:"ipsetsRefreshIntervalSeconds,omitempty"`
 }
 
 // TyphaAffinity allows configuration of node affinitiy characteristics for Typha pods.
@@ -221,6 +592,27 @@ var (
 	TigeraSecureEnterprise ProductVariant = "TigeraSecureEnterprise"
 )
 
+// NamespaceQuotasType specifies whether the ResourceQuota and the LimitRange of the calico-system namespace are
+// rendered.
+//
+// One of: Enabled, Disabled
+type NamespaceQuotasType string
+
+const (
+	NamespaceQuotasEnabled  NamespaceQuotasType = "Enabled"
+	NamespaceQuotasDisabled NamespaceQuotasType = "Disabled"
+)
+
+// HardeningType specifies whether the containers of the components other than the dataplane are hardened.
+//
+// One of: Enabled, Disabled
+type HardeningType string
+
+const (
+	HardeningEnabled  HardeningType = "Enabled"
+	HardeningDisabled HardeningType = "Disabled"
+)
+
 // NonPrivilegedType specifies whether Calico runs as permissioned or not
 //
 // One of: Enabled, Disabled
@@ -231,6 +623,69 @@ const (
 	NonPrivilegedDisabled NonPrivilegedType = "Disabled"
 )
 
+// NodeInitState specifies whether the node-init init container runs.
+//
+// One of: Enabled, Disabled
+type NodeInitState string
+
+const (
+	NodeInitEnabled  NodeInitState = "Enabled"
+	NodeInitDisabled NodeInitState = "Disabled"
+)
+
+// KubeControllerType is a controller of calico-kube-controllers.
+//
+// One of: Node, Service, FederatedServices
+// +kubebuilder:validation:Enum=Node;Service;FederatedServices
+type KubeControllerType string
+
+const (
+	KubeControllerNode              KubeControllerType = "Node"
+	KubeControllerService           KubeControllerType = "Service"
+	KubeControllerFederatedServices KubeControllerType = "FederatedServices"
+)
+
+// NodeSyncLabelsType specifies whether the labels of the Kubernetes nodes are copied to the Calico nodes.
+//
+// One of: Enabled, Disabled
+type NodeSyncLabelsType string
+
+const (
+	NodeSyncLabelsEnabled  NodeSyncLabelsType = "Enabled"
+	NodeSyncLabelsDisabled NodeSyncLabelsType = "Disabled"
+)
+
+// HostEndpointAutoCreateType specifies whether a host endpoint is created automatically for every node.
+//
+// One of: Enabled, Disabled
+type HostEndpointAutoCreateType string
+
+const (
+	HostEndpointAutoCreateEnabled  HostEndpointAutoCreateType = "Enabled"
+	HostEndpointAutoCreateDisabled HostEndpointAutoCreateType = "Disabled"
+)
+
+// UsageReportingType specifies whether the installed components report usage data.
+//
+// One of: Enabled, Disabled
+type UsageReportingType string
+
+const (
+	UsageReportingEnabled  UsageReportingType = "Enabled"
+	UsageReportingDisabled UsageReportingType = "Disabled"
+)
+
+// PolicySyncDriverType specifies the volume driver providing the policy sync API to the pods.
+//
+// One of: FlexVolume, CSI, Both
+type PolicySyncDriverType string
+
+const (
+	PolicySyncDriverFlexVolume PolicySyncDriverType = "FlexVolume"
+	PolicySyncDriverCSI        PolicySyncDriverType = "CSI"
+	PolicySyncDriverBoth       PolicySyncDriverType = "Both"
+)
+
 // ContainerIPForwardingType specifies whether the CNI config for container ip forwarding is enabled.
 type ContainerIPForwardingType string
 
@@ -239,6 +694,15 @@ const (
 	ContainerIPForwardingDisabled ContainerIPForwardingType = "Disabled"
 )
 
+// HostIPForwardingType specifies whether Felix enables ip forwarding on the hosts.
+// One of: Enabled, Disabled
+type HostIPForwardingType string
+
+const (
+	HostIPForwardingEnabled  HostIPForwardingType = "Enabled"
+	HostIPForwardingDisabled HostIPForwardingType = "Disabled"
+)
+
 // HostPortsType specifies host port support.
 //
 // One of: Enabled, Disabled
@@ -296,7 +760,7 @@ const (
 
 // LinuxDataplaneOption controls which dataplane is to be used on Linux nodes.
 //
-// One of: Iptables, BPF
+// One of: Iptables, BPF, VPP
 type LinuxDataplaneOption string
 
 const (
@@ -315,11 +779,54 @@ type CalicoNetworkSpec struct {
 	// +kubebuilder:validation:Enum=Iptables;BPF;VPP
 	LinuxDataplane *LinuxDataplaneOption `json:"linuxDataplane,omitempty"`
 
+	// VPPProfiles are named VPP configurations applied to the nodes matching their node selectors, so that a fleet
+	// mixing e.g. DPDK capable nodes and virtio VMs is configured from the Installation. A node gets the first profile
+	// it matches. The operator labels the nodes with their profile and renders the configuration of each profile in
+	// the calico-vpp-profile-<name> ConfigMap of the calico-system namespace. Valid only with the VPP dataplane.
+	// +optional
+	VPPProfiles []VPPProfile `json:"vppProfiles,omitempty"`
+
+	// VPPTunnels declares additional encapsulations supported by VPP, e.g. GTP-U or GENEVE for telco deployments.
+	// IP pools select one of them with their vppTunnel field. The operator renders the tunnels, the pools they carry
+	// and the overhead the VPP agent removes from the MTU of the pods in the calico-vpp-tunnels ConfigMap of the
+	// calico-system namespace. Valid only with the VPP dataplane.
+	// +optional
+	VPPTunnels []VPPTunnel `json:"vppTunnels,omitempty"`
+
+	// VPPNAT64 enables NAT64 in VPP, so that IPv6-only pods reach IPv4 destinations through the IPv6 addresses of
+	// the NAT64 prefix. The operator doesn't configure DNS64: the DNS of the cluster must synthesize the AAAA records
+	// of the IPv4 destinations in the same prefix, e.g. with the dns64 plugin of CoreDNS. The operator renders the
+	// prefix in the calico-vpp-nat64 ConfigMap of the calico-system namespace and reports the NAT64 status of the
+	// nodes in the status of the Installation. Valid only with the VPP dataplane and an IPv6 IP pool.
+	// +optional
+	VPPNAT64 *VPPNAT64 `json:"vppNAT64,omitempty"`
+
 	// BGP configures whether or not to enable Calico's BGP capabilities.
 	// +optional
 	// +kubebuilder:validation:Enum=Enabled;Disabled
 	BGP *BGPOption `json:"bgp,omitempty"`
 
+	// RouteReflectors configures in-cluster BGP route reflectors. When specified, the operator selects the route
+	// reflector nodes, peers every node with them and disables the full node-to-node BGP mesh. Valid only when BGP
+	// is enabled.
+	// +optional
+	RouteReflectors *RouteReflectors `json:"routeReflectors,omitempty"`
+
+	// ServiceAdvertisement configures the service CIDRs advertised over BGP from the nodes. When specified, the
+	// operator sets them in the default BGPConfiguration. Valid only when BGP is enabled.
+	// +optional
+	ServiceAdvertisement *ServiceAdvertisement `json:"serviceAdvertisement,omitempty"`
+
+	// BGPPeeringTemplates generate the BGP peers of each node from its labels, e.g. to peer the nodes of a rack with
+	// the top of rack switches of the rack. Valid only when BGP is enabled.
+	// +optional
+	BGPPeeringTemplates []BGPPeeringTemplate `json:"bgpPeeringTemplates,omitempty"`
+
+	// BGPGracefulRestart configures the handling of the BGP sessions of the nodes while calico-node restarts, e.g.
+	// during upgrades, so that the peers keep forwarding traffic to the restarting node. Valid only when BGP is enabled.
+	// +optional
+	BGPGracefulRestart *BGPGracefulRestart `json:"bgpGracefulRestart,omitempty"`
+
 	// IPPools contains a list of IP pools to create if none exist. At most one IP pool of each
 	// address family may be specified. If omitted, a single pool will be configured if needed.
 	// +optional
@@ -331,12 +838,14 @@ type CalicoNetworkSpec struct {
 	MTU *int32 `json:"mtu,omitempty"`
 
 	// NodeAddressAutodetectionV4 specifies an approach to automatically detect node IPv4 addresses. If not specified,
-	// will use default auto-detection settings to acquire an IPv4 address for each node.
+	// will use default auto-detection settings to acquire an IPv4 address for each node. With the VPP dataplane,
+	// it must select the address of the uplink of VPP, and defaults to the internal IP of the node.
 	// +optional
 	NodeAddressAutodetectionV4 *NodeAddressAutodetection `json:"nodeAddressAutodetectionV4,omitempty"`
 
 	// NodeAddressAutodetectionV6 specifies an approach to automatically detect node IPv6 addresses. If not specified,
-	// IPv6 addresses will not be auto-detected.
+	// IPv6 addresses will not be auto-detected. With the VPP dataplane, it must select the address of the uplink of
+	// VPP, and defaults to the internal IP of the node.
 	// +optional
 	NodeAddressAutodetectionV6 *NodeAddressAutodetection `json:"nodeAddressAutodetectionV6,omitempty"`
 
@@ -358,11 +867,453 @@ type CalicoNetworkSpec struct {
 	// +optional
 	// +kubebuilder:validation:Enum=Enabled;Disabled
 	ContainerIPForwarding *ContainerIPForwardingType `json:"containerIPForwarding,omitempty"`
+
+	// HostIPForwarding configures whether Felix enables ip forwarding in the host network namespace of the nodes
+	// (the net.ipv4.ip_forward and net.ipv6.conf.all.forwarding sysctls). When Disabled, ip forwarding must be
+	// enabled on the hosts by other means for the pods to be reachable.
+	// Default: Enabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	HostIPForwarding *HostIPForwardingType `json:"hostIPForwarding,omitempty"`
+
+	// AWSSecondaryIPs configures Calico to give the pods secondary IP addresses of the ENIs of the EC2 instances, so
+	// that they are routable in the VPC, e.g. for egress through the VPC or to use one IP pool per subnet. Felix
+	// attaches the ENIs and assigns the IP addresses. Valid only when using the Calico CNI plugin and Calico IPAM.
+	// +optional
+	AWSSecondaryIPs *AWSSecondaryIPs `json:"awsSecondaryIPs,omitempty"`
+}
+
+// AWSSecondaryIPs configures the secondary ENIs and IP addresses of the EC2 instances.
+type AWSSecondaryIPs struct {
+	// Mode selects how the ENIs are used. Enabled attaches secondary ENIs to the nodes and assigns the pod IPs as
+	// secondary IP addresses of those ENIs. EnabledENIPerWorkload attaches a dedicated ENI to each pod instead.
+	// Disabled leaves the ENIs alone.
+	// Default: Enabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;EnabledENIPerWorkload;Disabled
+	Mode *AWSSecondaryIPMode `json:"mode,omitempty"`
+
+	// RoutingRulePriority is the priority of the routing rules that route the traffic of the pods through their ENIs.
+	// Default: 101
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32765
+	RoutingRulePriority *int32 `json:"routingRulePriority,omitempty"`
+
+	// IAMRoleARN is the ARN of the IAM role used by calico-node to manage the ENIs, through IAM roles for service
+	// accounts. The role must allow the EC2 actions that attach ENIs and assign IP addresses. If not set, the
+	// instance profile of the nodes must allow them.
+	// +optional
+	IAMRoleARN string `json:"iamRoleARN,omitempty"`
+
+	// SubnetPools are the IP pools backed by the subnets of the VPC. The operator creates an IP pool for each of
+	// them; it never deletes those pools since they may still be in use.
+	// +optional
+	SubnetPools []AWSSubnetPool `json:"subnetPools,omitempty"`
+}
+
+// AWSSubnetPool is an IP pool whose addresses are the addresses of a subnet of the VPC.
+type AWSSubnetPool struct {
+	// Name is the name of the IP pool.
+	Name string `json:"name"`
+
+	// CIDR is the CIDR of the IP pool. It must be within the CIDR of the subnet.
+	CIDR string `json:"cidr"`
+
+	// SubnetID is the ID of the subnet, e.g. subnet-0123456789abcdef0.
+	SubnetID string `json:"subnetID"`
+
+	// BlockSize is the CIDR size of the IPAM blocks allocated from the pool.
+	// Default: 26
+	// +optional
+	// +kubebuilder:validation:Minimum=20
+	// +kubebuilder:validation:Maximum=32
+	BlockSize *int32 `json:"blockSize,omitempty"`
+
+	// NodeSelector selects the nodes which assign addresses of the pool to their pods, e.g. the nodes of the
+	// availability zone of the subnet.
+	// Default: all()
+	// +optional
+	NodeSelector string `json:"nodeSelector,omitempty"`
+}
+
+// AWSSecondaryIPMode specifies how the secondary ENIs of the EC2 instances are used.
+//
+// One of: Enabled, EnabledENIPerWorkload, Disabled
+type AWSSecondaryIPMode string
+
+const (
+	AWSSecondaryIPEnabled               AWSSecondaryIPMode = "Enabled"
+	AWSSecondaryIPEnabledENIPerWorkload AWSSecondaryIPMode = "EnabledENIPerWorkload"
+	AWSSecondaryIPDisabled              AWSSecondaryIPMode = "Disabled"
+)
+
+// RouteReflectors configures the in-cluster BGP route reflectors.
+type RouteReflectors struct {
+	// Replicas is the number of nodes acting as route reflectors.
+	// Default: 3
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ClusterID is the route reflector cluster ID shared by the route reflector nodes. It must be an IPv4 address.
+	// Default: 244.0.0.1
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+
+	// NodeSelector restricts the nodes which may be selected as route reflectors to the ones with the given labels.
+	// If omitted, any Linux node may be selected.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Password references a key of a Secret in the tigera-operator namespace holding the password of the BGP sessions
+	// with the route reflectors. The operator copies the Secret to the calico-system namespace and allows calico-node
+	// to read it. The sessions are re-established when the password changes.
+	// +optional
+	Password *v1.SecretKeySelector `json:"password,omitempty"`
+}
+
+// VPPProfile is a VPP configuration applied to the nodes matching its node selector.
+type VPPProfile struct {
+	// Name is the name of the profile. It is the value of the operator.tigera.io/vpp-profile label of its nodes.
+	Name string `json:"name"`
+
+	// NodeSelector selects the nodes of the profile by their labels. If omitted, the profile matches every node.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// UplinkDriver is the driver used by VPP for the uplink interface of the nodes. If omitted, the uplink driver of
+	// the VPPDataplane is used, and if it isn't set either, VPP selects a driver supported by the uplink interface.
+	// +optional
+	// +kubebuilder:validation:Enum=af_packet;af_xdp;avf;dpdk;rdma;virtio;vmxnet3
+	UplinkDriver *VPPUplinkDriver `json:"uplinkDriver,omitempty"`
+
+	// Workers is the number of worker threads of VPP, each running on a dedicated core. If omitted, VPP only runs its
+	// main thread.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Workers *int32 `json:"workers,omitempty"`
+
+	// CPUPinning pins the main thread and the workers of VPP to dedicated cores. If omitted, the threads of VPP are
+	// not pinned.
+	// +optional
+	CPUPinning *VPPCPUPinning `json:"cpuPinning,omitempty"`
+
+	// BuffersPerNUMA is the number of packet buffers allocated by VPP on each NUMA node. If omitted, the VPP default
+	// is used.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BuffersPerNUMA *int32 `json:"buffersPerNUMA,omitempty"`
+
+	// BufferDataSize is the size in bytes of the data of the packet buffers allocated by VPP, which should hold the
+	// largest frames of the uplink for them not to be chained, e.g. 9216 with jumbo frames. If omitted, the VPP
+	// default of 2048 is used.
+	// +optional
+	// +kubebuilder:validation:Minimum=512
+	// +kubebuilder:validation:Maximum=65535
+	BufferDataSize *int32 `json:"bufferDataSize,omitempty"`
+
+	// Memory sizes the memory regions of VPP on the nodes of the profile, which must fit within the memory limit of
+	// the vpp container set in the VPPDataplane. If omitted, the VPP defaults are used.
+	// +optional
+	Memory *VPPMemory `json:"memory,omitempty"`
+
+	// PowerProfile selects how VPP polls the interfaces. BusyPoll keeps the threads of VPP polling, for the highest
+	// throughput and the lowest latency. SleepWhenIdle makes VPP sleep between the polls and the interfaces fall back
+	// to interrupts when idle, which trades throughput for power, e.g. on edge nodes.
+	// Default: BusyPoll
+	// +optional
+	// +kubebuilder:validation:Enum=BusyPoll;SleepWhenIdle
+	PowerProfile *VPPPowerProfile `json:"powerProfile,omitempty"`
+
+	// CryptoEngine selects the engine VPP uses for the encryption of IPsec and WireGuard. Native uses the
+	// instructions of the CPU, IPsecMB the Intel Multi-Buffer Crypto library, QAT the Intel QuickAssist devices
+	// through DPDK, and Async offloads the crypto operations to the workers of VPP. A profile with the QAT engine
+	// only matches the nodes labelled with intel.feature.node.kubernetes.io/qat=true, as done by the Node Feature
+	// Discovery, so the nodes without QAT devices fall through to the next profiles. If omitted, VPP selects the
+	// fastest engine for each algorithm.
+	// +optional
+	// +kubebuilder:validation:Enum=Native;IPsecMB;QAT;Async
+	CryptoEngine *VPPCryptoEngine `json:"cryptoEngine,omitempty"`
+
+	// Plugins selects the plugins loaded by VPP, e.g. to disable unused plugins for attack surface or memory reasons,
+	// or to force-enable crypto engines. If omitted, every plugin is loaded except the DPDK plugin, which is only
+	// loaded with the dpdk uplink driver.
+	// +optional
+	Plugins *VPPPlugins `json:"plugins,omitempty"`
+
+	// DPDK configures the Environment Abstraction Layer of DPDK, which is loaded with the dpdk uplink driver or the
+	// QAT crypto engine.
+	// +optional
+	DPDK *VPPDPDK `json:"dpdk,omitempty"`
+
+	// StartupConfigOverride references a key of a ConfigMap in the tigera-operator namespace holding a snippet of
+	// VPP startup configuration, which is merged with the configuration generated for the profile. The directives of
+	// the snippet replace the generated directives with the same name in the same section, and its other directives
+	// and sections are added. The directives the dataplane relies on are rejected: the socksvr and plugins sections,
+	// and the exec, startup-config, cli-listen, pidfile and interactive directives of the unix section.
+	// +optional
+	StartupConfigOverride *v1.ConfigMapKeySelector `json:"startupConfigOverride,omitempty"`
+}
+
+// VPPCPUPinning selects the cores of the main thread and of the workers of VPP.
+type VPPCPUPinning struct {
+	// Mode selects how the cores are chosen. With Manual, the main thread runs on MainCore and the workers on
+	// WorkerCores. With CPUManager, the calico-vpp-node pods of the profile are Guaranteed, the vpp container
+	// requesting one CPU for the main thread and one for each worker of the profile, so that the static policy of the
+	// kubelet CPU manager assigns it exclusive CPUs out of the CPUs not reserved for the system. The cores are
+	// numbered relative to these CPUs: the main thread runs on the first one, and the workers on the following ones.
+	// +kubebuilder:validation:Enum=Manual;CPUManager
+	Mode VPPCPUPinningMode `json:"mode"`
+
+	// MainCore is the core of the main thread of VPP, with the Manual mode.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MainCore *int32 `json:"mainCore,omitempty"`
+
+	// WorkerCores are the cores of the workers of VPP, with the Manual mode, one worker running on each core. It is a
+	// list of cores and ranges of cores, e.g. 2-5,8, and can't be set along with the workers of the profile.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(-[0-9]+)?(,[0-9]+(-[0-9]+)?)*$`
+	WorkerCores string `json:"workerCores,omitempty"`
+}
+
+// VPPCPUPinningMode is how the cores of VPP are chosen.
+// One of: Manual, CPUManager
+type VPPCPUPinningMode string
+
+const (
+	VPPCPUPinningManual     VPPCPUPinningMode = "Manual"
+	VPPCPUPinningCPUManager VPPCPUPinningMode = "CPUManager"
+)
+
+// VPPPlugins selects the plugins loaded by VPP. The plugins are named after their file, e.g. crypto_native_plugin.so.
+type VPPPlugins struct {
+	// Default selects whether the plugins which are not listed are loaded. With Disabled, VPP only loads the plugins
+	// of Enabled along with the plugins the dataplane requires: calico_plugin.so and the cnat_plugin.so,
+	// acl_plugin.so, nat_plugin.so and capo_plugin.so it depends on, the plugin of the uplink driver, which must then
+	// be set, and the plugin of the crypto engine.
+	// Default: Enabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	Default *VPPPluginDefault `json:"default,omitempty"`
+
+	// Enabled are the plugins VPP loads.
+	// +optional
+	Enabled []string `json:"enabled,omitempty"`
+
+	// Disabled are the plugins VPP doesn't load. The plugins the dataplane requires can't be disabled.
+	// +optional
+	Disabled []string `json:"disabled,omitempty"`
+}
+
+// VPPDPDK configures the Environment Abstraction Layer (EAL) of DPDK. The PCI devices are given by their address,
+// e.g. 0000:00:08.0.
+type VPPDPDK struct {
+	// AllowedPCIDevices are the only PCI devices DPDK probes. If omitted, DPDK probes every PCI device bound to a
+	// driver it supports, except the DeniedPCIDevices.
+	// +optional
+	AllowedPCIDevices []string `json:"allowedPCIDevices,omitempty"`
+
+	// DeniedPCIDevices are PCI devices DPDK doesn't probe. It can't be used along with AllowedPCIDevices.
+	// +optional
+	DeniedPCIDevices []string `json:"deniedPCIDevices,omitempty"`
+
+	// ExtraArgs are additional directives of the dpdk section of the VPP startup configuration, e.g.
+	// "socket-mem 1024,1024", which VPP passes on to the EAL.
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+
+	// LogLevel is the log level of the EAL.
+	// Default: notice
+	// +optional
+	// +kubebuilder:validation:Enum=emergency;alert;critical;error;warning;notice;info;debug
+	LogLevel *VPPDPDKLogLevel `json:"logLevel,omitempty"`
+}
+
+// VPPDPDKLogLevel is a log level of the EAL of DPDK.
+// One of: emergency, alert, critical, error, warning, notice, info, debug
+type VPPDPDKLogLevel string
+
+const (
+	VPPDPDKLogLevelEmergency VPPDPDKLogLevel = "emergency"
+	VPPDPDKLogLevelAlert     VPPDPDKLogLevel = "alert"
+	VPPDPDKLogLevelCritical  VPPDPDKLogLevel = "critical"
+	VPPDPDKLogLevelError     VPPDPDKLogLevel = "error"
+	VPPDPDKLogLevelWarning   VPPDPDKLogLevel = "warning"
+	VPPDPDKLogLevelNotice    VPPDPDKLogLevel = "notice"
+	VPPDPDKLogLevelInfo      VPPDPDKLogLevel = "info"
+	VPPDPDKLogLevelDebug     VPPDPDKLogLevel = "debug"
+)
+
+// VPPPluginDefault selects whether VPP loads the plugins which are not listed.
+// One of: Enabled, Disabled
+type VPPPluginDefault string
+
+const (
+	VPPPluginDefaultEnabled  VPPPluginDefault = "Enabled"
+	VPPPluginDefaultDisabled VPPPluginDefault = "Disabled"
+)
+
+// VPPPowerProfile is how VPP polls the interfaces.
+// One of: BusyPoll, SleepWhenIdle
+type VPPPowerProfile string
+
+const (
+	VPPPowerProfileBusyPoll      VPPPowerProfile = "BusyPoll"
+	VPPPowerProfileSleepWhenIdle VPPPowerProfile = "SleepWhenIdle"
+)
+
+// VPPCryptoEngine is the engine VPP uses for the cryptographic operations.
+// One of: Native, IPsecMB, QAT, Async
+type VPPCryptoEngine string
+
+const (
+	VPPCryptoEngineNative  VPPCryptoEngine = "Native"
+	VPPCryptoEngineIPsecMB VPPCryptoEngine = "IPsecMB"
+	VPPCryptoEngineQAT     VPPCryptoEngine = "QAT"
+	VPPCryptoEngineAsync   VPPCryptoEngine = "Async"
+)
+
+// VPPUplinkDriver is the driver of the uplink interface of VPP.
+// One of: af_packet, af_xdp, avf, dpdk, rdma, virtio, vmxnet3
+type VPPUplinkDriver string
+
+const (
+	VPPUplinkDriverAFPacket VPPUplinkDriver = "af_packet"
+	VPPUplinkDriverAFXDP    VPPUplinkDriver = "af_xdp"
+	VPPUplinkDriverAVF      VPPUplinkDriver = "avf"
+	VPPUplinkDriverDPDK     VPPUplinkDriver = "dpdk"
+	VPPUplinkDriverRDMA     VPPUplinkDriver = "rdma"
+	VPPUplinkDriverVirtio   VPPUplinkDriver = "virtio"
+	VPPUplinkDriverVMXNET3  VPPUplinkDriver = "vmxnet3"
+)
+
+// VPPTunnel is an additional encapsulation supported by VPP.
+type VPPTunnel struct {
+	// Name is the name of the tunnel, which the IP pools select it by.
+	Name string `json:"name"`
+
+	// Type is the encapsulation of the tunnel.
+	// +kubebuilder:validation:Enum=GTPU;GENEVE
+	Type VPPTunnelType `json:"type"`
+
+	// Port is the UDP destination port of the tunnel.
+	// Default: 2152 for GTPU, 6081 for GENEVE
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port *int32 `json:"port,omitempty"`
+
+	// Overhead is the number of bytes the encapsulation adds to the packets. Felix doesn't account for it when it
+	// detects the MTU, so spec.calicoNetwork.mtu must be set when the IP pools use VPP tunnels, and the MTU and the
+	// largest overhead of the tunnels in use must not exceed 9216 bytes. The defaults account for an IPv4 outer header.
+	// Default: 36 for GTPU, 50 for GENEVE
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Overhead *int32 `json:"overhead,omitempty"`
+}
+
+// VPPNAT64 configures NAT64 in VPP.
+type VPPNAT64 struct {
+	// Prefix is the IPv6 prefix the IPv4 addresses are embedded in, as defined in RFC 6052. Its length must be one
+	// of 32, 40, 48, 56, 64 or 96.
+	// Default: 64:ff9b::/96
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// VPPTunnelType is the encapsulation of a VPP tunnel.
+// One of: GTPU, GENEVE
+type VPPTunnelType string
+
+const (
+	VPPTunnelGTPU   VPPTunnelType = "GTPU"
+	VPPTunnelGENEVE VPPTunnelType = "GENEVE"
+)
+
+// ServiceAdvertisement configures the service CIDRs advertised over BGP.
+type ServiceAdvertisement struct {
+	// ServiceClusterIPs are the CIDRs from which the service cluster IPs are allocated. They must cover the service
+	// cluster IP range of the cluster.
+	// +optional
+	ServiceClusterIPs []string `json:"serviceClusterIPs,omitempty"`
+
+	// ServiceExternalIPs are the CIDRs of the service external IPs. Only the external IPs within one of them are
+	// advertised.
+	// +optional
+	ServiceExternalIPs []string `json:"serviceExternalIPs,omitempty"`
+
+	// ServiceLoadBalancerIPs are the CIDRs of the service LoadBalancer IPs. Only the LoadBalancer ingress IPs within
+	// one of them are advertised.
+	// +optional
+	ServiceLoadBalancerIPs []string `json:"serviceLoadBalancerIPs,omitempty"`
+}
+
+// BGPPeeringTemplate maps the values of a node label to the BGP peers of the nodes with that value. The operator
+// generates a BGPPeer for each node and peer.
+type BGPPeeringTemplate struct {
+	// Name of the template. It prefixes the names of the BGPPeers generated for the template.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// NodeLabel is the node label whose value selects the peers of a node, e.g. the rack of the node.
+	NodeLabel string `json:"nodeLabel"`
+
+	// Values lists the peers of the nodes for each value of the node label. No peers are generated for the nodes
+	// without the label or with a value that isn't listed.
+	Values []BGPPeeringTemplateValue `json:"values"`
+}
+
+// BGPPeeringTemplateValue lists the BGP peers of the nodes with a value of the label of a BGPPeeringTemplate.
+type BGPPeeringTemplateValue struct {
+	// Value of the node label.
+	Value string `json:"value"`
+
+	// Peers of the nodes with the value, e.g. both top of rack switches of a rack.
+	Peers []BGPPeerDefinition `json:"peers"`
+}
+
+// BGPPeerDefinition defines a BGP peer of a node.
+type BGPPeerDefinition struct {
+	// PeerIP is the IP address of the peer, optionally followed by a port number.
+	PeerIP string `json:"peerIP"`
+
+	// ASNumber is the AS number of the peer.
+	// +kubebuilder:validation:Minimum=1
+	ASNumber uint32 `json:"asNumber"`
+}
+
+// BGPGracefulRestart configures the timings of the BGP graceful restart of the nodes.
+type BGPGracefulRestart struct {
+	// MaxRestartTimeSeconds is the graceful restart time of the BGP sessions configured by the operator, i.e. the
+	// route reflector and peering template sessions. The peers keep the routes of a restarting node for this long.
+	// Default: 120
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxRestartTimeSeconds *int32 `json:"maxRestartTimeSeconds,omitempty"`
+
+	// ShutdownGracePeriodSeconds is the time given to calico-node, or to calico-vpp-node with the VPP dataplane, to shut
+	// down, during which the BGP sessions are closed gracefully.
+	// Default: 5
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ShutdownGracePeriodSeconds *int64 `json:"shutdownGracePeriodSeconds,omitempty"`
+
+	// ConvergenceSeconds is the time a restarted calico-node, or calico-vpp-node with the VPP dataplane, must stay
+	// ready before it is considered available, to let the routes converge before the rollout moves to the next node.
+	// The nodes are only ready once their BGP sessions are established.
+	// Default: 0
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ConvergenceSeconds *int32 `json:"convergenceSeconds,omitempty"`
 }
 
 // NodeAddressAutodetection provides configuration options for auto-detecting node addresses. At most one option
 // can be used. If no detection option is specified, then IP auto detection will be disabled for this address family and IPs
 // must be specified directly on the Node resource.
+// With the VPP dataplane, the detected addresses are the addresses of the uplink of VPP, so the detection must select
+// the uplink interface; it defaults to the internal IP of the node.
 type NodeAddressAutodetection struct {
 	// FirstFound uses default interface matching parameters to select an interface, performing best-effort
 	// filtering based on well-known interface names.
@@ -488,6 +1439,12 @@ type IPPool struct {
 	// Default: 26 (IPv4), 122 (IPv6)
 	// +optional
 	BlockSize *int32 `json:"blockSize,omitempty"`
+
+	// VPPTunnel is the name of the tunnel of spec.calicoNetwork.vppTunnels encapsulating the traffic of the pool
+	// between the nodes. The encapsulation of the pool must be None, which is its default when a tunnel is selected.
+	// Valid only with the VPP dataplane.
+	// +optional
+	VPPTunnel string `json:"vppTunnel,omitempty"`
 }
 
 // CNIPluginType describes the type of CNI plugin used.
@@ -519,6 +1476,16 @@ func (cp CNIPluginType) String() string {
 	return string(cp)
 }
 
+// CNIChainingType specifies whether the provider CNI plugin is chained with the plugins installed by Calico.
+//
+// One of: Enabled, Disabled
+type CNIChainingType string
+
+const (
+	CNIChainingEnabled  CNIChainingType = "Enabled"
+	CNIChainingDisabled CNIChainingType = "Disabled"
+)
+
 type IPAMPluginType string
 
 const (
@@ -558,6 +1525,10 @@ type IPAMSpec struct {
 	// for all other values of the CNI plugin the plugin binaries and CNI config is a dependency
 	// that is expected to be installed separately.
 	//
+	// With HostLocal, the addresses of the pods are assigned from the pod CIDRs of their node
+	// (spec.podCIDRs of the Node), and the IP pools must select all the nodes. Static addresses
+	// requested through the cni.projectcalico.org/ipAddrs annotation require the Calico IPAM.
+	//
 	// Default: Calico
 	// +kubebuilder:validation:Enum=Calico;HostLocal;AmazonVPC;AzureVNET
 	Type IPAMPluginType `json:"type"`
@@ -584,6 +1555,15 @@ type CNISpec struct {
 	// Calico Enterprise installation.
 	// +optional
 	IPAM *IPAMSpec `json:"ipam"`
+
+	// Chaining configures the operator to install a CNI config list which chains the GKE, AmazonVPC or AzureVNET
+	// plugin with the portmap and bandwidth plugins, so that host ports and bandwidth limits are supported while
+	// Calico only enforces policy. The provider plugin binaries are still expected to be installed separately.
+	// Valid only when the CNI plugin is not Calico.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	Chaining *CNIChainingType `json:"chaining,omitempty"`
 }
 
 // InstallationStatus defines the observed state of the Calico or Calico Enterprise installation.
@@ -604,6 +1584,82 @@ type InstallationStatus struct {
 	// Computed is the final installation including overlaid resources.
 	// +optional
 	Computed *InstallationSpec `json:"computed,omitempty"`
+
+	// OperatorVersion is the version of the operator that last installed the components. It is used to check that
+	// upgrades of the operator follow a supported upgrade path.
+	// +optional
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+
+	// ConnectivityTest is the status of the last dataplane connectivity test.
+	// +optional
+	ConnectivityTest *ConnectivityTestStatus `json:"connectivityTest,omitempty"`
+
+	// NAT64 is the NAT64 status of the nodes of the VPP dataplane, when spec.calicoNetwork.vppNAT64 is set. The nodes
+	// where NAT64 isn't ready come first, and at most 100 nodes are listed.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	NAT64 []NodeNAT64Status `json:"nat64,omitempty"`
+
+	// Revision is the revision of the spec of the Installation which was last rendered and became available. It can
+	// be used with spec.rollbackTo.
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// NodeNAT64Status is the NAT64 status of a node, as reported by the VPP agent of the node.
+type NodeNAT64Status struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+
+	// Ready is true when the VPP agent of the node translates the traffic of the NAT64 prefix.
+	Ready bool `json:"ready"`
+
+	// Message reports why NAT64 is not ready on the node.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ConnectivityTestState is the state of a connectivity test.
+//
+// One of: Running, Succeeded, Failed
+type ConnectivityTestState string
+
+const (
+	ConnectivityTestRunning   ConnectivityTestState = "Running"
+	ConnectivityTestSucceeded ConnectivityTestState = "Succeeded"
+	ConnectivityTestFailed    ConnectivityTestState = "Failed"
+)
+
+// ConnectivityTestStatus is the status of a dataplane connectivity test.
+type ConnectivityTestStatus struct {
+	// Trigger is the trigger of the connectivity test when it started.
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+
+	// OperatorVersion is the version of the operator which ran the test.
+	// +optional
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+
+	// State is the state of the test.
+	// +kubebuilder:validation:Enum=Running;Succeeded;Failed
+	State ConnectivityTestState `json:"state"`
+
+	// Message reports the checks which failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is the time the test started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the test completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Node is the node migrated to the VPP dataplane whose connectivity the test verified, when it ran for the
+	// dataplane migration.
+	// +optional
+	Node string `json:"node,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -36,6 +36,22 @@ type LogCollectorSpec struct {
 	// +optional
 	// +kubebuilder:validation:Enum=Enabled;Disabled
 	CollectProcessPath *CollectProcessPathOption `json:"collectProcessPath,omitempty"`
+
+	// Configuration for enabling/disabling the collection of the events of the VPP dataplane, e.g. the interface
+	// flaps and the API errors of VPP, into their own index next to the flow logs. Only used with the VPP dataplane.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	CollectDataplaneEvents *CollectDataplaneEventsOption `json:"collectDataplaneEvents,omitempty"`
+
+	// NamespaceSelection selects the namespaces whose flow and L7 logs are collected, to limit the volume of the logs
+	// of shared clusters. With OptIn, only the namespaces labeled operator.tigera.io/log-collection=Enabled are
+	// collected. With OptOut, all the namespaces but the ones labeled operator.tigera.io/log-collection=Disabled are
+	// collected. A log is collected if either of its endpoints is in a collected namespace.
+	// Default: AllNamespaces
+	// +optional
+	// +kubebuilder:validation:Enum=AllNamespaces;OptIn;OptOut
+	NamespaceSelection *LogCollectionNamespaceSelection `json:"namespaceSelection,omitempty"`
 }
 
 type CollectProcessPathOption string
@@ -45,6 +61,21 @@ const (
 	CollectProcessPathDisable CollectProcessPathOption = "Disabled"
 )
 
+type CollectDataplaneEventsOption string
+
+const (
+	CollectDataplaneEventsEnable  CollectDataplaneEventsOption = "Enabled"
+	CollectDataplaneEventsDisable CollectDataplaneEventsOption = "Disabled"
+)
+
+type LogCollectionNamespaceSelection string
+
+const (
+	LogCollectionAllNamespaces LogCollectionNamespaceSelection = "AllNamespaces"
+	LogCollectionOptIn         LogCollectionNamespaceSelection = "OptIn"
+	LogCollectionOptOut        LogCollectionNamespaceSelection = "OptOut"
+)
+
 type AdditionalLogStoreSpec struct {
 	// If specified, enables exporting of flow, audit, and DNS logs to Amazon S3 storage.
 	// +optional
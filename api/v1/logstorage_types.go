@@ -52,6 +52,36 @@ type LogStorageSpec struct {
 	// Only ECKOperator is supported for this spec.
 	// +optional
 	ComponentResources []LogStorageComponentResource `json:"componentResources,omitempty"`
+
+	// ESGateway configures the es-gateway deployment, which fronts Elasticsearch and Kibana for the log ingestion and
+	// the queries of the other components.
+	// +optional
+	ESGateway *ESGatewaySpec `json:"esGateway,omitempty"`
+}
+
+// ESGatewaySpec configures the es-gateway deployment.
+type ESGatewaySpec struct {
+	// Replicas is the number of es-gateway pods. When omitted, the ControlPlaneReplicas of the Installation is used.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ResourceRequirements allows customization of limits and requests for compute resources such as cpu and memory.
+	// +optional
+	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+
+	// TLS configures the TLS of the HTTPS server of es-gateway.
+	// +optional
+	TLS *TLSSettings `json:"tls,omitempty"`
+}
+
+// TLSSettings configures the TLS of the HTTPS server of a component. The servers accept TLS 1.2 and TLS 1.3.
+type TLSSettings struct {
+	// CipherSuites are the cipher suites the server accepts with TLS 1.2, named as in the IANA registry, e.g.
+	// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. The cipher suites of TLS 1.3 can't be configured. When omitted, the
+	// default cipher suites of the component are accepted.
+	// +optional
+	CipherSuites []string `json:"cipherSuites,omitempty"`
 }
 
 // LogStorageStatus defines the observed state of Tigera flow and DNS log storage.
@@ -77,7 +107,10 @@ type Nodes struct {
 	// +optional
 	NodeSets []NodeSet `json:"nodeSets,omitempty"`
 
-	// ResourceRequirements defines the resource limits and requirements for the Elasticsearch cluster.
+	// ResourceRequirements defines the resource limits and requirements for the Elasticsearch cluster. The storage
+	// requirement sets the size of the volumes of the Elasticsearch nodes. When it is increased and the storage class
+	// allows volume expansion, the volumes are expanded in place. Otherwise, the Elasticsearch nodes are replaced by
+	// nodes with volumes of the new size, and the data is migrated to them.
 	// +optional
 	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
 }
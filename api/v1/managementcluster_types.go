@@ -27,6 +27,87 @@ type ManagementClusterSpec struct {
 	// Valid examples are: "0.0.0.0:31000", "example.com:32000", "[::1]:32500"
 	// +optional
 	Address string `json:"address,omitempty"`
+
+	// TunnelCertificateRotation configures the rotation of the CA that signs the tunnel certificates of the managed
+	// clusters.
+	// +optional
+	TunnelCertificateRotation *TunnelCertificateRotation `json:"tunnelCertificateRotation,omitempty"`
+
+	// TunnelKeepAlive tunes how Voltron detects broken tunnels, for managed clusters connected over lossy links.
+	// +optional
+	TunnelKeepAlive *TunnelKeepAlive `json:"tunnelKeepAlive,omitempty"`
+
+	// ManagedClusterAccess restricts which users and groups of the management cluster Voltron impersonates into which
+	// managed clusters. When set, a user may only access the managed clusters that one of the rules grants them access
+	// to. When omitted, every user of the manager may access all managed clusters.
+	// +optional
+	ManagedClusterAccess []ManagedClusterAccessRule `json:"managedClusterAccess,omitempty"`
+}
+
+// ManagedClusterAccessRule grants users and groups of the management cluster access to a set of managed clusters.
+type ManagedClusterAccessRule struct {
+	// Name identifies the rule. The RBAC rendered for the rule is named after it.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// ManagedClusters are the names of the managed clusters the rule grants access to. When omitted, the rule grants
+	// access to all managed clusters.
+	// +optional
+	ManagedClusters []string `json:"managedClusters,omitempty"`
+
+	// Users are the users of the management cluster that are granted access.
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Groups are the groups of the management cluster that are granted access.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+}
+
+// TunnelKeepAlive tunes the keepalive of the tunnel between a managed cluster and its management cluster. Fields that are
+// not set keep the defaults of Voltron and guardian.
+type TunnelKeepAlive struct {
+	// Interval is how often a keepalive message is sent over the tunnel.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Timeout is how long the tunnel may go without a response to its keepalive messages before it is closed.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// TCPKeepAlivePeriod is the keepalive period of the TCP connection carrying the tunnel.
+	// +optional
+	TCPKeepAlivePeriod *metav1.Duration `json:"tcpKeepAlivePeriod,omitempty"`
+}
+
+// TunnelCertificateRotation configures when the tunnel CA is rotated and for how long the previous CA remains trusted.
+type TunnelCertificateRotation struct {
+	// RotateBefore is how long before the expiry of the tunnel CA a new CA is created.
+	// Default: 720h
+	// +optional
+	RotateBefore *metav1.Duration `json:"rotateBefore,omitempty"`
+
+	// OverlapPeriod is how long both the previous and the new tunnel CA are trusted after a rotation started. Managed
+	// clusters must apply their regenerated manifest within this period to stay connected.
+	// Default: 168h
+	// +optional
+	OverlapPeriod *metav1.Duration `json:"overlapPeriod,omitempty"`
+}
+
+const (
+	// TunnelCertificateRotationCondition is true while the tunnel CA is being rotated.
+	TunnelCertificateRotationCondition = "TunnelCertificateRotation"
+
+	TunnelCertificateRotationInProgress = "RotationInProgress"
+	TunnelCertificateRotationScheduled  = "RotationScheduled"
+)
+
+// ManagementClusterStatus defines the observed state of a ManagementCluster
+type ManagementClusterStatus struct {
+	// Conditions represents the latest observed state of the management cluster, such as the progress of a tunnel
+	// certificate rotation.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -39,7 +120,8 @@ type ManagementCluster struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec ManagementClusterSpec `json:"spec,omitempty"`
+	Spec   ManagementClusterSpec   `json:"spec,omitempty"`
+	Status ManagementClusterStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -26,6 +27,101 @@ type ManagementClusterConnectionSpec struct {
 	// should be able to access this address. This field is used by managed clusters only.
 	// +optional
 	ManagementClusterAddr string `json:"managementClusterAddr,omitempty"`
+
+	// Proxy configures the HTTPS proxy guardian connects through to reach the management cluster, for managed
+	// clusters that have no direct egress.
+	// +optional
+	Proxy *GuardianProxy `json:"proxy,omitempty"`
+
+	// TunnelKeepAlive tunes how guardian detects a broken tunnel, for managed clusters connected over lossy links.
+	// +optional
+	TunnelKeepAlive *TunnelKeepAlive `json:"tunnelKeepAlive,omitempty"`
+
+	// ReconnectBackoff tunes how guardian retries to establish a broken tunnel.
+	// +optional
+	ReconnectBackoff *TunnelReconnectBackoff `json:"reconnectBackoff,omitempty"`
+
+	// GuardianDeployment overrides the resources and scheduling of the guardian deployment, for instance to run it
+	// on small edge nodes.
+	// +optional
+	GuardianDeployment *GuardianDeployment `json:"guardianDeployment,omitempty"`
+}
+
+// GuardianDeployment overrides the settings of the guardian deployment.
+type GuardianDeployment struct {
+	// Replicas is the number of guardian pods.
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources allows customization of limits and requests for compute resources such as cpu and memory for the
+	// guardian container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector is used to select the nodes guardian runs on. If set, it replaces the ControlPlaneNodeSelector of the
+	// Installation for guardian.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are applied to the guardian pods. If set, they replace the ControlPlaneTolerations of the
+	// Installation for guardian.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// TunnelReconnectBackoff configures the exponential backoff between attempts to re-establish the tunnel. Fields that
+// are not set keep the defaults of guardian.
+type TunnelReconnectBackoff struct {
+	// InitialInterval is the delay before the first attempt to reconnect.
+	// +optional
+	InitialInterval *metav1.Duration `json:"initialInterval,omitempty"`
+
+	// MaxInterval is the maximum delay between two attempts to reconnect.
+	// +optional
+	MaxInterval *metav1.Duration `json:"maxInterval,omitempty"`
+}
+
+// GuardianProxy configures the HTTPS proxy of the tunnel to the management cluster.
+type GuardianProxy struct {
+	// HTTPSProxy is the URL of the proxy. Ex.: "http://proxy.example.com:3128".
+	HTTPSProxy string `json:"httpsProxy"`
+
+	// NoProxy is a list of additional hosts, domains or CIDRs guardian reaches without the proxy. In-cluster services
+	// and the Kubernetes API server are always reached directly.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+
+	// CASecretName is the name of a secret in the operator namespace that holds, under the ca.crt key, the CA
+	// certificate guardian trusts in addition to the system CAs, such as the CA of a TLS intercepting proxy.
+	// +optional
+	CASecretName string `json:"caSecretName,omitempty"`
+}
+
+// ManagementClusterConnectionStatus defines the observed state of ManagementClusterConnection
+type ManagementClusterConnectionStatus struct {
+	// Tunnel reports the health of the tunnel to the management cluster, as observed by guardian.
+	// +optional
+	Tunnel *TunnelStatus `json:"tunnel,omitempty"`
+}
+
+// TunnelStatus reports the health of the tunnel between a managed cluster and its management cluster.
+type TunnelStatus struct {
+	// Connected is whether the tunnel to the management cluster is currently established.
+	Connected bool `json:"connected"`
+
+	// LastHeartbeatTime is when guardian last received a heartbeat from the management cluster.
+	// +optional
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+
+	// RoundTripTime is the round trip time of the last heartbeat.
+	// +optional
+	RoundTripTime *metav1.Duration `json:"roundTripTime,omitempty"`
+
+	// ReconnectCount is how many times guardian re-established the tunnel since it started.
+	// +optional
+	ReconnectCount int64 `json:"reconnectCount,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -38,7 +134,8 @@ type ManagementClusterConnection struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec ManagementClusterConnectionSpec `json:"spec,omitempty"`
+	Spec   ManagementClusterConnectionSpec   `json:"spec,omitempty"`
+	Status ManagementClusterConnectionStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
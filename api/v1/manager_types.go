@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,6 +26,29 @@ type ManagerSpec struct {
 	// Deprecated. Please use the Authentication CR for configuring authentication.
 	// +optional
 	Auth *Auth `json:"auth,omitempty"`
+
+	// Replicas is the number of tigera-manager pods, which serve the UI and proxy its queries to Elasticsearch with
+	// es-proxy. When omitted, the ControlPlaneReplicas of the Installation is used. It is ignored on management and
+	// managed clusters, which run a single pod.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ESProxy configures the es-proxy container of the tigera-manager pods.
+	// +optional
+	ESProxy *ESProxySpec `json:"esProxy,omitempty"`
+
+	// TLS configures the TLS of the HTTPS server of the tigera-manager pods, which is terminated by their voltron
+	// container before the queries are proxied to the UI and es-proxy.
+	// +optional
+	TLS *TLSSettings `json:"tls,omitempty"`
+}
+
+// ESProxySpec configures the es-proxy container of the tigera-manager pods.
+type ESProxySpec struct {
+	// ResourceRequirements allows customization of limits and requests for compute resources such as cpu and memory.
+	// +optional
+	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
 }
 
 // ManagerStatus defines the observed state of the Calico Enterprise manager GUI.
@@ -17,11 +17,252 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // MonitorSpec defines the desired state of Tigera monitor.
 type MonitorSpec struct {
+	// Prometheus configures the Prometheus instance deployed by the operator.
+	// +optional
+	Prometheus *Prometheus `json:"prometheus,omitempty"`
+
+	// ScrapeTargets overrides the scrape configuration of the targets monitored by Prometheus, e.g. to scrape
+	// high-cardinality metrics less often or to drop them.
+	// +optional
+	ScrapeTargets []ScrapeTarget `json:"scrapeTargets,omitempty"`
+}
+
+// ScrapeTargetName is the name of a target monitored by Prometheus.
+// +kubebuilder:validation:Enum=CalicoNode;ElasticsearchMetrics;FluentdMetrics;VPP
+type ScrapeTargetName string
+
+const (
+	ScrapeTargetCalicoNode           ScrapeTargetName = "CalicoNode"
+	ScrapeTargetElasticsearchMetrics ScrapeTargetName = "ElasticsearchMetrics"
+	ScrapeTargetFluentdMetrics       ScrapeTargetName = "FluentdMetrics"
+	ScrapeTargetVPP                  ScrapeTargetName = "VPP"
+)
+
+// ScrapeTarget defines the scrape configuration overrides of a target monitored by Prometheus.
+type ScrapeTarget struct {
+	// Name of the target the overrides apply to.
+	Name ScrapeTargetName `json:"name"`
+
+	// Interval at which the metrics of the target are scraped.
+	// Default: 5s
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	Interval string `json:"interval,omitempty"`
+
+	// ScrapeTimeout is the timeout after which a scrape of the target is aborted. It must not be greater than Interval.
+	// Default: 5s, or Interval if that is shorter.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	ScrapeTimeout string `json:"scrapeTimeout,omitempty"`
+
+	// MetricRelabelConfigs is the list of relabel configurations applied to the scraped samples before they are
+	// ingested, e.g. to drop high-cardinality metrics.
+	// +optional
+	MetricRelabelConfigs []RelabelConfig `json:"metricRelabelConfigs,omitempty"`
+}
+
+// Prometheus defines the configuration of the Prometheus instance deployed by the operator.
+type Prometheus struct {
+	// Retention is the duration for which Prometheus retains metrics. It must match the regular expression
+	// [0-9]+(ms|s|m|h|d|w|y) (milliseconds seconds minutes hours days weeks years).
+	// Default: 24h
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	Retention string `json:"retention,omitempty"`
+
+	// RetentionSize is the maximum number of bytes used by the stored metrics, e.g. 512MB. When both Retention and
+	// RetentionSize are set, whichever limit is reached first triggers the removal of old metrics.
+	// If not specified, the stored metrics are not limited by size.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(0|([0-9]*[.])?[0-9]+((K|M|G|T|E|P)i?)?B)$`
+	RetentionSize string `json:"retentionSize,omitempty"`
+
+	// Storage configures the persistent storage used by Prometheus. If not specified, metrics are stored in an
+	// emptyDir volume and are lost when the Prometheus pod restarts.
+	// +optional
+	Storage *PrometheusStorage `json:"storage,omitempty"`
+
+	// Resources allows customization of limits and requests for compute resources such as cpu and memory for the
+	// Prometheus container.
+	// Default: 400Mi memory request.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// RemoteWrite is a list of endpoints to which Prometheus sends samples using the remote write protocol,
+	// e.g. Cortex, Mimir or Thanos receivers.
+	// +optional
+	RemoteWrite []PrometheusRemoteWrite `json:"remoteWrite,omitempty"`
+
+	// Thanos configures a Thanos sidecar that runs alongside Prometheus. If not specified, no sidecar is deployed.
+	// +optional
+	Thanos *PrometheusThanos `json:"thanos,omitempty"`
+
+	// Replicas is the number of Prometheus replicas. When more than one replica is configured, the replicas are spread
+	// across nodes, protected by a PodDisruptionBudget and their samples carry a "prometheus_replica" external label
+	// so that they can be deduplicated by the receiving system.
+	// Default: 1
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ExternalLabels are the labels added to the samples sent to external systems, e.g. through remote write or the
+	// Thanos sidecar.
+	// +optional
+	ExternalLabels map[string]string `json:"externalLabels,omitempty"`
+}
+
+// PrometheusThanos defines the configuration of the Thanos sidecar.
+type PrometheusThanos struct {
+	// ObjectStorageConfigSecretName is the name of a secret in the tigera-operator namespace holding the Thanos object
+	// storage configuration under the "objstore.yml" key. When set, the sidecar uploads the Prometheus TSDB blocks to
+	// the object storage for long-term retention.
+	// +optional
+	ObjectStorageConfigSecretName string `json:"objectStorageConfigSecretName,omitempty"`
+
+	// Resources allows customization of limits and requests for compute resources such as cpu and memory for the
+	// Thanos sidecar container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// PrometheusRemoteWrite defines an endpoint to which Prometheus sends samples using the remote write protocol.
+type PrometheusRemoteWrite struct {
+	// URL is the URL of the endpoint to send samples to.
+	URL string `json:"url"`
+
+	// Name of the remote write queue. If specified it must be unique, it is used in metrics and logging to
+	// differentiate queues.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// AuthSecretName is the name of a secret in the tigera-operator namespace holding the credentials used to
+	// authenticate against the endpoint. A secret with "username" and "password" keys configures basic authentication,
+	// a secret with a "token" key configures bearer token authentication.
+	// +optional
+	AuthSecretName string `json:"authSecretName,omitempty"`
+
+	// WriteRelabelConfigs is the list of relabel configurations applied to samples before they are sent to the endpoint.
+	// +optional
+	WriteRelabelConfigs []RelabelConfig `json:"writeRelabelConfigs,omitempty"`
+
+	// QueueConfig allows tuning of the remote write queue parameters.
+	// +optional
+	QueueConfig *RemoteWriteQueueConfig `json:"queueConfig,omitempty"`
+}
+
+// RelabelAction is the action performed by a relabel configuration.
+// +kubebuilder:validation:Enum=replace;keep;drop;hashmod;labelmap;labeldrop;labelkeep
+type RelabelAction string
+
+const (
+	RelabelActionReplace   RelabelAction = "replace"
+	RelabelActionKeep      RelabelAction = "keep"
+	RelabelActionDrop      RelabelAction = "drop"
+	RelabelActionHashMod   RelabelAction = "hashmod"
+	RelabelActionLabelMap  RelabelAction = "labelmap"
+	RelabelActionLabelDrop RelabelAction = "labeldrop"
+	RelabelActionLabelKeep RelabelAction = "labelkeep"
+)
+
+// RelabelConfig defines a rewrite of the label set of the samples, as in the relabel_config section of the Prometheus
+// configuration.
+type RelabelConfig struct {
+	// SourceLabels select values from existing labels. Their content is concatenated using Separator and matched
+	// against Regex.
+	// +optional
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+
+	// Separator placed between the concatenated source label values.
+	// Default: ;
+	// +optional
+	Separator string `json:"separator,omitempty"`
+
+	// TargetLabel is the label to which the resulting value is written in a replace action. Regex capture groups are
+	// available.
+	// +optional
+	TargetLabel string `json:"targetLabel,omitempty"`
+
+	// Regex is the regular expression against which the extracted value is matched.
+	// Default: (.*)
+	// +optional
+	Regex string `json:"regex,omitempty"`
+
+	// Modulus to take of the hash of the source label values in a hashmod action.
+	// +optional
+	Modulus uint64 `json:"modulus,omitempty"`
+
+	// Replacement is the value written to TargetLabel in a replace action if Regex matches. Regex capture groups are
+	// available.
+	// Default: $1
+	// +optional
+	Replacement string `json:"replacement,omitempty"`
+
+	// Action to perform based on the match of Regex.
+	// Default: replace
+	// +optional
+	Action RelabelAction `json:"action,omitempty"`
+}
+
+// RemoteWriteQueueConfig defines the tuning of the queue of samples sent to a remote write endpoint.
+type RemoteWriteQueueConfig struct {
+	// Capacity is the number of samples to buffer per shard before samples are dropped.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Capacity int32 `json:"capacity,omitempty"`
+
+	// MinShards is the minimum number of shards, i.e. amount of concurrency.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinShards int32 `json:"minShards,omitempty"`
+
+	// MaxShards is the maximum number of shards, i.e. amount of concurrency.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxShards int32 `json:"maxShards,omitempty"`
+
+	// MaxSamplesPerSend is the maximum number of samples per send.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxSamplesPerSend int32 `json:"maxSamplesPerSend,omitempty"`
+
+	// BatchSendDeadline is the maximum time a sample waits in the buffer.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	BatchSendDeadline string `json:"batchSendDeadline,omitempty"`
+
+	// MaxRetries is the maximum number of times a batch is retried on recoverable errors.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries int32 `json:"maxRetries,omitempty"`
+
+	// MinBackoff is the initial retry delay. It is doubled on every retry.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	MinBackoff string `json:"minBackoff,omitempty"`
+
+	// MaxBackoff is the maximum retry delay.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	MaxBackoff string `json:"maxBackoff,omitempty"`
+}
+
+// PrometheusStorage defines the persistent storage used by Prometheus.
+type PrometheusStorage struct {
+	// StorageClassName is the name of the StorageClass used to provision the PersistentVolumeClaim for Prometheus.
+	// If not specified, the default StorageClass of the cluster is used.
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// Size is the size of the PersistentVolumeClaim requested for Prometheus.
+	// +kubebuilder:validation:Required
+	Size resource.Quantity `json:"size"`
 }
 
 // MonitorStatus defines the observed state of Tigera monitor.
@@ -0,0 +1,60 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenantSpec defines the desired state of a Tenant
+type TenantSpec struct {
+	// ID uniquely identifies the tenant on the management cluster.
+	// +kubebuilder:validation:MinLength=1
+	ID string `json:"id"`
+
+	// Address is the externally reachable address to which the managed clusters of the tenant connect. It is used to
+	// populate the manifests of the managed clusters of the tenant. When omitted, the address of the ManagementCluster is
+	// used.
+	// +optional
+	Address string `json:"address,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced
+
+// Tenant isolates a set of managed clusters of a management cluster. A Voltron instance with its own tunnel CA and RBAC
+// is deployed in the namespace of the Tenant, and accepts the tunnels of the managed clusters of the tenant only. At most
+// one instance of this resource is supported per namespace. It must be named "default".
+type Tenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TenantSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TenantList contains a list of Tenant
+type TenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Tenant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Tenant{}, &TenantList{})
+}
@@ -33,6 +33,72 @@ type TigeraStatusStatus struct {
 	// Conditions represents the latest observed set of conditions for this component. A component may be one or more of
 	// Available, Progressing, or Degraded.
 	Conditions []TigeraStatusCondition `json:"conditions"`
+
+	// Encryption is the status of the encryption of the traffic between the nodes, when WireGuard or IPsec is
+	// enabled. It is only reported for the calico component.
+	// +optional
+	Encryption *EncryptionStatus `json:"encryption,omitempty"`
+}
+
+// EncryptionType is the encryption of the traffic between the nodes.
+// One of: WireGuard, IPsec
+type EncryptionType string
+
+const (
+	EncryptionWireGuard EncryptionType = "WireGuard"
+	EncryptionIPsec     EncryptionType = "IPsec"
+)
+
+// EncryptionStatus is the status of the encryption of the traffic between the nodes. It is collected from the
+// Prometheus metrics of Felix, which are only served when the NodeMetricsPort of the Installation is set. With the
+// VPP dataplane, the traffic is encrypted by VPP rather than by Felix, so the status of the nodes is not collected.
+type EncryptionStatus struct {
+	// Type is the encryption enabled in the default FelixConfiguration.
+	// +kubebuilder:validation:Enum=WireGuard;IPsec
+	Type EncryptionType `json:"type"`
+
+	// Message reports why the status of the nodes could not be collected.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// EncryptedNodes is the number of nodes running calico-node which have an established encrypted session with
+	// all the other nodes.
+	// +optional
+	EncryptedNodes int32 `json:"encryptedNodes,omitempty"`
+
+	// UnencryptedNodes is the number of nodes running calico-node which lack an established encrypted session with
+	// some other nodes, or whose status could not be collected.
+	// +optional
+	UnencryptedNodes int32 `json:"unencryptedNodes,omitempty"`
+
+	// Nodes is the encryption status of the nodes counted in UnencryptedNodes, sorted by name. At most 100 nodes
+	// are reported.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Nodes []NodeEncryptionStatus `json:"nodes,omitempty"`
+}
+
+// NodeEncryptionStatus is the encryption status of the traffic of a node.
+type NodeEncryptionStatus struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+
+	// EstablishedPeers is the number of other nodes with which the node has an established encrypted session. A
+	// WireGuard session is established when its latest handshake is less than 3 minutes old.
+	EstablishedPeers int32 `json:"establishedPeers"`
+
+	// OldestHandshakeSeconds is the age of the oldest latest handshake of the established WireGuard sessions of the
+	// node. It is not reported for IPsec.
+	// +optional
+	OldestHandshakeSeconds *int64 `json:"oldestHandshakeSeconds,omitempty"`
+
+	// UnencryptedPeers is the number of other nodes with which the node has no established encrypted session, so
+	// that its traffic to them falls back to being sent unencrypted.
+	UnencryptedPeers int32 `json:"unencryptedPeers"`
+
+	// Error reports why the status of the node could not be collected.
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -45,6 +111,7 @@ type TigeraStatusStatus struct {
 // +kubebuilder:printcolumn:name="Progressing",type="string",JSONPath=".status.conditions[?(@.type=='Progressing')].status",description="Whether the component is processing changes."
 // +kubebuilder:printcolumn:name="Degraded",type="string",JSONPath=".status.conditions[?(@.type=='Degraded')].status",description="Whether the component is degraded."
 // +kubebuilder:printcolumn:name="Since",type="date",JSONPath=".status.conditions[?(@.type=='Available')].lastTransitionTime",description="The time the component's Available status last changed."
+// +kubebuilder:printcolumn:name="Encrypted",type="string",JSONPath=".status.conditions[?(@.type=='Encrypted')].status",description="Whether the traffic between the nodes is encrypted.",priority=1
 type TigeraStatus struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -74,12 +141,20 @@ const (
 
 	// Degraded means the component is not operating as desired and user action is required.
 	ComponentDegraded StatusConditionType = "Degraded"
+
+	// Encrypted means that the traffic between all the nodes is encrypted with WireGuard or IPsec. It is only
+	// reported for the calico component.
+	ComponentEncrypted StatusConditionType = "Encrypted"
+
+	// PullSecretsSynced means that the copies of the pull secrets in the namespaces of the components are up to date
+	// with the pull secrets in the operator namespace. It is only reported for the calico component.
+	ComponentPullSecretsSynced StatusConditionType = "PullSecretsSynced"
 )
 
 // TigeraStatusCondition represents a condition attached to a particular component.
 // +k8s:deepcopy-gen=true
 type TigeraStatusCondition struct {
-	// The type of condition. May be Available, Progressing, or Degraded.
+	// The type of condition. May be Available, Progressing, Degraded, Encrypted, or PullSecretsSynced.
 	Type StatusConditionType `json:"type"`
 
 	// The status of the condition. May be True, False, or Unknown.
@@ -0,0 +1,301 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VPPLogLevel is the log level of the VPP agent.
+// One of: Error, Warning, Info, Debug
+// +kubebuilder:validation:Enum=Error;Warning;Info;Debug
+type VPPLogLevel string
+
+const (
+	VPPLogLevelError   VPPLogLevel = "Error"
+	VPPLogLevelWarning VPPLogLevel = "Warning"
+	VPPLogLevelInfo    VPPLogLevel = "Info"
+	VPPLogLevelDebug   VPPLogLevel = "Debug"
+)
+
+// VPPGSOType controls whether VPP uses the generic segmentation offload on the interfaces of the pods.
+// One of: Enabled, Disabled
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type VPPGSOType string
+
+const (
+	VPPGSOEnabled  VPPGSOType = "Enabled"
+	VPPGSODisabled VPPGSOType = "Disabled"
+)
+
+// VPPBootstrapTaintType controls whether the operator taints the nodes of the VPP dataplane until their dataplane is
+// ready.
+// One of: Enabled, Disabled
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type VPPBootstrapTaintType string
+
+const (
+	VPPBootstrapTaintEnabled  VPPBootstrapTaintType = "Enabled"
+	VPPBootstrapTaintDisabled VPPBootstrapTaintType = "Disabled"
+)
+
+// VPPRxMode is how VPP receives the packets of an uplink interface.
+// One of: Polling, Interrupt, Adaptive
+// +kubebuilder:validation:Enum=Polling;Interrupt;Adaptive
+type VPPRxMode string
+
+const (
+	VPPRxModePolling   VPPRxMode = "Polling"
+	VPPRxModeInterrupt VPPRxMode = "Interrupt"
+	VPPRxModeAdaptive  VPPRxMode = "Adaptive"
+)
+
+// VPPDataplaneSpec defines the desired state of VPPDataplane
+type VPPDataplaneSpec struct {
+	// UplinkInterface is the interface of the nodes which VPP takes over to connect them to the network. It can't be
+	// set along with UplinkInterfaces.
+	// Default: the interface of the default route of the node
+	// +optional
+	UplinkInterface string `json:"uplinkInterface,omitempty"`
+
+	// UplinkInterfaces are the interfaces of the nodes which VPP takes over to connect them to the network, for the
+	// nodes with several NICs carrying the traffic of the pods. The VPP agent migrates the addresses and the routes of
+	// each of them from Linux to VPP, and the first one carries the address of the node. The migration status of
+	// each uplink of each node is reported in the status. It can't be set along with UplinkInterface.
+	// +optional
+	UplinkInterfaces []VPPUplinkInterface `json:"uplinkInterfaces,omitempty"`
+
+	// UplinkDriver is the driver used by VPP for the uplink interface of the nodes whose VPP profile doesn't set one.
+	// The calico-vpp-node pods load the kernel modules of the drivers in use before VPP starts. If omitted, VPP
+	// selects a driver supported by the uplink interface.
+	// +optional
+	// +kubebuilder:validation:Enum=af_packet;af_xdp;avf;dpdk;rdma;virtio;vmxnet3
+	UplinkDriver *VPPUplinkDriver `json:"uplinkDriver,omitempty"`
+
+	// ServicePrefixes are the CIDRs of the Kubernetes Services, which VPP load balances to their endpoints. They
+	// must match the service CIDRs of the cluster.
+	// Default: 10.96.0.0/12
+	// +optional
+	ServicePrefixes []string `json:"servicePrefixes,omitempty"`
+
+	// LogLevel is the log level of the VPP agent.
+	// Default: Info
+	// +optional
+	LogLevel *VPPLogLevel `json:"logLevel,omitempty"`
+
+	// GSO controls whether VPP uses the generic segmentation offload on the interfaces of the pods, which improves
+	// the throughput of TCP.
+	// Default: Enabled
+	// +optional
+	GSO *VPPGSOType `json:"gso,omitempty"`
+
+	// Hugepages opts in the provisioning of the hugepages VPP allocates its packet buffers from, which the dpdk
+	// uplink driver requires, on the nodes of the VPP dataplane. The calico-vpp-hugepages DaemonSet reserves them,
+	// the calico-vpp-node pods wait for them before starting VPP, and their readiness on each node is reported in
+	// the status. If omitted, the hugepages must be reserved on the nodes beforehand.
+	// +optional
+	Hugepages *VPPHugepages `json:"hugepages,omitempty"`
+
+	// BootstrapTaint controls whether the operator taints the nodes joining the VPP dataplane with the NoSchedule
+	// taint operator.tigera.io/vpp-dataplane-bootstrap until their calico-vpp-node pod is ready, so that the pods
+	// don't land on nodes without networking. The taint is removed once the dataplane of the node is ready, and isn't
+	// put back when it restarts. The nodes may register with the taint to be covered before the operator sees them.
+	// Default: Disabled
+	// +optional
+	BootstrapTaint *VPPBootstrapTaintType `json:"bootstrapTaint,omitempty"`
+
+	// Buffers sizes the packet buffers allocated by VPP on the nodes without a VPP profile, for the high throughput
+	// deployments. The VPP profiles size them on their nodes. If omitted, the VPP defaults are used.
+	// +optional
+	Buffers *VPPBuffers `json:"buffers,omitempty"`
+
+	// Memory sizes the memory regions of VPP on the nodes without a VPP profile, e.g. a larger main heap for large
+	// routing tables. The VPP profiles size them on their nodes. If omitted, the VPP defaults are used.
+	// +optional
+	Memory *VPPMemory `json:"memory,omitempty"`
+
+	// VPPResources are the resources of the vpp container of the calico-vpp-node pods. The memory regions of VPP, of
+	// the VPPDataplane and of each VPP profile, must fit within its memory limit. The VPP profiles pinning the CPUs with
+	// the CPU manager require a memory limit, and a CPU limit, if set, of the workers of each of them plus one.
+	// +optional
+	VPPResources *corev1.ResourceRequirements `json:"vppResources,omitempty"`
+
+	// AgentResources are the resources of the agent container of the calico-vpp-node pods. The VPP profiles pinning
+	// the CPUs with the CPU manager require its CPU and memory limits.
+	// +optional
+	AgentResources *corev1.ResourceRequirements `json:"agentResources,omitempty"`
+}
+
+// VPPMemory sizes the memory regions of VPP. The sizes are rounded down to a multiple of 1Ki.
+type VPPMemory struct {
+	// MainHeapSize is the size of the main heap of VPP, which holds the routing tables, the sessions and the other
+	// state of the dataplane. If omitted, the VPP default is used.
+	// +optional
+	MainHeapSize *resource.Quantity `json:"mainHeapSize,omitempty"`
+
+	// StatsSegmentSize is the size of the shared memory segment holding the statistics of VPP, which grows with the
+	// number of interfaces and routes. If omitted, the VPP default is used.
+	// +optional
+	StatsSegmentSize *resource.Quantity `json:"statsSegmentSize,omitempty"`
+
+	// APISegmentSize is the size of the shared memory segment of the binary API of VPP, used by the VPP agent. If
+	// omitted, the VPP default is used.
+	// +optional
+	APISegmentSize *resource.Quantity `json:"apiSegmentSize,omitempty"`
+}
+
+// VPPBuffers sizes the packet buffers allocated by VPP.
+type VPPBuffers struct {
+	// BuffersPerNUMA is the number of packet buffers allocated by VPP on each NUMA node.
+	// Default: 16384
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BuffersPerNUMA *int32 `json:"buffersPerNUMA,omitempty"`
+
+	// DataSize is the size in bytes of the data of each packet buffer, which should hold the largest frames of the
+	// uplink for them not to be chained, e.g. 9216 with jumbo frames.
+	// Default: 2048
+	// +optional
+	// +kubebuilder:validation:Minimum=512
+	// +kubebuilder:validation:Maximum=65535
+	DataSize *int32 `json:"dataSize,omitempty"`
+}
+
+// VPPHugepages is the number and the size of the hugepages reserved on each node of the VPP dataplane.
+type VPPHugepages struct {
+	// Count is the number of hugepages reserved on each node. The hugepages already reserved on a node are kept when
+	// there are more of them.
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count"`
+
+	// Size is the size of the hugepages.
+	// Default: 2Mi
+	// +optional
+	// +kubebuilder:validation:Enum=2Mi;1Gi
+	Size *VPPHugepageSize `json:"size,omitempty"`
+}
+
+// VPPHugepageSize is the size of the hugepages.
+// One of: 2Mi, 1Gi
+type VPPHugepageSize string
+
+const (
+	VPPHugepageSize2Mi VPPHugepageSize = "2Mi"
+	VPPHugepageSize1Gi VPPHugepageSize = "1Gi"
+)
+
+// VPPUplinkInterface is an interface of the nodes which VPP takes over. Exactly one of InterfaceName and PCIAddress
+// must be set.
+type VPPUplinkInterface struct {
+	// InterfaceName is the name of the interface in Linux.
+	// +optional
+	InterfaceName string `json:"interfaceName,omitempty"`
+
+	// PCIAddress is the PCI address of the interface, e.g. 0000:00:08.0, for the interfaces whose name differs
+	// between the nodes.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{4}:[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-7]$`
+	PCIAddress string `json:"pciAddress,omitempty"`
+
+	// Driver is the driver used by VPP for the interface. If omitted, the uplink driver of the VPP profile of the
+	// node, or of the VPPDataplane, is used.
+	// +optional
+	// +kubebuilder:validation:Enum=af_packet;af_xdp;avf;dpdk;rdma;virtio;vmxnet3
+	Driver *VPPUplinkDriver `json:"driver,omitempty"`
+
+	// RxMode is how VPP receives the packets of the interface. Polling gives the lowest latency at the cost of a
+	// core spinning, Interrupt saves the CPU at the cost of latency, and Adaptive switches between them with the load.
+	// The dpdk driver only supports Polling. If omitted, the rx mode of the VPP profile of the node is used.
+	// +optional
+	RxMode *VPPRxMode `json:"rxMode,omitempty"`
+}
+
+// VPPDataplaneStatus defines the observed state of VPPDataplane
+type VPPDataplaneStatus struct {
+	// State provides user-readable status.
+	State string `json:"state,omitempty"`
+
+	// Uplinks is the migration status of the uplink interfaces of the nodes of the VPP dataplane, when
+	// spec.uplinkInterfaces is set. The nodes whose uplinks aren't migrated are listed first, and at most 100 nodes
+	// are listed.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Uplinks []NodeUplinkStatus `json:"uplinks,omitempty"`
+
+	// Hugepages is the readiness of the hugepages of the nodes of the VPP dataplane, when spec.hugepages is set. The
+	// nodes whose hugepages aren't ready are listed first, and at most 100 nodes are listed.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Hugepages []NodeHugepagesStatus `json:"hugepages,omitempty"`
+}
+
+// NodeUplinkStatus is the migration status of the uplink interfaces of a node. The VPP manager migrates all the
+// uplinks before it starts VPP, so they are migrated once the calico-vpp-node pod of the node is ready.
+type NodeUplinkStatus struct {
+	// Node is the name of the node.
+	Node string `json:"node"`
+
+	// Ready is true when the addresses and the routes of the uplinks of the node are migrated to VPP.
+	Ready bool `json:"ready"`
+
+	// Message reports why the uplinks are not migrated.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// NodeHugepagesStatus is the readiness of the hugepages of a node.
+type NodeHugepagesStatus struct {
+	// Node is the name of the node.
+	Node string `json:"node"`
+
+	// Ready is true when the hugepages set in the spec are reserved on the node.
+	Ready bool `json:"ready"`
+
+	// Message reports why the hugepages are not reserved.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// VPPDataplane installs the VPP dataplane on the nodes migrated to it: the calico-vpp-node DaemonSet, which runs VPP
+// and the VPP agent, and its configuration. It is only supported with the VPP LinuxDataplane. At most one instance of
+// this resource is supported. It must be named "default".
+type VPPDataplane struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VPPDataplaneSpec   `json:"spec,omitempty"`
+	Status VPPDataplaneStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VPPDataplaneList contains a list of VPPDataplane
+type VPPDataplaneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VPPDataplane `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VPPDataplane{}, &VPPDataplaneList{})
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WhiskerNotificationMode controls whether the Whisker UI checks for new releases and security advisories.
+// One of: Enabled, Disabled
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type WhiskerNotificationMode string
+
+const (
+	WhiskerNotificationsEnabled  WhiskerNotificationMode = "Enabled"
+	WhiskerNotificationsDisabled WhiskerNotificationMode = "Disabled"
+)
+
+// WhiskerSpec defines the desired state of Whisker
+type WhiskerSpec struct {
+	// Notifications controls whether the Whisker UI checks for new releases of Calico and security advisories.
+	// Disable it on clusters without access to the internet.
+	// Default: Enabled
+	// +optional
+	Notifications *WhiskerNotificationMode `json:"notifications,omitempty"`
+}
+
+// WhiskerStatus defines the observed state of Whisker
+type WhiskerStatus struct {
+	// State provides user-readable status.
+	State string `json:"state,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// Whisker installs the flow visualization of the Calico variant: Goldmane, which aggregates the flows reported by
+// Felix, and the Whisker UI which displays them. At most one instance of this resource is supported. It must be named
+// "default".
+type Whisker struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WhiskerSpec   `json:"spec,omitempty"`
+	Status WhiskerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WhiskerList contains a list of Whisker
+type WhiskerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Whisker `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Whisker{}, &WhiskerList{})
+}
@@ -23,7 +23,9 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -31,7 +33,7 @@ func (in *APIServer) DeepCopyInto(out *APIServer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	out.Status = in.Status
 }
 
@@ -53,6 +55,146 @@ func (in *APIServer) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerAudit) DeepCopyInto(out *APIServerAudit) {
+	*out = *in
+	if in.Log != nil {
+		in, out := &in.Log, &out.Log
+		*out = new(APIServerAuditLog)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(APIServerAuditWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerAudit.
+func (in *APIServerAudit) DeepCopy() *APIServerAudit {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerAudit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerAuditLog) DeepCopyInto(out *APIServerAuditLog) {
+	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxBackups != nil {
+		in, out := &in.MaxBackups, &out.MaxBackups
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerAuditLog.
+func (in *APIServerAuditLog) DeepCopy() *APIServerAuditLog {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerAuditLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerAuditWebhook) DeepCopyInto(out *APIServerAuditWebhook) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(AuditWebhookMode)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerAuditWebhook.
+func (in *APIServerAuditWebhook) DeepCopy() *APIServerAuditWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerAuditWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerAutoscaling) DeepCopyInto(out *APIServerAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerAutoscaling.
+func (in *APIServerAutoscaling) DeepCopy() *APIServerAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerCertificateIssuer) DeepCopyInto(out *APIServerCertificateIssuer) {
+	*out = *in
+	if in.CertManagerIssuerRef != nil {
+		in, out := &in.CertManagerIssuerRef, &out.CertManagerIssuerRef
+		*out = new(CertManagerIssuerReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerCertificateIssuer.
+func (in *APIServerCertificateIssuer) DeepCopy() *APIServerCertificateIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerCertificateIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerComponentResource) DeepCopyInto(out *APIServerComponentResource) {
+	*out = *in
+	if in.ResourceRequirements != nil {
+		in, out := &in.ResourceRequirements, &out.ResourceRequirements
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerComponentResource.
+func (in *APIServerComponentResource) DeepCopy() *APIServerComponentResource {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerComponentResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServerList) DeepCopyInto(out *APIServerList) {
 	*out = *in
@@ -85,9 +227,145 @@ func (in *APIServerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerPodDisruptionBudget) DeepCopyInto(out *APIServerPodDisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerPodDisruptionBudget.
+func (in *APIServerPodDisruptionBudget) DeepCopy() *APIServerPodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerPodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerRequestHandling) DeepCopyInto(out *APIServerRequestHandling) {
+	*out = *in
+	if in.RequestTimeout != nil {
+		in, out := &in.RequestTimeout, &out.RequestTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxRequestsInFlight != nil {
+		in, out := &in.MaxRequestsInFlight, &out.MaxRequestsInFlight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxMutatingRequestsInFlight != nil {
+		in, out := &in.MaxMutatingRequestsInFlight, &out.MaxMutatingRequestsInFlight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PriorityAndFairness != nil {
+		in, out := &in.PriorityAndFairness, &out.PriorityAndFairness
+		*out = new(PriorityAndFairnessOption)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerRequestHandling.
+func (in *APIServerRequestHandling) DeepCopy() *APIServerRequestHandling {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerRequestHandling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServerSpec) DeepCopyInto(out *APIServerSpec) {
 	*out = *in
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(APIServerAudit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(APIServerAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisabledResources != nil {
+		in, out := &in.DisabledResources, &out.DisabledResources
+		*out = make([]APIServerResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostNetwork != nil {
+		in, out := &in.HostNetwork, &out.HostNetwork
+		*out = new(APIServerHostNetworkOption)
+		**out = **in
+	}
+	if in.RequestHandling != nil {
+		in, out := &in.RequestHandling, &out.RequestHandling
+		*out = new(APIServerRequestHandling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComponentResources != nil {
+		in, out := &in.ComponentResources, &out.ComponentResources
+		*out = make([]APIServerComponentResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueryServerLogLevel != nil {
+		in, out := &in.QueryServerLogLevel, &out.QueryServerLogLevel
+		*out = new(QueryServerLogLevel)
+		**out = **in
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(APIServerPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateIssuer != nil {
+		in, out := &in.CertificateIssuer, &out.CertificateIssuer
+		*out = new(APIServerCertificateIssuer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BaselinePolicy != nil {
+		in, out := &in.BaselinePolicy, &out.BaselinePolicy
+		*out = new(BaselinePolicyType)
+		**out = **in
+	}
+	if in.BaselineDefaultDeny != nil {
+		in, out := &in.BaselineDefaultDeny, &out.BaselineDefaultDeny
+		*out = new(BaselinePolicyType)
+		**out = **in
+	}
+	if in.NetworkSets != nil {
+		in, out := &in.NetworkSets, &out.NetworkSets
+		*out = make([]NetworkSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerSpec.
@@ -115,6 +393,58 @@ func (in *APIServerStatus) DeepCopy() *APIServerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSSecondaryIPs) DeepCopyInto(out *AWSSecondaryIPs) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(AWSSecondaryIPMode)
+		**out = **in
+	}
+	if in.RoutingRulePriority != nil {
+		in, out := &in.RoutingRulePriority, &out.RoutingRulePriority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SubnetPools != nil {
+		in, out := &in.SubnetPools, &out.SubnetPools
+		*out = make([]AWSSubnetPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSSecondaryIPs.
+func (in *AWSSecondaryIPs) DeepCopy() *AWSSecondaryIPs {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSSecondaryIPs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSSubnetPool) DeepCopyInto(out *AWSSubnetPool) {
+	*out = *in
+	if in.BlockSize != nil {
+		in, out := &in.BlockSize, &out.BlockSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSSubnetPool.
+func (in *AWSSubnetPool) DeepCopy() *AWSSubnetPool {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSSubnetPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdditionalLogSourceSpec) DeepCopyInto(out *AdditionalLogSourceSpec) {
 	*out = *in
@@ -553,54 +883,187 @@ func (in *AuthenticationStatus) DeepCopy() *AuthenticationStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CNISpec) DeepCopyInto(out *CNISpec) {
+func (in *BGPGracefulRestart) DeepCopyInto(out *BGPGracefulRestart) {
 	*out = *in
-	if in.IPAM != nil {
-		in, out := &in.IPAM, &out.IPAM
-		*out = new(IPAMSpec)
+	if in.MaxRestartTimeSeconds != nil {
+		in, out := &in.MaxRestartTimeSeconds, &out.MaxRestartTimeSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ShutdownGracePeriodSeconds != nil {
+		in, out := &in.ShutdownGracePeriodSeconds, &out.ShutdownGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ConvergenceSeconds != nil {
+		in, out := &in.ConvergenceSeconds, &out.ConvergenceSeconds
+		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CNISpec.
-func (in *CNISpec) DeepCopy() *CNISpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPGracefulRestart.
+func (in *BGPGracefulRestart) DeepCopy() *BGPGracefulRestart {
 	if in == nil {
 		return nil
 	}
-	out := new(CNISpec)
+	out := new(BGPGracefulRestart)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CalicoNetworkSpec) DeepCopyInto(out *CalicoNetworkSpec) {
+func (in *BGPPeerDefinition) DeepCopyInto(out *BGPPeerDefinition) {
 	*out = *in
-	if in.LinuxDataplane != nil {
-		in, out := &in.LinuxDataplane, &out.LinuxDataplane
-		*out = new(LinuxDataplaneOption)
-		**out = **in
-	}
-	if in.BGP != nil {
-		in, out := &in.BGP, &out.BGP
-		*out = new(BGPOption)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeerDefinition.
+func (in *BGPPeerDefinition) DeepCopy() *BGPPeerDefinition {
+	if in == nil {
+		return nil
 	}
-	if in.IPPools != nil {
-		in, out := &in.IPPools, &out.IPPools
-		*out = make([]IPPool, len(*in))
+	out := new(BGPPeerDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPeeringTemplate) DeepCopyInto(out *BGPPeeringTemplate) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]BGPPeeringTemplateValue, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.MTU != nil {
-		in, out := &in.MTU, &out.MTU
-		*out = new(int32)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeeringTemplate.
+func (in *BGPPeeringTemplate) DeepCopy() *BGPPeeringTemplate {
+	if in == nil {
+		return nil
 	}
-	if in.NodeAddressAutodetectionV4 != nil {
-		in, out := &in.NodeAddressAutodetectionV4, &out.NodeAddressAutodetectionV4
-		*out = new(NodeAddressAutodetection)
-		(*in).DeepCopyInto(*out)
+	out := new(BGPPeeringTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPeeringTemplateValue) DeepCopyInto(out *BGPPeeringTemplateValue) {
+	*out = *in
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]BGPPeerDefinition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeeringTemplateValue.
+func (in *BGPPeeringTemplateValue) DeepCopy() *BGPPeeringTemplateValue {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPPeeringTemplateValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CNISpec) DeepCopyInto(out *CNISpec) {
+	*out = *in
+	if in.IPAM != nil {
+		in, out := &in.IPAM, &out.IPAM
+		*out = new(IPAMSpec)
+		**out = **in
+	}
+	if in.Chaining != nil {
+		in, out := &in.Chaining, &out.Chaining
+		*out = new(CNIChainingType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CNISpec.
+func (in *CNISpec) DeepCopy() *CNISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CNISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoNetworkSpec) DeepCopyInto(out *CalicoNetworkSpec) {
+	*out = *in
+	if in.LinuxDataplane != nil {
+		in, out := &in.LinuxDataplane, &out.LinuxDataplane
+		*out = new(LinuxDataplaneOption)
+		**out = **in
+	}
+	if in.VPPProfiles != nil {
+		in, out := &in.VPPProfiles, &out.VPPProfiles
+		*out = make([]VPPProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VPPTunnels != nil {
+		in, out := &in.VPPTunnels, &out.VPPTunnels
+		*out = make([]VPPTunnel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VPPNAT64 != nil {
+		in, out := &in.VPPNAT64, &out.VPPNAT64
+		*out = new(VPPNAT64)
+		**out = **in
+	}
+	if in.BGP != nil {
+		in, out := &in.BGP, &out.BGP
+		*out = new(BGPOption)
+		**out = **in
+	}
+	if in.RouteReflectors != nil {
+		in, out := &in.RouteReflectors, &out.RouteReflectors
+		*out = new(RouteReflectors)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAdvertisement != nil {
+		in, out := &in.ServiceAdvertisement, &out.ServiceAdvertisement
+		*out = new(ServiceAdvertisement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BGPPeeringTemplates != nil {
+		in, out := &in.BGPPeeringTemplates, &out.BGPPeeringTemplates
+		*out = make([]BGPPeeringTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BGPGracefulRestart != nil {
+		in, out := &in.BGPGracefulRestart, &out.BGPGracefulRestart
+		*out = new(BGPGracefulRestart)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IPPools != nil {
+		in, out := &in.IPPools, &out.IPPools
+		*out = make([]IPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeAddressAutodetectionV4 != nil {
+		in, out := &in.NodeAddressAutodetectionV4, &out.NodeAddressAutodetectionV4
+		*out = new(NodeAddressAutodetection)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.NodeAddressAutodetectionV6 != nil {
 		in, out := &in.NodeAddressAutodetectionV6, &out.NodeAddressAutodetectionV6
@@ -622,6 +1085,16 @@ func (in *CalicoNetworkSpec) DeepCopyInto(out *CalicoNetworkSpec) {
 		*out = new(ContainerIPForwardingType)
 		**out = **in
 	}
+	if in.HostIPForwarding != nil {
+		in, out := &in.HostIPForwarding, &out.HostIPForwarding
+		*out = new(HostIPForwardingType)
+		**out = **in
+	}
+	if in.AWSSecondaryIPs != nil {
+		in, out := &in.AWSSecondaryIPs, &out.AWSSecondaryIPs
+		*out = new(AWSSecondaryIPs)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoNetworkSpec.
@@ -634,6 +1107,21 @@ func (in *CalicoNetworkSpec) DeepCopy() *CalicoNetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateManagement) DeepCopyInto(out *CertificateManagement) {
 	*out = *in
@@ -763,6 +1251,104 @@ func (in *ComponentResource) DeepCopy() *ComponentResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityTest) DeepCopyInto(out *ConnectivityTest) {
+	*out = *in
+	if in.AfterUpgrade != nil {
+		in, out := &in.AfterUpgrade, &out.AfterUpgrade
+		*out = new(ConnectivityTestAfterUpgradeType)
+		**out = **in
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityTest.
+func (in *ConnectivityTest) DeepCopy() *ConnectivityTest {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityTestStatus) DeepCopyInto(out *ConnectivityTestStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityTestStatus.
+func (in *ConnectivityTestStatus) DeepCopy() *ConnectivityTestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityTestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ESGatewaySpec) DeepCopyInto(out *ESGatewaySpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ResourceRequirements != nil {
+		in, out := &in.ResourceRequirements, &out.ResourceRequirements
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSSettings)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ESGatewaySpec.
+func (in *ESGatewaySpec) DeepCopy() *ESGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ESGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ESProxySpec) DeepCopyInto(out *ESProxySpec) {
+	*out = *in
+	if in.ResourceRequirements != nil {
+		in, out := &in.ResourceRequirements, &out.ResourceRequirements
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ESProxySpec.
+func (in *ESProxySpec) DeepCopy() *ESProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ESProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EksCloudwatchLogsSpec) DeepCopyInto(out *EksCloudwatchLogsSpec) {
 	*out = *in
@@ -798,6 +1384,65 @@ func (in *GroupSearch) DeepCopy() *GroupSearch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardianDeployment) DeepCopyInto(out *GuardianDeployment) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardianDeployment.
+func (in *GuardianDeployment) DeepCopy() *GuardianDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardianDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardianProxy) DeepCopyInto(out *GuardianProxy) {
+	*out = *in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardianProxy.
+func (in *GuardianProxy) DeepCopy() *GuardianProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardianProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPAMSpec) DeepCopyInto(out *IPAMSpec) {
 	*out = *in
@@ -1005,6 +1650,21 @@ func (in *InstallationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallationRollback) DeepCopyInto(out *InstallationRollback) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationRollback.
+func (in *InstallationRollback) DeepCopy() *InstallationRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallationRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 	*out = *in
@@ -1057,7 +1717,32 @@ func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.PolicySyncDriver != nil {
+		in, out := &in.PolicySyncDriver, &out.PolicySyncDriver
+		*out = new(PolicySyncDriverType)
+		**out = **in
+	}
 	in.NodeUpdateStrategy.DeepCopyInto(&out.NodeUpdateStrategy)
+	if in.NodeCanaryRollout != nil {
+		in, out := &in.NodeCanaryRollout, &out.NodeCanaryRollout
+		*out = new(NodeCanaryRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataplaneMigration != nil {
+		in, out := &in.DataplaneMigration, &out.DataplaneMigration
+		*out = new(DataplaneMigration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeShutdown != nil {
+		in, out := &in.NodeShutdown, &out.NodeShutdown
+		*out = new(NodeShutdown)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataplaneSync != nil {
+		in, out := &in.DataplaneSync, &out.DataplaneSync
+		*out = new(DataplaneSync)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ComponentResources != nil {
 		in, out := &in.ComponentResources, &out.ComponentResources
 		*out = make([]ComponentResource, len(*in))
@@ -1065,6 +1750,11 @@ func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NamespaceQuotas != nil {
+		in, out := &in.NamespaceQuotas, &out.NamespaceQuotas
+		*out = new(NamespaceQuotasType)
+		**out = **in
+	}
 	if in.CertificateManagement != nil {
 		in, out := &in.CertificateManagement, &out.CertificateManagement
 		*out = new(CertificateManagement)
@@ -1075,6 +1765,46 @@ func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 		*out = new(NonPrivilegedType)
 		**out = **in
 	}
+	if in.Hardening != nil {
+		in, out := &in.Hardening, &out.Hardening
+		*out = new(HardeningType)
+		**out = **in
+	}
+	if in.NodeInit != nil {
+		in, out := &in.NodeInit, &out.NodeInit
+		*out = new(NodeInit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeControllers != nil {
+		in, out := &in.KubeControllers, &out.KubeControllers
+		*out = new(KubeControllers)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UsageReporting != nil {
+		in, out := &in.UsageReporting, &out.UsageReporting
+		*out = new(UsageReportingType)
+		**out = **in
+	}
+	if in.KubernetesServiceEndpoint != nil {
+		in, out := &in.KubernetesServiceEndpoint, &out.KubernetesServiceEndpoint
+		*out = new(KubernetesServiceEndpoint)
+		**out = **in
+	}
+	if in.ConnectivityTest != nil {
+		in, out := &in.ConnectivityTest, &out.ConnectivityTest
+		*out = new(ConnectivityTest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(InstallationRollback)
+		**out = **in
+	}
+	if in.RolloutWatchdog != nil {
+		in, out := &in.RolloutWatchdog, &out.RolloutWatchdog
+		*out = new(RolloutWatchdog)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationSpec.
@@ -1095,7 +1825,17 @@ func (in *InstallationStatus) DeepCopyInto(out *InstallationStatus) {
 		*out = new(InstallationSpec)
 		(*in).DeepCopyInto(*out)
 	}
-}
+	if in.ConnectivityTest != nil {
+		in, out := &in.ConnectivityTest, &out.ConnectivityTest
+		*out = new(ConnectivityTestStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NAT64 != nil {
+		in, out := &in.NAT64, &out.NAT64
+		*out = make([]NodeNAT64Status, len(*in))
+		copy(*out, *in)
+	}
+}
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationStatus.
 func (in *InstallationStatus) DeepCopy() *InstallationStatus {
@@ -1223,6 +1963,81 @@ func (in *IntrusionDetectionStatus) DeepCopy() *IntrusionDetectionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeControllers) DeepCopyInto(out *KubeControllers) {
+	*out = *in
+	if in.EnabledControllers != nil {
+		in, out := &in.EnabledControllers, &out.EnabledControllers
+		*out = make([]KubeControllerType, len(*in))
+		copy(*out, *in)
+	}
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(KubeControllersNode)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllers.
+func (in *KubeControllers) DeepCopy() *KubeControllers {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeControllers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeControllersNode) DeepCopyInto(out *KubeControllersNode) {
+	*out = *in
+	if in.ReconcilerPeriod != nil {
+		in, out := &in.ReconcilerPeriod, &out.ReconcilerPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SyncLabels != nil {
+		in, out := &in.SyncLabels, &out.SyncLabels
+		*out = new(NodeSyncLabelsType)
+		**out = **in
+	}
+	if in.HostEndpointAutoCreate != nil {
+		in, out := &in.HostEndpointAutoCreate, &out.HostEndpointAutoCreate
+		*out = new(HostEndpointAutoCreateType)
+		**out = **in
+	}
+	if in.LeakGracePeriod != nil {
+		in, out := &in.LeakGracePeriod, &out.LeakGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllersNode.
+func (in *KubeControllersNode) DeepCopy() *KubeControllersNode {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeControllersNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesServiceEndpoint) DeepCopyInto(out *KubernetesServiceEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesServiceEndpoint.
+func (in *KubernetesServiceEndpoint) DeepCopy() *KubernetesServiceEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesServiceEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogCollectionSpec) DeepCopyInto(out *LogCollectionSpec) {
 	*out = *in
@@ -1330,6 +2145,16 @@ func (in *LogCollectorSpec) DeepCopyInto(out *LogCollectorSpec) {
 		*out = new(CollectProcessPathOption)
 		**out = **in
 	}
+	if in.CollectDataplaneEvents != nil {
+		in, out := &in.CollectDataplaneEvents, &out.CollectDataplaneEvents
+		*out = new(CollectDataplaneEventsOption)
+		**out = **in
+	}
+	if in.NamespaceSelection != nil {
+		in, out := &in.NamespaceSelection, &out.NamespaceSelection
+		*out = new(LogCollectionNamespaceSelection)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectorSpec.
@@ -1468,6 +2293,11 @@ func (in *LogStorageSpec) DeepCopyInto(out *LogStorageSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ESGateway != nil {
+		in, out := &in.ESGateway, &out.ESGateway
+		*out = new(ESGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStorageSpec.
@@ -1495,12 +2325,43 @@ func (in *LogStorageStatus) DeepCopy() *LogStorageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedClusterAccessRule) DeepCopyInto(out *ManagedClusterAccessRule) {
+	*out = *in
+	if in.ManagedClusters != nil {
+		in, out := &in.ManagedClusters, &out.ManagedClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterAccessRule.
+func (in *ManagedClusterAccessRule) DeepCopy() *ManagedClusterAccessRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedClusterAccessRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementCluster) DeepCopyInto(out *ManagementCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementCluster.
@@ -1526,7 +2387,8 @@ func (in *ManagementClusterConnection) DeepCopyInto(out *ManagementClusterConnec
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterConnection.
@@ -1582,6 +2444,26 @@ func (in *ManagementClusterConnectionList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementClusterConnectionSpec) DeepCopyInto(out *ManagementClusterConnectionSpec) {
 	*out = *in
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(GuardianProxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TunnelKeepAlive != nil {
+		in, out := &in.TunnelKeepAlive, &out.TunnelKeepAlive
+		*out = new(TunnelKeepAlive)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReconnectBackoff != nil {
+		in, out := &in.ReconnectBackoff, &out.ReconnectBackoff
+		*out = new(TunnelReconnectBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GuardianDeployment != nil {
+		in, out := &in.GuardianDeployment, &out.GuardianDeployment
+		*out = new(GuardianDeployment)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterConnectionSpec.
@@ -1594,6 +2476,26 @@ func (in *ManagementClusterConnectionSpec) DeepCopy() *ManagementClusterConnecti
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterConnectionStatus) DeepCopyInto(out *ManagementClusterConnectionStatus) {
+	*out = *in
+	if in.Tunnel != nil {
+		in, out := &in.Tunnel, &out.Tunnel
+		*out = new(TunnelStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterConnectionStatus.
+func (in *ManagementClusterConnectionStatus) DeepCopy() *ManagementClusterConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementClusterList) DeepCopyInto(out *ManagementClusterList) {
 	*out = *in
@@ -1629,6 +2531,23 @@ func (in *ManagementClusterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementClusterSpec) DeepCopyInto(out *ManagementClusterSpec) {
 	*out = *in
+	if in.TunnelCertificateRotation != nil {
+		in, out := &in.TunnelCertificateRotation, &out.TunnelCertificateRotation
+		*out = new(TunnelCertificateRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TunnelKeepAlive != nil {
+		in, out := &in.TunnelKeepAlive, &out.TunnelKeepAlive
+		*out = new(TunnelKeepAlive)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedClusterAccess != nil {
+		in, out := &in.ManagedClusterAccess, &out.ManagedClusterAccess
+		*out = make([]ManagedClusterAccessRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterSpec.
@@ -1641,6 +2560,28 @@ func (in *ManagementClusterSpec) DeepCopy() *ManagementClusterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementClusterStatus) DeepCopyInto(out *ManagementClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementClusterStatus.
+func (in *ManagementClusterStatus) DeepCopy() *ManagementClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Manager) DeepCopyInto(out *Manager) {
 	*out = *in
@@ -1708,6 +2649,21 @@ func (in *ManagerSpec) DeepCopyInto(out *ManagerSpec) {
 		*out = new(Auth)
 		**out = **in
 	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ESProxy != nil {
+		in, out := &in.ESProxy, &out.ESProxy
+		*out = new(ESProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSSettings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerSpec.
@@ -1745,7 +2701,7 @@ func (in *Monitor) DeepCopyInto(out *Monitor) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	out.Status = in.Status
 }
 
@@ -1802,6 +2758,18 @@ func (in *MonitorList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonitorSpec) DeepCopyInto(out *MonitorSpec) {
 	*out = *in
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(Prometheus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScrapeTargets != nil {
+		in, out := &in.ScrapeTargets, &out.ScrapeTargets
+		*out = make([]ScrapeTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorSpec.
@@ -1829,6 +2797,33 @@ func (in *MonitorStatus) DeepCopy() *MonitorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSet) DeepCopyInto(out *NetworkSet) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Nets != nil {
+		in, out := &in.Nets, &out.Nets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSet.
+func (in *NetworkSet) DeepCopy() *NetworkSet {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeAddressAutodetection) DeepCopyInto(out *NodeAddressAutodetection) {
 	*out = *in
@@ -1886,6 +2881,73 @@ func (in *NodeAffinity) DeepCopy() *NodeAffinity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCanaryRollout) DeepCopyInto(out *NodeCanaryRollout) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SoakSeconds != nil {
+		in, out := &in.SoakSeconds, &out.SoakSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRestarts != nil {
+		in, out := &in.MaxRestarts, &out.MaxRestarts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCanaryRollout.
+func (in *NodeCanaryRollout) DeepCopy() *NodeCanaryRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCanaryRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInit) DeepCopyInto(out *NodeInit) {
+	*out = *in
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(NodeInitState)
+		**out = **in
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make([]Sysctl, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInit.
+func (in *NodeInit) DeepCopy() *NodeInit {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSet) DeepCopyInto(out *NodeSet) {
 	*out = *in
@@ -1921,6 +2983,31 @@ func (in *NodeSetSelectionAttribute) DeepCopy() *NodeSetSelectionAttribute {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeShutdown) DeepCopyInto(out *NodeShutdown) {
+	*out = *in
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DrainSeconds != nil {
+		in, out := &in.DrainSeconds, &out.DrainSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeShutdown.
+func (in *NodeShutdown) DeepCopy() *NodeShutdown {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeShutdown)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Nodes) DeepCopyInto(out *Nodes) {
 	*out = *in
@@ -1949,32 +3036,179 @@ func (in *Nodes) DeepCopy() *Nodes {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Retention) DeepCopyInto(out *Retention) {
+func (in *Prometheus) DeepCopyInto(out *Prometheus) {
 	*out = *in
-	if in.Flows != nil {
-		in, out := &in.Flows, &out.Flows
-		*out = new(int32)
-		**out = **in
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(PrometheusStorage)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.AuditReports != nil {
-		in, out := &in.AuditReports, &out.AuditReports
-		*out = new(int32)
-		**out = **in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Snapshots != nil {
-		in, out := &in.Snapshots, &out.Snapshots
-		*out = new(int32)
-		**out = **in
+	if in.RemoteWrite != nil {
+		in, out := &in.RemoteWrite, &out.RemoteWrite
+		*out = make([]PrometheusRemoteWrite, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.ComplianceReports != nil {
-		in, out := &in.ComplianceReports, &out.ComplianceReports
+	if in.Thanos != nil {
+		in, out := &in.Thanos, &out.Thanos
+		*out = new(PrometheusThanos)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ExternalLabels != nil {
+		in, out := &in.ExternalLabels, &out.ExternalLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retention.
-func (in *Retention) DeepCopy() *Retention {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Prometheus.
+func (in *Prometheus) DeepCopy() *Prometheus {
+	if in == nil {
+		return nil
+	}
+	out := new(Prometheus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRemoteWrite) DeepCopyInto(out *PrometheusRemoteWrite) {
+	*out = *in
+	if in.WriteRelabelConfigs != nil {
+		in, out := &in.WriteRelabelConfigs, &out.WriteRelabelConfigs
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueueConfig != nil {
+		in, out := &in.QueueConfig, &out.QueueConfig
+		*out = new(RemoteWriteQueueConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRemoteWrite.
+func (in *PrometheusRemoteWrite) DeepCopy() *PrometheusRemoteWrite {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRemoteWrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusStorage) DeepCopyInto(out *PrometheusStorage) {
+	*out = *in
+	in.Size.DeepCopyInto(&out.Size)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusStorage.
+func (in *PrometheusStorage) DeepCopy() *PrometheusStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusThanos) DeepCopyInto(out *PrometheusThanos) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusThanos.
+func (in *PrometheusThanos) DeepCopy() *PrometheusThanos {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusThanos)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
+	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelabelConfig.
+func (in *RelabelConfig) DeepCopy() *RelabelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RelabelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteWriteQueueConfig) DeepCopyInto(out *RemoteWriteQueueConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteWriteQueueConfig.
+func (in *RemoteWriteQueueConfig) DeepCopy() *RemoteWriteQueueConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteWriteQueueConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Retention) DeepCopyInto(out *Retention) {
+	*out = *in
+	if in.Flows != nil {
+		in, out := &in.Flows, &out.Flows
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AuditReports != nil {
+		in, out := &in.AuditReports, &out.AuditReports
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ComplianceReports != nil {
+		in, out := &in.ComplianceReports, &out.ComplianceReports
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retention.
+func (in *Retention) DeepCopy() *Retention {
 	if in == nil {
 		return nil
 	}
@@ -1984,129 +3218,1109 @@ func (in *Retention) DeepCopy() *Retention {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3StoreSpec) DeepCopyInto(out *S3StoreSpec) {
+func (in *RolloutWatchdog) DeepCopyInto(out *RolloutWatchdog) {
+	*out = *in
+	if in.StallTimeout != nil {
+		in, out := &in.StallTimeout, &out.StallTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PauseOnStall != nil {
+		in, out := &in.PauseOnStall, &out.PauseOnStall
+		*out = new(RolloutPauseOnStallType)
+		**out = **in
+	}
+	if in.StallsAcknowledgedAt != nil {
+		in, out := &in.StallsAcknowledgedAt, &out.StallsAcknowledgedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWatchdog.
+func (in *RolloutWatchdog) DeepCopy() *RolloutWatchdog {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutWatchdog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteReflectors) DeepCopyInto(out *RouteReflectors) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteReflectors.
+func (in *RouteReflectors) DeepCopy() *RouteReflectors {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteReflectors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3StoreSpec) DeepCopyInto(out *S3StoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3StoreSpec.
+func (in *S3StoreSpec) DeepCopy() *S3StoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(S3StoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScrapeTarget) DeepCopyInto(out *ScrapeTarget) {
+	*out = *in
+	if in.MetricRelabelConfigs != nil {
+		in, out := &in.MetricRelabelConfigs, &out.MetricRelabelConfigs
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrapeTarget.
+func (in *ScrapeTarget) DeepCopy() *ScrapeTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ScrapeTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAdvertisement) DeepCopyInto(out *ServiceAdvertisement) {
+	*out = *in
+	if in.ServiceClusterIPs != nil {
+		in, out := &in.ServiceClusterIPs, &out.ServiceClusterIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceExternalIPs != nil {
+		in, out := &in.ServiceExternalIPs, &out.ServiceExternalIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceLoadBalancerIPs != nil {
+		in, out := &in.ServiceLoadBalancerIPs, &out.ServiceLoadBalancerIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAdvertisement.
+func (in *ServiceAdvertisement) DeepCopy() *ServiceAdvertisement {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAdvertisement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SplunkStoreSpec) DeepCopyInto(out *SplunkStoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkStoreSpec.
+func (in *SplunkStoreSpec) DeepCopy() *SplunkStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SplunkStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyslogStoreSpec) DeepCopyInto(out *SyslogStoreSpec) {
+	*out = *in
+	if in.PacketSize != nil {
+		in, out := &in.PacketSize, &out.PacketSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LogTypes != nil {
+		in, out := &in.LogTypes, &out.LogTypes
+		*out = make([]SyslogLogType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogStoreSpec.
+func (in *SyslogStoreSpec) DeepCopy() *SyslogStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SyslogStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sysctl) DeepCopyInto(out *Sysctl) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sysctl.
+func (in *Sysctl) DeepCopy() *Sysctl {
+	if in == nil {
+		return nil
+	}
+	out := new(Sysctl)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSSettings) DeepCopyInto(out *TLSSettings) {
+	*out = *in
+	if in.CipherSuites != nil {
+		in, out := &in.CipherSuites, &out.CipherSuites
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSettings.
+func (in *TLSSettings) DeepCopy() *TLSSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tenant) DeepCopyInto(out *Tenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenant.
+func (in *Tenant) DeepCopy() *Tenant {
+	if in == nil {
+		return nil
+	}
+	out := new(Tenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Tenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantList) DeepCopyInto(out *TenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Tenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantList.
+func (in *TenantList) DeepCopy() *TenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
+func (in *TenantSpec) DeepCopy() *TenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TigeraStatus) DeepCopyInto(out *TigeraStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatus.
+func (in *TigeraStatus) DeepCopy() *TigeraStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TigeraStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TigeraStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TigeraStatusCondition) DeepCopyInto(out *TigeraStatusCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusCondition.
+func (in *TigeraStatusCondition) DeepCopy() *TigeraStatusCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(TigeraStatusCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TigeraStatusList) DeepCopyInto(out *TigeraStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TigeraStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusList.
+func (in *TigeraStatusList) DeepCopy() *TigeraStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(TigeraStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TigeraStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TigeraStatusSpec) DeepCopyInto(out *TigeraStatusSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusSpec.
+func (in *TigeraStatusSpec) DeepCopy() *TigeraStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TigeraStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TigeraStatusStatus) DeepCopyInto(out *TigeraStatusStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]TigeraStatusCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(EncryptionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusStatus.
+func (in *TigeraStatusStatus) DeepCopy() *TigeraStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TigeraStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunnelCertificateRotation) DeepCopyInto(out *TunnelCertificateRotation) {
+	*out = *in
+	if in.RotateBefore != nil {
+		in, out := &in.RotateBefore, &out.RotateBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.OverlapPeriod != nil {
+		in, out := &in.OverlapPeriod, &out.OverlapPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelCertificateRotation.
+func (in *TunnelCertificateRotation) DeepCopy() *TunnelCertificateRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(TunnelCertificateRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunnelKeepAlive) DeepCopyInto(out *TunnelKeepAlive) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TCPKeepAlivePeriod != nil {
+		in, out := &in.TCPKeepAlivePeriod, &out.TCPKeepAlivePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelKeepAlive.
+func (in *TunnelKeepAlive) DeepCopy() *TunnelKeepAlive {
+	if in == nil {
+		return nil
+	}
+	out := new(TunnelKeepAlive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunnelReconnectBackoff) DeepCopyInto(out *TunnelReconnectBackoff) {
+	*out = *in
+	if in.InitialInterval != nil {
+		in, out := &in.InitialInterval, &out.InitialInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxInterval != nil {
+		in, out := &in.MaxInterval, &out.MaxInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelReconnectBackoff.
+func (in *TunnelReconnectBackoff) DeepCopy() *TunnelReconnectBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(TunnelReconnectBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunnelStatus) DeepCopyInto(out *TunnelStatus) {
+	*out = *in
+	if in.LastHeartbeatTime != nil {
+		in, out := &in.LastHeartbeatTime, &out.LastHeartbeatTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RoundTripTime != nil {
+		in, out := &in.RoundTripTime, &out.RoundTripTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelStatus.
+func (in *TunnelStatus) DeepCopy() *TunnelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TunnelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TyphaAffinity) DeepCopyInto(out *TyphaAffinity) {
+	*out = *in
+	if in.NodeAffinity != nil {
+		in, out := &in.NodeAffinity, &out.NodeAffinity
+		*out = new(NodeAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TyphaAffinity.
+func (in *TyphaAffinity) DeepCopy() *TyphaAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(TyphaAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserMatch) DeepCopyInto(out *UserMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserMatch.
+func (in *UserMatch) DeepCopy() *UserMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(UserMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserSearch) DeepCopyInto(out *UserSearch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserSearch.
+func (in *UserSearch) DeepCopy() *UserSearch {
+	if in == nil {
+		return nil
+	}
+	out := new(UserSearch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPProfile) DeepCopyInto(out *VPPProfile) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UplinkDriver != nil {
+		in, out := &in.UplinkDriver, &out.UplinkDriver
+		*out = new(VPPUplinkDriver)
+		**out = **in
+	}
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CPUPinning != nil {
+		in, out := &in.CPUPinning, &out.CPUPinning
+		*out = new(VPPCPUPinning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BuffersPerNUMA != nil {
+		in, out := &in.BuffersPerNUMA, &out.BuffersPerNUMA
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BufferDataSize != nil {
+		in, out := &in.BufferDataSize, &out.BufferDataSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(VPPMemory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PowerProfile != nil {
+		in, out := &in.PowerProfile, &out.PowerProfile
+		*out = new(VPPPowerProfile)
+		**out = **in
+	}
+	if in.CryptoEngine != nil {
+		in, out := &in.CryptoEngine, &out.CryptoEngine
+		*out = new(VPPCryptoEngine)
+		**out = **in
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(VPPPlugins)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DPDK != nil {
+		in, out := &in.DPDK, &out.DPDK
+		*out = new(VPPDPDK)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupConfigOverride != nil {
+		in, out := &in.StartupConfigOverride, &out.StartupConfigOverride
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPProfile.
+func (in *VPPProfile) DeepCopy() *VPPProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPCPUPinning) DeepCopyInto(out *VPPCPUPinning) {
+	*out = *in
+	if in.MainCore != nil {
+		in, out := &in.MainCore, &out.MainCore
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPCPUPinning.
+func (in *VPPCPUPinning) DeepCopy() *VPPCPUPinning {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPCPUPinning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPDPDK) DeepCopyInto(out *VPPDPDK) {
+	*out = *in
+	if in.AllowedPCIDevices != nil {
+		in, out := &in.AllowedPCIDevices, &out.AllowedPCIDevices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedPCIDevices != nil {
+		in, out := &in.DeniedPCIDevices, &out.DeniedPCIDevices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogLevel != nil {
+		in, out := &in.LogLevel, &out.LogLevel
+		*out = new(VPPDPDKLogLevel)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPDPDK.
+func (in *VPPDPDK) DeepCopy() *VPPDPDK {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPDPDK)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPPlugins) DeepCopyInto(out *VPPPlugins) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(VPPPluginDefault)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPPlugins.
+func (in *VPPPlugins) DeepCopy() *VPPPlugins {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPPlugins)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPTunnel) DeepCopyInto(out *VPPTunnel) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Overhead != nil {
+		in, out := &in.Overhead, &out.Overhead
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPTunnel.
+func (in *VPPTunnel) DeepCopy() *VPPTunnel {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPTunnel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPNAT64) DeepCopyInto(out *VPPNAT64) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPNAT64.
+func (in *VPPNAT64) DeepCopy() *VPPNAT64 {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPNAT64)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNAT64Status) DeepCopyInto(out *NodeNAT64Status) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNAT64Status.
+func (in *NodeNAT64Status) DeepCopy() *NodeNAT64Status {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNAT64Status)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPDataplane) DeepCopyInto(out *VPPDataplane) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPDataplane.
+func (in *VPPDataplane) DeepCopy() *VPPDataplane {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPDataplane)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VPPDataplane) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPDataplaneList) DeepCopyInto(out *VPPDataplaneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VPPDataplane, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPDataplaneList.
+func (in *VPPDataplaneList) DeepCopy() *VPPDataplaneList {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPDataplaneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VPPDataplaneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPDataplaneSpec) DeepCopyInto(out *VPPDataplaneSpec) {
+	*out = *in
+	if in.UplinkDriver != nil {
+		in, out := &in.UplinkDriver, &out.UplinkDriver
+		*out = new(VPPUplinkDriver)
+		**out = **in
+	}
+	if in.UplinkInterfaces != nil {
+		in, out := &in.UplinkInterfaces, &out.UplinkInterfaces
+		*out = make([]VPPUplinkInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServicePrefixes != nil {
+		in, out := &in.ServicePrefixes, &out.ServicePrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogLevel != nil {
+		in, out := &in.LogLevel, &out.LogLevel
+		*out = new(VPPLogLevel)
+		**out = **in
+	}
+	if in.GSO != nil {
+		in, out := &in.GSO, &out.GSO
+		*out = new(VPPGSOType)
+		**out = **in
+	}
+	if in.Hugepages != nil {
+		in, out := &in.Hugepages, &out.Hugepages
+		*out = new(VPPHugepages)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BootstrapTaint != nil {
+		in, out := &in.BootstrapTaint, &out.BootstrapTaint
+		*out = new(VPPBootstrapTaintType)
+		**out = **in
+	}
+	if in.Buffers != nil {
+		in, out := &in.Buffers, &out.Buffers
+		*out = new(VPPBuffers)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(VPPMemory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VPPResources != nil {
+		in, out := &in.VPPResources, &out.VPPResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AgentResources != nil {
+		in, out := &in.AgentResources, &out.AgentResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPDataplaneSpec.
+func (in *VPPDataplaneSpec) DeepCopy() *VPPDataplaneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPDataplaneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPDataplaneStatus) DeepCopyInto(out *VPPDataplaneStatus) {
+	*out = *in
+	if in.Uplinks != nil {
+		in, out := &in.Uplinks, &out.Uplinks
+		*out = make([]NodeUplinkStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hugepages != nil {
+		in, out := &in.Hugepages, &out.Hugepages
+		*out = make([]NodeHugepagesStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPDataplaneStatus.
+func (in *VPPDataplaneStatus) DeepCopy() *VPPDataplaneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPDataplaneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPPUplinkInterface) DeepCopyInto(out *VPPUplinkInterface) {
+	*out = *in
+	if in.Driver != nil {
+		in, out := &in.Driver, &out.Driver
+		*out = new(VPPUplinkDriver)
+		**out = **in
+	}
+	if in.RxMode != nil {
+		in, out := &in.RxMode, &out.RxMode
+		*out = new(VPPRxMode)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPUplinkInterface.
+func (in *VPPUplinkInterface) DeepCopy() *VPPUplinkInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(VPPUplinkInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUplinkStatus) DeepCopyInto(out *NodeUplinkStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3StoreSpec.
-func (in *S3StoreSpec) DeepCopy() *S3StoreSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUplinkStatus.
+func (in *NodeUplinkStatus) DeepCopy() *NodeUplinkStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(S3StoreSpec)
+	out := new(NodeUplinkStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SplunkStoreSpec) DeepCopyInto(out *SplunkStoreSpec) {
+func (in *VPPBuffers) DeepCopyInto(out *VPPBuffers) {
 	*out = *in
+	if in.BuffersPerNUMA != nil {
+		in, out := &in.BuffersPerNUMA, &out.BuffersPerNUMA
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DataSize != nil {
+		in, out := &in.DataSize, &out.DataSize
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkStoreSpec.
-func (in *SplunkStoreSpec) DeepCopy() *SplunkStoreSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPBuffers.
+func (in *VPPBuffers) DeepCopy() *VPPBuffers {
 	if in == nil {
 		return nil
 	}
-	out := new(SplunkStoreSpec)
+	out := new(VPPBuffers)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SyslogStoreSpec) DeepCopyInto(out *SyslogStoreSpec) {
+func (in *VPPMemory) DeepCopyInto(out *VPPMemory) {
 	*out = *in
-	if in.PacketSize != nil {
-		in, out := &in.PacketSize, &out.PacketSize
-		*out = new(int32)
-		**out = **in
+	if in.MainHeapSize != nil {
+		in, out := &in.MainHeapSize, &out.MainHeapSize
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	if in.LogTypes != nil {
-		in, out := &in.LogTypes, &out.LogTypes
-		*out = make([]SyslogLogType, len(*in))
-		copy(*out, *in)
+	if in.StatsSegmentSize != nil {
+		in, out := &in.StatsSegmentSize, &out.StatsSegmentSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.APISegmentSize != nil {
+		in, out := &in.APISegmentSize, &out.APISegmentSize
+		x := (*in).DeepCopy()
+		*out = &x
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogStoreSpec.
-func (in *SyslogStoreSpec) DeepCopy() *SyslogStoreSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPMemory.
+func (in *VPPMemory) DeepCopy() *VPPMemory {
 	if in == nil {
 		return nil
 	}
-	out := new(SyslogStoreSpec)
+	out := new(VPPMemory)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TigeraStatus) DeepCopyInto(out *TigeraStatus) {
+func (in *VPPHugepages) DeepCopyInto(out *VPPHugepages) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(VPPHugepageSize)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatus.
-func (in *TigeraStatus) DeepCopy() *TigeraStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPPHugepages.
+func (in *VPPHugepages) DeepCopy() *VPPHugepages {
 	if in == nil {
 		return nil
 	}
-	out := new(TigeraStatus)
+	out := new(VPPHugepages)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TigeraStatus) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeHugepagesStatus) DeepCopyInto(out *NodeHugepagesStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeHugepagesStatus.
+func (in *NodeHugepagesStatus) DeepCopy() *NodeHugepagesStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(NodeHugepagesStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TigeraStatusCondition) DeepCopyInto(out *TigeraStatusCondition) {
+func (in *Whisker) DeepCopyInto(out *Whisker) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusCondition.
-func (in *TigeraStatusCondition) DeepCopy() *TigeraStatusCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Whisker.
+func (in *Whisker) DeepCopy() *Whisker {
 	if in == nil {
 		return nil
 	}
-	out := new(TigeraStatusCondition)
+	out := new(Whisker)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Whisker) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TigeraStatusList) DeepCopyInto(out *TigeraStatusList) {
+func (in *WhiskerList) DeepCopyInto(out *WhiskerList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]TigeraStatus, len(*in))
+		*out = make([]Whisker, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusList.
-func (in *TigeraStatusList) DeepCopy() *TigeraStatusList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WhiskerList.
+func (in *WhiskerList) DeepCopy() *WhiskerList {
 	if in == nil {
 		return nil
 	}
-	out := new(TigeraStatusList)
+	out := new(WhiskerList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TigeraStatusList) DeepCopyObject() runtime.Object {
+func (in *WhiskerList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2114,88 +4328,303 @@ func (in *TigeraStatusList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TigeraStatusSpec) DeepCopyInto(out *TigeraStatusSpec) {
+func (in *WhiskerSpec) DeepCopyInto(out *WhiskerSpec) {
 	*out = *in
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(WhiskerNotificationMode)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusSpec.
-func (in *TigeraStatusSpec) DeepCopy() *TigeraStatusSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WhiskerSpec.
+func (in *WhiskerSpec) DeepCopy() *WhiskerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TigeraStatusSpec)
+	out := new(WhiskerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TigeraStatusStatus) DeepCopyInto(out *TigeraStatusStatus) {
+func (in *WhiskerStatus) DeepCopyInto(out *WhiskerStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]TigeraStatusCondition, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WhiskerStatus.
+func (in *WhiskerStatus) DeepCopy() *WhiskerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WhiskerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplaneMigration) DeepCopyInto(out *DataplaneMigration) {
+	*out = *in
+	if in.DrainNodes != nil {
+		in, out := &in.DrainNodes, &out.DrainNodes
+		*out = new(DataplaneMigrationDrain)
+		**out = **in
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplaneMigration.
+func (in *DataplaneMigration) DeepCopy() *DataplaneMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(DataplaneMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionStatus) DeepCopyInto(out *EncryptionStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeEncryptionStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusStatus.
-func (in *TigeraStatusStatus) DeepCopy() *TigeraStatusStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionStatus.
+func (in *EncryptionStatus) DeepCopy() *EncryptionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TigeraStatusStatus)
+	out := new(EncryptionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TyphaAffinity) DeepCopyInto(out *TyphaAffinity) {
+func (in *NodeEncryptionStatus) DeepCopyInto(out *NodeEncryptionStatus) {
 	*out = *in
-	if in.NodeAffinity != nil {
-		in, out := &in.NodeAffinity, &out.NodeAffinity
-		*out = new(NodeAffinity)
+	if in.OldestHandshakeSeconds != nil {
+		in, out := &in.OldestHandshakeSeconds, &out.OldestHandshakeSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeEncryptionStatus.
+func (in *NodeEncryptionStatus) DeepCopy() *NodeEncryptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeEncryptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplaneSync) DeepCopyInto(out *DataplaneSync) {
+	*out = *in
+	if in.RouteRefreshIntervalSeconds != nil {
+		in, out := &in.RouteRefreshIntervalSeconds, &out.RouteRefreshIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IptablesRefreshIntervalSeconds != nil {
+		in, out := &in.IptablesRefreshIntervalSeconds, &out.IptablesRefreshIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IpsetsRefreshIntervalSeconds != nil {
+		in, out := &in.IpsetsRefreshIntervalSeconds, &out.IpsetsRefreshIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplaneSync.
+func (in *DataplaneSync) DeepCopy() *DataplaneSync {
+	if in == nil {
+		return nil
+	}
+	out := new(DataplaneSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplaneRestart) DeepCopyInto(out *DataplaneRestart) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplaneRestart.
+func (in *DataplaneRestart) DeepCopy() *DataplaneRestart {
+	if in == nil {
+		return nil
+	}
+	out := new(DataplaneRestart)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataplaneRestart) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplaneRestartList) DeepCopyInto(out *DataplaneRestartList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataplaneRestart, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplaneRestartList.
+func (in *DataplaneRestartList) DeepCopy() *DataplaneRestartList {
+	if in == nil {
+		return nil
+	}
+	out := new(DataplaneRestartList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataplaneRestartList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplaneRestartSpec) DeepCopyInto(out *DataplaneRestartSpec) {
+	*out = *in
+	if in.Component != nil {
+		in, out := &in.Component, &out.Component
+		*out = new(DataplaneRestartComponent)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(DataplaneRestartRateLimit)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TyphaAffinity.
-func (in *TyphaAffinity) DeepCopy() *TyphaAffinity {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplaneRestartSpec.
+func (in *DataplaneRestartSpec) DeepCopy() *DataplaneRestartSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TyphaAffinity)
+	out := new(DataplaneRestartSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UserMatch) DeepCopyInto(out *UserMatch) {
+func (in *DataplaneRestartRateLimit) DeepCopyInto(out *DataplaneRestartRateLimit) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplaneRestartRateLimit.
+func (in *DataplaneRestartRateLimit) DeepCopy() *DataplaneRestartRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(DataplaneRestartRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataplaneRestartStatus) DeepCopyInto(out *DataplaneRestartStatus) {
 	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeRestartStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserMatch.
-func (in *UserMatch) DeepCopy() *UserMatch {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataplaneRestartStatus.
+func (in *DataplaneRestartStatus) DeepCopy() *DataplaneRestartStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(UserMatch)
+	out := new(DataplaneRestartStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UserSearch) DeepCopyInto(out *UserSearch) {
+func (in *NodeRestartStatus) DeepCopyInto(out *NodeRestartStatus) {
 	*out = *in
+	if in.RestartTime != nil {
+		in, out := &in.RestartTime, &out.RestartTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserSearch.
-func (in *UserSearch) DeepCopy() *UserSearch {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRestartStatus.
+func (in *NodeRestartStatus) DeepCopy() *NodeRestartStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(UserSearch)
+	out := new(NodeRestartStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -48,6 +48,18 @@ var (
 		Image:   "{{ .Image }}",
 	}
 {{- end }}
+{{ with .Components.csi }}
+	ComponentCSI = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
+{{- end }}
+{{ with index .Components "node-driver-registrar" }}
+	ComponentCSINodeDriverRegistrar = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
+{{- end }}
 {{ with index .Components "calico/apiserver"}}
 	ComponentCalicoAPIServer = component{
 		Version: "{{ .Version }}",
@@ -59,6 +71,36 @@ var (
 		Version: "{{ .Version }}",
 		Image:   "{{ .Image }}",
 	}
+{{- end }}
+{{ with index .Components "calico/goldmane"}}
+	ComponentCalicoGoldmane = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
+{{- end }}
+{{ with index .Components "calico/whisker"}}
+	ComponentCalicoWhisker = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
+{{- end }}
+{{ with index .Components "calico/whisker-backend"}}
+	ComponentCalicoWhiskerBackend = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
+{{- end }}
+{{ with index .Components "calicovpp/vpp"}}
+	ComponentCalicoVPP = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
+{{- end }}
+{{ with index .Components "calicovpp/agent"}}
+	ComponentCalicoVPPAgent = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
 {{- end }}
 	ComponentOperatorInit = component{
 		Version: version.VERSION,
@@ -71,8 +113,15 @@ var (
 		ComponentCalicoNode,
 		ComponentCalicoTypha,
 		ComponentFlexVolume,
+		ComponentCSI,
+		ComponentCSINodeDriverRegistrar,
 		ComponentOperatorInit,
 		ComponentCalicoAPIServer,
 		ComponentWindows,
+		ComponentCalicoGoldmane,
+		ComponentCalicoWhisker,
+		ComponentCalicoWhiskerBackend,
+		ComponentCalicoVPP,
+		ComponentCalicoVPPAgent,
 	}
 )
@@ -31,6 +31,8 @@ var defaultImages = map[string]string{
 	"calicoctl":               "calico/ctl",
 	"flannel":                 "coreos/flannel",
 	"flexvol":                 "calico/pod2daemon-flexvol",
+	"csi":                     "calico/csi",
+	"node-driver-registrar":   "calico/node-driver-registrar",
 	"typha":                   "calico/typha",
 	"eck-elasticsearch":       "tigera/elasticsearch",
 	"eck-kibana":              "tigera/kibana",
@@ -39,6 +41,11 @@ var defaultImages = map[string]string{
 	"key-cert-provisioner":    "tigera/key-cert-provisioner",
 	"calico/apiserver":        "calico/apiserver",
 	"calico/windows-upgrade":  "calico/windows-upgrade",
+	"calico/goldmane":         "calico/goldmane",
+	"calico/whisker":          "calico/whisker",
+	"calico/whisker-backend":  "calico/whisker-backend",
+	"calicovpp/vpp":           "calicovpp/vpp",
+	"calicovpp/agent":         "calicovpp/agent",
 }
 
 var ignoredImages = map[string]struct{}{
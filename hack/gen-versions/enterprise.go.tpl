@@ -190,6 +190,12 @@ var (
 		Image:   "{{ .Image }}",
 	}
 {{- end }}
+{{ with index .Components "thanos" }}
+	ComponentThanos = component{
+		Version: "{{ .Version }}",
+		Image:   "{{ .Image }}",
+	}
+{{- end }}
 {{ with index .Components "cnx-queryserver" }}
 	ComponentQueryServer = component{
 		Version: "{{ .Version }}",
@@ -267,6 +273,7 @@ var (
 		ComponentPrometheus,
 		ComponentTigeraPrometheusService,
 		ComponentPrometheusAlertmanager,
+		ComponentThanos,
 		ComponentQueryServer,
 		ComponentTigeraKubeControllers,
 		ComponentTigeraNode,
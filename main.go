@@ -19,13 +19,18 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/url"
 	"os"
 	goruntime "runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudflare/cfssl/log"
 	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -46,10 +51,14 @@ import (
 	"github.com/tigera/operator/pkg/awssgsetup"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/connectivitytest"
+	"github.com/tigera/operator/pkg/controller/installation"
 	"github.com/tigera/operator/pkg/controller/options"
 	"github.com/tigera/operator/pkg/controller/utils"
 	"github.com/tigera/operator/pkg/crds"
 	"github.com/tigera/operator/pkg/dns"
+	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/statusreport"
 	"github.com/tigera/operator/version"
 	// +kubebuilder:scaffold:imports
 )
@@ -88,6 +97,12 @@ func main() {
 	var printEnterpriseCRDs string
 	var sgSetup bool
 	var manageCRDs bool
+	var clusterDomain string
+	var connectivityTest bool
+	var connectivityTestServer bool
+	var manifestsOnly bool
+	var complianceReport bool
+	var maxConcurrentReconciles int
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", true,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -105,6 +120,18 @@ func main() {
 		"Setup Security Groups in AWS (should only be used on OpenShift).")
 	flag.BoolVar(&manageCRDs, "manage-crds", false,
 		"Operator should manage the projectcalico.org and operator.tigera.io CRDs.")
+	flag.StringVar(&clusterDomain, "cluster-domain", "",
+		"The cluster domain of the cluster. When not set, it is detected from the cluster.")
+	flag.BoolVar(&connectivityTest, "connectivity-test", false,
+		"Run the checks of the dataplane connectivity test then exit (should only be used by the connectivity test Job).")
+	flag.BoolVar(&connectivityTestServer, "connectivity-test-server", false,
+		"Serve the connections of the dataplane connectivity test (should only be used by the connectivity test pods).")
+	flag.BoolVar(&manifestsOnly, "manifests-only", false,
+		"Write the manifests rendered for each component to a Secret of the operator namespace instead of applying them, for a GitOps pipeline to apply them.")
+	flag.BoolVar(&complianceReport, "compliance-report", false,
+		"Evaluate the manifests rendered for each component against the built-in hardening checklist, and write the findings to a ConfigMap of the operator namespace.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", utils.DefaultReconcileSlots,
+		"The number of reconciles the controllers run at once, time-sliced between the controllers so that a busy controller doesn't starve the others. 0 doesn't limit them.")
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
@@ -149,6 +176,58 @@ func main() {
 		os.Exit(0)
 	}
 
+	// The status command prints the status of the installation for a quick triage, e.g.
+	// kubectl exec -n tigera-operator deploy/tigera-operator -- operator status
+	if flag.Arg(0) == "status" {
+		if err := printStatus(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// The simulate command renders the components for a synthetic topology, to plan the capacity of a rollout, e.g.
+	// operator simulate topology.yaml
+	if flag.Arg(0) == "simulate" {
+		if err := printSimulation(flag.Arg(1)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// The rollback command rolls the Installation back to a known-good revision, or to the previous one when the
+	// revision is omitted, e.g.
+	// kubectl exec -n tigera-operator deploy/tigera-operator -- operator rollback 3
+	if flag.Arg(0) == "rollback" {
+		if err := rollbackInstallation(flag.Arg(1)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// The connectivity test pods are rendered by the operator, and don't need the API server.
+	if connectivityTestServer {
+		cfg := connectivitytest.ConfigFromEnv()
+		if err := connectivitytest.Serve(ctrl.SetupSignalHandler(), cfg.Port); err != nil {
+			setupLog.Error(err, "Connectivity test server failed")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if connectivityTest {
+		if failures := connectivitytest.Run(context.Background(), connectivitytest.ConfigFromEnv()); len(failures) > 0 {
+			msg := strings.Join(failures, "; ")
+			// The message is reported by the operator in the status of the Installation.
+			_ = ioutil.WriteFile(corev1.TerminationMessagePathDefault, []byte(msg), 0644)
+			setupLog.Info("Connectivity test failed", "failures", msg)
+			os.Exit(1)
+		}
+		setupLog.Info("Connectivity test passed")
+		os.Exit(0)
+	}
+
 	if urlOnlyKubeconfig != "" {
 		if err := setKubernetesServiceEnv(urlOnlyKubeconfig); err != nil {
 			setupLog.Error(err, "Terminating")
@@ -202,6 +281,16 @@ func main() {
 		Port:               9443,
 		LeaderElection:     enableLeaderElection,
 		LeaderElectionID:   "operator-lock",
+		// Only cache the secrets and configmaps rendered by the operator, along with the ones of the namespaces holding
+		// secrets and configmaps provided by the user or created by ECK.
+		NewCache: utils.NewCacheFunc(
+			common.OperatorNamespace(),
+			common.CalicoNamespace,
+			common.TigeraPrometheusNamespace,
+			render.ECKOperatorNamespace,
+			render.ElasticsearchNamespace,
+			render.KibanaNamespace,
+		),
 		// We should test this again in the future to see if the problem with LicenseKey updates
 		// being missed is resolved. Prior to controller-runtime 0.7 we observed Test failures
 		// where LicenseKey updates would be missed and the client cache did not have the LicenseKey.
@@ -246,26 +335,50 @@ func main() {
 		os.Exit(1)
 	}
 
-	clusterDomain, err := dns.GetClusterDomain(dns.DefaultResolveConfPath)
-	if err != nil {
-		clusterDomain = dns.DefaultClusterDomain
-		log.Error(err, fmt.Sprintf("Couldn't find the cluster domain from the resolv.conf, defaulting to %s", clusterDomain))
+	if clusterDomain == "" {
+		apiServerAddr := net.JoinHostPort(os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT"))
+		clusterDomain, err = dns.DetectClusterDomain(ctx, clientset, dns.DefaultResolveConfPath, apiServerAddr)
+		if err != nil {
+			clusterDomain = dns.DefaultClusterDomain
+			log.Error(err, fmt.Sprintf("Couldn't detect the cluster domain, defaulting to %s", clusterDomain))
+			go restartOnClusterDomainChange(sigHandler, clientset, apiServerAddr, clusterDomain)
+		}
 	}
+	setupLog.WithValues("clusterDomain", clusterDomain).Info("Using cluster domain")
 
 	kubernetesVersion, err := common.GetKubernetesVersion(clientset)
 	if err != nil {
 		log.Error(err, fmt.Sprintf("Unable to resolve Kubernetes version, defaulting to v1.18"))
 		kubernetesVersion = &common.VersionInfo{Major: 1, Minor: 18}
 	}
+	// The version changes when the cluster is upgraded, which is tracked while the operator runs.
+	kubernetesVersionTracker := common.NewKubernetesVersionTracker(clientset, kubernetesVersion)
+	if err = mgr.Add(kubernetesVersionTracker); err != nil {
+		setupLog.Error(err, "unable to track the Kubernetes version")
+		os.Exit(1)
+	}
 
 	options := options.AddOptions{
 		DetectedProvider:    provider,
 		EnterpriseCRDExists: enterpriseCRDExists,
 		AmazonCRDExists:     amazonCRDExists,
 		ClusterDomain:       clusterDomain,
-		KubernetesVersion:   kubernetesVersion,
+		KubernetesVersion:   kubernetesVersionTracker,
 		ManageCRDs:          manageCRDs,
 		ShutdownContext:     sigHandler,
+		LicenseKeyCache:     utils.NewLicenseKeyCache(),
+	}
+
+	if manifestsOnly {
+		setupLog.Info("Exporting the manifests of the components instead of applying them")
+		utils.SetManifestExporter(utils.NewSecretManifestExporter(mgr.GetClient()))
+	}
+	if complianceReport {
+		setupLog.Info("Reporting the compliance findings of the components")
+		utils.SetComplianceReporter(utils.NewConfigMapComplianceReporter(mgr.GetClient()))
+	}
+	if maxConcurrentReconciles > 0 {
+		utils.SetReconcileScheduler(utils.NewReconcileScheduler(maxConcurrentReconciles))
 	}
 
 	err = controllers.AddToManager(mgr, options)
@@ -282,6 +395,31 @@ func main() {
 
 }
 
+// restartOnClusterDomainChange keeps detecting the cluster domain after the operator started with the assumed default
+// one. The operator exits once a different cluster domain is detected, so that it is restarted and re-renders the
+// resources depending on DNS names, e.g. certificates and service addresses, with the detected cluster domain.
+func restartOnClusterDomainChange(ctx context.Context, cs kubernetes.Interface, apiServerAddr, assumed string) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		clusterDomain, err := dns.DetectClusterDomain(ctx, cs, dns.DefaultResolveConfPath, apiServerAddr)
+		if err != nil {
+			continue
+		}
+		if clusterDomain != assumed {
+			setupLog.WithValues("clusterDomain", clusterDomain, "assumed", assumed).Info("Detected a different cluster domain, restarting")
+			os.Exit(0)
+		}
+		return
+	}
+}
+
 // setKubernetesServiceEnv configured the environment with the location of the Kubernetes API
 // based on the provided kubeconfig file. We need this since we can't rely on the kube-proxy being present,
 // since this operator may be the one installing the proxy! It's based off of logic in the cluster-network-operator.
@@ -357,3 +495,61 @@ func showCRDs(variant operatorv1.ProductVariant, outputType string) error {
 
 	return nil
 }
+
+// printStatus prints the status of the Installation, of the components and of the dataplane of the nodes.
+func printStatus() error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	return statusreport.Print(context.Background(), c, os.Stdout)
+}
+
+// rollbackInstallation requests the rollback of the Installation to the given revision, or to the previous one if the
+// revision is empty.
+func rollbackInstallation(arg string) error {
+	var revision int64
+	if arg != "" {
+		var err error
+		if revision, err = strconv.ParseInt(arg, 10, 64); err != nil || revision <= 0 {
+			return fmt.Errorf("usage: operator rollback [revision]")
+		}
+	}
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	if err = installation.RequestRollback(context.Background(), c, revision); err != nil {
+		return err
+	}
+	fmt.Println("Rolling back the Installation, its revision is reported in status.revision once it is available")
+	return nil
+}
+
+// printSimulation prints what the components need for the topology of the given file.
+func printSimulation(path string) error {
+	if path == "" {
+		return fmt.Errorf("usage: operator simulate <topology file>")
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	topology := &installation.SimulatedTopology{}
+	if err = yaml.Unmarshal(b, topology); err != nil {
+		return fmt.Errorf("failed to parse the topology: %w", err)
+	}
+	report, err := installation.Simulate(topology)
+	if err != nil {
+		return err
+	}
+	return report.Print(os.Stdout)
+}
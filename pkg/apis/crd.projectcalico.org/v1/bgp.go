@@ -0,0 +1,138 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	KindBGPPeer              = "BGPPeer"
+	KindBGPPeerList          = "BGPPeerList"
+	KindBGPConfiguration     = "BGPConfiguration"
+	KindBGPConfigurationList = "BGPConfigurationList"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BGPPeer contains information about a BGPPeer resource.
+type BGPPeer struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the BGPPeer.
+	Spec BGPPeerSpec `json:"spec,omitempty"`
+}
+
+// BGPPeerSpec contains the specification for a BGPPeer resource.
+type BGPPeerSpec struct {
+	// The node name identifying the Calico node instance that is targeted by this peer.
+	// If this is not set, and no nodeSelector is specified, then this BGP peer selects all
+	// nodes in the cluster.
+	Node string `json:"node,omitempty"`
+
+	// The IP address of the peer followed by an optional port number to peer with.
+	PeerIP string `json:"peerIP,omitempty"`
+
+	// The AS Number of the peer.
+	ASNumber uint32 `json:"asNumber,omitempty"`
+
+	// Selector for the nodes that should have this peering. When this is set, the Node
+	// field must be empty.
+	NodeSelector string `json:"nodeSelector,omitempty"`
+
+	// Selector for the remote nodes to peer with. When this is set, the PeerIP and
+	// ASNumber fields must be empty.
+	PeerSelector string `json:"peerSelector,omitempty"`
+
+	// Time to allow for software restart. When specified, this is configured as the graceful
+	// restart timeout. When not specified, the BIRD default of 120s is used.
+	MaxRestartTime *metav1.Duration `json:"maxRestartTime,omitempty"`
+
+	// Optional BGP password for the peerings generated by this BGPPeer resource.
+	Password *BGPPassword `json:"password,omitempty"`
+}
+
+// BGPPassword contains ways to specify a BGP password.
+type BGPPassword struct {
+	// Selects a key of a secret in the node pod's namespace.
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BGPPeerList contains a list of BGPPeer resources.
+type BGPPeerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []BGPPeer `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BGPConfiguration contains the configuration for any BGP routing.
+type BGPConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the BGPConfiguration.
+	Spec BGPConfigurationSpec `json:"spec,omitempty"`
+}
+
+// BGPConfigurationSpec contains the values of the BGP configuration.
+type BGPConfigurationSpec struct {
+	// NodeToNodeMeshEnabled sets whether full node to node BGP mesh is enabled. [Default: true]
+	NodeToNodeMeshEnabled *bool `json:"nodeToNodeMeshEnabled,omitempty"`
+
+	// ASNumber is the default AS number used by a node. [Default: 64512]
+	ASNumber *uint32 `json:"asNumber,omitempty"`
+
+	// ServiceClusterIPs are the CIDR blocks from which service cluster IPs are allocated.
+	// If specified, Calico will advertise these blocks, as well as any cluster IPs within them.
+	ServiceClusterIPs []ServiceClusterIPBlock `json:"serviceClusterIPs,omitempty"`
+
+	// ServiceExternalIPs are the CIDR blocks for Kubernetes Service External IPs.
+	// Kubernetes Service ExternalIPs will only be advertised if they are within one of these blocks.
+	ServiceExternalIPs []ServiceExternalIPBlock `json:"serviceExternalIPs,omitempty"`
+
+	// ServiceLoadBalancerIPs are the CIDR blocks for Kubernetes Service LoadBalancer IPs.
+	// Kubernetes Service status.LoadBalancer.Ingress IPs will only be advertised if they are within one of these blocks.
+	ServiceLoadBalancerIPs []ServiceLoadBalancerIPBlock `json:"serviceLoadBalancerIPs,omitempty"`
+}
+
+// ServiceClusterIPBlock represents a single allowed ClusterIP CIDR block.
+type ServiceClusterIPBlock struct {
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// ServiceExternalIPBlock represents a single allowed External IP CIDR block.
+type ServiceExternalIPBlock struct {
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// ServiceLoadBalancerIPBlock represents a single allowed LoadBalancer IP CIDR block.
+type ServiceLoadBalancerIPBlock struct {
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BGPConfigurationList contains a list of BGPConfiguration resources.
+type BGPConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []BGPConfiguration `json:"items"`
+}
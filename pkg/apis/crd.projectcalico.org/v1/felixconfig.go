@@ -354,13 +354,29 @@ type FelixConfigurationSpec struct {
 	// WireguardMTU controls the MTU on the Wireguard interface. See Configuring MTU [Default: 1420]
 	WireguardMTU *int `json:"wireguardMTU,omitempty"`
 
+	// IPSecMode controls which mode IPSec is operating on. Default value means IPSec is not enabled. Only supported
+	// by Calico Enterprise. [Default: ""]
+	IPSecMode string `json:"ipsecMode,omitempty"`
+
 	// Set source-destination-check on AWS EC2 instances. Accepted value must be one of "DoNothing", "Enabled" or "Disabled".
 	// [Default: DoNothing]
 	AWSSrcDstCheck *AWSSrcDstCheckOption `json:"awsSrcDstCheck,omitempty" validate:"omitempty,oneof=DoNothing Enable Disable"`
 
+	// AWSSecondaryIPSupport controls whether Felix will try to provision AWS secondary ENIs and secondary IPs for
+	// workloads that have IPs from IP pools that are configured with an AWS subnet ID. [Default: Disabled]
+	AWSSecondaryIPSupport string `json:"awsSecondaryIPSupport,omitempty" validate:"omitempty,oneof=Enabled EnabledENIPerWorkload Disabled"`
+
+	// AWSSecondaryIPRoutingRulePriority controls the priority that Felix will use for routing rules when programming
+	// them for AWS Secondary IP support. [Default: 101]
+	AWSSecondaryIPRoutingRulePriority *int `json:"awsSecondaryIPRoutingRulePriority,omitempty" validate:"omitempty,gte=0,lte=4294967295"`
+
 	// TPROXYMode sets whether traffic is directed through a transparent proxy for further processing or not
 	// [Default: Disabled]
 	TPROXYMode *TPROXYModeOption `json:"tproxyMode,omitempty"`
+
+	// FlowLogsGoldmaneServer is the address of the Goldmane server to which Felix reports the flows of the node. The
+	// flows are not reported when it is not set.
+	FlowLogsGoldmaneServer *string `json:"flowLogsGoldmaneServer,omitempty"`
 }
 
 type RouteTableRange struct {
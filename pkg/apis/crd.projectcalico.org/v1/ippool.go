@@ -59,6 +59,11 @@ type IPPoolSpec struct {
 
 	// Allows IPPool to allocate for a specific node by label selector.
 	NodeSelector string `json:"nodeSelector,omitempty" validate:"omitempty,selector"`
+
+	// AWSSubnetID if specified Calico will attempt to ensure that IPs chosen from this IP pool are routed
+	// to the corresponding node by adding one or more secondary ENIs to the node and explicitly assigning
+	// the IP to one of the secondary ENIs. Only IPv4 pools are supported.
+	AWSSubnetID string `json:"awsSubnetID,omitempty" validate:"omitempty"`
 }
 
 type VXLANMode string
@@ -34,6 +34,10 @@ var (
 
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&BGPPeer{},
+		&BGPPeerList{},
+		&BGPConfiguration{},
+		&BGPConfigurationList{},
 		&IPPool{},
 		&IPPoolList{},
 		&FelixConfiguration{},
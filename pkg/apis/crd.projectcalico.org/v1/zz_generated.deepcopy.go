@@ -23,10 +23,267 @@ package v1
 
 import (
 	"github.com/tigera/operator/pkg/controller/migration/convert/numorstring"
+	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoHostEndpointConfig) DeepCopyInto(out *AutoHostEndpointConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoHostEndpointConfig.
+func (in *AutoHostEndpointConfig) DeepCopy() *AutoHostEndpointConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoHostEndpointConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPConfiguration) DeepCopyInto(out *BGPConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPConfiguration.
+func (in *BGPConfiguration) DeepCopy() *BGPConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BGPConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPConfigurationList) DeepCopyInto(out *BGPConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BGPConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPConfigurationList.
+func (in *BGPConfigurationList) DeepCopy() *BGPConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BGPConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPConfigurationSpec) DeepCopyInto(out *BGPConfigurationSpec) {
+	*out = *in
+	if in.NodeToNodeMeshEnabled != nil {
+		in, out := &in.NodeToNodeMeshEnabled, &out.NodeToNodeMeshEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ASNumber != nil {
+		in, out := &in.ASNumber, &out.ASNumber
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.ServiceClusterIPs != nil {
+		in, out := &in.ServiceClusterIPs, &out.ServiceClusterIPs
+		*out = make([]ServiceClusterIPBlock, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceExternalIPs != nil {
+		in, out := &in.ServiceExternalIPs, &out.ServiceExternalIPs
+		*out = make([]ServiceExternalIPBlock, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceLoadBalancerIPs != nil {
+		in, out := &in.ServiceLoadBalancerIPs, &out.ServiceLoadBalancerIPs
+		*out = make([]ServiceLoadBalancerIPBlock, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPConfigurationSpec.
+func (in *BGPConfigurationSpec) DeepCopy() *BGPConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPassword) DeepCopyInto(out *BGPPassword) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPassword.
+func (in *BGPPassword) DeepCopy() *BGPPassword {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPPassword)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPeer) DeepCopyInto(out *BGPPeer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeer.
+func (in *BGPPeer) DeepCopy() *BGPPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BGPPeer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPeerList) DeepCopyInto(out *BGPPeerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BGPPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeerList.
+func (in *BGPPeerList) DeepCopy() *BGPPeerList {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPPeerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BGPPeerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPeerSpec) DeepCopyInto(out *BGPPeerSpec) {
+	*out = *in
+	if in.MaxRestartTime != nil {
+		in, out := &in.MaxRestartTime, &out.MaxRestartTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(BGPPassword)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeerSpec.
+func (in *BGPPeerSpec) DeepCopy() *BGPPeerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPPeerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllersConfig) DeepCopyInto(out *ControllersConfig) {
+	*out = *in
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(NodeControllerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(PolicyControllerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadEndpoint != nil {
+		in, out := &in.WorkloadEndpoint, &out.WorkloadEndpoint
+		*out = new(WorkloadEndpointControllerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(ServiceAccountControllerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(NamespaceControllerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllersConfig.
+func (in *ControllersConfig) DeepCopy() *ControllersConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllersConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FelixConfiguration) DeepCopyInto(out *FelixConfiguration) {
 	*out = *in
@@ -414,11 +671,21 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 		*out = new(AWSSrcDstCheckOption)
 		**out = **in
 	}
+	if in.AWSSecondaryIPRoutingRulePriority != nil {
+		in, out := &in.AWSSecondaryIPRoutingRulePriority, &out.AWSSecondaryIPRoutingRulePriority
+		*out = new(int)
+		**out = **in
+	}
 	if in.TPROXYMode != nil {
 		in, out := &in.TPROXYMode, &out.TPROXYMode
 		*out = new(TPROXYModeOption)
 		**out = **in
 	}
+	if in.FlowLogsGoldmaneServer != nil {
+		in, out := &in.FlowLogsGoldmaneServer, &out.FlowLogsGoldmaneServer
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FelixConfigurationSpec.
@@ -510,6 +777,7 @@ func (in *KubeControllersConfiguration) DeepCopyInto(out *KubeControllersConfigu
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllersConfiguration.
@@ -565,11 +833,22 @@ func (in *KubeControllersConfigurationList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeControllersConfigurationSpec) DeepCopyInto(out *KubeControllersConfigurationSpec) {
 	*out = *in
+	if in.EtcdV3CompactionPeriod != nil {
+		in, out := &in.EtcdV3CompactionPeriod, &out.EtcdV3CompactionPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.PrometheusMetricsPort != nil {
 		in, out := &in.PrometheusMetricsPort, &out.PrometheusMetricsPort
 		*out = new(int)
 		**out = **in
 	}
+	in.Controllers.DeepCopyInto(&out.Controllers)
+	if in.DebugProfilePort != nil {
+		in, out := &in.DebugProfilePort, &out.DebugProfilePort
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllersConfigurationSpec.
@@ -582,6 +861,99 @@ func (in *KubeControllersConfigurationSpec) DeepCopy() *KubeControllersConfigura
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeControllersConfigurationStatus) DeepCopyInto(out *KubeControllersConfigurationStatus) {
+	*out = *in
+	in.RunningConfig.DeepCopyInto(&out.RunningConfig)
+	if in.EnvironmentVars != nil {
+		in, out := &in.EnvironmentVars, &out.EnvironmentVars
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllersConfigurationStatus.
+func (in *KubeControllersConfigurationStatus) DeepCopy() *KubeControllersConfigurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeControllersConfigurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceControllerConfig) DeepCopyInto(out *NamespaceControllerConfig) {
+	*out = *in
+	if in.ReconcilerPeriod != nil {
+		in, out := &in.ReconcilerPeriod, &out.ReconcilerPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceControllerConfig.
+func (in *NamespaceControllerConfig) DeepCopy() *NamespaceControllerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeControllerConfig) DeepCopyInto(out *NodeControllerConfig) {
+	*out = *in
+	if in.ReconcilerPeriod != nil {
+		in, out := &in.ReconcilerPeriod, &out.ReconcilerPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.HostEndpoint != nil {
+		in, out := &in.HostEndpoint, &out.HostEndpoint
+		*out = new(AutoHostEndpointConfig)
+		**out = **in
+	}
+	if in.LeakGracePeriod != nil {
+		in, out := &in.LeakGracePeriod, &out.LeakGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeControllerConfig.
+func (in *NodeControllerConfig) DeepCopy() *NodeControllerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyControllerConfig) DeepCopyInto(out *PolicyControllerConfig) {
+	*out = *in
+	if in.ReconcilerPeriod != nil {
+		in, out := &in.ReconcilerPeriod, &out.ReconcilerPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyControllerConfig.
+func (in *PolicyControllerConfig) DeepCopy() *PolicyControllerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProtoPort) DeepCopyInto(out *ProtoPort) {
 	*out = *in
@@ -611,3 +983,88 @@ func (in *RouteTableRange) DeepCopy() *RouteTableRange {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountControllerConfig) DeepCopyInto(out *ServiceAccountControllerConfig) {
+	*out = *in
+	if in.ReconcilerPeriod != nil {
+		in, out := &in.ReconcilerPeriod, &out.ReconcilerPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountControllerConfig.
+func (in *ServiceAccountControllerConfig) DeepCopy() *ServiceAccountControllerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceClusterIPBlock) DeepCopyInto(out *ServiceClusterIPBlock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceClusterIPBlock.
+func (in *ServiceClusterIPBlock) DeepCopy() *ServiceClusterIPBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceClusterIPBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceExternalIPBlock) DeepCopyInto(out *ServiceExternalIPBlock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceExternalIPBlock.
+func (in *ServiceExternalIPBlock) DeepCopy() *ServiceExternalIPBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceExternalIPBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceLoadBalancerIPBlock) DeepCopyInto(out *ServiceLoadBalancerIPBlock) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceLoadBalancerIPBlock.
+func (in *ServiceLoadBalancerIPBlock) DeepCopy() *ServiceLoadBalancerIPBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceLoadBalancerIPBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadEndpointControllerConfig) DeepCopyInto(out *WorkloadEndpointControllerConfig) {
+	*out = *in
+	if in.ReconcilerPeriod != nil {
+		in, out := &in.ReconcilerPeriod, &out.ReconcilerPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadEndpointControllerConfig.
+func (in *WorkloadEndpointControllerConfig) DeepCopy() *WorkloadEndpointControllerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadEndpointControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -29,4 +29,17 @@ const (
 	ThreatDefenseFeature = "threat-defense"
 	// ExportLogsFeature to 3rd party systems feature name
 	ExportLogsFeature = "export-logs"
+
+	// OperatorManagedLabel is set on the secrets and configmaps rendered by the operator, which only caches the ones
+	// carrying it outside of a few namespaces.
+	OperatorManagedLabel = "operator.tigera.io/managed"
+
+	// DataplaneMigrationTaintKey is the key of the NoSchedule taint of the nodes being migrated to the VPP dataplane.
+	DataplaneMigrationTaintKey = "operator.tigera.io/dataplane-migration"
+	// VPPBootstrapTaintKey is the key of the NoSchedule taint of the nodes joining the VPP dataplane, until their
+	// dataplane is ready.
+	VPPBootstrapTaintKey = "operator.tigera.io/vpp-dataplane-bootstrap"
+	// LinuxDataplaneLabel is set on the nodes migrated to the VPP dataplane, with the dataplane as value, so that
+	// the VPP dataplane is only scheduled on them.
+	LinuxDataplaneLabel = "operator.tigera.io/linux-dataplane"
 )
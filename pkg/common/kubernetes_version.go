@@ -15,13 +15,20 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 )
 
+// kubernetesVersionPollInterval is the interval at which the KubernetesVersionTracker reads the version of the API
+// server.
+const kubernetesVersionPollInterval = 5 * time.Minute
+
 // VersionInfo contains information about the version of Kubernetes API the cluster is using
 // Major and Minor fields map to the v<Major>.<Minor>+ part of the version string
 type VersionInfo struct {
@@ -59,3 +66,89 @@ func (v *VersionInfo) ProvidesCertV1API() bool {
 	}
 	return false
 }
+
+// ProvidesPodSecurityPolicy returns if policy/v1beta1 PodSecurityPolicies are supported given the current k8s version.
+// They are removed in v1.25.
+func (v *VersionInfo) ProvidesPodSecurityPolicy() bool {
+	if v != nil && (v.Major > 1 || (v.Major == 1 && v.Minor >= 25)) {
+		return false
+	}
+	return true
+}
+
+// ProvidesCSIDriverV1API returns if k8s.io/api/storage/v1 CSIDrivers are supported given the current k8s version
+func (v *VersionInfo) ProvidesCSIDriverV1API() bool {
+	if v != nil && (v.Major > 1 || (v.Major == 1 && v.Minor >= 18)) {
+		return true
+	}
+	return false
+}
+
+// KubernetesVersionTracker holds the version of the Kubernetes API server. The version is read again periodically, so
+// that the rendering depending on it is re-evaluated when the cluster is upgraded, without restarting the operator.
+type KubernetesVersionTracker struct {
+	clientset kubernetes.Interface
+	lock      sync.RWMutex
+	version   *VersionInfo
+	listeners []func()
+}
+
+// NewKubernetesVersionTracker returns a KubernetesVersionTracker starting with the given version.
+func NewKubernetesVersionTracker(clientset kubernetes.Interface, version *VersionInfo) *KubernetesVersionTracker {
+	return &KubernetesVersionTracker{clientset: clientset, version: version}
+}
+
+// Get returns the last known version of the API server. A nil tracker has no version.
+func (t *KubernetesVersionTracker) Get() *VersionInfo {
+	if t == nil {
+		return nil
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.version
+}
+
+// OnChange registers a function called after every change of the version.
+func (t *KubernetesVersionTracker) OnChange(f func()) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.listeners = append(t.listeners, f)
+}
+
+// Refresh reads the version of the API server and notifies the listeners if it changed. It returns whether the
+// version changed.
+func (t *KubernetesVersionTracker) Refresh() (bool, error) {
+	v, err := GetKubernetesVersion(t.clientset)
+	if err != nil {
+		return false, err
+	}
+
+	t.lock.Lock()
+	if t.version != nil && *t.version == *v {
+		t.lock.Unlock()
+		return false, nil
+	}
+	t.version = v
+	listeners := append([]func(){}, t.listeners...)
+	t.lock.Unlock()
+
+	for _, f := range listeners {
+		f()
+	}
+	return true, nil
+}
+
+// Start refreshes the version periodically until the context is done. Errors are ignored, the version is read again
+// at the next interval.
+func (t *KubernetesVersionTracker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(kubernetesVersionPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			_, _ = t.Refresh()
+		}
+	}
+}
@@ -74,3 +74,34 @@ var _ = Describe("Test get Kubernetes version", func() {
 		Expect(err).To(Equal(fmt.Errorf("failed to parse k8s minor version: %s", invalidMinor)))
 	})
 })
+
+var _ = Describe("Test the Kubernetes version tracker", func() {
+	It("should notify the listeners when the version changes", func() {
+		clientset := fake.NewSimpleClientset()
+		clientset.Discovery().(*discoveryFake.FakeDiscovery).FakedServerVersion = &version.Info{Major: "1", Minor: "24"}
+		tracker := NewKubernetesVersionTracker(clientset, &VersionInfo{Major: 1, Minor: 24})
+		notified := 0
+		tracker.OnChange(func() { notified++ })
+
+		changed, err := tracker.Refresh()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(notified).To(Equal(0))
+		Expect(tracker.Get().ProvidesPodSecurityPolicy()).To(BeTrue())
+
+		clientset.Discovery().(*discoveryFake.FakeDiscovery).FakedServerVersion = &version.Info{Major: "1", Minor: "25"}
+		changed, err = tracker.Refresh()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(notified).To(Equal(1))
+		Expect(*tracker.Get()).To(Equal(VersionInfo{Major: 1, Minor: 25}))
+		Expect(tracker.Get().ProvidesPodSecurityPolicy()).To(BeFalse())
+	})
+
+	It("should have no version when nil", func() {
+		var tracker *KubernetesVersionTracker
+		Expect(tracker.Get()).To(BeNil())
+		Expect(tracker.Get().ProvidesPodSecurityPolicy()).To(BeTrue())
+		Expect(tracker.Get().ProvidesCSIDriverV1API()).To(BeFalse())
+	})
+})
@@ -0,0 +1,179 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compliance evaluates the objects rendered by the operator against a built-in hardening checklist, derived
+// from the FIPS and STIG requirements, for the security reviews of the deployments.
+package compliance
+
+import (
+	"fmt"
+	"strings"
+
+	apps "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta "k8s.io/api/batch/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Severity is the severity of a finding.
+type Severity string
+
+const (
+	SeverityHigh   Severity = "High"
+	SeverityMedium Severity = "Medium"
+)
+
+// The checks of the checklist.
+const (
+	// CheckPrivilegedContainer flags the containers running privileged.
+	CheckPrivilegedContainer = "privileged-container"
+	// CheckHostPathMount flags the containers mounting a path of the host.
+	CheckHostPathMount = "host-path-mount"
+	// CheckTLSMinVersion flags the containers configured to accept a TLS version older than TLS 1.2, through an
+	// environment variable named *_TLS_MIN_VERSION or a --tls-min-version argument.
+	CheckTLSMinVersion = "tls-min-version"
+	// CheckTLSCipherSuites flags the containers configured to accept TLS cipher suites which aren't approved by FIPS
+	// 140-2, through an environment variable named *_TLS_CIPHER_SUITES or a --tls-cipher-suites argument.
+	CheckTLSCipherSuites = "tls-cipher-suites"
+)
+
+// weakTLSVersions are the spellings of the TLS versions older than TLS 1.2 in the settings of the components.
+var weakTLSVersions = map[string]bool{
+	"VersionTLS10": true,
+	"VersionTLS11": true,
+	"TLS10":        true,
+	"TLS11":        true,
+	"TLSv1":        true,
+	"TLSv1.0":      true,
+	"TLSv1.1":      true,
+	"1.0":          true,
+	"1.1":          true,
+}
+
+// fipsCipherSuites are the TLS cipher suites approved by FIPS 140-2, which are the ones Go accepts in its FIPS mode.
+var fipsCipherSuites = map[string]bool{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256": true,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384": true,
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":   true,
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":   true,
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":         true,
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":         true,
+	"TLS_AES_128_GCM_SHA256":                  true,
+	"TLS_AES_256_GCM_SHA384":                  true,
+}
+
+// nonFIPSCipherSuites returns the cipher suites of the given comma separated list which aren't approved by FIPS.
+func nonFIPSCipherSuites(suites string) []string {
+	var nonFIPS []string
+	for _, suite := range strings.Split(suites, ",") {
+		if suite = strings.TrimSpace(suite); suite != "" && !fipsCipherSuites[suite] {
+			nonFIPS = append(nonFIPS, suite)
+		}
+	}
+	return nonFIPS
+}
+
+// Finding is a failed check of the checklist for a container of a rendered object.
+type Finding struct {
+	Check     string   `json:"check"`
+	Severity  Severity `json:"severity"`
+	Kind      string   `json:"kind"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name"`
+	Container string   `json:"container"`
+	Message   string   `json:"message"`
+}
+
+// Evaluate returns the findings of the checklist for the pod templates of the given objects, in the order of the
+// objects and of their containers. The objects which don't template pods have no findings.
+func Evaluate(objs []client.Object) []Finding {
+	var findings []Finding
+	for _, obj := range objs {
+		kind, podSpec := podTemplate(obj)
+		if podSpec == nil {
+			continue
+		}
+		hostPaths := map[string]string{}
+		for _, vol := range podSpec.Volumes {
+			if vol.HostPath != nil {
+				hostPaths[vol.Name] = vol.HostPath.Path
+			}
+		}
+
+		containers := append(append([]v1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+		for _, c := range containers {
+			finding := func(check string, severity Severity, format string, args ...interface{}) {
+				findings = append(findings, Finding{
+					Check:     check,
+					Severity:  severity,
+					Kind:      kind,
+					Namespace: obj.GetNamespace(),
+					Name:      obj.GetName(),
+					Container: c.Name,
+					Message:   fmt.Sprintf(format, args...),
+				})
+			}
+
+			if c.SecurityContext != nil && c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged {
+				finding(CheckPrivilegedContainer, SeverityHigh, "the container runs privileged")
+			}
+			for _, m := range c.VolumeMounts {
+				if path, ok := hostPaths[m.Name]; ok {
+					finding(CheckHostPathMount, SeverityMedium, "the container mounts the host path %s on %s", path, m.MountPath)
+				}
+			}
+			for _, env := range c.Env {
+				if strings.HasSuffix(env.Name, "TLS_MIN_VERSION") && weakTLSVersions[env.Value] {
+					finding(CheckTLSMinVersion, SeverityHigh, "the environment variable %s accepts %s, older than TLS 1.2", env.Name, env.Value)
+				}
+				if strings.HasSuffix(env.Name, "TLS_CIPHER_SUITES") {
+					if suites := nonFIPSCipherSuites(env.Value); len(suites) > 0 {
+						finding(CheckTLSCipherSuites, SeverityHigh, "the environment variable %s accepts %s, not approved by FIPS", env.Name, strings.Join(suites, ","))
+					}
+				}
+			}
+			for _, arg := range append(append([]string{}, c.Command...), c.Args...) {
+				if v := strings.TrimPrefix(arg, "--tls-min-version="); v != arg && weakTLSVersions[v] {
+					finding(CheckTLSMinVersion, SeverityHigh, "the argument %s accepts a version older than TLS 1.2", arg)
+				}
+				if v := strings.TrimPrefix(arg, "--tls-cipher-suites="); v != arg {
+					if suites := nonFIPSCipherSuites(v); len(suites) > 0 {
+						finding(CheckTLSCipherSuites, SeverityHigh, "the argument --tls-cipher-suites accepts %s, not approved by FIPS", strings.Join(suites, ","))
+					}
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// podTemplate returns the kind of obj and the spec of the pods it templates, or nil if it doesn't template pods.
+func podTemplate(obj client.Object) (string, *v1.PodSpec) {
+	switch o := obj.(type) {
+	case *v1.PodTemplate:
+		return "PodTemplate", &o.Template.Spec
+	case *apps.Deployment:
+		return "Deployment", &o.Spec.Template.Spec
+	case *apps.DaemonSet:
+		return "DaemonSet", &o.Spec.Template.Spec
+	case *apps.StatefulSet:
+		return "StatefulSet", &o.Spec.Template.Spec
+	case *batchv1beta.CronJob:
+		return "CronJob", &o.Spec.JobTemplate.Spec.Template.Spec
+	case *batchv1.Job:
+		return "Job", &o.Spec.Template.Spec
+	}
+	return "", nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tigera/operator/pkg/compliance"
+	"github.com/tigera/operator/pkg/ptr"
+)
+
+var _ = Describe("Compliance checklist", func() {
+	It("reports the privileged containers, the host path mounts and the TLS versions older than TLS 1.2", func() {
+		ds := &apps.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-node", Namespace: "calico-system"},
+			Spec: apps.DaemonSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+				Volumes: []v1.Volume{
+					{Name: "var-run", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/var/run"}}},
+					{Name: "tmp", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+				},
+				InitContainers: []v1.Container{{
+					Name:            "install-cni",
+					SecurityContext: &v1.SecurityContext{Privileged: ptr.BoolToPtr(true)},
+				}},
+				Containers: []v1.Container{{
+					Name:         "calico-node",
+					VolumeMounts: []v1.VolumeMount{{Name: "var-run", MountPath: "/host/var/run"}, {Name: "tmp", MountPath: "/tmp"}},
+					Env:          []v1.EnvVar{{Name: "ES_GATEWAY_TLS_MIN_VERSION", Value: "VersionTLS11"}},
+					Args:         []string{"--tls-min-version=VersionTLS10"},
+				}},
+			}}},
+		}
+
+		findings := compliance.Evaluate([]client.Object{ds})
+		Expect(findings).To(Equal([]compliance.Finding{
+			{
+				Check: compliance.CheckPrivilegedContainer, Severity: compliance.SeverityHigh, Kind: "DaemonSet",
+				Namespace: "calico-system", Name: "calico-node", Container: "install-cni",
+				Message: "the container runs privileged",
+			},
+			{
+				Check: compliance.CheckHostPathMount, Severity: compliance.SeverityMedium, Kind: "DaemonSet",
+				Namespace: "calico-system", Name: "calico-node", Container: "calico-node",
+				Message: "the container mounts the host path /var/run on /host/var/run",
+			},
+			{
+				Check: compliance.CheckTLSMinVersion, Severity: compliance.SeverityHigh, Kind: "DaemonSet",
+				Namespace: "calico-system", Name: "calico-node", Container: "calico-node",
+				Message: "the environment variable ES_GATEWAY_TLS_MIN_VERSION accepts VersionTLS11, older than TLS 1.2",
+			},
+			{
+				Check: compliance.CheckTLSMinVersion, Severity: compliance.SeverityHigh, Kind: "DaemonSet",
+				Namespace: "calico-system", Name: "calico-node", Container: "calico-node",
+				Message: "the argument --tls-min-version=VersionTLS10 accepts a version older than TLS 1.2",
+			},
+		}))
+	})
+
+	It("has no findings for hardened containers and for the objects which don't template pods", func() {
+		d := &apps.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-manager", Namespace: "tigera-manager"},
+			Spec: apps.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+				Containers: []v1.Container{{
+					Name:            "tigera-manager",
+					SecurityContext: &v1.SecurityContext{Privileged: ptr.BoolToPtr(false)},
+					Env:             []v1.EnvVar{{Name: "ES_GATEWAY_TLS_MIN_VERSION", Value: "VersionTLS12"}},
+				}},
+			}}},
+		}
+		cm := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "tigera-manager", Namespace: "tigera-manager"}}
+
+		Expect(compliance.Evaluate([]client.Object{d, cm})).To(BeEmpty())
+	})
+
+	It("reports the TLS cipher suites which aren't approved by FIPS", func() {
+		d := &apps.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-manager", Namespace: "tigera-manager"},
+			Spec: apps.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+				Containers: []v1.Container{{
+					Name: "tigera-manager",
+					Env: []v1.EnvVar{
+						{Name: "VOLTRON_TLS_CIPHER_SUITES", Value: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305"},
+						{Name: "ES_PROXY_TLS_CIPHER_SUITES", Value: "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+					},
+					Args: []string{"--tls-cipher-suites=TLS_RSA_WITH_RC4_128_SHA,TLS_AES_128_GCM_SHA256"},
+				}},
+			}}},
+		}
+
+		Expect(compliance.Evaluate([]client.Object{d})).To(Equal([]compliance.Finding{
+			{
+				Check: compliance.CheckTLSCipherSuites, Severity: compliance.SeverityHigh, Kind: "Deployment",
+				Namespace: "tigera-manager", Name: "tigera-manager", Container: "tigera-manager",
+				Message: "the environment variable VOLTRON_TLS_CIPHER_SUITES accepts TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305, not approved by FIPS",
+			},
+			{
+				Check: compliance.CheckTLSCipherSuites, Severity: compliance.SeverityHigh, Kind: "Deployment",
+				Namespace: "tigera-manager", Name: "tigera-manager", Container: "tigera-manager",
+				Message: "the argument --tls-cipher-suites accepts TLS_RSA_WITH_RC4_128_SHA, not approved by FIPS",
+			},
+		}))
+	})
+})
@@ -44,6 +44,16 @@ var (
 		Image:   "calico/pod2daemon-flexvol",
 	}
 
+	ComponentCSI = component{
+		Version: "master",
+		Image:   "calico/csi",
+	}
+
+	ComponentCSINodeDriverRegistrar = component{
+		Version: "master",
+		Image:   "calico/node-driver-registrar",
+	}
+
 	ComponentCalicoAPIServer = component{
 		Version: "master",
 		Image:   "calico/apiserver",
@@ -53,6 +63,31 @@ var (
 		Version: "master",
 		Image:   "calico/windows-upgrade",
 	}
+
+	ComponentCalicoGoldmane = component{
+		Version: "master",
+		Image:   "calico/goldmane",
+	}
+
+	ComponentCalicoWhisker = component{
+		Version: "master",
+		Image:   "calico/whisker",
+	}
+
+	ComponentCalicoWhiskerBackend = component{
+		Version: "master",
+		Image:   "calico/whisker-backend",
+	}
+
+	ComponentCalicoVPP = component{
+		Version: "master",
+		Image:   "calicovpp/vpp",
+	}
+
+	ComponentCalicoVPPAgent = component{
+		Version: "master",
+		Image:   "calicovpp/agent",
+	}
 	ComponentOperatorInit = component{
 		Version: version.VERSION,
 		Image:   "tigera/operator",
@@ -64,8 +99,15 @@ var (
 		ComponentCalicoNode,
 		ComponentCalicoTypha,
 		ComponentFlexVolume,
+		ComponentCSI,
+		ComponentCSINodeDriverRegistrar,
 		ComponentOperatorInit,
 		ComponentCalicoAPIServer,
 		ComponentWindows,
+		ComponentCalicoGoldmane,
+		ComponentCalicoWhisker,
+		ComponentCalicoWhiskerBackend,
+		ComponentCalicoVPP,
+		ComponentCalicoVPPAgent,
 	}
 )
@@ -162,6 +162,11 @@ var (
 		Image:   "tigera/alertmanager",
 	}
 
+	ComponentThanos = component{
+		Version: "v0.23.1",
+		Image:   "tigera/thanos",
+	}
+
 	ComponentQueryServer = component{
 		Version: "master",
 		Image:   "tigera/cnx-queryserver",
@@ -230,6 +235,7 @@ var (
 		ComponentPrometheus,
 		ComponentTigeraPrometheusService,
 		ComponentPrometheusAlertmanager,
+		ComponentThanos,
 		ComponentQueryServer,
 		ComponentTigeraKubeControllers,
 		ComponentTigeraNode,
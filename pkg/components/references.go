@@ -39,8 +39,15 @@ func GetReference(c component, registry, imagePath, imagePrefix string, is *oper
 			ComponentCalicoTypha,
 			ComponentCalicoKubeControllers,
 			ComponentFlexVolume,
+			ComponentCSI,
+			ComponentCSINodeDriverRegistrar,
 			ComponentCalicoAPIServer,
-			ComponentWindows:
+			ComponentWindows,
+			ComponentCalicoGoldmane,
+			ComponentCalicoWhisker,
+			ComponentCalicoWhiskerBackend,
+			ComponentCalicoVPP,
+			ComponentCalicoVPPAgent:
 
 			registry = CalicoRegistry
 		case ComponentElasticsearchOperator:
@@ -49,7 +56,8 @@ func GetReference(c component, registry, imagePath, imagePrefix string, is *oper
 			ComponentCSRInitContainer:
 			registry = InitRegistry
 		case ComponentPrometheus,
-			ComponentPrometheusAlertmanager:
+			ComponentPrometheusAlertmanager,
+			ComponentThanos:
 			registry = PrometheusRegistry
 		default:
 			registry = TigeraRegistry
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package connectivitytest implements the dataplane connectivity test run by the operator. The operator image runs
+// both the server pods of the test and the client pod of the test Job.
+package connectivitytest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	// The environment variables configuring the test, set by the operator on the pods of the test.
+	ServiceEnv          = "CONNECTIVITY_TEST_SERVICE"
+	PodsServiceEnv      = "CONNECTIVITY_TEST_PODS_SERVICE"
+	PortEnv             = "CONNECTIVITY_TEST_PORT"
+	ExternalEndpointEnv = "CONNECTIVITY_TEST_EXTERNAL_ENDPOINT"
+
+	defaultTimeout = 5 * time.Second
+)
+
+// Config is the configuration of the checks.
+type Config struct {
+	// Service is the name of the Service of the server pods.
+	Service string
+	// PodsService is the name of the headless Service of the server pods, which resolves to their addresses.
+	PodsService string
+	// Port is the port of the server pods and of their Services.
+	Port string
+	// ExternalEndpoint is the host:port of an endpoint outside of the cluster. It is not checked when empty.
+	ExternalEndpoint string
+	// Timeout is the timeout of each check.
+	Timeout time.Duration
+}
+
+// ConfigFromEnv returns the configuration set in the environment by the operator.
+func ConfigFromEnv() Config {
+	return Config{
+		Service:          os.Getenv(ServiceEnv),
+		PodsService:      os.Getenv(PodsServiceEnv),
+		Port:             os.Getenv(PortEnv),
+		ExternalEndpoint: os.Getenv(ExternalEndpointEnv),
+		Timeout:          defaultTimeout,
+	}
+}
+
+// Handler returns the handler of the server pods.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// Serve serves the connections of the test on the given port until the context is done.
+func Serve(ctx context.Context, port string) error {
+	srv := &http.Server{Addr: net.JoinHostPort("", port), Handler: Handler()}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Run runs the checks and returns the failures: the resolution of the name of the headless Service, the connections
+// to each server pod and to the Service, and the connection to the external endpoint.
+func Run(ctx context.Context, cfg Config) []string {
+	var failures []string
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	addrs, err := net.DefaultResolver.LookupHost(lookupCtx, cfg.PodsService)
+	cancel()
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("DNS: %v", err))
+	}
+	for _, addr := range addrs {
+		if err := get(ctx, client, net.JoinHostPort(addr, cfg.Port)); err != nil {
+			failures = append(failures, fmt.Sprintf("pod-to-pod: %v", err))
+		}
+	}
+
+	if err := get(ctx, client, net.JoinHostPort(cfg.Service, cfg.Port)); err != nil {
+		failures = append(failures, fmt.Sprintf("pod-to-service: %v", err))
+	}
+
+	if cfg.ExternalEndpoint != "" {
+		d := net.Dialer{Timeout: cfg.Timeout}
+		conn, err := d.DialContext(ctx, "tcp", cfg.ExternalEndpoint)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("external egress: %v", err))
+		} else {
+			_ = conn.Close()
+		}
+	}
+	return failures
+}
+
+func get(ctx context.Context, client *http.Client, hostPort string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+hostPort+"/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %s", hostPort, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connectivitytest_test
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/connectivitytest"
+)
+
+var _ = Describe("Connectivity test", func() {
+	var srv *httptest.Server
+	var cfg connectivitytest.Config
+
+	BeforeEach(func() {
+		srv = httptest.NewServer(connectivitytest.Handler())
+		host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+		Expect(err).NotTo(HaveOccurred())
+		cfg = connectivitytest.Config{
+			Service:     host,
+			PodsService: host,
+			Port:        port,
+			Timeout:     time.Second,
+		}
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("should pass the checks when the servers are reachable", func() {
+		cfg.ExternalEndpoint = srv.Listener.Addr().String()
+		Expect(connectivitytest.Run(context.Background(), cfg)).To(BeEmpty())
+	})
+
+	It("should report the failed checks", func() {
+		srv.Close()
+		failures := connectivitytest.Run(context.Background(), cfg)
+		Expect(failures).To(HaveLen(2))
+		Expect(failures[0]).To(HavePrefix("pod-to-pod: "))
+		Expect(failures[1]).To(HavePrefix("pod-to-service: "))
+	})
+})
@@ -68,7 +68,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) reconcile.Recon
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("amazoncloudintegration-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("amazoncloudintegration-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("amazoncloudintegration-controller", r)})
 	if err != nil {
 		return fmt.Errorf("Failed to create amazoncloudintegration-controller: %v", err)
 	}
@@ -182,6 +182,7 @@ func (r *ReconcileAmazonCloudIntegration) Reconcile(ctx context.Context, request
 
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetHardening(network.Hardening)
 
 	// Render the desired objects from the CRD and create or update them.
 	reqLogger.V(3).Info("rendering components")
@@ -190,7 +191,6 @@ func (r *ReconcileAmazonCloudIntegration) Reconcile(ctx context.Context, request
 		Installation:           network,
 		Credentials:            awsCredential,
 		PullSecrets:            pullSecrets,
-		Openshift:              r.provider == operatorv1.ProviderOpenShift,
 	}
 	component, err := render.AmazonCloudIntegration(amazonCloudIntegrationCfg)
 	if err != nil {
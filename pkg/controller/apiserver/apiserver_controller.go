@@ -17,14 +17,20 @@ package apiserver
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/openshift/library-go/pkg/crypto"
+
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/dns"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -45,6 +51,9 @@ import (
 
 var log = logf.Log.WithName("controller_apiserver")
 
+// certificateIssuerAnnotation records on the API server TLS Secret the certificate issuer its certificate was issued for.
+const certificateIssuerAnnotation = "operator.tigera.io/certificate-issuer"
+
 // Add creates a new APIServer Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, opts options.AddOptions) error {
@@ -61,6 +70,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) *ReconcileAPISe
 		enterpriseCRDsExist: opts.EnterpriseCRDExists,
 		status:              status.New(mgr.GetClient(), "apiserver", opts.KubernetesVersion),
 		clusterDomain:       opts.ClusterDomain,
+		kubernetesVersion:   opts.KubernetesVersion,
 	}
 	r.status.Run(opts.ShutdownContext)
 	return r
@@ -69,7 +79,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) *ReconcileAPISe
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r *ReconcileAPIServer) error {
 	// Create a new controller
-	c, err := controller.New("apiserver-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("apiserver-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("apiserver-controller", r)})
 	if err != nil {
 		return fmt.Errorf("Failed to create apiserver-controller: %v", err)
 	}
@@ -81,15 +91,19 @@ func add(mgr manager.Manager, r *ReconcileAPIServer) error {
 		return fmt.Errorf("apiserver-controller failed to watch primary resource: %v", err)
 	}
 
-	if err = utils.AddNetworkWatch(c); err != nil {
-		log.V(5).Info("Failed to create network watch", "err", err)
-		return fmt.Errorf("apiserver-controller failed to watch Tigera network resource: %v", err)
+	if err = utils.AddCRDependencyWatches(c, utils.APIServerCR); err != nil {
+		return fmt.Errorf("apiserver-controller failed to watch the CRs it depends on: %w", err)
 	}
 
 	if err = utils.AddConfigMapWatch(c, render.K8sSvcEndpointConfigMapName, common.OperatorNamespace()); err != nil {
 		return fmt.Errorf("apiserver-controller failed to watch ConfigMap %s: %w", render.K8sSvcEndpointConfigMapName, err)
 	}
 
+	// Re-render the PodSecurityPolicies when the cluster is upgraded.
+	if err = utils.AddKubernetesVersionWatch(c, r.kubernetesVersion, utils.DefaultTSEEInstanceKey); err != nil {
+		return fmt.Errorf("apiserver-controller failed to watch the Kubernetes version: %w", err)
+	}
+
 	if r.amazonCRDExists {
 		err = c.Watch(&source.Kind{Type: &operatorv1.AmazonCloudIntegration{}}, &handler.EnqueueRequestForObject{})
 		if err != nil {
@@ -136,6 +150,14 @@ func add(mgr manager.Manager, r *ReconcileAPIServer) error {
 		return fmt.Errorf("apiserver-controller failed to watch the Secret resource: %v", err)
 	}
 
+	// The audit policy ConfigMap and the audit webhook Secret are referenced by name from the APIServer CR.
+	if err = utils.AddConfigMapWatch(c, "", common.OperatorNamespace()); err != nil {
+		return fmt.Errorf("apiserver-controller failed to watch ConfigMaps: %v", err)
+	}
+	if err = utils.AddSecretsWatch(c, "", common.OperatorNamespace()); err != nil {
+		return fmt.Errorf("apiserver-controller failed to watch Secrets: %v", err)
+	}
+
 	if err = imageset.AddImageSetWatch(c); err != nil {
 		return fmt.Errorf("apiserver-controller failed to watch ImageSet: %w", err)
 	}
@@ -158,6 +180,8 @@ type ReconcileAPIServer struct {
 	enterpriseCRDsExist bool
 	status              status.StatusManager
 	clusterDomain       string
+	// kubernetesVersion is the version of the cluster, which the PodSecurityPolicies depend on.
+	kubernetesVersion *common.KubernetesVersionTracker
 }
 
 // Reconcile reads that state of the cluster for a APIServer object and makes changes based on the state read
@@ -183,27 +207,95 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	r.status.OnCRFound()
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 
+	if result, unmet, err := utils.CheckDependencies(ctx, r.client, reqLogger, r.status,
+		utils.CRDependenciesReady(utils.APIServerCR),
+	); unmet {
+		return result, err
+	}
+
 	// Query for the installation object.
 	variant, network, err := utils.GetInstallation(context.Background(), r.client)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			r.status.SetDegraded("Installation not found", err.Error())
-			return reconcile.Result{}, err
-		}
 		r.status.SetDegraded("Error querying installation", err.Error())
 		return reconcile.Result{}, err
 	}
-	if variant == "" {
-		r.status.SetDegraded(fmt.Sprintf("Waiting for Installation to be ready"), "")
+	ns := rmeta.APIServerNamespace(variant)
+
+	if pdb := instance.Spec.PodDisruptionBudget; pdb != nil && pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		r.status.SetDegraded("Only one of minAvailable and maxUnavailable can be set for the PodDisruptionBudget", "")
+		return reconcile.Result{}, nil
+	}
+
+	if as := instance.Spec.Autoscaling; as != nil && as.MinReplicas != nil && *as.MinReplicas > as.MaxReplicas {
+		r.status.SetDegraded(fmt.Sprintf("Autoscaling minReplicas %d is greater than maxReplicas %d", *as.MinReplicas, as.MaxReplicas), "")
+		return reconcile.Result{}, nil
+	}
+
+	// The open-source API server is rendered for the Calico variant, which lacks the audit logging and the query server.
+	if variant != operatorv1.TigeraSecureEnterprise {
+		if instance.Spec.Audit != nil {
+			r.status.SetDegraded(fmt.Sprintf("Audit logging of the API server is only supported for variant %s", operatorv1.TigeraSecureEnterprise), "")
+			return reconcile.Result{}, nil
+		}
+		if instance.Spec.QueryServerLogLevel != nil {
+			r.status.SetDegraded(fmt.Sprintf("The query server is only supported for variant %s", operatorv1.TigeraSecureEnterprise), "")
+			return reconcile.Result{}, nil
+		}
+		for _, cr := range instance.Spec.ComponentResources {
+			if cr.ComponentName == operatorv1.ComponentNameQueryServer {
+				r.status.SetDegraded(fmt.Sprintf("The query server is only supported for variant %s", operatorv1.TigeraSecureEnterprise), "")
+				return reconcile.Result{}, nil
+			}
+		}
+		if baselinePolicyEnabled(instance) {
+			r.status.SetDegraded(fmt.Sprintf("The baseline policy is only supported for variant %s", operatorv1.TigeraSecureEnterprise), "")
+			return reconcile.Result{}, nil
+		}
+	}
+
+	if err := validateNetworkSets(instance.Spec.NetworkSets); err != nil {
+		r.status.SetDegraded("Invalid network sets", err.Error())
+		return reconcile.Result{}, nil
+	}
+
+	if audit := instance.Spec.Audit; audit != nil && audit.Log != nil && audit.Log.Path != "" {
+		if err := validateAuditLogPath(audit.Log.Path); err != nil {
+			r.status.SetDegraded("Invalid audit log path", err.Error())
+			return reconcile.Result{}, nil
+		}
+	}
+
+	issuer := instance.Spec.CertificateIssuer
+	if issuer != nil && issuer.Type != operatorv1.CertificateIssuerSelfSigned && network.CertificateManagement != nil {
+		r.status.SetDegraded("A certificate issuer cannot be combined with the CertificateManagement of the Installation", "")
+		return reconcile.Result{}, nil
+	}
+	if issuer != nil && issuer.Type == operatorv1.CertificateIssuerCertManager && issuer.CertManagerIssuerRef == nil {
+		r.status.SetDegraded("The CertManager certificate issuer requires a certManagerIssuerRef", "")
 		return reconcile.Result{}, nil
 	}
-	ns := rmeta.APIServerNamespace(variant)
 
 	// We need separate certificates for OSS vs Enterprise.
 	secretName := render.ProjectCalicoApiServerTLSSecretName(network.Variant)
 	operatorManagedApiserverSecret := true
 	var tlsSecret *v1.Secret
-	if network.CertificateManagement == nil {
+	var caBundle []byte
+	if network.CertificateManagement == nil && issuer != nil && issuer.Type == operatorv1.CertificateIssuerCertManager {
+		r.status.RemoveCertificateSigningRequests(ns)
+
+		tlsSecret, caBundle, err = r.getCertManagerCertificate(ctx, instance, network.Variant)
+		if err != nil {
+			log.Error(err, "Error reading the certificate issued by cert-manager")
+			r.status.SetDegraded("Error reading the certificate issued by cert-manager", err.Error())
+			return reconcile.Result{}, err
+		}
+		if tlsSecret == nil {
+			r.status.SetDegraded("Waiting for cert-manager to issue the API server certificate", "")
+			return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		// The certificate is managed by cert-manager, so it is only copied to the API server namespace.
+		operatorManagedApiserverSecret = false
+	} else if network.CertificateManagement == nil {
 		// Check that if the apiserver cert pair secret exists that it is valid (has key and cert fields)
 		// If it does not exist then this function still returns true
 		tlsSecret, err = utils.ValidateCertPair(r.client,
@@ -220,16 +312,52 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 
 		r.status.RemoveCertificateSigningRequests(ns)
 
+		// The certificate issued by the operator for another issuer is re-issued, so that the CA bundle of the
+		// APIService is recomputed for the current one.
+		issuerType := operatorv1.CertificateIssuerSelfSigned
+		if issuer != nil {
+			issuerType = issuer.Type
+		}
+		if tlsSecret != nil && issuedFor(tlsSecret) != issuerType {
+			if operatorIssued, err := utils.IsCertOperatorIssued(tlsSecret.Data[render.APIServerSecretCertName]); err == nil && operatorIssued {
+				log.Info("Re-issuing the API server certificate for the new certificate issuer", "issuer", issuerType)
+				tlsSecret = nil
+			}
+		}
+
 		svcDNSNames := dns.GetServiceDNSNames(render.ProjectCalicoApiServerServiceName(network.Variant), rmeta.APIServerNamespace(network.Variant), r.clusterDomain)
-		tlsSecret, operatorManagedApiserverSecret, err = utils.EnsureCertificateSecret(
-			secretName, tlsSecret, render.APIServerSecretKeyName, render.APIServerSecretCertName, rmeta.DefaultCertificateDuration, svcDNSNames...,
-		)
+		if issuer != nil && issuer.Type == operatorv1.CertificateIssuerOperatorCA {
+			var ca *crypto.CA
+			var caSecret *v1.Secret
+			ca, caSecret, err = r.getOperatorCA(ctx)
+			if err != nil {
+				log.Error(err, "Error reading the operator CA")
+				r.status.SetDegraded("Error reading the operator CA", err.Error())
+				return reconcile.Result{}, err
+			}
+			tlsSecret, operatorManagedApiserverSecret, err = utils.EnsureCertificateSignedByCA(
+				ca, secretName, tlsSecret, render.APIServerSecretKeyName, render.APIServerSecretCertName, rmeta.DefaultCertificateDuration, nil, svcDNSNames...,
+			)
+			if operatorManagedApiserverSecret {
+				caBundle = caSecret.Data[utils.OperatorCACertName]
+			}
+		} else {
+			tlsSecret, operatorManagedApiserverSecret, err = utils.EnsureCertificateSecret(
+				secretName, tlsSecret, render.APIServerSecretKeyName, render.APIServerSecretCertName, rmeta.DefaultCertificateDuration, svcDNSNames...,
+			)
+		}
 
 		if err != nil {
 			log.Error(err, "Error ensuring TLS certificate exists and has valid DNS names")
 			r.status.SetDegraded("Error ensuring TLS certificate exists and has valid DNS names", err.Error())
 			return reconcile.Result{}, err
 		}
+		if operatorManagedApiserverSecret {
+			if tlsSecret.Annotations == nil {
+				tlsSecret.Annotations = map[string]string{}
+			}
+			tlsSecret.Annotations[certificateIssuerAnnotation] = string(issuerType)
+		}
 
 	} else {
 		// Monitor pending CSRs for the TigeraStatus
@@ -248,7 +376,16 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	var amazon *operatorv1.AmazonCloudIntegration
 	var managementCluster *operatorv1.ManagementCluster
 	var managementClusterConnection *operatorv1.ManagementClusterConnection
+	var auditPolicy *v1.ConfigMap
+	var auditWebhookSecret *v1.Secret
 	if variant == operatorv1.TigeraSecureEnterprise {
+		auditPolicy, auditWebhookSecret, err = r.getAuditConfig(ctx, instance.Spec.Audit)
+		if err != nil {
+			log.Error(err, "Error reading the audit configuration")
+			r.status.SetDegraded("Error reading the audit configuration", err.Error())
+			return reconcile.Result{}, err
+		}
+
 		managementCluster, err = utils.GetManagementCluster(ctx, r.client)
 		if err != nil {
 			log.Error(err, "Error reading ManagementCluster")
@@ -308,6 +445,7 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	}
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetHardening(network.Hardening)
 
 	// Render the desired objects from the CRD and create or update them.
 	reqLogger.V(3).Info("rendering components")
@@ -315,15 +453,27 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	apiServerCfg := render.APIServerConfiguration{
 		K8SServiceEndpoint:          k8sapi.Endpoint,
 		Installation:                network,
-		ForceHostNetwork:            false,
+		ForceHostNetwork:            instance.Spec.HostNetwork != nil && *instance.Spec.HostNetwork == operatorv1.APIServerHostNetworkEnabled,
 		ManagementCluster:           managementCluster,
 		ManagementClusterConnection: managementClusterConnection,
 		AmazonCloudIntegration:      amazon,
 		TLSKeyPair:                  tlsSecret,
 		PullSecrets:                 pullSecrets,
-		Openshift:                   r.provider == operatorv1.ProviderOpenShift,
 		TunnelCASecret:              tunnelCASecret,
 		ClusterDomain:               r.clusterDomain,
+		Audit:                       instance.Spec.Audit,
+		AuditPolicy:                 auditPolicy,
+		AuditWebhookSecret:          auditWebhookSecret,
+		Replicas:                    instance.Spec.Replicas,
+		Autoscaling:                 instance.Spec.Autoscaling,
+		DisabledResources:           instance.Spec.DisabledResources,
+		RequestHandling:             instance.Spec.RequestHandling,
+		ComponentResources:          instance.Spec.ComponentResources,
+		KubernetesVersion:           r.kubernetesVersion.Get(),
+		QueryServerLogLevel:         instance.Spec.QueryServerLogLevel,
+		TopologySpreadConstraints:   instance.Spec.TopologySpreadConstraints,
+		PodDisruptionBudget:         instance.Spec.PodDisruptionBudget,
+		CABundle:                    caBundle,
 	}
 
 	component, err := render.APIServer(&apiServerCfg)
@@ -386,7 +536,6 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 
 		packetCaptureApiCfg := &render.PacketCaptureApiConfiguration{
 			PullSecrets:        pullSecrets,
-			Openshift:          r.provider == operatorv1.ProviderOpenShift,
 			Installation:       network,
 			KeyValidatorConfig: keyValidatorConfig,
 			ServerCertSecret:   packetCaptureCertSecret,
@@ -417,6 +566,22 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	// The baseline policies are served by the API server, so they can only be created once it is available.
+	if baselinePolicyEnabled(instance) {
+		if err = r.bootstrapBaselinePolicy(ctx, instance); err != nil {
+			log.Error(err, "Error bootstrapping the baseline policy")
+			r.status.SetDegraded("Error bootstrapping the baseline policy", err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+
+	// The GlobalNetworkSets are served by the API server as well.
+	if err = r.reconcileNetworkSets(ctx, instance, handler); err != nil {
+		log.Error(err, "Error reconciling the network sets")
+		r.status.SetDegraded("Error reconciling the network sets", err.Error())
+		return reconcile.Result{}, err
+	}
+
 	// Everything is available - update the CRD status.
 	instance.Status.State = operatorv1.TigeraStatusReady
 	if err = r.client.Status().Update(ctx, instance); err != nil {
@@ -424,3 +589,120 @@ func (r *ReconcileAPIServer) Reconcile(ctx context.Context, request reconcile.Re
 	}
 	return reconcile.Result{}, nil
 }
+
+// getAuditConfig reads the audit policy ConfigMap and the audit webhook Secret referenced by the given audit
+// configuration from the operator namespace. A nil value is returned for each of them that is not configured.
+func (r *ReconcileAPIServer) getAuditConfig(ctx context.Context, audit *operatorv1.APIServerAudit) (*v1.ConfigMap, *v1.Secret, error) {
+	if audit == nil {
+		return nil, nil, nil
+	}
+
+	var policy *v1.ConfigMap
+	if audit.PolicyConfigMapName != "" {
+		policy = &v1.ConfigMap{}
+		key := types.NamespacedName{Name: audit.PolicyConfigMapName, Namespace: common.OperatorNamespace()}
+		if err := r.client.Get(ctx, key, policy); err != nil {
+			return nil, nil, fmt.Errorf("failed to read audit policy ConfigMap %q: %w", audit.PolicyConfigMapName, err)
+		}
+		if _, ok := policy.Data[render.APIServerAuditPolicyKey]; !ok {
+			return nil, nil, fmt.Errorf("audit policy ConfigMap %q does not have a field named %q", audit.PolicyConfigMapName, render.APIServerAuditPolicyKey)
+		}
+	}
+
+	var webhookSecret *v1.Secret
+	if audit.Webhook != nil && audit.Webhook.ConfigSecretName != "" {
+		var err error
+		webhookSecret, err = utils.GetSecret(ctx, r.client, audit.Webhook.ConfigSecretName, common.OperatorNamespace())
+		if err != nil {
+			return nil, nil, err
+		}
+		if webhookSecret == nil {
+			return nil, nil, fmt.Errorf("audit webhook Secret %q not found", audit.Webhook.ConfigSecretName)
+		}
+		if _, ok := webhookSecret.Data[render.APIServerAuditWebhookConfigKey]; !ok {
+			return nil, nil, fmt.Errorf("audit webhook Secret %q does not have a field named %q", audit.Webhook.ConfigSecretName, render.APIServerAuditWebhookConfigKey)
+		}
+	}
+
+	return policy, webhookSecret, nil
+}
+
+// auditLogSystemDirs are the system directories of the hosts, which can't hold the audit log file since its directory
+// is mounted read-write in the API server pods. Their subdirectories are rejected too, except for the ones of /var.
+var auditLogSystemDirs = []string{"/bin", "/boot", "/dev", "/etc", "/lib", "/lib64", "/proc", "/root", "/run", "/sbin", "/sys", "/usr", "/var/lib", "/var/run"}
+
+// validateAuditLogPath returns an error if the directory of the audit log file, which is mounted from the host, is the
+// root, a top-level directory, /var/log or a system directory.
+func validateAuditLogPath(path string) error {
+	dir := filepath.Dir(filepath.Clean(path))
+	if dir == "/" || filepath.Dir(dir) == "/" || dir == "/var/log" {
+		return fmt.Errorf("spec.audit.log.path %q would mount the host directory %s in the API server pods, which should be a dedicated directory, e.g. /var/log/calico/audit", path, dir)
+	}
+	for _, sys := range auditLogSystemDirs {
+		if dir == sys || strings.HasPrefix(dir, sys+"/") {
+			return fmt.Errorf("spec.audit.log.path %q is in the system directory %s of the hosts", path, sys)
+		}
+	}
+	return nil
+}
+
+// issuedFor returns the certificate issuer the operator issued the certificate of the Secret for. The certificates
+// issued before the issuer was recorded were self-signed.
+func issuedFor(secret *v1.Secret) operatorv1.CertificateIssuerType {
+	if issuer, ok := secret.Annotations[certificateIssuerAnnotation]; ok {
+		return operatorv1.CertificateIssuerType(issuer)
+	}
+	return operatorv1.CertificateIssuerSelfSigned
+}
+
+// getOperatorCA returns the CA the operator signs certificates with along with the Secret holding it. A newly generated
+// CA is stored right away, without an owner, so that it outlives the APIServer and keeps signing the same certificates.
+func (r *ReconcileAPIServer) getOperatorCA(ctx context.Context) (*crypto.CA, *v1.Secret, error) {
+	ca, caSecret, err := utils.GetOrCreateOperatorCA(ctx, r.client)
+	if err != nil {
+		return nil, nil, err
+	}
+	if caSecret.ResourceVersion == "" {
+		hdler := utils.NewComponentHandler(log, r.client, r.scheme, nil)
+		if err := hdler.CreateOrUpdateOrDelete(ctx, render.NewPassthrough(caSecret), nil); err != nil {
+			return nil, nil, err
+		}
+	}
+	return ca, caSecret, nil
+}
+
+// getCertManagerCertificate creates the cert-manager Certificate of the API server and returns the serving certificate
+// issued for it, converted to the keys expected by the API server, along with the CA bundle of its issuer. A nil Secret
+// is returned while cert-manager has not issued the certificate yet.
+func (r *ReconcileAPIServer) getCertManagerCertificate(ctx context.Context, instance *operatorv1.APIServer, variant operatorv1.ProductVariant) (*v1.Secret, []byte, error) {
+	cert := render.APIServerCertManagerCertificate(variant, instance.Spec.CertificateIssuer.CertManagerIssuerRef, r.clusterDomain)
+	hdler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	if err := hdler.CreateOrUpdateOrDelete(ctx, render.NewPassthrough(cert), nil); err != nil {
+		return nil, nil, err
+	}
+
+	issued, err := utils.GetSecret(ctx, r.client, render.APIServerCertManagerSecretName(variant), common.OperatorNamespace())
+	if err != nil || issued == nil {
+		return nil, nil, err
+	}
+	if len(issued.Data[v1.TLSCertKey]) == 0 || len(issued.Data[v1.TLSPrivateKeyKey]) == 0 {
+		return nil, nil, nil
+	}
+
+	caBundle := issued.Data["ca.crt"]
+	if len(caBundle) == 0 {
+		// Not all issuers share their CA, in which case the issued certificate itself is trusted.
+		caBundle = issued.Data[v1.TLSCertKey]
+	}
+	return &v1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      render.ProjectCalicoApiServerTLSSecretName(variant),
+			Namespace: common.OperatorNamespace(),
+		},
+		Data: map[string][]byte{
+			render.APIServerSecretKeyName:  issued.Data[v1.TLSPrivateKeyKey],
+			render.APIServerSecretCertName: issued.Data[v1.TLSCertKey],
+		},
+	}, caBundle, nil
+}
@@ -16,18 +16,22 @@ package apiserver
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/stretchr/testify/mock"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/render"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
@@ -35,10 +39,14 @@ import (
 	"github.com/tigera/operator/test"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	apiregv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -60,6 +68,8 @@ var _ = Describe("apiserver controller tests", func() {
 		Expect(apis.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 		Expect(appsv1.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 		Expect(rbacv1.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(autoscalingv1.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(netv1.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 
 		ctx = context.Background()
 		cli = fake.NewFakeClientWithScheme(scheme)
@@ -70,6 +80,8 @@ var _ = Describe("apiserver controller tests", func() {
 		mockStatus.On("AddDeployments", mock.Anything).Return()
 		mockStatus.On("AddStatefulSets", mock.Anything).Return()
 		mockStatus.On("AddCronJobs", mock.Anything)
+		mockStatus.On("AddJobs", mock.Anything)
+		mockStatus.On("IsRolloutPaused").Return(false)
 		mockStatus.On("IsAvailable").Return(true)
 		mockStatus.On("OnCRFound").Return()
 		mockStatus.On("ClearDegraded")
@@ -298,6 +310,263 @@ var _ = Describe("apiserver controller tests", func() {
 			Expect(cli.Get(ctx, client.ObjectKey{Namespace: common.OperatorNamespace(), Name: render.PacketCaptureCertSecret}, secret)).ShouldNot(HaveOccurred())
 			Expect(secret.GetOwnerReferences()).To(HaveLen(1))
 		})
+
+		It("should sign the apiserver TLS cert with the operator CA", func() {
+			setUpApiServerInstallation(cli, ctx, variant, nil)
+			instance := &operatorv1.APIServer{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).ShouldNot(HaveOccurred())
+			instance.Spec.CertificateIssuer = &operatorv1.APIServerCertificateIssuer{Type: operatorv1.CertificateIssuerOperatorCA}
+			Expect(cli.Update(ctx, instance)).ShouldNot(HaveOccurred())
+
+			r := ReconcileAPIServer{
+				client:   cli,
+				scheme:   scheme,
+				provider: operatorv1.ProviderNone,
+				status:   mockStatus,
+			}
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ca := &v1.Secret{}
+			Expect(cli.Get(ctx, client.ObjectKey{Namespace: common.OperatorNamespace(), Name: utils.OperatorCASecretName}, ca)).ShouldNot(HaveOccurred())
+			Expect(ca.GetOwnerReferences()).To(BeEmpty())
+
+			tlsSecret := &v1.Secret{}
+			Expect(cli.Get(ctx, client.ObjectKey{Namespace: common.OperatorNamespace(), Name: "tigera-apiserver-certs"}, tlsSecret)).ShouldNot(HaveOccurred())
+			caBlock, _ := pem.Decode(ca.Data[utils.OperatorCACertName])
+			caCert, err := x509.ParseCertificate(caBlock.Bytes)
+			Expect(err).ShouldNot(HaveOccurred())
+			certBlock, _ := pem.Decode(tlsSecret.Data[render.APIServerSecretCertName])
+			cert, err := x509.ParseCertificate(certBlock.Bytes)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cert.CheckSignatureFrom(caCert)).ShouldNot(HaveOccurred())
+
+			apiService := &apiregv1.APIService{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "v3.projectcalico.org"}, apiService)).ShouldNot(HaveOccurred())
+			Expect(apiService.Spec.CABundle).To(Equal(ca.Data[utils.OperatorCACertName]))
+
+			// The certificate is kept across reconciles.
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			reconciled := &v1.Secret{}
+			Expect(cli.Get(ctx, client.ObjectKey{Namespace: common.OperatorNamespace(), Name: "tigera-apiserver-certs"}, reconciled)).ShouldNot(HaveOccurred())
+			Expect(reconciled.Data).To(Equal(tlsSecret.Data))
+
+			// Switching back to a self-signed certificate re-issues it, along with the CA bundle of the APIService.
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).ShouldNot(HaveOccurred())
+			instance.Spec.CertificateIssuer = nil
+			Expect(cli.Update(ctx, instance)).ShouldNot(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cli.Get(ctx, client.ObjectKey{Namespace: common.OperatorNamespace(), Name: "tigera-apiserver-certs"}, reconciled)).ShouldNot(HaveOccurred())
+			Expect(reconciled.Data).NotTo(Equal(tlsSecret.Data))
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "v3.projectcalico.org"}, apiService)).ShouldNot(HaveOccurred())
+			Expect(apiService.Spec.CABundle).To(Equal(reconciled.Data[render.APIServerSecretCertName]))
+		})
+
+		It("should render the open-source API server for the Calico variant", func() {
+			setUpApiServerInstallation(cli, ctx, operatorv1.Calico, nil)
+
+			r := ReconcileAPIServer{
+				client:   cli,
+				scheme:   scheme,
+				provider: operatorv1.ProviderNone,
+				status:   mockStatus,
+			}
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			d := appsv1.Deployment{
+				TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "calico-apiserver",
+					Namespace: "calico-apiserver",
+				},
+			}
+			Expect(test.GetResource(cli, &d)).To(BeNil())
+			Expect(d.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(d.Spec.Template.Spec.Containers[0].Image).To(Equal(
+				fmt.Sprintf("some.registry.org/%s:%s",
+					components.ComponentCalicoAPIServer.Image,
+					components.ComponentCalicoAPIServer.Version)))
+
+			apiService := &apiregv1.APIService{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "v3.projectcalico.org"}, apiService)).ShouldNot(HaveOccurred())
+			Expect(apiService.Spec.Service.Namespace).To(Equal("calico-apiserver"))
+
+			// The enterprise-only packet capture API is not rendered.
+			pcDeployment := &appsv1.Deployment{}
+			err = cli.Get(ctx, client.ObjectKey{Namespace: render.PacketCaptureNamespace, Name: render.PacketCaptureName}, pcDeployment)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should degrade when enterprise-only options are set for the Calico variant", func() {
+			setUpApiServerInstallation(cli, ctx, operatorv1.Calico, nil)
+			instance := &operatorv1.APIServer{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).ShouldNot(HaveOccurred())
+			instance.Spec.Audit = &operatorv1.APIServerAudit{}
+			Expect(cli.Update(ctx, instance)).ShouldNot(HaveOccurred())
+			mockStatus.On("SetDegraded", "Audit logging of the API server is only supported for variant TigeraSecureEnterprise", "").Return()
+
+			r := ReconcileAPIServer{
+				client:   cli,
+				scheme:   scheme,
+				provider: operatorv1.ProviderNone,
+				status:   mockStatus,
+			}
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			mockStatus.AssertExpectations(GinkgoT())
+
+			d := &appsv1.Deployment{}
+			err = cli.Get(ctx, client.ObjectKey{Namespace: "calico-apiserver", Name: "calico-apiserver"}, d)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should degrade when the resources of the query server are set for the Calico variant", func() {
+			setUpApiServerInstallation(cli, ctx, operatorv1.Calico, nil)
+			instance := &operatorv1.APIServer{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).ShouldNot(HaveOccurred())
+			instance.Spec.ComponentResources = []operatorv1.APIServerComponentResource{{
+				ComponentName:        operatorv1.ComponentNameQueryServer,
+				ResourceRequirements: &v1.ResourceRequirements{},
+			}}
+			Expect(cli.Update(ctx, instance)).ShouldNot(HaveOccurred())
+			mockStatus.On("SetDegraded", "The query server is only supported for variant TigeraSecureEnterprise", "").Return()
+
+			r := ReconcileAPIServer{
+				client:   cli,
+				scheme:   scheme,
+				provider: operatorv1.ProviderNone,
+				status:   mockStatus,
+			}
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			mockStatus.AssertExpectations(GinkgoT())
+
+			d := &appsv1.Deployment{}
+			err = cli.Get(ctx, client.ObjectKey{Namespace: "calico-apiserver", Name: "calico-apiserver"}, d)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should bootstrap the baseline policy once", func() {
+			setUpApiServerInstallation(cli, ctx, variant, &operatorv1.CertificateManagement{})
+			instance := &operatorv1.APIServer{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).ShouldNot(HaveOccurred())
+			enabled := operatorv1.BaselinePolicyEnabled
+			instance.Spec.BaselinePolicy = &enabled
+			Expect(cli.Update(ctx, instance)).ShouldNot(HaveOccurred())
+
+			r := ReconcileAPIServer{
+				client:   cli,
+				scheme:   scheme,
+				provider: operatorv1.ProviderNone,
+				status:   mockStatus,
+			}
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			tier := &v3.Tier{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: render.BaselinePolicyTierName}, tier)).ShouldNot(HaveOccurred())
+			Expect(tier.Annotations).NotTo(HaveKey(baselinePolicyPendingAnnotation))
+			for _, name := range []string{"platform.allow-kube-system", "platform.allow-kube-dns", "platform.allow-metrics", "platform.pass", "default-deny"} {
+				Expect(cli.Get(ctx, client.ObjectKey{Name: name}, &v3.GlobalNetworkPolicy{})).ShouldNot(HaveOccurred())
+			}
+
+			// The policies removed by the user are not recreated.
+			Expect(cli.Delete(ctx, &v3.GlobalNetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "default-deny"}})).ShouldNot(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			err = cli.Get(ctx, client.ObjectKey{Name: "default-deny"}, &v3.GlobalNetworkPolicy{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should only bootstrap the default deny of an upgraded cluster when it is enabled", func() {
+			setUpApiServerInstallation(cli, ctx, variant, &operatorv1.CertificateManagement{})
+			instance := &operatorv1.APIServer{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).ShouldNot(HaveOccurred())
+			enabled := operatorv1.BaselinePolicyEnabled
+			instance.Spec.BaselinePolicy = &enabled
+			Expect(cli.Update(ctx, instance)).ShouldNot(HaveOccurred())
+			instance.Status.State = operatorv1.TigeraStatusReady
+			Expect(cli.Status().Update(ctx, instance)).ShouldNot(HaveOccurred())
+
+			r := ReconcileAPIServer{
+				client:   cli,
+				scheme:   scheme,
+				provider: operatorv1.ProviderNone,
+				status:   mockStatus,
+			}
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "platform.pass"}, &v3.GlobalNetworkPolicy{})).ShouldNot(HaveOccurred())
+			err = cli.Get(ctx, client.ObjectKey{Name: "default-deny"}, &v3.GlobalNetworkPolicy{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			// The default deny is bootstrapped with the policies when it is enabled explicitly.
+			Expect(cli.Delete(ctx, &v3.Tier{ObjectMeta: metav1.ObjectMeta{Name: render.BaselinePolicyTierName}})).ShouldNot(HaveOccurred())
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).ShouldNot(HaveOccurred())
+			instance.Spec.BaselineDefaultDeny = &enabled
+			Expect(cli.Update(ctx, instance)).ShouldNot(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "default-deny"}, &v3.GlobalNetworkPolicy{})).ShouldNot(HaveOccurred())
+		})
+
+		It("should render the network sets and delete the removed ones", func() {
+			setUpApiServerInstallation(cli, ctx, variant, &operatorv1.CertificateManagement{})
+			instance := &operatorv1.APIServer{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).ShouldNot(HaveOccurred())
+			instance.Spec.NetworkSets = []operatorv1.NetworkSet{
+				{Name: "corporate", Nets: []string{"10.0.0.0/8"}},
+				{Name: "proxies", Nets: []string{"172.16.1.10/32"}},
+			}
+			Expect(cli.Update(ctx, instance)).ShouldNot(HaveOccurred())
+
+			r := ReconcileAPIServer{
+				client:   cli,
+				scheme:   scheme,
+				provider: operatorv1.ProviderNone,
+				status:   mockStatus,
+			}
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			for _, name := range []string{"corporate", "proxies"} {
+				Expect(cli.Get(ctx, client.ObjectKey{Name: name}, &v3.GlobalNetworkSet{})).ShouldNot(HaveOccurred())
+			}
+
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).ShouldNot(HaveOccurred())
+			instance.Spec.NetworkSets = instance.Spec.NetworkSets[:1]
+			Expect(cli.Update(ctx, instance)).ShouldNot(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "corporate"}, &v3.GlobalNetworkSet{})).ShouldNot(HaveOccurred())
+			err = cli.Get(ctx, client.ObjectKey{Name: "proxies"}, &v3.GlobalNetworkSet{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should reject the audit log paths in the root or the system directories of the hosts", func() {
+			Expect(validateAuditLogPath("/var/log/calico/audit/tsee-audit.log")).NotTo(HaveOccurred())
+			Expect(validateAuditLogPath("/var/log/audit/tsee-audit.log")).NotTo(HaveOccurred())
+			Expect(validateAuditLogPath("/data/audit/tsee-audit.log")).NotTo(HaveOccurred())
+			Expect(validateAuditLogPath("/audit.log")).To(MatchError(ContainSubstring("would mount the host directory /")))
+			Expect(validateAuditLogPath("/var/log/calico/../../../audit.log")).To(HaveOccurred())
+			Expect(validateAuditLogPath("/var/audit.log")).To(HaveOccurred())
+			Expect(validateAuditLogPath("/var/log/audit.log")).To(HaveOccurred())
+			Expect(validateAuditLogPath("/etc/kubernetes/audit.log")).To(MatchError(`spec.audit.log.path "/etc/kubernetes/audit.log" is in the system directory /etc of the hosts`))
+			Expect(validateAuditLogPath("/var/lib/kubelet/audit.log")).To(HaveOccurred())
+		})
+
+		It("should reject invalid network sets", func() {
+			Expect(validateNetworkSets([]operatorv1.NetworkSet{{Name: "corporate", Nets: []string{"10.0.0.0/8"}}})).NotTo(HaveOccurred())
+			Expect(validateNetworkSets([]operatorv1.NetworkSet{{Name: "Corporate", Nets: []string{"10.0.0.0/8"}}})).To(HaveOccurred())
+			Expect(validateNetworkSets([]operatorv1.NetworkSet{{Name: "corporate"}})).To(HaveOccurred())
+			Expect(validateNetworkSets([]operatorv1.NetworkSet{{Name: "corporate", Nets: []string{"10.0.0.1"}}})).To(HaveOccurred())
+			Expect(validateNetworkSets([]operatorv1.NetworkSet{
+				{Name: "corporate", Nets: []string{"10.0.0.0/8"}},
+				{Name: "corporate", Nets: []string{"192.168.0.0/16"}},
+			})).To(HaveOccurred())
+		})
 	})
 })
 
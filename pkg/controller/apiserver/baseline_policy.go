@@ -0,0 +1,88 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+func baselinePolicyEnabled(instance *operatorv1.APIServer) bool {
+	return instance.Spec.BaselinePolicy != nil && *instance.Spec.BaselinePolicy == operatorv1.BaselinePolicyEnabled
+}
+
+// baselineDefaultDeny returns whether the baseline policy set bootstrapped now includes the default deny. Unless it is
+// configured explicitly, it is only included on a new install, since it would cut the traffic of the workloads already
+// running on an upgraded cluster. The API server of a new install was never ready.
+func baselineDefaultDeny(instance *operatorv1.APIServer) bool {
+	if dd := instance.Spec.BaselineDefaultDeny; dd != nil {
+		return *dd == operatorv1.BaselinePolicyEnabled
+	}
+	return instance.Status.State == ""
+}
+
+// baselinePolicyPendingAnnotation is set on the platform tier until all the baseline policies are created, so that an
+// interrupted bootstrap is resumed. Its value records whether the default deny is part of the bootstrapped set, so that
+// the resumed bootstrap creates the same policies.
+const (
+	baselinePolicyPendingAnnotation  = "operator.tigera.io/baseline-policy-pending"
+	baselinePolicyPending            = "true"
+	baselinePolicyPendingDefaultDeny = "default-deny"
+)
+
+// bootstrapBaselinePolicy creates the baseline policies if the platform tier doesn't exist yet. Once they are created,
+// the policies belong to the user: they are neither updated nor recreated, so that they can be tuned or removed.
+func (r *ReconcileAPIServer) bootstrapBaselinePolicy(ctx context.Context, instance *operatorv1.APIServer) error {
+	pending := baselinePolicyPending
+	if baselineDefaultDeny(instance) {
+		pending = baselinePolicyPendingDefaultDeny
+	}
+
+	// The tier comes first, since the policies of a tier can only be created once it exists.
+	tier := &v3.Tier{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: render.BaselinePolicyTierName}, tier)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to read tier %q: %w", render.BaselinePolicyTierName, err)
+		}
+		tier = render.BaselinePolicy(false)[0].(*v3.Tier)
+		tier.Annotations = map[string]string{baselinePolicyPendingAnnotation: pending}
+		if err := r.client.Create(ctx, tier); err != nil {
+			return fmt.Errorf("failed to create tier %q: %w", render.BaselinePolicyTierName, err)
+		}
+	} else if pending = tier.Annotations[baselinePolicyPendingAnnotation]; pending == "" {
+		return nil
+	}
+
+	objs := render.BaselinePolicy(pending == baselinePolicyPendingDefaultDeny)
+	for _, obj := range objs[1:] {
+		if err := r.client.Create(ctx, obj); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create %s %q: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+	}
+
+	delete(tier.Annotations, baselinePolicyPendingAnnotation)
+	if err := r.client.Update(ctx, tier); err != nil {
+		return fmt.Errorf("failed to update tier %q: %w", render.BaselinePolicyTierName, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// validateNetworkSets returns an error if a network set has an invalid or duplicate name, or an invalid CIDR.
+func validateNetworkSets(sets []operatorv1.NetworkSet) error {
+	names := map[string]bool{}
+	for _, s := range sets {
+		if errs := validation.IsDNS1123Subdomain(s.Name); len(errs) > 0 {
+			return fmt.Errorf("network set name %q is invalid: %v", s.Name, errs)
+		}
+		if names[s.Name] {
+			return fmt.Errorf("network set %q is declared more than once", s.Name)
+		}
+		names[s.Name] = true
+		if len(s.Nets) == 0 {
+			return fmt.Errorf("network set %q has no nets", s.Name)
+		}
+		for _, n := range s.Nets {
+			if _, _, err := net.ParseCIDR(n); err != nil {
+				return fmt.Errorf("network set %q has an invalid CIDR %q", s.Name, n)
+			}
+		}
+	}
+	return nil
+}
+
+// reconcileNetworkSets renders the network sets of the APIServer as GlobalNetworkSets, and deletes the ones that were
+// rendered before and are not declared anymore.
+func (r *ReconcileAPIServer) reconcileNetworkSets(ctx context.Context, instance *operatorv1.APIServer, handler utils.ComponentHandler) error {
+	existing := &v3.GlobalNetworkSetList{}
+	if err := r.client.List(ctx, existing, client.HasLabels{render.NetworkSetLabel}); err != nil {
+		return fmt.Errorf("failed to list the GlobalNetworkSets: %w", err)
+	}
+	var names []string
+	for _, s := range existing.Items {
+		names = append(names, s.Name)
+	}
+	return handler.CreateOrUpdateOrDelete(ctx, render.NetworkSets(instance.Spec.NetworkSets, names), nil)
+}
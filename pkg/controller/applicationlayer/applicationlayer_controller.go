@@ -56,7 +56,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 
 	reconciler := newReconciler(mgr, opts, licenseAPIReady)
 
-	c, err := controller.New("applicationlayer-controller", mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
+	c, err := controller.New("applicationlayer-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("applicationlayer-controller", reconciler)})
 	if err != nil {
 		return err
 	}
@@ -67,7 +67,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return err
 	}
 
-	go utils.WaitToAddLicenseKeyWatch(c, k8sClient, log, licenseAPIReady)
+	go utils.WaitToAddLicenseKeyWatch(c, k8sClient, log, licenseAPIReady, opts.LicenseKeyCache)
 
 	return add(mgr, c)
 }
@@ -228,6 +228,7 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 	component := applicationlayer.ApplicationLayer(config)
 
 	ch := utils.NewComponentHandler(log, r.client, r.scheme, applicationLayer)
+	ch.SetHardening(installation.Hardening)
 
 	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
 		reqLogger.Error(err, "Error with images from ImageSet")
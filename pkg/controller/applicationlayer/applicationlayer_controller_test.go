@@ -68,6 +68,8 @@ var _ = Describe("Application layer controller tests", func() {
 			mockStatus.On("IsAvailable").Return(true)
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
+			mockStatus.On("AddJobs", mock.Anything)
+			mockStatus.On("IsRolloutPaused").Return(false)
 			mockStatus.On("OnCRFound").Return()
 			mockStatus.On("OnCRNotFound").Return()
 			mockStatus.On("ClearDegraded")
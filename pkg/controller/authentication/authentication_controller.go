@@ -75,7 +75,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) *ReconcileAuthe
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r *ReconcileAuthentication) error {
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: utils.FairReconciler(controllerName, r)})
 	if err != nil {
 		return fmt.Errorf("failed to create %s: %w", controllerName, err)
 	}
@@ -255,10 +255,10 @@ func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconci
 
 	// Create a component handler to manage the rendered component.
 	hlr := utils.NewComponentHandler(log, r.client, r.scheme, authentication)
+	hlr.SetHardening(install.Hardening)
 
 	dexComponentCfg := &render.DexComponentConfiguration{
 		PullSecrets:   pullSecrets,
-		Openshift:     r.provider == oprv1.ProviderOpenShift,
 		Installation:  install,
 		DexConfig:     dexCfg,
 		ClusterDomain: r.clusterDomain,
@@ -17,6 +17,7 @@ package clusterconnection
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/controller/options"
@@ -35,12 +36,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const controllerName = "clusterconnection-controller"
 
+// tunnelHeartbeatTimeout is how long the tunnel may go without a heartbeat before it is reported as broken.
+const tunnelHeartbeatTimeout = 2 * time.Minute
+
 var log = logf.Log.WithName(controllerName)
 
 // Add creates a new ManagementClusterConnection Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -57,10 +62,11 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(cli client.Client, schema *runtime.Scheme, statusMgr status.StatusManager, p operatorv1.Provider, opts options.AddOptions) reconcile.Reconciler {
 	c := &ReconcileConnection{
-		Client:   cli,
-		Scheme:   schema,
-		Provider: p,
-		status:   statusMgr,
+		Client:        cli,
+		Scheme:        schema,
+		Provider:      p,
+		status:        statusMgr,
+		clusterDomain: opts.ClusterDomain,
 	}
 	c.status.Run(opts.ShutdownContext)
 	return c
@@ -69,7 +75,7 @@ func newReconciler(cli client.Client, schema *runtime.Scheme, statusMgr status.S
 // add adds a new controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: utils.FairReconciler(controllerName, r)})
 	if err != nil {
 		return fmt.Errorf("failed to create %s: %w", controllerName, err)
 	}
@@ -80,8 +86,9 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("%s failed to watch primary resource: %w", controllerName, err)
 	}
 
-	// Watch for changes to primary resource ManagementClusterConnection
-	err = c.Watch(&source.Kind{Type: &operatorv1.ManagementClusterConnection{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to primary resource ManagementClusterConnection. Guardian updates its status periodically, which
+	// is checked on requeue instead.
+	err = c.Watch(&source.Kind{Type: &operatorv1.ManagementClusterConnection{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
 	if err != nil {
 		return fmt.Errorf("%s failed to watch primary resource: %w", controllerName, err)
 	}
@@ -100,6 +107,11 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("%s failed to watch Secret resource %s: %w", controllerName, render.PrometheusTLSSecretName, err)
 	}
 
+	// Watch all secrets in the operator namespace, the proxy CA secret is named by the user.
+	if err = utils.AddSecretsWatch(c, "", common.OperatorNamespace()); err != nil {
+		return fmt.Errorf("%s failed to watch secrets: %w", controllerName, err)
+	}
+
 	if err = utils.AddNetworkWatch(c); err != nil {
 		return fmt.Errorf("%s failed to watch Network resource: %w", controllerName, err)
 	}
@@ -116,10 +128,11 @@ var _ reconcile.Reconciler = &ReconcileConnection{}
 
 // ReconcileConnection reconciles a ManagementClusterConnection object
 type ReconcileConnection struct {
-	Client   client.Client
-	Scheme   *runtime.Scheme
-	Provider operatorv1.Provider
-	status   status.StatusManager
+	Client        client.Client
+	Scheme        *runtime.Scheme
+	Provider      operatorv1.Provider
+	status        status.StatusManager
+	clusterDomain string
 }
 
 // Reconcile reads that state of the cluster for a ManagementClusterConnection object and makes changes based on the
@@ -210,15 +223,40 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, err
 	}
 
+	var proxyCASecret *corev1.Secret
+	if proxy := managementClusterConnection.Spec.Proxy; proxy != nil && proxy.CASecretName != "" {
+		proxyCASecret, err = utils.ValidateCertPair(r.Client,
+			common.OperatorNamespace(),
+			proxy.CASecretName,
+			"", // We don't need the key.
+			render.GuardianProxyCAKey,
+		)
+		if err != nil {
+			reqLogger.Error(err, fmt.Sprintf("failed to retrieve %s", proxy.CASecretName))
+			r.status.SetDegraded(fmt.Sprintf("Failed to retrieve %s", proxy.CASecretName), err.Error())
+			return reconcile.Result{}, err
+		} else if proxyCASecret == nil {
+			reqLogger.Info(fmt.Sprintf("Waiting for secret '%s' to become available", proxy.CASecretName))
+			r.status.SetDegraded(fmt.Sprintf("Waiting for secret '%s' to become available", proxy.CASecretName), "")
+			return reconcile.Result{}, nil
+		}
+	}
+
 	ch := utils.NewComponentHandler(log, r.Client, r.Scheme, managementClusterConnection)
+	ch.SetHardening(instl.Hardening)
 	guardianCfg := &render.GuardianConfiguration{
 		URL:                  managementClusterConnection.Spec.ManagementClusterAddr,
+		Proxy:                managementClusterConnection.Spec.Proxy,
+		TunnelKeepAlive:      managementClusterConnection.Spec.TunnelKeepAlive,
+		ReconnectBackoff:     managementClusterConnection.Spec.ReconnectBackoff,
+		Deployment:           managementClusterConnection.Spec.GuardianDeployment,
+		ProxyCASecret:        proxyCASecret,
 		PullSecrets:          pullSecrets,
-		Openshift:            r.Provider == operatorv1.ProviderOpenShift,
 		Installation:         instl,
 		TunnelSecret:         tunnelSecret,
 		PacketCaptureSecret:  packetCaptureServerCertSecret,
 		PrometheusCertSecret: prometheusCertSecret,
+		ClusterDomain:        r.clusterDomain,
 	}
 	component := render.Guardian(guardianCfg)
 
@@ -233,6 +271,19 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 		return result, err
 	}
 
+	// Surface a broken tunnel, as reported by guardian, so that it is visible in the TigeraStatus.
+	if tunnel := managementClusterConnection.Status.Tunnel; tunnel != nil {
+		result.RequeueAfter = tunnelHeartbeatTimeout
+		if !tunnel.Connected {
+			r.status.SetDegraded("The tunnel to the management cluster is not connected", fmt.Sprintf("Reconnected %d times", tunnel.ReconnectCount))
+			return result, nil
+		}
+		if tunnel.LastHeartbeatTime != nil && time.Since(tunnel.LastHeartbeatTime.Time) > tunnelHeartbeatTimeout {
+			r.status.SetDegraded(fmt.Sprintf("No heartbeat received from the management cluster since %s", tunnel.LastHeartbeatTime.Format(time.RFC3339)), "")
+			return result, nil
+		}
+	}
+
 	r.status.ClearDegraded()
 
 	//We should create the Guardian deployment.
@@ -17,6 +17,7 @@ package clusterconnection_test
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
@@ -68,6 +69,8 @@ var _ = Describe("ManagementClusterConnection controller tests", func() {
 		mockStatus.On("AddDeployments", mock.Anything)
 		mockStatus.On("AddStatefulSets", mock.Anything)
 		mockStatus.On("AddCronJobs", mock.Anything)
+		mockStatus.On("AddJobs", mock.Anything)
+		mockStatus.On("IsRolloutPaused").Return(false)
 		mockStatus.On("ClearDegraded", mock.Anything)
 		mockStatus.On("SetDegraded", mock.Anything, mock.Anything)
 		mockStatus.On("OnCRFound").Return()
@@ -158,6 +161,28 @@ var _ = Describe("ManagementClusterConnection controller tests", func() {
 		})
 	})
 
+	Context("tunnel health", func() {
+		It("should degrade when guardian reports a broken tunnel", func() {
+			cfg.Status.Tunnel = &operatorv1.TunnelStatus{Connected: false, ReconnectCount: 3}
+			Expect(c.Status().Update(ctx, cfg)).NotTo(HaveOccurred())
+
+			result, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "The tunnel to the management cluster is not connected", "Reconnected 3 times")
+		})
+
+		It("should degrade when the heartbeat of the tunnel is stale", func() {
+			heartbeat := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+			cfg.Status.Tunnel = &operatorv1.TunnelStatus{Connected: true, LastHeartbeatTime: &heartbeat}
+			Expect(c.Status().Update(ctx, cfg)).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ToNot(HaveOccurred())
+			mockStatus.AssertCalled(GinkgoT(), "SetDegraded", fmt.Sprintf("No heartbeat received from the management cluster since %s", heartbeat.Format(time.RFC3339)), "")
+		})
+	})
+
 	Context("image reconciliation", func() {
 		It("should use builtin images", func() {
 			r = clusterconnection.NewReconcilerWithShims(c, scheme, mockStatus, operatorv1.ProviderNone)
@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/controller/options"
@@ -58,7 +59,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	reconciler := newReconciler(mgr, opts, licenseAPIReady)
 
 	// Create a new controller
-	controller, err := controller.New("compliance-controller", mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
+	controller, err := controller.New("compliance-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("compliance-controller", reconciler)})
 	if err != nil {
 		return err
 	}
@@ -69,7 +70,12 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return err
 	}
 
-	go utils.WaitToAddLicenseKeyWatch(controller, k8sClient, log, licenseAPIReady)
+	go utils.WaitToAddLicenseKeyWatch(controller, k8sClient, log, licenseAPIReady, opts.LicenseKeyCache)
+
+	// Re-render the PodSecurityPolicies when the cluster is upgraded.
+	if err = utils.AddKubernetesVersionWatch(controller, opts.KubernetesVersion, utils.DefaultTSEEInstanceKey); err != nil {
+		return fmt.Errorf("compliance-controller failed to watch the Kubernetes version: %w", err)
+	}
 
 	return add(mgr, controller)
 }
@@ -77,12 +83,14 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 // newReconciler returns a new *reconcile.Reconciler
 func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady *utils.ReadyFlag) reconcile.Reconciler {
 	r := &ReconcileCompliance{
-		client:          mgr.GetClient(),
-		scheme:          mgr.GetScheme(),
-		provider:        opts.DetectedProvider,
-		status:          status.New(mgr.GetClient(), "compliance", opts.KubernetesVersion),
-		clusterDomain:   opts.ClusterDomain,
-		licenseAPIReady: licenseAPIReady,
+		client:            mgr.GetClient(),
+		scheme:            mgr.GetScheme(),
+		provider:          opts.DetectedProvider,
+		status:            status.New(mgr.GetClient(), "compliance", opts.KubernetesVersion),
+		clusterDomain:     opts.ClusterDomain,
+		licenseAPIReady:   licenseAPIReady,
+		licenseKeyCache:   opts.LicenseKeyCache,
+		kubernetesVersion: opts.KubernetesVersion,
 	}
 	r.status.Run(opts.ShutdownContext)
 	return r
@@ -106,8 +114,8 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		return fmt.Errorf("compliance-controller failed to watch ImageSet: %w", err)
 	}
 
-	if err = utils.AddAPIServerWatch(c); err != nil {
-		return fmt.Errorf("compliance-controller failed to watch APIServer resource: %w", err)
+	if err = utils.AddCRDependencyWatches(c, utils.ComplianceCR); err != nil {
+		return fmt.Errorf("compliance-controller failed to watch the CRs it depends on: %w", err)
 	}
 
 	// Watch the given secrets in each both the compliance and operator namespaces
@@ -160,6 +168,9 @@ type ReconcileCompliance struct {
 	status          status.StatusManager
 	clusterDomain   string
 	licenseAPIReady *utils.ReadyFlag
+	licenseKeyCache *utils.LicenseKeyCache
+	// kubernetesVersion is the version of the cluster, which the PodSecurityPolicies depend on.
+	kubernetesVersion *common.KubernetesVersionTracker
 }
 
 func GetCompliance(ctx context.Context, cli client.Client) (*operatorv1.Compliance, error) {
@@ -197,24 +208,13 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 	r.status.OnCRFound()
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 
-	if !utils.IsAPIServerReady(r.client, reqLogger) {
-		r.status.SetDegraded("Waiting for Tigera API server to be ready", "")
-		return reconcile.Result{}, err
-	}
-
-	if !r.licenseAPIReady.IsReady() {
-		r.status.SetDegraded("Waiting for LicenseKeyAPI to be ready", "")
-		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
-	}
-
-	license, err := utils.FetchLicenseKey(ctx, r.client)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			r.status.SetDegraded("License not found", err.Error())
-			return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
-		}
-		r.status.SetDegraded("Error querying license", err.Error())
-		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	var license v3.LicenseKey
+	if result, unmet, err := utils.CheckDependencies(ctx, r.client, reqLogger, r.status,
+		utils.CRDependenciesReady(utils.ComplianceCR),
+		utils.LicenseAPIReady(r.licenseAPIReady),
+		utils.LicenseKeyInstalled(r.licenseKeyCache, &license),
+	); unmet {
+		return result, err
 	}
 
 	// Query for the installation object.
@@ -348,6 +348,7 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetHardening(network.Hardening)
 
 	keyValidatorConfig, err := utils.GetKeyValidatorConfig(ctx, r.client, authenticationCR, r.clusterDomain)
 	if err != nil {
@@ -363,7 +364,6 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 
 	reqLogger.V(3).Info("rendering components")
 	var hasNoLicense = !utils.IsFeatureActive(license, common.ComplianceFeature)
-	openshift := r.provider == operatorv1.ProviderOpenShift
 	complianceCfg := &render.ComplianceConfiguration{
 		ESSecrets:                   esSecrets,
 		ManagerInternalTLSSecret:    managerInternalTLSSecret,
@@ -371,12 +371,12 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 		ComplianceServerCertSecret:  complianceServerCertSecret,
 		ESClusterConfig:             esClusterConfig,
 		PullSecrets:                 pullSecrets,
-		Openshift:                   openshift,
 		ManagementCluster:           managementCluster,
 		ManagementClusterConnection: managementClusterConnection,
 		KeyValidatorConfig:          keyValidatorConfig,
 		ClusterDomain:               r.clusterDomain,
 		HasNoLicense:                hasNoLicense,
+		KubernetesVersion:           r.kubernetesVersion.Get(),
 	}
 	// Render the desired objects from the CRD and create or update them.
 	component, err := render.Compliance(complianceCfg)
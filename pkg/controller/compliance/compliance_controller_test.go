@@ -78,6 +78,8 @@ var _ = Describe("Compliance controller tests", func() {
 		mockStatus.On("RemoveDaemonsets", mock.Anything).Return()
 		mockStatus.On("AddStatefulSets", mock.Anything).Return()
 		mockStatus.On("AddCronJobs", mock.Anything)
+		mockStatus.On("AddJobs", mock.Anything)
+		mockStatus.On("IsRolloutPaused").Return(false)
 		mockStatus.On("IsAvailable").Return(true)
 		mockStatus.On("OnCRFound").Return()
 		mockStatus.On("ClearDegraded")
@@ -0,0 +1,332 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplanerestart
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/options"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const (
+	defaultMaxUnavailable  = 1
+	defaultIntervalSeconds = 30
+	defaultTimeoutSeconds  = 600
+)
+
+var log = logf.Log.WithName("controller_dataplanerestart")
+
+// Add creates a new DataplaneRestart Controller and adds it to the Manager.
+// The Manager will set fields on the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, opts options.AddOptions) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) *ReconcileDataplaneRestart {
+	return &ReconcileDataplaneRestart{
+		client:   mgr.GetClient(),
+		recorder: mgr.GetEventRecorderFor("dataplanerestart-controller"),
+		now:      time.Now,
+	}
+}
+
+// add adds watches for resources that are available at startup.
+func add(mgr manager.Manager, r *ReconcileDataplaneRestart) error {
+	c, err := controller.New("dataplanerestart-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("dataplanerestart-controller", r)})
+	if err != nil {
+		return fmt.Errorf("Failed to create dataplanerestart-controller: %w", err)
+	}
+
+	err = c.Watch(&source.Kind{Type: &operatorv1.DataplaneRestart{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("dataplanerestart-controller failed to watch primary resource: %w", err)
+	}
+
+	// The restarts running move on to the next nodes once the new pods of the restarted nodes are ready.
+	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(r.runningRestarts), predicate.NewPredicateFuncs(func(o client.Object) bool {
+		_, vppNode := o.GetLabels()[render.VPPNodePodLabel]
+		return o.GetNamespace() == common.CalicoNamespace && (vppNode || o.GetLabels()["k8s-app"] == common.NodeDaemonSetName)
+	}))
+	if err != nil {
+		return fmt.Errorf("dataplanerestart-controller failed to watch the dataplane pods: %w", err)
+	}
+
+	// The nodes deleted while they wait for their turn or their new pod are skipped.
+	err = c.Watch(&source.Kind{Type: &corev1.Node{}}, handler.EnqueueRequestsFromMapFunc(r.runningRestarts), predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	})
+	if err != nil {
+		return fmt.Errorf("dataplanerestart-controller failed to watch nodes: %w", err)
+	}
+
+	return nil
+}
+
+// Blank assignment to verify that ReconcileDataplaneRestart implements reconcile.Reconciler.
+var _ reconcile.Reconciler = &ReconcileDataplaneRestart{}
+
+// ReconcileDataplaneRestart reconciles the DataplaneRestart objects.
+type ReconcileDataplaneRestart struct {
+	client   client.Client
+	recorder record.EventRecorder
+	now      func() time.Time
+}
+
+// runningRestarts returns a request for each DataplaneRestart which is running.
+func (r *ReconcileDataplaneRestart) runningRestarts(client.Object) []reconcile.Request {
+	restarts := &operatorv1.DataplaneRestartList{}
+	if err := r.client.List(context.Background(), restarts); err != nil {
+		log.Error(err, "Failed to list the DataplaneRestarts")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, restart := range restarts.Items {
+		if !finished(restart.Status.Phase) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: restart.Name}})
+		}
+	}
+	return requests
+}
+
+// Reconcile restarts the dataplane of the nodes selected by the DataplaneRestart. The nodes are selected once, when
+// the restart starts, and restarted in the order of their names. The pod of a node is deleted after its state is
+// recorded in the status, so that a restart is never lost nor repeated, and the next nodes wait for the new pod to be
+// ready. A node whose new pod isn't ready within the timeout fails the restart, and the nodes pending aren't restarted.
+// The progress of the nodes is recorded as Events of the DataplaneRestart as well.
+func (r *ReconcileDataplaneRestart) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Name", request.Name)
+	reqLogger.Info("Reconciling DataplaneRestart")
+
+	instance := &operatorv1.DataplaneRestart{}
+	if err := r.client.Get(ctx, request.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if finished(instance.Status.Phase) {
+		return reconcile.Result{}, nil
+	}
+
+	// The calico-vpp-node pods belong to the DaemonSets of the VPP profiles as well.
+	app, podSelector := render.VPPNodeName, client.ListOption(client.HasLabels{render.VPPNodePodLabel})
+	if c := instance.Spec.Component; c != nil && *c == operatorv1.DataplaneRestartCalicoNode {
+		app, podSelector = common.NodeDaemonSetName, client.MatchingLabels{"k8s-app": common.NodeDaemonSetName}
+	}
+	pods := &corev1.PodList{}
+	if err := r.client.List(ctx, pods, client.InNamespace(common.CalicoNamespace), podSelector); err != nil {
+		return reconcile.Result{}, err
+	}
+	podsByNode := map[string]*corev1.Pod{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		// The pod replacing a pod being deleted takes precedence.
+		if p, ok := podsByNode[pod.Spec.NodeName]; !ok || p.DeletionTimestamp != nil {
+			podsByNode[pod.Spec.NodeName] = pod
+		}
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.client.List(ctx, nodes); err != nil {
+		return reconcile.Result{}, err
+	}
+	nodeExists := map[string]bool{}
+	for _, node := range nodes.Items {
+		nodeExists[node.Name] = true
+	}
+
+	now := metav1.NewTime(r.now())
+	st := &instance.Status
+	if st.Phase == "" {
+		selector := labels.SelectorFromSet(instance.Spec.NodeSelector)
+		sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+		for _, node := range nodes.Items {
+			if _, ok := podsByNode[node.Name]; ok && selector.Matches(labels.Set(node.Labels)) {
+				st.Nodes = append(st.Nodes, operatorv1.NodeRestartStatus{Node: node.Name, State: operatorv1.NodeRestartPending})
+			}
+		}
+		st.Phase, st.StartTime = operatorv1.DataplaneRestartRunning, &now
+		reqLogger.Info("Restarting the dataplane of the nodes", "component", app, "reason", instance.Spec.Reason, "nodes", len(st.Nodes))
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, "Started", "Restarting the %s dataplane of %d nodes: %s", app, len(st.Nodes), instance.Spec.Reason)
+	}
+
+	maxUnavailable, interval := defaultMaxUnavailable, defaultIntervalSeconds*time.Second
+	if rl := instance.Spec.RateLimit; rl != nil {
+		if rl.MaxUnavailable != nil {
+			maxUnavailable = int(*rl.MaxUnavailable)
+		}
+		if rl.IntervalSeconds != nil {
+			interval = time.Duration(*rl.IntervalSeconds) * time.Second
+		}
+	}
+	timeout := defaultTimeoutSeconds * time.Second
+	if instance.Spec.TimeoutSeconds != nil {
+		timeout = time.Duration(*instance.Spec.TimeoutSeconds) * time.Second
+	}
+
+	// toDelete are the pods deleted once the status is updated, including the ones whose deletion failed before.
+	var toDelete []*corev1.Pod
+	var restarting int
+	var failed bool
+	var lastRestart time.Time
+	var requeueAfter time.Duration
+	for i := range st.Nodes {
+		n := &st.Nodes[i]
+		if n.RestartTime != nil && n.RestartTime.Time.After(lastRestart) {
+			lastRestart = n.RestartTime.Time
+		}
+		failed = failed || n.State == operatorv1.NodeRestartFailed
+		if n.State != operatorv1.NodeRestartRestarting {
+			continue
+		}
+		if !nodeExists[n.Node] {
+			reqLogger.Info("Skipping node deleted during its restart", "node", n.Node)
+			r.recorder.Eventf(instance, corev1.EventTypeWarning, "NodeSkipped", "Node %s was deleted during its restart", n.Node)
+			n.State = operatorv1.NodeRestartSkipped
+			continue
+		}
+		pod := podsByNode[n.Node]
+		switch {
+		case pod != nil && pod.Name == n.Pod:
+			if pod.DeletionTimestamp == nil {
+				toDelete = append(toDelete, pod)
+			}
+		case pod != nil && podReady(pod):
+			reqLogger.Info("Dataplane of the node restarted", "node", n.Node, "pod", pod.Name)
+			r.recorder.Eventf(instance, corev1.EventTypeNormal, "NodeRestarted", "Dataplane of node %s restarted, pod %s is ready", n.Node, pod.Name)
+			n.State = operatorv1.NodeRestartRestarted
+			continue
+		}
+		wait := n.RestartTime.Add(timeout).Sub(now.Time)
+		if wait <= 0 {
+			reqLogger.Info("Dataplane of the node not ready within the timeout", "node", n.Node, "timeout", timeout)
+			r.recorder.Eventf(instance, corev1.EventTypeWarning, "NodeFailed", "Dataplane of node %s not ready within %s of its restart", n.Node, timeout)
+			n.State = operatorv1.NodeRestartFailed
+			failed = true
+			continue
+		}
+		requeueAfter = minDuration(requeueAfter, wait)
+		restarting++
+	}
+
+	for i := range st.Nodes {
+		n := &st.Nodes[i]
+		if n.State != operatorv1.NodeRestartPending {
+			continue
+		}
+		if failed || restarting >= maxUnavailable {
+			break
+		}
+		if wait := lastRestart.Add(interval).Sub(now.Time); !lastRestart.IsZero() && wait > 0 {
+			requeueAfter = minDuration(requeueAfter, wait)
+			break
+		}
+		pod := podsByNode[n.Node]
+		if pod == nil || !nodeExists[n.Node] {
+			reqLogger.Info("Skipping node without a dataplane pod", "node", n.Node)
+			r.recorder.Eventf(instance, corev1.EventTypeWarning, "NodeSkipped", "Node %s has no dataplane pod to restart", n.Node)
+			n.State = operatorv1.NodeRestartSkipped
+			continue
+		}
+		reqLogger.Info("Restarting the dataplane of the node", "node", n.Node, "pod", pod.Name, "reason", instance.Spec.Reason)
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, "NodeRestarting", "Restarting the dataplane of node %s, deleting pod %s", n.Node, pod.Name)
+		n.State, n.Pod, n.RestartTime = operatorv1.NodeRestartRestarting, pod.Name, &now
+		toDelete = append(toDelete, pod)
+		restarting++
+		lastRestart = now.Time
+		requeueAfter = minDuration(requeueAfter, timeout)
+	}
+
+	switch {
+	case restarting > 0:
+	case failed:
+		reqLogger.Info("Restart of the dataplane of the nodes failed", "reason", instance.Spec.Reason)
+		r.recorder.Event(instance, corev1.EventTypeWarning, "Failed", "Restart failed, the nodes pending aren't restarted")
+		st.Phase, st.CompletionTime = operatorv1.DataplaneRestartFailed, &now
+	case !pending(st.Nodes):
+		reqLogger.Info("Dataplane of the nodes restarted", "reason", instance.Spec.Reason)
+		r.recorder.Event(instance, corev1.EventTypeNormal, "Completed", "Dataplane of the nodes restarted")
+		st.Phase, st.CompletionTime = operatorv1.DataplaneRestartCompleted, &now
+	}
+	if err := r.client.Status().Update(ctx, instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for _, pod := range toDelete {
+		if err := r.client.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("failed to delete pod %s: %w", pod.Name, err)
+		}
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// finished returns whether the restart is over, so that it isn't reconciled anymore.
+func finished(phase operatorv1.DataplaneRestartPhase) bool {
+	return phase == operatorv1.DataplaneRestartCompleted || phase == operatorv1.DataplaneRestartFailed
+}
+
+// minDuration returns the shortest of the durations, where zero is no duration.
+func minDuration(d, other time.Duration) time.Duration {
+	if d == 0 || other < d {
+		return other
+	}
+	return d
+}
+
+// pending returns whether some of the nodes are waiting for their turn.
+func pending(nodes []operatorv1.NodeRestartStatus) bool {
+	for _, n := range nodes {
+		if n.State == operatorv1.NodeRestartPending {
+			return true
+		}
+	}
+	return false
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
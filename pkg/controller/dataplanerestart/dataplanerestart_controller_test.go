@@ -0,0 +1,217 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplanerestart
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("DataplaneRestart controller tests", func() {
+	var cli client.Client
+	var ctx context.Context
+	var r ReconcileDataplaneRestart
+	var now time.Time
+	var restart *operatorv1.DataplaneRestart
+	var recorder *record.FakeRecorder
+
+	createPod := func(name, app, node string) {
+		labels := map[string]string{"k8s-app": app}
+		if app == render.VPPNodeName {
+			labels[render.VPPNodePodLabel] = "true"
+		}
+		Expect(cli.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.CalicoNamespace, Labels: labels},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		})).NotTo(HaveOccurred())
+	}
+	podExists := func(name string) bool {
+		err := cli.Get(ctx, types.NamespacedName{Name: name, Namespace: common.CalicoNamespace}, &corev1.Pod{})
+		return err == nil
+	}
+	reconcileRestart := func() (reconcile.Result, operatorv1.DataplaneRestartStatus) {
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "restart"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "restart"}, restart)).NotTo(HaveOccurred())
+		return result, restart.Status
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+		ctx = context.Background()
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+		now = time.Date(2021, 11, 2, 10, 0, 0, 0, time.UTC)
+		recorder = record.NewFakeRecorder(100)
+		r = ReconcileDataplaneRestart{client: cli, recorder: recorder, now: func() time.Time { return now }}
+
+		for _, name := range []string{"node-a", "node-b", "node-c"} {
+			labels := map[string]string{"rack": "r1"}
+			if name == "node-c" {
+				labels["rack"] = "r2"
+			}
+			Expect(cli.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}})).NotTo(HaveOccurred())
+			createPod(render.VPPNodeName+"-"+name, render.VPPNodeName, name)
+			createPod(common.NodeDaemonSetName+"-"+name, common.NodeDaemonSetName, name)
+		}
+
+		restart = &operatorv1.DataplaneRestart{
+			ObjectMeta: metav1.ObjectMeta{Name: "restart"},
+			Spec: operatorv1.DataplaneRestartSpec{
+				NodeSelector: map[string]string{"rack": "r1"},
+				Reason:       "VPP stuck after the upgrade of the NIC firmware",
+			},
+		}
+		Expect(cli.Create(ctx, restart)).NotTo(HaveOccurred())
+	})
+
+	It("should restart the VPP dataplane of the selected nodes one after the other", func() {
+		start := metav1.NewTime(now)
+		result, st := reconcileRestart()
+		Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+		Expect(st.Phase).To(Equal(operatorv1.DataplaneRestartRunning))
+		Expect(st.Nodes).To(HaveLen(2))
+		Expect(st.Nodes[0].Node).To(Equal("node-a"))
+		Expect(st.Nodes[0].State).To(Equal(operatorv1.NodeRestartRestarting))
+		Expect(st.Nodes[0].Pod).To(Equal(render.VPPNodeName + "-node-a"))
+		Expect(st.Nodes[0].RestartTime.Equal(&start)).To(BeTrue())
+		Expect(st.Nodes[1]).To(Equal(operatorv1.NodeRestartStatus{Node: "node-b", State: operatorv1.NodeRestartPending}))
+		Expect(podExists(render.VPPNodeName + "-node-a")).To(BeFalse())
+		Expect(podExists(common.NodeDaemonSetName + "-node-a")).To(BeTrue())
+
+		// node-b waits for the new pod of node-a to be ready.
+		_, st = reconcileRestart()
+		Expect(st.Nodes[1].State).To(Equal(operatorv1.NodeRestartPending))
+
+		// Then for the interval between the restarts.
+		createPod(render.VPPNodeName+"-node-a-new", render.VPPNodeName, "node-a")
+		now = now.Add(10 * time.Second)
+		result, st = reconcileRestart()
+		Expect(result.RequeueAfter).To(Equal(20 * time.Second))
+		Expect(st.Nodes[0].State).To(Equal(operatorv1.NodeRestartRestarted))
+		Expect(st.Nodes[1].State).To(Equal(operatorv1.NodeRestartPending))
+		Expect(podExists(render.VPPNodeName + "-node-b")).To(BeTrue())
+
+		now = now.Add(20 * time.Second)
+		_, st = reconcileRestart()
+		Expect(st.Nodes[1].State).To(Equal(operatorv1.NodeRestartRestarting))
+		Expect(podExists(render.VPPNodeName + "-node-b")).To(BeFalse())
+
+		createPod(render.VPPNodeName+"-node-b-new", render.VPPNodeName, "node-b")
+		_, st = reconcileRestart()
+		Expect(st.Nodes[1].State).To(Equal(operatorv1.NodeRestartRestarted))
+		Expect(st.Phase).To(Equal(operatorv1.DataplaneRestartCompleted))
+		Expect(st.CompletionTime).NotTo(BeNil())
+
+		// node-c isn't selected, and a completed restart isn't repeated.
+		Expect(podExists(render.VPPNodeName + "-node-c")).To(BeTrue())
+		_, st = reconcileRestart()
+		Expect(st.Phase).To(Equal(operatorv1.DataplaneRestartCompleted))
+		Expect(podExists(render.VPPNodeName + "-node-a-new")).To(BeTrue())
+
+		Expect(recorder.Events).To(HaveLen(6))
+		Expect(<-recorder.Events).To(Equal("Normal Started Restarting the calico-vpp-node dataplane of 2 nodes: VPP stuck after the upgrade of the NIC firmware"))
+		Expect(<-recorder.Events).To(Equal("Normal NodeRestarting Restarting the dataplane of node node-a, deleting pod calico-vpp-node-node-a"))
+		Expect(<-recorder.Events).To(Equal("Normal NodeRestarted Dataplane of node node-a restarted, pod calico-vpp-node-node-a-new is ready"))
+	})
+
+	It("should skip the nodes deleted during their restart", func() {
+		reconcileRestart()
+		Expect(cli.Delete(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}})).NotTo(HaveOccurred())
+
+		now = now.Add(30 * time.Second)
+		_, st := reconcileRestart()
+		Expect(st.Nodes[0].State).To(Equal(operatorv1.NodeRestartSkipped))
+		Expect(st.Nodes[1].State).To(Equal(operatorv1.NodeRestartRestarting))
+		Expect(podExists(render.VPPNodeName + "-node-b")).To(BeFalse())
+	})
+
+	It("should fail the restart when the new pod of a node isn't ready in time", func() {
+		timeout := int32(60)
+		restart.Spec.TimeoutSeconds = &timeout
+		Expect(cli.Update(ctx, restart)).NotTo(HaveOccurred())
+
+		result, _ := reconcileRestart()
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+		now = now.Add(30 * time.Second)
+		result, st := reconcileRestart()
+		Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+		Expect(st.Nodes[0].State).To(Equal(operatorv1.NodeRestartRestarting))
+
+		now = now.Add(30 * time.Second)
+		result, st = reconcileRestart()
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(st.Phase).To(Equal(operatorv1.DataplaneRestartFailed))
+		Expect(st.CompletionTime).NotTo(BeNil())
+		Expect(st.Nodes[0].State).To(Equal(operatorv1.NodeRestartFailed))
+		Expect(st.Nodes[1].State).To(Equal(operatorv1.NodeRestartPending))
+		Expect(podExists(render.VPPNodeName + "-node-b")).To(BeTrue())
+
+		// A failed restart isn't resumed.
+		createPod(render.VPPNodeName+"-node-a-new", render.VPPNodeName, "node-a")
+		_, st = reconcileRestart()
+		Expect(st.Phase).To(Equal(operatorv1.DataplaneRestartFailed))
+		Expect(podExists(render.VPPNodeName + "-node-b")).To(BeTrue())
+	})
+
+	It("should restart the calico-node pods at the rate limit, and skip the nodes which lost their pod", func() {
+		component := operatorv1.DataplaneRestartCalicoNode
+		restart.Spec.Component = &component
+		restart.Spec.NodeSelector = nil
+		maxUnavailable, interval := int32(2), int32(0)
+		restart.Spec.RateLimit = &operatorv1.DataplaneRestartRateLimit{MaxUnavailable: &maxUnavailable, IntervalSeconds: &interval}
+		Expect(cli.Update(ctx, restart)).NotTo(HaveOccurred())
+
+		_, st := reconcileRestart()
+		Expect(st.Nodes).To(HaveLen(3))
+		Expect(st.Nodes[0].State).To(Equal(operatorv1.NodeRestartRestarting))
+		Expect(st.Nodes[1].State).To(Equal(operatorv1.NodeRestartRestarting))
+		Expect(st.Nodes[2].State).To(Equal(operatorv1.NodeRestartPending))
+		Expect(podExists(common.NodeDaemonSetName + "-node-a")).To(BeFalse())
+		Expect(podExists(common.NodeDaemonSetName + "-node-b")).To(BeFalse())
+		Expect(podExists(render.VPPNodeName + "-node-a")).To(BeTrue())
+
+		Expect(cli.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: common.NodeDaemonSetName + "-node-c", Namespace: common.CalicoNamespace,
+		}})).NotTo(HaveOccurred())
+		createPod(common.NodeDaemonSetName+"-node-a-new", common.NodeDaemonSetName, "node-a")
+		_, st = reconcileRestart()
+		Expect(st.Nodes[0].State).To(Equal(operatorv1.NodeRestartRestarted))
+		Expect(st.Nodes[1].State).To(Equal(operatorv1.NodeRestartRestarting))
+		Expect(st.Nodes[2].State).To(Equal(operatorv1.NodeRestartSkipped))
+		Expect(st.Phase).To(Equal(operatorv1.DataplaneRestartRunning))
+	})
+})
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+)
+
+// nodeToNodeMeshDisabledAnnotation is set on the default BGPConfiguration when the operator disabled the full
+// node-to-node mesh in favour of the route reflectors, so that the mesh is only restored if the operator disabled it.
+const nodeToNodeMeshDisabledAnnotation = "operator.tigera.io/node-to-node-mesh-disabled"
+
+// restoreNodeToNodeMesh enables the full node-to-node BGP mesh in the default BGPConfiguration again if the operator
+// disabled it.
+func (r *ReconcileInstallation) restoreNodeToNodeMesh(ctx context.Context, log logr.Logger) error {
+	bgpConfig := &crdv1.BGPConfiguration{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: "default"}, bgpConfig); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		r.SetDegraded("Unable to read BGPConfiguration", err, log)
+		return err
+	}
+	if bgpConfig.Annotations[nodeToNodeMeshDisabledAnnotation] != "true" {
+		return nil
+	}
+
+	patchFrom := client.MergeFrom(bgpConfig.DeepCopy())
+	t := true
+	bgpConfig.Spec.NodeToNodeMeshEnabled = &t
+	delete(bgpConfig.Annotations, nodeToNodeMeshDisabledAnnotation)
+	if err := r.client.Patch(ctx, bgpConfig, patchFrom); err != nil {
+		r.SetDegraded("Unable to Patch default BGPConfiguration", err, log)
+		return err
+	}
+	return nil
+}
+
+// updateBGPConfiguration updates the default BGPConfiguration for the given network: the full node-to-node mesh is
+// disabled when route reflectors are configured, and the advertised service CIDRs are set when service advertisement
+// is configured. Service advertisement is left alone otherwise, so that it may be managed outside of the operator.
+func (r *ReconcileInstallation) updateBGPConfiguration(ctx context.Context, network *operator.CalicoNetworkSpec, log logr.Logger) error {
+	if network == nil || (network.RouteReflectors == nil && network.ServiceAdvertisement == nil) {
+		return nil
+	}
+
+	bgpConfig := &crdv1.BGPConfiguration{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: "default"}, bgpConfig)
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.SetDegraded("Unable to read BGPConfiguration", err, log)
+		return err
+	}
+	patchFrom := client.MergeFrom(bgpConfig.DeepCopy())
+	bgpConfig.ObjectMeta.Name = "default"
+	updated := false
+
+	if network.RouteReflectors != nil && (bgpConfig.Spec.NodeToNodeMeshEnabled == nil || *bgpConfig.Spec.NodeToNodeMeshEnabled) {
+		updated = true
+		f := false
+		bgpConfig.Spec.NodeToNodeMeshEnabled = &f
+		if bgpConfig.Annotations == nil {
+			bgpConfig.Annotations = map[string]string{}
+		}
+		bgpConfig.Annotations[nodeToNodeMeshDisabledAnnotation] = "true"
+	}
+
+	if sa := network.ServiceAdvertisement; sa != nil {
+		var clusterIPs []crdv1.ServiceClusterIPBlock
+		for _, c := range sa.ServiceClusterIPs {
+			clusterIPs = append(clusterIPs, crdv1.ServiceClusterIPBlock{CIDR: c})
+		}
+		var externalIPs []crdv1.ServiceExternalIPBlock
+		for _, c := range sa.ServiceExternalIPs {
+			externalIPs = append(externalIPs, crdv1.ServiceExternalIPBlock{CIDR: c})
+		}
+		var loadBalancerIPs []crdv1.ServiceLoadBalancerIPBlock
+		for _, c := range sa.ServiceLoadBalancerIPs {
+			loadBalancerIPs = append(loadBalancerIPs, crdv1.ServiceLoadBalancerIPBlock{CIDR: c})
+		}
+		if !reflect.DeepEqual(bgpConfig.Spec.ServiceClusterIPs, clusterIPs) ||
+			!reflect.DeepEqual(bgpConfig.Spec.ServiceExternalIPs, externalIPs) ||
+			!reflect.DeepEqual(bgpConfig.Spec.ServiceLoadBalancerIPs, loadBalancerIPs) {
+			updated = true
+			bgpConfig.Spec.ServiceClusterIPs = clusterIPs
+			bgpConfig.Spec.ServiceExternalIPs = externalIPs
+			bgpConfig.Spec.ServiceLoadBalancerIPs = loadBalancerIPs
+		}
+	}
+
+	if !updated {
+		return nil
+	}
+	if bgpConfig.ResourceVersion == "" {
+		if err := r.client.Create(ctx, bgpConfig); err != nil {
+			r.SetDegraded("Unable to Create default BGPConfiguration", err, log)
+			return err
+		}
+	} else {
+		if err := r.client.Patch(ctx, bgpConfig, patchFrom); err != nil {
+			r.SetDegraded("Unable to Patch default BGPConfiguration", err, log)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/controller/status"
+)
+
+var _ = Describe("BGPConfiguration", func() {
+	var (
+		c      client.Client
+		ctx    context.Context
+		r      *ReconcileInstallation
+		reqLog = logf.Log.WithName("test")
+	)
+
+	getBGPConfiguration := func() *crdv1.BGPConfiguration {
+		bgpConfig := &crdv1.BGPConfiguration{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "default"}, bgpConfig)).NotTo(HaveOccurred())
+		return bgpConfig
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+		ctx = context.Background()
+		r = &ReconcileInstallation{client: c, scheme: scheme, status: &status.MockStatus{}}
+	})
+
+	It("should not create a BGPConfiguration when there is nothing to configure", func() {
+		Expect(r.updateBGPConfiguration(ctx, &operator.CalicoNetworkSpec{}, reqLog)).NotTo(HaveOccurred())
+		Expect(r.restoreNodeToNodeMesh(ctx, reqLog)).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "default"}, &crdv1.BGPConfiguration{})).To(HaveOccurred())
+	})
+
+	It("should only restore the node-to-node mesh if the operator disabled it", func() {
+		network := &operator.CalicoNetworkSpec{RouteReflectors: &operator.RouteReflectors{}}
+		Expect(r.updateBGPConfiguration(ctx, network, reqLog)).NotTo(HaveOccurred())
+		Expect(*getBGPConfiguration().Spec.NodeToNodeMeshEnabled).To(BeFalse())
+
+		Expect(r.restoreNodeToNodeMesh(ctx, reqLog)).NotTo(HaveOccurred())
+		bgpConfig := getBGPConfiguration()
+		Expect(*bgpConfig.Spec.NodeToNodeMeshEnabled).To(BeTrue())
+		Expect(bgpConfig.Annotations).NotTo(HaveKey(nodeToNodeMeshDisabledAnnotation))
+
+		// A mesh disabled by the user is left alone.
+		f := false
+		bgpConfig.Spec.NodeToNodeMeshEnabled = &f
+		Expect(c.Update(ctx, bgpConfig)).NotTo(HaveOccurred())
+		Expect(r.restoreNodeToNodeMesh(ctx, reqLog)).NotTo(HaveOccurred())
+		Expect(*getBGPConfiguration().Spec.NodeToNodeMeshEnabled).To(BeFalse())
+	})
+
+	It("should set the advertised service CIDRs", func() {
+		network := &operator.CalicoNetworkSpec{ServiceAdvertisement: &operator.ServiceAdvertisement{
+			ServiceClusterIPs:      []string{"10.96.0.0/12"},
+			ServiceLoadBalancerIPs: []string{"192.0.2.0/24"},
+		}}
+		Expect(r.updateBGPConfiguration(ctx, network, reqLog)).NotTo(HaveOccurred())
+		bgpConfig := getBGPConfiguration()
+		Expect(bgpConfig.Spec.NodeToNodeMeshEnabled).To(BeNil())
+		Expect(bgpConfig.Spec.ServiceClusterIPs).To(ConsistOf(crdv1.ServiceClusterIPBlock{CIDR: "10.96.0.0/12"}))
+		Expect(bgpConfig.Spec.ServiceExternalIPs).To(BeEmpty())
+		Expect(bgpConfig.Spec.ServiceLoadBalancerIPs).To(ConsistOf(crdv1.ServiceLoadBalancerIPBlock{CIDR: "192.0.2.0/24"}))
+
+		network.ServiceAdvertisement.ServiceLoadBalancerIPs = nil
+		Expect(r.updateBGPConfiguration(ctx, network, reqLog)).NotTo(HaveOccurred())
+		Expect(getBGPConfiguration().Spec.ServiceLoadBalancerIPs).To(BeEmpty())
+	})
+})
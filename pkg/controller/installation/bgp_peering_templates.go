@@ -0,0 +1,95 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// nodeBGPPredicate only passes the node events which may change the route reflectors or the BGP peers generated from
+// the templates: nodes being added or removed, and changes of their labels, schedulability or readiness.
+var nodeBGPPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		return !reflect.DeepEqual(oldNode.Labels, newNode.Labels) ||
+			oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable ||
+			nodeReady(oldNode) != nodeReady(newNode)
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// bgpPeeringTemplatesConfiguration returns the configuration of the BGP peering templates component, with the nodes and
+// the previously generated BGPPeers.
+func (r *ReconcileInstallation) bgpPeeringTemplatesConfiguration(ctx context.Context, install *operator.InstallationSpec) (*render.BGPPeeringTemplatesConfiguration, error) {
+	cfg := &render.BGPPeeringTemplatesConfiguration{Installation: install}
+
+	generated := &crdv1.BGPPeerList{}
+	if err := r.client.List(ctx, generated, client.HasLabels{render.BGPPeeringTemplateLabel}); err != nil {
+		return nil, err
+	}
+	cfg.GeneratedPeers = generated.Items
+
+	if install.CalicoNetwork != nil && len(install.CalicoNetwork.BGPPeeringTemplates) > 0 {
+		nodes, err := r.nodes()
+		if err != nil {
+			return nil, err
+		}
+		cfg.Nodes = nodes
+	}
+	return cfg, nil
+}
+
+// nodes returns the nodes of the cluster from the node informer shared with the typha autoscaler, once it has synced.
+func (r *ReconcileInstallation) nodes() ([]corev1.Node, error) {
+	if !r.nodeIndexInformer.HasSynced() {
+		return nil, fmt.Errorf("waiting for the nodes to be synced")
+	}
+	var nodes []corev1.Node
+	for _, obj := range r.nodeIndexInformer.GetIndexer().List() {
+		if node, ok := obj.(*corev1.Node); ok {
+			nodes = append(nodes, *node)
+		}
+	}
+	return nodes, nil
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/version"
+)
+
+// connectivityTestTimeout bounds the run of a connectivity test from its start, leaving the servers time to roll out
+// before the Job runs the checks within its own deadline.
+const connectivityTestTimeout = 10 * time.Minute
+
+// reconcileConnectivityTest starts the connectivity test when it is due, renders its objects while it runs, and
+// records its result in the status of the given installation. The previous version is the version of the operator
+// that last installed the components. The test also verifies the connectivity of the nodes migrated to the VPP
+// dataplane, from the migrated node, and reports its result to the node rollout. It returns true if the test is
+// running or due.
+func (r *ReconcileInstallation) reconcileConnectivityTest(ctx context.Context, instance *operator.Installation, imageSet *operator.ImageSet, previousVersion string, log logr.Logger) (bool, error) {
+	ct := instance.Spec.ConnectivityTest
+	migrated, requested := r.nodeRollout.migrationToVerify()
+	switch {
+	case ct != nil && connectivityTestDue(ct, instance.Status.ConnectivityTest, previousVersion):
+		instance.Status.ConnectivityTest = newConnectivityTestStatus(ct, "")
+		log.Info("Starting the connectivity test")
+	case migrated != "" && migrationConnectivityTestDue(instance.Status.ConnectivityTest, migrated, requested):
+		instance.Status.ConnectivityTest = newConnectivityTestStatus(ct, migrated)
+		log.Info("Starting the connectivity test of the node migrated to the VPP dataplane", "node", migrated)
+	}
+
+	cfg := &render.ConnectivityTestConfiguration{
+		Installation:  &instance.Spec,
+		ClusterDomain: r.clusterDomain,
+	}
+	st := instance.Status.ConnectivityTest
+	if st != nil && st.State == operator.ConnectivityTestRunning && st.StartTime != nil && (ct != nil || st.Node != "") {
+		cfg.Run = strconv.FormatInt(st.StartTime.Unix(), 10)
+		cfg.Node = st.Node
+
+		var err error
+		if cfg.ServersReady, err = connectivityTestServersReady(ctx, r.client, render.ConnectivityTestNodes(ct)); err != nil {
+			return false, err
+		}
+		if cfg.ServersReady {
+			done, err := connectivityTestResult(ctx, r.client, cfg.Run, st)
+			if err != nil {
+				return false, err
+			}
+			if done {
+				log.Info("Connectivity test completed", "state", st.State, "message", st.Message)
+				cfg.Run = ""
+			}
+		}
+		if cfg.Run != "" && connectivityTestTimedOut(st, time.Now()) {
+			log.Info("Connectivity test timed out", "message", st.Message)
+			cfg.Run = ""
+		}
+		if cfg.Run == "" {
+			r.nodeRollout.migrationVerified(st.Node, st)
+		}
+	}
+
+	component := render.ConnectivityTest(cfg)
+	if err := imageset.ResolveImages(imageSet, component); err != nil {
+		return false, err
+	}
+	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetHardening(instance.Spec.Hardening)
+	if err := handler.CreateOrUpdateOrDelete(ctx, component, nil); err != nil {
+		return false, err
+	}
+	return cfg.Run != "" || migrated != "", nil
+}
+
+// newConnectivityTestStatus returns the status of a connectivity test starting now. The node is the node migrated to
+// the VPP dataplane whose connectivity the test verifies, if any.
+func newConnectivityTestStatus(ct *operator.ConnectivityTest, node string) *operator.ConnectivityTestStatus {
+	now := metav1.Now()
+	st := &operator.ConnectivityTestStatus{
+		OperatorVersion: version.VERSION,
+		State:           operator.ConnectivityTestRunning,
+		StartTime:       &now,
+		Node:            node,
+	}
+	if ct != nil {
+		st.Trigger = ct.Trigger
+	}
+	return st
+}
+
+// connectivityTestDue returns true if a connectivity test must start: the trigger changed since the last test, or
+// the operator was upgraded and no test ran since.
+func connectivityTestDue(ct *operator.ConnectivityTest, st *operator.ConnectivityTestStatus, previousVersion string) bool {
+	if ct.Trigger != "" && (st == nil || st.Trigger != ct.Trigger) {
+		return true
+	}
+	upgraded := previousVersion != "" && previousVersion != version.VERSION
+	return upgraded && ct.AfterUpgrade != nil && *ct.AfterUpgrade == operator.ConnectivityTestAfterUpgradeEnabled &&
+		(st == nil || st.OperatorVersion != version.VERSION)
+}
+
+// migrationConnectivityTestDue returns true if a connectivity test must start to verify the connectivity of the given
+// migrated node: no test is running, and the last test didn't verify the node since the verification was requested.
+func migrationConnectivityTestDue(st *operator.ConnectivityTestStatus, node string, requested time.Time) bool {
+	if st == nil {
+		return true
+	}
+	if st.State == operator.ConnectivityTestRunning {
+		return false
+	}
+	return st.Node != node || st.StartTime == nil || st.StartTime.Time.Before(requested)
+}
+
+// connectivityTestTimedOut fails the given running test, and returns true, if it didn't complete within
+// connectivityTestTimeout of its start, e.g. when the pods of the servers can't be scheduled.
+func connectivityTestTimedOut(st *operator.ConnectivityTestStatus, now time.Time) bool {
+	if now.Sub(st.StartTime.Time) < connectivityTestTimeout {
+		return false
+	}
+	completion := metav1.NewTime(now)
+	st.State = operator.ConnectivityTestFailed
+	st.Message = fmt.Sprintf("the test didn't complete within %s", connectivityTestTimeout)
+	st.CompletionTime = &completion
+	return true
+}
+
+// connectivityTestServersReady returns true if the given number of servers of the connectivity test are available.
+func connectivityTestServersReady(ctx context.Context, cli client.Client, replicas int32) (bool, error) {
+	d := &appsv1.Deployment{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: render.ConnectivityTestName, Namespace: common.CalicoNamespace}, d); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return d.Status.AvailableReplicas >= replicas, nil
+}
+
+// connectivityTestResult updates the given status with the result of the Job of the given run, and returns true if
+// the Job completed.
+func connectivityTestResult(ctx context.Context, cli client.Client, run string, st *operator.ConnectivityTestStatus) (bool, error) {
+	job := &batchv1.Job{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: render.ConnectivityTestName, Namespace: common.CalicoNamespace}, job); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if job.Spec.Template.Annotations[render.ConnectivityTestRunAnnotation] != run {
+		// The Job of a previous run, which is replaced.
+		return false, nil
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			st.State = operator.ConnectivityTestSucceeded
+			st.Message = ""
+		case batchv1.JobFailed:
+			st.State = operator.ConnectivityTestFailed
+			st.Message = cond.Message
+			msg, err := connectivityTestFailures(ctx, cli)
+			if err != nil {
+				return false, err
+			}
+			if msg != "" {
+				st.Message = msg
+			}
+		default:
+			continue
+		}
+		now := metav1.Now()
+		st.CompletionTime = &now
+		return true, nil
+	}
+	return false, nil
+}
+
+// connectivityTestFailures returns the failed checks reported in the termination message of the pod of the Job.
+func connectivityTestFailures(ctx context.Context, cli client.Client) (string, error) {
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(common.CalicoNamespace), client.MatchingLabels{"job-name": render.ConnectivityTestName}); err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		for _, s := range pod.Status.ContainerStatuses {
+			if s.State.Terminated != nil && s.State.Terminated.Message != "" {
+				return s.State.Terminated.Message, nil
+			}
+		}
+	}
+	return "", nil
+}
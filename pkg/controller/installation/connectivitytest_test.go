@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/version"
+)
+
+var _ = Describe("Connectivity test", func() {
+	It("should start a test when the trigger changes", func() {
+		ct := &operator.ConnectivityTest{}
+		Expect(connectivityTestDue(ct, nil, "")).To(BeFalse())
+
+		ct.Trigger = "1"
+		Expect(connectivityTestDue(ct, nil, "")).To(BeTrue())
+		Expect(connectivityTestDue(ct, &operator.ConnectivityTestStatus{Trigger: "1"}, "")).To(BeFalse())
+		Expect(connectivityTestDue(ct, &operator.ConnectivityTestStatus{Trigger: "0"}, "")).To(BeTrue())
+	})
+
+	It("should start a test after an upgrade if enabled", func() {
+		ct := &operator.ConnectivityTest{}
+		Expect(connectivityTestDue(ct, nil, "v0.0.0-old")).To(BeFalse())
+
+		enabled := operator.ConnectivityTestAfterUpgradeEnabled
+		ct.AfterUpgrade = &enabled
+		Expect(connectivityTestDue(ct, nil, "")).To(BeFalse())
+		Expect(connectivityTestDue(ct, nil, version.VERSION)).To(BeFalse())
+		Expect(connectivityTestDue(ct, nil, "v0.0.0-old")).To(BeTrue())
+		Expect(connectivityTestDue(ct, &operator.ConnectivityTestStatus{OperatorVersion: version.VERSION}, "v0.0.0-old")).To(BeFalse())
+	})
+
+	It("should verify a migrated node once no test is running", func() {
+		requested := time.Now()
+		Expect(migrationConnectivityTestDue(nil, "node-0", requested)).To(BeTrue())
+
+		before := metav1.NewTime(requested.Add(-time.Minute))
+		after := metav1.NewTime(requested.Add(time.Second))
+		Expect(migrationConnectivityTestDue(&operator.ConnectivityTestStatus{State: operator.ConnectivityTestRunning, StartTime: &before}, "node-0", requested)).To(BeFalse())
+		Expect(migrationConnectivityTestDue(&operator.ConnectivityTestStatus{State: operator.ConnectivityTestSucceeded, StartTime: &after}, "node-0", requested)).To(BeTrue())
+		Expect(migrationConnectivityTestDue(&operator.ConnectivityTestStatus{State: operator.ConnectivityTestFailed, StartTime: &before, Node: "node-0"}, "node-0", requested)).To(BeTrue())
+		Expect(migrationConnectivityTestDue(&operator.ConnectivityTestStatus{State: operator.ConnectivityTestFailed, StartTime: &after, Node: "node-0"}, "node-0", requested)).To(BeFalse())
+	})
+
+	Context("result", func() {
+		var ctx context.Context
+		var scheme *runtime.Scheme
+		var st *operator.ConnectivityTestStatus
+
+		job := func(run string, cond batchv1.JobConditionType) *batchv1.Job {
+			j := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: render.ConnectivityTestName, Namespace: common.CalicoNamespace}}
+			j.Spec.Template.Annotations = map[string]string{render.ConnectivityTestRunAnnotation: run}
+			if cond != "" {
+				j.Status.Conditions = []batchv1.JobCondition{{Type: cond, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"}}
+			}
+			return j
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			scheme = runtime.NewScheme()
+			Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+			Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+			Expect(batchv1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+			st = &operator.ConnectivityTestStatus{State: operator.ConnectivityTestRunning}
+		})
+
+		It("should wait for the job of the run", func() {
+			c := fake.NewFakeClientWithScheme(scheme)
+			Expect(connectivityTestResult(ctx, c, "2", st)).To(BeFalse())
+
+			c = fake.NewFakeClientWithScheme(scheme, job("1", batchv1.JobComplete))
+			Expect(connectivityTestResult(ctx, c, "2", st)).To(BeFalse())
+
+			c = fake.NewFakeClientWithScheme(scheme, job("2", ""))
+			Expect(connectivityTestResult(ctx, c, "2", st)).To(BeFalse())
+			Expect(st.State).To(Equal(operator.ConnectivityTestRunning))
+		})
+
+		It("should record the success of the test", func() {
+			c := fake.NewFakeClientWithScheme(scheme, job("2", batchv1.JobComplete))
+			Expect(connectivityTestResult(ctx, c, "2", st)).To(BeTrue())
+			Expect(st.State).To(Equal(operator.ConnectivityTestSucceeded))
+			Expect(st.CompletionTime).NotTo(BeNil())
+		})
+
+		It("should record the failed checks", func() {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      render.ConnectivityTestName + "-abcde",
+					Namespace: common.CalicoNamespace,
+					Labels:    map[string]string{"job-name": render.ConnectivityTestName},
+				},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  "connectivity-test",
+						State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "pod 10.0.0.1: timeout"}},
+					}},
+				},
+			}
+			c := fake.NewFakeClientWithScheme(scheme, job("2", batchv1.JobFailed), pod)
+			Expect(connectivityTestResult(ctx, c, "2", st)).To(BeTrue())
+			Expect(st.State).To(Equal(operator.ConnectivityTestFailed))
+			Expect(st.Message).To(Equal("pod 10.0.0.1: timeout"))
+
+			// The condition of the job is reported when the pod is gone.
+			st.State = operator.ConnectivityTestRunning
+			c = fake.NewFakeClientWithScheme(scheme, job("2", batchv1.JobFailed))
+			Expect(connectivityTestResult(ctx, c, "2", st)).To(BeTrue())
+			Expect(st.Message).To(Equal("Job has reached the specified backoff limit"))
+		})
+
+		It("should fail the test when it doesn't complete in time", func() {
+			start := time.Date(2021, 11, 2, 10, 0, 0, 0, time.UTC)
+			st.StartTime = &metav1.Time{Time: start}
+			Expect(connectivityTestTimedOut(st, start.Add(9*time.Minute))).To(BeFalse())
+			Expect(st.State).To(Equal(operator.ConnectivityTestRunning))
+
+			Expect(connectivityTestTimedOut(st, start.Add(10*time.Minute))).To(BeTrue())
+			Expect(st.State).To(Equal(operator.ConnectivityTestFailed))
+			Expect(st.Message).To(Equal("the test didn't complete within 10m0s"))
+			Expect(st.CompletionTime.Time).To(Equal(start.Add(10 * time.Minute)))
+		})
+	})
+})
@@ -50,9 +50,9 @@ import (
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/render"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
-	"github.com/tigera/operator/pkg/render/common/resourcequota"
 	"github.com/tigera/operator/pkg/render/common/secret"
 	"github.com/tigera/operator/pkg/tls"
+	"github.com/tigera/operator/version"
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/library-go/pkg/crypto"
@@ -116,8 +116,8 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) (*ReconcileInst
 		return nil, err
 	}
 
-	// Create the SharedIndexInformer used by the typhaAutoscaler and
-	// calicoWindowsUpgrader.
+	// Create the SharedIndexInformer used by the typhaAutoscaler, the
+	// calicoWindowsUpgrader, the node rollout and the BGP configuration.
 	nodeListWatch := cache.NewListWatchFromClient(cs.CoreV1().RESTClient(), "nodes", "", fields.Everything())
 	nodeIndexInformer := cache.NewSharedIndexInformer(nodeListWatch, &corev1.Node{}, 0, cache.Indexers{})
 	go nodeIndexInformer.Run(opts.ShutdownContext.Done())
@@ -129,39 +129,52 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) (*ReconcileInst
 	// Create a Calico Windows upgrader.
 	calicoWindowsUpgrader := windows.NewCalicoWindowsUpgrader(cs, mgr.GetClient(), nodeIndexInformer, statusManager)
 
+	// Create the node rollout, used for the canary rollouts of calico-node.
+	nodeRollout := newNodeRollout(cs, nodeIndexInformer)
+
+	// Create the collector of the encryption status of the traffic between the nodes.
+	encryptionStatus := newEncryptionStatusCollector(mgr.GetClient(), statusManager)
+
 	r := &ReconcileInstallation{
 		config:                mgr.GetConfig(),
 		client:                mgr.GetClient(),
+		clientset:             cs,
 		scheme:                mgr.GetScheme(),
 		watches:               make(map[runtime.Object]struct{}),
 		autoDetectedProvider:  opts.DetectedProvider,
 		status:                statusManager,
 		typhaAutoscaler:       typhaScaler,
 		calicoWindowsUpgrader: calicoWindowsUpgrader,
+		nodeRollout:           nodeRollout,
+		encryptionStatus:      encryptionStatus,
+		nodeIndexInformer:     nodeIndexInformer,
 		namespaceMigration:    nm,
 		amazonCRDExists:       opts.AmazonCRDExists,
 		enterpriseCRDsExist:   opts.EnterpriseCRDExists,
 		clusterDomain:         opts.ClusterDomain,
 		manageCRDs:            opts.ManageCRDs,
+		kubernetesVersion:     opts.KubernetesVersion,
 	}
 	r.status.Run(opts.ShutdownContext)
 	r.typhaAutoscaler.start(opts.ShutdownContext)
 	r.calicoWindowsUpgrader.Start(opts.ShutdownContext)
+	r.nodeRollout.start(opts.ShutdownContext)
+	r.encryptionStatus.start(opts.ShutdownContext)
 	return r, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r *ReconcileInstallation) error {
 	// Create a new controller
-	c, err := controller.New("tigera-installation-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("tigera-installation-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("tigera-installation-controller", r)})
 	if err != nil {
 		return fmt.Errorf("Failed to create tigera-installation-controller: %w", err)
 	}
 
 	r.controller = c
 
-	// Watch for changes to primary resource Installation
-	err = c.Watch(&source.Kind{Type: &operator.Installation{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to primary resource Installation. Status only updates are ignored.
+	err = c.Watch(&source.Kind{Type: &operator.Installation{}}, &handler.EnqueueRequestForObject{}, utils.PrimaryResourceChangedPredicate())
 	if err != nil {
 		return fmt.Errorf("tigera-installation-controller failed to watch primary resource: %w", err)
 	}
@@ -191,6 +204,11 @@ func add(mgr manager.Manager, r *ReconcileInstallation) error {
 		}
 	}
 
+	// The overrides of the VPP startup configuration are in ConfigMaps with arbitrary names.
+	if err = utils.AddConfigMapWatch(c, "", common.OperatorNamespace()); err != nil {
+		return fmt.Errorf("tigera-installation-controller failed to watch ConfigMaps: %w", err)
+	}
+
 	if err = utils.AddConfigMapWatch(c, active.ActiveConfigMapName, common.CalicoNamespace); err != nil {
 		return fmt.Errorf("tigera-installation-controller failed to watch ConfigMap %s: %w", active.ActiveConfigMapName, err)
 	}
@@ -233,6 +251,28 @@ func add(mgr manager.Manager, r *ReconcileInstallation) error {
 		}
 	}
 
+	// Watch for changes to the nodes which may change the route reflectors or the generated BGP peers. The nodes
+	// are watched through the informer of the typha autoscaler, rather than through another cache of all the nodes.
+	err = c.Watch(&source.Informer{Informer: r.nodeIndexInformer}, handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: utils.DefaultInstanceKey}}
+	}), nodeBGPPredicate)
+	if err != nil {
+		return fmt.Errorf("tigera-installation-controller failed to watch nodes: %w", err)
+	}
+
+	// Watch for changes to the VPPDataplane, which reserves the hugepages of the VPP nodes instead of node-init.
+	err = c.Watch(&source.Kind{Type: &operator.VPPDataplane{}}, handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: utils.DefaultInstanceKey}}
+	}))
+	if err != nil {
+		return fmt.Errorf("tigera-installation-controller failed to watch VPPDataplane: %w", err)
+	}
+
+	// Re-render the components when the cluster is upgraded, since their rendering depends on its version.
+	if err = utils.AddKubernetesVersionWatch(c, r.kubernetesVersion, utils.DefaultInstanceKey); err != nil {
+		return fmt.Errorf("tigera-installation-controller failed to watch the Kubernetes version: %w", err)
+	}
+
 	// Watch for changes to KubeControllersConfiguration.
 	err = c.Watch(&source.Kind{Type: &crdv1.KubeControllersConfiguration{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
@@ -307,6 +347,7 @@ type ReconcileInstallation struct {
 	// that reads objects from the cache and writes to the apiserver
 	config                *rest.Config
 	client                client.Client
+	clientset             kubernetes.Interface
 	scheme                *runtime.Scheme
 	controller            controller.Controller
 	watches               map[runtime.Object]struct{}
@@ -314,12 +355,16 @@ type ReconcileInstallation struct {
 	status                status.StatusManager
 	typhaAutoscaler       *typhaAutoscaler
 	calicoWindowsUpgrader windows.CalicoWindowsUpgrader
+	nodeRollout           *nodeRollout
+	encryptionStatus      *encryptionStatusCollector
+	nodeIndexInformer     cache.SharedIndexInformer
 	namespaceMigration    migration.NamespaceMigration
 	enterpriseCRDsExist   bool
 	amazonCRDExists       bool
 	migrationChecked      bool
 	clusterDomain         string
 	manageCRDs            bool
+	kubernetesVersion     *common.KubernetesVersionTracker
 }
 
 // updateInstallationWithDefaults returns the default installation instance with defaults populated.
@@ -411,6 +456,12 @@ func fillDefaults(instance *operator.Installation) error {
 		instance.Spec.NonPrivileged = &npd
 	}
 
+	// Default to reporting usage, so that the computed installation in the status shows whether it is reported.
+	if instance.Spec.UsageReporting == nil {
+		ur := operator.UsageReportingEnabled
+		instance.Spec.UsageReporting = &ur
+	}
+
 	// Default the CNI plugin based on the Kubernetes provider.
 	if instance.Spec.CNI == nil {
 		instance.Spec.CNI = &operator.CNISpec{}
@@ -528,6 +579,43 @@ func fillDefaults(instance *operator.Installation) error {
 		}
 	}
 
+	// Default the ports and the overheads of the VPP tunnels.
+	for i := range instance.Spec.CalicoNetwork.VPPTunnels {
+		t := &instance.Spec.CalicoNetwork.VPPTunnels[i]
+		var port, overhead int32
+		switch t.Type {
+		case operator.VPPTunnelGTPU:
+			// Outer IPv4 (20) + UDP (8) + GTP-U (8) headers.
+			port, overhead = 2152, 36
+		case operator.VPPTunnelGENEVE:
+			// Outer IPv4 (20) + UDP (8) + GENEVE (8) headers, and the inner Ethernet header (14).
+			port, overhead = 6081, 50
+		default:
+			continue
+		}
+		if t.Port == nil {
+			t.Port = &port
+		}
+		if t.Overhead == nil {
+			t.Overhead = &overhead
+		}
+	}
+
+	if nat64 := instance.Spec.CalicoNetwork.VPPNAT64; nat64 != nil && nat64.Prefix == "" {
+		nat64.Prefix = render.DefaultVPPNAT64Prefix
+	}
+
+	// Default the route reflectors, if requested.
+	if rr := instance.Spec.CalicoNetwork.RouteReflectors; rr != nil {
+		if rr.Replicas == nil {
+			var three int32 = 3
+			rr.Replicas = &three
+		}
+		if rr.ClusterID == "" {
+			rr.ClusterID = render.DefaultRouteReflectorClusterID
+		}
+	}
+
 	needIPv4Autodetection := false
 	if *instance.Spec.CalicoNetwork.LinuxDataplane == operator.LinuxDataplaneBPF {
 		// BPF dataplane requires IP autodetection even if we're not using Calico IPAM.
@@ -540,7 +628,7 @@ func fillDefaults(instance *operator.Installation) error {
 
 	if v4pool != nil {
 		if v4pool.Encapsulation == "" {
-			if instance.Spec.CNI.Type == operator.PluginCalico {
+			if instance.Spec.CNI.Type == operator.PluginCalico && v4pool.VPPTunnel == "" {
 				v4pool.Encapsulation = operator.EncapsulationIPIP
 			} else {
 				v4pool.Encapsulation = operator.EncapsulationNone
@@ -559,15 +647,24 @@ func fillDefaults(instance *operator.Installation) error {
 		needIPv4Autodetection = true
 	}
 
+	// VPP takes over the uplink of the node and replaces it with an interface of its own, which the well-known
+	// interface names of first found don't account for. The internal IP of the node is the address of the uplink.
+	vppDataplane := *instance.Spec.CalicoNetwork.LinuxDataplane == operator.LinuxDataplaneVPP
+	nodeInternalIP := operator.NodeInternalIP
+
 	if needIPv4Autodetection && instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 == nil {
-		switch instance.Spec.KubernetesProvider {
-		case operator.ProviderDockerEE:
+		switch {
+		case vppDataplane:
+			instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 = &operator.NodeAddressAutodetection{
+				Kubernetes: &nodeInternalIP,
+			}
+		case instance.Spec.KubernetesProvider == operator.ProviderDockerEE:
 			// firstFound finds the Docker Enterprise interface prefixed with br-, which is unusable for the
 			// node address, so instead skip the interface br-.
 			instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 = &operator.NodeAddressAutodetection{
 				SkipInterface: "^br-.*",
 			}
-		case operator.ProviderEKS:
+		case instance.Spec.KubernetesProvider == operator.ProviderEKS:
 			// EKS uses multiple interfaces to spread load; we want to pick the main interface with the
 			// default route.
 			instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 = &operator.NodeAddressAutodetection{
@@ -593,10 +690,16 @@ func fillDefaults(instance *operator.Installation) error {
 			v6pool.NodeSelector = operator.NodeSelectorDefault
 		}
 		if instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 == nil {
-			// Default IPv6 address detection to "first found" if not specified.
-			t := true
-			instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 = &operator.NodeAddressAutodetection{
-				FirstFound: &t,
+			if vppDataplane {
+				instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 = &operator.NodeAddressAutodetection{
+					Kubernetes: &nodeInternalIP,
+				}
+			} else {
+				// Default IPv6 address detection to "first found" if not specified.
+				t := true
+				instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6 = &operator.NodeAddressAutodetection{
+					FirstFound: &t,
+				}
 			}
 		}
 		if v6pool.BlockSize == nil {
@@ -624,6 +727,12 @@ func fillDefaults(instance *operator.Installation) error {
 			mm := operator.MultiInterfaceModeNone
 			instance.Spec.CalicoNetwork.MultiInterfaceMode = &mm
 		}
+	} else if instance.Spec.CNI.Chaining != nil && *instance.Spec.CNI.Chaining == operator.CNIChainingEnabled {
+		// The portmap plugin is chained with the provider CNI plugin, so host ports are supported.
+		if instance.Spec.CalicoNetwork.HostPorts == nil && *instance.Spec.CalicoNetwork.LinuxDataplane != operator.LinuxDataplaneBPF {
+			hp := operator.HostPortsEnabled
+			instance.Spec.CalicoNetwork.HostPorts = &hp
+		}
 	}
 
 	// If not specified by the user, set the default control plane replicas to 2.
@@ -699,18 +808,32 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	if err := r.client.Get(ctx, utils.DefaultInstanceKey, instance); err != nil {
 		if apierrors.IsNotFound(err) {
 			reqLogger.Info("Installation config not found")
+			status.SetRolloutWatchdog(nil)
 			r.status.OnCRNotFound()
 			return reconcile.Result{}, nil
 		}
 		reqLogger.Error(err, "An error occurred when querying the Installation resource")
 		return reconcile.Result{}, err
 	}
+	// The rollout watchdog applies to the status managers of all the controllers.
+	status.SetRolloutWatchdog(status.NewRolloutWatchdog(instance.Spec.RolloutWatchdog))
 	status := instance.Status
 	preDefaultPatchFrom := client.MergeFrom(instance.DeepCopy())
 
 	// Mark CR found so we can report converter problems via tigerastatus
 	r.status.OnCRFound()
 
+	// Roll back to a known-good revision. Updating the Installation triggers a reconcile of the spec of the revision.
+	if instance.Spec.RollbackTo != nil {
+		revision, err := rollbackInstallation(ctx, r.client, instance)
+		if err != nil {
+			r.SetDegraded("Unable to roll back the Installation", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		reqLogger.Info("Rolled back the Installation", "revision", revision)
+		return reconcile.Result{}, nil
+	}
+
 	if !r.migrationChecked {
 		// update Installation resource with existing install if it exists.
 		nc, err := convert.NeedsConversion(ctx, r.client)
@@ -756,6 +879,8 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		r.SetDegraded("Failed to write defaults", err, reqLogger)
 		return reconcile.Result{}, err
 	}
+	// The base installation is the spec recorded in the revisions, which the overlay is applied to.
+	baseSpec := instance.Spec.DeepCopy()
 
 	// update Installation with 'overlay'
 	overlay := operator.Installation{}
@@ -776,6 +901,41 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
+	// The version of the cluster changes when it is upgraded, so it is read on every reconcile.
+	kubernetesVersion := r.kubernetesVersion.Get()
+	if kubernetesVersion != nil && render.CSIDriverEnabled(&instance.Spec) && !kubernetesVersion.ProvidesCSIDriverV1API() {
+		err := fmt.Errorf("the CSI policy sync driver requires Kubernetes v1.18 or later")
+		r.SetDegraded("Unsupported Kubernetes version", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
+	// Check that the components can be rolled before changing anything.
+	if err := r.runPreflightChecks(ctx, instance, status); err != nil {
+		r.SetDegraded("Unsupported upgrade", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
+	// Check the advertised service cluster IPs against the service cluster IP range of the cluster.
+	if instance.Spec.CalicoNetwork != nil && instance.Spec.CalicoNetwork.ServiceAdvertisement != nil {
+		clusterCIDRs, err := clusterServiceCIDRs(ctx, r.client)
+		if err != nil {
+			r.SetDegraded("Unable to determine the service cluster IP range", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		if err := validateServiceClusterIPs(instance.Spec.CalicoNetwork.ServiceAdvertisement.ServiceClusterIPs, clusterCIDRs); err != nil {
+			r.SetDegraded("Invalid service advertisement", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Check the IP pools against the service cluster IP range and the addresses of the nodes when they change.
+	if ipPoolsChanged(status.Computed, &instance.Spec) {
+		if err := checkIPPoolConflicts(ctx, r.client, instance.Spec.CalicoNetwork); err != nil {
+			r.SetDegraded("IP pool conflicts with the cluster network", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+	}
+
 	if err = r.updateCRDs(ctx, instance.Spec.Variant, reqLogger); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -783,6 +943,8 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	// Update calicoWindowsUpgrader with the installation it needs to
 	// process Calico Windows upgrades.
 	r.calicoWindowsUpgrader.UpdateConfig(&instance.Spec)
+	r.nodeRollout.updateConfig(&instance.Spec)
+	r.encryptionStatus.updateConfig(&instance.Spec)
 
 	// now that migrated config is stored in the installation resource, we no longer need
 	// to check if a migration is needed for the lifetime of the operator.
@@ -851,6 +1013,34 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
+	// Update the copies of the pull secrets in the namespaces of the other components if they were rotated.
+	if err = r.rotatePullSecrets(ctx, pullSecrets, reqLogger); err != nil {
+		r.SetDegraded("Error propagating the pull secrets", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
+	// Query for the BGP password of the route reflectors in the operator namespace.
+	var bgpPasswordSecret *corev1.Secret
+	if cn := instance.Spec.CalicoNetwork; cn != nil && cn.RouteReflectors != nil && cn.RouteReflectors.Password != nil {
+		password := cn.RouteReflectors.Password
+		bgpPasswordSecret, err = utils.GetSecret(ctx, r.client, password.Name, common.OperatorNamespace())
+		if err != nil {
+			r.SetDegraded("Error retrieving the BGP password Secret", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		if bgpPasswordSecret == nil || len(bgpPasswordSecret.Data[password.Key]) == 0 {
+			r.status.SetDegraded("Waiting for the BGP password Secret",
+				fmt.Sprintf("key %s of Secret %s/%s not found", password.Key, common.OperatorNamespace(), password.Name))
+			return reconcile.Result{}, nil
+		}
+	}
+	// The copies of the BGP password Secrets, so that the copy of a Secret which is no longer referenced is deleted.
+	bgpPasswordCopies := &corev1.SecretList{}
+	if err := r.client.List(ctx, bgpPasswordCopies, client.InNamespace(common.CalicoNamespace), client.HasLabels{render.RouteReflectorPasswordLabel}); err != nil {
+		r.SetDegraded("Error listing the copies of the BGP password Secret", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	var managementCluster *operator.ManagementCluster
 	var managementClusterConnection *operator.ManagementClusterConnection
 	var logCollector *operator.LogCollector
@@ -957,6 +1147,14 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		objs = append(objs, typhaNodeTLS.CAConfigMap)
 	}
 
+	// Felix verifies Goldmane, which it reports the flows to, with the Typha CA bundle, so the operator CA signing the
+	// certificate of Goldmane is added to the bundle rendered for Felix and Typha.
+	typhaNodeTLS, err = r.trustOperatorCA(ctx, typhaNodeTLS)
+	if err != nil {
+		r.SetDegraded("Error reading the operator CA", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	birdTemplates, err := getBirdTemplates(r.client)
 	if err != nil {
 		log.Error(err, "Error retrieving confd templates")
@@ -987,6 +1185,13 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
+	// The core components may reach the API server through an endpoint of their own, e.g. when the Service of the API
+	// server is not usable before the dataplane is up.
+	k8sServiceEp := k8sapi.Endpoint
+	if ep := instance.Spec.KubernetesServiceEndpoint; ep != nil {
+		k8sServiceEp = k8sapi.ServiceEndpoint{Host: ep.Host, Port: strconv.Itoa(int(ep.Port))}
+	}
+
 	openShiftOnAws := false
 	if instance.Spec.KubernetesProvider == operator.ProviderOpenShift {
 		openShiftOnAws, err = isOpenshiftOnAws(instance, ctx, r.client)
@@ -1065,6 +1270,17 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		kubeControllersMetricsPort = *kubeControllersConfig.Spec.PrometheusMetricsPort
 	}
 
+	// node-init doesn't reserve the hugepages of the VPP nodes when the VPPDataplane does.
+	vppHugepagesReserved := false
+	if isVPPDataplane(&instance.Spec) {
+		vpp, err := utils.GetVPPDataplane(ctx, r.client)
+		if err != nil {
+			r.SetDegraded("Error reading VPPDataplane", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		vppHugepagesReserved = vpp != nil && vpp.Spec.Hugepages != nil
+	}
+
 	nodeAppArmorProfile := ""
 	a := instance.GetObjectMeta().GetAnnotations()
 	if val, ok := a[techPreviewFeatureSeccompApparmor]; ok {
@@ -1110,33 +1326,26 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
-	if instance.Spec.KubernetesProvider == operator.ProviderGKE {
-		// We do this only for GKE as other providers don't (yet?)
-		// automatically add resource quota that constrains whether
-		// Calico components that are marked cluster or node critical
-		// can be scheduled.
-		criticalPriorityClasses := []string{render.NodePriorityClassName, render.ClusterPriorityClassName}
-		resourceQuotaObj := resourcequota.ResourceQuotaForPriorityClassScope(resourcequota.CalicoCriticalResourceQuotaName,
-			common.CalicoNamespace, criticalPriorityClasses)
-		resourceQuotaComponent := render.NewPassthrough(resourceQuotaObj)
-		components = append(components, resourceQuotaComponent)
-
-	}
+	// The ResourceQuota is rendered for GKE even when the namespace quotas are disabled, as other providers don't
+	// (yet?) automatically add resource quota that constrains whether Calico components that are marked cluster or
+	// node critical can be scheduled.
+	components = append(components, render.CalicoNamespaceQuotas(&instance.Spec))
 
 	// Build a configuration for rendering calico/typha.
 	typhaCfg := render.TyphaConfiguration{
-		K8sServiceEp:           k8sapi.Endpoint,
+		K8sServiceEp:           k8sServiceEp,
 		Installation:           &instance.Spec,
 		TLS:                    typhaNodeTLS,
 		AmazonCloudIntegration: aci,
 		MigrateNamespaces:      needNsMigration,
 		ClusterDomain:          r.clusterDomain,
+		KubernetesVersion:      kubernetesVersion,
 	}
 	components = append(components, render.Typha(&typhaCfg))
 
 	// Build a configuration for rendering calico/node.
 	nodeCfg := render.NodeConfiguration{
-		K8sServiceEp:            k8sapi.Endpoint,
+		K8sServiceEp:            k8sServiceEp,
 		Installation:            &instance.Spec,
 		AmazonCloudIntegration:  aci,
 		LogCollector:            logCollector,
@@ -1147,23 +1356,57 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		BGPLayouts:              bgpLayout,
 		NodeAppArmorProfile:     nodeAppArmorProfile,
 		MigrateNamespaces:       needNsMigration,
+		KubernetesVersion:       kubernetesVersion,
+		VPPHugepagesReserved:    vppHugepagesReserved,
 	}
-	components = append(components, render.Node(&nodeCfg))
+	nodeComponent := render.Node(&nodeCfg)
+	components = append(components, nodeComponent)
 
 	// Build a configuration for rendering calico/kube-controllers.
 	kubeControllersCfg := kubecontrollers.KubeControllersConfiguration{
-		K8sServiceEp:                k8sapi.Endpoint,
+		K8sServiceEp:                k8sServiceEp,
 		Installation:                &instance.Spec,
 		ManagementCluster:           managementCluster,
 		ManagementClusterConnection: managementClusterConnection,
 		ClusterDomain:               r.clusterDomain,
 		MetricsPort:                 kubeControllersMetricsPort,
+		Configuration:               kubeControllersConfig,
 		ManagerInternalSecret:       managerInternalTLSSecret,
+		KubernetesVersion:           kubernetesVersion,
 	}
 	components = append(components, kubecontrollers.NewCalicoKubeControllers(&kubeControllersCfg))
 
 	components = append(components, render.Windows(&instance.Spec))
 
+	components = append(components, render.CSI(&instance.Spec, kubernetesVersion))
+
+	components = append(components, render.AWSSecondaryIPPools(&instance.Spec))
+
+	vppProfilesCfg, err := r.vppProfilesConfiguration(ctx, &instance.Spec)
+	if err != nil {
+		r.SetDegraded("Error querying the VPP profiles configuration", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	vppProfilesCfg.LogCollector = logCollector
+	components = append(components, render.VPPProfiles(vppProfilesCfg))
+
+	var routeReflectors *operator.RouteReflectors
+	if instance.Spec.CalicoNetwork != nil {
+		routeReflectors = instance.Spec.CalicoNetwork.RouteReflectors
+	}
+	bgpPeeringTemplatesCfg, err := r.bgpPeeringTemplatesConfiguration(ctx, &instance.Spec)
+	if err != nil {
+		r.SetDegraded("Error querying the BGP peering templates configuration", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	components = append(components, render.BGPPeeringTemplates(bgpPeeringTemplatesCfg))
+
+	components = append(components, render.RouteReflectors(&render.RouteReflectorsConfiguration{
+		Installation:   &instance.Spec,
+		PasswordSecret: bgpPasswordSecret,
+		PasswordCopies: bgpPasswordCopies.Items,
+	}))
+
 	imageSet, err := imageset.GetImageSet(ctx, r.client, instance.Spec.Variant)
 	if err != nil {
 		r.SetDegraded("Error getting ImageSet", err, reqLogger)
@@ -1180,19 +1423,62 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
+	// Restore the node-to-node mesh before the route reflector peering is removed.
+	if routeReflectors == nil {
+		if err = r.restoreNodeToNodeMesh(ctx, reqLogger); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	// Create a component handler to create or update the rendered components.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetHardening(instance.Spec.Hardening)
+	nodeDeferred, typhaStalled := false, false
 	for _, component := range components {
+		// Typha is handled before calico-node, so calico-node is only updated once the updated typha is ready.
+		if component == nodeComponent {
+			if nodeDeferred, typhaStalled, err = deferNodeRollout(ctx, r.clientset); err != nil {
+				r.SetDegraded("Error checking the typha rollout", err, reqLogger)
+				return reconcile.Result{}, err
+			}
+			if nodeDeferred {
+				reqLogger.Info("Waiting for the typha rollout before updating calico-node")
+				continue
+			}
+		}
 		if err := handler.CreateOrUpdateOrDelete(ctx, component, nil); err != nil {
 			r.SetDegraded("Error creating / updating resource", err, reqLogger)
 			return reconcile.Result{}, err
 		}
 	}
 
+	// Select the route reflectors once the nodes peer with them, and only then disable the node-to-node mesh.
+	if err = r.reconcileRouteReflectorNodes(ctx, routeReflectors, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err = r.updateBGPConfiguration(ctx, instance.Spec.CalicoNetwork, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Label the nodes with their VPP profile once the ConfigMaps of the profiles exist.
+	var vppProfiles []operator.VPPProfile
+	if instance.Spec.CalicoNetwork != nil {
+		vppProfiles = instance.Spec.CalicoNetwork.VPPProfiles
+	}
+	if err = r.reconcileVPPProfileNodes(ctx, vppProfiles, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// TODO: We handle too many components in this controller at the moment. Once we are done consolidating,
 	// we can have the CreateOrUpdate logic handle this for us.
 	r.status.AddDaemonsets([]types.NamespacedName{{Name: "calico-node", Namespace: "calico-system"}})
 	r.status.AddDeployments([]types.NamespacedName{{Name: "calico-kube-controllers", Namespace: "calico-system"}})
+	csiDaemonset := types.NamespacedName{Name: render.CSIDaemonSetName, Namespace: common.CalicoNamespace}
+	if render.CSIDriverEnabled(&instance.Spec) {
+		r.status.AddDaemonsets([]types.NamespacedName{csiDaemonset})
+	} else {
+		r.status.RemoveDaemonsets(csiDaemonset)
+	}
 	if instance.Spec.CertificateManagement != nil {
 		r.status.AddCertificateSigningRequests(render.CSRLabelCalicoSystem, map[string]string{
 			"k8s-app": render.CSRLabelCalicoSystem,
@@ -1245,6 +1531,23 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	// Check whether the update of calico-node waits for typha. If so, requeue a reconcile, and report the rollout of
+	// typha if it exceeded its progress deadline.
+	if nodeDeferred {
+		if typhaStalled {
+			r.status.SetDegraded("Rollout of calico-node deferred", "The rollout of typha exceeded its progress deadline")
+			return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	// Check whether the canary rollout of calico-node, or the migration of the nodes to the VPP dataplane, is halted.
+	// If so, report it and requeue a reconcile.
+	if halted, reason := r.nodeRollout.halted(); halted {
+		r.status.SetDegraded("Rollout of calico-node halted", reason)
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
 	// We have successfully reconciled the Calico installation.
 	if instance.Spec.KubernetesProvider == operator.ProviderOpenShift {
 		openshiftConfig := &configv1.Network{}
@@ -1282,6 +1585,26 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	// Run the connectivity test once the components are available. Its state is written with the status below.
+	connectivityTestRunning, err := r.reconcileConnectivityTest(ctx, instance, imageSet, status.OperatorVersion, reqLogger)
+	if err != nil {
+		r.SetDegraded("Error running the connectivity test", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
+	nat64Status, err := vppNAT64Status(ctx, r.client, &instance.Spec)
+	if err != nil {
+		r.SetDegraded("Error querying the NAT64 status of the nodes", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
+	// The components are available, so the spec is a known-good revision to roll back to.
+	revision, err := recordInstallationRevision(ctx, r.client, baseSpec)
+	if err != nil {
+		r.SetDegraded("Error recording the revision of the Installation", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	// Write updated status.
 	instance.Status.MTU = int32(statusMTU)
 	instance.Status.Variant = instance.Spec.Variant
@@ -1291,10 +1614,18 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		instance.Status.ImageSet = imageSet.Name
 	}
 	instance.Status.Computed = &instance.Spec
+	instance.Status.OperatorVersion = version.VERSION
+	instance.Status.NAT64 = nat64Status
+	instance.Status.Revision = revision
 	if err = r.client.Status().Update(ctx, instance); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	// The nodes migrated to the VPP dataplane wait for the connectivity test to verify them.
+	if connectivityTestRunning || instance.Spec.DataplaneMigration != nil {
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
 	// Created successfully. Requeue anyway so that we perform periodic reconciliation.
 	// This acts as a backstop to catch reconcile issues, and also makes sure we spot when
 	// things change that might not trigger a reconciliation.
@@ -1427,6 +1758,10 @@ func (r *ReconcileInstallation) validateTyphaCAConfigMap() (*corev1.ConfigMap, e
 	return cm, nil
 }
 
+// awsSecondaryIPSupportAnnotation is set on the default FelixConfiguration when the operator enabled the AWS secondary
+// IP support, so that it is only disabled again if the operator enabled it.
+const awsSecondaryIPSupportAnnotation = "operator.tigera.io/aws-secondary-ip-support"
+
 // setDefaultOnFelixConfiguration will take the passed in fc and add any defaulting needed
 // based on the install config. If the FelixConfig ResourceVersion is empty,
 // then the FelixConfig default will be created, otherwise a patch will be performed.
@@ -1463,6 +1798,39 @@ func (r *ReconcileInstallation) setDefaultsOnFelixConfiguration(ctx context.Cont
 			}
 		}
 	}
+
+	// The AWS secondary IPs are configured through the installation, so keep the FelixConfiguration in sync with it.
+	if install.Spec.CalicoNetwork != nil && install.Spec.CalicoNetwork.AWSSecondaryIPs != nil {
+		aws := install.Spec.CalicoNetwork.AWSSecondaryIPs
+		mode := string(operator.AWSSecondaryIPEnabled)
+		if aws.Mode != nil {
+			mode = string(*aws.Mode)
+		}
+		if fc.Spec.AWSSecondaryIPSupport != mode {
+			updated = true
+			fc.Spec.AWSSecondaryIPSupport = mode
+		}
+		if aws.RoutingRulePriority != nil {
+			priority := int(*aws.RoutingRulePriority)
+			if fc.Spec.AWSSecondaryIPRoutingRulePriority == nil || *fc.Spec.AWSSecondaryIPRoutingRulePriority != priority {
+				updated = true
+				fc.Spec.AWSSecondaryIPRoutingRulePriority = &priority
+			}
+		}
+		if fc.Annotations[awsSecondaryIPSupportAnnotation] != "true" {
+			updated = true
+			if fc.Annotations == nil {
+				fc.Annotations = map[string]string{}
+			}
+			fc.Annotations[awsSecondaryIPSupportAnnotation] = "true"
+		}
+	} else if fc.Annotations[awsSecondaryIPSupportAnnotation] == "true" {
+		// The AWS secondary IPs were removed from the installation, so disable them again in Felix.
+		updated = true
+		fc.Spec.AWSSecondaryIPSupport = string(operator.AWSSecondaryIPDisabled)
+		fc.Spec.AWSSecondaryIPRoutingRulePriority = nil
+		delete(fc.Annotations, awsSecondaryIPSupportAnnotation)
+	}
 	if !updated {
 		return nil
 	}
@@ -1530,6 +1898,28 @@ func (r *ReconcileInstallation) updateCRDs(ctx context.Context, variant operator
 	return nil
 }
 
+// trustOperatorCA returns the given Typha and Felix TLS configuration with the certificate of the operator CA appended
+// to its CA bundle, if the operator CA exists. The CA ConfigMap of the operator namespace is left untouched.
+func (r *ReconcileInstallation) trustOperatorCA(ctx context.Context, tntls *render.TyphaNodeTLS) (*render.TyphaNodeTLS, error) {
+	caSecret, err := utils.GetSecret(ctx, r.client, utils.OperatorCASecretName, common.OperatorNamespace())
+	if err != nil || caSecret == nil {
+		return tntls, err
+	}
+	caCert := string(caSecret.Data[utils.OperatorCACertName])
+	bundle := tntls.CAConfigMap.Data[render.TyphaCABundleName]
+	if caCert == "" || strings.Contains(bundle, caCert) {
+		return tntls, nil
+	}
+
+	trusted := *tntls
+	trusted.CAConfigMap = tntls.CAConfigMap.DeepCopy()
+	if bundle != "" && !strings.HasSuffix(bundle, "\n") {
+		bundle += "\n"
+	}
+	trusted.CAConfigMap.Data[render.TyphaCABundleName] = bundle + caCert
+	return &trusted, nil
+}
+
 func CreateNewTyphaNodeTLS() (*render.TyphaNodeTLS, error) {
 	// Make CA
 	ca, err := tls.MakeCA(fmt.Sprintf("%s@%d", rmeta.TigeraOperatorCAIssuerPrefix, time.Now().Unix()))
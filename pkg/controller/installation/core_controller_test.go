@@ -25,6 +25,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	schedv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -352,12 +353,15 @@ var _ = Describe("Testing core-controller installation", func() {
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
+			mockStatus.On("AddJobs", mock.Anything)
+			mockStatus.On("IsRolloutPaused").Return(false)
 			mockStatus.On("IsAvailable").Return(true)
 			mockStatus.On("OnCRFound").Return()
 			mockStatus.On("ClearDegraded")
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("RemoveCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetPullSecretsStatus", mock.Anything, mock.Anything, mock.Anything)
 
 			// Create the indexer and informer shared by the typhaAutoscaler and
 			// calicoWindowsUpgrader.
@@ -365,7 +369,7 @@ var _ = Describe("Testing core-controller installation", func() {
 			nodeIndexInformer := cache.NewSharedIndexInformer(nlw, &corev1.Node{}, 0, cache.Indexers{})
 
 			go nodeIndexInformer.Run(ctx.Done())
-			for nodeIndexInformer.HasSynced() {
+			for !nodeIndexInformer.HasSynced() {
 				time.Sleep(100 * time.Millisecond)
 			}
 
@@ -375,11 +379,15 @@ var _ = Describe("Testing core-controller installation", func() {
 			r = ReconcileInstallation{
 				config:                nil, // there is no fake for config
 				client:                c,
+				clientset:             cs,
 				scheme:                scheme,
 				autoDetectedProvider:  operator.ProviderNone,
 				status:                mockStatus,
 				typhaAutoscaler:       newTyphaAutoscaler(cs, nodeIndexInformer, test.NewTyphaListWatch(cs), mockStatus),
 				calicoWindowsUpgrader: windows.NewCalicoWindowsUpgrader(cs, c, nodeIndexInformer, mockStatus, syncPeriodOption),
+				nodeRollout:           newNodeRollout(cs, nodeIndexInformer),
+				nodeIndexInformer:     nodeIndexInformer,
+				encryptionStatus:      newEncryptionStatusCollector(c, mockStatus),
 				namespaceMigration:    &fakeNamespaceMigration{},
 				amazonCRDExists:       true,
 				enterpriseCRDsExist:   true,
@@ -699,12 +707,15 @@ var _ = Describe("Testing core-controller installation", func() {
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
+			mockStatus.On("AddJobs", mock.Anything)
+			mockStatus.On("IsRolloutPaused").Return(false)
 			mockStatus.On("IsAvailable").Return(true)
 			mockStatus.On("OnCRFound").Return()
 			mockStatus.On("ClearDegraded")
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("RemoveCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetPullSecretsStatus", mock.Anything, mock.Anything, mock.Anything)
 			mockStatus.On("SetWindowsUpgradeStatus", mock.Anything, mock.Anything, mock.Anything, nil)
 
 			// Create the indexer and informer shared by the typhaAutoscaler and
@@ -713,7 +724,7 @@ var _ = Describe("Testing core-controller installation", func() {
 			nodeIndexInformer := cache.NewSharedIndexInformer(nlw, &corev1.Node{}, 0, cache.Indexers{})
 
 			go nodeIndexInformer.Run(ctx.Done())
-			for nodeIndexInformer.HasSynced() {
+			for !nodeIndexInformer.HasSynced() {
 				time.Sleep(100 * time.Millisecond)
 			}
 
@@ -723,11 +734,15 @@ var _ = Describe("Testing core-controller installation", func() {
 			r = ReconcileInstallation{
 				config:                nil, // there is no fake for config
 				client:                c,
+				clientset:             cs,
 				scheme:                scheme,
 				autoDetectedProvider:  operator.ProviderNone,
 				status:                mockStatus,
 				typhaAutoscaler:       newTyphaAutoscaler(cs, nodeIndexInformer, test.NewTyphaListWatch(cs), mockStatus),
 				calicoWindowsUpgrader: windows.NewCalicoWindowsUpgrader(cs, c, nodeIndexInformer, mockStatus, syncPeriodOption),
+				nodeRollout:           newNodeRollout(cs, nodeIndexInformer),
+				nodeIndexInformer:     nodeIndexInformer,
+				encryptionStatus:      newEncryptionStatusCollector(c, mockStatus),
 				namespaceMigration:    &fakeNamespaceMigration{},
 				amazonCRDExists:       true,
 				enterpriseCRDsExist:   true,
@@ -822,6 +837,23 @@ var _ = Describe("Testing core-controller installation", func() {
 			Expect(secret.GetOwnerReferences()).To(HaveLen(1))
 		})
 
+		It("should add the operator CA to the Typha CA bundle of Felix", func() {
+			_, caSecret, err := utils.GetOrCreateOperatorCA(ctx, c)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(c.Create(ctx, caSecret)).NotTo(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			operatorCM := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: render.TyphaCAConfigMapName, Namespace: common.OperatorNamespace()}, operatorCM)).ShouldNot(HaveOccurred())
+			Expect(operatorCM.Data[render.TyphaCABundleName]).NotTo(ContainSubstring(string(caSecret.Data[utils.OperatorCACertName])))
+
+			cm := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: render.TyphaCAConfigMapName, Namespace: common.CalicoNamespace}, cm)).ShouldNot(HaveOccurred())
+			Expect(cm.Data[render.TyphaCABundleName]).To(Equal(operatorCM.Data[render.TyphaCABundleName] + string(caSecret.Data[utils.OperatorCACertName])))
+		})
+
 		It("should not add OwnerReference to user supplied node and typha certs", func() {
 
 			testCA := test.MakeTestCA("core-test")
@@ -936,6 +968,7 @@ var _ = Describe("Testing core-controller installation", func() {
 			mockStatus.On("ClearDegraded")
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetPullSecretsStatus", mock.Anything, mock.Anything, mock.Anything)
 
 			// Create the indexer and informer shared by the typhaAutoscaler and
 			// calicoWindowsUpgrader.
@@ -944,7 +977,7 @@ var _ = Describe("Testing core-controller installation", func() {
 			nodeIndexInformer := cache.NewSharedIndexInformer(nlw, &corev1.Node{}, 0, cache.Indexers{})
 
 			go nodeIndexInformer.Run(ctx.Done())
-			for nodeIndexInformer.HasSynced() {
+			for !nodeIndexInformer.HasSynced() {
 				time.Sleep(100 * time.Millisecond)
 			}
 
@@ -954,11 +987,15 @@ var _ = Describe("Testing core-controller installation", func() {
 			r = ReconcileInstallation{
 				config:                nil, // there is no fake for config
 				client:                c,
+				clientset:             cs,
 				scheme:                scheme,
 				autoDetectedProvider:  operator.ProviderNone,
 				status:                mockStatus,
 				typhaAutoscaler:       newTyphaAutoscaler(cs, nodeIndexInformer, test.NewTyphaListWatch(cs), mockStatus),
 				calicoWindowsUpgrader: windows.NewCalicoWindowsUpgrader(cs, c, nodeIndexInformer, mockStatus, syncPeriodOption),
+				nodeRollout:           newNodeRollout(cs, nodeIndexInformer),
+				nodeIndexInformer:     nodeIndexInformer,
+				encryptionStatus:      newEncryptionStatusCollector(c, mockStatus),
 				namespaceMigration:    &fakeNamespaceMigration{},
 				amazonCRDExists:       true,
 				enterpriseCRDsExist:   true,
@@ -1069,6 +1106,121 @@ var _ = Describe("Testing core-controller installation", func() {
 			Expect(*fc.Spec.RouteTableRange).To(Equal(crdv1.RouteTableRange{Min: 65, Max: 99}))
 			Expect(fc.Spec.LogSeverityScreen).To(Equal("Error"))
 		})
+		It("should Reconcile with AWS secondary IPs", func() {
+			priority := int32(200)
+			cr.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{
+				AWSSecondaryIPs: &operator.AWSSecondaryIPs{
+					RoutingRulePriority: &priority,
+					SubnetPools: []operator.AWSSubnetPool{
+						{Name: "egress-a", CIDR: "10.0.16.0/24", SubnetID: "subnet-0123456789abcdef0"},
+					},
+				},
+			}
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// Check that Felix provisions the secondary IPs.
+			fc := &crdv1.FelixConfiguration{}
+			err = c.Get(ctx, types.NamespacedName{Name: "default"}, fc)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fc.Spec.AWSSecondaryIPSupport).To(Equal("Enabled"))
+			Expect(fc.Spec.AWSSecondaryIPRoutingRulePriority).NotTo(BeNil())
+			Expect(*fc.Spec.AWSSecondaryIPRoutingRulePriority).To(Equal(200))
+
+			// Check that the IP pool of the subnet is created.
+			pool := &crdv1.IPPool{}
+			err = c.Get(ctx, types.NamespacedName{Name: "egress-a"}, pool)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pool.Spec.AWSSubnetID).To(Equal("subnet-0123456789abcdef0"))
+
+			// Check that Felix no longer provisions the secondary IPs once they are removed.
+			Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, cr)).NotTo(HaveOccurred())
+			cr.Spec.CalicoNetwork.AWSSecondaryIPs = nil
+			Expect(c.Update(ctx, cr)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			fc = &crdv1.FelixConfiguration{}
+			err = c.Get(ctx, types.NamespacedName{Name: "default"}, fc)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fc.Spec.AWSSecondaryIPSupport).To(Equal("Disabled"))
+			Expect(fc.Spec.AWSSecondaryIPRoutingRulePriority).To(BeNil())
+		})
+
+		It("should re-copy the BGP password Secret when it is rotated", func() {
+			bgp := operator.BGPEnabled
+			cr.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{
+				BGP: &bgp,
+				RouteReflectors: &operator.RouteReflectors{
+					Password: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "bgp-password"},
+						Key:                  "password",
+					},
+				},
+			}
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			source := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bgp-password", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{"password": []byte("secret")},
+			}
+			Expect(c.Create(ctx, source)).NotTo(HaveOccurred())
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			copied := &corev1.Secret{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: "bgp-password", Namespace: common.CalicoNamespace}, copied)).NotTo(HaveOccurred())
+			Expect(copied.Data["password"]).To(Equal([]byte("secret")))
+
+			// Rotating the password updates the copy.
+			source.Data["password"] = []byte("rotated")
+			Expect(c.Update(ctx, source)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(c.Get(ctx, types.NamespacedName{Name: "bgp-password", Namespace: common.CalicoNamespace}, copied)).NotTo(HaveOccurred())
+			Expect(copied.Data["password"]).To(Equal([]byte("rotated")))
+
+			// Switching to another Secret deletes the copy of the previous one.
+			Expect(c.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bgp-password-v2", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{"password": []byte("secret-v2")},
+			})).NotTo(HaveOccurred())
+			Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, cr)).NotTo(HaveOccurred())
+			cr.Spec.CalicoNetwork.RouteReflectors.Password.Name = "bgp-password-v2"
+			Expect(c.Update(ctx, cr)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(c.Get(ctx, types.NamespacedName{Name: "bgp-password-v2", Namespace: common.CalicoNamespace}, copied)).NotTo(HaveOccurred())
+			err = c.Get(ctx, types.NamespacedName{Name: "bgp-password", Namespace: common.CalicoNamespace}, &corev1.Secret{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should Reconcile with a custom Kubernetes service endpoint", func() {
+			cr.Spec.KubernetesServiceEndpoint = &operator.KubernetesServiceEndpoint{Host: "10.0.0.100", Port: 6443}
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			expectedEnv := []corev1.EnvVar{
+				{Name: "KUBERNETES_SERVICE_HOST", Value: "10.0.0.100"},
+				{Name: "KUBERNETES_SERVICE_PORT", Value: "6443"},
+			}
+
+			ds := appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace}}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(&ds), &ds)).NotTo(HaveOccurred())
+			node := test.GetContainer(ds.Spec.Template.Spec.Containers, "calico-node")
+			Expect(node).NotTo(BeNil())
+			Expect(node.Env).To(ContainElements(expectedEnv))
+
+			for _, name := range []string{common.TyphaDeploymentName, common.KubeControllersDeploymentName} {
+				d := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.CalicoNamespace}}
+				Expect(c.Get(ctx, client.ObjectKeyFromObject(&d), &d)).NotTo(HaveOccurred())
+				container := test.GetContainer(d.Spec.Template.Spec.Containers, name)
+				Expect(container).NotTo(BeNil())
+				Expect(container.Env).To(ContainElements(expectedEnv))
+			}
+		})
+
 		It("should Reconcile with GKE and create a resource quota", func() {
 			cr.Spec.KubernetesProvider = operator.ProviderGKE
 			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
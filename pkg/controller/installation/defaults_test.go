@@ -51,6 +51,7 @@ var _ = Describe("Defaulting logic tests", func() {
 		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
 		Expect(instance.Spec.NonPrivileged).NotTo(BeNil())
 		Expect(*instance.Spec.NonPrivileged).To(Equal(operator.NonPrivilegedDisabled))
+		Expect(*instance.Spec.UsageReporting).To(Equal(operator.UsageReportingEnabled))
 	})
 
 	It("should properly fill defaults on an empty TigeraSecureEnterprise instance", func() {
@@ -75,6 +76,7 @@ var _ = Describe("Defaulting logic tests", func() {
 		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
 		Expect(instance.Spec.NonPrivileged).NotTo(BeNil())
 		Expect(*instance.Spec.NonPrivileged).To(Equal(operator.NonPrivilegedDisabled))
+		Expect(*instance.Spec.UsageReporting).To(Equal(operator.UsageReportingEnabled))
 	})
 
 	It("should not override custom configuration", func() {
@@ -168,11 +170,13 @@ var _ = Describe("Defaulting logic tests", func() {
 		dpBPF := operator.LinuxDataplaneBPF
 		hpDisabled := operator.HostPortsDisabled
 		npDisabled := operator.NonPrivilegedDisabled
+		urDisabled := operator.UsageReportingDisabled
 		instance := &operator.Installation{
 			Spec: operator.InstallationSpec{
-				Variant:       operator.TigeraSecureEnterprise,
-				NonPrivileged: &npDisabled,
-				Registry:      "test-reg/",
+				Variant:        operator.TigeraSecureEnterprise,
+				NonPrivileged:  &npDisabled,
+				UsageReporting: &urDisabled,
+				Registry:       "test-reg/",
 				ImagePullSecrets: []v1.LocalObjectReference{
 					{
 						Name: "pullSecret1",
@@ -303,6 +307,46 @@ var _ = Describe("Defaulting logic tests", func() {
 		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
 	})
 
+	It("should default the node address autodetection to the internal IP of the node with VPP", func() {
+		vpp := operator.LinuxDataplaneVPP
+		instance := &operator.Installation{
+			Spec: operator.InstallationSpec{
+				CalicoNetwork: &operator.CalicoNetworkSpec{
+					LinuxDataplane: &vpp,
+					IPPools:        []operator.IPPool{{CIDR: "192.168.0.0/16"}, {CIDR: "fd00::0/64"}},
+				},
+			},
+		}
+
+		Expect(fillDefaults(instance)).NotTo(HaveOccurred())
+		internalIP := operator.NodeInternalIP
+		Expect(instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4).To(Equal(&operator.NodeAddressAutodetection{Kubernetes: &internalIP}))
+		Expect(instance.Spec.CalicoNetwork.NodeAddressAutodetectionV6).To(Equal(&operator.NodeAddressAutodetection{Kubernetes: &internalIP}))
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should default the VPP tunnels and the pools they encapsulate", func() {
+		vpp := operator.LinuxDataplaneVPP
+		mtu := int32(1400)
+		instance := &operator.Installation{
+			Spec: operator.InstallationSpec{
+				CalicoNetwork: &operator.CalicoNetworkSpec{
+					LinuxDataplane: &vpp,
+					MTU:            &mtu,
+					IPPools:        []operator.IPPool{{CIDR: "192.168.0.0/16", VPPTunnel: "gtpu"}},
+					VPPTunnels:     []operator.VPPTunnel{{Name: "gtpu", Type: operator.VPPTunnelGTPU}},
+				},
+			},
+		}
+
+		Expect(fillDefaults(instance)).NotTo(HaveOccurred())
+		Expect(instance.Spec.CalicoNetwork.IPPools[0].Encapsulation).To(Equal(operator.EncapsulationNone))
+		tunnel := instance.Spec.CalicoNetwork.VPPTunnels[0]
+		Expect(*tunnel.Port).To(Equal(int32(2152)))
+		Expect(*tunnel.Overhead).To(Equal(int32(36)))
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
 	table.DescribeTable("All pools should have all fields set from mergeAndFillDefaults function",
 		func(i *operator.Installation, on *osconfigv1.Network, kadmc *v1.ConfigMap, awsN *appsv1.DaemonSet) {
 			Expect(mergeAndFillDefaults(i, on, kadmc, nil)).To(BeNil())
@@ -0,0 +1,283 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/status"
+)
+
+var encryptionStatusLog = logf.Log.WithName("encryption_status")
+
+const (
+	defaultEncryptionStatusSyncPeriod = 60 * time.Second
+	felixMetricsScrapeTimeout         = 5 * time.Second
+
+	// A WireGuard session expires when no handshake completed for 3 minutes.
+	wireguardSessionTimeout = 180 * time.Second
+
+	wireguardHandshakeMetric = "wireguard_latest_handshake_seconds"
+	ipsecBindingsMetric      = "felix_ipsec_bindings_total"
+
+	// The number of nodes whose metrics are scraped concurrently.
+	encryptionStatusScrapeWorkers = 20
+
+	// The maximum number of nodes whose encryption status is reported.
+	maxEncryptionNodeStatuses = 100
+)
+
+// encryptionStatusCollector periodically collects the encryption status of the traffic between the nodes from the
+// Prometheus metrics of Felix, when WireGuard or IPsec is enabled in the default FelixConfiguration, and reports it
+// to the status manager.
+type encryptionStatusCollector struct {
+	client        client.Client
+	statusManager status.StatusManager
+	syncPeriod    time.Duration
+	now           func() time.Time
+	scrape        func(ctx context.Context, url string) ([]byte, error)
+
+	lock    sync.Mutex
+	install *operator.InstallationSpec
+}
+
+// newEncryptionStatusCollector creates a new encryption status collector, which collects the status every minute.
+func newEncryptionStatusCollector(cli client.Client, statusManager status.StatusManager) *encryptionStatusCollector {
+	return &encryptionStatusCollector{
+		client:        cli,
+		statusManager: statusManager,
+		syncPeriod:    defaultEncryptionStatusSyncPeriod,
+		now:           time.Now,
+		scrape:        scrapeFelixMetrics,
+	}
+}
+
+// updateConfig updates the installation the collector uses.
+func (c *encryptionStatusCollector) updateConfig(install *operator.InstallationSpec) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.install = install
+}
+
+// start starts the collector, reporting the encryption status every sync period.
+func (c *encryptionStatusCollector) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.syncPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				st, err := c.collect(ctx)
+				if err != nil {
+					encryptionStatusLog.Error(err, "Failed to collect the encryption status")
+					continue
+				}
+				c.statusManager.SetEncryptionStatus(st)
+			case <-ctx.Done():
+				encryptionStatusLog.Info("encryption status collector shutting down")
+				return
+			}
+		}
+	}()
+}
+
+// collect returns the encryption status of the nodes running calico-node, or nil if the traffic is not encrypted. The
+// metrics of the nodes are scraped concurrently, and only the nodes which don't encrypt all their traffic are listed.
+func (c *encryptionStatusCollector) collect(ctx context.Context) (*operator.EncryptionStatus, error) {
+	c.lock.Lock()
+	install := c.install
+	c.lock.Unlock()
+	if install == nil {
+		return nil, nil
+	}
+
+	fc := &crdv1.FelixConfiguration{}
+	if err := c.client.Get(ctx, types.NamespacedName{Name: "default"}, fc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	st := &operator.EncryptionStatus{}
+	switch {
+	case fc.Spec.WireguardEnabled != nil && *fc.Spec.WireguardEnabled:
+		st.Type = operator.EncryptionWireGuard
+	case fc.Spec.IPSecMode != "":
+		st.Type = operator.EncryptionIPsec
+	default:
+		return nil, nil
+	}
+	if isVPPDataplane(install) {
+		// Felix doesn't program the encryption of the VPP dataplane, so its metrics would show every node as
+		// unencrypted.
+		st.Message = "the traffic is encrypted by VPP, whose encryption status is not collected"
+		return st, nil
+	}
+	if install.NodeMetricsPort == nil {
+		st.Message = "spec.nodeMetricsPort must be set to collect the encryption status from Felix"
+		return st, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.client.List(ctx, pods, client.InNamespace(common.CalicoNamespace), client.MatchingLabels{"k8s-app": common.NodeDaemonSetName}); err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Spec.NodeName < pods.Items[j].Spec.NodeName })
+
+	peers := int32(len(pods.Items) - 1)
+	nodes := make([]operator.NodeEncryptionStatus, len(pods.Items))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < encryptionStatusScrapeWorkers && w < len(pods.Items); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				nodes[i] = c.nodeStatus(ctx, st.Type, &pods.Items[i], *install.NodeMetricsPort, peers)
+			}
+		}()
+	}
+	for i := range pods.Items {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	for _, ns := range nodes {
+		if ns.Error == "" && ns.UnencryptedPeers == 0 {
+			st.EncryptedNodes++
+			continue
+		}
+		st.UnencryptedNodes++
+		if len(st.Nodes) < maxEncryptionNodeStatuses {
+			st.Nodes = append(st.Nodes, ns)
+		}
+	}
+	return st, nil
+}
+
+// nodeStatus returns the encryption status of the node of the given calico-node pod, which has the given number of
+// peers, from the metrics of its Felix.
+func (c *encryptionStatusCollector) nodeStatus(ctx context.Context, t operator.EncryptionType, pod *corev1.Pod, port int32, peers int32) operator.NodeEncryptionStatus {
+	ns := operator.NodeEncryptionStatus{Name: pod.Spec.NodeName}
+	if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+		ns.Error = "calico-node is not running"
+		return ns
+	}
+
+	// calico-node runs on the host network, so the address of its pod is the address of the node.
+	url := fmt.Sprintf("http://%s/metrics", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port))))
+	metrics, err := c.scrape(ctx, url)
+	if err != nil {
+		ns.Error = fmt.Sprintf("failed to collect the metrics of Felix: %v", err)
+		return ns
+	}
+
+	switch t {
+	case operator.EncryptionWireGuard:
+		for _, handshake := range metricValues(metrics, wireguardHandshakeMetric) {
+			if handshake <= 0 {
+				continue
+			}
+			age := int64(c.now().Sub(time.Unix(int64(handshake), 0)).Seconds())
+			if age > int64(wireguardSessionTimeout.Seconds()) {
+				continue
+			}
+			ns.EstablishedPeers++
+			if ns.OldestHandshakeSeconds == nil || age > *ns.OldestHandshakeSeconds {
+				ns.OldestHandshakeSeconds = &age
+			}
+		}
+	case operator.EncryptionIPsec:
+		for _, bindings := range metricValues(metrics, ipsecBindingsMetric) {
+			ns.EstablishedPeers += int32(bindings)
+		}
+	}
+	if ns.EstablishedPeers < peers {
+		ns.UnencryptedPeers = peers - ns.EstablishedPeers
+	}
+	return ns
+}
+
+// scrapeFelixMetrics returns the Prometheus metrics served at the given URL.
+func scrapeFelixMetrics(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, felixMetricsScrapeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response %s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// metricValues returns the values of the samples of the given metric in the Prometheus text format.
+func metricValues(metrics []byte, name string) []float64 {
+	var values []float64
+	scanner := bufio.NewScanner(bytes.NewReader(metrics))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, name) {
+			continue
+		}
+		rest := line[len(name):]
+		if strings.HasPrefix(rest, "{") {
+			end := strings.LastIndex(rest, "}")
+			if end < 0 {
+				continue
+			}
+			rest = rest[end+1:]
+		} else if !strings.HasPrefix(rest, " ") {
+			// Another metric with the same prefix.
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		if v, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			values = append(values, v)
+		}
+	}
+	return values
+}
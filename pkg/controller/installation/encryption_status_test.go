@@ -0,0 +1,176 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/status"
+)
+
+var _ = Describe("Encryption status", func() {
+	var (
+		cli       client.Client
+		ctx       context.Context
+		now       time.Time
+		collector *encryptionStatusCollector
+		fc        *crdv1.FelixConfiguration
+		metrics   map[string]string
+	)
+
+	createPod := func(node, ip string) {
+		Expect(cli.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("calico-node-%s", node),
+				Namespace: common.CalicoNamespace,
+				Labels:    map[string]string{"k8s-app": common.NodeDaemonSetName},
+			},
+			Spec:   corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		})).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		ctx = context.Background()
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+		now = time.Unix(1600000000, 0)
+
+		enabled := true
+		fc = &crdv1.FelixConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       crdv1.FelixConfigurationSpec{WireguardEnabled: &enabled},
+		}
+		Expect(cli.Create(ctx, fc)).NotTo(HaveOccurred())
+		createPod("node-1", "10.0.0.1")
+		createPod("node-2", "10.0.0.2")
+		createPod("node-3", "10.0.0.3")
+
+		metrics = map[string]string{}
+		collector = newEncryptionStatusCollector(cli, &status.MockStatus{})
+		collector.now = func() time.Time { return now }
+		collector.scrape = func(ctx context.Context, url string) ([]byte, error) {
+			m, ok := metrics[url]
+			if !ok {
+				return nil, fmt.Errorf("connection refused")
+			}
+			return []byte(m), nil
+		}
+		port := int32(9091)
+		collector.updateConfig(&operator.InstallationSpec{NodeMetricsPort: &port})
+	})
+
+	It("should report the WireGuard sessions of each node", func() {
+		metrics["http://10.0.0.1:9091/metrics"] = fmt.Sprintf(`# HELP wireguard_latest_handshake_seconds wireguard interface latest handshake unix timestamp in seconds to a peer
+# TYPE wireguard_latest_handshake_seconds gauge
+wireguard_latest_handshake_seconds{hostname="node-1",peer_endpoint="10.0.0.2:51820",peer_key="a",public_key="b"} %d
+wireguard_latest_handshake_seconds{hostname="node-1",peer_endpoint="10.0.0.3:51820",peer_key="c",public_key="b"} %d
+`, now.Add(-30*time.Second).Unix(), now.Add(-90*time.Second).Unix())
+		// The session with node-1 expired.
+		metrics["http://10.0.0.2:9091/metrics"] = fmt.Sprintf(`wireguard_latest_handshake_seconds{hostname="node-2",peer_endpoint="10.0.0.1:51820",peer_key="b",public_key="a"} %d
+wireguard_latest_handshake_seconds{hostname="node-2",peer_endpoint="10.0.0.3:51820",peer_key="c",public_key="a"} %d
+`, now.Add(-10*time.Minute).Unix(), now.Add(-60*time.Second).Unix())
+
+		st, err := collector.collect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st.Type).To(Equal(operator.EncryptionWireGuard))
+		Expect(st.Message).To(BeEmpty())
+		Expect(st.EncryptedNodes).To(Equal(int32(1)))
+		Expect(st.UnencryptedNodes).To(Equal(int32(2)))
+		Expect(st.Nodes).To(HaveLen(2))
+
+		sixty := int64(60)
+		Expect(st.Nodes[0]).To(Equal(operator.NodeEncryptionStatus{Name: "node-2", EstablishedPeers: 1, OldestHandshakeSeconds: &sixty, UnencryptedPeers: 1}))
+		Expect(st.Nodes[1].Name).To(Equal("node-3"))
+		Expect(st.Nodes[1].Error).To(ContainSubstring("connection refused"))
+	})
+
+	It("should report the IPsec bindings of each node", func() {
+		fc.Spec.WireguardEnabled = nil
+		fc.Spec.IPSecMode = "PSK"
+		Expect(cli.Update(ctx, fc)).NotTo(HaveOccurred())
+		for i := 1; i <= 3; i++ {
+			metrics[fmt.Sprintf("http://10.0.0.%d:9091/metrics", i)] = "felix_ipsec_bindings_total 2\n"
+		}
+		metrics["http://10.0.0.3:9091/metrics"] = "felix_ipsec_bindings_total 1\nfelix_ipsec_bindings_total_errors 4\n"
+
+		st, err := collector.collect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st.Type).To(Equal(operator.EncryptionIPsec))
+		Expect(st.EncryptedNodes).To(Equal(int32(2)))
+		Expect(st.Nodes).To(ConsistOf(
+			operator.NodeEncryptionStatus{Name: "node-3", EstablishedPeers: 1, UnencryptedPeers: 1},
+		))
+	})
+
+	It("should bound the nodes whose traffic is not encrypted", func() {
+		for i := 4; i <= 150; i++ {
+			createPod(fmt.Sprintf("node-%03d", i), fmt.Sprintf("10.0.1.%d", i))
+		}
+		st, err := collector.collect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st.EncryptedNodes).To(BeZero())
+		Expect(st.UnencryptedNodes).To(Equal(int32(150)))
+		Expect(st.Nodes).To(HaveLen(100))
+		Expect(st.Nodes[0].Name).To(Equal("node-004"))
+	})
+
+	It("should not collect the encryption status from Felix with the VPP dataplane", func() {
+		port := int32(9091)
+		vpp := operator.LinuxDataplaneVPP
+		collector.updateConfig(&operator.InstallationSpec{
+			NodeMetricsPort: &port,
+			CalicoNetwork:   &operator.CalicoNetworkSpec{LinuxDataplane: &vpp},
+		})
+		st, err := collector.collect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st.Type).To(Equal(operator.EncryptionWireGuard))
+		Expect(st.Message).To(Equal("the traffic is encrypted by VPP, whose encryption status is not collected"))
+		Expect(st.Nodes).To(BeEmpty())
+	})
+
+	It("should require the metrics of Felix", func() {
+		collector.updateConfig(&operator.InstallationSpec{})
+		st, err := collector.collect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st.Message).To(Equal("spec.nodeMetricsPort must be set to collect the encryption status from Felix"))
+		Expect(st.Nodes).To(BeEmpty())
+	})
+
+	It("should not report any status when the traffic is not encrypted", func() {
+		fc.Spec.WireguardEnabled = nil
+		Expect(cli.Update(ctx, fc)).NotTo(HaveOccurred())
+		st, err := collector.collect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st).To(BeNil())
+	})
+})
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+)
+
+// The IP pools must not overlap the networks of the cluster: the traffic to the services or to the nodes would be
+// routed to the pods otherwise. The networks are read from the cluster, so the IP pools are only checked when they
+// change, or until the installation is first reconciled.
+
+// ipPoolsChanged returns true if the IP pools or the node address autodetection of the desired spec differ from the
+// installed one.
+func ipPoolsChanged(installed, desired *operator.InstallationSpec) bool {
+	if installed == nil || installed.CalicoNetwork == nil || desired.CalicoNetwork == nil {
+		return true
+	}
+	i, d := installed.CalicoNetwork, desired.CalicoNetwork
+	return !reflect.DeepEqual(i.IPPools, d.IPPools) ||
+		!reflect.DeepEqual(i.NodeAddressAutodetectionV4, d.NodeAddressAutodetectionV4) ||
+		!reflect.DeepEqual(i.NodeAddressAutodetectionV6, d.NodeAddressAutodetectionV6)
+}
+
+// checkIPPoolConflicts checks the IP pools of the given network against the service cluster IP range and the
+// addresses of the nodes read from the cluster.
+func checkIPPoolConflicts(ctx context.Context, cli client.Client, network *operator.CalicoNetworkSpec) error {
+	if network == nil || len(network.IPPools) == 0 {
+		return nil
+	}
+	serviceCIDRs, err := clusterServiceCIDRs(ctx, cli)
+	if err != nil {
+		return err
+	}
+	nodes := &corev1.NodeList{}
+	if err := cli.List(ctx, nodes); err != nil {
+		return fmt.Errorf("unable to list the nodes: %w", err)
+	}
+	return validateIPPoolConflicts(network, serviceCIDRs, nodes.Items)
+}
+
+// validateIPPoolConflicts checks that the IP pools of the given network don't overlap the given service CIDRs, the
+// CIDRs of the node address autodetection, nor contain an address of the given nodes.
+func validateIPPoolConflicts(network *operator.CalicoNetworkSpec, serviceCIDRs []*net.IPNet, nodes []corev1.Node) error {
+	var underlayCIDRs []string
+	for _, ad := range []*operator.NodeAddressAutodetection{network.NodeAddressAutodetectionV4, network.NodeAddressAutodetectionV6} {
+		if ad != nil {
+			underlayCIDRs = append(underlayCIDRs, ad.CIDRS...)
+		}
+	}
+
+	for _, pool := range network.IPPools {
+		_, poolCIDR, err := net.ParseCIDR(pool.CIDR)
+		if err != nil {
+			// Invalid pools are reported by the validation of the installation.
+			continue
+		}
+		for _, svc := range serviceCIDRs {
+			if cidrsOverlap(poolCIDR, svc) {
+				return fmt.Errorf("IP pool %s overlaps the service cluster IP range %s", pool.CIDR, svc)
+			}
+		}
+		for _, c := range underlayCIDRs {
+			_, underlay, err := net.ParseCIDR(c)
+			if err != nil {
+				continue
+			}
+			if cidrsOverlap(poolCIDR, underlay) {
+				return fmt.Errorf("IP pool %s overlaps the node address autodetection CIDR %s", pool.CIDR, c)
+			}
+		}
+		for _, node := range nodes {
+			for _, addr := range node.Status.Addresses {
+				if addr.Type != corev1.NodeInternalIP && addr.Type != corev1.NodeExternalIP {
+					continue
+				}
+				if ip := net.ParseIP(addr.Address); ip != nil && poolCIDR.Contains(ip) {
+					return fmt.Errorf("IP pool %s contains the address %s of node %s", pool.CIDR, addr.Address, node.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// cidrsOverlap returns true if the given CIDRs share an address.
+func cidrsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
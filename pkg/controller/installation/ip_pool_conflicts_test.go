@@ -0,0 +1,99 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+)
+
+var _ = Describe("IP pool conflicts", func() {
+	var network *operator.CalicoNetworkSpec
+	var serviceCIDRs []*net.IPNet
+
+	node := func(name, address string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: name},
+				{Type: corev1.NodeInternalIP, Address: address},
+			}},
+		}
+	}
+
+	BeforeEach(func() {
+		network = &operator.CalicoNetworkSpec{
+			IPPools: []operator.IPPool{{CIDR: "192.168.0.0/16"}, {CIDR: "fd00:192:168::/48"}},
+		}
+		_, svc, err := net.ParseCIDR("10.96.0.0/12")
+		Expect(err).NotTo(HaveOccurred())
+		serviceCIDRs = []*net.IPNet{svc}
+	})
+
+	It("should accept IP pools apart from the cluster network", func() {
+		Expect(validateIPPoolConflicts(network, serviceCIDRs, []corev1.Node{node("node1", "172.16.0.10")})).NotTo(HaveOccurred())
+	})
+
+	It("should reject an IP pool overlapping the service cluster IP range", func() {
+		network.IPPools[0].CIDR = "10.0.0.0/8"
+		Expect(validateIPPoolConflicts(network, serviceCIDRs, nil)).To(MatchError("IP pool 10.0.0.0/8 overlaps the service cluster IP range 10.96.0.0/12"))
+	})
+
+	It("should reject an IP pool containing the address of a node", func() {
+		Expect(validateIPPoolConflicts(network, serviceCIDRs, []corev1.Node{node("node1", "172.16.0.10"), node("node2", "192.168.1.20")})).
+			To(MatchError("IP pool 192.168.0.0/16 contains the address 192.168.1.20 of node node2"))
+	})
+
+	It("should reject an IP pool overlapping the node address autodetection CIDRs", func() {
+		network.NodeAddressAutodetectionV6 = &operator.NodeAddressAutodetection{CIDRS: []string{"fd00:192:168:1::/64"}}
+		Expect(validateIPPoolConflicts(network, serviceCIDRs, nil)).To(MatchError("IP pool fd00:192:168::/48 overlaps the node address autodetection CIDR fd00:192:168:1::/64"))
+	})
+
+	It("should only check the IP pools when they change", func() {
+		installed := &operator.InstallationSpec{CalicoNetwork: network.DeepCopy()}
+		desired := &operator.InstallationSpec{CalicoNetwork: network.DeepCopy()}
+		Expect(ipPoolsChanged(nil, desired)).To(BeTrue())
+		Expect(ipPoolsChanged(installed, desired)).To(BeFalse())
+		desired.CalicoNetwork.IPPools[0].CIDR = "10.0.0.0/8"
+		Expect(ipPoolsChanged(installed, desired)).To(BeTrue())
+	})
+
+	It("should read the service cluster IP range and the nodes from the cluster", func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		n := node("node1", "192.168.1.20")
+		cli := fake.NewFakeClientWithScheme(scheme,
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: metav1.NamespaceDefault},
+				Spec:       corev1.ServiceSpec{ClusterIP: "10.96.0.1"},
+			},
+			&n,
+		)
+		Expect(checkIPPoolConflicts(context.Background(), cli, network)).To(MatchError("IP pool 192.168.0.0/16 contains the address 192.168.1.20 of node node1"))
+
+		network.IPPools[0].CIDR = "10.96.0.0/16"
+		Expect(checkIPPoolConflicts(context.Background(), cli, network)).To(MatchError("IP pool 10.96.0.0/16 overlaps the service cluster IP range 10.96.0.1/32"))
+	})
+})
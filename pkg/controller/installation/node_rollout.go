@@ -0,0 +1,563 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+)
+
+var nodeRolloutLog = logf.Log.WithName("node_rollout")
+
+const (
+	defaultNodeRolloutSyncPeriod = 10 * time.Second
+
+	defaultCanaryPercentage              = 10
+	defaultCanarySoakSeconds             = 300
+	defaultCanaryMaxRestarts             = 3
+	defaultCanaryProgressDeadlineSeconds = 600
+
+	defaultMigrationProgressDeadlineSeconds = 600
+)
+
+// nodeRollout periodically rolls out the calico-node daemonset when canary rollouts are enabled, in which case the
+// daemonset is rendered with the OnDelete update strategy. It deletes the outdated pods of the canary nodes first, and
+// only deletes the outdated pods of the other nodes once the updated canary pods have been ready for the soak time.
+// At most MaxUnavailable pods of the NodeUpdateStrategy are unavailable at a time. The rollout of a revision of the
+// daemonset halts when one of its pods keeps restarting or does not become ready, until the daemonset is updated again.
+// When the dataplane migration is enabled, the nodes are first migrated to the VPP dataplane one at a time, and the
+// connectivity of each migrated node is verified by the connectivity test, which the installation controller runs.
+type nodeRollout struct {
+	client            kubernetes.Interface
+	nodeIndexInformer cache.SharedIndexInformer
+	syncPeriod        time.Duration
+	now               func() time.Time
+
+	lock    sync.Mutex
+	install *operator.InstallationSpec
+	// haltedRevision is the revision of the daemonset whose rollout is halted, and haltReason the reason why.
+	haltedRevision string
+	haltReason     string
+	// verifyNode is the migrated node whose connectivity is to be verified since verifyRequested, and verifiedNode
+	// the last migrated node whose connectivity was verified.
+	verifyNode      string
+	verifyRevision  string
+	verifyRequested time.Time
+	verifiedNode    string
+}
+
+type nodeRolloutOption func(*nodeRollout)
+
+// nodeRolloutPeriod is an option that sets a custom sync period for the node rollout.
+func nodeRolloutPeriod(syncPeriod time.Duration) nodeRolloutOption {
+	return func(n *nodeRollout) {
+		n.syncPeriod = syncPeriod
+	}
+}
+
+// newNodeRollout creates a new node rollout, optionally applying any options to the default node rollout instance.
+// The default sync period is 10 seconds.
+func newNodeRollout(cs kubernetes.Interface, nodeIndexInformer cache.SharedIndexInformer, options ...nodeRolloutOption) *nodeRollout {
+	n := &nodeRollout{
+		client:            cs,
+		nodeIndexInformer: nodeIndexInformer,
+		syncPeriod:        defaultNodeRolloutSyncPeriod,
+		now:               time.Now,
+	}
+	for _, option := range options {
+		option(n)
+	}
+	return n
+}
+
+// updateConfig updates the installation the node rollout uses.
+func (n *nodeRollout) updateConfig(install *operator.InstallationSpec) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.install = install
+}
+
+// halted returns whether the rollout of the current revision of the calico-node daemonset is halted, and why.
+func (n *nodeRollout) halted() (bool, string) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.haltedRevision != "", n.haltReason
+}
+
+// migrationToVerify returns the node migrated to the VPP dataplane whose connectivity is to be verified by a
+// connectivity test started after the returned time, if any.
+func (n *nodeRollout) migrationToVerify() (string, time.Time) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.verifyNode, n.verifyRequested
+}
+
+// migrationVerified records the result of the connectivity test of the given migrated node. The migration proceeds
+// with the next node when the test succeeded, and halts with the node tainted when it failed.
+func (n *nodeRollout) migrationVerified(node string, st *operator.ConnectivityTestStatus) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if node == "" || node != n.verifyNode {
+		return
+	}
+	switch st.State {
+	case operator.ConnectivityTestSucceeded:
+		nodeRolloutLog.Info("Verified the connectivity of the node migrated to the VPP dataplane", "node", node)
+		n.verifiedNode = node
+	case operator.ConnectivityTestFailed:
+		n.haltedRevision = n.verifyRevision
+		n.haltReason = fmt.Sprintf("the connectivity test of node %s failed after its migration to the VPP dataplane: %s", node, st.Message)
+		nodeRolloutLog.Info("Halting the migration to the VPP dataplane", "node", node, "reason", n.haltReason)
+	default:
+		return
+	}
+	n.verifyNode, n.verifyRevision, n.verifyRequested = "", "", time.Time{}
+}
+
+// start starts the node rollout, rolling out the calico-node daemonset every sync period.
+func (n *nodeRollout) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(n.syncPeriod)
+		defer ticker.Stop()
+		for !n.nodeIndexInformer.HasSynced() {
+			time.Sleep(100 * time.Millisecond)
+		}
+		nodeRolloutLog.Info("Starting node rollout", "syncPeriod", n.syncPeriod)
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := n.rollOut(ctx); err != nil {
+					nodeRolloutLog.Error(err, "Failed to roll out calico-node")
+				}
+			case <-ctx.Done():
+				nodeRolloutLog.Info("node rollout shutting down")
+				return
+			}
+		}
+	}()
+}
+
+// rollOut deletes the outdated calico-node pods that may be updated now.
+func (n *nodeRollout) rollOut(ctx context.Context) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.install == nil || (n.install.NodeCanaryRollout == nil && n.install.DataplaneMigration == nil) {
+		n.haltedRevision, n.haltReason = "", ""
+		return nil
+	}
+	cfg := n.install.NodeCanaryRollout
+
+	ds, err := n.client.AppsV1().DaemonSets(common.CalicoNamespace).Get(ctx, common.NodeDaemonSetName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if ds.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType {
+		// The daemonset is not rendered for canary rollouts yet.
+		return nil
+	}
+
+	revision, err := n.currentRevision(ctx, ds)
+	if err != nil {
+		return err
+	}
+	if revision == "" {
+		return nil
+	}
+	if n.haltedRevision != "" {
+		if n.haltedRevision == revision {
+			return nil
+		}
+		nodeRolloutLog.Info("calico-node was updated, resuming the rollout", "revision", revision)
+		n.haltedRevision, n.haltReason = "", ""
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return err
+	}
+	podList, err := n.client.CoreV1().Pods(common.CalicoNamespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Spec.NodeName < pods[j].Spec.NodeName })
+
+	if migration := n.install.DataplaneMigration; migration != nil {
+		migrated, err := n.migrateDataplane(ctx, migration, revision, pods)
+		if err != nil || !migrated {
+			return err
+		}
+	}
+
+	canaries, err := n.canaryNodes(cfg, pods)
+	if err != nil {
+		return err
+	}
+
+	var updatedCanaries, outdatedCanaries, outdated []corev1.Pod
+	unavailable := int(ds.Status.DesiredNumberScheduled) - len(pods)
+	for _, pod := range pods {
+		if ready, _ := podReady(&pod); !ready || pod.DeletionTimestamp != nil {
+			unavailable++
+		}
+		if pod.Labels[appsv1.DefaultDaemonSetUniqueLabelKey] == revision {
+			if reason := n.podFailure(cfg, &pod); reason != "" {
+				n.haltedRevision, n.haltReason = revision, reason
+				nodeRolloutLog.Info("Halting the rollout of calico-node", "revision", revision, "reason", reason)
+				return nil
+			}
+			if canaries[pod.Spec.NodeName] {
+				updatedCanaries = append(updatedCanaries, pod)
+			}
+			continue
+		}
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if canaries[pod.Spec.NodeName] {
+			outdatedCanaries = append(outdatedCanaries, pod)
+		} else {
+			outdated = append(outdated, pod)
+		}
+	}
+
+	maxUnavailable := 1
+	if ru := n.install.NodeUpdateStrategy.RollingUpdate; ru != nil && ru.MaxUnavailable != nil {
+		if maxUnavailable, err = intstr.GetValueFromIntOrPercent(ru.MaxUnavailable, len(pods), false); err != nil {
+			return fmt.Errorf("invalid maxUnavailable value: %w", err)
+		}
+		if maxUnavailable < 1 {
+			maxUnavailable = 1
+		}
+	}
+
+	toDelete := outdatedCanaries
+	if len(outdatedCanaries) == 0 {
+		if !n.soaked(cfg, updatedCanaries) {
+			return nil
+		}
+		toDelete = outdated
+	}
+	for i := 0; i < len(toDelete) && unavailable < maxUnavailable; i++ {
+		pod := toDelete[i]
+		nodeRolloutLog.Info("Deleting outdated calico-node pod", "pod", pod.Name, "node", pod.Spec.NodeName, "revision", revision)
+		if err := n.client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod %s: %w", pod.Name, err)
+		}
+		unavailable++
+	}
+	return nil
+}
+
+// currentRevision returns the hash of the latest ControllerRevision of the daemonset, which labels its updated pods.
+func (n *nodeRollout) currentRevision(ctx context.Context, ds *appsv1.DaemonSet) (string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	revisions, err := n.client.AppsV1().ControllerRevisions(ds.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", err
+	}
+
+	var latest *appsv1.ControllerRevision
+	for i := range revisions.Items {
+		r := &revisions.Items[i]
+		if !metav1.IsControlledBy(r, ds) {
+			continue
+		}
+		if latest == nil || r.Revision > latest.Revision {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+	return latest.Labels[appsv1.DefaultDaemonSetUniqueLabelKey], nil
+}
+
+// canaryNodes returns the names of the canary nodes: the nodes matching the node selector of the configuration, or
+// else the first nodes of the given pods, which are sorted by node name. There are none without canary rollouts.
+func (n *nodeRollout) canaryNodes(cfg *operator.NodeCanaryRollout, pods []corev1.Pod) (map[string]bool, error) {
+	canaries := map[string]bool{}
+	if cfg == nil {
+		return canaries, nil
+	}
+	if len(cfg.NodeSelector) > 0 {
+		selector := labels.SelectorFromSet(cfg.NodeSelector)
+		for _, obj := range n.nodeIndexInformer.GetIndexer().List() {
+			node, ok := obj.(*corev1.Node)
+			if !ok {
+				return nil, fmt.Errorf("Never expected index to have anything other than a Node object: %v", obj)
+			}
+			if selector.Matches(labels.Set(node.Labels)) {
+				canaries[node.Name] = true
+			}
+		}
+		return canaries, nil
+	}
+
+	percentage := defaultCanaryPercentage
+	if cfg.Percentage != nil {
+		percentage = int(*cfg.Percentage)
+	}
+	count := (len(pods)*percentage + 99) / 100
+	for i := 0; i < count && i < len(pods); i++ {
+		canaries[pods[i].Spec.NodeName] = true
+	}
+	return canaries, nil
+}
+
+// podFailure returns why the given updated pod halts the rollout, or an empty string if it doesn't.
+func (n *nodeRollout) podFailure(cfg *operator.NodeCanaryRollout, pod *corev1.Pod) string {
+	maxRestarts := int32(defaultCanaryMaxRestarts)
+	if cfg != nil && cfg.MaxRestarts != nil {
+		maxRestarts = *cfg.MaxRestarts
+	}
+
+	deadline := time.Duration(defaultCanaryProgressDeadlineSeconds) * time.Second
+	if cfg != nil && cfg.ProgressDeadlineSeconds != nil {
+		deadline = time.Duration(*cfg.ProgressDeadlineSeconds) * time.Second
+	}
+	return podFailure(pod, maxRestarts, deadline, n.now())
+}
+
+// podFailure returns why the given updated pod halts the rollout when it restarted more than maxRestarts times or is
+// still unready after the deadline, or an empty string if it doesn't.
+func podFailure(pod *corev1.Pod, maxRestarts int32, deadline time.Duration, now time.Time) string {
+	for _, cs := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		if cs.RestartCount > maxRestarts {
+			return fmt.Sprintf("container %s of pod %s on node %s restarted %d times", cs.Name, pod.Name, pod.Spec.NodeName, cs.RestartCount)
+		}
+	}
+	if ready, _ := podReady(pod); !ready && now.Sub(pod.CreationTimestamp.Time) > deadline {
+		return fmt.Sprintf("pod %s on node %s is not ready after %s", pod.Name, pod.Spec.NodeName, deadline)
+	}
+	return ""
+}
+
+// soaked returns whether the given updated canary pods have all been ready for the soak time of the configuration.
+func (n *nodeRollout) soaked(cfg *operator.NodeCanaryRollout, pods []corev1.Pod) bool {
+	soak := time.Duration(defaultCanarySoakSeconds) * time.Second
+	if cfg != nil && cfg.SoakSeconds != nil {
+		soak = time.Duration(*cfg.SoakSeconds) * time.Second
+	}
+	for i := range pods {
+		ready, since := podReady(&pods[i])
+		if !ready || n.now().Sub(since) < soak {
+			return false
+		}
+	}
+	return true
+}
+
+// migrateDataplane migrates the nodes running calico-node to the VPP dataplane one at a time, in the order of their
+// names, and returns whether all of them are migrated. A node is tainted, drained if configured, and labeled so that
+// the VPP dataplane is scheduled there, before its outdated calico-node pod is deleted. Its taint is removed once the
+// updated pod is ready and the connectivity test succeeded from the node. The migration halts with the node tainted
+// when the updated pod does not become ready or the connectivity test fails.
+func (n *nodeRollout) migrateDataplane(ctx context.Context, cfg *operator.DataplaneMigration, revision string, pods []corev1.Pod) (bool, error) {
+	// A node may have a terminating pod besides its updated pod.
+	podsByNode := map[string]*corev1.Pod{}
+	for i := range pods {
+		if p := podsByNode[pods[i].Spec.NodeName]; p == nil || p.DeletionTimestamp != nil {
+			podsByNode[pods[i].Spec.NodeName] = &pods[i]
+		}
+	}
+
+	var nodes []*corev1.Node
+	for _, obj := range n.nodeIndexInformer.GetIndexer().List() {
+		node, ok := obj.(*corev1.Node)
+		if !ok {
+			return false, fmt.Errorf("Never expected index to have anything other than a Node object: %v", obj)
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	for _, node := range nodes {
+		pod := podsByNode[node.Name]
+		if nodeMigrated(node) || (pod == nil && !migrationTainted(node)) {
+			continue
+		}
+		// The cached node may be outdated, e.g. right after its taint was removed.
+		node, err := n.client.CoreV1().Nodes().Get(ctx, node.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if nodeMigrated(node) {
+			continue
+		}
+		return false, n.migrateNode(ctx, cfg, revision, node, pod)
+	}
+	return true, nil
+}
+
+// migrateNode takes the next step of the migration of the given node to the VPP dataplane. The pod is the calico-node
+// pod of the node, if it is running.
+func (n *nodeRollout) migrateNode(ctx context.Context, cfg *operator.DataplaneMigration, revision string, node *corev1.Node, pod *corev1.Pod) error {
+	if !migrationTainted(node) {
+		nodeRolloutLog.Info("Tainting node for the migration to the VPP dataplane", "node", node.Name)
+		node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{Key: common.DataplaneMigrationTaintKey, Effect: corev1.TaintEffectNoSchedule})
+		_, err := n.client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		return err
+	}
+
+	if node.Labels[common.LinuxDataplaneLabel] != string(operator.LinuxDataplaneVPP) {
+		if cfg.DrainNodes != nil && *cfg.DrainNodes == operator.DataplaneMigrationDrainEnabled {
+			drained, err := n.drainNode(ctx, node.Name)
+			if err != nil || !drained {
+				return err
+			}
+		}
+		nodeRolloutLog.Info("Labeling node for the VPP dataplane", "node", node.Name)
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[common.LinuxDataplaneLabel] = string(operator.LinuxDataplaneVPP)
+		_, err := n.client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		return err
+	}
+
+	if pod == nil || pod.DeletionTimestamp != nil {
+		// Wait for the updated pod to be created.
+		return nil
+	}
+	if pod.Labels[appsv1.DefaultDaemonSetUniqueLabelKey] != revision {
+		nodeRolloutLog.Info("Deleting outdated calico-node pod", "pod", pod.Name, "node", node.Name, "revision", revision)
+		if err := n.client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod %s: %w", pod.Name, err)
+		}
+		return nil
+	}
+
+	deadline := time.Duration(defaultMigrationProgressDeadlineSeconds) * time.Second
+	if cfg.ProgressDeadlineSeconds != nil {
+		deadline = time.Duration(*cfg.ProgressDeadlineSeconds) * time.Second
+	}
+	if reason := podFailure(pod, defaultCanaryMaxRestarts, deadline, n.now()); reason != "" {
+		n.haltedRevision, n.haltReason = revision, reason
+		nodeRolloutLog.Info("Halting the migration to the VPP dataplane", "node", node.Name, "reason", reason)
+		return nil
+	}
+	if ready, _ := podReady(pod); !ready {
+		return nil
+	}
+	if n.verifiedNode != node.Name {
+		if n.verifyNode != node.Name {
+			nodeRolloutLog.Info("Verifying the connectivity of the node migrated to the VPP dataplane", "node", node.Name)
+			n.verifyNode, n.verifyRevision, n.verifyRequested = node.Name, revision, n.now()
+		}
+		return nil
+	}
+
+	nodeRolloutLog.Info("Node migrated to the VPP dataplane", "node", node.Name)
+	var taints []corev1.Taint
+	for _, t := range node.Spec.Taints {
+		if t.Key != common.DataplaneMigrationTaintKey {
+			taints = append(taints, t)
+		}
+	}
+	node.Spec.Taints = taints
+	_, err := n.client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	return err
+}
+
+// drainNode evicts the pods of the given node, except the ones of daemonsets and the static pods, and returns whether
+// it is drained. The evictions refused because of a PodDisruptionBudget are retried on the next sync.
+func (n *nodeRollout) drainNode(ctx context.Context, nodeName string) (bool, error) {
+	podList, err := n.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	drained := true
+	for _, pod := range podList.Items {
+		if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+			continue
+		}
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		drained = false
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		nodeRolloutLog.Info("Evicting pod for the migration to the VPP dataplane", "pod", pod.Name, "namespace", pod.Namespace, "node", nodeName)
+		err := n.client.CoreV1().Pods(pod.Namespace).Evict(ctx, &policyv1beta1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsTooManyRequests(err) {
+			return false, fmt.Errorf("failed to evict pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+	return drained, nil
+}
+
+// migrationTainted returns whether the node is tainted for the migration to the VPP dataplane.
+func migrationTainted(node *corev1.Node) bool {
+	for _, t := range node.Spec.Taints {
+		if t.Key == common.DataplaneMigrationTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMigrated returns whether the migration of the node to the VPP dataplane is complete.
+func nodeMigrated(node *corev1.Node) bool {
+	return node.Labels[common.LinuxDataplaneLabel] == string(operator.LinuxDataplaneVPP) && !migrationTainted(node)
+}
+
+// podReady returns whether the pod is ready, and since when.
+func podReady(pod *corev1.Pod) (bool, time.Time) {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue, c.LastTransitionTime.Time
+		}
+	}
+	return false, time.Time{}
+}
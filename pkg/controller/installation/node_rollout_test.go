@@ -0,0 +1,310 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/test"
+)
+
+var _ = Describe("Node canary rollout", func() {
+	const oldRevision, newRevision = "old", "new"
+
+	var (
+		cs      *kfake.Clientset
+		ctx     context.Context
+		cancel  context.CancelFunc
+		now     time.Time
+		rollout *nodeRollout
+		install *operator.InstallationSpec
+	)
+
+	createPod := func(node, revision string, ready bool, readySince time.Time) {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		_, err := cs.CoreV1().Pods(common.CalicoNamespace).Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("calico-node-%s", node),
+				Namespace:         common.CalicoNamespace,
+				Labels:            map[string]string{"k8s-app": "calico-node", appsv1.DefaultDaemonSetUniqueLabelKey: revision},
+				CreationTimestamp: metav1.NewTime(readySince),
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(
+					&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: common.NodeDaemonSetName, UID: types.UID("calico-node")}},
+					appsv1.SchemeGroupVersion.WithKind("DaemonSet"),
+				)},
+			},
+			Spec: corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(readySince)}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "calico-node"}},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	podNames := func() []string {
+		pods, err := cs.CoreV1().Pods(common.CalicoNamespace).List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, p := range pods.Items {
+			names = append(names, p.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		now = time.Now()
+		cs = kfake.NewSimpleClientset()
+
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace, UID: types.UID("calico-node")},
+			Spec: appsv1.DaemonSetSpec{
+				Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "calico-node"}},
+				UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType},
+			},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 10},
+		}
+		_, err := cs.AppsV1().DaemonSets(common.CalicoNamespace).Create(ctx, ds, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		for i, revision := range []string{oldRevision, newRevision} {
+			_, err = cs.AppsV1().ControllerRevisions(common.CalicoNamespace).Create(ctx, &appsv1.ControllerRevision{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            fmt.Sprintf("calico-node-%s", revision),
+					Namespace:       common.CalicoNamespace,
+					Labels:          map[string]string{"k8s-app": "calico-node", appsv1.DefaultDaemonSetUniqueLabelKey: revision},
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ds, appsv1.SchemeGroupVersion.WithKind("DaemonSet"))},
+				},
+				Revision: int64(i + 1),
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		for i := 0; i < 10; i++ {
+			createPod(fmt.Sprintf("node-%d", i), oldRevision, true, now.Add(-time.Hour))
+		}
+
+		two := intstr.FromInt(2)
+		install = &operator.InstallationSpec{
+			NodeUpdateStrategy: appsv1.DaemonSetUpdateStrategy{RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &two}},
+			NodeCanaryRollout:  &operator.NodeCanaryRollout{},
+		}
+
+		nodeIndexInformer := cache.NewSharedIndexInformer(test.NewNodeListWatch(cs), &corev1.Node{}, 0, cache.Indexers{})
+		go nodeIndexInformer.Run(ctx.Done())
+		for !nodeIndexInformer.HasSynced() {
+			time.Sleep(10 * time.Millisecond)
+		}
+		rollout = newNodeRollout(cs, nodeIndexInformer)
+		rollout.now = func() time.Time { return now }
+		rollout.updateConfig(install)
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("should only update the canary nodes until they have soaked", func() {
+		Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+		Expect(podNames()).NotTo(ContainElement("calico-node-node-0"))
+		Expect(podNames()).To(HaveLen(9))
+
+		// The canary is recreated, but is not ready for long enough.
+		createPod("node-0", newRevision, true, now.Add(-time.Minute))
+		Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+		Expect(podNames()).To(HaveLen(10))
+
+		// Once it soaked, up to maxUnavailable pods of the other nodes are updated.
+		now = now.Add(10 * time.Minute)
+		Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+		Expect(podNames()).To(HaveLen(8))
+		Expect(podNames()).NotTo(ContainElement(BeElementOf("calico-node-node-1", "calico-node-node-2")))
+		halted, _ := rollout.halted()
+		Expect(halted).To(BeFalse())
+	})
+
+	It("should use the nodes matching the node selector as canaries", func() {
+		for _, name := range []string{"node-3", "node-7"} {
+			_, err := cs.CoreV1().Nodes().Create(ctx, &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"canary": "true"}},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Eventually(func() int { return len(rollout.nodeIndexInformer.GetIndexer().List()) }).Should(Equal(2))
+
+		install.NodeCanaryRollout.NodeSelector = map[string]string{"canary": "true"}
+		Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+		Expect(podNames()).To(HaveLen(8))
+		Expect(podNames()).NotTo(ContainElement(BeElementOf("calico-node-node-3", "calico-node-node-7")))
+	})
+
+	It("should halt the rollout when an updated pod does not become ready", func() {
+		Expect(cs.CoreV1().Pods(common.CalicoNamespace).Delete(ctx, "calico-node-node-0", metav1.DeleteOptions{})).NotTo(HaveOccurred())
+		createPod("node-0", newRevision, false, now.Add(-time.Hour))
+
+		Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+		halted, reason := rollout.halted()
+		Expect(halted).To(BeTrue())
+		Expect(reason).To(ContainSubstring("calico-node-node-0"))
+		Expect(podNames()).To(HaveLen(10))
+
+		// Disabling the canary rollouts clears the halt.
+		install.NodeCanaryRollout = nil
+		Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+		halted, _ = rollout.halted()
+		Expect(halted).To(BeFalse())
+	})
+
+	Context("with the dataplane migration", func() {
+		getNode := func(name string) *corev1.Node {
+			node, err := cs.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			return node
+		}
+
+		BeforeEach(func() {
+			for i := 0; i < 10; i++ {
+				_, err := cs.CoreV1().Nodes().Create(ctx, &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i)},
+				}, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Eventually(func() int { return len(rollout.nodeIndexInformer.GetIndexer().List()) }).Should(Equal(10))
+
+			install.NodeCanaryRollout = nil
+			install.DataplaneMigration = &operator.DataplaneMigration{}
+		})
+
+		It("should migrate the nodes one at a time", func() {
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(migrationTainted(getNode("node-0"))).To(BeTrue())
+			Expect(podNames()).To(HaveLen(10))
+
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(getNode("node-0").Labels).To(HaveKeyWithValue(common.LinuxDataplaneLabel, "VPP"))
+
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(podNames()).To(HaveLen(9))
+			Expect(podNames()).NotTo(ContainElement("calico-node-node-0"))
+
+			// The taint is only removed once the updated pod is ready, after which the next node is migrated.
+			createPod("node-0", newRevision, false, now)
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(migrationTainted(getNode("node-0"))).To(BeTrue())
+
+			Expect(cs.CoreV1().Pods(common.CalicoNamespace).Delete(ctx, "calico-node-node-0", metav1.DeleteOptions{})).NotTo(HaveOccurred())
+			createPod("node-0", newRevision, true, now)
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(migrationTainted(getNode("node-0"))).To(BeTrue())
+
+			// The connectivity of the node is verified before its taint is removed.
+			node, requested := rollout.migrationToVerify()
+			Expect(node).To(Equal("node-0"))
+			Expect(requested).To(Equal(now))
+			rollout.migrationVerified("node-0", &operator.ConnectivityTestStatus{State: operator.ConnectivityTestSucceeded})
+			node, _ = rollout.migrationToVerify()
+			Expect(node).To(BeEmpty())
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(nodeMigrated(getNode("node-0"))).To(BeTrue())
+			Expect(migrationTainted(getNode("node-1"))).To(BeFalse())
+
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(migrationTainted(getNode("node-1"))).To(BeTrue())
+			Expect(podNames()).To(HaveLen(10))
+		})
+
+		It("should halt the migration with the node tainted when the updated pod does not become ready", func() {
+			for i := 0; i < 3; i++ {
+				Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			}
+			Expect(podNames()).NotTo(ContainElement("calico-node-node-0"))
+			createPod("node-0", newRevision, false, now.Add(-time.Hour))
+
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			halted, reason := rollout.halted()
+			Expect(halted).To(BeTrue())
+			Expect(reason).To(ContainSubstring("calico-node-node-0"))
+			Expect(migrationTainted(getNode("node-0"))).To(BeTrue())
+		})
+
+		It("should halt the migration with the node tainted when the connectivity test fails", func() {
+			for i := 0; i < 3; i++ {
+				Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			}
+			createPod("node-0", newRevision, true, now)
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+
+			rollout.migrationVerified("node-0", &operator.ConnectivityTestStatus{State: operator.ConnectivityTestFailed, Message: "service unreachable"})
+			halted, reason := rollout.halted()
+			Expect(halted).To(BeTrue())
+			Expect(reason).To(Equal("the connectivity test of node node-0 failed after its migration to the VPP dataplane: service unreachable"))
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(migrationTainted(getNode("node-0"))).To(BeTrue())
+		})
+
+		It("should drain the nodes when enabled", func() {
+			cs.PrependReactor("create", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "eviction" {
+					return false, nil, nil
+				}
+				eviction := action.(ktesting.CreateAction).GetObject().(*policyv1beta1.Eviction)
+				return true, nil, cs.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), eviction.Namespace, eviction.Name)
+			})
+			_, err := cs.CoreV1().Pods("default").Create(ctx, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec:       corev1.PodSpec{NodeName: "node-0"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			enabled := operator.DataplaneMigrationDrainEnabled
+			install.DataplaneMigration.DrainNodes = &enabled
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(migrationTainted(getNode("node-0"))).To(BeTrue())
+
+			// The node is only labeled once its pods are gone.
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(podNames()).To(HaveLen(10))
+			_, err = cs.CoreV1().Pods("default").Get(ctx, "app", metav1.GetOptions{})
+			Expect(err).To(HaveOccurred())
+			Expect(getNode("node-0").Labels).NotTo(HaveKey(common.LinuxDataplaneLabel))
+
+			Expect(rollout.rollOut(ctx)).NotTo(HaveOccurred())
+			Expect(getNode("node-0").Labels).To(HaveKeyWithValue(common.LinuxDataplaneLabel, "VPP"))
+		})
+	})
+})
@@ -0,0 +1,146 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gv "github.com/hashicorp/go-version"
+	apiextenv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/crds"
+	"github.com/tigera/operator/version"
+)
+
+const (
+	// allowUnsupportedUpgradeAnnotation can be set to "true" on the Installation to skip the checks of the upgrade
+	// path and of the dataplane changes. This is not supported.
+	allowUnsupportedUpgradeAnnotation = "unsupported.operator.tigera.io/allow-unsupported-upgrade"
+
+	// maxMinorVersionSkew is the number of minor versions an upgrade of the operator may move forward.
+	maxMinorVersionSkew = 1
+)
+
+// runPreflightChecks checks that the components can be rolled with the given installation. The upgrade path and the
+// CRDs are only checked when the operator version differs from the one that last installed the components, given in
+// the status of the installation.
+func (r *ReconcileInstallation) runPreflightChecks(ctx context.Context, instance *operator.Installation, status operator.InstallationStatus) error {
+	allowUnsupported := strings.ToLower(instance.Annotations[allowUnsupportedUpgradeAnnotation]) == "true"
+
+	if !allowUnsupported {
+		if err := checkVPPCompatibility(status.Computed, &instance.Spec); err != nil {
+			return err
+		}
+	}
+
+	if status.OperatorVersion == "" || status.OperatorVersion == version.VERSION {
+		return nil
+	}
+
+	if !allowUnsupported {
+		if err := checkUpgradePath(status.OperatorVersion, buildVersion); err != nil {
+			return err
+		}
+	}
+
+	// The CRDs are updated after the preflight checks when the operator manages them.
+	if !r.manageCRDs {
+		if err := checkCRDs(ctx, r.client, instance.Spec.Variant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUpgradePath checks that the operator may be upgraded from the given previous version to the current one. Only
+// upgrades within a major version moving forward by at most maxMinorVersionSkew minor versions are supported. The
+// check is skipped if either version is not a valid version.
+func checkUpgradePath(previous string, current *gv.Version) error {
+	if current == nil {
+		log.Info("No valid build version, skipping upgrade path checks")
+		return nil
+	}
+	prev, err := versionFromBuildVersion(previous)
+	if err != nil {
+		log.Info("No valid previous operator version, skipping upgrade path checks", "version", previous)
+		return nil
+	}
+
+	prevSegments, curSegments := prev.Segments(), current.Segments()
+	switch {
+	case curSegments[0] != prevSegments[0]:
+		return fmt.Errorf("upgrading the operator from %s to %s is not supported: the major versions differ", prev, current)
+	case curSegments[1] < prevSegments[1]:
+		return fmt.Errorf("downgrading the operator from %s to %s is not supported", prev, current)
+	case curSegments[1]-prevSegments[1] > maxMinorVersionSkew:
+		return fmt.Errorf("upgrading the operator from %s to %s is not supported: upgrade through each minor version in turn", prev, current)
+	}
+	return nil
+}
+
+// checkVPPCompatibility checks that the given spec doesn't switch the Linux dataplane to or from VPP compared to the
+// installed spec, since the VPP dataplane can only be chosen when installing a cluster.
+func checkVPPCompatibility(installed, desired *operator.InstallationSpec) error {
+	if installed == nil || installed.CalicoNetwork == nil {
+		return nil
+	}
+	wasVPP := isVPPDataplane(installed)
+	if isVPPDataplane(desired) == wasVPP {
+		return nil
+	}
+	if wasVPP {
+		return fmt.Errorf("switching the Linux dataplane of an installed cluster from VPP is not supported")
+	}
+	return fmt.Errorf("switching the Linux dataplane of an installed cluster to VPP is not supported")
+}
+
+func isVPPDataplane(spec *operator.InstallationSpec) bool {
+	return spec.CalicoNetwork != nil && spec.CalicoNetwork.LinuxDataplane != nil &&
+		*spec.CalicoNetwork.LinuxDataplane == operator.LinuxDataplaneVPP
+}
+
+// checkCRDs checks that the CRDs of the given variant are installed and serve every version the operator uses.
+func checkCRDs(ctx context.Context, cli client.Client, variant operator.ProductVariant) error {
+	var missing []string
+	for _, expected := range crds.GetCRDs(variant) {
+		installed := &apiextenv1.CustomResourceDefinition{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: expected.Name}, installed); err != nil {
+			if apierrors.IsNotFound(err) {
+				missing = append(missing, expected.Name)
+				continue
+			}
+			return fmt.Errorf("failed to get CRD %s: %w", expected.Name, err)
+		}
+
+		served := map[string]bool{}
+		for _, v := range installed.Spec.Versions {
+			served[v.Name] = v.Served
+		}
+		for _, v := range expected.Spec.Versions {
+			if v.Served && !served[v.Name] {
+				missing = append(missing, fmt.Sprintf("%s/%s", expected.Name, v.Name))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the CRDs required by this version of the operator are not installed: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	gv "github.com/hashicorp/go-version"
+	"github.com/onsi/ginkgo/extensions/table"
+	apiextenv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/crds"
+)
+
+var _ = Describe("Preflight checks", func() {
+	table.DescribeTable("should check the upgrade path of the operator",
+		func(previous, current string, expectErr bool) {
+			cur, err := gv.NewVersion(current)
+			Expect(err).NotTo(HaveOccurred())
+			err = checkUpgradePath(previous, cur)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		table.Entry("patch upgrade", "v1.22.0", "v1.22.3", false),
+		table.Entry("minor upgrade", "v1.22.3", "v1.23.0", false),
+		table.Entry("git describe version", "v1.22.3-11-ga64a5a6", "v1.23.0", false),
+		table.Entry("patch downgrade", "v1.22.3", "v1.22.1", false),
+		table.Entry("minor downgrade", "v1.23.0", "v1.22.3", true),
+		table.Entry("skipped minor version", "v1.21.0", "v1.23.0", true),
+		table.Entry("major upgrade", "v1.23.0", "v2.0.0", true),
+		table.Entry("invalid previous version", "ga64a5a6-dirty", "v1.23.0", false),
+	)
+
+	vpp := operator.LinuxDataplaneVPP
+	iptables := operator.LinuxDataplaneIptables
+	table.DescribeTable("should reject switching the dataplane to or from VPP",
+		func(installed, desired *operator.InstallationSpec, expectErr bool) {
+			err := checkVPPCompatibility(installed, desired)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		table.Entry("new installation", nil, &operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{LinuxDataplane: &vpp}}, false),
+		table.Entry("VPP kept", &operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{LinuxDataplane: &vpp}},
+			&operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{LinuxDataplane: &vpp}}, false),
+		table.Entry("switch to VPP", &operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{LinuxDataplane: &iptables}},
+			&operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{LinuxDataplane: &vpp}}, true),
+		table.Entry("switch from VPP", &operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{LinuxDataplane: &vpp}},
+			&operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{}}, true),
+	)
+
+	It("should report the missing CRDs", func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c := fake.NewFakeClientWithScheme(scheme)
+		ctx := context.Background()
+
+		expected := crds.GetCRDs(operator.Calico)
+		for _, crd := range expected[1:] {
+			Expect(c.Create(ctx, crd.DeepCopy())).NotTo(HaveOccurred())
+		}
+		err := checkCRDs(ctx, c, operator.Calico)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(expected[0].Name))
+
+		Expect(c.Create(ctx, expected[0].DeepCopy())).NotTo(HaveOccurred())
+		Expect(checkCRDs(ctx, c, operator.Calico)).NotTo(HaveOccurred())
+
+		// A CRD no longer serving the version used by the operator is reported too.
+		installed := &apiextenv1.CustomResourceDefinition{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: expected[0].Name}, installed)).NotTo(HaveOccurred())
+		installed.Spec.Versions[0].Served = false
+		Expect(c.Update(ctx, installed)).NotTo(HaveOccurred())
+		Expect(checkCRDs(ctx, c, operator.Calico)).To(HaveOccurred())
+	})
+})
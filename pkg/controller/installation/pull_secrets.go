@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tigera/operator/pkg/common"
+)
+
+// The pull secrets of the installation are copied to the namespace of every component by the controller rendering
+// it. Most controllers only reconcile on changes of their own resources, so the copies are updated here when the
+// pull secret is rotated in the operator namespace, and the pods which failed to pull their images with the stale
+// copy are restarted rather than waiting for the back-off of the kubelet.
+
+// rotatePullSecrets updates the stale copies of the given pull secrets and restarts the pods failing to pull their
+// images in the namespaces of the updated copies. The outcome is reported with the PullSecretsSynced condition.
+func (r *ReconcileInstallation) rotatePullSecrets(ctx context.Context, pullSecrets []*corev1.Secret, log logr.Logger) error {
+	namespaces, restarted, err := r.propagateAndRestart(ctx, pullSecrets, log)
+	r.status.SetPullSecretsStatus(namespaces, restarted, err)
+	return err
+}
+
+func (r *ReconcileInstallation) propagateAndRestart(ctx context.Context, pullSecrets []*corev1.Secret, log logr.Logger) ([]string, []string, error) {
+	namespaces, err := propagatePullSecrets(ctx, r.client, pullSecrets)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(namespaces) == 0 {
+		return nil, nil, nil
+	}
+	log.Info("Updated the pull secrets", "namespaces", namespaces)
+
+	restarted, err := restartImagePullBackOffPods(ctx, r.client, namespaces)
+	if err != nil {
+		return namespaces, nil, err
+	}
+	if len(restarted) > 0 {
+		log.Info("Restarted the pods failing to pull their images", "pods", restarted)
+	}
+	return namespaces, restarted, nil
+}
+
+// propagatePullSecrets updates the copies of the given pull secrets which differ from them, and returns the
+// namespaces of the updated copies. The copies are the secrets of the same name rendered by the operator.
+func propagatePullSecrets(ctx context.Context, c client.Client, pullSecrets []*corev1.Secret) ([]string, error) {
+	if len(pullSecrets) == 0 {
+		return nil, nil
+	}
+
+	copies := &corev1.SecretList{}
+	if err := c.List(ctx, copies, client.MatchingLabels{common.OperatorManagedLabel: "true"}); err != nil {
+		return nil, err
+	}
+
+	var namespaces []string
+	for _, ps := range pullSecrets {
+		for i := range copies.Items {
+			s := &copies.Items[i]
+			if s.Name != ps.Name || s.Namespace == ps.Namespace {
+				continue
+			}
+			if s.Type == ps.Type && reflect.DeepEqual(s.Data, ps.Data) {
+				continue
+			}
+			s.Type = ps.Type
+			s.Data = ps.Data
+			if err := c.Update(ctx, s); err != nil {
+				return nil, err
+			}
+			namespaces = append(namespaces, s.Namespace)
+		}
+	}
+	return namespaces, nil
+}
+
+// restartImagePullBackOffPods deletes the pods rendered by the operator in the given namespaces which fail to pull
+// the image of one of their containers, and returns their names. Only the pods with a controller are deleted, so that
+// they are recreated.
+func restartImagePullBackOffPods(ctx context.Context, c client.Client, namespaces []string) ([]string, error) {
+	var restarted []string
+	for _, ns := range namespaces {
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(ns), client.MatchingLabels{common.OperatorManagedLabel: "true"}); err != nil {
+			return nil, err
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if metav1.GetControllerOf(pod) == nil || !failsToPullImage(pod) {
+				continue
+			}
+			if err := c.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			restarted = append(restarted, pod.Namespace+"/"+pod.Name)
+		}
+	}
+	return restarted, nil
+}
+
+// failsToPullImage returns true if a container of the pod waits for the pull of its image after a failure.
+func failsToPullImage(pod *corev1.Pod) bool {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, s := range statuses {
+		if s.State.Waiting == nil {
+			continue
+		}
+		switch s.State.Waiting.Reason {
+		case "ImagePullBackOff", "ErrImagePull":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+)
+
+var _ = Describe("Pull secrets rotation", func() {
+	var c client.Client
+	var ctx context.Context
+	var pullSecret *corev1.Secret
+
+	managed := map[string]string{common.OperatorManagedLabel: "true"}
+
+	pullSecretCopy := func(ns string, data string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pull-secret", Namespace: ns, Labels: managed},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(data)},
+		}
+	}
+
+	pod := func(name, ns, reason string) *corev1.Pod {
+		t := true
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       ns,
+				Labels:          managed,
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs", UID: "uid", Controller: &t}},
+			},
+		}
+		if reason != "" {
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  "c",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}},
+			}}
+		}
+		return p
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		ctx = context.Background()
+		pullSecret = pullSecretCopy(common.OperatorNamespace(), "rotated")
+		pullSecret.Labels = nil
+		c = fake.NewFakeClientWithScheme(scheme,
+			pullSecret,
+			pullSecretCopy("calico-system", "rotated"),
+			pullSecretCopy("tigera-manager", "stale"),
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "tigera-manager", Labels: managed}},
+		)
+	})
+
+	It("should update the stale copies of the pull secrets", func() {
+		namespaces, err := propagatePullSecrets(ctx, c, []*corev1.Secret{pullSecret})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namespaces).To(ConsistOf("tigera-manager"))
+
+		s := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "pull-secret", Namespace: "tigera-manager"}, s)).NotTo(HaveOccurred())
+		Expect(s.Data).To(Equal(pullSecret.Data))
+
+		// The copies are up to date.
+		namespaces, err = propagatePullSecrets(ctx, c, []*corev1.Secret{pullSecret})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namespaces).To(BeEmpty())
+	})
+
+	It("should restart the pods failing to pull their images", func() {
+		for _, p := range []*corev1.Pod{
+			pod("backoff", "tigera-manager", "ImagePullBackOff"),
+			pod("err", "tigera-manager", "ErrImagePull"),
+			pod("running", "tigera-manager", ""),
+			pod("other-namespace", "tigera-compliance", "ImagePullBackOff"),
+		} {
+			Expect(c.Create(ctx, p)).NotTo(HaveOccurred())
+		}
+		unowned := pod("unowned", "tigera-manager", "ImagePullBackOff")
+		unowned.OwnerReferences = nil
+		Expect(c.Create(ctx, unowned)).NotTo(HaveOccurred())
+
+		restarted, err := restartImagePullBackOffPods(ctx, c, []string{"tigera-manager"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restarted).To(ConsistOf("tigera-manager/backoff", "tigera-manager/err"))
+
+		pods := &corev1.PodList{}
+		Expect(c.List(ctx, pods)).NotTo(HaveOccurred())
+		var names []string
+		for _, p := range pods.Items {
+			names = append(names, p.Name)
+		}
+		Expect(names).To(ConsistOf("running", "other-namespace", "unowned"))
+	})
+})
@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
+)
+
+const (
+	// installationRevisionsConfigMapName is the name of the ConfigMap in the operator namespace holding the
+	// known-good revisions of the spec of the Installation, in JSON, by revision number.
+	installationRevisionsConfigMapName = "installation-revisions"
+
+	// installationRevisionHistoryLimit is the number of revisions kept in the ConfigMap.
+	installationRevisionHistoryLimit = 10
+)
+
+// installationRevisions returns the ConfigMap holding the revisions of the Installation, which is empty if it doesn't
+// exist yet, and the numbers of the revisions in increasing order.
+func installationRevisions(ctx context.Context, cli client.Client) (*corev1.ConfigMap, []int64, error) {
+	cm := &corev1.ConfigMap{}
+	err := cli.Get(ctx, types.NamespacedName{Name: installationRevisionsConfigMapName, Namespace: common.OperatorNamespace()}, cm)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, nil, err
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: installationRevisionsConfigMapName, Namespace: common.OperatorNamespace()},
+		}
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+
+	var revisions []int64
+	for key := range cm.Data {
+		if revision, err := strconv.ParseInt(key, 10, 64); err == nil {
+			revisions = append(revisions, revision)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i] < revisions[j] })
+	return cm, revisions, nil
+}
+
+// recordInstallationRevision records the spec as the latest known-good revision of the Installation, unless it
+// already is, and returns the number of its revision. Like the revisions of a Deployment, a spec which matches an older
+// revision, e.g. after a rollback, is moved to a new revision rather than recorded twice. The oldest revisions beyond
+// the history limit are removed.
+func recordInstallationRevision(ctx context.Context, cli client.Client, spec *operator.InstallationSpec) (int64, error) {
+	cm, revisions, err := installationRevisions(ctx, cli)
+	if err != nil {
+		return 0, err
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return 0, err
+	}
+
+	var revision int64 = 1
+	if n := len(revisions); n > 0 {
+		latest := revisions[n-1]
+		if cm.Data[strconv.FormatInt(latest, 10)] == string(data) {
+			return latest, nil
+		}
+		revision = latest + 1
+	}
+	for i, r := range revisions {
+		if cm.Data[strconv.FormatInt(r, 10)] == string(data) {
+			delete(cm.Data, strconv.FormatInt(r, 10))
+			revisions = append(revisions[:i], revisions[i+1:]...)
+			break
+		}
+	}
+	cm.Data[strconv.FormatInt(revision, 10)] = string(data)
+	revisions = append(revisions, revision)
+	for len(revisions) > installationRevisionHistoryLimit {
+		delete(cm.Data, strconv.FormatInt(revisions[0], 10))
+		revisions = revisions[1:]
+	}
+
+	if cm.ResourceVersion == "" {
+		err = cli.Create(ctx, cm)
+	} else {
+		err = cli.Update(ctx, cm)
+	}
+	return revision, err
+}
+
+// rollbackInstallation replaces the spec of the Installation with the spec of the revision of spec.rollbackTo, and
+// returns the number of the revision. This clears spec.rollbackTo, since the revisions never set it. If the revision
+// is 0, the Installation is rolled back to the current revision when its spec was changed since, e.g. by a change
+// which never became available, or else to the revision before the current one.
+func rollbackInstallation(ctx context.Context, cli client.Client, instance *operator.Installation) (int64, error) {
+	cm, revisions, err := installationRevisions(ctx, cli)
+	if err != nil {
+		return 0, err
+	}
+
+	revision := instance.Spec.RollbackTo.Revision
+	if revision == 0 {
+		spec := instance.Spec.DeepCopy()
+		spec.RollbackTo = nil
+		data, err := json.Marshal(spec)
+		if err != nil {
+			return 0, err
+		}
+		if current, ok := cm.Data[strconv.FormatInt(instance.Status.Revision, 10)]; ok && current != string(data) {
+			revision = instance.Status.Revision
+		}
+	}
+	if revision == 0 {
+		for _, r := range revisions {
+			if r < instance.Status.Revision {
+				revision = r
+			}
+		}
+		if revision == 0 {
+			return 0, fmt.Errorf("no revision of the Installation before revision %d", instance.Status.Revision)
+		}
+	}
+	data, ok := cm.Data[strconv.FormatInt(revision, 10)]
+	if !ok {
+		return 0, fmt.Errorf("revision %d of the Installation not found", revision)
+	}
+
+	spec := operator.InstallationSpec{}
+	if err := json.Unmarshal([]byte(data), &spec); err != nil {
+		return 0, fmt.Errorf("revision %d of the Installation is invalid: %w", revision, err)
+	}
+	instance.Spec = spec
+	return revision, cli.Update(ctx, instance)
+}
+
+// RequestRollback sets spec.rollbackTo on the Installation, for the operator to roll it back to the given revision, or
+// to the previous known-good one if it is 0. It backs the rollback command of the operator.
+func RequestRollback(ctx context.Context, cli client.Client, revision int64) error {
+	if revision != 0 {
+		_, revisions, err := installationRevisions(ctx, cli)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, r := range revisions {
+			found = found || r == revision
+		}
+		if !found {
+			return fmt.Errorf("revision %d of the Installation not found, the known-good revisions are %v", revision, revisions)
+		}
+	}
+
+	instance := &operator.Installation{}
+	if err := cli.Get(ctx, utils.DefaultInstanceKey, instance); err != nil {
+		return err
+	}
+	patchFrom := client.MergeFrom(instance.DeepCopy())
+	instance.Spec.RollbackTo = &operator.InstallationRollback{Revision: revision}
+	return cli.Patch(ctx, instance, patchFrom)
+}
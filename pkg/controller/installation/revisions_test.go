@@ -0,0 +1,139 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+)
+
+var _ = Describe("Installation revisions", func() {
+	var (
+		cli client.Client
+		ctx context.Context
+	)
+
+	specWithMTU := func(mtu int32) *operator.InstallationSpec {
+		return &operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{MTU: &mtu}}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+		ctx = context.Background()
+	})
+
+	It("should record the revisions which change the spec", func() {
+		revision, err := recordInstallationRevision(ctx, cli, specWithMTU(1500))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision).To(Equal(int64(1)))
+
+		revision, err = recordInstallationRevision(ctx, cli, specWithMTU(1500))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision).To(Equal(int64(1)))
+
+		revision, err = recordInstallationRevision(ctx, cli, specWithMTU(1450))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision).To(Equal(int64(2)))
+
+		// The spec of an older revision, e.g. after a rollback, is moved to a new revision.
+		revision, err = recordInstallationRevision(ctx, cli, specWithMTU(1500))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision).To(Equal(int64(3)))
+		_, revisions, err := installationRevisions(ctx, cli)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revisions).To(Equal([]int64{2, 3}))
+	})
+
+	It("should only keep the last revisions", func() {
+		for i := 0; i < installationRevisionHistoryLimit+2; i++ {
+			_, err := recordInstallationRevision(ctx, cli, specWithMTU(int32(1400+i)))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		_, revisions, err := installationRevisions(ctx, cli)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revisions).To(HaveLen(installationRevisionHistoryLimit))
+		Expect(revisions[0]).To(Equal(int64(3)))
+		Expect(revisions[installationRevisionHistoryLimit-1]).To(Equal(int64(installationRevisionHistoryLimit + 2)))
+	})
+
+	It("should roll the Installation back to a revision", func() {
+		for _, mtu := range []int32{1500, 1450, 1400} {
+			_, err := recordInstallationRevision(ctx, cli, specWithMTU(mtu))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		instance := &operator.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       *specWithMTU(9000),
+			Status:     operator.InstallationStatus{Revision: 3},
+		}
+		instance.Spec.RollbackTo = &operator.InstallationRollback{}
+		Expect(cli.Create(ctx, instance)).NotTo(HaveOccurred())
+
+		// Without a revision, a spec which never became available is rolled back to the current revision.
+		revision, err := rollbackInstallation(ctx, cli, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision).To(Equal(int64(3)))
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, instance)).NotTo(HaveOccurred())
+		Expect(instance.Spec.RollbackTo).To(BeNil())
+		Expect(*instance.Spec.CalicoNetwork.MTU).To(Equal(int32(1400)))
+
+		// And the current revision to the revision before it.
+		instance.Spec.RollbackTo = &operator.InstallationRollback{}
+		revision, err = rollbackInstallation(ctx, cli, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision).To(Equal(int64(2)))
+		Expect(*instance.Spec.CalicoNetwork.MTU).To(Equal(int32(1450)))
+
+		instance.Spec.RollbackTo = &operator.InstallationRollback{Revision: 1}
+		_, err = rollbackInstallation(ctx, cli, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*instance.Spec.CalicoNetwork.MTU).To(Equal(int32(1500)))
+
+		instance.Spec.RollbackTo = &operator.InstallationRollback{Revision: 7}
+		_, err = rollbackInstallation(ctx, cli, instance)
+		Expect(err).To(MatchError("revision 7 of the Installation not found"))
+
+		instance.Status.Revision = 1
+		instance.Spec.RollbackTo = &operator.InstallationRollback{}
+		_, err = rollbackInstallation(ctx, cli, instance)
+		Expect(err).To(MatchError("no revision of the Installation before revision 1"))
+	})
+
+	It("should request the rollback of the Installation", func() {
+		_, err := recordInstallationRevision(ctx, cli, specWithMTU(1500))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &operator.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).NotTo(HaveOccurred())
+
+		Expect(RequestRollback(ctx, cli, 2)).To(MatchError("revision 2 of the Installation not found, the known-good revisions are [1]"))
+		Expect(RequestRollback(ctx, cli, 1)).NotTo(HaveOccurred())
+		instance := &operator.Installation{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, instance)).NotTo(HaveOccurred())
+		Expect(instance.Spec.RollbackTo).To(Equal(&operator.InstallationRollback{Revision: 1}))
+	})
+})
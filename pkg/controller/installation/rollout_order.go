@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/tigera/operator/pkg/common"
+)
+
+// The rollouts of typha and calico-node are ordered: when both are rolled at once, the restarting calico-node pods
+// all reconnect to the few typha replicas which are ready, which overloads them. So calico-node is only updated once
+// typha is rolled out, and typha is only scaled down once calico-node is rolled out.
+//
+// The deployment and the daemonset are read from the API server rather than from the cache of the controller, since
+// they may have been updated by the same reconcile.
+
+// deploymentProgressDeadlineExceeded is the reason of the Progressing condition of a deployment whose rollout didn't
+// progress within its progressDeadlineSeconds.
+const deploymentProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+// deferNodeRollout returns true if the update of the calico-node daemonset must wait for the rollout of typha. The
+// first rollout of calico-node is never deferred. It also returns true if the rollout of typha exceeded its progress
+// deadline, in which case calico-node stays deferred, since it would restart against the typha replicas which fail,
+// but the stall must be reported.
+func deferNodeRollout(ctx context.Context, cs kubernetes.Interface) (deferred bool, stalled bool, err error) {
+	_, err = cs.AppsV1().DaemonSets(common.CalicoNamespace).Get(ctx, common.NodeDaemonSetName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+
+	typha, err := cs.AppsV1().Deployments(common.CalicoNamespace).Get(ctx, common.TyphaDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	if deploymentRolledOut(typha) {
+		return false, false, nil
+	}
+	return true, deploymentStalled(typha), nil
+}
+
+// nodeRolledOut returns true if the calico-node daemonset is rolled out, or doesn't exist.
+func nodeRolledOut(ctx context.Context, cs kubernetes.Interface) (bool, error) {
+	ds, err := cs.AppsV1().DaemonSets(common.CalicoNamespace).Get(ctx, common.NodeDaemonSetName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return daemonSetRolledOut(ds), nil
+}
+
+// deploymentRolledOut returns true if all the replicas of the deployment run its latest spec and are available. It
+// follows kubectl rollout status.
+func deploymentRolledOut(d *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas >= replicas &&
+		d.Status.Replicas <= d.Status.UpdatedReplicas &&
+		d.Status.AvailableReplicas >= d.Status.UpdatedReplicas
+}
+
+// deploymentStalled returns true if the rollout of the deployment exceeded its progress deadline. It follows kubectl
+// rollout status.
+func deploymentStalled(d *appsv1.Deployment) bool {
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing {
+			return c.Reason == deploymentProgressDeadlineExceeded
+		}
+	}
+	return false
+}
+
+// daemonSetRolledOut returns true if the pods of all the nodes run the latest spec of the daemonset and are
+// available. It follows kubectl rollout status.
+func daemonSetRolledOut(ds *appsv1.DaemonSet) bool {
+	return ds.Status.ObservedGeneration >= ds.Generation &&
+		ds.Status.UpdatedNumberScheduled >= ds.Status.DesiredNumberScheduled &&
+		ds.Status.NumberAvailable >= ds.Status.DesiredNumberScheduled
+}
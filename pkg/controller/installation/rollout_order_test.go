@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("Typha and calico-node rollout order", func() {
+	var cs *kfake.Clientset
+	var ctx context.Context
+	var typha *appsv1.Deployment
+	var node *appsv1.DaemonSet
+
+	expectDeferred := func(deferred, stalled bool) {
+		d, s, err := deferNodeRollout(ctx, cs)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, d).To(Equal(deferred))
+		ExpectWithOffset(1, s).To(Equal(stalled))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		replicas := int32(3)
+		typha = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-typha", Namespace: "calico-system", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				Replicas:           3,
+				UpdatedReplicas:    3,
+				AvailableReplicas:  3,
+			},
+		}
+		node = &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-node", Namespace: "calico-system", Generation: 1},
+			Status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     1,
+				DesiredNumberScheduled: 5,
+				UpdatedNumberScheduled: 5,
+				NumberAvailable:        5,
+			},
+		}
+	})
+
+	It("should not defer the first rollout of calico-node", func() {
+		typha.Status.UpdatedReplicas = 0
+		cs = kfake.NewSimpleClientset(typha)
+		expectDeferred(false, false)
+	})
+
+	It("should defer the calico-node rollout until typha is rolled out", func() {
+		cs = kfake.NewSimpleClientset(typha, node)
+		expectDeferred(false, false)
+
+		// The update of the deployment is not observed yet.
+		typha.Generation = 3
+		cs = kfake.NewSimpleClientset(typha, node)
+		expectDeferred(true, false)
+
+		// Some replicas are not updated or not available yet.
+		typha.Status.ObservedGeneration = 3
+		typha.Status.Replicas = 4
+		cs = kfake.NewSimpleClientset(typha, node)
+		expectDeferred(true, false)
+
+		typha.Status.Replicas = 3
+		typha.Status.AvailableReplicas = 2
+		cs = kfake.NewSimpleClientset(typha, node)
+		expectDeferred(true, false)
+	})
+
+	It("should report the typha rollout which exceeded its progress deadline", func() {
+		typha.Generation = 3
+		typha.Status.Conditions = []appsv1.DeploymentCondition{{
+			Type:   appsv1.DeploymentProgressing,
+			Status: corev1.ConditionFalse,
+			Reason: "ProgressDeadlineExceeded",
+		}}
+		cs = kfake.NewSimpleClientset(typha, node)
+		expectDeferred(true, true)
+	})
+
+	It("should report the calico-node rollout status", func() {
+		cs = kfake.NewSimpleClientset()
+		Expect(nodeRolledOut(ctx, cs)).To(BeTrue())
+
+		cs = kfake.NewSimpleClientset(node)
+		Expect(nodeRolledOut(ctx, cs)).To(BeTrue())
+
+		node.Status.UpdatedNumberScheduled = 4
+		cs = kfake.NewSimpleClientset(node)
+		Expect(nodeRolledOut(ctx, cs)).To(BeFalse())
+	})
+})
@@ -0,0 +1,123 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// selectRouteReflectors returns the names of the nodes that should act as route reflectors. The nodes already acting as
+// route reflectors are kept as long as they are eligible, so that the route reflectors only move when needed.
+func selectRouteReflectors(nodes []corev1.Node, rr *operator.RouteReflectors) map[string]bool {
+	selector := labels.SelectorFromSet(rr.NodeSelector)
+	var current, candidates []string
+	for _, n := range nodes {
+		if !routeReflectorEligible(&n, selector) {
+			continue
+		}
+		if n.Labels[render.RouteReflectorLabel] == "true" {
+			current = append(current, n.Name)
+		} else {
+			candidates = append(candidates, n.Name)
+		}
+	}
+	sort.Strings(current)
+	sort.Strings(candidates)
+
+	selected := map[string]bool{}
+	for _, name := range append(current, candidates...) {
+		if int32(len(selected)) == *rr.Replicas {
+			break
+		}
+		selected[name] = true
+	}
+	return selected
+}
+
+// routeReflectorEligible returns whether the given node may act as a route reflector: it must be a ready and
+// schedulable Linux node matching the selector.
+func routeReflectorEligible(node *corev1.Node, selector labels.Selector) bool {
+	if node.Spec.Unschedulable || !selector.Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if os, ok := node.Labels["kubernetes.io/os"]; ok && os != "linux" {
+		return false
+	}
+	return nodeReady(node)
+}
+
+// reconcileRouteReflectorNodes labels and annotates the nodes selected as route reflectors, and removes the label and
+// the annotation from the other nodes. Every node is unlabelled when rr is nil.
+func (r *ReconcileInstallation) reconcileRouteReflectorNodes(ctx context.Context, rr *operator.RouteReflectors, log logr.Logger) error {
+	nodes, err := r.nodes()
+	if err != nil {
+		r.SetDegraded("Unable to list nodes", err, log)
+		return err
+	}
+
+	selected := map[string]bool{}
+	if rr != nil {
+		selected = selectRouteReflectors(nodes, rr)
+		if int32(len(selected)) < *rr.Replicas {
+			log.Info("Not enough eligible nodes for the requested route reflectors", "replicas", *rr.Replicas, "selected", len(selected))
+		}
+	}
+
+	for i := range nodes {
+		// The nodes are shared with the informer, so they are copied before being modified.
+		node := nodes[i].DeepCopy()
+		patchFrom := client.MergeFrom(node.DeepCopy())
+		_, labelled := node.Labels[render.RouteReflectorLabel]
+		_, annotated := node.Annotations[render.RouteReflectorClusterIDAnnotation]
+
+		if selected[node.Name] {
+			if node.Labels[render.RouteReflectorLabel] == "true" && node.Annotations[render.RouteReflectorClusterIDAnnotation] == rr.ClusterID {
+				continue
+			}
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			if node.Annotations == nil {
+				node.Annotations = map[string]string{}
+			}
+			node.Labels[render.RouteReflectorLabel] = "true"
+			node.Annotations[render.RouteReflectorClusterIDAnnotation] = rr.ClusterID
+		} else {
+			// Only clear the cluster ID of the nodes the operator made route reflectors.
+			if !labelled {
+				continue
+			}
+			delete(node.Labels, render.RouteReflectorLabel)
+			if annotated {
+				delete(node.Annotations, render.RouteReflectorClusterIDAnnotation)
+			}
+		}
+
+		if err := r.client.Patch(ctx, node, patchFrom); err != nil {
+			r.SetDegraded("Unable to Patch route reflector node", err, log)
+			return err
+		}
+	}
+	return nil
+}
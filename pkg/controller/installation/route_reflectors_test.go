@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/test"
+)
+
+var _ = Describe("Route reflectors", func() {
+	var (
+		c        client.Client
+		ctx      context.Context
+		cancel   context.CancelFunc
+		informer cache.SharedIndexInformer
+		r        *ReconcileInstallation
+		rr       *operator.RouteReflectors
+		reqLog   = logf.Log.WithName("test")
+		replica  int32
+	)
+
+	node := func(name string, ready bool, labels map[string]string) *corev1.Node {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}},
+		}
+	}
+
+	routeReflectorNodes := func() []string {
+		nodes := &corev1.NodeList{}
+		Expect(c.List(ctx, nodes)).NotTo(HaveOccurred())
+		var names []string
+		for _, n := range nodes.Items {
+			if n.Labels[render.RouteReflectorLabel] == "true" {
+				Expect(n.Annotations[render.RouteReflectorClusterIDAnnotation]).To(Equal(rr.ClusterID))
+				names = append(names, n.Name)
+			} else {
+				Expect(n.Annotations).NotTo(HaveKey(render.RouteReflectorClusterIDAnnotation))
+			}
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+		ctx, cancel = context.WithCancel(context.Background())
+
+		informer = cache.NewSharedIndexInformer(test.NewNodeListWatch(kfake.NewSimpleClientset()), &corev1.Node{}, 0, cache.Indexers{})
+		go informer.Run(ctx.Done())
+		for !informer.HasSynced() {
+			time.Sleep(10 * time.Millisecond)
+		}
+		r = &ReconcileInstallation{client: c, scheme: scheme, status: &status.MockStatus{}, nodeIndexInformer: informer}
+
+		replica = 2
+		rr = &operator.RouteReflectors{Replicas: &replica, ClusterID: render.DefaultRouteReflectorClusterID}
+
+		for _, n := range []*corev1.Node{
+			node("node-a", true, nil),
+			node("node-b", false, nil),
+			node("node-c", true, map[string]string{"kubernetes.io/os": "windows"}),
+			node("node-d", true, nil),
+			node("node-e", true, map[string]string{"rr": "true"}),
+		} {
+			Expect(c.Create(ctx, n)).NotTo(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	// reconcileNodes feeds the nodes of the client to the informer, as the watch of the nodes would, and reconciles the
+	// route reflector nodes.
+	reconcileNodes := func(rr *operator.RouteReflectors) {
+		nodes := &corev1.NodeList{}
+		Expect(c.List(ctx, nodes)).NotTo(HaveOccurred())
+		for i := range nodes.Items {
+			Expect(informer.GetIndexer().Update(&nodes.Items[i])).NotTo(HaveOccurred())
+		}
+		reconcileNodes(rr)
+	}
+
+	It("should label the eligible nodes and keep the current route reflectors", func() {
+		reconcileNodes(rr)
+		Expect(routeReflectorNodes()).To(ConsistOf("node-a", "node-d"))
+
+		// A new eligible node sorting first doesn't move the route reflectors.
+		Expect(c.Create(ctx, node("node-0", true, nil))).NotTo(HaveOccurred())
+		reconcileNodes(rr)
+		Expect(routeReflectorNodes()).To(ConsistOf("node-a", "node-d"))
+
+		rr.NodeSelector = map[string]string{"rr": "true"}
+		reconcileNodes(rr)
+		Expect(routeReflectorNodes()).To(ConsistOf("node-e"))
+
+		reconcileNodes(nil)
+		Expect(routeReflectorNodes()).To(BeEmpty())
+	})
+})
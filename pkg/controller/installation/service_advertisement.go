@@ -0,0 +1,130 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// extractKubeadmServiceCIDRs looks through the kubeadm config map and parses the line starting with 'serviceSubnet'.
+// It returns no CIDRs if the line is missing.
+func extractKubeadmServiceCIDRs(kubeadmConfig *corev1.ConfigMap) ([]*net.IPNet, error) {
+	var line []string
+	re := regexp.MustCompile(`serviceSubnet: (.*)`)
+	for _, l := range kubeadmConfig.Data {
+		if line = re.FindStringSubmatch(l); line != nil {
+			break
+		}
+	}
+	if len(line) == 0 {
+		return nil, nil
+	}
+
+	// IPv4 and IPv6 CIDRs will be separated by a comma in a dual stack setup.
+	var cidrs []*net.IPNet
+	for _, c := range strings.Split(strings.TrimSpace(line[1]), ",") {
+		_, cidr, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, err
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	return cidrs, nil
+}
+
+// clusterServiceCIDRs returns the service cluster IP range of the cluster. The range is read from the kubeadm
+// configuration when there is one. Otherwise, the cluster IPs of the kubernetes service are returned as single address
+// CIDRs, since the range itself isn't exposed by the API.
+func clusterServiceCIDRs(ctx context.Context, cli client.Client) ([]*net.IPNet, error) {
+	kubeadmConfig := &corev1.ConfigMap{}
+	err := cli.Get(ctx, types.NamespacedName{Name: kubeadmConfigMap, Namespace: metav1.NamespaceSystem}, kubeadmConfig)
+	if err == nil {
+		cidrs, err := extractKubeadmServiceCIDRs(kubeadmConfig)
+		if err != nil || len(cidrs) > 0 {
+			return cidrs, err
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("unable to read kubeadm config map: %w", err)
+	}
+
+	svc := &corev1.Service{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: "kubernetes", Namespace: metav1.NamespaceDefault}, svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read the kubernetes service: %w", err)
+	}
+	ips := svc.Spec.ClusterIPs
+	if len(ips) == 0 && svc.Spec.ClusterIP != "" {
+		ips = []string{svc.Spec.ClusterIP}
+	}
+	var cidrs []*net.IPNet
+	for _, s := range ips {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			continue
+		}
+		bits := 128
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 32
+		}
+		cidrs = append(cidrs, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return cidrs, nil
+}
+
+// validateServiceClusterIPs checks that, for each IP family with advertised service cluster IP CIDRs, the service
+// cluster IP range of the cluster is covered by one of them.
+func validateServiceClusterIPs(advertised []string, clusterCIDRs []*net.IPNet) error {
+	var cidrs []*net.IPNet
+	for _, c := range advertised {
+		_, cidr, err := net.ParseCIDR(c)
+		if err != nil {
+			return err
+		}
+		cidrs = append(cidrs, cidr)
+	}
+
+	for _, cluster := range clusterCIDRs {
+		sameFamily, covered := false, false
+		for _, cidr := range cidrs {
+			if len(cidr.IP) != len(cluster.IP) {
+				continue
+			}
+			sameFamily = true
+			clusterOnes, _ := cluster.Mask.Size()
+			ones, _ := cidr.Mask.Size()
+			if ones <= clusterOnes && cidr.Contains(cluster.IP) {
+				covered = true
+				break
+			}
+		}
+		if sameFamily && !covered {
+			return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.serviceClusterIPs (%s) does not cover the service cluster IP range %s",
+				strings.Join(advertised, ","), cluster)
+		}
+	}
+	return nil
+}
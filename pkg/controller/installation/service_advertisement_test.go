@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/onsi/ginkgo/extensions/table"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Service advertisement", func() {
+	table.DescribeTable("should check the advertised service cluster IPs",
+		func(advertised []string, clusterIPRange []string, expectErr bool) {
+			cm := &corev1.ConfigMap{Data: map[string]string{
+				"ClusterConfiguration": "networking:\n  podSubnet: 192.168.0.0/16\n  serviceSubnet: " + clusterIPRange[0] + "\n",
+			}}
+			if len(clusterIPRange) > 1 {
+				cm.Data["ClusterConfiguration"] = "networking:\n  serviceSubnet: " + clusterIPRange[0] + "," + clusterIPRange[1] + "\n"
+			}
+			cidrs, err := extractKubeadmServiceCIDRs(cm)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cidrs).To(HaveLen(len(clusterIPRange)))
+
+			err = validateServiceClusterIPs(advertised, cidrs)
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		table.Entry("same range", []string{"10.96.0.0/12"}, []string{"10.96.0.0/12"}, false),
+		table.Entry("larger range", []string{"10.0.0.0/8"}, []string{"10.96.0.0/12"}, false),
+		table.Entry("smaller range", []string{"10.96.0.0/16"}, []string{"10.96.0.0/12"}, true),
+		table.Entry("other range", []string{"172.16.0.0/12"}, []string{"10.96.0.0/12"}, true),
+		table.Entry("only one family advertised", []string{"10.96.0.0/12"}, []string{"10.96.0.0/12", "fd00:10:96::/112"}, false),
+		table.Entry("dual stack", []string{"10.96.0.0/12", "fd00:10:96::/108"}, []string{"10.96.0.0/12", "fd00:10:96::/112"}, false),
+		table.Entry("wrong IPv6 range", []string{"10.96.0.0/12", "fd00:10:97::/112"}, []string{"10.96.0.0/12", "fd00:10:96::/112"}, true),
+	)
+
+	It("should fall back on the cluster IP of the kubernetes service", func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c := fake.NewFakeClientWithScheme(scheme)
+		ctx := context.Background()
+
+		cidrs, err := clusterServiceCIDRs(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidrs).To(BeEmpty())
+
+		Expect(c.Create(ctx, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: metav1.NamespaceDefault},
+			Spec:       corev1.ServiceSpec{ClusterIP: "10.96.0.1", ClusterIPs: []string{"10.96.0.1"}},
+		})).NotTo(HaveOccurred())
+		cidrs, err = clusterServiceCIDRs(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidrs).To(HaveLen(1))
+		Expect(cidrs[0].String()).To(Equal("10.96.0.1/32"))
+
+		Expect(validateServiceClusterIPs([]string{"10.96.0.0/12"}, cidrs)).NotTo(HaveOccurred())
+		Expect(validateServiceClusterIPs([]string{"10.100.0.0/16"}, cidrs)).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,392 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+	"github.com/tigera/operator/pkg/dns"
+	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/render/kubecontrollers"
+)
+
+// SimulatedTopology is a synthetic cluster for which the components of an Installation are rendered without applying
+// them, e.g. to plan the capacity needed by Calico before it is rolled out.
+type SimulatedTopology struct {
+	// Installation is the spec of the Installation to simulate. It is defaulted and validated like the Installation
+	// of a cluster.
+	Installation operator.InstallationSpec `json:"installation"`
+
+	// VPPDataplane is the spec of the VPPDataplane, which is rendered with the VPP LinuxDataplane.
+	VPPDataplane *operator.VPPDataplaneSpec `json:"vppDataplane,omitempty"`
+
+	// ImageSet is the ImageSet the images are resolved with, if any.
+	ImageSet *operator.ImageSet `json:"imageSet,omitempty"`
+
+	// NodeGroups are the nodes of the cluster.
+	NodeGroups []SimulatedNodeGroup `json:"nodeGroups"`
+}
+
+// SimulatedNodeGroup is a number of identical nodes of a SimulatedTopology.
+type SimulatedNodeGroup struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+
+	// Labels are the labels of the nodes. The nodes are Linux nodes unless their kubernetes.io/os label says
+	// otherwise, and they are considered migrated to the VPP dataplane with the VPP LinuxDataplane unless their
+	// operator.tigera.io/linux-dataplane label says otherwise.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// SimulationReport is what the components of a SimulatedTopology need.
+type SimulationReport struct {
+	Nodes int
+
+	// VPPProfiles is the number of nodes of each VPP profile, an empty name counting the nodes without a profile.
+	VPPProfiles map[string]int
+
+	// Workloads are the daemonsets and deployments of the components, with the resources of all their pods.
+	Workloads []SimulatedWorkload
+
+	// Requests and Limits are the totals of the resources of the workloads.
+	Requests corev1.ResourceList
+	Limits   corev1.ResourceList
+
+	// Images are the images of the workloads, with the number of pods running each of them. Most of them are run by
+	// daemonsets, so it is also the number of nodes pulling them.
+	Images map[string]int
+}
+
+// SimulatedWorkload is a daemonset or a deployment of the components of a SimulatedTopology.
+type SimulatedWorkload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Pods      int
+	Requests  corev1.ResourceList
+	Limits    corev1.ResourceList
+}
+
+// Simulate renders and validates the components of the Installation of the topology, and schedules their pods on the
+// nodes of the topology. The nodes of a group are identical, so each group is scheduled once whatever its size.
+func Simulate(topology *SimulatedTopology) (*SimulationReport, error) {
+	if len(topology.NodeGroups) == 0 {
+		return nil, fmt.Errorf("the topology has no nodes")
+	}
+	nodes := 0
+	for _, g := range topology.NodeGroups {
+		if g.Count <= 0 {
+			return nil, fmt.Errorf("the count of node group %q must be positive", g.Name)
+		}
+		nodes += g.Count
+	}
+
+	instance := &operator.Installation{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec:       *topology.Installation.DeepCopy(),
+	}
+	if err := fillDefaults(instance); err != nil {
+		return nil, fmt.Errorf("failed to fill the defaults of the Installation: %w", err)
+	}
+	if err := validateCustomResource(instance); err != nil {
+		return nil, fmt.Errorf("invalid Installation: %w", err)
+	}
+
+	tls, err := CreateNewTyphaNodeTLS()
+	if err != nil {
+		return nil, err
+	}
+	components := []render.Component{
+		render.Typha(&render.TyphaConfiguration{Installation: &instance.Spec, TLS: tls, ClusterDomain: dns.DefaultClusterDomain}),
+		render.Node(&render.NodeConfiguration{Installation: &instance.Spec, TLS: tls, ClusterDomain: dns.DefaultClusterDomain}),
+		kubecontrollers.NewCalicoKubeControllers(&kubecontrollers.KubeControllersConfiguration{Installation: &instance.Spec, ClusterDomain: dns.DefaultClusterDomain}),
+		render.Windows(&instance.Spec),
+		render.CSI(&instance.Spec, nil),
+		render.VPPProfiles(&render.VPPProfilesConfiguration{Installation: &instance.Spec}),
+	}
+	if vppDataplaneEnabled(&instance.Spec) {
+		spec := operator.VPPDataplaneSpec{}
+		if topology.VPPDataplane != nil {
+			spec = *topology.VPPDataplane
+		}
+		components = append(components, render.VPPDataplane(&render.VPPDataplaneConfiguration{
+			Installation: &instance.Spec,
+			VPPDataplane: &operator.VPPDataplane{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Spec: spec},
+		}))
+	}
+	if err = imageset.ValidateImageSet(topology.ImageSet); err != nil {
+		return nil, err
+	}
+	if err = imageset.ResolveImages(topology.ImageSet, components...); err != nil {
+		return nil, err
+	}
+
+	report := &SimulationReport{
+		Nodes:       nodes,
+		VPPProfiles: map[string]int{},
+		Requests:    corev1.ResourceList{},
+		Limits:      corev1.ResourceList{},
+		Images:      map[string]int{},
+	}
+	groups := simulatedNodes(topology.NodeGroups, &instance.Spec)
+	linuxNodes := 0
+	for _, g := range groups {
+		if g.Labels["kubernetes.io/os"] != "linux" {
+			continue
+		}
+		linuxNodes += g.count
+		if instance.Spec.CalicoNetwork != nil && len(instance.Spec.CalicoNetwork.VPPProfiles) > 0 {
+			report.VPPProfiles[g.Labels[render.VPPProfileLabel]] += g.count
+		}
+	}
+
+	for _, c := range components {
+		toCreate, _ := c.Objects()
+		for _, obj := range toCreate {
+			var w *SimulatedWorkload
+			var spec *corev1.PodSpec
+			switch o := obj.(type) {
+			case *appsv1.DaemonSet:
+				w = &SimulatedWorkload{Kind: "DaemonSet", Namespace: o.Namespace, Name: o.Name}
+				spec = &o.Spec.Template.Spec
+				for _, g := range groups {
+					if schedulable(spec, g.Labels) {
+						w.Pods += g.count
+					}
+				}
+			case *appsv1.Deployment:
+				w = &SimulatedWorkload{Kind: "Deployment", Namespace: o.Namespace, Name: o.Name, Pods: 1}
+				spec = &o.Spec.Template.Spec
+				if o.Spec.Replicas != nil {
+					w.Pods = int(*o.Spec.Replicas)
+				} else if o.Name == common.TyphaDeploymentName {
+					// The replicas of Typha are set by the Typha autoscaler after the number of Linux nodes.
+					w.Pods = common.GetExpectedTyphaScale(linuxNodes)
+				}
+			default:
+				continue
+			}
+			w.Requests, w.Limits = podResources(spec, w.Pods)
+			addResources(report.Requests, w.Requests)
+			addResources(report.Limits, w.Limits)
+			for _, ctr := range append(spec.InitContainers, spec.Containers...) {
+				report.Images[ctr.Image] += w.Pods
+			}
+			report.Workloads = append(report.Workloads, *w)
+		}
+	}
+	return report, nil
+}
+
+// simulatedNodeGroup is a node group with the labels set by the operator on its nodes.
+type simulatedNodeGroup struct {
+	corev1.Node
+	count int
+}
+
+// simulatedNodes returns the node groups with the labels the operator would set on their nodes: the VPP profile and,
+// while the nodes are migrated to the VPP LinuxDataplane, the dataplane of the migrated nodes.
+func simulatedNodes(groups []SimulatedNodeGroup, install *operator.InstallationSpec) []simulatedNodeGroup {
+	var profiles []operator.VPPProfile
+	if install.CalicoNetwork != nil {
+		profiles = install.CalicoNetwork.VPPProfiles
+	}
+	var nodes []simulatedNodeGroup
+	for _, g := range groups {
+		n := simulatedNodeGroup{count: g.Count}
+		n.Name = g.Name
+		n.Labels = map[string]string{"kubernetes.io/os": "linux"}
+		for k, v := range g.Labels {
+			n.Labels[k] = v
+		}
+		if n.Labels["kubernetes.io/os"] == "linux" && vppDataplaneEnabled(install) && install.DataplaneMigration != nil {
+			if _, ok := n.Labels[common.LinuxDataplaneLabel]; !ok {
+				n.Labels[common.LinuxDataplaneLabel] = string(operator.LinuxDataplaneVPP)
+			}
+		}
+		if profile := selectVPPProfile(&n.Node, profiles); profile != "" {
+			n.Labels[render.VPPProfileLabel] = profile
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// vppDataplaneEnabled returns true if the Installation uses the VPP dataplane on Linux.
+func vppDataplaneEnabled(install *operator.InstallationSpec) bool {
+	cn := install.CalicoNetwork
+	return cn != nil && cn.LinuxDataplane != nil && *cn.LinuxDataplane == operator.LinuxDataplaneVPP
+}
+
+// schedulable returns true if a pod of the spec can be scheduled on a node with the given labels, after its node
+// selector and its required node affinity. The taints of the nodes are not simulated.
+func schedulable(spec *corev1.PodSpec, nodeLabels map[string]string) bool {
+	if !labels.SelectorFromSet(spec.NodeSelector).Matches(labels.Set(nodeLabels)) {
+		return false
+	}
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil || spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// The terms are ORed, and the expressions of a term are ANDed.
+	for _, term := range spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if nodeSelectorTermMatches(term, nodeLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, nodeLabels map[string]string) bool {
+	selector := labels.NewSelector()
+	for _, e := range term.MatchExpressions {
+		r, err := labels.NewRequirement(e.Key, nodeSelectorOperators[e.Operator], e.Values)
+		if err != nil {
+			return false
+		}
+		selector = selector.Add(*r)
+	}
+	return selector.Matches(labels.Set(nodeLabels))
+}
+
+// podResources returns the resources of the given number of pods of the spec. The init containers run before the
+// containers, so a pod needs the most of its largest init container and of the sum of its containers.
+func podResources(spec *corev1.PodSpec, pods int) (corev1.ResourceList, corev1.ResourceList) {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, c := range spec.Containers {
+		addResources(requests, c.Resources.Requests)
+		addResources(limits, c.Resources.Limits)
+	}
+	for _, c := range spec.InitContainers {
+		maxResources(requests, c.Resources.Requests)
+		maxResources(limits, c.Resources.Limits)
+	}
+	for _, l := range []corev1.ResourceList{requests, limits} {
+		for name, q := range l {
+			l[name] = *resource.NewMilliQuantity(q.MilliValue()*int64(pods), q.Format)
+		}
+	}
+	return requests, limits
+}
+
+func addResources(total, l corev1.ResourceList) {
+	for name, q := range l {
+		t := total[name]
+		t.Add(q)
+		total[name] = t
+	}
+}
+
+func maxResources(total, l corev1.ResourceList) {
+	for name, q := range l {
+		if t, ok := total[name]; !ok || q.Cmp(t) > 0 {
+			total[name] = q.DeepCopy()
+		}
+	}
+}
+
+// Print writes the report in tables, with a column for each of the resources of the workloads.
+func (r *SimulationReport) Print(w io.Writer) error {
+	fmt.Fprintf(w, "Nodes: %d\n", r.Nodes)
+	if len(r.VPPProfiles) > 0 {
+		var profiles []string
+		for name, n := range r.VPPProfiles {
+			if name == "" {
+				name = "<none>"
+			}
+			profiles = append(profiles, fmt.Sprintf("%s %d", name, n))
+		}
+		sort.Strings(profiles)
+		fmt.Fprintf(w, "VPP profiles: %s\n", strings.Join(profiles, ", "))
+	}
+
+	var names []corev1.ResourceName
+	for name := range r.Requests {
+		names = append(names, name)
+	}
+	for name := range r.Limits {
+		if _, ok := r.Requests[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	header := []string{"WORKLOAD", "PODS"}
+	for _, name := range names {
+		header = append(header, strings.ToUpper(string(name))+" (REQUESTS/LIMITS)")
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	row := func(name string, pods int, requests, limits corev1.ResourceList) {
+		cols := []string{name, fmt.Sprint(pods)}
+		for _, n := range names {
+			cols = append(cols, quantity(requests, n)+"/"+quantity(limits, n))
+		}
+		fmt.Fprintln(tw, strings.Join(cols, "\t"))
+	}
+	pods := 0
+	for _, wl := range r.Workloads {
+		row(fmt.Sprintf("%s %s/%s", wl.Kind, wl.Namespace, wl.Name), wl.Pods, wl.Requests, wl.Limits)
+		pods += wl.Pods
+	}
+	row("Total", pods, r.Requests, r.Limits)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var images []string
+	for image := range r.Images {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "IMAGE\tPODS")
+	for _, image := range images {
+		fmt.Fprintf(tw, "%s\t%d\n", image, r.Images[image])
+	}
+	return tw.Flush()
+}
+
+func quantity(l corev1.ResourceList, name corev1.ResourceName) string {
+	q, ok := l[name]
+	if !ok {
+		return "-"
+	}
+	return q.String()
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("Simulation", func() {
+	var topology *SimulatedTopology
+
+	BeforeEach(func() {
+		topology = &SimulatedTopology{
+			Installation: operator.InstallationSpec{
+				ComponentResources: []operator.ComponentResource{{
+					ComponentName: operator.ComponentNameNode,
+					ResourceRequirements: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+					},
+				}},
+			},
+			NodeGroups: []SimulatedNodeGroup{
+				{Name: "workers", Count: 400},
+				{Name: "gateways", Count: 100, Labels: map[string]string{"role": "gateway"}},
+				{Name: "windows", Count: 10, Labels: map[string]string{"kubernetes.io/os": "windows"}},
+			},
+		}
+	})
+
+	workload := func(r *SimulationReport, name string) SimulatedWorkload {
+		for _, w := range r.Workloads {
+			if w.Name == name {
+				return w
+			}
+		}
+		Fail("workload " + name + " not found")
+		return SimulatedWorkload{}
+	}
+
+	It("should schedule the components on the nodes and total their resources", func() {
+		r, err := Simulate(topology)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Nodes).To(Equal(510))
+
+		node := workload(r, common.NodeDaemonSetName)
+		Expect(node.Pods).To(Equal(500))
+		Expect(node.Requests.Cpu().String()).To(Equal("125"))
+		Expect(workload(r, common.TyphaDeploymentName).Pods).To(Equal(common.GetExpectedTyphaScale(500)))
+		Expect(r.Requests.Cpu().Cmp(resource.MustParse("125"))).To(BeNumerically(">=", 0))
+
+		nodeImage, err := components.GetReference(components.ComponentCalicoNode, "", "", "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Images).To(HaveKeyWithValue(nodeImage, 500))
+
+		var out bytes.Buffer
+		Expect(r.Print(&out)).NotTo(HaveOccurred())
+		Expect(out.String()).To(ContainSubstring("Nodes: 510"))
+		Expect(out.String()).To(ContainSubstring(nodeImage))
+	})
+
+	It("should count the nodes of each VPP profile and schedule the VPP dataplane", func() {
+		vpp := operator.LinuxDataplaneVPP
+		topology.Installation.CalicoNetwork = &operator.CalicoNetworkSpec{
+			LinuxDataplane: &vpp,
+			VPPProfiles: []operator.VPPProfile{
+				{Name: "gateway", NodeSelector: map[string]string{"role": "gateway"}},
+				{Name: "default"},
+			},
+		}
+
+		r, err := Simulate(topology)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.VPPProfiles).To(Equal(map[string]int{"gateway": 100, "default": 400}))
+		Expect(workload(r, render.VPPNodeName).Pods).To(Equal(0))
+		Expect(workload(r, render.VPPNodeDaemonSetName("gateway")).Pods).To(Equal(100))
+		Expect(workload(r, render.VPPNodeDaemonSetName("default")).Pods).To(Equal(400))
+	})
+
+	It("should not schedule the VPP dataplane on the nodes which are not migrated", func() {
+		vpp := operator.LinuxDataplaneVPP
+		topology.Installation.CalicoNetwork = &operator.CalicoNetworkSpec{LinuxDataplane: &vpp}
+		topology.Installation.DataplaneMigration = &operator.DataplaneMigration{}
+		topology.NodeGroups[1].Labels[common.LinuxDataplaneLabel] = string(operator.LinuxDataplaneBPF)
+
+		r, err := Simulate(topology)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workload(r, render.VPPNodeName).Pods).To(Equal(400))
+	})
+
+	It("should reject an invalid Installation", func() {
+		topology.Installation.CalicoNetwork = &operator.CalicoNetworkSpec{
+			VPPProfiles: []operator.VPPProfile{{Name: "default"}},
+		}
+		_, err := Simulate(topology)
+		Expect(err).To(MatchError("invalid Installation: spec.calicoNetwork.vppProfiles requires the VPP dataplane"))
+	})
+
+	It("should reject an empty node group", func() {
+		topology.NodeGroups[0].Count = 0
+		_, err := Simulate(topology)
+		Expect(err).To(MatchError(`the count of node group "workers" must be positive`))
+	})
+})
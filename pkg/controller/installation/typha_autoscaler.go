@@ -189,6 +189,17 @@ func (t *typhaAutoscaler) autoscaleReplicas() error {
 	}
 
 	typhaLog.V(5).Info("Checking if we need to scale typha", "expectedReplicas", expectedReplicas, "currentReplicas", t.activeReplicas)
+	if int32(expectedReplicas) < t.activeReplicas {
+		// Don't remove typhas while the calico-node pods restart and reconnect to them, retry on the next run instead.
+		rolledOut, err := nodeRolledOut(context.Background(), t.client)
+		if err != nil {
+			return fmt.Errorf("could not get the calico-node rollout status: %w", err)
+		}
+		if !rolledOut {
+			typhaLog.Info("Waiting for the calico-node rollout before scaling typha down", "expectedReplicas", expectedReplicas, "currentReplicas", t.activeReplicas)
+			return nil
+		}
+	}
 	if int32(expectedReplicas) != t.activeReplicas {
 		err = t.updateReplicas(int32(expectedReplicas))
 		if err != nil && !apierrors.IsNotFound(err) {
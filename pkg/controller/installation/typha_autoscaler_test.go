@@ -162,6 +162,45 @@ var _ = Describe("Test typha autoscaler ", func() {
 		verifyTyphaReplicas(c, 2)
 	})
 
+	It("should not scale Typha down while calico-node is rolled out", func() {
+		// Create a typha deployment scaled for a three node cluster.
+		var r int32 = 2
+		typha := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-typha", Namespace: "calico-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &r},
+		}
+		_, err := c.AppsV1().Deployments("calico-system").Create(ctx, typha, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+
+		// Create a calico-node daemonset whose pods are being updated.
+		ds := &appsv1.DaemonSet{
+			TypeMeta:   metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-node", Namespace: "calico-system"},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 1, NumberAvailable: 2},
+		}
+		ds, err = c.AppsV1().DaemonSets("calico-system").Create(ctx, ds, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+
+		_ = CreateNode(c, "node1", map[string]string{"kubernetes.io/os": "linux"}, nil)
+		_ = CreateNode(c, "node2", map[string]string{"kubernetes.io/os": "linux"}, nil)
+
+		ta := newTyphaAutoscaler(c, nodeIndexInformer, tlw, statusManager, typhaAutoscalerPeriod(10*time.Millisecond))
+		ta.start(ctx)
+
+		// Typha is not scaled down for the two node cluster until the rollout completes.
+		Consistently(func() int32 {
+			typha, err := c.AppsV1().Deployments("calico-system").Get(ctx, "calico-typha", metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			return *typha.Spec.Replicas
+		}, 500*time.Millisecond).Should(BeEquivalentTo(2))
+
+		ds.Status.UpdatedNumberScheduled = 2
+		_, err = c.AppsV1().DaemonSets("calico-system").UpdateStatus(ctx, ds, metav1.UpdateOptions{})
+		Expect(err).To(BeNil())
+		verifyTyphaReplicas(c, 1)
+	})
+
 	It("should ignore non-migrated nodes in its count", func() {
 		typhaMeta := metav1.ObjectMeta{
 			Name:      "calico-typha",
@@ -18,11 +18,14 @@ import (
 	"fmt"
 	"net"
 	"path"
+	"regexp"
+	"strconv"
 	"strings"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/render"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // validateCustomResource validates that the given custom resource is correct. This
@@ -103,6 +106,11 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 			instance.Spec.CNI.Type, strings.Join(operatorv1.CNIPluginTypesString, ","))
 	}
 
+	cniChaining := instance.Spec.CNI.Chaining != nil && *instance.Spec.CNI.Chaining == operatorv1.CNIChainingEnabled
+	if cniChaining && instance.Spec.CNI.Type == operatorv1.PluginCalico {
+		return fmt.Errorf("spec.cni.chaining is not supported with spec.cni.type %s", instance.Spec.CNI.Type)
+	}
+
 	// Verify Calico settings, if specified.
 	if instance.Spec.CalicoNetwork != nil {
 		bpfDataplane := instance.Spec.CalicoNetwork.LinuxDataplane != nil && *instance.Spec.CalicoNetwork.LinuxDataplane == operatorv1.LinuxDataplaneBPF
@@ -158,6 +166,15 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 							v4pool.Encapsulation,
 							instance.Spec.CNI.Type,
 							instance.Spec.CNI.IPAM.Type)
+					case operatorv1.EncapsulationIPIP, operatorv1.EncapsulationIPIPCrossSubnet:
+						// Without BGP, nothing programs the IPIP routes to the pod CIDRs of the nodes.
+						if instance.Spec.CalicoNetwork.BGP == nil || *instance.Spec.CalicoNetwork.BGP == operatorv1.BGPDisabled {
+							return fmt.Errorf("IPIP encapsulation requires that BGP is enabled")
+						}
+					}
+					// The addresses are assigned from the pod CIDRs of every node.
+					if v4pool.NodeSelector != "all()" {
+						return fmt.Errorf("ipPool.nodeSelector (%s) should be 'all()' with %s IPAM", v4pool.NodeSelector, instance.Spec.CNI.IPAM.Type)
 					}
 				}
 			} else {
@@ -229,6 +246,10 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 				if v6pool.NodeSelector != "all()" {
 					return fmt.Errorf("ipPool.nodeSelector (%s) should be 'all()' when using non-Calico CNI plugin", v6pool.NodeSelector)
 				}
+			} else if instance.Spec.CNI.IPAM.Type == operatorv1.IPAMPluginHostLocal {
+				if v6pool.NodeSelector != "all()" {
+					return fmt.Errorf("ipPool.nodeSelector (%s) should be 'all()' with %s IPAM", v6pool.NodeSelector, instance.Spec.CNI.IPAM.Type)
+				}
 			}
 			if v6pool.NodeSelector == "" {
 				return fmt.Errorf("ipPool.nodeSelector should not be empty")
@@ -261,6 +282,99 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 			if instance.Spec.CalicoNetwork.HostPorts != nil && *instance.Spec.CalicoNetwork.HostPorts == operatorv1.HostPortsDisabled {
 				return fmt.Errorf("VPP doesn't support disabling HostPorts")
 			}
+			if err := validateVPPProfiles(instance.Spec.CalicoNetwork.VPPProfiles); err != nil {
+				return err
+			}
+			if err := validateVPPTunnels(instance.Spec.CalicoNetwork); err != nil {
+				return err
+			}
+			if nat64 := instance.Spec.CalicoNetwork.VPPNAT64; nat64 != nil {
+				if v6pool == nil {
+					return fmt.Errorf("spec.calicoNetwork.vppNAT64 requires an IPv6 IP pool")
+				}
+				if err := validateNAT64Prefix(nat64.Prefix); err != nil {
+					return err
+				}
+			}
+		} else if len(instance.Spec.CalicoNetwork.VPPProfiles) > 0 {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles requires the VPP dataplane")
+		} else if len(instance.Spec.CalicoNetwork.VPPTunnels) > 0 {
+			return fmt.Errorf("spec.calicoNetwork.vppTunnels requires the VPP dataplane")
+		} else if instance.Spec.CalicoNetwork.VPPNAT64 != nil {
+			return fmt.Errorf("spec.calicoNetwork.vppNAT64 requires the VPP dataplane")
+		} else {
+			for _, pool := range instance.Spec.CalicoNetwork.IPPools {
+				if pool.VPPTunnel != "" {
+					return fmt.Errorf("ipPool.vppTunnel requires the VPP dataplane")
+				}
+			}
+		}
+
+		if rr := instance.Spec.CalicoNetwork.RouteReflectors; rr != nil {
+			if instance.Spec.CalicoNetwork.BGP == nil || *instance.Spec.CalicoNetwork.BGP == operatorv1.BGPDisabled {
+				return fmt.Errorf("spec.calicoNetwork.routeReflectors requires BGP to be enabled")
+			}
+			if rr.Replicas != nil && *rr.Replicas < 1 {
+				return fmt.Errorf("spec.calicoNetwork.routeReflectors.replicas must be at least 1")
+			}
+			if rr.ClusterID != "" {
+				if ip := net.ParseIP(rr.ClusterID); ip == nil || ip.To4() == nil {
+					return fmt.Errorf("spec.calicoNetwork.routeReflectors.clusterID (%s) must be an IPv4 address", rr.ClusterID)
+				}
+			}
+		}
+
+		if sa := instance.Spec.CalicoNetwork.ServiceAdvertisement; sa != nil {
+			if instance.Spec.CalicoNetwork.BGP == nil || *instance.Spec.CalicoNetwork.BGP == operatorv1.BGPDisabled {
+				return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement requires BGP to be enabled")
+			}
+			for field, cidrs := range map[string][]string{
+				"serviceClusterIPs":      sa.ServiceClusterIPs,
+				"serviceExternalIPs":     sa.ServiceExternalIPs,
+				"serviceLoadBalancerIPs": sa.ServiceLoadBalancerIPs,
+			} {
+				for _, c := range cidrs {
+					if _, _, err := net.ParseCIDR(c); err != nil {
+						return fmt.Errorf("spec.calicoNetwork.serviceAdvertisement.%s (%s) is invalid: %s", field, c, err)
+					}
+				}
+			}
+		}
+
+		if gr := instance.Spec.CalicoNetwork.BGPGracefulRestart; gr != nil {
+			if instance.Spec.CalicoNetwork.BGP == nil || *instance.Spec.CalicoNetwork.BGP == operatorv1.BGPDisabled {
+				return fmt.Errorf("spec.calicoNetwork.bgpGracefulRestart requires BGP to be enabled")
+			}
+			if gr.MaxRestartTimeSeconds != nil && *gr.MaxRestartTimeSeconds < 1 {
+				return fmt.Errorf("spec.calicoNetwork.bgpGracefulRestart.maxRestartTimeSeconds must be at least 1")
+			}
+			if gr.ShutdownGracePeriodSeconds != nil && *gr.ShutdownGracePeriodSeconds < 1 {
+				return fmt.Errorf("spec.calicoNetwork.bgpGracefulRestart.shutdownGracePeriodSeconds must be at least 1")
+			}
+			if gr.ConvergenceSeconds != nil && *gr.ConvergenceSeconds < 0 {
+				return fmt.Errorf("spec.calicoNetwork.bgpGracefulRestart.convergenceSeconds must not be negative")
+			}
+		}
+
+		if len(instance.Spec.CalicoNetwork.BGPPeeringTemplates) > 0 {
+			if instance.Spec.CalicoNetwork.BGP == nil || *instance.Spec.CalicoNetwork.BGP == operatorv1.BGPDisabled {
+				return fmt.Errorf("spec.calicoNetwork.bgpPeeringTemplates requires BGP to be enabled")
+			}
+			if err := validateBGPPeeringTemplates(instance.Spec.CalicoNetwork.BGPPeeringTemplates); err != nil {
+				return err
+			}
+		}
+
+		if aws := instance.Spec.CalicoNetwork.AWSSecondaryIPs; aws != nil {
+			if instance.Spec.Variant != operatorv1.TigeraSecureEnterprise {
+				return fmt.Errorf("spec.calicoNetwork.awsSecondaryIPs is only supported for spec.variant %s", operatorv1.TigeraSecureEnterprise)
+			}
+			if instance.Spec.CNI.Type != operatorv1.PluginCalico || instance.Spec.CNI.IPAM.Type != operatorv1.IPAMPluginCalico {
+				return fmt.Errorf("spec.calicoNetwork.awsSecondaryIPs requires the Calico CNI plugin and Calico IPAM")
+			}
+			if err := validateAWSSecondaryIPs(aws); err != nil {
+				return err
+			}
 		}
 
 		if bpfDataplane && instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 == nil {
@@ -282,8 +396,8 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 		}
 
 		if instance.Spec.CalicoNetwork.HostPorts != nil {
-			if instance.Spec.CNI.Type != operatorv1.PluginCalico {
-				return fmt.Errorf("spec.calicoNetwork.hostPorts is supported only for Calico CNI")
+			if instance.Spec.CNI.Type != operatorv1.PluginCalico && !cniChaining {
+				return fmt.Errorf("spec.calicoNetwork.hostPorts is supported only for Calico CNI or with spec.cni.chaining enabled")
 			}
 
 			err := validateHostPorts(instance.Spec.CalicoNetwork.HostPorts)
@@ -315,6 +429,14 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 			instance.Spec.FlexVolumePath)
 	}
 
+	if d := instance.Spec.PolicySyncDriver; d != nil && *d != operatorv1.PolicySyncDriverCSI && instance.Spec.FlexVolumePath == "None" {
+		return fmt.Errorf("Installation spec.PolicySyncDriver '%s' requires spec.FlexVolumePath to be set", *d)
+	}
+
+	if p := instance.Spec.KubeletVolumePluginPath; p != "" && !path.IsAbs(p) {
+		return fmt.Errorf("Installation spec.KubeletVolumePluginPath '%s' is not an absolute path", p)
+	}
+
 	// We only support RollingUpdate for the node daemonset strategy.
 	if instance.Spec.NodeUpdateStrategy.Type != appsv1.RollingUpdateDaemonSetStrategyType {
 		return fmt.Errorf("Installation spec.NodeUpdateStrategy.type '%s' is not supported",
@@ -359,8 +481,177 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 		if instance.Spec.Variant == operatorv1.TigeraSecureEnterprise {
 			return fmt.Errorf("Non-privileged Calico is not supported for spec.Variant=%s", operatorv1.TigeraSecureEnterprise)
 		}
+
+		// node-init writes the kernel parameters of the hosts, which requires a privileged container.
+		if ni := instance.Spec.NodeInit; ni != nil && ni.State != nil && *ni.State == operatorv1.NodeInitEnabled {
+			return fmt.Errorf("Non-privileged Calico is not supported when spec.NodeInit is enabled")
+		}
+	}
+
+	if instance.Spec.NodeInit != nil {
+		if err := validateSysctls(instance.Spec.NodeInit.Sysctls); err != nil {
+			return err
+		}
+	}
+
+	if instance.Spec.KubeControllers != nil {
+		if err := validateKubeControllers(instance.Spec.Variant, instance.Spec.KubeControllers); err != nil {
+			return err
+		}
+	}
+
+	if ep := instance.Spec.KubernetesServiceEndpoint; ep != nil {
+		if ep.Host == "" {
+			return fmt.Errorf("spec.kubernetesServiceEndpoint.host must be set")
+		}
+		if ep.Port < 1 || ep.Port > 65535 {
+			return fmt.Errorf("spec.kubernetesServiceEndpoint.port (%d) is not a valid port", ep.Port)
+		}
+	}
+
+	if ns := instance.Spec.NodeShutdown; ns != nil {
+		if err := validateNodeShutdown(ns); err != nil {
+			return err
+		}
+	}
+
+	if instance.Spec.DataplaneMigration != nil {
+		cn := instance.Spec.CalicoNetwork
+		if cn == nil || cn.LinuxDataplane == nil || *cn.LinuxDataplane != operatorv1.LinuxDataplaneVPP {
+			return fmt.Errorf("spec.dataplaneMigration requires spec.calicoNetwork.linuxDataplane to be %s", operatorv1.LinuxDataplaneVPP)
+		}
+	}
+
+	if ds := instance.Spec.DataplaneSync; ds != nil {
+		if err := validateDataplaneSync(ds); err != nil {
+			return err
+		}
+	}
+
+	if w := instance.Spec.RolloutWatchdog; w != nil && w.StallTimeout != nil && w.StallTimeout.Duration <= 0 {
+		return fmt.Errorf("spec.rolloutWatchdog.stallTimeout must be positive")
+	}
+
+	return nil
+}
+
+// validateKubeControllers validates the configuration of calico-kube-controllers.
+func validateKubeControllers(variant operatorv1.ProductVariant, kc *operatorv1.KubeControllers) error {
+	seen := map[operatorv1.KubeControllerType]bool{}
+	for _, ctrl := range kc.EnabledControllers {
+		switch ctrl {
+		case operatorv1.KubeControllerNode:
+		case operatorv1.KubeControllerService, operatorv1.KubeControllerFederatedServices:
+			if variant != operatorv1.TigeraSecureEnterprise {
+				return fmt.Errorf("spec.KubeControllers.EnabledControllers: %s is only supported for spec.Variant=%s", ctrl, operatorv1.TigeraSecureEnterprise)
+			}
+		default:
+			return fmt.Errorf("spec.KubeControllers.EnabledControllers: %s is not a valid controller", ctrl)
+		}
+		if seen[ctrl] {
+			return fmt.Errorf("spec.KubeControllers.EnabledControllers: %s is listed more than once", ctrl)
+		}
+		seen[ctrl] = true
+	}
+
+	if n := kc.Node; n != nil {
+		if n.ReconcilerPeriod != nil && n.ReconcilerPeriod.Duration <= 0 {
+			return fmt.Errorf("spec.KubeControllers.Node.ReconcilerPeriod must be positive")
+		}
+		if n.LeakGracePeriod != nil && n.LeakGracePeriod.Duration < 0 {
+			return fmt.Errorf("spec.KubeControllers.Node.LeakGracePeriod must not be negative")
+		}
+	}
+	return nil
+}
+
+// validateDataplaneSync validates the dataplane sync settings.
+func validateDataplaneSync(ds *operatorv1.DataplaneSync) error {
+	if ds.RouteRefreshIntervalSeconds != nil && *ds.RouteRefreshIntervalSeconds < 0 {
+		return fmt.Errorf("spec.dataplaneSync.routeRefreshIntervalSeconds must not be negative")
+	}
+	if ds.IptablesRefreshIntervalSeconds != nil && *ds.IptablesRefreshIntervalSeconds < 0 {
+		return fmt.Errorf("spec.dataplaneSync.iptablesRefreshIntervalSeconds must not be negative")
+	}
+	if ds.IpsetsRefreshIntervalSeconds != nil && *ds.IpsetsRefreshIntervalSeconds < 0 {
+		return fmt.Errorf("spec.dataplaneSync.ipsetsRefreshIntervalSeconds must not be negative")
+	}
+	return nil
+}
+
+// validateNodeShutdown validates the shutdown of calico-node: the grace period must leave time for calico-node to
+// shut down after the drain.
+func validateNodeShutdown(ns *operatorv1.NodeShutdown) error {
+	if ns.DrainSeconds != nil && *ns.DrainSeconds < 0 {
+		return fmt.Errorf("spec.nodeShutdown.drainSeconds must not be negative")
+	}
+	if ns.TerminationGracePeriodSeconds == nil {
+		if ns.DrainSeconds != nil && *ns.DrainSeconds > 0 {
+			return fmt.Errorf("spec.nodeShutdown.terminationGracePeriodSeconds must be set with spec.nodeShutdown.drainSeconds")
+		}
+		return nil
+	}
+	if *ns.TerminationGracePeriodSeconds < 1 {
+		return fmt.Errorf("spec.nodeShutdown.terminationGracePeriodSeconds must be at least 1")
+	}
+	if ns.DrainSeconds != nil && int64(*ns.DrainSeconds) >= *ns.TerminationGracePeriodSeconds {
+		return fmt.Errorf("spec.nodeShutdown.terminationGracePeriodSeconds (%d) must be larger than spec.nodeShutdown.drainSeconds (%d)",
+			*ns.TerminationGracePeriodSeconds, *ns.DrainSeconds)
+	}
+	return nil
+}
+
+var awsSubnetIDRegexp = regexp.MustCompile(`^subnet-[0-9a-f]+$`)
+
+// validateAWSSecondaryIPs validates the IAM role and the subnet pools of the AWS secondary IPs: the pools must have
+// unique names and IPv4 CIDRs, and belong to a subnet.
+func validateAWSSecondaryIPs(aws *operatorv1.AWSSecondaryIPs) error {
+	if aws.IAMRoleARN != "" && !strings.HasPrefix(aws.IAMRoleARN, "arn:") {
+		return fmt.Errorf("spec.calicoNetwork.awsSecondaryIPs.iamRoleARN (%s) is not an ARN", aws.IAMRoleARN)
+	}
+
+	names := map[string]bool{}
+	for _, p := range aws.SubnetPools {
+		if p.Name == "" {
+			return fmt.Errorf("spec.calicoNetwork.awsSecondaryIPs.subnetPools.name should not be empty")
+		}
+		if names[p.Name] {
+			return fmt.Errorf("spec.calicoNetwork.awsSecondaryIPs.subnetPools.name %s is not unique", p.Name)
+		}
+		names[p.Name] = true
+
+		ip, cidr, err := net.ParseCIDR(p.CIDR)
+		if err != nil || ip.To4() == nil {
+			return fmt.Errorf("spec.calicoNetwork.awsSecondaryIPs.subnetPools[%s].cidr (%s) must be an IPv4 CIDR", p.Name, p.CIDR)
+		}
+		if !awsSubnetIDRegexp.MatchString(p.SubnetID) {
+			return fmt.Errorf("spec.calicoNetwork.awsSecondaryIPs.subnetPools[%s].subnetID (%s) is invalid", p.Name, p.SubnetID)
+		}
+		if p.BlockSize != nil {
+			if *p.BlockSize > 32 || *p.BlockSize < 20 {
+				return fmt.Errorf("spec.calicoNetwork.awsSecondaryIPs.subnetPools[%s].blockSize must be greater than 19 and less than or equal to 32", p.Name)
+			}
+			if ones, _ := cidr.Mask.Size(); int32(ones) > *p.BlockSize {
+				return fmt.Errorf("spec.calicoNetwork.awsSecondaryIPs.subnetPools[%s] is too small for its block size", p.Name)
+			}
+		}
 	}
+	return nil
+}
 
+var sysctlKeyRegexp = regexp.MustCompile(`^[a-z0-9_-]+(\.[a-zA-Z0-9_-]+)*$`)
+
+// validateSysctls checks that the sysctls set by node-init are kernel parameter names with values that can be
+// written safely by its script.
+func validateSysctls(sysctls []operatorv1.Sysctl) error {
+	for _, s := range sysctls {
+		if !sysctlKeyRegexp.MatchString(s.Key) {
+			return fmt.Errorf("spec.nodeInit.sysctls key '%s' is not a valid kernel parameter name", s.Key)
+		}
+		if s.Value == "" || strings.ContainsAny(s.Value, "'\n") {
+			return fmt.Errorf("spec.nodeInit.sysctls[%s] value '%s' is invalid", s.Key, s.Value)
+		}
+	}
 	return nil
 }
 
@@ -417,3 +708,298 @@ func validateHostPorts(hp *operatorv1.HostPortsType) error {
 
 	return nil
 }
+
+// validateBGPPeeringTemplates validates the BGP peering templates: the names of the templates and the values of each
+// template must be unique, and every peer must have a valid IP address and AS number.
+func validateBGPPeeringTemplates(templates []operatorv1.BGPPeeringTemplate) error {
+	names := map[string]bool{}
+	for _, t := range templates {
+		if t.Name == "" {
+			return fmt.Errorf("spec.calicoNetwork.bgpPeeringTemplates.name should not be empty")
+		}
+		if names[t.Name] {
+			return fmt.Errorf("spec.calicoNetwork.bgpPeeringTemplates.name %s is not unique", t.Name)
+		}
+		names[t.Name] = true
+		if t.NodeLabel == "" {
+			return fmt.Errorf("spec.calicoNetwork.bgpPeeringTemplates[%s].nodeLabel should not be empty", t.Name)
+		}
+
+		values := map[string]bool{}
+		for _, v := range t.Values {
+			if values[v.Value] {
+				return fmt.Errorf("spec.calicoNetwork.bgpPeeringTemplates[%s] lists the value %s more than once", t.Name, v.Value)
+			}
+			values[v.Value] = true
+			for _, p := range v.Peers {
+				ip := p.PeerIP
+				if host, _, err := net.SplitHostPort(ip); err == nil {
+					ip = host
+				}
+				if net.ParseIP(ip) == nil {
+					return fmt.Errorf("spec.calicoNetwork.bgpPeeringTemplates[%s] peerIP (%s) is invalid", t.Name, p.PeerIP)
+				}
+				if p.ASNumber == 0 {
+					return fmt.Errorf("spec.calicoNetwork.bgpPeeringTemplates[%s] asNumber of peer %s should not be 0", t.Name, p.PeerIP)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// vppPluginRegexp matches the file names of the VPP plugins, which are written as is in the VPP startup configuration.
+var vppPluginRegexp = regexp.MustCompile(`^[a-z0-9_]+\.so$`)
+
+// validateVPPPlugins validates the plugins of a VPP profile: a plugin can't be both enabled and disabled, and the
+// plugins the dataplane requires can't be disabled.
+func validateVPPPlugins(p *operatorv1.VPPProfile) error {
+	if d := p.Plugins.Default; d != nil {
+		switch *d {
+		case operatorv1.VPPPluginDefaultEnabled:
+		case operatorv1.VPPPluginDefaultDisabled:
+			if p.UplinkDriver == nil {
+				return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].plugins.default %s requires an uplinkDriver", p.Name, *d)
+			}
+		default:
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].plugins.default %s is invalid, should be one of %s,%s",
+				p.Name, *d, operatorv1.VPPPluginDefaultEnabled, operatorv1.VPPPluginDefaultDisabled)
+		}
+	}
+
+	enabled := map[string]bool{}
+	for _, plugin := range p.Plugins.Enabled {
+		if !vppPluginRegexp.MatchString(plugin) {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].plugins.enabled %q is not a plugin file name", p.Name, plugin)
+		}
+		enabled[plugin] = true
+	}
+	for _, plugin := range render.VPPRequiredPlugins(p) {
+		enabled[plugin] = true
+	}
+	for _, plugin := range p.Plugins.Disabled {
+		if !vppPluginRegexp.MatchString(plugin) {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].plugins.disabled %q is not a plugin file name", p.Name, plugin)
+		}
+		if enabled[plugin] {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].plugins.disabled %s is enabled or required by the dataplane", p.Name, plugin)
+		}
+	}
+	return nil
+}
+
+// validateVPPCPUPinning validates the CPU pinning of a VPP profile: the cores are only set with the Manual mode, where
+// the worker cores replace the number of workers and the main core can't run a worker.
+func validateVPPCPUPinning(p *operatorv1.VPPProfile) error {
+	pin := p.CPUPinning
+	switch pin.Mode {
+	case operatorv1.VPPCPUPinningCPUManager:
+		if pin.MainCore != nil || pin.WorkerCores != "" {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].cpuPinning mainCore and workerCores require the %s mode", p.Name, operatorv1.VPPCPUPinningManual)
+		}
+		return nil
+	case operatorv1.VPPCPUPinningManual:
+	default:
+		return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].cpuPinning.mode %s is invalid, should be one of %s,%s",
+			p.Name, pin.Mode, operatorv1.VPPCPUPinningManual, operatorv1.VPPCPUPinningCPUManager)
+	}
+
+	if pin.MainCore == nil && pin.WorkerCores == "" {
+		return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].cpuPinning requires mainCore or workerCores with the %s mode", p.Name, pin.Mode)
+	}
+	if pin.MainCore != nil && *pin.MainCore < 0 {
+		return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].cpuPinning.mainCore should not be negative", p.Name)
+	}
+	if pin.WorkerCores == "" {
+		return nil
+	}
+	if p.Workers != nil {
+		return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].cpuPinning.workerCores can't be set along with workers", p.Name)
+	}
+	for _, r := range strings.Split(pin.WorkerCores, ",") {
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		last := first
+		if err == nil && len(bounds) == 2 {
+			last, err = strconv.Atoi(bounds[1])
+		}
+		if err != nil || last < first {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].cpuPinning.workerCores %q is not a list of cores", p.Name, pin.WorkerCores)
+		}
+		if pin.MainCore != nil && int(*pin.MainCore) >= first && int(*pin.MainCore) <= last {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].cpuPinning.workerCores includes the mainCore %d", p.Name, *pin.MainCore)
+		}
+	}
+	return nil
+}
+
+// vppPCIAddressRegexp matches the PCI addresses of the devices, in the domain:bus:device.function format.
+var vppPCIAddressRegexp = regexp.MustCompile(`^[0-9a-fA-F]{4}:[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-7]$`)
+
+// validateVPPDPDK validates the DPDK configuration of a VPP profile: DPDK must be loaded, the PCI devices must be
+// addresses, and the extra arguments must be single directives of the dpdk section.
+func validateVPPDPDK(p *operatorv1.VPPProfile) error {
+	d := p.DPDK
+	if (p.UplinkDriver == nil || *p.UplinkDriver != operatorv1.VPPUplinkDriverDPDK) &&
+		(p.CryptoEngine == nil || *p.CryptoEngine != operatorv1.VPPCryptoEngineQAT) {
+		return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].dpdk requires the %s uplinkDriver or the %s cryptoEngine",
+			p.Name, operatorv1.VPPUplinkDriverDPDK, operatorv1.VPPCryptoEngineQAT)
+	}
+	if len(d.AllowedPCIDevices) > 0 && len(d.DeniedPCIDevices) > 0 {
+		return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].dpdk.allowedPCIDevices and deniedPCIDevices are mutually exclusive", p.Name)
+	}
+	for _, dev := range append(append([]string{}, d.AllowedPCIDevices...), d.DeniedPCIDevices...) {
+		if !vppPCIAddressRegexp.MatchString(dev) {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].dpdk PCI device %q is not a PCI address", p.Name, dev)
+		}
+	}
+	for _, arg := range d.ExtraArgs {
+		if strings.TrimSpace(arg) == "" || strings.ContainsAny(arg, "{}\n") {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].dpdk.extraArgs %q is not a single directive", p.Name, arg)
+		}
+	}
+	if l := d.LogLevel; l != nil {
+		switch *l {
+		case operatorv1.VPPDPDKLogLevelEmergency, operatorv1.VPPDPDKLogLevelAlert, operatorv1.VPPDPDKLogLevelCritical,
+			operatorv1.VPPDPDKLogLevelError, operatorv1.VPPDPDKLogLevelWarning, operatorv1.VPPDPDKLogLevelNotice,
+			operatorv1.VPPDPDKLogLevelInfo, operatorv1.VPPDPDKLogLevelDebug:
+		default:
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].dpdk.logLevel %s is invalid", p.Name, *l)
+		}
+	}
+	return nil
+}
+
+// validateNAT64Prefix validates that the NAT64 prefix is an IPv6 CIDR of one of the lengths of RFC 6052.
+func validateNAT64Prefix(prefix string) error {
+	ip, cidr, err := net.ParseCIDR(prefix)
+	if err != nil || ip.To4() != nil {
+		return fmt.Errorf("spec.calicoNetwork.vppNAT64.prefix (%s) is not an IPv6 CIDR", prefix)
+	}
+	switch ones, _ := cidr.Mask.Size(); ones {
+	case 32, 40, 48, 56, 64, 96:
+	default:
+		return fmt.Errorf("spec.calicoNetwork.vppNAT64.prefix (%s) length should be one of 32, 40, 48, 56, 64 or 96", prefix)
+	}
+	return nil
+}
+
+// vppMaxFrameSize is the largest frame VPP sends on its uplinks.
+const vppMaxFrameSize = 9216
+
+// validateVPPTunnels validates the VPP tunnels and the IP pools selecting them: the names of the tunnels must be
+// unique, and the pools must select a declared tunnel and leave the encapsulation to it. Felix doesn't account for
+// the overhead of the VPP tunnels when it detects the MTU, so the MTU must be set when a pool uses a tunnel, and
+// leave room for the largest overhead of the tunnels in use.
+func validateVPPTunnels(cn *operatorv1.CalicoNetworkSpec) error {
+	names := map[string]bool{}
+	overheads := map[string]int32{}
+	for _, t := range cn.VPPTunnels {
+		if t.Name == "" {
+			return fmt.Errorf("spec.calicoNetwork.vppTunnels.name should not be empty")
+		}
+		if names[t.Name] {
+			return fmt.Errorf("spec.calicoNetwork.vppTunnels.name %s is not unique", t.Name)
+		}
+		names[t.Name] = true
+		switch t.Type {
+		case operatorv1.VPPTunnelGTPU, operatorv1.VPPTunnelGENEVE:
+		default:
+			return fmt.Errorf("spec.calicoNetwork.vppTunnels[%s].type %s is invalid, should be one of %s,%s",
+				t.Name, t.Type, operatorv1.VPPTunnelGTPU, operatorv1.VPPTunnelGENEVE)
+		}
+		if t.Port != nil && (*t.Port < 1 || *t.Port > 65535) {
+			return fmt.Errorf("spec.calicoNetwork.vppTunnels[%s].port (%d) is not a valid port", t.Name, *t.Port)
+		}
+		if t.Overhead != nil {
+			if *t.Overhead < 0 {
+				return fmt.Errorf("spec.calicoNetwork.vppTunnels[%s].overhead should not be negative", t.Name)
+			}
+			overheads[t.Name] = *t.Overhead
+		}
+	}
+	var overhead int32
+	var tunnel string
+	for _, pool := range cn.IPPools {
+		if pool.VPPTunnel == "" {
+			continue
+		}
+		if !names[pool.VPPTunnel] {
+			return fmt.Errorf("ipPool.vppTunnel %s of %s is not one of spec.calicoNetwork.vppTunnels", pool.VPPTunnel, pool.CIDR)
+		}
+		if pool.Encapsulation != operatorv1.EncapsulationNone {
+			return fmt.Errorf("ipPool.encapsulation of %s should be None since it is encapsulated by the VPP tunnel %s", pool.CIDR, pool.VPPTunnel)
+		}
+		if tunnel == "" || overheads[pool.VPPTunnel] > overhead {
+			tunnel, overhead = pool.VPPTunnel, overheads[pool.VPPTunnel]
+		}
+	}
+	if tunnel == "" {
+		return nil
+	}
+	if cn.MTU == nil {
+		return fmt.Errorf("spec.calicoNetwork.mtu should be set since the detected MTU doesn't leave room for the VPP tunnel %s", tunnel)
+	}
+	if *cn.MTU+overhead > vppMaxFrameSize {
+		return fmt.Errorf("spec.calicoNetwork.mtu (%d) and the overhead (%d) of the VPP tunnel %s exceed the largest VPP frame size %d",
+			*cn.MTU, overhead, tunnel, vppMaxFrameSize)
+	}
+	return nil
+}
+
+// validateVPPProfiles validates the VPP profiles: the names of the profiles must be unique and valid label values,
+// since the nodes are labelled with them.
+func validateVPPProfiles(profiles []operatorv1.VPPProfile) error {
+	names := map[string]bool{}
+	for _, p := range profiles {
+		if p.Name == "" {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles.name should not be empty")
+		}
+		if errs := validation.IsDNS1123Label(p.Name); len(errs) > 0 {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles.name %s is invalid: %s", p.Name, strings.Join(errs, ", "))
+		}
+		if names[p.Name] {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles.name %s is not unique", p.Name)
+		}
+		names[p.Name] = true
+		if p.Workers != nil && *p.Workers < 0 {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].workers should not be negative", p.Name)
+		}
+		if p.CPUPinning != nil {
+			if err := validateVPPCPUPinning(&p); err != nil {
+				return err
+			}
+		}
+		if p.BuffersPerNUMA != nil && *p.BuffersPerNUMA < 1 {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].buffersPerNUMA should be at least 1", p.Name)
+		}
+		if ds := p.BufferDataSize; ds != nil && (*ds < 512 || *ds > 65535) {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].bufferDataSize should be between 512 and 65535", p.Name)
+		}
+		if p.Memory != nil {
+			if _, err := render.ValidateVPPMemory(p.Memory); err != nil {
+				return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].memory.%v", p.Name, err)
+			}
+		}
+		if pp := p.PowerProfile; pp != nil && *pp != operatorv1.VPPPowerProfileBusyPoll && *pp != operatorv1.VPPPowerProfileSleepWhenIdle {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].powerProfile %s is invalid, should be one of %s,%s",
+				p.Name, *pp, operatorv1.VPPPowerProfileBusyPoll, operatorv1.VPPPowerProfileSleepWhenIdle)
+		}
+		if ce := p.CryptoEngine; ce != nil && *ce != operatorv1.VPPCryptoEngineNative && *ce != operatorv1.VPPCryptoEngineIPsecMB &&
+			*ce != operatorv1.VPPCryptoEngineQAT && *ce != operatorv1.VPPCryptoEngineAsync {
+			return fmt.Errorf("spec.calicoNetwork.vppProfiles[%s].cryptoEngine %s is invalid, should be one of %s,%s,%s,%s",
+				p.Name, *ce, operatorv1.VPPCryptoEngineNative, operatorv1.VPPCryptoEngineIPsecMB, operatorv1.VPPCryptoEngineQAT, operatorv1.VPPCryptoEngineAsync)
+		}
+		if p.Plugins != nil {
+			if err := validateVPPPlugins(&p); err != nil {
+				return err
+			}
+		}
+		if p.DPDK != nil {
+			if err := validateVPPDPDK(&p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -15,11 +15,14 @@
 package installation
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	operator "github.com/tigera/operator/api/v1"
 )
@@ -166,6 +169,45 @@ var _ = Describe("Installation validation tests", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should require the FlexVolumePath unless only the CSI policy sync driver is used", func() {
+		instance.Spec.FlexVolumePath = "None"
+		both := operator.PolicySyncDriverBoth
+		instance.Spec.PolicySyncDriver = &both
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		csi := operator.PolicySyncDriverCSI
+		instance.Spec.PolicySyncDriver = &csi
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.KubeletVolumePluginPath = "var/lib/kubelet"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should only allow CNI chaining with the provider CNI plugins", func() {
+		chaining := operator.CNIChainingEnabled
+		instance.Spec.CNI.Chaining = &chaining
+		err := validateCustomResource(instance)
+		Expect(err).To(MatchError("spec.cni.chaining is not supported with spec.cni.type Calico"))
+
+		instance.Spec.KubernetesProvider = operator.ProviderEKS
+		instance.Spec.CNI.Type = operator.PluginAmazonVPC
+		instance.Spec.CNI.IPAM.Type = operator.IPAMPluginAmazonVPC
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should allow HostPorts with a provider CNI plugin only when CNI chaining is enabled", func() {
+		hp := operator.HostPortsEnabled
+		instance.Spec.KubernetesProvider = operator.ProviderEKS
+		instance.Spec.CNI.Type = operator.PluginAmazonVPC
+		instance.Spec.CNI.IPAM.Type = operator.IPAMPluginAmazonVPC
+		instance.Spec.CalicoNetwork.HostPorts = &hp
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		chaining := operator.CNIChainingEnabled
+		instance.Spec.CNI.Chaining = &chaining
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
 	It("should not allow HostPorts to be disabled with VPP", func() {
 		vpp := operator.LinuxDataplaneVPP
 		bgp := operator.BGPEnabled
@@ -182,6 +224,238 @@ var _ = Describe("Installation validation tests", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should validate the VPP profiles", func() {
+		vpp := operator.LinuxDataplaneVPP
+		bgp := operator.BGPEnabled
+		workers := int32(2)
+		instance.Spec.CNI.Type = operator.PluginCalico
+		instance.Spec.CalicoNetwork.BGP = &bgp
+		instance.Spec.CalicoNetwork.VPPProfiles = []operator.VPPProfile{
+			{Name: "dpdk", NodeSelector: map[string]string{"nic": "mlx5"}, Workers: &workers},
+			{Name: "virtio"},
+		}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.LinuxDataplane = &vpp
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.VPPProfiles[1].Name = "dpdk"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.VPPProfiles[1].Name = "Virtio_VMs"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.VPPProfiles[1].Name = "virtio"
+		powerProfile := operator.VPPPowerProfile("Turbo")
+		instance.Spec.CalicoNetwork.VPPProfiles[1].PowerProfile = &powerProfile
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[virtio].powerProfile Turbo is invalid, should be one of BusyPoll,SleepWhenIdle"))
+
+		powerProfile = operator.VPPPowerProfileSleepWhenIdle
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		cryptoEngine := operator.VPPCryptoEngine("OpenSSL")
+		instance.Spec.CalicoNetwork.VPPProfiles[1].CryptoEngine = &cryptoEngine
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[virtio].cryptoEngine OpenSSL is invalid, should be one of Native,IPsecMB,QAT,Async"))
+
+		cryptoEngine = operator.VPPCryptoEngineAsync
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.VPPProfiles[1].DPDK = &operator.VPPDPDK{AllowedPCIDevices: []string{"0000:00:08.0"}}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[virtio].dpdk requires the dpdk uplinkDriver or the QAT cryptoEngine"))
+
+		cryptoEngine = operator.VPPCryptoEngineQAT
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.VPPProfiles[1].DPDK.DeniedPCIDevices = []string{"0000:00:09.0"}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[virtio].dpdk.allowedPCIDevices and deniedPCIDevices are mutually exclusive"))
+
+		instance.Spec.CalicoNetwork.VPPProfiles[1].DPDK.AllowedPCIDevices = nil
+		instance.Spec.CalicoNetwork.VPPProfiles[1].DPDK.DeniedPCIDevices = []string{"eth0"}
+		Expect(validateCustomResource(instance)).To(MatchError(`spec.calicoNetwork.vppProfiles[virtio].dpdk PCI device "eth0" is not a PCI address`))
+
+		instance.Spec.CalicoNetwork.VPPProfiles[1].DPDK.DeniedPCIDevices = nil
+		instance.Spec.CalicoNetwork.VPPProfiles[1].DPDK.ExtraArgs = []string{"}\nunix { exec /tmp/x"}
+		Expect(validateCustomResource(instance)).To(MatchError(`spec.calicoNetwork.vppProfiles[virtio].dpdk.extraArgs "}\nunix { exec /tmp/x" is not a single directive`))
+		instance.Spec.CalicoNetwork.VPPProfiles[1].DPDK = nil
+
+		disabled := operator.VPPPluginDefaultDisabled
+		instance.Spec.CalicoNetwork.VPPProfiles[1].Plugins = &operator.VPPPlugins{
+			Default: &disabled,
+			Enabled: []string{"crypto_native_plugin.so"},
+		}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[virtio].plugins.default Disabled requires an uplinkDriver"))
+
+		virtio := operator.VPPUplinkDriverVirtio
+		instance.Spec.CalicoNetwork.VPPProfiles[1].UplinkDriver = &virtio
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.VPPProfiles[1].Plugins.Disabled = []string{"calico_plugin.so"}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[virtio].plugins.disabled calico_plugin.so is enabled or required by the dataplane"))
+		instance.Spec.CalicoNetwork.VPPProfiles[1].Plugins.Disabled = []string{"cnat_plugin.so"}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[virtio].plugins.disabled cnat_plugin.so is enabled or required by the dataplane"))
+
+		instance.Spec.CalicoNetwork.VPPProfiles[1].Plugins.Disabled = []string{"nat_plugin.so { enable }"}
+		Expect(validateCustomResource(instance)).To(MatchError(`spec.calicoNetwork.vppProfiles[virtio].plugins.disabled "nat_plugin.so { enable }" is not a plugin file name`))
+		instance.Spec.CalicoNetwork.VPPProfiles[1].Plugins = nil
+
+		mainCore := int32(1)
+		instance.Spec.CalicoNetwork.VPPProfiles[0].CPUPinning = &operator.VPPCPUPinning{Mode: operator.VPPCPUPinningCPUManager, MainCore: &mainCore}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[dpdk].cpuPinning mainCore and workerCores require the Manual mode"))
+
+		instance.Spec.CalicoNetwork.VPPProfiles[0].CPUPinning = &operator.VPPCPUPinning{Mode: operator.VPPCPUPinningCPUManager}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.VPPProfiles[0].CPUPinning = &operator.VPPCPUPinning{Mode: operator.VPPCPUPinningManual, MainCore: &mainCore, WorkerCores: "2-5,8"}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[dpdk].cpuPinning.workerCores can't be set along with workers"))
+
+		instance.Spec.CalicoNetwork.VPPProfiles[0].Workers = nil
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.VPPProfiles[0].CPUPinning.WorkerCores = "5-2"
+		Expect(validateCustomResource(instance)).To(MatchError(`spec.calicoNetwork.vppProfiles[dpdk].cpuPinning.workerCores "5-2" is not a list of cores`))
+
+		instance.Spec.CalicoNetwork.VPPProfiles[0].CPUPinning.WorkerCores = "0-3"
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[dpdk].cpuPinning.workerCores includes the mainCore 1"))
+
+		instance.Spec.CalicoNetwork.VPPProfiles[0].CPUPinning = &operator.VPPCPUPinning{Mode: operator.VPPCPUPinningManual}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppProfiles[dpdk].cpuPinning requires mainCore or workerCores with the Manual mode"))
+		instance.Spec.CalicoNetwork.VPPProfiles[0].CPUPinning = nil
+		instance.Spec.CalicoNetwork.VPPProfiles[0].Workers = &workers
+
+		workers = -1
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should validate the VPP tunnels", func() {
+		vpp := operator.LinuxDataplaneVPP
+		bgp := operator.BGPEnabled
+		instance.Spec.CNI.Type = operator.PluginCalico
+		instance.Spec.CalicoNetwork.BGP = &bgp
+		instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{{
+			CIDR:          "192.168.0.0/16",
+			Encapsulation: operator.EncapsulationNone,
+			NATOutgoing:   operator.NATOutgoingEnabled,
+			NodeSelector:  "all()",
+			VPPTunnel:     "gtpu",
+		}}
+		instance.Spec.CalicoNetwork.VPPTunnels = []operator.VPPTunnel{{Name: "gtpu", Type: operator.VPPTunnelGTPU}}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppTunnels requires the VPP dataplane"))
+
+		instance.Spec.CalicoNetwork.LinuxDataplane = &vpp
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.mtu should be set since the detected MTU doesn't leave room for the VPP tunnel gtpu"))
+
+		overhead, mtu := int32(36), int32(9200)
+		instance.Spec.CalicoNetwork.VPPTunnels[0].Overhead = &overhead
+		instance.Spec.CalicoNetwork.MTU = &mtu
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.mtu (9200) and the overhead (36) of the VPP tunnel gtpu exceed the largest VPP frame size 9216"))
+
+		mtu = 1400
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.IPPools[0].Encapsulation = operator.EncapsulationVXLAN
+		Expect(validateCustomResource(instance)).To(MatchError("ipPool.encapsulation of 192.168.0.0/16 should be None since it is encapsulated by the VPP tunnel gtpu"))
+
+		instance.Spec.CalicoNetwork.IPPools[0].Encapsulation = operator.EncapsulationNone
+		instance.Spec.CalicoNetwork.IPPools[0].VPPTunnel = "geneve"
+		Expect(validateCustomResource(instance)).To(MatchError("ipPool.vppTunnel geneve of 192.168.0.0/16 is not one of spec.calicoNetwork.vppTunnels"))
+
+		instance.Spec.CalicoNetwork.VPPTunnels = append(instance.Spec.CalicoNetwork.VPPTunnels, operator.VPPTunnel{Name: "gtpu", Type: operator.VPPTunnelGENEVE})
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppTunnels.name gtpu is not unique"))
+	})
+
+	It("should validate the NAT64 configuration of VPP", func() {
+		vpp := operator.LinuxDataplaneVPP
+		bgp := operator.BGPEnabled
+		instance.Spec.CNI.Type = operator.PluginCalico
+		instance.Spec.CalicoNetwork.BGP = &bgp
+		instance.Spec.CalicoNetwork.VPPNAT64 = &operator.VPPNAT64{Prefix: "64:ff9b::/96"}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppNAT64 requires the VPP dataplane"))
+
+		instance.Spec.CalicoNetwork.LinuxDataplane = &vpp
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppNAT64 requires an IPv6 IP pool"))
+
+		instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{{
+			CIDR:          "fd00::/64",
+			Encapsulation: operator.EncapsulationNone,
+			NATOutgoing:   operator.NATOutgoingDisabled,
+			NodeSelector:  "all()",
+		}}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.VPPNAT64.Prefix = "64:ff9b::/80"
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppNAT64.prefix (64:ff9b::/80) length should be one of 32, 40, 48, 56, 64 or 96"))
+
+		instance.Spec.CalicoNetwork.VPPNAT64.Prefix = "10.0.0.0/8"
+		Expect(validateCustomResource(instance)).To(MatchError("spec.calicoNetwork.vppNAT64.prefix (10.0.0.0/8) is not an IPv6 CIDR"))
+	})
+
+	It("should validate the dataplane sync settings", func() {
+		routeRefresh := int32(0)
+		ipsetsRefresh := int32(-1)
+		instance.Spec.DataplaneSync = &operator.DataplaneSync{RouteRefreshIntervalSeconds: &routeRefresh}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.DataplaneSync.IpsetsRefreshIntervalSeconds = &ipsetsRefresh
+		Expect(validateCustomResource(instance)).To(MatchError("spec.dataplaneSync.ipsetsRefreshIntervalSeconds must not be negative"))
+	})
+
+	It("should validate the route reflectors", func() {
+		en := operator.BGPEnabled
+		dis := operator.BGPDisabled
+		instance.Spec.CalicoNetwork.RouteReflectors = &operator.RouteReflectors{ClusterID: "244.0.0.1"}
+		instance.Spec.CalicoNetwork.BGP = &dis
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.BGP = &en
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.RouteReflectors.ClusterID = "fd00::1"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		var zero int32
+		instance.Spec.CalicoNetwork.RouteReflectors.ClusterID = ""
+		instance.Spec.CalicoNetwork.RouteReflectors.Replicas = &zero
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should validate the service advertisement", func() {
+		en := operator.BGPEnabled
+		dis := operator.BGPDisabled
+		instance.Spec.CalicoNetwork.ServiceAdvertisement = &operator.ServiceAdvertisement{
+			ServiceClusterIPs:  []string{"10.96.0.0/12"},
+			ServiceExternalIPs: []string{"192.0.2.0/24"},
+		}
+		instance.Spec.CalicoNetwork.BGP = &dis
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.BGP = &en
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.ServiceAdvertisement.ServiceLoadBalancerIPs = []string{"192.0.2.1"}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should validate the BGP peering templates", func() {
+		en := operator.BGPEnabled
+		instance.Spec.CalicoNetwork.BGP = &en
+		instance.Spec.CalicoNetwork.BGPPeeringTemplates = []operator.BGPPeeringTemplate{{
+			Name:      "tor",
+			NodeLabel: "rack",
+			Values: []operator.BGPPeeringTemplateValue{
+				{Value: "r1", Peers: []operator.BGPPeerDefinition{{PeerIP: "10.0.1.1", ASNumber: 65001}, {PeerIP: "[fd00::1]:179", ASNumber: 65001}}},
+			},
+		}}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.BGPPeeringTemplates[0].Values[0].Peers[0].PeerIP = "tor1"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.BGPPeeringTemplates[0].Values[0].Peers[0].PeerIP = "10.0.1.1"
+		instance.Spec.CalicoNetwork.BGPPeeringTemplates = append(instance.Spec.CalicoNetwork.BGPPeeringTemplates,
+			instance.Spec.CalicoNetwork.BGPPeeringTemplates[0])
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
 	It("should prevent IPIP if BGP is disabled", func() {
 		disabled := operator.BGPDisabled
 		instance.Spec.CalicoNetwork.BGP = &disabled
@@ -325,6 +599,130 @@ var _ = Describe("Installation validation tests", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("should validate the AWS secondary IPs", func() {
+		instance.Spec.CalicoNetwork.AWSSecondaryIPs = &operator.AWSSecondaryIPs{
+			IAMRoleARN: "arn:aws:iam::123456789012:role/calico-node",
+			SubnetPools: []operator.AWSSubnetPool{
+				{Name: "egress-a", CIDR: "10.0.16.0/24", SubnetID: "subnet-0123456789abcdef0"},
+			},
+		}
+		err := validateCustomResource(instance)
+		Expect(err).To(MatchError("spec.calicoNetwork.awsSecondaryIPs is only supported for spec.variant TigeraSecureEnterprise"))
+
+		instance.Spec.Variant = operator.TigeraSecureEnterprise
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.AWSSecondaryIPs.SubnetPools[0].SubnetID = "sn-0123"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.AWSSecondaryIPs.SubnetPools[0].SubnetID = "subnet-0123456789abcdef0"
+		instance.Spec.CalicoNetwork.AWSSecondaryIPs.SubnetPools[0].CIDR = "fd00:10::/120"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.AWSSecondaryIPs.SubnetPools[0].CIDR = "10.0.16.0/24"
+		instance.Spec.CalicoNetwork.AWSSecondaryIPs.SubnetPools = append(instance.Spec.CalicoNetwork.AWSSecondaryIPs.SubnetPools,
+			operator.AWSSubnetPool{Name: "egress-a", CIDR: "10.0.17.0/24", SubnetID: "subnet-0123456789abcdef1"})
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.CalicoNetwork.AWSSecondaryIPs.SubnetPools = nil
+		instance.Spec.CNI.Type = operator.PluginAmazonVPC
+		instance.Spec.CNI.IPAM.Type = operator.IPAMPluginAmazonVPC
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should not allow Calico to run in non-privileged mode if node-init is enabled", func() {
+		np := operator.NonPrivilegedEnabled
+		instance.Spec.NonPrivileged = &np
+		enabled := operator.NodeInitEnabled
+		instance.Spec.NodeInit = &operator.NodeInit{State: &enabled}
+		err := validateCustomResource(instance)
+		Expect(err).To(MatchError("Non-privileged Calico is not supported when spec.NodeInit is enabled"))
+	})
+
+	It("should validate the node-init sysctls", func() {
+		instance.Spec.NodeInit = &operator.NodeInit{
+			Sysctls: []operator.Sysctl{
+				{Key: "net.core.rmem_max", Value: "16777216"},
+				{Key: "net.ipv4.tcp_rmem", Value: "4096 87380 16777216"},
+			},
+		}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.NodeInit.Sysctls[0].Key = "../../etc/passwd"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+
+		instance.Spec.NodeInit.Sysctls[0].Key = "net.core.rmem_max"
+		instance.Spec.NodeInit.Sysctls[1].Value = "1'; reboot; echo '"
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+	})
+
+	It("should validate the kube-controllers configuration", func() {
+		instance.Spec.KubeControllers = &operator.KubeControllers{
+			EnabledControllers: []operator.KubeControllerType{operator.KubeControllerNode},
+			Node: &operator.KubeControllersNode{
+				ReconcilerPeriod: &metav1.Duration{Duration: time.Minute},
+				LeakGracePeriod:  &metav1.Duration{},
+			},
+		}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.KubeControllers.Node.ReconcilerPeriod.Duration = 0
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+		instance.Spec.KubeControllers.Node.ReconcilerPeriod.Duration = time.Minute
+
+		instance.Spec.KubeControllers.EnabledControllers = append(instance.Spec.KubeControllers.EnabledControllers, operator.KubeControllerNode)
+		Expect(validateCustomResource(instance)).To(MatchError("spec.KubeControllers.EnabledControllers: Node is listed more than once"))
+
+		instance.Spec.KubeControllers.EnabledControllers = []operator.KubeControllerType{operator.KubeControllerFederatedServices}
+		Expect(validateCustomResource(instance)).To(HaveOccurred())
+		instance.Spec.Variant = operator.TigeraSecureEnterprise
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
+	It("should validate the Kubernetes service endpoint", func() {
+		instance.Spec.KubernetesServiceEndpoint = &operator.KubernetesServiceEndpoint{Host: "10.0.0.100", Port: 6443}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.KubernetesServiceEndpoint.Port = 0
+		Expect(validateCustomResource(instance)).To(MatchError("spec.kubernetesServiceEndpoint.port (0) is not a valid port"))
+
+		instance.Spec.KubernetesServiceEndpoint = &operator.KubernetesServiceEndpoint{Port: 6443}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.kubernetesServiceEndpoint.host must be set"))
+	})
+
+	It("should validate the rollout watchdog", func() {
+		instance.Spec.RolloutWatchdog = &operator.RolloutWatchdog{StallTimeout: &metav1.Duration{Duration: 5 * time.Minute}}
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		instance.Spec.RolloutWatchdog.StallTimeout.Duration = 0
+		Expect(validateCustomResource(instance)).To(MatchError("spec.rolloutWatchdog.stallTimeout must be positive"))
+	})
+
+	It("should validate the shutdown of calico-node", func() {
+		var grace int64 = 30
+		var drain int32 = 20
+		instance.Spec.NodeShutdown = &operator.NodeShutdown{DrainSeconds: &drain}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.nodeShutdown.terminationGracePeriodSeconds must be set with spec.nodeShutdown.drainSeconds"))
+
+		instance.Spec.NodeShutdown.TerminationGracePeriodSeconds = &grace
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+		drain = 30
+		Expect(validateCustomResource(instance)).To(MatchError("spec.nodeShutdown.terminationGracePeriodSeconds (30) must be larger than spec.nodeShutdown.drainSeconds (30)"))
+	})
+
+	It("should only allow the dataplane migration with VPP", func() {
+		instance.Spec.DataplaneMigration = &operator.DataplaneMigration{}
+		Expect(validateCustomResource(instance)).To(MatchError("spec.dataplaneMigration requires spec.calicoNetwork.linuxDataplane to be VPP"))
+
+		vpp := operator.LinuxDataplaneVPP
+		bgp := operator.BGPEnabled
+		instance.Spec.CalicoNetwork.LinuxDataplane = &vpp
+		instance.Spec.CalicoNetwork.BGP = &bgp
+		instance.Spec.CNI.Type = operator.PluginCalico
+		Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+	})
+
 	Describe("validate Calico CNI plugin Type", func() {
 		DescribeTable("test invalid IPAM",
 			func(ipam operator.IPAMPluginType) {
@@ -393,6 +791,45 @@ var _ = Describe("Installation validation tests", func() {
 				err := validateCustomResource(instance)
 				Expect(err).NotTo(HaveOccurred())
 			})
+
+			It("with IPPool with IPIP encapsulation requires BGP", func() {
+				instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{
+					{
+						CIDR:          "192.168.0.0/24",
+						Encapsulation: operator.EncapsulationIPIP,
+						NATOutgoing:   operator.NATOutgoingEnabled,
+						NodeSelector:  "all()",
+					},
+				}
+				Expect(fillDefaults(instance)).NotTo(HaveOccurred())
+				Expect(validateCustomResource(instance)).To(MatchError("IPIP encapsulation requires that BGP is enabled"))
+
+				enable := operator.BGPEnabled
+				instance.Spec.CalicoNetwork.BGP = &enable
+				Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+			})
+
+			It("with IPPools which don't select all the nodes fails", func() {
+				instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{
+					{
+						CIDR:          "192.168.0.0/24",
+						Encapsulation: operator.EncapsulationNone,
+						NATOutgoing:   operator.NATOutgoingEnabled,
+						NodeSelector:  "all()",
+					},
+					{
+						CIDR:          "fd00::/64",
+						Encapsulation: operator.EncapsulationNone,
+						NATOutgoing:   operator.NATOutgoingDisabled,
+						NodeSelector:  "edge == 'true'",
+					},
+				}
+				Expect(fillDefaults(instance)).NotTo(HaveOccurred())
+				Expect(validateCustomResource(instance)).To(MatchError("ipPool.nodeSelector (edge == 'true') should be 'all()' with HostLocal IPAM"))
+
+				instance.Spec.CalicoNetwork.IPPools[1].NodeSelector = "all()"
+				Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+			})
 		})
 	})
 	Describe("validate non-calico CNI plugin Type", func() {
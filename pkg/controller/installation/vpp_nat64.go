@@ -0,0 +1,64 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// maxNAT64NodeStatuses is the maximum number of nodes listed in the NAT64 status of the Installation, so that its size
+// doesn't grow with the cluster.
+const maxNAT64NodeStatuses = 100
+
+// vppNAT64Status returns the NAT64 status of the nodes of the VPP dataplane, as annotated by their VPP agent, or nil if
+// NAT64 is not configured. The nodes where NAT64 isn't ready come first, and at most maxNAT64NodeStatuses nodes are
+// returned.
+func vppNAT64Status(ctx context.Context, cli client.Client, install *operator.InstallationSpec) ([]operator.NodeNAT64Status, error) {
+	if install.CalicoNetwork == nil || install.CalicoNetwork.VPPNAT64 == nil {
+		return nil, nil
+	}
+
+	nodes := &metav1.PartialObjectMetadataList{TypeMeta: metav1.TypeMeta{Kind: "NodeList", APIVersion: "v1"}}
+	if err := cli.List(ctx, nodes, client.MatchingLabels(render.VPPNodeSelector(install))); err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+
+	st := []operator.NodeNAT64Status{}
+	for _, node := range nodes.Items {
+		ns := operator.NodeNAT64Status{Name: node.Name}
+		switch v, ok := node.Annotations[render.VPPNAT64StatusAnnotation]; {
+		case !ok:
+			ns.Message = "waiting for the VPP agent to configure NAT64"
+		case v == render.VPPNAT64StatusReady:
+			ns.Ready = true
+		default:
+			ns.Message = v
+		}
+		st = append(st, ns)
+	}
+	sort.SliceStable(st, func(i, j int) bool { return !st[i].Ready && st[j].Ready })
+	if len(st) > maxNAT64NodeStatuses {
+		st = st[:maxNAT64NodeStatuses]
+	}
+	return st, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("VPP NAT64 status", func() {
+	var (
+		c   client.Client
+		ctx context.Context
+	)
+
+	createNode := func(name string, labels, annotations map[string]string) {
+		Expect(c.Create(ctx, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+		})).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		ctx = context.Background()
+		c = fake.NewClientBuilder().WithScheme(scheme).Build()
+	})
+
+	It("should report the NAT64 status of the VPP nodes, the nodes where it isn't ready first", func() {
+		linux := map[string]string{"kubernetes.io/os": "linux"}
+		createNode("node-b", linux, map[string]string{render.VPPNAT64StatusAnnotation: "no IPv4 address on the uplink"})
+		createNode("node-a", linux, map[string]string{render.VPPNAT64StatusAnnotation: render.VPPNAT64StatusReady})
+		createNode("node-c", linux, nil)
+		createNode("windows", map[string]string{"kubernetes.io/os": "windows"}, nil)
+
+		install := &operator.InstallationSpec{
+			CalicoNetwork: &operator.CalicoNetworkSpec{VPPNAT64: &operator.VPPNAT64{Prefix: render.DefaultVPPNAT64Prefix}},
+		}
+		st, err := vppNAT64Status(ctx, c, install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st).To(Equal([]operator.NodeNAT64Status{
+			{Name: "node-b", Message: "no IPv4 address on the uplink"},
+			{Name: "node-c", Message: "waiting for the VPP agent to configure NAT64"},
+			{Name: "node-a", Ready: true},
+		}))
+	})
+
+	It("should only report the nodes migrated to VPP during a dataplane migration", func() {
+		createNode("node-a", map[string]string{"kubernetes.io/os": "linux", common.LinuxDataplaneLabel: string(operator.LinuxDataplaneVPP)}, nil)
+		createNode("node-b", map[string]string{"kubernetes.io/os": "linux"}, nil)
+
+		install := &operator.InstallationSpec{
+			CalicoNetwork:      &operator.CalicoNetworkSpec{VPPNAT64: &operator.VPPNAT64{Prefix: render.DefaultVPPNAT64Prefix}},
+			DataplaneMigration: &operator.DataplaneMigration{},
+		}
+		st, err := vppNAT64Status(ctx, c, install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st).To(Equal([]operator.NodeNAT64Status{{Name: "node-a", Message: "waiting for the VPP agent to configure NAT64"}}))
+	})
+
+	It("should bound the number of nodes", func() {
+		for i := 0; i < maxNAT64NodeStatuses+10; i++ {
+			createNode(fmt.Sprintf("node-%03d", i), map[string]string{"kubernetes.io/os": "linux"}, nil)
+		}
+		install := &operator.InstallationSpec{
+			CalicoNetwork: &operator.CalicoNetworkSpec{VPPNAT64: &operator.VPPNAT64{Prefix: render.DefaultVPPNAT64Prefix}},
+		}
+		st, err := vppNAT64Status(ctx, c, install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st).To(HaveLen(maxNAT64NodeStatuses))
+	})
+
+	It("should not report any status without NAT64", func() {
+		createNode("node-a", nil, nil)
+		st, err := vppNAT64Status(ctx, c, &operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st).To(BeNil())
+	})
+})
@@ -0,0 +1,123 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// vppProfilesConfiguration returns the configuration of the VPP profiles component, with the previously rendered
+// ConfigMaps of the profiles and the validated overrides of their startup configuration.
+func (r *ReconcileInstallation) vppProfilesConfiguration(ctx context.Context, install *operator.InstallationSpec) (*render.VPPProfilesConfiguration, error) {
+	cfg := &render.VPPProfilesConfiguration{Installation: install, StartupConfigOverrides: map[string]string{}}
+
+	cms := &corev1.ConfigMapList{}
+	if err := r.client.List(ctx, cms, client.InNamespace(common.CalicoNamespace), client.HasLabels{render.VPPProfileLabel}); err != nil {
+		return nil, err
+	}
+	cfg.ProfileConfigMaps = cms.Items
+
+	if install.CalicoNetwork == nil {
+		return cfg, nil
+	}
+	for _, p := range install.CalicoNetwork.VPPProfiles {
+		ref := p.StartupConfigOverride
+		if ref == nil {
+			continue
+		}
+		cm := &corev1.ConfigMap{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: common.OperatorNamespace()}, cm)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		override, ok := cm.Data[ref.Key]
+		if err != nil || !ok {
+			if ref.Optional != nil && *ref.Optional {
+				continue
+			}
+			return nil, fmt.Errorf("key %s of ConfigMap %s/%s not found for the startup configuration of VPP profile %s",
+				ref.Key, common.OperatorNamespace(), ref.Name, p.Name)
+		}
+		if err = render.ValidateVPPStartupConfigOverride(override); err != nil {
+			return nil, fmt.Errorf("invalid startup configuration override of VPP profile %s: %w", p.Name, err)
+		}
+		cfg.StartupConfigOverrides[p.Name] = override
+	}
+	return cfg, nil
+}
+
+// selectVPPProfile returns the name of the first of the given profiles matching the node, or an empty string if none
+// matches. Only Linux nodes get a profile, and only the nodes with QAT devices get a profile with the QAT crypto
+// engine.
+func selectVPPProfile(node *corev1.Node, profiles []operator.VPPProfile) string {
+	if os, ok := node.Labels["kubernetes.io/os"]; ok && os != "linux" {
+		return ""
+	}
+	for _, p := range profiles {
+		if p.CryptoEngine != nil && *p.CryptoEngine == operator.VPPCryptoEngineQAT && node.Labels[render.VPPQATNodeLabel] != "true" {
+			continue
+		}
+		if labels.SelectorFromSet(p.NodeSelector).Matches(labels.Set(node.Labels)) {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// reconcileVPPProfileNodes labels the nodes with the name of their VPP profile, and removes the label from the nodes
+// without a profile.
+func (r *ReconcileInstallation) reconcileVPPProfileNodes(ctx context.Context, profiles []operator.VPPProfile, log logr.Logger) error {
+	nodes := &corev1.NodeList{}
+	if err := r.client.List(ctx, nodes); err != nil {
+		r.SetDegraded("Unable to list nodes", err, log)
+		return err
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		profile := selectVPPProfile(node, profiles)
+		current, labelled := node.Labels[render.VPPProfileLabel]
+		if profile == current && (profile != "" || !labelled) {
+			continue
+		}
+
+		patchFrom := client.MergeFrom(node.DeepCopy())
+		if profile == "" {
+			delete(node.Labels, render.VPPProfileLabel)
+		} else {
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[render.VPPProfileLabel] = profile
+		}
+		if err := r.client.Patch(ctx, node, patchFrom); err != nil {
+			r.SetDegraded("Unable to Patch VPP profile node", err, log)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("VPP profiles", func() {
+	var (
+		c      client.Client
+		ctx    context.Context
+		r      *ReconcileInstallation
+		reqLog = logf.Log.WithName("test")
+	)
+
+	profiles := []operator.VPPProfile{
+		{Name: "dpdk", NodeSelector: map[string]string{"nic": "mlx5"}},
+		{Name: "virtio", NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "vm"}},
+	}
+
+	nodeProfiles := func() map[string]string {
+		nodes := &corev1.NodeList{}
+		Expect(c.List(ctx, nodes)).NotTo(HaveOccurred())
+		profiles := map[string]string{}
+		for _, n := range nodes.Items {
+			if p, ok := n.Labels[render.VPPProfileLabel]; ok {
+				profiles[n.Name] = p
+			}
+		}
+		return profiles
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+		ctx = context.Background()
+		r = &ReconcileInstallation{client: c, scheme: scheme, status: &status.MockStatus{}}
+
+		for _, n := range []*corev1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "bare-metal", Labels: map[string]string{"nic": "mlx5"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "vm", Labels: map[string]string{"node.kubernetes.io/instance-type": "vm"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "windows", Labels: map[string]string{"nic": "mlx5", "kubernetes.io/os": "windows"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		} {
+			Expect(c.Create(ctx, n)).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should give a node the first profile it matches", func() {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"nic": "mlx5"}}}
+		Expect(selectVPPProfile(node, profiles)).To(Equal("dpdk"))
+		Expect(selectVPPProfile(node, append([]operator.VPPProfile{{Name: "all"}}, profiles...))).To(Equal("all"))
+		Expect(selectVPPProfile(&corev1.Node{}, profiles)).To(BeEmpty())
+	})
+
+	It("should only give the profiles with the QAT crypto engine to the nodes with QAT devices", func() {
+		qat := operator.VPPCryptoEngineQAT
+		qatProfiles := append([]operator.VPPProfile{{Name: "qat", NodeSelector: map[string]string{"nic": "mlx5"}, CryptoEngine: &qat}}, profiles...)
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"nic": "mlx5"}}}
+		Expect(selectVPPProfile(node, qatProfiles)).To(Equal("dpdk"))
+
+		node.Labels[render.VPPQATNodeLabel] = "true"
+		Expect(selectVPPProfile(node, qatProfiles)).To(Equal("qat"))
+	})
+
+	It("should label the nodes with their profile", func() {
+		Expect(r.reconcileVPPProfileNodes(ctx, profiles, reqLog)).NotTo(HaveOccurred())
+		Expect(nodeProfiles()).To(Equal(map[string]string{"bare-metal": "dpdk", "vm": "virtio"}))
+
+		Expect(r.reconcileVPPProfileNodes(ctx, profiles[1:], reqLog)).NotTo(HaveOccurred())
+		Expect(nodeProfiles()).To(Equal(map[string]string{"vm": "virtio"}))
+
+		Expect(r.reconcileVPPProfileNodes(ctx, nil, reqLog)).NotTo(HaveOccurred())
+		Expect(nodeProfiles()).To(BeEmpty())
+	})
+
+	It("should read and validate the overrides of the startup configuration", func() {
+		install := &operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{VPPProfiles: []operator.VPPProfile{{
+			Name: "dpdk",
+			StartupConfigOverride: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "vpp-startup"},
+				Key:                  "dpdk",
+			},
+		}}}}
+		_, err := r.vppProfilesConfiguration(ctx, install)
+		Expect(err).To(MatchError("key dpdk of ConfigMap tigera-operator/vpp-startup not found for the startup configuration of VPP profile dpdk"))
+
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "vpp-startup", Namespace: common.OperatorNamespace()},
+			Data:       map[string]string{"dpdk": "unix {\n  exec /tmp/evil\n}\n"},
+		}
+		Expect(c.Create(ctx, cm)).NotTo(HaveOccurred())
+		_, err = r.vppProfilesConfiguration(ctx, install)
+		Expect(err).To(MatchError("invalid startup configuration override of VPP profile dpdk: the exec directive of the unix section can't be overridden"))
+
+		cm.Data["dpdk"] = "dpdk {\n  dev default { num-rx-queues 4 }\n}\n"
+		Expect(c.Update(ctx, cm)).NotTo(HaveOccurred())
+		cfg, err := r.vppProfilesConfiguration(ctx, install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.StartupConfigOverrides).To(Equal(map[string]string{"dpdk": cm.Data["dpdk"]}))
+	})
+})
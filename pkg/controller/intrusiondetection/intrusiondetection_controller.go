@@ -66,7 +66,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	reconciler := newReconciler(mgr, opts, licenseAPIReady, dpiAPIReady)
 
 	// Create a new controller
-	controller, err := controller.New("intrusiondetection-controller", mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
+	controller, err := controller.New("intrusiondetection-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("intrusiondetection-controller", reconciler)})
 	if err != nil {
 		return fmt.Errorf("Failed to create intrusiondetection-controller: %v", err)
 	}
@@ -77,24 +77,31 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return err
 	}
 
-	go utils.WaitToAddLicenseKeyWatch(controller, k8sClient, log, licenseAPIReady)
+	go utils.WaitToAddLicenseKeyWatch(controller, k8sClient, log, licenseAPIReady, opts.LicenseKeyCache)
 
 	go utils.WaitToAddResourceWatch(controller, k8sClient, log, dpiAPIReady,
 		&v3.DeepPacketInspection{TypeMeta: metav1.TypeMeta{Kind: v3.KindDeepPacketInspection}})
 
+	// Re-render the PodSecurityPolicies when the cluster is upgraded.
+	if err = utils.AddKubernetesVersionWatch(controller, opts.KubernetesVersion, utils.DefaultTSEEInstanceKey); err != nil {
+		return fmt.Errorf("intrusiondetection-controller failed to watch the Kubernetes version: %w", err)
+	}
+
 	return add(mgr, controller)
 }
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady *utils.ReadyFlag, dpiAPIReady *utils.ReadyFlag) reconcile.Reconciler {
 	r := &ReconcileIntrusionDetection{
-		client:          mgr.GetClient(),
-		scheme:          mgr.GetScheme(),
-		provider:        opts.DetectedProvider,
-		status:          status.New(mgr.GetClient(), "intrusion-detection", opts.KubernetesVersion),
-		clusterDomain:   opts.ClusterDomain,
-		licenseAPIReady: licenseAPIReady,
-		dpiAPIReady:     dpiAPIReady,
+		client:            mgr.GetClient(),
+		scheme:            mgr.GetScheme(),
+		provider:          opts.DetectedProvider,
+		status:            status.New(mgr.GetClient(), "intrusion-detection", opts.KubernetesVersion),
+		clusterDomain:     opts.ClusterDomain,
+		licenseAPIReady:   licenseAPIReady,
+		licenseKeyCache:   opts.LicenseKeyCache,
+		dpiAPIReady:       dpiAPIReady,
+		kubernetesVersion: opts.KubernetesVersion,
 	}
 	r.status.Run(opts.ShutdownContext)
 	return r
@@ -133,8 +140,8 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		return fmt.Errorf("intrusiondetection-controller failed to watch ImageSet: %w", err)
 	}
 
-	if err = utils.AddAPIServerWatch(c); err != nil {
-		return fmt.Errorf("intrusiondetection-controller failed to watch APIServer resource: %v", err)
+	if err = utils.AddCRDependencyWatches(c, utils.IntrusionDetectionCR); err != nil {
+		return fmt.Errorf("intrusiondetection-controller failed to watch the CRs it depends on: %v", err)
 	}
 
 	for _, secretName := range []string{
@@ -189,7 +196,10 @@ type ReconcileIntrusionDetection struct {
 	status          status.StatusManager
 	clusterDomain   string
 	licenseAPIReady *utils.ReadyFlag
+	licenseKeyCache *utils.LicenseKeyCache
 	dpiAPIReady     *utils.ReadyFlag
+	// kubernetesVersion is the version of the cluster, which the PodSecurityPolicies depend on.
+	kubernetesVersion *common.KubernetesVersionTracker
 }
 
 // Reconcile reads that state of the cluster for a IntrusionDetection object and makes changes based on the state read
@@ -227,24 +237,13 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		return reconcile.Result{}, err
 	}
 
-	if !utils.IsAPIServerReady(r.client, reqLogger) {
-		r.status.SetDegraded("Waiting for Tigera API server to be ready", "")
-		return reconcile.Result{}, err
-	}
-
-	if !r.licenseAPIReady.IsReady() {
-		r.status.SetDegraded("Waiting for LicenseKeyAPI to be ready", "")
-		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
-	}
-
-	license, err := utils.FetchLicenseKey(ctx, r.client)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			r.status.SetDegraded("License not found", err.Error())
-			return reconcile.Result{RequeueAfter: 10 * time.Second}, err
-		}
-		r.status.SetDegraded("Error querying license", err.Error())
-		return reconcile.Result{RequeueAfter: 10 * time.Second}, err
+	var license v3.LicenseKey
+	if result, unmet, err := utils.CheckDependencies(ctx, r.client, reqLogger, r.status,
+		utils.CRDependenciesReady(utils.IntrusionDetectionCR),
+		utils.LicenseAPIReady(r.licenseAPIReady),
+		utils.LicenseKeyInstalled(r.licenseKeyCache, &license),
+	); unmet {
+		return result, err
 	}
 
 	// Query for the installation object.
@@ -356,6 +355,7 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetHardening(network.Hardening)
 
 	reqLogger.V(3).Info("rendering components")
 	// Render the desired objects from the CRD and create or update them.
@@ -367,12 +367,12 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		Installation:             network,
 		ESClusterConfig:          esClusterConfig,
 		PullSecrets:              pullSecrets,
-		Openshift:                r.provider == operatorv1.ProviderOpenShift,
 		ClusterDomain:            r.clusterDomain,
 		ESLicenseType:            esLicenseType,
 		ManagedCluster:           managementClusterConnection != nil,
 		HasNoLicense:             hasNoLicense,
 		ManagerInternalTLSSecret: managerInternalTLSSecret,
+		KubernetesVersion:        r.kubernetesVersion.Get(),
 	}
 	component := render.IntrusionDetection(intrusionDetectionCfg)
 
@@ -435,7 +435,6 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		TyphaTLSSecret:     typhaTLSSecret,
 		TyphaCAConfigMap:   typhaCAConfigMap,
 		PullSecrets:        pullSecrets,
-		Openshift:          r.provider == operatorv1.ProviderOpenShift,
 		HasNoLicense:       hasNoLicense,
 		HasNoDPIResource:   hasNoDPIResource,
 		ESClusterConfig:    esClusterConfig,
@@ -22,6 +22,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -33,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operatorv1 "github.com/tigera/operator/api/v1"
 	v1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
@@ -62,7 +64,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	reconciler := newReconciler(mgr, opts, licenseAPIReady)
 
 	// Create a new controller
-	controller, err := controller.New("logcollector-controller", mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
+	controller, err := controller.New("logcollector-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("logcollector-controller", reconciler)})
 	if err != nil {
 		return fmt.Errorf("Failed to create logcollector-controller: %v", err)
 	}
@@ -73,7 +75,12 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return err
 	}
 
-	go utils.WaitToAddLicenseKeyWatch(controller, k8sClient, log, licenseAPIReady)
+	go utils.WaitToAddLicenseKeyWatch(controller, k8sClient, log, licenseAPIReady, opts.LicenseKeyCache)
+
+	// Re-render the PodSecurityPolicies when the cluster is upgraded.
+	if err = utils.AddKubernetesVersionWatch(controller, opts.KubernetesVersion, utils.DefaultTSEEInstanceKey); err != nil {
+		return fmt.Errorf("logcollector-controller failed to watch the Kubernetes version: %w", err)
+	}
 
 	return add(mgr, controller)
 }
@@ -81,12 +88,14 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady *utils.ReadyFlag) reconcile.Reconciler {
 	c := &ReconcileLogCollector{
-		client:          mgr.GetClient(),
-		scheme:          mgr.GetScheme(),
-		provider:        opts.DetectedProvider,
-		status:          status.New(mgr.GetClient(), "log-collector", opts.KubernetesVersion),
-		clusterDomain:   opts.ClusterDomain,
-		licenseAPIReady: licenseAPIReady,
+		client:            mgr.GetClient(),
+		scheme:            mgr.GetScheme(),
+		provider:          opts.DetectedProvider,
+		status:            status.New(mgr.GetClient(), "log-collector", opts.KubernetesVersion),
+		clusterDomain:     opts.ClusterDomain,
+		licenseAPIReady:   licenseAPIReady,
+		licenseKeyCache:   opts.LicenseKeyCache,
+		kubernetesVersion: opts.KubernetesVersion,
 	}
 	c.status.Run(opts.ShutdownContext)
 	return c
@@ -102,9 +111,9 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		return fmt.Errorf("logcollector-controller failed to watch primary resource: %v", err)
 	}
 
-	err = utils.AddAPIServerWatch(c)
+	err = utils.AddCRDependencyWatches(c, utils.LogCollectorCR)
 	if err != nil {
-		return fmt.Errorf("logcollector-controller failed to watch APIServer resource: %v", err)
+		return fmt.Errorf("logcollector-controller failed to watch the CRs it depends on: %v", err)
 	}
 
 	if err = utils.AddNetworkWatch(c); err != nil {
@@ -131,7 +140,16 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		}
 	}
 
-	err = c.Watch(&source.Kind{Type: &corev1.Node{}}, &handler.EnqueueRequestForObject{})
+	// The namespaces opt in or out of the log collection with a label.
+	err = c.Watch(&source.Kind{Type: &corev1.Namespace{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("logcollector-controller failed to watch the namespace resource: %w", err)
+	}
+
+	// Only the metadata of the nodes is cached since only their labels matter.
+	err = c.Watch(&source.Kind{Type: &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{Kind: "Node", APIVersion: "v1"},
+	}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
 		return fmt.Errorf("logcollector-controller failed to watch the node resource: %w", err)
 	}
@@ -152,6 +170,9 @@ type ReconcileLogCollector struct {
 	status          status.StatusManager
 	clusterDomain   string
 	licenseAPIReady *utils.ReadyFlag
+	licenseKeyCache *utils.LicenseKeyCache
+	// kubernetesVersion is the version of the cluster, which the PodSecurityPolicies depend on.
+	kubernetesVersion *common.KubernetesVersionTracker
 }
 
 // GetLogCollector returns the default LogCollector instance with defaults populated.
@@ -235,24 +256,13 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 	r.status.OnCRFound()
 	preDefaultPatchFrom := client.MergeFrom(instance.DeepCopy())
 
-	if !utils.IsAPIServerReady(r.client, reqLogger) {
-		r.status.SetDegraded("Waiting for Tigera API server to be ready", "")
-		return reconcile.Result{}, nil
-	}
-
-	if !r.licenseAPIReady.IsReady() {
-		r.status.SetDegraded("Waiting for LicenseKeyAPI to be ready", "")
-		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
-	}
-
-	license, err := utils.FetchLicenseKey(ctx, r.client)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			r.status.SetDegraded("License not found", err.Error())
-			return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
-		}
-		r.status.SetDegraded("Error querying license", err.Error())
-		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	var license v3.LicenseKey
+	if result, unmet, err := utils.CheckDependencies(ctx, r.client, reqLogger, r.status,
+		utils.CRDependenciesReady(utils.LogCollectorCR),
+		utils.LicenseAPIReady(r.licenseAPIReady),
+		utils.LicenseKeyInstalled(r.licenseKeyCache, &license),
+	); unmet {
+		return result, err
 	}
 	modifiedFields := fillDefaults(instance)
 	// Update the LogCollector instance with any changes that have occurred.
@@ -396,6 +406,14 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 		r.status.SetDegraded("Error retrieving Fluentd filters", err.Error())
 		return reconcile.Result{}, err
 	}
+	if selection := instance.Spec.NamespaceSelection; selection != nil {
+		namespaces, err := getLogCollectionNamespaces(ctx, r.client, *selection)
+		if err != nil {
+			r.status.SetDegraded("Error listing the namespaces of the log collection", err.Error())
+			return reconcile.Result{}, err
+		}
+		filters = render.AddNamespaceFilters(filters, *selection, namespaces)
+	}
 
 	var eksConfig *render.EksCloudwatchLogConfig
 	if installation.KubernetesProvider == operatorv1.ProviderEKS {
@@ -418,19 +436,21 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetHardening(installation.Hardening)
 
 	fluentdCfg := &render.FluentdConfiguration{
-		LogCollector:    instance,
-		ESSecrets:       esSecrets,
-		ESClusterConfig: esClusterConfig,
-		S3Credential:    s3Credential,
-		SplkCredential:  splunkCredential,
-		Filters:         filters,
-		EKSConfig:       eksConfig,
-		PullSecrets:     pullSecrets,
-		Installation:    installation,
-		ClusterDomain:   r.clusterDomain,
-		OSType:          rmeta.OSTypeLinux,
+		LogCollector:      instance,
+		ESSecrets:         esSecrets,
+		ESClusterConfig:   esClusterConfig,
+		S3Credential:      s3Credential,
+		SplkCredential:    splunkCredential,
+		Filters:           filters,
+		EKSConfig:         eksConfig,
+		PullSecrets:       pullSecrets,
+		Installation:      installation,
+		ClusterDomain:     r.clusterDomain,
+		OSType:            rmeta.OSTypeLinux,
+		KubernetesVersion: r.kubernetesVersion.Get(),
 	}
 	// Render the fluentd component for Linux
 	component := render.Fluentd(fluentdCfg)
@@ -454,17 +474,18 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 
 	if hasWindowsNodes {
 		fluentdCfg = &render.FluentdConfiguration{
-			LogCollector:    instance,
-			ESSecrets:       esSecrets,
-			ESClusterConfig: esClusterConfig,
-			S3Credential:    s3Credential,
-			SplkCredential:  splunkCredential,
-			Filters:         filters,
-			EKSConfig:       eksConfig,
-			PullSecrets:     pullSecrets,
-			Installation:    installation,
-			ClusterDomain:   r.clusterDomain,
-			OSType:          rmeta.OSTypeWindows,
+			LogCollector:      instance,
+			ESSecrets:         esSecrets,
+			ESClusterConfig:   esClusterConfig,
+			S3Credential:      s3Credential,
+			SplkCredential:    splunkCredential,
+			Filters:           filters,
+			EKSConfig:         eksConfig,
+			PullSecrets:       pullSecrets,
+			Installation:      installation,
+			ClusterDomain:     r.clusterDomain,
+			OSType:            rmeta.OSTypeWindows,
+			KubernetesVersion: r.kubernetesVersion.Get(),
 		}
 		component = render.Fluentd(fluentdCfg)
 
@@ -476,6 +497,7 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 
 		// Create a component handler to manage the rendered component.
 		handler = utils.NewComponentHandler(log, r.client, r.scheme, instance)
+		handler.SetHardening(installation.Hardening)
 
 		if err := handler.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
 			r.status.SetDegraded("Error creating / updating resource", err.Error())
@@ -607,9 +629,28 @@ func getFluentdFilters(client client.Client) (*render.FluentdFilters, error) {
 	return &render.FluentdFilters{
 		Flow: cm.Data[render.FluentdFilterFlowName],
 		DNS:  cm.Data[render.FluentdFilterDNSName],
+		L7:   cm.Data[render.FluentdFilterL7Name],
 	}, nil
 }
 
+// getLogCollectionNamespaces returns the namespaces labeled for the namespace selection of the log collection: the
+// namespaces opted in with OptIn, or opted out with OptOut.
+func getLogCollectionNamespaces(ctx context.Context, cli client.Client, selection operatorv1.LogCollectionNamespaceSelection) ([]string, error) {
+	value := render.LogCollectionNamespaceLabelValue(selection)
+	if value == "" {
+		return nil, nil
+	}
+	nsList := &corev1.NamespaceList{}
+	if err := cli.List(ctx, nsList, client.MatchingLabels{render.LogCollectionNamespaceLabel: value}); err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, ns := range nsList.Items {
+		namespaces = append(namespaces, ns.Name)
+	}
+	return namespaces, nil
+}
+
 func getEksCloudwatchLogConfig(client client.Client, interval int32, region, group, prefix string) (*render.EksCloudwatchLogConfig, error) {
 	if region == "" {
 		return nil, fmt.Errorf("Missing AWS region info")
@@ -72,6 +72,8 @@ var _ = Describe("LogCollector controller tests", func() {
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
+			mockStatus.On("AddJobs", mock.Anything)
+			mockStatus.On("IsRolloutPaused").Return(false)
 			mockStatus.On("IsAvailable").Return(true)
 			mockStatus.On("OnCRFound").Return()
 			mockStatus.On("ClearDegraded")
@@ -156,6 +158,35 @@ var _ = Describe("LogCollector controller tests", func() {
 					components.ComponentFluentd.Image,
 					components.ComponentFluentd.Version)))
 		})
+		It("should collect the flow and L7 logs of the namespaces opted in", func() {
+			for name, labels := range map[string]map[string]string{
+				"shop":    {render.LogCollectionNamespaceLabel: render.LogCollectionNamespaceEnabled},
+				"billing": {render.LogCollectionNamespaceLabel: render.LogCollectionNamespaceEnabled},
+				"noisy":   {render.LogCollectionNamespaceLabel: render.LogCollectionNamespaceDisabled},
+				"default": nil,
+			} {
+				Expect(c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}})).NotTo(HaveOccurred())
+			}
+			lc := &operatorv1.LogCollector{}
+			Expect(c.Get(ctx, utils.DefaultTSEEInstanceKey, lc)).NotTo(HaveOccurred())
+			optIn := operatorv1.LogCollectionOptIn
+			lc.Spec.NamespaceSelection = &optIn
+			Expect(c.Update(ctx, lc)).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cm := corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      render.FluentdFilterConfigMapName,
+					Namespace: render.LogCollectorNamespace,
+				},
+			}
+			Expect(test.GetResource(c, &cm)).To(BeNil())
+			Expect(cm.Data[render.FluentdFilterFlowName]).To(ContainSubstring("pattern /^(billing|shop)$/"))
+			Expect(cm.Data[render.FluentdFilterL7Name]).To(ContainSubstring("pattern /^(billing|shop)$/"))
+		})
 		It("should use images from imageset", func() {
 			Expect(c.Create(ctx, &operatorv1.ImageSet{
 				ObjectMeta: metav1.ObjectMeta{Name: "enterprise-" + components.EnterpriseRelease},
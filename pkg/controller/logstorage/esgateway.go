@@ -18,6 +18,7 @@ import (
 )
 
 func (r *ReconcileLogStorage) createEsGateway(
+	ls *operatorv1.LogStorage,
 	install *operatorv1.InstallationSpec,
 	variant operatorv1.ProductVariant,
 	pullSecrets []*corev1.Secret,
@@ -84,6 +85,9 @@ func (r *ReconcileLogStorage) createEsGateway(
 		ClusterDomain:              r.clusterDomain,
 		EsAdminUserName:            esAdminUserName,
 	}
+	if ls != nil {
+		cfg.ESGateway = ls.Spec.ESGateway
+	}
 
 	esGatewayComponent := esgateway.EsGateway(cfg)
 
@@ -61,10 +61,11 @@ func (r *ReconcileLogStorage) createLogStorage(
 	var kbOperatorManagedCertSecret bool
 	var err error
 	finalizerCleanup := false
+	storageClass := &storagev1.StorageClass{}
 
 	if managementClusterConnection == nil {
 		// Check if there is a StorageClass available to run Elasticsearch on.
-		if err = r.client.Get(ctx, client.ObjectKey{Name: ls.Spec.StorageClassName}, &storagev1.StorageClass{}); err != nil {
+		if err = r.client.Get(ctx, client.ObjectKey{Name: ls.Spec.StorageClassName}, storageClass); err != nil {
 			if errors.IsNotFound(err) {
 				err := fmt.Errorf("couldn't find storage class %s, this must be provided", ls.Spec.StorageClassName)
 				reqLogger.Error(err, err.Error())
@@ -95,6 +96,11 @@ func (r *ReconcileLogStorage) createLogStorage(
 		r.status.SetDegraded("An error occurred trying to retrieve Elasticsearch", err.Error())
 		return reconcile.Result{}, false, finalizerCleanup, err
 	}
+	if err = validateStorage(ls, elasticsearch); err != nil {
+		reqLogger.Error(err, err.Error())
+		r.status.SetDegraded("An error occurred while validating LogStorage", err.Error())
+		return reconcile.Result{}, false, finalizerCleanup, err
+	}
 
 	kibana, err := r.getKibana(ctx)
 	if err != nil {
@@ -148,6 +154,8 @@ func (r *ReconcileLogStorage) createLogStorage(
 		ClusterDomain:               r.clusterDomain,
 		DexCfg:                      dexCfg,
 		ElasticLicenseType:          esLicenseType,
+		VolumeExpansion:             storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion,
+		KubernetesVersion:           r.kubernetesVersion.Get(),
 	}
 
 	component := render.LogStorage(logStorageCfg)
@@ -199,6 +207,19 @@ func (r *ReconcileLogStorage) createLogStorage(
 	return reconcile.Result{}, true, finalizerCleanup, nil
 }
 
+// validateStorage checks that the storage of the Elasticsearch nodes isn't decreased, since their volumes can't be
+// shrunk. Replacing the NodeSets instead would migrate all the data to new volumes.
+func validateStorage(ls *operatorv1.LogStorage, elasticsearch *esv1.Elasticsearch) error {
+	if elasticsearch == nil || len(elasticsearch.Spec.NodeSets) == 0 || len(elasticsearch.Spec.NodeSets[0].VolumeClaimTemplates) == 0 {
+		return nil
+	}
+	current := elasticsearch.Spec.NodeSets[0].VolumeClaimTemplates[0].Spec.Resources.Requests.Storage()
+	if storage := render.ElasticsearchStorage(ls); storage.Cmp(*current) < 0 {
+		return fmt.Errorf("LogStorage spec.nodes.resourceRequirements can't decrease the storage of the Elasticsearch nodes from %s to %s", current, &storage)
+	}
+	return nil
+}
+
 func (r *ReconcileLogStorage) validateLogStorage(curatorSecrets []*corev1.Secret, esLicenseType render.ElasticsearchLicenseType, reqLogger logr.Logger, ctx context.Context) (reconcile.Result, bool, error) {
 	var err error
 
@@ -220,7 +241,7 @@ func (r *ReconcileLogStorage) validateLogStorage(curatorSecrets []*corev1.Secret
 	return reconcile.Result{}, true, nil
 }
 
-func (r *ReconcileLogStorage) applyILMPolicies(ls *operatorv1.LogStorage, reqLogger logr.Logger, ctx context.Context) (reconcile.Result, bool, error) {
+func (r *ReconcileLogStorage) applyILMPolicies(ls *operatorv1.LogStorage, clusterConfig *relasticsearch.ClusterConfig, reqLogger logr.Logger, ctx context.Context) (reconcile.Result, bool, error) {
 	// ES should be in ready phase when execution reaches here, apply ILM polices
 	esClient, err := r.esCliCreator(r.client, ctx, relasticsearch.HTTPSEndpoint(rmeta.OSTypeLinux, r.clusterDomain))
 	if err != nil {
@@ -234,6 +255,12 @@ func (r *ReconcileLogStorage) applyILMPolicies(ls *operatorv1.LogStorage, reqLog
 		r.status.SetDegraded("Failed to create or update Elasticsearch lifecycle policies", err.Error())
 		return reconcile.Result{}, false, err
 	}
+
+	if err = esClient.SetIndexTemplates(ctx, clusterConfig); err != nil {
+		reqLogger.Error(err, "failed to create or update Elasticsearch index templates")
+		r.status.SetDegraded("Failed to create or update Elasticsearch index templates", err.Error())
+		return reconcile.Result{}, false, err
+	}
 	return reconcile.Result{}, true, nil
 }
 
@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -71,18 +72,19 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return err
 	}
 
-	return add(mgr, r)
+	return add(mgr, r, opts.KubernetesVersion)
 }
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(cli client.Client, schema *runtime.Scheme, statusMgr status.StatusManager, opts options.AddOptions, esCliCreator utils.ElasticsearchClientCreator) (*ReconcileLogStorage, error) {
 	c := &ReconcileLogStorage{
-		client:        cli,
-		scheme:        schema,
-		status:        statusMgr,
-		provider:      opts.DetectedProvider,
-		esCliCreator:  esCliCreator,
-		clusterDomain: opts.ClusterDomain,
+		client:            cli,
+		scheme:            schema,
+		status:            statusMgr,
+		provider:          opts.DetectedProvider,
+		esCliCreator:      esCliCreator,
+		clusterDomain:     opts.ClusterDomain,
+		kubernetesVersion: opts.KubernetesVersion,
 	}
 
 	c.status.Run(opts.ShutdownContext)
@@ -90,33 +92,39 @@ func newReconciler(cli client.Client, schema *runtime.Scheme, statusMgr status.S
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
-	c, err := controller.New("log-storage-controller", mgr, controller.Options{Reconciler: r})
+func add(mgr manager.Manager, r reconcile.Reconciler, kubernetesVersion *common.KubernetesVersionTracker) error {
+	c, err := controller.New("log-storage-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("log-storage-controller", r)})
 	if err != nil {
 		return err
 	}
 
-	// Watch for changes to primary resource LogStorage
-	err = c.Watch(&source.Kind{Type: &operatorv1.LogStorage{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to primary resource LogStorage. Status only updates are ignored.
+	err = c.Watch(&source.Kind{Type: &operatorv1.LogStorage{}}, &handler.EnqueueRequestForObject{}, utils.PrimaryResourceChangedPredicate())
 	if err != nil {
 		return err
 	}
 
-	if err = utils.AddNetworkWatch(c); err != nil {
-		return fmt.Errorf("log-storage-controller failed to watch Network resource: %w", err)
+	if err = utils.AddCRDependencyWatches(c, utils.LogStorageCR); err != nil {
+		return fmt.Errorf("log-storage-controller failed to watch the CRs it depends on: %w", err)
 	}
 
 	if err = imageset.AddImageSetWatch(c); err != nil {
 		return fmt.Errorf("log-storage-controller failed to watch ImageSet: %w", err)
 	}
 
+	// Re-render the PodSecurityPolicies when the cluster is upgraded.
+	if err = utils.AddKubernetesVersionWatch(c, kubernetesVersion, utils.DefaultTSEEInstanceKey); err != nil {
+		return fmt.Errorf("log-storage-controller failed to watch the Kubernetes version: %w", err)
+	}
+
 	if err := addLogStorageWatches(c); err != nil {
 		return err
 	}
 
 	// Watch all the elasticsearch user secrets in the operator namespace. In the future, we may want put this logic in
 	// the utils folder where the other watch logic is.
-	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForObject{}, &predicate.Funcs{
+	// The namespace of the watched object selects the cache of the operator namespace.
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: common.OperatorNamespace()}}}, &handler.EnqueueRequestForObject{}, &predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
 			_, hasLabel := e.Object.GetLabels()[logstoragecommon.TigeraElasticsearchUserSecretLabel]
 			return e.Object.GetNamespace() == common.OperatorNamespace() && hasLabel
@@ -129,7 +137,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 			_, hasLabel := e.Object.GetLabels()[logstoragecommon.TigeraElasticsearchUserSecretLabel]
 			return e.Object.GetNamespace() == common.OperatorNamespace() && hasLabel
 		},
-	})
+	}, utils.ContentChangedPredicate{})
 	if err != nil {
 		return err
 	}
@@ -196,6 +204,8 @@ type ReconcileLogStorage struct {
 	provider      operatorv1.Provider
 	esCliCreator  utils.ElasticsearchClientCreator
 	clusterDomain string
+	// kubernetesVersion is the version of the cluster, which the PodSecurityPolicies depend on.
+	kubernetesVersion *common.KubernetesVersionTracker
 }
 
 // fillDefaults populates the default values onto an LogStorage object.
@@ -313,6 +323,13 @@ func (r *ReconcileLogStorage) Reconcile(ctx context.Context, request reconcile.R
 			r.status.SetDegraded("An error occurred while validating LogStorage", err.Error())
 			return reconcile.Result{}, err
 		}
+		if ls.Spec.ESGateway != nil {
+			if err = relasticsearch.ValidateTLSSettings(ls.Spec.ESGateway.TLS); err != nil {
+				err = fmt.Errorf("LogStorage spec.esGateway.tls: %w", err)
+				r.status.SetDegraded("An error occurred while validating LogStorage", err.Error())
+				return reconcile.Result{}, err
+			}
+		}
 
 		setLogStorageFinalizer(ls)
 
@@ -324,12 +341,14 @@ func (r *ReconcileLogStorage) Reconcile(ctx context.Context, request reconcile.R
 		}
 	}
 
+	if result, unmet, err := utils.CheckDependencies(ctx, r.client, reqLogger, r.status,
+		utils.CRDependenciesReady(utils.LogStorageCR),
+	); unmet {
+		return result, err
+	}
+
 	variant, install, err := utils.GetInstallation(context.Background(), r.client)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			r.status.SetDegraded("Installation not found", err.Error())
-			return reconcile.Result{}, err
-		}
 		r.status.SetDegraded("An error occurred while querying Installation", err.Error())
 		return reconcile.Result{}, err
 	}
@@ -437,6 +456,7 @@ func (r *ReconcileLogStorage) Reconcile(ctx context.Context, request reconcile.R
 	} else {
 		hdler = utils.NewComponentHandler(reqLogger, r.client, r.scheme, managementClusterConnection)
 	}
+	hdler.SetHardening(install.Hardening)
 
 	authentication, err := utils.GetAuthentication(ctx, r.client)
 	if err != nil && !errors.IsNotFound(err) {
@@ -493,6 +513,7 @@ func (r *ReconcileLogStorage) Reconcile(ctx context.Context, request reconcile.R
 		}
 
 		result, proceed, err = r.createEsGateway(
+			ls,
 			install,
 			variant,
 			pullSecrets,
@@ -505,7 +526,7 @@ func (r *ReconcileLogStorage) Reconcile(ctx context.Context, request reconcile.R
 			return result, err
 		}
 
-		result, proceed, err = r.applyILMPolicies(ls, reqLogger, ctx)
+		result, proceed, err = r.applyILMPolicies(ls, clusterConfig, reqLogger, ctx)
 		if err != nil || !proceed {
 			return result, err
 		}
@@ -228,6 +228,8 @@ var _ = Describe("LogStorage controller", func() {
 						mockStatus.On("AddDeployments", mock.Anything).Return()
 						mockStatus.On("AddStatefulSets", mock.Anything).Return()
 						mockStatus.On("AddCronJobs", mock.Anything)
+						mockStatus.On("AddJobs", mock.Anything)
+						mockStatus.On("IsRolloutPaused").Return(false)
 						mockStatus.On("OnCRNotFound").Return()
 						mockStatus.On("ClearDegraded")
 						mockStatus.On("ReadyToMonitor")
@@ -272,6 +274,8 @@ var _ = Describe("LogStorage controller", func() {
 						mockStatus.On("AddDeployments", mock.Anything).Return()
 						mockStatus.On("AddStatefulSets", mock.Anything).Return()
 						mockStatus.On("AddCronJobs", mock.Anything)
+						mockStatus.On("AddJobs", mock.Anything)
+						mockStatus.On("IsRolloutPaused").Return(false)
 						mockStatus.On("ClearDegraded", mock.Anything).Return()
 						mockStatus.On("ReadyToMonitor")
 
@@ -349,6 +353,8 @@ var _ = Describe("LogStorage controller", func() {
 					mockStatus.On("AddDeployments", mock.Anything)
 					mockStatus.On("AddStatefulSets", mock.Anything)
 					mockStatus.On("AddCronJobs", mock.Anything)
+					mockStatus.On("AddJobs", mock.Anything)
+					mockStatus.On("IsRolloutPaused").Return(false)
 					mockStatus.On("OnCRFound").Return()
 					mockStatus.On("ReadyToMonitor")
 				})
@@ -1386,6 +1392,8 @@ var _ = Describe("LogStorage controller", func() {
 					mockStatus.On("AddDeployments", mock.Anything)
 					mockStatus.On("AddStatefulSets", mock.Anything)
 					mockStatus.On("AddCronJobs", mock.Anything)
+					mockStatus.On("AddJobs", mock.Anything)
+					mockStatus.On("IsRolloutPaused").Return(false)
 					mockStatus.On("ClearDegraded", mock.Anything)
 					mockStatus.On("OnCRFound").Return()
 					mockStatus.On("ReadyToMonitor")
@@ -1492,6 +1500,38 @@ var _ = Describe("LogStorage controller", func() {
 			Expect(validateComponentResources(&ls.Spec)).To(BeNil())
 		})
 	})
+	Context("LogStorageSpec, validateStorage", func() {
+		elasticsearch := func(storage string) *esv1.Elasticsearch {
+			return &esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+					},
+				}}},
+			}}}}
+		}
+		logStorage := func(storage string) *operatorv1.LogStorage {
+			return &operatorv1.LogStorage{Spec: operatorv1.LogStorageSpec{Nodes: &operatorv1.Nodes{
+				ResourceRequirements: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+				},
+			}}}
+		}
+
+		It("should return nil when Elasticsearch doesn't exist", func() {
+			Expect(validateStorage(logStorage("5Gi"), nil)).To(BeNil())
+		})
+
+		It("should return nil when the storage is unchanged or increased", func() {
+			Expect(validateStorage(logStorage("10Gi"), elasticsearch("10Gi"))).To(BeNil())
+			Expect(validateStorage(logStorage("20Gi"), elasticsearch("10Gi"))).To(BeNil())
+		})
+
+		It("should return an error when the storage is decreased", func() {
+			Expect(validateStorage(logStorage("5Gi"), elasticsearch("10Gi"))).To(MatchError(
+				"LogStorage spec.nodes.resourceRequirements can't decrease the storage of the Elasticsearch nodes from 10Gi to 5Gi"))
+		})
+	})
 	Context("LogStorageSpec, fillDefaults", func() {
 		ls := operatorv1.LogStorage{Spec: operatorv1.LogStorageSpec{}}
 		fillDefaults(&ls)
@@ -1685,3 +1725,7 @@ func createKibanaSecrets() []client.Object {
 func (*mockESClient) SetILMPolicies(ctx context.Context, ls *operatorv1.LogStorage) error {
 	return nil
 }
+
+func (*mockESClient) SetIndexTemplates(ctx context.Context, clusterConfig *relasticsearch.ClusterConfig) error {
+	return nil
+}
@@ -0,0 +1,406 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/options"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const controllerName = "managedcluster-controller"
+
+const (
+	defaultTunnelCARotateBefore  = 30 * 24 * time.Hour
+	defaultTunnelCAOverlapPeriod = 7 * 24 * time.Hour
+)
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new ManagedCluster Controller and adds it to the Manager. The controller generates the connection
+// manifest of every ManagedCluster on a management cluster. This controller is meant only for enterprise users.
+func Add(mgr manager.Manager, opts options.AddOptions) error {
+	if !opts.EnterpriseCRDExists {
+		// No need to start this controller.
+		return nil
+	}
+
+	var managedClusterAPIReady = &utils.ReadyFlag{}
+
+	reconciler := newReconciler(mgr, opts, managedClusterAPIReady)
+
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: utils.FairReconciler(controllerName, reconciler)})
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", controllerName, err)
+	}
+
+	k8sClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		log.Error(err, "Failed to establish a connection to k8s")
+		return err
+	}
+
+	// The ManagedCluster API is served by the Calico API server, so it can only be watched once that is available.
+	go utils.WaitToAddResourceWatch(c, k8sClient, log, managedClusterAPIReady, &v3.ManagedCluster{TypeMeta: metav1.TypeMeta{Kind: v3.KindManagedCluster}})
+
+	return add(c)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, opts options.AddOptions, managedClusterAPIReady *utils.ReadyFlag) reconcile.Reconciler {
+	r := &ReconcileManagedCluster{
+		client:                 mgr.GetClient(),
+		scheme:                 mgr.GetScheme(),
+		status:                 status.New(mgr.GetClient(), "managed-cluster", opts.KubernetesVersion),
+		managedClusterAPIReady: managedClusterAPIReady,
+	}
+	r.status.Run(opts.ShutdownContext)
+	return r
+}
+
+// add adds watches for resources that are available at startup
+func add(c controller.Controller) error {
+	err := c.Watch(&source.Kind{Type: &operatorv1.ManagementCluster{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("%s failed to watch ManagementCluster resource: %w", controllerName, err)
+	}
+
+	err = c.Watch(&source.Kind{Type: &operatorv1.Tenant{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("%s failed to watch Tenant resource: %w", controllerName, err)
+	}
+
+	// The tunnel CA signs the certificates of the managed clusters. Each tenant has its own tunnel CA in its namespace.
+	isTunnelSecret := func(obj client.Object) bool {
+		return obj.GetName() == render.VoltronTunnelSecretName
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForObject{}, &predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isTunnelSecret(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isTunnelSecret(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isTunnelSecret(e.Object)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%s failed to watch Secret resource %s: %w", controllerName, render.VoltronTunnelSecretName, err)
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileManagedCluster implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileManagedCluster{}
+
+// ReconcileManagedCluster reconciles the ManagedCluster objects of a management cluster.
+type ReconcileManagedCluster struct {
+	client                 client.Client
+	scheme                 *runtime.Scheme
+	status                 status.StatusManager
+	managedClusterAPIReady *utils.ReadyFlag
+}
+
+// Reconcile generates the connection manifest of every ManagedCluster. The manifest is stored in a secret in the operator
+// namespace, or in the namespace of the tenant of the ManagedCluster, that is owned by the ManagedCluster, so that it
+// is garbage collected along with it. Since all ManagedClusters are reconciled at once, the request itself is ignored.
+func (r *ReconcileManagedCluster) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling managed clusters")
+
+	managementCluster, err := utils.GetManagementCluster(ctx, r.client)
+	if err != nil {
+		reqLogger.Error(err, "Error reading ManagementCluster")
+		r.status.SetDegraded("Error reading ManagementCluster", err.Error())
+		return reconcile.Result{}, err
+	} else if managementCluster == nil {
+		r.status.OnCRNotFound()
+		return reconcile.Result{}, nil
+	}
+	r.status.OnCRFound()
+
+	if !r.managedClusterAPIReady.IsReady() {
+		r.status.SetDegraded("Waiting for the ManagedCluster API to be ready", "")
+		return reconcile.Result{}, nil
+	}
+
+	if managementCluster.Spec.Address == "" {
+		r.status.SetDegraded("ManagementCluster address is not set, can't generate managed cluster manifests", "")
+		return reconcile.Result{}, nil
+	}
+
+	// The tunnel CA is created by the API server controller.
+	tunnelCASecret, err := utils.ValidateCertPair(r.client,
+		common.OperatorNamespace(),
+		render.VoltronTunnelSecretName,
+		render.VoltronTunnelSecretKeyName,
+		render.VoltronTunnelSecretCertName,
+	)
+	if err != nil {
+		reqLogger.Error(err, "Invalid tunnel CA")
+		r.status.SetDegraded("Error validating the tunnel CA", err.Error())
+		return reconcile.Result{}, err
+	} else if tunnelCASecret == nil {
+		r.status.SetDegraded(fmt.Sprintf("Waiting for secret '%s' to become available", render.VoltronTunnelSecretName), "")
+		return reconcile.Result{}, nil
+	}
+
+	requeueAfter, err := r.reconcileTunnelCARotation(ctx, managementCluster, tunnelCASecret)
+	if err != nil {
+		reqLogger.Error(err, "Error rotating the tunnel CA")
+		r.status.SetDegraded("Error rotating the tunnel CA", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	managedClusters := &v3.ManagedClusterList{}
+	if err := r.client.List(ctx, managedClusters); err != nil {
+		r.status.SetDegraded("Error listing ManagedClusters", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	for i := range managedClusters.Items {
+		managedCluster := &managedClusters.Items[i]
+		namespace, addr, clusterTunnelCASecret := common.OperatorNamespace(), managementCluster.Spec.Address, tunnelCASecret
+
+		// The managed clusters of a tenant connect to the Voltron instance of the tenant, which only trusts the tunnel CA
+		// of the tenant. Their manifests are kept in the namespace of the tenant.
+		if tenantNamespace := managedCluster.Labels[render.TenantNamespaceLabel]; tenantNamespace != "" {
+			tenant := &operatorv1.Tenant{}
+			if err := r.client.Get(ctx, client.ObjectKey{Name: render.TenantResourceName, Namespace: tenantNamespace}, tenant); err != nil {
+				if errors.IsNotFound(err) {
+					r.status.SetDegraded(fmt.Sprintf("Waiting for the Tenant of ManagedCluster %s in namespace %s", managedCluster.Name, tenantNamespace), "")
+					return reconcile.Result{}, nil
+				}
+				r.status.SetDegraded("Error reading Tenant", err.Error())
+				return reconcile.Result{}, err
+			}
+
+			// The tunnel CA of the tenant is created by the tenant controller.
+			clusterTunnelCASecret, err = utils.ValidateCertPair(r.client,
+				tenantNamespace,
+				render.VoltronTunnelSecretName,
+				render.VoltronTunnelSecretKeyName,
+				render.VoltronTunnelSecretCertName,
+			)
+			if err != nil {
+				reqLogger.Error(err, "Invalid tunnel CA", "Tenant", tenantNamespace)
+				r.status.SetDegraded(fmt.Sprintf("Error validating the tunnel CA of Tenant %s", tenantNamespace), err.Error())
+				return reconcile.Result{}, err
+			} else if clusterTunnelCASecret == nil {
+				r.status.SetDegraded(fmt.Sprintf("Waiting for secret '%s' in namespace '%s' to become available", render.VoltronTunnelSecretName, tenantNamespace), "")
+				return reconcile.Result{}, nil
+			}
+
+			namespace = tenantNamespace
+			if tenant.Spec.Address != "" {
+				addr = tenant.Spec.Address
+			}
+		}
+
+		if err := r.reconcileManifest(ctx, managedCluster, namespace, addr, clusterTunnelCASecret); err != nil {
+			reqLogger.Error(err, "Error generating the manifest", "ManagedCluster", managedCluster.Name)
+			r.status.SetDegraded(fmt.Sprintf("Error generating the manifest of ManagedCluster %s", managedCluster.Name), err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+
+	r.status.ClearDegraded()
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// reconcileTunnelCARotation starts a rotation of the tunnel CA when it is about to expire and completes it once the
+// overlap period has passed. The progress is recorded in the status of the ManagementCluster. It returns when the next
+// step of the rotation is due.
+func (r *ReconcileManagedCluster) reconcileTunnelCARotation(ctx context.Context, managementCluster *operatorv1.ManagementCluster, tunnelCASecret *corev1.Secret) (time.Duration, error) {
+	rotateBefore, overlapPeriod := defaultTunnelCARotateBefore, defaultTunnelCAOverlapPeriod
+	if rotation := managementCluster.Spec.TunnelCertificateRotation; rotation != nil {
+		if rotation.RotateBefore != nil {
+			rotateBefore = rotation.RotateBefore.Duration
+		}
+		if rotation.OverlapPeriod != nil {
+			overlapPeriod = rotation.OverlapPeriod.Duration
+		}
+	}
+	now := time.Now()
+
+	if _, rotating := tunnelCASecret.Data[render.VoltronTunnelSecretNextKeyName]; rotating {
+		started, err := time.Parse(time.RFC3339, tunnelCASecret.Annotations[render.VoltronTunnelRotationStartedAnnotation])
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse the start time of the tunnel CA rotation: %w", err)
+		}
+		end := started.Add(overlapPeriod)
+		if now.Before(end) {
+			return end.Sub(now), r.setTunnelCARotationCondition(ctx, managementCluster, metav1.ConditionTrue, operatorv1.TunnelCertificateRotationInProgress, rotationInProgressMessage(started, end))
+		}
+
+		// The overlap period has passed, only the new CA remains trusted.
+		cert, key := render.TunnelSigningCA(tunnelCASecret)
+		tunnelCASecret.Data[render.VoltronTunnelSecretCertName] = cert
+		tunnelCASecret.Data[render.VoltronTunnelSecretKeyName] = key
+		delete(tunnelCASecret.Data, render.VoltronTunnelSecretNextKeyName)
+		delete(tunnelCASecret.Annotations, render.VoltronTunnelRotationStartedAnnotation)
+		if err := r.client.Update(ctx, tunnelCASecret); err != nil {
+			return 0, err
+		}
+		log.Info("Completed the rotation of the tunnel CA")
+	}
+
+	expiry, err := render.CertificateExpiry(tunnelCASecret.Data[render.VoltronTunnelSecretCertName])
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the tunnel CA certificate: %w", err)
+	}
+	start := expiry.Add(-rotateBefore)
+	if now.Before(start) {
+		return start.Sub(now), r.setTunnelCARotationCondition(ctx, managementCluster, metav1.ConditionFalse, operatorv1.TunnelCertificateRotationScheduled,
+			fmt.Sprintf("The next rotation of the tunnel CA starts at %s", start.Format(time.RFC3339)))
+	}
+
+	// Trust both the previous and the new CA until the overlap period has passed. The previous CA comes first so that
+	// its key still matches the cert field.
+	cert, key := render.CreateVoltronTunnelCA()
+	tunnelCASecret.Data[render.VoltronTunnelSecretCertName] = append(tunnelCASecret.Data[render.VoltronTunnelSecretCertName], cert...)
+	tunnelCASecret.Data[render.VoltronTunnelSecretNextKeyName] = key
+	if tunnelCASecret.Annotations == nil {
+		tunnelCASecret.Annotations = map[string]string{}
+	}
+	tunnelCASecret.Annotations[render.VoltronTunnelRotationStartedAnnotation] = now.Format(time.RFC3339)
+	if err := r.client.Update(ctx, tunnelCASecret); err != nil {
+		return 0, err
+	}
+	log.Info("Started a rotation of the tunnel CA", "expiry", expiry)
+
+	return overlapPeriod, r.setTunnelCARotationCondition(ctx, managementCluster, metav1.ConditionTrue, operatorv1.TunnelCertificateRotationInProgress, rotationInProgressMessage(now, now.Add(overlapPeriod)))
+}
+
+func rotationInProgressMessage(started, end time.Time) string {
+	return fmt.Sprintf("Rotation of the tunnel CA started at %s, managed clusters must apply their regenerated manifest before %s", started.Format(time.RFC3339), end.Format(time.RFC3339))
+}
+
+// setTunnelCARotationCondition updates the tunnel certificate rotation condition of the ManagementCluster, if it changed.
+func (r *ReconcileManagedCluster) setTunnelCARotationCondition(ctx context.Context, managementCluster *operatorv1.ManagementCluster, status metav1.ConditionStatus, reason, message string) error {
+	conditions := managementCluster.Status.DeepCopy().Conditions
+	meta.SetStatusCondition(&managementCluster.Status.Conditions, metav1.Condition{
+		Type:               operatorv1.TunnelCertificateRotationCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: managementCluster.Generation,
+	})
+	if reflect.DeepEqual(conditions, managementCluster.Status.Conditions) {
+		return nil
+	}
+	return r.client.Status().Update(ctx, managementCluster)
+}
+
+// reconcileManifest renders the manifest secret of the given managed cluster in the given namespace and records the
+// fingerprint of its certificate on the ManagedCluster, so that Voltron accepts the tunnel of the managed cluster. While
+// the tunnel CA is being rotated, the fingerprint of the previous certificate stays active and the one of the new
+// certificate is recorded as the next one, which becomes active once the rotation completes.
+func (r *ReconcileManagedCluster) reconcileManifest(ctx context.Context, managedCluster *v3.ManagedCluster, namespace, addr string, tunnelCASecret *corev1.Secret) error {
+	// During a rotation this holds both the previous and the new CA.
+	caCert := tunnelCASecret.Data[render.VoltronTunnelSecretCertName]
+
+	cert, key, err := r.managedClusterCertificate(ctx, managedCluster.Name, namespace, tunnelCASecret)
+	if err != nil {
+		return err
+	}
+
+	component, err := render.ManagedClusterManifest(&render.ManagedClusterManifestConfiguration{
+		ClusterName:           managedCluster.Name,
+		Namespace:             namespace,
+		ManagementClusterAddr: addr,
+		TunnelCACert:          caCert,
+		Certificate:           cert,
+		Key:                   key,
+	})
+	if err != nil {
+		return err
+	}
+
+	hdler := utils.NewComponentHandler(log, r.client, r.scheme, managedCluster)
+	if err := hdler.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
+		return err
+	}
+
+	fingerprint, err := render.ManagedClusterCertificateFingerprint(cert)
+	if err != nil {
+		return err
+	}
+	desired := map[string]string{render.ManagedClusterFingerprintAnnotation: fingerprint}
+	// The managed cluster keeps presenting its previous certificate until it applies its regenerated manifest.
+	_, rotating := tunnelCASecret.Data[render.VoltronTunnelSecretNextKeyName]
+	if active := managedCluster.Annotations[render.ManagedClusterFingerprintAnnotation]; rotating && active != "" && active != fingerprint {
+		desired = map[string]string{render.ManagedClusterFingerprintAnnotation: active, render.ManagedClusterNextFingerprintAnnotation: fingerprint}
+	}
+	if managedCluster.Annotations[render.ManagedClusterFingerprintAnnotation] == desired[render.ManagedClusterFingerprintAnnotation] &&
+		managedCluster.Annotations[render.ManagedClusterNextFingerprintAnnotation] == desired[render.ManagedClusterNextFingerprintAnnotation] {
+		return nil
+	}
+	if managedCluster.Annotations == nil {
+		managedCluster.Annotations = map[string]string{}
+	}
+	delete(managedCluster.Annotations, render.ManagedClusterNextFingerprintAnnotation)
+	for k, v := range desired {
+		managedCluster.Annotations[k] = v
+	}
+	return r.client.Update(ctx, managedCluster)
+}
+
+// managedClusterCertificate returns the certificate and key of the managed cluster stored in its manifest secret, as long
+// as they are signed by the signing tunnel CA. Otherwise, a new certificate and key are created.
+func (r *ReconcileManagedCluster) managedClusterCertificate(ctx context.Context, clusterName, namespace string, tunnelCASecret *corev1.Secret) ([]byte, []byte, error) {
+	caCert, caKey := render.TunnelSigningCA(tunnelCASecret)
+
+	manifestSecret, err := utils.GetSecret(ctx, r.client, render.ManagedClusterManifestSecretName(clusterName), namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if manifestSecret != nil {
+		cert := manifestSecret.Data[render.GuardianSecretManagedClusterCertName]
+		key := manifestSecret.Data[render.GuardianSecretManagedClusterKeyName]
+		if len(key) != 0 && render.IsSignedBy(cert, caCert) {
+			return cert, key, nil
+		}
+	}
+
+	return render.CreateManagedClusterCertificate(caCert, caKey, clusterName)
+}
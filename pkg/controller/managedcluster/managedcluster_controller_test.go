@@ -0,0 +1,306 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package managedcluster
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("ManagedCluster controller tests", func() {
+	var cli client.Client
+	var ctx context.Context
+	var mockStatus *status.MockStatus
+	var r ReconcileManagedCluster
+	var scheme *runtime.Scheme
+	var tunnelCASecret *corev1.Secret
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+		ctx = context.Background()
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		mockStatus = &status.MockStatus{}
+		mockStatus.On("OnCRFound").Return()
+		mockStatus.On("ClearDegraded").Return()
+
+		apiReady := &utils.ReadyFlag{}
+		apiReady.MarkAsReady()
+		r = ReconcileManagedCluster{
+			client:                 cli,
+			scheme:                 scheme,
+			status:                 mockStatus,
+			managedClusterAPIReady: apiReady,
+		}
+
+		Expect(cli.Create(ctx, &operatorv1.ManagementCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec:       operatorv1.ManagementClusterSpec{Address: "example.com:30449"},
+		})).NotTo(HaveOccurred())
+
+		// Any self-signed CA will do as tunnel CA.
+		ca := render.CreateDexTLSSecret("tigera-voltron")
+		tunnelCASecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: render.VoltronTunnelSecretName, Namespace: common.OperatorNamespace()},
+			Data: map[string][]byte{
+				render.VoltronTunnelSecretCertName: ca.Data[corev1.TLSCertKey],
+				render.VoltronTunnelSecretKeyName:  ca.Data[corev1.TLSPrivateKeyKey],
+			},
+		}
+		Expect(cli.Create(ctx, tunnelCASecret)).NotTo(HaveOccurred())
+
+		Expect(cli.Create(ctx, &v3.ManagedCluster{
+			TypeMeta:   metav1.TypeMeta{Kind: v3.KindManagedCluster, APIVersion: v3.GroupVersionCurrent},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-a"},
+		})).NotTo(HaveOccurred())
+	})
+
+	getManifestSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.ManagedClusterManifestSecretName("cluster-a"), Namespace: common.OperatorNamespace()}, secret)).NotTo(HaveOccurred())
+		return secret
+	}
+
+	It("should generate the manifest of a managed cluster", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertExpectations(GinkgoT())
+
+		secret := getManifestSecret()
+		Expect(secret.Data).To(HaveKey(render.ManagedClusterManifestKey))
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		Expect(secret.OwnerReferences[0].Kind).To(Equal(v3.KindManagedCluster))
+		Expect(secret.OwnerReferences[0].Name).To(Equal("cluster-a"))
+
+		cert := secret.Data[render.GuardianSecretManagedClusterCertName]
+		Expect(render.IsSignedBy(cert, tunnelCASecret.Data[render.VoltronTunnelSecretCertName])).To(BeTrue())
+
+		fingerprint, err := render.ManagedClusterCertificateFingerprint(cert)
+		Expect(err).NotTo(HaveOccurred())
+		managedCluster := &v3.ManagedCluster{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "cluster-a"}, managedCluster)).NotTo(HaveOccurred())
+		Expect(managedCluster.Annotations).To(HaveKeyWithValue(render.ManagedClusterFingerprintAnnotation, fingerprint))
+	})
+
+	It("should keep the certificate of a managed cluster across reconciles", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		cert := getManifestSecret().Data[render.GuardianSecretManagedClusterCertName]
+
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getManifestSecret().Data[render.GuardianSecretManagedClusterCertName]).To(Equal(cert))
+	})
+
+	It("should issue a new certificate when the tunnel CA changes", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		cert := getManifestSecret().Data[render.GuardianSecretManagedClusterCertName]
+
+		ca := render.CreateDexTLSSecret("tigera-voltron")
+		tunnelCASecret.Data = map[string][]byte{
+			render.VoltronTunnelSecretCertName: ca.Data[corev1.TLSCertKey],
+			render.VoltronTunnelSecretKeyName:  ca.Data[corev1.TLSPrivateKeyKey],
+		}
+		Expect(cli.Update(ctx, tunnelCASecret)).NotTo(HaveOccurred())
+
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		newCert := getManifestSecret().Data[render.GuardianSecretManagedClusterCertName]
+		Expect(newCert).NotTo(Equal(cert))
+		Expect(render.IsSignedBy(newCert, ca.Data[corev1.TLSCertKey])).To(BeTrue())
+	})
+
+	getTunnelCASecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.VoltronTunnelSecretName, Namespace: common.OperatorNamespace()}, secret)).NotTo(HaveOccurred())
+		return secret
+	}
+
+	getRotationCondition := func() *metav1.Condition {
+		managementCluster := &operatorv1.ManagementCluster{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, managementCluster)).NotTo(HaveOccurred())
+		return meta.FindStatusCondition(managementCluster.Status.Conditions, operatorv1.TunnelCertificateRotationCondition)
+	}
+
+	setRotateBefore := func(d time.Duration) {
+		managementCluster := &operatorv1.ManagementCluster{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, managementCluster)).NotTo(HaveOccurred())
+		managementCluster.Spec.TunnelCertificateRotation = &operatorv1.TunnelCertificateRotation{RotateBefore: &metav1.Duration{Duration: d}}
+		Expect(cli.Update(ctx, managementCluster)).NotTo(HaveOccurred())
+	}
+
+	It("should schedule the rotation of the tunnel CA", func() {
+		result, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+		condition := getRotationCondition()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(operatorv1.TunnelCertificateRotationScheduled))
+		Expect(getTunnelCASecret().Data).NotTo(HaveKey(render.VoltronTunnelSecretNextKeyName))
+	})
+
+	It("should start a rotation when the tunnel CA is about to expire", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		cert := getManifestSecret().Data[render.GuardianSecretManagedClusterCertName]
+		fingerprint, err := render.ManagedClusterCertificateFingerprint(cert)
+		Expect(err).NotTo(HaveOccurred())
+
+		// The tunnel CA is valid for less than this.
+		setRotateBefore(50000 * time.Hour)
+		result, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(7 * 24 * time.Hour))
+
+		secret := getTunnelCASecret()
+		Expect(secret.Data).To(HaveKey(render.VoltronTunnelSecretNextKeyName))
+		Expect(secret.Annotations).To(HaveKey(render.VoltronTunnelRotationStartedAnnotation))
+		Expect(secret.Data[render.VoltronTunnelSecretKeyName]).To(Equal(tunnelCASecret.Data[render.VoltronTunnelSecretKeyName]))
+		Expect(strings.Count(string(secret.Data[render.VoltronTunnelSecretCertName]), "BEGIN CERTIFICATE")).To(Equal(2))
+
+		newCA, _ := render.TunnelSigningCA(secret)
+		newCert := getManifestSecret().Data[render.GuardianSecretManagedClusterCertName]
+		Expect(render.IsSignedBy(newCert, newCA)).To(BeTrue())
+
+		managedCluster := &v3.ManagedCluster{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "cluster-a"}, managedCluster)).NotTo(HaveOccurred())
+		newFingerprint, err := render.ManagedClusterCertificateFingerprint(newCert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(managedCluster.Annotations).To(HaveKeyWithValue(render.ManagedClusterFingerprintAnnotation, fingerprint))
+		Expect(managedCluster.Annotations).To(HaveKeyWithValue(render.ManagedClusterNextFingerprintAnnotation, newFingerprint))
+
+		condition := getRotationCondition()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(operatorv1.TunnelCertificateRotationInProgress))
+	})
+
+	It("should complete a rotation once the overlap period has passed", func() {
+		setRotateBefore(50000 * time.Hour)
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		secret := getTunnelCASecret()
+		newCA, newKey := render.TunnelSigningCA(secret)
+
+		setRotateBefore(720 * time.Hour)
+		secret.Annotations[render.VoltronTunnelRotationStartedAnnotation] = time.Now().Add(-8 * 24 * time.Hour).Format(time.RFC3339)
+		Expect(cli.Update(ctx, secret)).NotTo(HaveOccurred())
+		cert := getManifestSecret().Data[render.GuardianSecretManagedClusterCertName]
+
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		secret = getTunnelCASecret()
+		Expect(secret.Data[render.VoltronTunnelSecretCertName]).To(Equal(newCA))
+		Expect(secret.Data[render.VoltronTunnelSecretKeyName]).To(Equal(newKey))
+		Expect(secret.Data).NotTo(HaveKey(render.VoltronTunnelSecretNextKeyName))
+		Expect(secret.Annotations).NotTo(HaveKey(render.VoltronTunnelRotationStartedAnnotation))
+
+		// The certificate already signed by the new CA is kept.
+		Expect(getManifestSecret().Data[render.GuardianSecretManagedClusterCertName]).To(Equal(cert))
+		managedCluster := &v3.ManagedCluster{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "cluster-a"}, managedCluster)).NotTo(HaveOccurred())
+		fingerprint, err := render.ManagedClusterCertificateFingerprint(cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(managedCluster.Annotations).To(HaveKeyWithValue(render.ManagedClusterFingerprintAnnotation, fingerprint))
+		Expect(managedCluster.Annotations).NotTo(HaveKey(render.ManagedClusterNextFingerprintAnnotation))
+
+		condition := getRotationCondition()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(operatorv1.TunnelCertificateRotationScheduled))
+	})
+
+	It("should sign the certificate of a managed cluster of a tenant with the tunnel CA of the tenant", func() {
+		Expect(cli.Create(ctx, &operatorv1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: render.TenantResourceName, Namespace: "tenant-a"},
+			Spec:       operatorv1.TenantSpec{ID: "a", Address: "tenant-a.example.com:30449"},
+		})).NotTo(HaveOccurred())
+		ca := render.CreateDexTLSSecret("tigera-voltron")
+		Expect(cli.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: render.VoltronTunnelSecretName, Namespace: "tenant-a"},
+			Data: map[string][]byte{
+				render.VoltronTunnelSecretCertName: ca.Data[corev1.TLSCertKey],
+				render.VoltronTunnelSecretKeyName:  ca.Data[corev1.TLSPrivateKeyKey],
+			},
+		})).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &v3.ManagedCluster{
+			TypeMeta: metav1.TypeMeta{Kind: v3.KindManagedCluster, APIVersion: v3.GroupVersionCurrent},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster-b",
+				Labels: map[string]string{render.TenantNamespaceLabel: "tenant-a"},
+			},
+		})).NotTo(HaveOccurred())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertExpectations(GinkgoT())
+
+		secret := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.ManagedClusterManifestSecretName("cluster-b"), Namespace: "tenant-a"}, secret)).NotTo(HaveOccurred())
+		cert := secret.Data[render.GuardianSecretManagedClusterCertName]
+		Expect(render.IsSignedBy(cert, ca.Data[corev1.TLSCertKey])).To(BeTrue())
+		Expect(render.IsSignedBy(cert, tunnelCASecret.Data[render.VoltronTunnelSecretCertName])).To(BeFalse())
+		Expect(string(secret.Data[render.ManagedClusterManifestKey])).To(ContainSubstring("tenant-a.example.com:30449"))
+	})
+
+	It("should degrade when the tunnel CA is not available", func() {
+		Expect(cli.Delete(ctx, tunnelCASecret)).NotTo(HaveOccurred())
+		mockStatus.On("SetDegraded", "Waiting for secret 'tigera-management-cluster-connection' to become available", "").Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Waiting for secret 'tigera-management-cluster-connection' to become available", "")
+		mockStatus.AssertNotCalled(GinkgoT(), "ClearDegraded")
+	})
+
+	It("should not generate manifests when the cluster is not a management cluster", func() {
+		Expect(cli.Delete(ctx, &operatorv1.ManagementCluster{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}})).NotTo(HaveOccurred())
+		mockStatus.On("OnCRNotFound").Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		secret := &corev1.Secret{}
+		err = cli.Get(ctx, client.ObjectKey{Name: render.ManagedClusterManifestSecretName("cluster-a"), Namespace: common.OperatorNamespace()}, secret)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -19,9 +19,9 @@ import (
 	"fmt"
 	"time"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
-	"github.com/tigera/operator/pkg/controller/compliance"
 	"github.com/tigera/operator/pkg/controller/options"
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/controller/utils"
@@ -32,6 +32,7 @@ import (
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -61,7 +62,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	reconciler := newReconciler(mgr, opts, licenseAPIReady)
 
 	// Create a new controller
-	controller, err := controller.New("cmanager-controller", mgr, controller.Options{Reconciler: reconcile.Reconciler(reconciler)})
+	controller, err := controller.New("cmanager-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("cmanager-controller", reconciler)})
 	if err != nil {
 		return fmt.Errorf("failed to create manager-controller: %w", err)
 	}
@@ -72,7 +73,12 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return err
 	}
 
-	go utils.WaitToAddLicenseKeyWatch(controller, k8sClient, log, licenseAPIReady)
+	go utils.WaitToAddLicenseKeyWatch(controller, k8sClient, log, licenseAPIReady, opts.LicenseKeyCache)
+
+	// Re-render the PodSecurityPolicies when the cluster is upgraded.
+	if err = utils.AddKubernetesVersionWatch(controller, opts.KubernetesVersion, utils.DefaultTSEEInstanceKey); err != nil {
+		return fmt.Errorf("manager-controller failed to watch the Kubernetes version: %w", err)
+	}
 
 	return add(mgr, controller)
 }
@@ -80,12 +86,14 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady *utils.ReadyFlag) reconcile.Reconciler {
 	c := &ReconcileManager{
-		client:          mgr.GetClient(),
-		scheme:          mgr.GetScheme(),
-		provider:        opts.DetectedProvider,
-		status:          status.New(mgr.GetClient(), "manager", opts.KubernetesVersion),
-		clusterDomain:   opts.ClusterDomain,
-		licenseAPIReady: licenseAPIReady,
+		client:            mgr.GetClient(),
+		scheme:            mgr.GetScheme(),
+		provider:          opts.DetectedProvider,
+		status:            status.New(mgr.GetClient(), "manager", opts.KubernetesVersion),
+		clusterDomain:     opts.ClusterDomain,
+		licenseAPIReady:   licenseAPIReady,
+		licenseKeyCache:   opts.LicenseKeyCache,
+		kubernetesVersion: opts.KubernetesVersion,
 	}
 	c.status.Run(opts.ShutdownContext)
 	return c
@@ -96,15 +104,15 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions, licenseAPIReady
 func add(mgr manager.Manager, c controller.Controller) error {
 	var err error
 
-	// Watch for changes to primary resource Manager
-	err = c.Watch(&source.Kind{Type: &operatorv1.Manager{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to primary resource Manager. Status only updates are ignored.
+	err = c.Watch(&source.Kind{Type: &operatorv1.Manager{}}, &handler.EnqueueRequestForObject{}, utils.PrimaryResourceChangedPredicate())
 	if err != nil {
 		return fmt.Errorf("manager-controller failed to watch primary resource: %w", err)
 	}
 
-	err = utils.AddAPIServerWatch(c)
+	err = utils.AddCRDependencyWatches(c, utils.ManagerCR)
 	if err != nil {
-		return fmt.Errorf("manager-controller failed to watch APIServer resource: %w", err)
+		return fmt.Errorf("manager-controller failed to watch the CRs it depends on: %w", err)
 	}
 
 	err = utils.AddComplianceWatch(c)
@@ -183,6 +191,9 @@ type ReconcileManager struct {
 	status          status.StatusManager
 	clusterDomain   string
 	licenseAPIReady *utils.ReadyFlag
+	licenseKeyCache *utils.LicenseKeyCache
+	// kubernetesVersion is the version of the cluster, which the PodSecurityPolicies depend on.
+	kubernetesVersion *common.KubernetesVersionTracker
 }
 
 // GetManager returns the default manager instance with defaults populated.
@@ -222,24 +233,18 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 	r.status.OnCRFound()
 
-	if !utils.IsAPIServerReady(r.client, reqLogger) {
-		r.status.SetDegraded("Waiting for Tigera API server to be ready", "")
+	if err = relasticsearch.ValidateTLSSettings(instance.Spec.TLS); err != nil {
+		r.status.SetDegraded("Error validating Manager", fmt.Sprintf("spec.tls: %s", err))
 		return reconcile.Result{}, nil
 	}
 
-	if !r.licenseAPIReady.IsReady() {
-		r.status.SetDegraded("Waiting for LicenseKeyAPI to be ready", "")
-		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
-	}
-
-	license, err := utils.FetchLicenseKey(ctx, r.client)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			r.status.SetDegraded("License not found", err.Error())
-			return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
-		}
-		r.status.SetDegraded("Error querying license", err.Error())
-		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	var license v3.LicenseKey
+	if result, unmet, err := utils.CheckDependencies(ctx, r.client, reqLogger, r.status,
+		utils.CRDependenciesReady(utils.ManagerCR),
+		utils.LicenseAPIReady(r.licenseAPIReady),
+		utils.LicenseKeyInstalled(r.licenseKeyCache, &license),
+	); unmet {
+		return result, err
 	}
 
 	// Fetch the Installation instance. We need this for a few reasons.
@@ -311,18 +316,8 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 
 	if installCompliance {
 		// Check that compliance is running.
-		compliance, err := compliance.GetCompliance(ctx, r.client)
-		if err != nil {
-			if errors.IsNotFound(err) {
-				r.status.SetDegraded("Compliance not found", err.Error())
-				return reconcile.Result{}, err
-			}
-			r.status.SetDegraded("Error querying compliance", err.Error())
-			return reconcile.Result{}, err
-		}
-		if compliance.Status.State != operatorv1.TigeraStatusReady {
-			r.status.SetDegraded("Compliance is not ready", fmt.Sprintf("compliance status: %s", compliance.Status.State))
-			return reconcile.Result{}, nil
+		if result, unmet, err := utils.CheckDependencies(ctx, r.client, reqLogger, r.status, utils.ComplianceReady); unmet {
+			return result, err
 		}
 
 		complianceServerCertSecret, err = utils.ValidateCertPair(r.client,
@@ -343,14 +338,8 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 	}
 
 	// check that prometheus is running
-	ns := &corev1.Namespace{}
-	if err = r.client.Get(ctx, client.ObjectKey{Name: common.TigeraPrometheusNamespace}, ns); err != nil {
-		if errors.IsNotFound(err) {
-			r.status.SetDegraded("tigera-prometheus namespace does not exist", "Dependency on tigera-prometheus not satisfied")
-		} else {
-			r.status.SetDegraded("Error querying prometheus", err.Error())
-		}
-		return reconcile.Result{}, err
+	if result, unmet, err := utils.CheckDependencies(ctx, r.client, reqLogger, r.status, utils.NamespaceExists(common.TigeraPrometheusNamespace, "prometheus")); unmet {
+		return result, err
 	}
 
 	pullSecrets, err := utils.GetNetworkingPullSecrets(installation, r.client)
@@ -502,15 +491,30 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 
 	// Create a component handler to manage the rendered component.
 	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetHardening(installation.Hardening)
 
 	// Set replicas to 1 for management or managed clusters.
 	// TODO Remove after MCM tigera-manager HA deployment is supported.
 	var replicas *int32 = installation.ControlPlaneReplicas
+	if instance.Spec.Replicas != nil {
+		replicas = instance.Spec.Replicas
+	}
 	if managementCluster != nil || managementClusterConnection != nil {
 		var mcmReplicas int32 = 1
 		replicas = &mcmReplicas
 	}
 
+	// Find the RBAC rendered for the managed cluster access rules, so that the RBAC of removed rules can be deleted.
+	accessBindings := &rbacv1.ClusterRoleBindingList{}
+	if err := r.client.List(ctx, accessBindings, client.HasLabels{render.ManagedClusterAccessLabel}); err != nil {
+		r.status.SetDegraded("Error listing the managed cluster access RBAC", err.Error())
+		return reconcile.Result{}, err
+	}
+	var accessRBAC []string
+	for _, binding := range accessBindings.Items {
+		accessRBAC = append(accessRBAC, binding.Name)
+	}
+
 	managerCfg := &render.ManagerConfiguration{
 		KeyValidatorConfig:            keyValidatorConfig,
 		ESSecrets:                     esSecrets,
@@ -521,7 +525,6 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 		ESClusterConfig:               esClusterConfig,
 		TLSKeyPair:                    tlsSecret,
 		PullSecrets:                   pullSecrets,
-		Openshift:                     r.provider == operatorv1.ProviderOpenShift,
 		Installation:                  installation,
 		ManagementCluster:             managementCluster,
 		TunnelSecret:                  tunnelSecret,
@@ -529,6 +532,10 @@ func (r *ReconcileManager) Reconcile(ctx context.Context, request reconcile.Requ
 		ClusterDomain:                 r.clusterDomain,
 		ESLicenseType:                 elasticLicenseType,
 		Replicas:                      replicas,
+		ESProxy:                       instance.Spec.ESProxy,
+		TLS:                           instance.Spec.TLS,
+		ManagedClusterAccessRBAC:      accessRBAC,
+		KubernetesVersion:             r.kubernetesVersion.Get(),
 	}
 
 	// Render the desired objects from the CRD and create or update them.
@@ -96,6 +96,8 @@ var _ = Describe("Manager controller tests", func() {
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
+			mockStatus.On("AddJobs", mock.Anything)
+			mockStatus.On("IsRolloutPaused").Return(false)
 			mockStatus.On("IsAvailable").Return(true)
 			mockStatus.On("OnCRFound").Return()
 			mockStatus.On("ClearDegraded")
@@ -378,6 +380,8 @@ var _ = Describe("Manager controller tests", func() {
 			mockStatus.On("AddDeployments", mock.Anything).Return()
 			mockStatus.On("AddStatefulSets", mock.Anything).Return()
 			mockStatus.On("AddCronJobs", mock.Anything)
+			mockStatus.On("AddJobs", mock.Anything)
+			mockStatus.On("IsRolloutPaused").Return(false)
 			mockStatus.On("IsAvailable").Return(true)
 			mockStatus.On("OnCRFound").Return()
 			mockStatus.On("ClearDegraded")
@@ -524,6 +528,40 @@ var _ = Describe("Manager controller tests", func() {
 					components.ComponentManagerProxy.Image,
 					components.ComponentManagerProxy.Version)))
 		})
+		It("should scale the manager with the replicas of the Manager", func() {
+			var managerReplicas int32 = 3
+			mgr := &operatorv1.Manager{}
+			Expect(c.Get(ctx, utils.DefaultTSEEInstanceKey, mgr)).NotTo(HaveOccurred())
+			mgr.Spec.Replicas = &managerReplicas
+			Expect(c.Update(ctx, mgr)).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			d := appsv1.Deployment{
+				TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tigera-manager",
+					Namespace: render.ManagerNamespace,
+				},
+			}
+			Expect(test.GetResource(c, &d)).To(BeNil())
+			Expect(*d.Spec.Replicas).To(Equal(managerReplicas))
+		})
+
+		It("should degrade with an unsupported cipher suite", func() {
+			mgr := &operatorv1.Manager{}
+			Expect(c.Get(ctx, utils.DefaultTSEEInstanceKey, mgr)).NotTo(HaveOccurred())
+			mgr.Spec.TLS = &operatorv1.TLSSettings{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}
+			Expect(c.Update(ctx, mgr)).NotTo(HaveOccurred())
+			msg := `spec.tls: "TLS_RSA_WITH_RC4_128_SHA" is not a supported TLS 1.2 cipher suite`
+			mockStatus.On("SetDegraded", "Error validating Manager", msg).Return()
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Error validating Manager", msg)
+		})
+
 		It("should use images from imageset", func() {
 			Expect(c.Create(ctx, &operatorv1.ImageSet{
 				ObjectMeta: metav1.ObjectMeta{Name: "enterprise-" + components.EnterpriseRelease},
@@ -172,7 +172,6 @@ func handleCore(c *components, install *operatorv1.Installation) error {
 	c.node.ignoreEnv("calico-node", "CALICO_IPV4POOL_VXLAN")
 	c.node.ignoreEnv("calico-node", "FELIX_LOGSEVERITYSCREEN")
 	c.node.ignoreEnv("calico-node", "FELIX_HEALTHENABLED")
-	c.node.ignoreEnv("calico-node", "FELIX_USAGEREPORTINGENABLED")
 	c.node.ignoreEnv("calico-node", "FELIX_TYPHAK8SSERVICENAME")
 	c.node.ignoreEnv("calico-node", "FELIX_LOGSEVERITYSYS")
 	c.node.ignoreEnv("upgrade-ipam", "KUBERNETES_NODE_NAME")
@@ -441,3 +440,17 @@ func handleFelixNodeMetrics(c *components, install *operatorv1.Installation) err
 
 	return nil
 }
+
+// handleFelixUsageReporting is a migration handler which carries an opt-out of the felix usage reporting forward
+// via the UsageReporting field.
+func handleFelixUsageReporting(c *components, install *operatorv1.Installation) error {
+	usageReporting, err := c.node.getEnv(ctx, c.client, containerCalicoNode, "FELIX_USAGEREPORTINGENABLED")
+	if err != nil {
+		return err
+	}
+	if usageReporting != nil && strings.ToLower(*usageReporting) == "false" {
+		disabled := operatorv1.UsageReportingDisabled
+		install.Spec.UsageReporting = &disabled
+	}
+	return nil
+}
@@ -596,4 +596,22 @@ var _ = Describe("core handler", func() {
 			Expect(*i.Spec.NodeMetricsPort).To(Equal(int32(7777)))
 		})
 	})
+	Context("felix usage reporting", func() {
+		It("carries the opt-out forward", func() {
+			comps.node.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{
+				Name:  "FELIX_USAGEREPORTINGENABLED",
+				Value: "false",
+			}}
+			Expect(handleFelixUsageReporting(&comps, i)).ToNot(HaveOccurred())
+			Expect(*i.Spec.UsageReporting).To(Equal(operatorv1.UsageReportingDisabled))
+		})
+		It("leaves the usage reporting to the default otherwise", func() {
+			comps.node.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{
+				Name:  "FELIX_USAGEREPORTINGENABLED",
+				Value: "true",
+			}}
+			Expect(handleFelixUsageReporting(&comps, i)).ToNot(HaveOccurred())
+			Expect(i.Spec.UsageReporting).To(BeNil())
+		})
+	})
 })
@@ -20,6 +20,7 @@ var handlers = []handler{
 	handleAnnotations,
 	handleNodeSelectors,
 	handleFelixNodeMetrics,
+	handleFelixUsageReporting,
 	handleTyphaMetrics,
 	handleCalicoCNI,
 	handleNonCalicoCNI,
@@ -136,11 +136,8 @@ func handleCalicoCNI(c *components, install *operatorv1.Installation) error {
 		}
 	}
 	if c.cni.CalicoConfig.ContainerSettings.AllowIPForwarding {
-		return ErrIncompatibleCluster{
-			err:       "AllowIPForwarding not supported",
-			component: ComponentCNIConfig,
-			fix:       "disable 'AllowIPForwarding' in the CNI configuration",
-		}
+		ipForwarding := v1.ContainerIPForwardingEnabled
+		install.Spec.CalicoNetwork.ContainerIPForwarding = &ipForwarding
 	}
 
 	return nil
@@ -502,6 +502,47 @@ var _ = Describe("Convert network tests", func() {
 					Expect(*cfg.Spec.CalicoNetwork.HostPorts).To(Equal(operatorv1.HostPortsEnabled))
 				})
 			})
+			It("migrates the container ip forwarding setting", func() {
+				ds := emptyNodeSpec()
+				ds.Spec.Template.Spec.InitContainers[0].Env = []corev1.EnvVar{{
+					Name: "CNI_NETWORK_CONFIG",
+					Value: `{
+"name": "k8s-pod-network",
+"cniVersion": "0.3.1",
+"plugins": [
+  {
+	"type": "calico",
+	"log_level": "info",
+	"datastore_type": "kubernetes",
+	"nodename": "__KUBERNETES_NODE_NAME__",
+	"mtu": __CNI_MTU__,
+	"ipam": {
+		"type": "host-local"
+	},
+	"container_settings": {
+		"allow_ip_forwarding": true
+	},
+	"policy": {
+		"type": "k8s"
+	},
+	"kubernetes": {
+		"kubeconfig": "__KUBECONFIG_FILEPATH__"
+	}
+  }
+  ]
+}`,
+				}}
+				ds.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{
+					Name:  "CALICO_NETWORKING_BACKEND",
+					Value: "bird",
+				}}
+				c := fake.NewFakeClientWithScheme(scheme, ds, emptyKubeControllerSpec(), pool, emptyFelixConfig())
+				cfg, err := Convert(ctx, c)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cfg).ToNot(BeNil())
+				Expect(cfg.Spec.CalicoNetwork.ContainerIPForwarding).NotTo(BeNil())
+				Expect(*cfg.Spec.CalicoNetwork.ContainerIPForwarding).To(Equal(operatorv1.ContainerIPForwardingEnabled))
+			})
 			DescribeTable("block on IPAM flags", func(ipam string) {
 				ds := emptyNodeSpec()
 				ds.Spec.Template.Spec.InitContainers[0].Env = []corev1.EnvVar{{
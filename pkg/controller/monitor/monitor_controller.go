@@ -64,7 +64,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 	reconciler := newReconciler(mgr, opts, prometheusReady)
 
 	// Create a new controller
-	controller, err := controller.New("monitor-controller", mgr, controller.Options{Reconciler: reconciler})
+	controller, err := controller.New("monitor-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("monitor-controller", reconciler)})
 	if err != nil {
 		return fmt.Errorf("failed to create monitor-controller: %w", err)
 	}
@@ -115,7 +115,8 @@ func add(mgr manager.Manager, c controller.Controller) error {
 		return fmt.Errorf("monitor-controller failed to watch ImageSet: %w", err)
 	}
 
-	if err = utils.AddSecretsWatch(c, monitor.PrometheusTLSSecretName, common.OperatorNamespace()); err != nil {
+	// Watch all secrets in the operator namespace, the remote write authentication secrets are named by the user.
+	if err = utils.AddSecretsWatch(c, "", common.OperatorNamespace()); err != nil {
 		return fmt.Errorf("monitor-controller failed to watch secret: %w", err)
 	}
 
@@ -170,6 +171,11 @@ func (r *ReconcileMonitor) Reconcile(ctx context.Context, request reconcile.Requ
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 	r.status.OnCRFound()
 
+	if err = monitor.ValidateScrapeTargets(instance.Spec.ScrapeTargets); err != nil {
+		r.setDegraded(reqLogger, err, "Invalid Monitor scrapeTargets")
+		return reconcile.Result{}, nil
+	}
+
 	variant, install, err := utils.GetInstallation(context.Background(), r.client)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -261,6 +267,7 @@ func (r *ReconcileMonitor) Reconcile(ctx context.Context, request reconcile.Requ
 
 	// Create a component handler to manage the rendered component.
 	hdler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	hdler.SetHardening(install.Hardening)
 
 	alertmanagerConfigSecret, createInOperatorNamespace, err := r.readAlertmanagerConfigSecret(ctx)
 	if err != nil {
@@ -268,13 +275,28 @@ func (r *ReconcileMonitor) Reconcile(ctx context.Context, request reconcile.Requ
 		return reconcile.Result{}, err
 	}
 
+	remoteWriteSecrets, err := r.getRemoteWriteSecrets(ctx, instance)
+	if err != nil {
+		r.setDegraded(reqLogger, err, "Error retrieving remote write secrets")
+		return reconcile.Result{}, err
+	}
+
+	thanosObjectStorageSecret, err := r.getThanosObjectStorageSecret(ctx, instance)
+	if err != nil {
+		r.setDegraded(reqLogger, err, "Error retrieving Thanos object storage secret")
+		return reconcile.Result{}, err
+	}
+
 	monitorCfg := &monitor.Config{
-		Installation:             install,
-		PullSecrets:              pullSecrets,
-		AlertmanagerConfigSecret: alertmanagerConfigSecret,
-		KeyValidatorConfig:       keyValidatorConfig,
-		TLSSecret:                tlsSecret,
-		ClusterDomain:            r.clusterDomain,
+		Monitor:                   instance.Spec,
+		Installation:              install,
+		PullSecrets:               pullSecrets,
+		AlertmanagerConfigSecret:  alertmanagerConfigSecret,
+		KeyValidatorConfig:        keyValidatorConfig,
+		TLSSecret:                 tlsSecret,
+		ClusterDomain:             r.clusterDomain,
+		RemoteWriteSecrets:        remoteWriteSecrets,
+		ThanosObjectStorageSecret: thanosObjectStorageSecret,
 	}
 
 	// Render prometheus component
@@ -317,6 +339,61 @@ func (r *ReconcileMonitor) Reconcile(ctx context.Context, request reconcile.Requ
 	return reconcile.Result{}, nil
 }
 
+// getRemoteWriteSecrets retrieves and validates the authentication secrets referenced by the remote write endpoints of
+// the Monitor. A secret must either contain a token or both a username and a password.
+func (r *ReconcileMonitor) getRemoteWriteSecrets(ctx context.Context, instance *operatorv1.Monitor) ([]*corev1.Secret, error) {
+	if instance.Spec.Prometheus == nil {
+		return nil, nil
+	}
+
+	var secrets []*corev1.Secret
+	seen := map[string]bool{}
+	for _, rw := range instance.Spec.Prometheus.RemoteWrite {
+		if rw.AuthSecretName == "" || seen[rw.AuthSecretName] {
+			continue
+		}
+		seen[rw.AuthSecretName] = true
+
+		secret, err := utils.GetSecret(ctx, r.client, rw.AuthSecretName, common.OperatorNamespace())
+		if err != nil {
+			return nil, err
+		} else if secret == nil {
+			return nil, fmt.Errorf("remote write secret %q not found in namespace %q", rw.AuthSecretName, common.OperatorNamespace())
+		}
+
+		if _, ok := secret.Data[monitor.RemoteWriteTokenKey]; !ok {
+			for _, key := range []string{monitor.RemoteWriteUsernameKey, monitor.RemoteWritePasswordKey} {
+				if _, ok := secret.Data[key]; !ok {
+					return nil, fmt.Errorf("Expected secret %q to have a field named %q", rw.AuthSecretName, key)
+				}
+			}
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+// getThanosObjectStorageSecret retrieves and validates the object storage configuration secret of the Thanos sidecar.
+// nil is returned when the Monitor doesn't reference such a secret.
+func (r *ReconcileMonitor) getThanosObjectStorageSecret(ctx context.Context, instance *operatorv1.Monitor) (*corev1.Secret, error) {
+	if instance.Spec.Prometheus == nil || instance.Spec.Prometheus.Thanos == nil || instance.Spec.Prometheus.Thanos.ObjectStorageConfigSecretName == "" {
+		return nil, nil
+	}
+
+	name := instance.Spec.Prometheus.Thanos.ObjectStorageConfigSecretName
+	secret, err := utils.GetSecret(ctx, r.client, name, common.OperatorNamespace())
+	if err != nil {
+		return nil, err
+	} else if secret == nil {
+		return nil, fmt.Errorf("Thanos object storage secret %q not found in namespace %q", name, common.OperatorNamespace())
+	}
+
+	if _, ok := secret.Data[monitor.ThanosObjectStorageConfigKey]; !ok {
+		return nil, fmt.Errorf("Expected secret %q to have a field named %q", name, monitor.ThanosObjectStorageConfigKey)
+	}
+	return secret, nil
+}
+
 //go:embed alertmanager-config.yaml
 var alertmanagerConfig string
 
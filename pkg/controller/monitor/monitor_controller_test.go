@@ -62,6 +62,8 @@ var _ = Describe("Monitor controller tests", func() {
 		// Create an object we can use throughout the test to do the monitor reconcile loops.
 		mockStatus = &status.MockStatus{}
 		mockStatus.On("AddCronJobs", mock.Anything)
+		mockStatus.On("AddJobs", mock.Anything)
+		mockStatus.On("IsRolloutPaused").Return(false)
 		mockStatus.On("AddDaemonsets", mock.Anything)
 		mockStatus.On("AddDeployments", mock.Anything).Return()
 		mockStatus.On("AddStatefulSets", mock.Anything)
@@ -171,6 +173,20 @@ var _ = Describe("Monitor controller tests", func() {
 			Expect(cli.Delete(ctx, secretPrometheus)).To(BeNil())
 		})
 
+		It("should degrade with a scrape timeout greater than the interval", func() {
+			m := &operatorv1.Monitor{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, m)).NotTo(HaveOccurred())
+			m.Spec.ScrapeTargets = []operatorv1.ScrapeTarget{{Name: operatorv1.ScrapeTargetCalicoNode, Interval: "10s", ScrapeTimeout: "30s"}}
+			Expect(cli.Update(ctx, m)).NotTo(HaveOccurred())
+			msg := "the scrapeTimeout 30s of the scrape target CalicoNode is greater than its interval 10s"
+			mockStatus.On("SetDegraded", "Invalid Monitor scrapeTargets", msg).Return()
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid Monitor scrapeTargets", msg)
+			Expect(cli.Get(ctx, client.ObjectKey{Name: monitor.CalicoNodeMonitor, Namespace: common.TigeraPrometheusNamespace}, sm)).To(HaveOccurred())
+		})
+
 		It("should create the Alertmanager secret for new install", func() {
 			s := &corev1.Secret{}
 			// Make sure Alertmanager secrets don't exist in either Operator or Prometheus namespace.
@@ -5,6 +5,7 @@ import (
 
 	v1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
 )
 
 // AddOptions are passed to controllers when added to the controller manager. They
@@ -16,7 +17,9 @@ type AddOptions struct {
 	EnterpriseCRDExists bool
 	AmazonCRDExists     bool
 	ClusterDomain       string
-	KubernetesVersion   *common.VersionInfo
+	KubernetesVersion   *common.KubernetesVersionTracker
 	ManageCRDs          bool
 	ShutdownContext     context.Context
+	// LicenseKeyCache is shared by the controllers reading the LicenseKey.
+	LicenseKeyCache *utils.LicenseKeyCache
 }
@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/mock"
 	"k8s.io/apimachinery/pkg/types"
+
+	operator "github.com/tigera/operator/api/v1"
 )
 
 // TODO use mockery to generate mock
@@ -44,6 +46,10 @@ func (m *MockStatus) AddCronJobs(cjs []types.NamespacedName) {
 	m.Called(cjs)
 }
 
+func (m *MockStatus) AddJobs(jobs []types.NamespacedName) {
+	m.Called(jobs)
+}
+
 func (m *MockStatus) AddCertificateSigningRequests(name string, labels map[string]string) {
 	m.Called(name)
 }
@@ -64,6 +70,10 @@ func (m *MockStatus) RemoveCronJobs(cjs ...types.NamespacedName) {
 	m.Called(cjs)
 }
 
+func (m *MockStatus) RemoveJobs(jobs ...types.NamespacedName) {
+	m.Called(jobs)
+}
+
 func (m *MockStatus) RemoveCertificateSigningRequests(label string) {
 	m.Called(label)
 }
@@ -72,6 +82,14 @@ func (m *MockStatus) SetWindowsUpgradeStatus(pending, inProgress, completed []st
 	m.Called(pending, inProgress, completed, err)
 }
 
+func (m *MockStatus) SetEncryptionStatus(st *operator.EncryptionStatus) {
+	m.Called(st)
+}
+
+func (m *MockStatus) SetPullSecretsStatus(updated, restarted []string, err error) {
+	m.Called(updated, restarted, err)
+}
+
 func (m *MockStatus) SetDegraded(reason, msg string) {
 	m.Called(reason, msg)
 }
@@ -92,6 +110,10 @@ func (m *MockStatus) IsDegraded() bool {
 	return m.Called().Bool(0)
 }
 
+func (m *MockStatus) IsRolloutPaused() bool {
+	return m.Called().Bool(0)
+}
+
 func (m *MockStatus) WasCalled(method string, arguments ...interface{}) bool {
 	for _, call := range m.Calls {
 		if call.Method == method {
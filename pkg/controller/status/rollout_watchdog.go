@@ -0,0 +1,194 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+)
+
+const (
+	// DefaultRolloutStallTimeout is how long a rollout can go without progress before it is reported stalled, when
+	// the stall timeout of the rollout watchdog is not set.
+	DefaultRolloutStallTimeout = 10 * time.Minute
+
+	// maxStalledPods is the number of pods which are not ready listed in the message of a stalled rollout.
+	maxStalledPods = 5
+)
+
+// RolloutWatchdog configures the detection of the stalled rollouts of the daemonsets and deployments by the status
+// managers.
+type RolloutWatchdog struct {
+	// StallTimeout is how long a rollout can go without progress before it is reported stalled.
+	StallTimeout time.Duration
+
+	// PauseOnStall pauses the changes of the components with a stalled rollout.
+	PauseOnStall bool
+
+	// AcknowledgedAt is when the stalls were last acknowledged. A rollout is reported stalled only once it didn't
+	// progress for the stall timeout since then.
+	AcknowledgedAt time.Time
+}
+
+// NewRolloutWatchdog returns the RolloutWatchdog configured by the given spec, or nil if the rollouts are not
+// watched.
+func NewRolloutWatchdog(spec *operator.RolloutWatchdog) *RolloutWatchdog {
+	if spec == nil {
+		return nil
+	}
+	w := &RolloutWatchdog{
+		StallTimeout: DefaultRolloutStallTimeout,
+		PauseOnStall: spec.PauseOnStall != nil && *spec.PauseOnStall == operator.RolloutPauseOnStallEnabled,
+	}
+	if spec.StallTimeout != nil {
+		w.StallTimeout = spec.StallTimeout.Duration
+	}
+	if spec.StallsAcknowledgedAt != nil {
+		w.AcknowledgedAt = spec.StallsAcknowledgedAt.Time
+	}
+	return w
+}
+
+var (
+	rolloutWatchdogLock sync.RWMutex
+	rolloutWatchdog     *RolloutWatchdog
+
+	// now returns the current time, and is replaced by the tests.
+	now = time.Now
+)
+
+// SetRolloutWatchdog configures the rollout watchdog of all the status managers. A nil watchdog stops watching the
+// rollouts.
+func SetRolloutWatchdog(w *RolloutWatchdog) {
+	rolloutWatchdogLock.Lock()
+	defer rolloutWatchdogLock.Unlock()
+	rolloutWatchdog = w
+}
+
+func getRolloutWatchdog() *RolloutWatchdog {
+	rolloutWatchdogLock.RLock()
+	defer rolloutWatchdogLock.RUnlock()
+	return rolloutWatchdog
+}
+
+// rolloutProgress is the last observed progress of a rollout.
+type rolloutProgress struct {
+	// state summarizes the status of the workload. The rollout progresses when it changes.
+	state string
+
+	// since is when the state was first observed.
+	since time.Time
+}
+
+// stalledFor returns how long the rollout went without progress, since the last acknowledgment of the stalls.
+func (p *rolloutProgress) stalledFor(w *RolloutWatchdog) time.Duration {
+	since := p.since
+	if w.AcknowledgedAt.After(since) {
+		since = w.AcknowledgedAt
+	}
+	return now().Sub(since)
+}
+
+// rolloutStalled records in rollouts the progress of a rollout which is not complete, and returns why it is stalled,
+// or "" if it progressed within the stall timeout.
+func (m *statusManager) rolloutStalled(rollouts map[string]*rolloutProgress, kind string, nn types.NamespacedName, state string, selector *metav1.LabelSelector) string {
+	key := kind + "/" + nn.String()
+	p := m.rollouts[key]
+	if p == nil || p.state != state {
+		p = &rolloutProgress{state: state, since: now()}
+	}
+	rollouts[key] = p
+
+	w := getRolloutWatchdog()
+	if w == nil || p.stalledFor(w) < w.StallTimeout {
+		return ""
+	}
+	msg := fmt.Sprintf("%s %q rollout has not progressed for %s", kind, nn.String(), w.StallTimeout)
+	if pods := m.podsNotReady(selector, nn.Namespace); len(pods) > 0 {
+		msg += ", pods not ready: " + strings.Join(pods, ", ")
+	}
+	return msg
+}
+
+// IsRolloutPaused returns true if the changes of the component are paused because one of its rollouts is stalled, until
+// the stall is acknowledged.
+func (m *statusManager) IsRolloutPaused() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	w := getRolloutWatchdog()
+	if w == nil || !w.PauseOnStall {
+		return false
+	}
+	// The acknowledgment of the stalls resumes the changes right away, rather than at the next sync of the state.
+	for _, p := range m.rollouts {
+		if p.stalledFor(w) >= w.StallTimeout {
+			return true
+		}
+	}
+	return false
+}
+
+// podsNotReady returns the pods matching the selector which are not ready, with the reason they are waiting if any.
+func (m *statusManager) podsNotReady(selector *metav1.LabelSelector, namespace string) []string {
+	l := corev1.PodList{}
+	s, err := metav1.LabelSelectorAsMap(selector)
+	if err != nil {
+		return nil
+	}
+	if err := m.client.List(context.TODO(), &l, client.MatchingLabels(s), client.InNamespace(namespace)); err != nil {
+		log.WithValues("reason", err).Info("Failed to query pods")
+		return nil
+	}
+
+	var pods []string
+	for _, p := range l.Items {
+		if podReady(p) {
+			continue
+		}
+		if len(pods) == maxStalledPods {
+			pods = append(pods, "...")
+			break
+		}
+		pod := p.Name
+		for _, c := range append(p.Status.InitContainerStatuses, p.Status.ContainerStatuses...) {
+			if c.State.Waiting != nil && c.State.Waiting.Reason != "" {
+				pod += " (" + c.State.Waiting.Reason + ")"
+				break
+			}
+		}
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+func podReady(p corev1.Pod) bool {
+	for _, c := range p.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -41,6 +41,9 @@ import (
 
 var log = logf.Log.WithName("status_manager")
 
+// The maximum number of nodes listed in the message of the Encrypted condition.
+const maxEncryptionMessageNodes = 10
+
 // StatusManager manages the status for a single controller and component, and reports the status via
 // a TigeraStatus API object. The status manager uses the following conditions/states to represent the
 // component's current status:
@@ -51,6 +54,10 @@ var log = logf.Log.WithName("status_manager")
 //                first time, or being upgraded to a new configuration or version.
 // - Degraded: The component is not running the desired state and is not progressing towards it. Either the
 //             component has not been installed, has been updated with invalid configuration, or has crashed.
+// - Encrypted: The traffic between all the nodes is encrypted with WireGuard or IPsec. It is only reported by the
+//              components which are given an encryption status.
+// - PullSecretsSynced: The copies of the pull secrets in the namespaces of the components are up to date. It is only
+//                      reported by the components which are given the status of the propagation of the pull secrets.
 //
 // Each of these states can be set independently of each other. For example, a component can be both available and
 // degraded if it is running successfully but a configuration change has resulted in a configuration that cannot
@@ -63,18 +70,23 @@ type StatusManager interface {
 	AddDeployments(deps []types.NamespacedName)
 	AddStatefulSets(sss []types.NamespacedName)
 	AddCronJobs(cjs []types.NamespacedName)
+	AddJobs(jobs []types.NamespacedName)
 	AddCertificateSigningRequests(name string, labels map[string]string)
 	RemoveDaemonsets(dss ...types.NamespacedName)
 	RemoveDeployments(dps ...types.NamespacedName)
 	RemoveStatefulSets(sss ...types.NamespacedName)
 	RemoveCronJobs(cjs ...types.NamespacedName)
+	RemoveJobs(jobs ...types.NamespacedName)
 	RemoveCertificateSigningRequests(name string)
 	SetWindowsUpgradeStatus(pending, inProgress, completed []string, err error)
+	SetEncryptionStatus(st *operator.EncryptionStatus)
+	SetPullSecretsStatus(updated, restarted []string, err error)
 	SetDegraded(reason, msg string)
 	ClearDegraded()
 	IsAvailable() bool
 	IsProgressing() bool
 	IsDegraded() bool
+	IsRolloutPaused() bool
 	ReadyToMonitor()
 }
 
@@ -85,11 +97,15 @@ type statusManager struct {
 	deployments               map[string]types.NamespacedName
 	statefulsets              map[string]types.NamespacedName
 	cronjobs                  map[string]types.NamespacedName
+	jobs                      map[string]types.NamespacedName
 	certificatestatusrequests map[string]map[string]string
 	windowsNodeUpgrades       *windowsNodeUpgrades
+	encryption                *operator.EncryptionStatus
+	encryptionReported        bool
+	pullSecrets               *pullSecretsStatus
 	lock                      sync.Mutex
 	enabled                   *bool
-	kubernetesVersion         *common.VersionInfo
+	kubernetesVersion         *common.KubernetesVersionTracker
 
 	// Track degraded state as set by external controllers.
 	degraded               bool
@@ -102,6 +118,10 @@ type statusManager struct {
 	progressing []string
 	failing     []string
 
+	// Track the progress of the rollouts of the daemonsets and deployments, and the rollouts which stalled.
+	rollouts map[string]*rolloutProgress
+	stalled  []string
+
 	// readyToMonitor tells the status manager that it's ready to monitor the resources that it's been told to monitor,
 	// if there are any, and report statuses based on the state of those resources.
 	readyToMonitor bool
@@ -113,7 +133,7 @@ type statusManager struct {
 	crExists bool
 }
 
-func New(client client.Client, component string, kubernetesVersion *common.VersionInfo) StatusManager {
+func New(client client.Client, component string, kubernetesVersion *common.KubernetesVersionTracker) StatusManager {
 	// Best-effort initialization of CR status by checking for its existence.
 	crExists := true
 	ts := &operator.TigeraStatus{}
@@ -132,6 +152,7 @@ func New(client client.Client, component string, kubernetesVersion *common.Versi
 		deployments:               make(map[string]types.NamespacedName),
 		statefulsets:              make(map[string]types.NamespacedName),
 		cronjobs:                  make(map[string]types.NamespacedName),
+		jobs:                      make(map[string]types.NamespacedName),
 		certificatestatusrequests: make(map[string]map[string]string),
 		windowsNodeUpgrades:       newWindowsNodeUpgrades(),
 		kubernetesVersion:         kubernetesVersion,
@@ -174,6 +195,9 @@ func (m *statusManager) updateStatus() {
 		} else {
 			m.clearDegraded()
 		}
+
+		m.setEncrypted()
+		m.setPullSecretsSynced()
 	} else {
 		log.V(2).WithName(m.component).Info("Status manager is not ready to report component statuses.")
 
@@ -252,6 +276,9 @@ func (m *statusManager) OnCRNotFound() {
 	m.deployments = make(map[string]types.NamespacedName)
 	m.statefulsets = make(map[string]types.NamespacedName)
 	m.cronjobs = make(map[string]types.NamespacedName)
+	m.jobs = make(map[string]types.NamespacedName)
+	m.rollouts = make(map[string]*rolloutProgress)
+	m.stalled = []string{}
 }
 
 // AddDaemonsets tells the status manager to monitor the health of the given daemonsets.
@@ -290,6 +317,15 @@ func (m *statusManager) AddCronJobs(cjs []types.NamespacedName) {
 	}
 }
 
+// AddJobs tells the status manager to monitor the health of the given jobs.
+func (m *statusManager) AddJobs(jobs []types.NamespacedName) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, j := range jobs {
+		m.jobs[j.String()] = j
+	}
+}
+
 // AddCertificateSigningRequests tells the status manager to monitor the health of the given CertificateSigningRequests.
 func (m *statusManager) AddCertificateSigningRequests(name string, labels map[string]string) {
 	m.lock.Lock()
@@ -341,6 +377,83 @@ func (m *statusManager) SetWindowsUpgradeStatus(pending, inProgress, completed [
 	m.windowsUpgradeDegradedMsg = ""
 }
 
+// SetEncryptionStatus tells the status manager the encryption status of the traffic between the nodes, which is
+// reported with the Encrypted condition. A nil status means that the traffic is not encrypted.
+func (m *statusManager) SetEncryptionStatus(st *operator.EncryptionStatus) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.encryption = st
+	m.encryptionReported = true
+}
+
+// pullSecretsStatus tracks the propagation of the pull secrets: the namespaces whose copies were last updated, the pods
+// which were restarted then, and the error of the last propagation.
+type pullSecretsStatus struct {
+	updated   []string
+	restarted []string
+	err       error
+}
+
+// SetPullSecretsStatus tells the status manager the outcome of the propagation of the pull secrets, which is reported
+// with the PullSecretsSynced condition. The namespaces of the updated copies and the restarted pods are kept until the
+// next propagation which updates some copies.
+func (m *statusManager) SetPullSecretsStatus(updated, restarted []string, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.pullSecrets == nil {
+		m.pullSecrets = &pullSecretsStatus{}
+	}
+	m.pullSecrets.err = err
+	if len(updated) > 0 {
+		m.pullSecrets.updated = updated
+		m.pullSecrets.restarted = restarted
+	}
+}
+
+// pullSecretsCondition returns the PullSecretsSynced condition of the propagation of the pull secrets.
+func pullSecretsCondition(st *pullSecretsStatus) operator.TigeraStatusCondition {
+	if st.err != nil {
+		return operator.TigeraStatusCondition{Type: operator.ComponentPullSecretsSynced, Status: operator.ConditionFalse, Reason: "Failed to propagate the pull secrets", Message: st.err.Error()}
+	}
+	var msgs []string
+	if len(st.updated) > 0 {
+		msgs = append(msgs, fmt.Sprintf("Updated the copies in the namespaces %s", strings.Join(st.updated, ", ")))
+	}
+	if len(st.restarted) > 0 {
+		msgs = append(msgs, fmt.Sprintf("Restarted the pods failing to pull their images %s", strings.Join(st.restarted, ", ")))
+	}
+	return operator.TigeraStatusCondition{Type: operator.ComponentPullSecretsSynced, Status: operator.ConditionTrue, Reason: "The copies of the pull secrets are up to date", Message: strings.Join(msgs, "\n")}
+}
+
+// encryptedCondition returns the Encrypted condition of the encryption status: the traffic is encrypted when every
+// node has an established session with all the other nodes. The message lists at most maxEncryptionMessageNodes of
+// the nodes which don't encrypt all their traffic.
+func encryptedCondition(st *operator.EncryptionStatus) operator.TigeraStatusCondition {
+	if st == nil {
+		return operator.TigeraStatusCondition{Type: operator.ComponentEncrypted, Status: operator.ConditionFalse, Reason: "Encryption is not enabled"}
+	}
+	if st.Message != "" {
+		return operator.TigeraStatusCondition{Type: operator.ComponentEncrypted, Status: operator.ConditionUnknown, Reason: "Unable to collect the encryption status", Message: st.Message}
+	}
+	if st.UnencryptedNodes == 0 {
+		return operator.TigeraStatusCondition{Type: operator.ComponentEncrypted, Status: operator.ConditionTrue, Reason: fmt.Sprintf("All traffic is encrypted with %s", st.Type)}
+	}
+
+	msgs := []string{fmt.Sprintf("%d of %d nodes don't encrypt all their traffic", st.UnencryptedNodes, st.UnencryptedNodes+st.EncryptedNodes)}
+	for i, n := range st.Nodes {
+		if i == maxEncryptionMessageNodes {
+			msgs = append(msgs, fmt.Sprintf("and %d more", st.UnencryptedNodes-int32(i)))
+			break
+		}
+		if n.Error != "" {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", n.Name, n.Error))
+		} else {
+			msgs = append(msgs, fmt.Sprintf("%s: %d of %d peers unencrypted", n.Name, n.UnencryptedPeers, n.UnencryptedPeers+n.EstablishedPeers))
+		}
+	}
+	return operator.TigeraStatusCondition{Type: operator.ComponentEncrypted, Status: operator.ConditionFalse, Reason: "Some traffic is not encrypted", Message: strings.Join(msgs, "\n")}
+}
+
 // RemoveDaemonsets tells the status manager to stop monitoring the health of the given daemonsets
 func (m *statusManager) RemoveDaemonsets(dss ...types.NamespacedName) {
 	m.lock.Lock()
@@ -377,6 +490,15 @@ func (m *statusManager) RemoveCronJobs(cjs ...types.NamespacedName) {
 	}
 }
 
+// RemoveJobs tells the status manager to stop monitoring the health of the given jobs.
+func (m *statusManager) RemoveJobs(jobs ...types.NamespacedName) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, j := range jobs {
+		delete(m.jobs, j.String())
+	}
+}
+
 // RemoveCertificateSigningRequests tells the status manager to stop monitoring the health of the given CertificateSigningRequests.
 func (m *statusManager) RemoveCertificateSigningRequests(name string) {
 	m.lock.Lock()
@@ -466,6 +588,8 @@ func (m *statusManager) syncState() {
 	defer m.lock.Unlock()
 	progressing := []string{}
 	failing := []string{}
+	stalled := []string{}
+	rollouts := map[string]*rolloutProgress{}
 
 	// For each daemonset, check its rollout status.
 	for _, dsnn := range m.daemonsets {
@@ -475,6 +599,7 @@ func (m *statusManager) syncState() {
 			log.WithValues("reason", err).Info("Failed to query daemonset")
 			continue
 		}
+		n := len(progressing)
 		if ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
 			progressing = append(progressing, fmt.Sprintf("DaemonSet %q update is rolling out (%d out of %d updated)", dsnn.String(), ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled))
 		} else if ds.Status.NumberUnavailable > 0 {
@@ -484,6 +609,12 @@ func (m *statusManager) syncState() {
 		} else if ds.Generation > ds.Status.ObservedGeneration {
 			progressing = append(progressing, fmt.Sprintf("DaemonSet %q update is being processed (generation %d, observed generation %d)", dsnn.String(), ds.Generation, ds.Status.ObservedGeneration))
 		}
+		if len(progressing) > n {
+			state := fmt.Sprint(ds.Generation, ds.Status.ObservedGeneration, ds.Status.UpdatedNumberScheduled, ds.Status.NumberAvailable)
+			if msg := m.rolloutStalled(rollouts, "DaemonSet", dsnn, state, ds.Spec.Selector); msg != "" {
+				stalled = append(stalled, msg)
+			}
+		}
 
 		// Check if any pods within the daemonset are failing.
 		if f := m.podsFailing(ds.Spec.Selector, ds.Namespace); f != "" {
@@ -498,6 +629,7 @@ func (m *statusManager) syncState() {
 			log.WithValues("reason", err).Info("Failed to query deployment")
 			continue
 		}
+		n := len(progressing)
 		if dep.Status.UnavailableReplicas > 0 {
 			progressing = append(progressing, fmt.Sprintf("Deployment %q is not available (awaiting %d replicas)", depnn.String(), dep.Status.UnavailableReplicas))
 		} else if dep.Status.AvailableReplicas == 0 {
@@ -505,6 +637,12 @@ func (m *statusManager) syncState() {
 		} else if dep.Status.ObservedGeneration < dep.Generation {
 			progressing = append(progressing, fmt.Sprintf("Deployment %q update is being processed (generation %d, observed generation %d)", depnn.String(), dep.Generation, dep.Status.ObservedGeneration))
 		}
+		if len(progressing) > n {
+			state := fmt.Sprint(dep.Generation, dep.Status.ObservedGeneration, dep.Status.UpdatedReplicas, dep.Status.AvailableReplicas)
+			if msg := m.rolloutStalled(rollouts, "Deployment", depnn, state, dep.Spec.Selector); msg != "" {
+				stalled = append(stalled, msg)
+			}
+		}
 
 		// Check if any pods within the deployment are failing.
 		if f := m.podsFailing(dep.Spec.Selector, dep.Namespace); f != "" {
@@ -531,28 +669,52 @@ func (m *statusManager) syncState() {
 		}
 	}
 
-	for _, depnn := range m.cronjobs {
+	for _, cjnn := range m.cronjobs {
 		cj := &batch.CronJob{}
-		if err := m.client.Get(context.TODO(), depnn, cj); err != nil {
+		if err := m.client.Get(context.TODO(), cjnn, cj); err != nil {
 			log.WithValues("reason", err).Info("Failed to query cronjobs")
 			continue
 		}
 
-		var numFailed = 0
-		for _, jref := range cj.Status.Active {
-			j := &batchv1.Job{}
-			if err := m.client.Get(context.TODO(), types.NamespacedName{Namespace: jref.Namespace, Name: jref.Name}, j); err != nil {
-				log.WithValues("reason", err).Info("couldn't query cronjob job")
+		// The jobs of a cronjob are no longer active once they failed, so the failure is reported from the last
+		// job of the cronjob which finished, or from the active jobs retrying after a failure.
+		jobs := &batchv1.JobList{}
+		if err := m.client.List(context.TODO(), jobs, client.InNamespace(cj.Namespace)); err != nil {
+			log.WithValues("reason", err).Info("couldn't query cronjob jobs")
+			continue
+		}
+		var last *batchv1.Job
+		for i := range jobs.Items {
+			j := &jobs.Items[i]
+			if !metav1.IsControlledBy(j, cj) {
 				continue
 			}
-
-			if j.Status.Failed > 0 {
-				numFailed++
+			if f := jobRetrying(j); f != "" {
+				failing = append(failing, fmt.Sprintf("CronJob %q %s", cjnn.String(), f))
+			}
+			if jobFinished(j) && (last == nil || last.CreationTimestamp.Before(&j.CreationTimestamp)) {
+				last = j
 			}
 		}
+		if last != nil {
+			if f := jobFailed(last); f != "" {
+				failing = append(failing, fmt.Sprintf("CronJob %q %s", cjnn.String(), f))
+			}
+		}
+	}
 
-		if numFailed > 0 {
-			failing = append(failing, "cronjob/"+cj.Name+" failed in ns '"+cj.Namespace+"'")
+	for _, jnn := range m.jobs {
+		j := &batchv1.Job{}
+		if err := m.client.Get(context.TODO(), jnn, j); err != nil {
+			log.WithValues("reason", err).Info("Failed to query job")
+			continue
+		}
+		if f := jobFailed(j); f != "" {
+			failing = append(failing, fmt.Sprintf("Job %q %s", jnn.String(), f))
+		} else if f := jobRetrying(j); f != "" {
+			failing = append(failing, fmt.Sprintf("Job %q %s", jnn.String(), f))
+		} else if !jobFinished(j) {
+			progressing = append(progressing, fmt.Sprintf("Job %q has not completed yet", jnn.String()))
 		}
 	}
 
@@ -570,10 +732,44 @@ func (m *statusManager) syncState() {
 	}
 
 	m.progressing = progressing
-	m.failing = failing
+	m.failing = append(failing, stalled...)
+	m.stalled = stalled
+	m.rollouts = rollouts
 	m.hasSynced = true
 }
 
+// jobFinished returns true if the job completed or failed.
+func jobFinished(j *batchv1.Job) bool {
+	for _, c := range j.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// jobFailed returns why the job failed, once it ran out of retries or exceeded its deadline, or "" if it didn't.
+func jobFailed(j *batchv1.Job) string {
+	for _, c := range j.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return strings.TrimSuffix(fmt.Sprintf("failed (%s): %s", c.Reason, c.Message), ": ")
+		}
+	}
+	return ""
+}
+
+// jobRetrying returns the failures of the job while it is retrying its pods with a backoff, or "" if it isn't.
+func jobRetrying(j *batchv1.Job) string {
+	if jobFinished(j) || j.Status.Failed == 0 {
+		return ""
+	}
+	backoffLimit := int32(6)
+	if j.Spec.BackoffLimit != nil {
+		backoffLimit = *j.Spec.BackoffLimit
+	}
+	return fmt.Sprintf("is retrying after %d failed attempts (backoff limit %d)", j.Status.Failed, backoffLimit)
+}
+
 // isInitialized returns true if corresponding CR has been queried
 func (m *statusManager) isInitialized() bool {
 	m.lock.Lock()
@@ -691,8 +887,12 @@ func (m *statusManager) set(retry bool, conditions ...operator.TigeraStatusCondi
 		}
 	}
 
+	if m.encryptionReported {
+		ts.Status.Encryption = m.encryption.DeepCopy()
+	}
+
 	// If nothing has changed, we don't need to update in the API.
-	if reflect.DeepEqual(ts.Status.Conditions, old.Status.Conditions) {
+	if reflect.DeepEqual(ts.Status, old.Status) {
 		return
 	}
 
@@ -745,6 +945,28 @@ func (m *statusManager) setProgressing(reason, msg string) {
 	m.set(true, conditions...)
 }
 
+// setEncrypted reports the encryption status, if it was given.
+func (m *statusManager) setEncrypted() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.encryptionReported {
+		return
+	}
+	m.set(true, encryptedCondition(m.encryption))
+}
+
+// setPullSecretsSynced reports the propagation of the pull secrets, if it was given.
+func (m *statusManager) setPullSecretsSynced() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.pullSecrets == nil {
+		return
+	}
+	m.set(true, pullSecretsCondition(m.pullSecrets))
+}
+
 func (m *statusManager) clearDegraded() {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -806,14 +1028,17 @@ func (m *statusManager) degradedReason() string {
 	if m.windowsUpgradeDegradedMsg != "" {
 		reasons = append(reasons, common.CalicoWindowsNodeUpgradeStatusErrorReason)
 	}
-	if len(m.failing) != 0 {
+	if len(m.failing) > len(m.stalled) {
 		reasons = append(reasons, "Some pods are failing")
 	}
+	if len(m.stalled) != 0 {
+		reasons = append(reasons, "Some rollouts are stalled")
+	}
 	return strings.Join(reasons, "; ")
 }
 
 func hasPendingCSR(ctx context.Context, m *statusManager, labelMap map[string]string) (bool, error) {
-	if m.kubernetesVersion.ProvidesCertV1API() {
+	if m.kubernetesVersion.Get().ProvidesCertV1API() {
 		return hasPendingCSRUsingCertV1(ctx, m.client, labelMap)
 	}
 	// For k8s v1.19 onwards, certificate/v1beta1 will be deprecated and is planning to be removed on 1.22
@@ -17,11 +17,16 @@ package status
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	certV1 "k8s.io/api/certificates/v1"
 	certV1beta1 "k8s.io/api/certificates/v1beta1"
 	corev1 "k8s.io/api/core/v1"
@@ -52,11 +57,15 @@ var _ = Describe("Status reporting tests", func() {
 		// Setup Scheme for all resources
 		scheme := runtime.NewScheme()
 		Expect(certV1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(appsv1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(batchv1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Expect(batchv1beta1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
 		err := apis.AddToScheme(scheme)
 		Expect(err).NotTo(HaveOccurred())
 		client = fake.NewFakeClientWithScheme(scheme)
 
-		sm = New(client, "test-component", &common.VersionInfo{Major: 1, Minor: 19}).(*statusManager)
+		sm = New(client, "test-component", common.NewKubernetesVersionTracker(nil, &common.VersionInfo{Major: 1, Minor: 19})).(*statusManager)
 		Expect(sm.IsAvailable()).To(BeFalse())
 
 		oldScheme := runtime.NewScheme()
@@ -65,7 +74,7 @@ var _ = Describe("Status reporting tests", func() {
 		Expect(err).NotTo(HaveOccurred())
 		oldVersionClient = fake.NewFakeClientWithScheme(oldScheme)
 
-		oldVersionSm = New(oldVersionClient, "test-component", &common.VersionInfo{Major: 1, Minor: 18}).(*statusManager)
+		oldVersionSm = New(oldVersionClient, "test-component", common.NewKubernetesVersionTracker(nil, &common.VersionInfo{Major: 1, Minor: 18})).(*statusManager)
 		Expect(oldVersionSm.IsAvailable()).To(BeFalse())
 	})
 
@@ -484,5 +493,268 @@ var _ = Describe("Status reporting tests", func() {
 				Expect(sm.windowsNodeUpgrades.progressingReason()).To(Equal(""))
 			})
 		})
+
+		Context("Jobs", func() {
+			var backoffLimit int32 = 3
+
+			failedCondition := batchv1.JobCondition{
+				Type: batchv1.JobFailed, Status: corev1.ConditionTrue,
+				Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit",
+			}
+			completeCondition := batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}
+
+			It("should report the failed and the retrying jobs", func() {
+				Expect(client.Create(ctx, &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "NS1", Name: "failed"},
+					Spec:       batchv1.JobSpec{BackoffLimit: &backoffLimit},
+					Status:     batchv1.JobStatus{Failed: 4, Conditions: []batchv1.JobCondition{failedCondition}},
+				})).NotTo(HaveOccurred())
+				Expect(client.Create(ctx, &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "NS1", Name: "retrying"},
+					Spec:       batchv1.JobSpec{BackoffLimit: &backoffLimit},
+					Status:     batchv1.JobStatus{Active: 1, Failed: 2},
+				})).NotTo(HaveOccurred())
+				Expect(client.Create(ctx, &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "NS1", Name: "running"},
+					Status:     batchv1.JobStatus{Active: 1},
+				})).NotTo(HaveOccurred())
+				Expect(client.Create(ctx, &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "NS1", Name: "complete"},
+					Status:     batchv1.JobStatus{Succeeded: 1, Conditions: []batchv1.JobCondition{completeCondition}},
+				})).NotTo(HaveOccurred())
+
+				sm.AddJobs([]types.NamespacedName{
+					{Namespace: "NS1", Name: "failed"},
+					{Namespace: "NS1", Name: "retrying"},
+					{Namespace: "NS1", Name: "running"},
+					{Namespace: "NS1", Name: "complete"},
+				})
+				sm.syncState()
+				Expect(sm.failing).To(ConsistOf(
+					`Job "NS1/failed" failed (BackoffLimitExceeded): Job has reached the specified backoff limit`,
+					`Job "NS1/retrying" is retrying after 2 failed attempts (backoff limit 3)`,
+				))
+				Expect(sm.progressing).To(ConsistOf(`Job "NS1/running" has not completed yet`))
+
+				sm.RemoveJobs(types.NamespacedName{Namespace: "NS1", Name: "failed"}, types.NamespacedName{Namespace: "NS1", Name: "retrying"})
+				sm.syncState()
+				Expect(sm.failing).To(BeEmpty())
+			})
+
+			It("should report the cronjobs whose last job failed", func() {
+				cj := &batchv1beta1.CronJob{ObjectMeta: metav1.ObjectMeta{Namespace: "NS1", Name: "CJ1", UID: "cj1"}}
+				Expect(client.Create(ctx, cj)).NotTo(HaveOccurred())
+				isController := true
+				job := func(name string, created int64, conditions ...batchv1.JobCondition) *batchv1.Job {
+					return &batchv1.Job{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:         "NS1",
+							Name:              name,
+							CreationTimestamp: metav1.Unix(created, 0),
+							OwnerReferences: []metav1.OwnerReference{{
+								APIVersion: "batch/v1beta1", Kind: "CronJob", Name: cj.Name, UID: cj.UID, Controller: &isController,
+							}},
+						},
+						Status: batchv1.JobStatus{Conditions: conditions},
+					}
+				}
+				Expect(client.Create(ctx, job("CJ1-1", 100, failedCondition))).NotTo(HaveOccurred())
+				Expect(client.Create(ctx, job("CJ1-2", 200, completeCondition))).NotTo(HaveOccurred())
+
+				sm.AddCronJobs([]types.NamespacedName{{Namespace: "NS1", Name: "CJ1"}})
+				sm.syncState()
+				Expect(sm.failing).To(BeEmpty())
+
+				Expect(client.Create(ctx, job("CJ1-3", 300, failedCondition))).NotTo(HaveOccurred())
+				sm.syncState()
+				Expect(sm.failing).To(ConsistOf(
+					`CronJob "NS1/CJ1" failed (BackoffLimitExceeded): Job has reached the specified backoff limit`,
+				))
+			})
+		})
+
+		Context("Rollout watchdog", func() {
+			var clock time.Time
+			var ds *appsv1.DaemonSet
+
+			BeforeEach(func() {
+				clock = time.Now()
+				now = func() time.Time { return clock }
+				SetRolloutWatchdog(&RolloutWatchdog{StallTimeout: time.Minute, PauseOnStall: true})
+
+				ds = &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "NS1", Name: "DS1"},
+					Spec:       appsv1.DaemonSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "DS1"}}},
+					Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 1, NumberAvailable: 1},
+				}
+				Expect(client.Create(ctx, ds)).NotTo(HaveOccurred())
+				Expect(client.Create(ctx, &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "NS1", Name: "DS1-a", Labels: map[string]string{"k8s-app": "DS1"}},
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{{
+							Name:  "c",
+							State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+						}},
+					},
+				})).NotTo(HaveOccurred())
+				Expect(client.Create(ctx, &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "NS1", Name: "DS1-b", Labels: map[string]string{"k8s-app": "DS1"}},
+					Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+				})).NotTo(HaveOccurred())
+				sm.AddDaemonsets([]types.NamespacedName{{Namespace: "NS1", Name: "DS1"}})
+			})
+
+			AfterEach(func() {
+				SetRolloutWatchdog(nil)
+				now = time.Now
+			})
+
+			It("should report the rollouts which don't progress and pause the changes", func() {
+				sm.syncState()
+				Expect(sm.failing).To(BeEmpty())
+				Expect(sm.IsRolloutPaused()).To(BeFalse())
+
+				clock = clock.Add(2 * time.Minute)
+				sm.syncState()
+				Expect(sm.failing).To(ConsistOf(`DaemonSet "NS1/DS1" rollout has not progressed for 1m0s, pods not ready: DS1-a (ContainerCreating)`))
+				Expect(sm.degradedReason()).To(Equal("Some rollouts are stalled"))
+				Expect(sm.IsRolloutPaused()).To(BeTrue())
+
+				// The acknowledgment resumes the changes, and gives the rollout another stall timeout.
+				SetRolloutWatchdog(&RolloutWatchdog{StallTimeout: time.Minute, PauseOnStall: true, AcknowledgedAt: clock})
+				Expect(sm.IsRolloutPaused()).To(BeFalse())
+				sm.syncState()
+				Expect(sm.failing).To(BeEmpty())
+
+				clock = clock.Add(2 * time.Minute)
+				sm.syncState()
+				Expect(sm.failing).To(HaveLen(1))
+
+				// The rollout progresses.
+				ds.Status.UpdatedNumberScheduled = 2
+				ds.Status.NumberUnavailable = 1
+				Expect(client.Update(ctx, ds)).NotTo(HaveOccurred())
+				sm.syncState()
+				Expect(sm.failing).To(BeEmpty())
+				Expect(sm.IsRolloutPaused()).To(BeFalse())
+			})
+
+			It("should not pause the changes unless configured to", func() {
+				SetRolloutWatchdog(&RolloutWatchdog{StallTimeout: time.Minute})
+				sm.syncState()
+				clock = clock.Add(2 * time.Minute)
+				sm.syncState()
+				Expect(sm.failing).To(HaveLen(1))
+				Expect(sm.IsRolloutPaused()).To(BeFalse())
+			})
+		})
+
+		Context("Pull secrets status", func() {
+			pullSecretsCondition := func() operator.TigeraStatusCondition {
+				ts := &operator.TigeraStatus{}
+				Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+				for _, c := range ts.Status.Conditions {
+					if c.Type == operator.ComponentPullSecretsSynced {
+						return c
+					}
+				}
+				return operator.TigeraStatusCondition{}
+			}
+
+			It("should report the last propagation of the pull secrets", func() {
+				sm.setAvailable("All objects available", "")
+				sm.setPullSecretsSynced()
+				Expect(pullSecretsCondition().Type).To(BeEmpty())
+
+				sm.SetPullSecretsStatus([]string{"tigera-manager"}, []string{"tigera-manager/backoff"}, nil)
+				sm.setPullSecretsSynced()
+				cond := pullSecretsCondition()
+				Expect(cond.Status).To(Equal(operator.ConditionTrue))
+				Expect(cond.Message).To(Equal("Updated the copies in the namespaces tigera-manager\nRestarted the pods failing to pull their images tigera-manager/backoff"))
+
+				sm.SetPullSecretsStatus(nil, nil, fmt.Errorf("forbidden"))
+				sm.setPullSecretsSynced()
+				cond = pullSecretsCondition()
+				Expect(cond.Status).To(Equal(operator.ConditionFalse))
+				Expect(cond.Reason).To(Equal("Failed to propagate the pull secrets"))
+				Expect(cond.Message).To(Equal("forbidden"))
+
+				// The last update is kept while the copies are up to date.
+				sm.SetPullSecretsStatus(nil, nil, nil)
+				sm.setPullSecretsSynced()
+				cond = pullSecretsCondition()
+				Expect(cond.Status).To(Equal(operator.ConditionTrue))
+				Expect(cond.Message).To(ContainSubstring("tigera-manager/backoff"))
+			})
+		})
+
+		Context("Encryption status", func() {
+			getStatus := func() *operator.TigeraStatus {
+				ts := &operator.TigeraStatus{}
+				Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+				return ts
+			}
+			encryptedCondition := func(ts *operator.TigeraStatus) operator.TigeraStatusCondition {
+				for _, c := range ts.Status.Conditions {
+					if c.Type == operator.ComponentEncrypted {
+						return c
+					}
+				}
+				return operator.TigeraStatusCondition{}
+			}
+
+			It("should not report the encryption unless it was given", func() {
+				sm.setAvailable("All objects available", "")
+				sm.setEncrypted()
+				ts := getStatus()
+				Expect(encryptedCondition(ts).Type).To(BeEmpty())
+				Expect(ts.Status.Encryption).To(BeNil())
+			})
+
+			It("should report the nodes whose traffic is not encrypted", func() {
+				st := &operator.EncryptionStatus{
+					Type:             operator.EncryptionWireGuard,
+					EncryptedNodes:   1,
+					UnencryptedNodes: 2,
+					Nodes: []operator.NodeEncryptionStatus{
+						{Name: "n2", EstablishedPeers: 1, UnencryptedPeers: 1},
+						{Name: "n3", Error: "calico-node is not running"},
+					},
+				}
+				sm.SetEncryptionStatus(st)
+				sm.setEncrypted()
+				ts := getStatus()
+				Expect(ts.Status.Encryption).To(Equal(st))
+				cond := encryptedCondition(ts)
+				Expect(cond.Status).To(Equal(operator.ConditionFalse))
+				Expect(cond.Message).To(Equal("2 of 3 nodes don't encrypt all their traffic\nn2: 1 of 2 peers unencrypted\nn3: calico-node is not running"))
+
+				st = &operator.EncryptionStatus{Type: operator.EncryptionWireGuard, EncryptedNodes: 2}
+				sm.SetEncryptionStatus(st)
+				sm.setEncrypted()
+				cond = encryptedCondition(getStatus())
+				Expect(cond.Status).To(Equal(operator.ConditionTrue))
+				Expect(cond.Reason).To(Equal("All traffic is encrypted with WireGuard"))
+
+				sm.SetEncryptionStatus(nil)
+				sm.setEncrypted()
+				ts = getStatus()
+				Expect(ts.Status.Encryption).To(BeNil())
+				Expect(encryptedCondition(ts).Status).To(Equal(operator.ConditionFalse))
+			})
+
+			It("should only list the first nodes whose traffic is not encrypted in the condition", func() {
+				st := &operator.EncryptionStatus{Type: operator.EncryptionIPsec, UnencryptedNodes: 150}
+				for i := 0; i < 100; i++ {
+					st.Nodes = append(st.Nodes, operator.NodeEncryptionStatus{Name: fmt.Sprintf("n%03d", i), UnencryptedPeers: 1})
+				}
+				sm.SetEncryptionStatus(st)
+				sm.setEncrypted()
+				msgs := strings.Split(encryptedCondition(getStatus()).Message, "\n")
+				Expect(msgs).To(HaveLen(12))
+				Expect(msgs[0]).To(Equal("150 of 150 nodes don't encrypt all their traffic"))
+				Expect(msgs[11]).To(Equal("and 140 more"))
+			})
+		})
 	})
 })
@@ -0,0 +1,248 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/controller/options"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/dns"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const controllerName = "tenant-controller"
+
+var log = logf.Log.WithName(controllerName)
+
+// Add creates a new Tenant Controller and adds it to the Manager. The controller renders a Voltron instance in the
+// namespace of every Tenant of a management cluster. This controller is meant only for enterprise users.
+func Add(mgr manager.Manager, opts options.AddOptions) error {
+	if !opts.EnterpriseCRDExists {
+		// No need to start this controller.
+		return nil
+	}
+
+	reconciler := newReconciler(mgr, opts)
+
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: utils.FairReconciler(controllerName, reconciler)})
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", controllerName, err)
+	}
+
+	return add(c)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, opts options.AddOptions) reconcile.Reconciler {
+	r := &ReconcileTenant{
+		client:        mgr.GetClient(),
+		scheme:        mgr.GetScheme(),
+		status:        status.New(mgr.GetClient(), "tenant", opts.KubernetesVersion),
+		clusterDomain: opts.ClusterDomain,
+	}
+	r.status.Run(opts.ShutdownContext)
+	return r
+}
+
+// add adds watches for resources that are available at startup
+func add(c controller.Controller) error {
+	err := c.Watch(&source.Kind{Type: &operatorv1.Tenant{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("%s failed to watch primary resource: %w", controllerName, err)
+	}
+
+	err = c.Watch(&source.Kind{Type: &operatorv1.ManagementCluster{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("%s failed to watch ManagementCluster resource: %w", controllerName, err)
+	}
+
+	if err = utils.AddNetworkWatch(c); err != nil {
+		return fmt.Errorf("%s failed to watch Network resource: %w", controllerName, err)
+	}
+
+	// The secrets of the tenants live in the tenant namespaces, which are not known upfront.
+	isTenantSecret := func(obj client.Object) bool {
+		return obj.GetName() == render.VoltronTunnelSecretName || obj.GetName() == render.ManagerTLSSecretName
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForObject{}, &predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isTenantSecret(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isTenantSecret(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isTenantSecret(e.Object)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%s failed to watch Secret resources: %w", controllerName, err)
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileTenant implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileTenant{}
+
+// ReconcileTenant reconciles the Tenant objects of a management cluster.
+type ReconcileTenant struct {
+	client        client.Client
+	scheme        *runtime.Scheme
+	status        status.StatusManager
+	clusterDomain string
+}
+
+// Reconcile renders the Voltron instance of every Tenant along with the cluster-wide permissions shared by all of them.
+// Since the permissions of all tenants are bound at once, all Tenants are reconciled together and the request itself is
+// ignored.
+func (r *ReconcileTenant) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling tenants")
+
+	tenants := &operatorv1.TenantList{}
+	if err := r.client.List(ctx, tenants); err != nil {
+		r.status.SetDegraded("Error listing Tenants", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	managementCluster, err := utils.GetManagementCluster(ctx, r.client)
+	if err != nil {
+		reqLogger.Error(err, "Error reading ManagementCluster")
+		r.status.SetDegraded("Error reading ManagementCluster", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	if len(tenants.Items) == 0 {
+		r.status.OnCRNotFound()
+		if managementCluster != nil {
+			// Revoke the permissions of the last deleted tenant.
+			hdler := utils.NewComponentHandler(log, r.client, r.scheme, managementCluster)
+			if err := hdler.CreateOrUpdateOrDelete(ctx, render.TenantVoltronRBAC(nil), nil); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+	r.status.OnCRFound()
+
+	if managementCluster == nil {
+		r.status.SetDegraded("Tenants are only supported on a management cluster", "")
+		return reconcile.Result{}, nil
+	}
+
+	_, installation, err := utils.GetInstallation(ctx, r.client)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.status.SetDegraded("Installation not found", err.Error())
+			return reconcile.Result{}, err
+		}
+		r.status.SetDegraded("Error querying installation", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	pullSecrets, err := utils.GetNetworkingPullSecrets(installation, r.client)
+	if err != nil {
+		reqLogger.Error(err, "Error retrieving pull secrets")
+		r.status.SetDegraded("Error retrieving pull secrets", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	for i := range tenants.Items {
+		tenant := &tenants.Items[i]
+		if tenant.Name != render.TenantResourceName {
+			r.status.SetDegraded(fmt.Sprintf("Tenant %s/%s must be named %q", tenant.Namespace, tenant.Name, render.TenantResourceName), "")
+			return reconcile.Result{}, nil
+		}
+
+		if err := r.reconcileTenant(ctx, tenant, managementCluster, installation, pullSecrets); err != nil {
+			reqLogger.Error(err, "Error rendering the Voltron instance", "Tenant", tenant.Namespace)
+			r.status.SetDegraded(fmt.Sprintf("Error rendering the Voltron instance of Tenant %s", tenant.Namespace), err.Error())
+			return reconcile.Result{}, err
+		}
+	}
+
+	hdler := utils.NewComponentHandler(log, r.client, r.scheme, managementCluster)
+	if err := hdler.CreateOrUpdateOrDelete(ctx, render.TenantVoltronRBAC(tenants.Items), r.status); err != nil {
+		r.status.SetDegraded("Error creating / updating resource", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	r.status.ClearDegraded()
+	return reconcile.Result{}, nil
+}
+
+// reconcileTenant renders the Voltron instance of the given tenant. The tunnel CA of the tenant is generated on the first
+// reconcile and kept in the namespace of the tenant afterwards.
+func (r *ReconcileTenant) reconcileTenant(ctx context.Context, tenant *operatorv1.Tenant, managementCluster *operatorv1.ManagementCluster, installation *operatorv1.InstallationSpec, pullSecrets []*corev1.Secret) error {
+	tunnelSecret, err := utils.ValidateCertPair(r.client,
+		tenant.Namespace,
+		render.VoltronTunnelSecretName,
+		render.VoltronTunnelSecretKeyName,
+		render.VoltronTunnelSecretCertName,
+	)
+	if err != nil {
+		return err
+	}
+
+	tlsSecret, err := utils.ValidateCertPair(r.client,
+		tenant.Namespace,
+		render.ManagerTLSSecretName,
+		render.ManagerSecretKeyName,
+		render.ManagerSecretCertName,
+	)
+	if err != nil {
+		return err
+	}
+	certDur := 825 * 24 * time.Hour // 825days*24hours: Create cert with a max expiration that macOS 10.15 will accept
+	svcDNSNames := dns.GetServiceDNSNames(render.TenantVoltronServiceName, tenant.Namespace, r.clusterDomain)
+	tlsSecret, _, err = utils.EnsureCertificateSecret(
+		render.ManagerTLSSecretName, tlsSecret, render.ManagerSecretKeyName, render.ManagerSecretCertName, certDur, svcDNSNames...,
+	)
+	if err != nil {
+		return err
+	}
+
+	component := render.TenantVoltron(&render.TenantVoltronConfiguration{
+		Tenant:            tenant,
+		ManagementCluster: managementCluster,
+		TunnelSecret:      tunnelSecret,
+		TLSKeyPair:        tlsSecret,
+		PullSecrets:       pullSecrets,
+		Installation:      installation,
+	})
+
+	hdler := utils.NewComponentHandler(log, r.client, r.scheme, tenant)
+	hdler.SetHardening(installation.Hardening)
+	return hdler.CreateOrUpdateOrDelete(ctx, component, r.status)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/dns"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("Tenant controller tests", func() {
+	var cli client.Client
+	var ctx context.Context
+	var mockStatus *status.MockStatus
+	var r ReconcileTenant
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(appsv1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(rbacv1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+		ctx = context.Background()
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		mockStatus = &status.MockStatus{}
+		mockStatus.On("AddDaemonsets", mock.Anything)
+		mockStatus.On("AddDeployments", mock.Anything)
+		mockStatus.On("AddStatefulSets", mock.Anything)
+		mockStatus.On("AddCronJobs", mock.Anything)
+		mockStatus.On("AddJobs", mock.Anything)
+		mockStatus.On("IsRolloutPaused").Return(false)
+		mockStatus.On("OnCRFound").Return()
+		mockStatus.On("ClearDegraded").Return()
+
+		r = ReconcileTenant{
+			client:        cli,
+			scheme:        scheme,
+			status:        mockStatus,
+			clusterDomain: dns.DefaultClusterDomain,
+		}
+
+		Expect(cli.Create(ctx, &operatorv1.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       operatorv1.InstallationSpec{Variant: operatorv1.TigeraSecureEnterprise},
+		})).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &operatorv1.ManagementCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+		})).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &operatorv1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: render.TenantResourceName, Namespace: "tenant-a"},
+			Spec:       operatorv1.TenantSpec{ID: "a"},
+		})).NotTo(HaveOccurred())
+	})
+
+	getTunnelSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.VoltronTunnelSecretName, Namespace: "tenant-a"}, secret)).NotTo(HaveOccurred())
+		return secret
+	}
+
+	It("should render the Voltron instance of a tenant with its own tunnel CA", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertExpectations(GinkgoT())
+
+		deployment := &appsv1.Deployment{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.VoltronName, Namespace: "tenant-a"}, deployment)).NotTo(HaveOccurred())
+		Expect(deployment.OwnerReferences).To(HaveLen(1))
+		Expect(deployment.OwnerReferences[0].Kind).To(Equal("Tenant"))
+
+		tunnelSecret := getTunnelSecret()
+		Expect(tunnelSecret.Data).To(HaveKey(render.VoltronTunnelSecretCertName))
+
+		binding := &rbacv1.ClusterRoleBinding{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.TenantVoltronClusterRoleBindingName}, binding)).NotTo(HaveOccurred())
+		Expect(binding.Subjects).To(ConsistOf(rbacv1.Subject{Kind: "ServiceAccount", Name: render.TenantVoltronServiceAccountName, Namespace: "tenant-a"}))
+
+		// The tunnel CA of the tenant is kept across reconciles.
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getTunnelSecret().Data).To(Equal(tunnelSecret.Data))
+	})
+
+	It("should degrade when the cluster is not a management cluster", func() {
+		Expect(cli.Delete(ctx, &operatorv1.ManagementCluster{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}})).NotTo(HaveOccurred())
+		mockStatus.On("SetDegraded", "Tenants are only supported on a management cluster", "").Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Tenants are only supported on a management cluster", "")
+
+		Expect(cli.Get(ctx, client.ObjectKey{Name: render.VoltronName, Namespace: "tenant-a"}, &appsv1.Deployment{})).To(HaveOccurred())
+	})
+})
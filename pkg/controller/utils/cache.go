@@ -0,0 +1,178 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tigera/operator/pkg/common"
+)
+
+// NewCacheFunc returns the function creating the cache of the manager. Secrets and ConfigMaps are only cached when they
+// were rendered by the operator, i.e. they carry the common.OperatorManagedLabel, so that the unrelated secrets and
+// configmaps of the cluster are not held in memory nor trigger reconciles. The given namespaces hold secrets and
+// configmaps that are not rendered by the operator but are watched by it, e.g. the ones provided by the user in the
+// operator namespace, so all of their secrets and configmaps are cached.
+//
+// Pods are not scoped, since the status of some components is read from pods the operator doesn't render, e.g. the
+// ones of Prometheus and Alertmanager, which are created by the prometheus-operator.
+//
+// Reading a Secret or ConfigMap that is not cached falls back to reading it from the API server. Listing them also reads
+// them from the API server, unless only the ones with the common.OperatorManagedLabel are listed.
+func NewCacheFunc(namespaces ...string) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		managedSelector := labels.SelectorFromSet(labels.Set{common.OperatorManagedLabel: "true"})
+		managedOpts := opts
+		managedOpts.SelectorsByObject = cache.SelectorsByObject{
+			&corev1.Secret{}:    {Label: managedSelector},
+			&corev1.ConfigMap{}: {Label: managedSelector},
+		}
+		managed, err := cache.New(config, managedOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		namespaceCaches := map[string]cache.Cache{}
+		for _, ns := range namespaces {
+			nsOpts := opts
+			nsOpts.Namespace = ns
+			if namespaceCaches[ns], err = cache.New(config, nsOpts); err != nil {
+				return nil, err
+			}
+		}
+
+		reader, err := client.New(config, client.Options{Scheme: opts.Scheme, Mapper: opts.Mapper})
+		if err != nil {
+			return nil, err
+		}
+
+		return &scopedCache{Cache: managed, namespaceCaches: namespaceCaches, reader: reader}, nil
+	}
+}
+
+// scopedCache routes the Secrets and ConfigMaps of the namespaces given to NewCacheFunc to a cache of the namespace.
+// Every other object is served by the label scoped cache.
+type scopedCache struct {
+	cache.Cache
+	namespaceCaches map[string]cache.Cache
+	reader          client.Reader
+}
+
+// isScoped returns whether only some of the objects of the type of the given object are cached.
+func isScoped(obj runtime.Object) bool {
+	switch obj.(type) {
+	case *corev1.Secret, *corev1.ConfigMap, *corev1.SecretList, *corev1.ConfigMapList:
+		return true
+	}
+	return false
+}
+
+func (c *scopedCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if !isScoped(obj) {
+		return c.Cache.Get(ctx, key, obj)
+	}
+	if nsCache, ok := c.namespaceCaches[key.Namespace]; ok {
+		return nsCache.Get(ctx, key, obj)
+	}
+	err := c.Cache.Get(ctx, key, obj)
+	if kerrors.IsNotFound(err) {
+		// The object may exist without the operator managed label.
+		return c.reader.Get(ctx, key, obj)
+	}
+	return err
+}
+
+func (c *scopedCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if !isScoped(list) {
+		return c.Cache.List(ctx, list, opts...)
+	}
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if nsCache, ok := c.namespaceCaches[listOpts.Namespace]; ok {
+		return nsCache.List(ctx, list, opts...)
+	}
+	if listOpts.LabelSelector != nil {
+		if managed, ok := listOpts.LabelSelector.RequiresExactMatch(common.OperatorManagedLabel); ok && managed == "true" {
+			// Only the operator managed objects are listed, which are all cached.
+			return c.Cache.List(ctx, list, opts...)
+		}
+	}
+	// The label scoped cache may miss some of the objects.
+	return c.reader.List(ctx, list, opts...)
+}
+
+// GetInformer returns the informer of the namespace of the given object if it was given to NewCacheFunc. This is the
+// case for the watches added with AddNamespacedWatch, which set the namespace of the watched object.
+func (c *scopedCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	if isScoped(obj) {
+		if nsCache, ok := c.namespaceCaches[obj.GetNamespace()]; ok {
+			return nsCache.GetInformer(ctx, obj)
+		}
+	}
+	return c.Cache.GetInformer(ctx, obj)
+}
+
+func (c *scopedCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	if isScoped(obj) {
+		for _, nsCache := range c.namespaceCaches {
+			if err := nsCache.IndexField(ctx, obj, field, extractValue); err != nil {
+				return err
+			}
+		}
+	}
+	return c.Cache.IndexField(ctx, obj, field, extractValue)
+}
+
+func (c *scopedCache) Start(ctx context.Context) error {
+	errs := make(chan error, len(c.namespaceCaches)+1)
+	for ns, nsCache := range c.namespaceCaches {
+		go func(ns string, nsCache cache.Cache) {
+			if err := nsCache.Start(ctx); err != nil {
+				errs <- fmt.Errorf("failed to start the cache of namespace %s: %w", ns, err)
+			}
+		}(ns, nsCache)
+	}
+
+	go func() {
+		if err := c.Cache.Start(ctx); err != nil {
+			errs <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errs:
+		return err
+	}
+}
+
+func (c *scopedCache) WaitForCacheSync(ctx context.Context) bool {
+	for _, nsCache := range c.namespaceCaches {
+		if !nsCache.WaitForCacheSync(ctx) {
+			return false
+		}
+	}
+	return c.Cache.WaitForCacheSync(ctx)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/tigera/operator/pkg/common"
+)
+
+// fakeCache serves the reads of a cache from a client.
+type fakeCache struct {
+	cache.Cache
+	client client.Client
+}
+
+func (c *fakeCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	return c.client.Get(ctx, key, obj)
+}
+
+func (c *fakeCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return c.client.List(ctx, list, opts...)
+}
+
+var _ = Describe("Scoped cache", func() {
+	var c *scopedCache
+	var ctx context.Context
+
+	secret := func(name string, labels map[string]string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "calico-system", Labels: labels}}
+	}
+	names := func(list *corev1.SecretList) []string {
+		var n []string
+		for _, s := range list.Items {
+			n = append(n, s.Name)
+		}
+		return n
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		managed := map[string]string{common.OperatorManagedLabel: "true"}
+		// The cache only holds the managed secrets, and the API server holds all of them. The managed secret missing
+		// from the API server tells which of the two served a list.
+		c = &scopedCache{
+			Cache: &fakeCache{client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				secret("managed", managed),
+				secret("cached-only", managed),
+			).Build()},
+			namespaceCaches: map[string]cache.Cache{},
+			reader: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				secret("managed", managed),
+				secret("unmanaged", nil),
+			).Build(),
+		}
+	})
+
+	It("lists the operator managed secrets from the cache", func() {
+		list := &corev1.SecretList{}
+		Expect(c.List(ctx, list, client.MatchingLabels{common.OperatorManagedLabel: "true"})).NotTo(HaveOccurred())
+		Expect(names(list)).To(ConsistOf("managed", "cached-only"))
+	})
+
+	It("lists the other secrets from the API server", func() {
+		list := &corev1.SecretList{}
+		Expect(c.List(ctx, list, client.InNamespace("calico-system"))).NotTo(HaveOccurred())
+		Expect(names(list)).To(ConsistOf("managed", "unmanaged"))
+	})
+})
@@ -15,6 +15,7 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
 	"encoding/pem"
@@ -23,12 +24,16 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/openshift/library-go/pkg/crypto"
+
 	"github.com/tigera/operator/pkg/common"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
 	rsecret "github.com/tigera/operator/pkg/render/common/secret"
+	"github.com/tigera/operator/pkg/tls"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -101,6 +106,103 @@ func EnsureCertificateSecret(secretName string, secret *corev1.Secret, keyName s
 	return secret, operatorManaged, err
 }
 
+// The CA the operator signs certificates with when a component requests it, e.g. the APIServer with the OperatorCA
+// certificate issuer.
+const (
+	OperatorCASecretName = "tigera-operator-ca"
+	OperatorCAKeyName    = "tls.key"
+	OperatorCACertName   = "tls.crt"
+)
+
+// GetOrCreateOperatorCA returns the CA stored in the OperatorCASecretName Secret of the operator namespace along with
+// that Secret. If the Secret does not exist, a new CA is generated and the returned Secret must be created by the caller.
+func GetOrCreateOperatorCA(ctx context.Context, cli client.Client) (*crypto.CA, *corev1.Secret, error) {
+	secret, err := GetSecret(ctx, cli, OperatorCASecretName, common.OperatorNamespace())
+	if err != nil {
+		return nil, nil, err
+	}
+	if secret != nil {
+		ca, err := crypto.GetCAFromBytes(secret.Data[OperatorCACertName], secret.Data[OperatorCAKeyName])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load the CA from secret %q: %w", OperatorCASecretName, err)
+		}
+		return ca, secret, nil
+	}
+
+	certsLogger.Info(fmt.Sprintf("CA %q doesn't exist, creating it", OperatorCASecretName))
+	ca, err := tls.MakeCA(rmeta.DefaultOperatorCASignerName())
+	if err != nil {
+		return nil, nil, err
+	}
+	crtContent := &bytes.Buffer{}
+	keyContent := &bytes.Buffer{}
+	if err := ca.Config.WriteCertConfig(crtContent, keyContent); err != nil {
+		return nil, nil, err
+	}
+	secret = &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      OperatorCASecretName,
+			Namespace: common.OperatorNamespace(),
+		},
+		Data: map[string][]byte{
+			OperatorCACertName: crtContent.Bytes(),
+			OperatorCAKeyName:  keyContent.Bytes(),
+		},
+	}
+	return ca, secret, nil
+}
+
+// EnsureCertificateSignedByCA ensures that the certificate in the secret is signed by the given CA and has the expected
+// DNS names. If no secret is provided, or the operator-managed certificate in it is not signed by the CA or has the
+// wrong DNS names, a new secret is created with the given certificate extensions. As with EnsureCertificateSecret,
+// user-supplied secrets are returned as is and the second returned value reports whether the secret is managed by the
+// operator.
+func EnsureCertificateSignedByCA(ca *crypto.CA, secretName string, secret *corev1.Secret, keyName string, certName string, certDuration time.Duration, cef []crypto.CertificateExtensionFunc, svcDNSNames ...string) (*corev1.Secret, bool, error) {
+	if secret != nil {
+		operatorManaged, err := IsCertOperatorIssued(secret.Data[certName])
+		if err != nil {
+			return nil, false, err
+		}
+		if !operatorManaged {
+			return secret, false, nil
+		}
+
+		signed, err := isSignedBy(secret.Data[certName], ca)
+		if err != nil {
+			return nil, false, err
+		}
+		err = SecretHasExpectedDNSNames(secret, certName, svcDNSNames)
+		if err != nil && err != ErrInvalidCertDNSNames {
+			return nil, false, err
+		}
+		if signed && err == nil {
+			return secret, true, nil
+		}
+	}
+
+	certsLogger.Info(fmt.Sprintf("cert %q is missing or not signed by the operator CA, creating it", secretName))
+	secret, err := rsecret.CreateTLSSecret(ca,
+		secretName, common.OperatorNamespace(), keyName, certName,
+		certDuration, cef, svcDNSNames...,
+	)
+	return secret, true, err
+}
+
+// isSignedBy returns whether the PEM encoded certificate is signed by the given CA.
+func isSignedBy(certPem []byte, ca *crypto.CA) (bool, error) {
+	cert, err := parseCertificate(certPem)
+	if err != nil {
+		return false, err
+	}
+	for _, caCert := range ca.Config.Certs {
+		if cert.CheckSignatureFrom(caCert) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // IsOperatorIssued checks if the cert secret is issued operator.
 func IsOperatorIssued(issuer string) bool {
 	return operatorIssuedCertRegexp.MatchString(issuer)
@@ -128,16 +230,24 @@ func GetCertificateIssuer(certPem []byte) (string, error) {
 
 }
 
+// parseCertificate parses the first certificate of the PEM data. Certificate chains are supported, in which case the
+// first certificate is the leaf one, and blocks other than certificates are skipped.
 func parseCertificate(certBytes []byte) (*x509.Certificate, error) {
-	pemBlock, _ := pem.Decode(certBytes)
-	if pemBlock == nil {
-		return nil, ErrInvalidCertNoPEMData
-	}
-	cert, err := x509.ParseCertificate(pemBlock.Bytes)
-	if err != nil {
-		return nil, err
+	for rest := certBytes; ; {
+		var pemBlock *pem.Block
+		pemBlock, rest = pem.Decode(rest)
+		if pemBlock == nil {
+			return nil, ErrInvalidCertNoPEMData
+		}
+		if pemBlock.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(pemBlock.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert, nil
 	}
-	return cert, nil
 }
 
 // SecretHasExpectedDNSNames Check that the cert in the secret has the expected DNS names.
@@ -147,7 +257,11 @@ func SecretHasExpectedDNSNames(secret *corev1.Secret, certKeyName string, expect
 		return err
 	}
 
+	// The IP addresses of the certificate are given along with its DNS names.
 	dnsNames := sets.NewString(cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		dnsNames.Insert(ip.String())
+	}
 	if dnsNames.HasAll(expectedDNSNames...) {
 		return nil
 	}
@@ -0,0 +1,111 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/compliance"
+)
+
+const (
+	// ComplianceReportConfigMapPrefix prefixes the name of the ConfigMaps of the operator namespace holding the
+	// compliance reports of the components.
+	ComplianceReportConfigMapPrefix = "tigera-compliance-"
+
+	// ComplianceReportLabel labels the compliance report ConfigMaps, for the security review automation to list them.
+	ComplianceReportLabel = "operator.tigera.io/compliance-report"
+
+	// ComplianceFindingsKey is the key of the compliance report ConfigMaps holding the findings, as a JSON list.
+	ComplianceFindingsKey = "findings.json"
+)
+
+// ComplianceReporter reports the findings of the compliance checklist for the objects rendered by the components.
+type ComplianceReporter interface {
+	// Report reports the findings of the given component, which are empty when it passes the checklist.
+	Report(ctx context.Context, component string, findings []compliance.Finding) error
+}
+
+var (
+	complianceReporterLock sync.RWMutex
+	complianceReporter     ComplianceReporter
+)
+
+// SetComplianceReporter makes the component handlers evaluate the objects they render against the compliance
+// checklist, and hand the findings to the given reporter. A nil reporter switches the evaluation off.
+func SetComplianceReporter(r ComplianceReporter) {
+	complianceReporterLock.Lock()
+	defer complianceReporterLock.Unlock()
+	complianceReporter = r
+}
+
+func getComplianceReporter() ComplianceReporter {
+	complianceReporterLock.RLock()
+	defer complianceReporterLock.RUnlock()
+	return complianceReporter
+}
+
+// NewConfigMapComplianceReporter returns a ComplianceReporter writing the findings of each component in a ConfigMap of
+// the operator namespace, named after the component.
+func NewConfigMapComplianceReporter(cli client.Client) ComplianceReporter {
+	return &configMapComplianceReporter{client: cli}
+}
+
+type configMapComplianceReporter struct {
+	client client.Client
+}
+
+func (r *configMapComplianceReporter) Report(ctx context.Context, component string, findings []compliance.Finding) error {
+	if findings == nil {
+		findings = []compliance.Finding{}
+	}
+	b, err := json.Marshal(findings)
+	if err != nil {
+		return err
+	}
+
+	cm := &v1.ConfigMap{}
+	key := types.NamespacedName{Name: ComplianceReportConfigMapPrefix + component, Namespace: common.OperatorNamespace()}
+	err = r.client.Get(ctx, key, cm)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	data := map[string]string{ComplianceFindingsKey: string(b)}
+	if apierrors.IsNotFound(err) {
+		return r.client.Create(ctx, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    withOperatorManagedLabel(map[string]string{ComplianceReportLabel: "true"}),
+			},
+			Data: data,
+		})
+	}
+	if reflect.DeepEqual(cm.Data, data) {
+		return nil
+	}
+	cm.Data = data
+	return r.client.Update(ctx, cm)
+}
@@ -32,12 +32,18 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/diff"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/compliance"
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/render"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
@@ -45,6 +51,10 @@ import (
 
 type ComponentHandler interface {
 	CreateOrUpdateOrDelete(context.Context, render.Component, status.StatusManager) error
+
+	// SetHardening hardens the containers of the components other than the dataplane when the given hardening setting
+	// of the Installation is enabled.
+	SetHardening(*operatorv1.HardeningType)
 }
 
 // cr is allowed to be nil in the case we don't want to put ownership on a resource,
@@ -55,6 +65,7 @@ func NewComponentHandler(log logr.Logger, client client.Client, scheme *runtime.
 		scheme: scheme,
 		cr:     cr,
 		log:    log,
+		names:  map[string][]render.Component{},
 	}
 }
 
@@ -63,6 +74,17 @@ type componentHandler struct {
 	scheme *runtime.Scheme
 	cr     metav1.Object
 	log    logr.Logger
+
+	// hardened is true when the containers of the components other than the dataplane are hardened.
+	hardened bool
+
+	// names holds the components handled per name, to tell apart the manifests and compliance reports of the
+	// components of the same type.
+	names map[string][]render.Component
+}
+
+func (c *componentHandler) SetHardening(hardening *operatorv1.HardeningType) {
+	c.hardened = hardening != nil && *hardening == operatorv1.HardeningEnabled
 }
 
 func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component render.Component, status status.StatusManager) error {
@@ -74,6 +96,11 @@ func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 		cmpLog.Info("Component is not ready, skipping")
 		return nil
 	}
+	// The changes are paused while a rollout of the component is stalled, until the stall is acknowledged. The
+	// error requeues the reconcile, so that the changes resume once the rollout progresses or is acknowledged.
+	if status != nil && status.IsRolloutPaused() {
+		return fmt.Errorf("the changes of the component are paused until its stalled rollout is acknowledged with spec.rolloutWatchdog.stallsAcknowledgedAt of the Installation")
+	}
 	cmpLog.V(2).Info("Reconciling")
 
 	// Iterate through each object that comprises the component and attempt to create it,
@@ -82,10 +109,16 @@ func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 	var deployments []types.NamespacedName
 	var statefulsets []types.NamespacedName
 	var cronJobs []types.NamespacedName
+	var jobs []types.NamespacedName
 
 	objsToCreate, objsToDelete := component.Objects()
 	osType := component.SupportedOSType()
-
+	exporter := getManifestExporter()
+	reporter := getComplianceReporter()
+	var name string
+	if exporter != nil || reporter != nil {
+		name = c.componentName(component, objsToCreate, objsToDelete)
+	}
 	for _, obj := range objsToCreate {
 		om, ok := obj.(metav1.ObjectMetaAccessor)
 		if !ok {
@@ -104,6 +137,20 @@ func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 		// system as specified by the osType.
 		ensureOSSchedulingRestrictions(obj, osType)
 
+		// Harden the containers of the components other than the dataplane when the Installation enables it.
+		if c.hardened {
+			hardenObject(obj)
+		}
+
+		// Label the secrets and configmaps so that they are cached by the operator, and the pods so that they can be told
+		// apart from the ones the operator doesn't render.
+		setOperatorManagedLabel(obj)
+
+		// Let the registered mutators change the object, e.g. to inject a sidecar.
+		if err := render.MutateObject(obj); err != nil {
+			return err
+		}
+
 		// Keep track of some objects so we can report on their status.
 		switch obj.(type) {
 		case *apps.Deployment:
@@ -114,6 +161,13 @@ func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 			statefulsets = append(statefulsets, key)
 		case *batchv1beta.CronJob:
 			cronJobs = append(cronJobs, key)
+		case *batchv1.Job:
+			jobs = append(jobs, key)
+		}
+
+		// In the manifests only mode, the objects are exported instead of being applied.
+		if exporter != nil {
+			continue
 		}
 
 		cur, ok := obj.DeepCopyObject().(client.Object)
@@ -147,6 +201,7 @@ func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 
 		// if mergeState returns nil we don't want to update the object
 		if mobj := mergeState(obj, cur); mobj != nil {
+			logDiff(logCtx, cur, mobj)
 			switch obj.(type) {
 			case *batchv1.Job:
 				// Jobs can't be updated, they can't only be deleted then created
@@ -173,6 +228,39 @@ func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 		status.AddDeployments(deployments)
 		status.AddStatefulSets(statefulsets)
 		status.AddCronJobs(cronJobs)
+		status.AddJobs(jobs)
+	}
+
+	// The objects are evaluated against the compliance checklist as they are applied or exported, i.e. hardened and
+	// mutated.
+	if reporter != nil {
+		findings := compliance.Evaluate(objsToCreate)
+		if err := reporter.Report(ctx, name, findings); err != nil {
+			cmpLog.Error(err, "Failed to report the compliance findings")
+			return err
+		}
+		cmpLog.V(1).Info("Reported the compliance findings of the component", "name", name, "findings", len(findings))
+	}
+
+	if exporter != nil {
+		// The workloads are still monitored, since the GitOps pipeline applies them.
+		toCreate, err := marshalManifests(objsToCreate)
+		if err != nil {
+			return err
+		}
+		toDelete, err := marshalManifests(objsToDelete)
+		if err != nil {
+			return err
+		}
+		if err := exporter.Export(ctx, name, toCreate, toDelete); err != nil {
+			cmpLog.Error(err, "Failed to export the manifests")
+			return err
+		}
+		cmpLog.V(1).Info("Exported the manifests of the component", "name", name)
+		if status != nil {
+			status.ReadyToMonitor()
+		}
+		return nil
 	}
 
 	for _, obj := range objsToDelete {
@@ -194,6 +282,8 @@ func (c componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 				status.RemoveStatefulSets(key)
 			case *batchv1beta.CronJob:
 				status.RemoveCronJobs(key)
+			case *batchv1.Job:
+				status.RemoveJobs(key)
 			}
 		}
 	}
@@ -301,12 +391,115 @@ func mergeState(desired client.Object, current runtime.Object) client.Object {
 		dsa.Spec.ElasticsearchRef = csa.Spec.ElasticsearchRef
 		dsa.Status = csa.Status
 		return dsa
+	case *crdv1.KubeControllersConfiguration:
+		// kube-controllers reports its running configuration in the status, which is not a subresource of the
+		// CRD. Only update if the spec has changed, and keep the status.
+		cc := current.(*crdv1.KubeControllersConfiguration)
+		dc := desired.(*crdv1.KubeControllersConfiguration)
+		if reflect.DeepEqual(cc.Spec, dc.Spec) {
+			return nil
+		}
+		dc.Status = cc.Status
+		return dc
 	default:
 		// Default to just using the desired state, with an updated RV.
 		return desired
 	}
 }
 
+// diffLogLevel is the log level at which the changes the handler makes to existing objects are logged.
+const diffLogLevel = 5
+
+// logDiff logs the differences between the current and the desired state of an object that is about to be updated. It
+// helps to understand why the operator keeps updating an object, e.g. because another actor reverts the changes. Since
+// computing the differences is expensive, it is only done when the diffLogLevel is enabled.
+func logDiff(logCtx logr.Logger, current, desired client.Object) {
+	debugLog := logCtx.V(diffLogLevel)
+	if !debugLog.Enabled() {
+		return
+	}
+	debugLog.Info("Updating object", "diff", objectDiff(current, desired))
+}
+
+// objectDiff returns the differences between the current and the desired state of an object. The metadata fields set
+// by the API server and the status of the current object are not part of the desired state and are left out. The data
+// of Secrets is redacted, so only the keys added, removed or changed are reported.
+func objectDiff(current, desired client.Object) string {
+	cur := current.DeepCopyObject().(client.Object)
+	if s, ok := desired.(*v1.Secret); ok {
+		desired = s.DeepCopy()
+		redactSecretData(cur.(*v1.Secret), desired.(*v1.Secret))
+	}
+	cur.SetManagedFields(nil)
+	cur.SetGeneration(desired.GetGeneration())
+	cur.SetSelfLink(desired.GetSelfLink())
+	if u, ok := cur.(*unstructured.Unstructured); ok {
+		unstructured.RemoveNestedField(u.Object, "status")
+	} else if v := reflect.ValueOf(cur).Elem().FieldByName("Status"); v.IsValid() && v.CanSet() {
+		v.Set(reflect.ValueOf(desired).Elem().FieldByName("Status"))
+	}
+	return diff.ObjectReflectDiff(cur, desired)
+}
+
+const (
+	redactedValue        = "<redacted>"
+	redactedChangedValue = "<redacted, changed>"
+)
+
+// redactSecretData replaces the values of the data of the current and the desired Secret with placeholders, which
+// only tell whether the value of a key changes.
+func redactSecretData(current, desired *v1.Secret) {
+	for k, v := range desired.Data {
+		if cv, ok := current.Data[k]; ok && string(cv) != string(v) {
+			desired.Data[k] = []byte(redactedChangedValue)
+		} else {
+			desired.Data[k] = []byte(redactedValue)
+		}
+	}
+	for k, v := range desired.StringData {
+		if cv, ok := current.StringData[k]; ok && cv != v {
+			desired.StringData[k] = redactedChangedValue
+		} else {
+			desired.StringData[k] = redactedValue
+		}
+	}
+	for k := range current.Data {
+		current.Data[k] = []byte(redactedValue)
+	}
+	for k := range current.StringData {
+		current.StringData[k] = redactedValue
+	}
+}
+
+// setOperatorManagedLabel sets the common.OperatorManagedLabel on the given object if it is a Secret or a ConfigMap, or
+// on its pod template if it is a workload.
+func setOperatorManagedLabel(obj client.Object) {
+	switch o := obj.(type) {
+	case *v1.Secret, *v1.ConfigMap:
+		obj.SetLabels(withOperatorManagedLabel(obj.GetLabels()))
+	case *apps.Deployment:
+		o.Spec.Template.Labels = withOperatorManagedLabel(o.Spec.Template.Labels)
+	case *apps.DaemonSet:
+		o.Spec.Template.Labels = withOperatorManagedLabel(o.Spec.Template.Labels)
+	case *apps.StatefulSet:
+		o.Spec.Template.Labels = withOperatorManagedLabel(o.Spec.Template.Labels)
+	case *batchv1.Job:
+		o.Spec.Template.Labels = withOperatorManagedLabel(o.Spec.Template.Labels)
+	case *batchv1beta.CronJob:
+		o.Spec.JobTemplate.Spec.Template.Labels = withOperatorManagedLabel(o.Spec.JobTemplate.Spec.Template.Labels)
+	}
+}
+
+// withOperatorManagedLabel returns a copy of the given labels with the common.OperatorManagedLabel. The labels are
+// copied since the labels of a pod template are often shared with the selector of the workload, which is immutable.
+func withOperatorManagedLabel(labels map[string]string) map[string]string {
+	copied := map[string]string{common.OperatorManagedLabel: "true"}
+	for k, v := range labels {
+		copied[k] = v
+	}
+	return copied
+}
+
 // ensureOSSchedulingRestrictions ensures that if obj is a type that creates pods and if osType is not OSTypeAny that a
 // node selector is set on the pod template for the "kubernetes.io/os" label to ensure that the pod is scheduled
 // on a node running an operating system as specified by osType.
@@ -365,6 +558,10 @@ func ensureOSSchedulingRestrictions(obj client.Object, osType rmeta.OSType) {
 // desired annotation, i.e, the ones that the operators Components specify take preference.
 func mergeAnnotations(current, desired map[string]string) map[string]string {
 	for k, v := range current {
+		// The mutators which changed the object are only tracked on the desired object, since they can be removed.
+		if k == render.MutatedByAnnotation {
+			continue
+		}
 		// Copy over annotations that should be copied.
 		if _, ok := desired[k]; !ok {
 			desired[k] = v
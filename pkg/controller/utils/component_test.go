@@ -23,6 +23,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta "k8s.io/api/batch/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
@@ -78,7 +79,7 @@ var _ = Describe("Component handler tests", func() {
 
 		c = fake.NewFakeClientWithScheme(scheme)
 		ctx = context.Background()
-		sm = status.New(c, "fake-component", &common.VersionInfo{Major: 1, Minor: 19})
+		sm = status.New(c, "fake-component", common.NewKubernetesVersionTracker(nil, &common.VersionInfo{Major: 1, Minor: 19}))
 
 		// We need to provide something to handler even though it seems to be unused..
 		instance = &operatorv1.Manager{
@@ -88,6 +89,292 @@ var _ = Describe("Component handler tests", func() {
 		handler = utils.NewComponentHandler(log, c, scheme, instance)
 	})
 
+	It("labels the secrets and configmaps it renders as operator managed", func() {
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: "test-namespace"}},
+				&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-configmap", Namespace: "test-namespace", Labels: map[string]string{"k8s-app": "test"}}},
+			},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+
+		secret := &v1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-secret", Namespace: "test-namespace"}, secret)).NotTo(HaveOccurred())
+		Expect(secret.Labels).To(Equal(map[string]string{common.OperatorManagedLabel: "true"}))
+
+		cm := &v1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-configmap", Namespace: "test-namespace"}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Labels).To(Equal(map[string]string{"k8s-app": "test", common.OperatorManagedLabel: "true"}))
+	})
+
+	It("labels the pods of the workloads it renders as operator managed without changing their selector", func() {
+		labels := map[string]string{"k8s-app": "test"}
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{&apps.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "test-namespace"},
+				Spec: apps.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: labels},
+					Template: v1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+				},
+			}},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+
+		d := &apps.Deployment{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-deployment", Namespace: "test-namespace"}, d)).NotTo(HaveOccurred())
+		Expect(d.Spec.Template.Labels).To(Equal(map[string]string{"k8s-app": "test", common.OperatorManagedLabel: "true"}))
+		Expect(d.Spec.Selector.MatchLabels).To(Equal(map[string]string{"k8s-app": "test"}))
+	})
+
+	It("applies the objects mutated by the registered mutators", func() {
+		newComponent := func() *fakeComponent {
+			return &fakeComponent{
+				supportedOSType: rmeta.OSTypeLinux,
+				objs: []client.Object{
+					&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-configmap", Namespace: "test-namespace"}},
+				},
+			}
+		}
+		unregister := render.RegisterMutator(&labelMutator{name: "team-label", key: "team", value: "platform"})
+		Expect(handler.CreateOrUpdateOrDelete(ctx, newComponent(), sm)).NotTo(HaveOccurred())
+
+		cm := &v1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-configmap", Namespace: "test-namespace"}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Labels).To(HaveKeyWithValue("team", "platform"))
+		Expect(cm.Annotations).To(HaveKeyWithValue(render.MutatedByAnnotation, "team-label"))
+
+		// The mutation is kept by the following reconciles.
+		Expect(handler.CreateOrUpdateOrDelete(ctx, newComponent(), sm)).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-configmap", Namespace: "test-namespace"}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Labels).To(HaveKeyWithValue("team", "platform"))
+
+		// Once the mutator is removed, the object is applied as rendered.
+		unregister()
+		Expect(handler.CreateOrUpdateOrDelete(ctx, newComponent(), sm)).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-configmap", Namespace: "test-namespace"}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Labels).NotTo(HaveKey("team"))
+		Expect(cm.Annotations).NotTo(HaveKey(render.MutatedByAnnotation))
+	})
+
+	It("fails when a mutator fails", func() {
+		defer render.RegisterMutator(&labelMutator{name: "broken", err: fmt.Errorf("no label")})()
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{&v1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test-configmap", Namespace: "test-namespace"},
+			}},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).To(MatchError("mutator broken failed to mutate ConfigMap test-namespace/test-configmap: no label"))
+	})
+
+	It("hardens the containers of the components other than the dataplane when the Installation enables it", func() {
+		hardening := operatorv1.HardeningEnabled
+		handler.SetHardening(&hardening)
+		privileged := true
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{
+				&apps.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "test-namespace"},
+					Spec: apps.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+						Containers: []v1.Container{{
+							Name: "test",
+							SecurityContext: &v1.SecurityContext{
+								Capabilities: &v1.Capabilities{Add: []v1.Capability{"NET_BIND_SERVICE"}},
+							},
+						}},
+					}}},
+				},
+				&apps.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-daemonset", Namespace: "test-namespace"},
+					Spec: apps.DaemonSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+						Containers: []v1.Container{{Name: "test", SecurityContext: &v1.SecurityContext{Privileged: &privileged}}},
+					}}},
+				},
+			},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+
+		deployment := &apps.Deployment{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-deployment", Namespace: "test-namespace"}, deployment)).NotTo(HaveOccurred())
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(*container.SecurityContext.ReadOnlyRootFilesystem).To(BeTrue())
+		Expect(*container.SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+		Expect(*container.SecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(*container.SecurityContext.RunAsUser).To(BeEquivalentTo(10001))
+		Expect(container.SecurityContext.Capabilities).To(Equal(&v1.Capabilities{
+			Add:  []v1.Capability{"NET_BIND_SERVICE"},
+			Drop: []v1.Capability{"ALL"},
+		}))
+		Expect(container.VolumeMounts).To(ConsistOf(v1.VolumeMount{Name: "hardening-tmp", MountPath: "/tmp"}))
+		Expect(deployment.Spec.Template.Spec.Volumes).To(ConsistOf(v1.Volume{
+			Name:         "hardening-tmp",
+			VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+		}))
+
+		// The privileged pods of the dataplane are left unchanged.
+		ds := &apps.DaemonSet{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-daemonset", Namespace: "test-namespace"}, ds)).NotTo(HaveOccurred())
+		Expect(ds.Spec.Template.Spec.Containers[0].SecurityContext).To(Equal(&v1.SecurityContext{Privileged: &privileged}))
+		Expect(ds.Spec.Template.Spec.Volumes).To(BeEmpty())
+	})
+
+	It("keeps the user of the hardened containers reading host paths and mounts the directories they write to", func() {
+		hardening := operatorv1.HardeningEnabled
+		handler.SetHardening(&hardening)
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{&apps.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fluentd-node", Namespace: "tigera-fluentd"},
+				Spec: apps.DaemonSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+					Containers: []v1.Container{{
+						Name:         "fluentd",
+						VolumeMounts: []v1.VolumeMount{{Name: "var-log-calico", MountPath: "/var/log/calico"}},
+					}},
+					Volumes: []v1.Volume{{
+						Name:         "var-log-calico",
+						VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/var/log/calico"}},
+					}},
+				}}},
+			}},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+
+		ds := &apps.DaemonSet{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "fluentd-node", Namespace: "tigera-fluentd"}, ds)).NotTo(HaveOccurred())
+		container := ds.Spec.Template.Spec.Containers[0]
+		Expect(*container.SecurityContext.ReadOnlyRootFilesystem).To(BeTrue())
+		Expect(container.SecurityContext.RunAsNonRoot).To(BeNil())
+		Expect(container.SecurityContext.RunAsUser).To(BeNil())
+		Expect(container.VolumeMounts).To(ConsistOf(
+			v1.VolumeMount{Name: "var-log-calico", MountPath: "/var/log/calico"},
+			v1.VolumeMount{Name: "hardening-tmp", MountPath: "/tmp"},
+			v1.VolumeMount{Name: "hardening-fluentd-buffer", MountPath: "/fluentd/buffer"},
+		))
+		Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(3))
+	})
+
+	It("exports the manifests instead of applying them in the manifests only mode", func() {
+		utils.SetManifestExporter(utils.NewSecretManifestExporter(c))
+		defer utils.SetManifestExporter(nil)
+
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{&v1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test-configmap", Namespace: "test-namespace"},
+			}},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+
+		err := c.Get(ctx, client.ObjectKey{Name: "test-configmap", Namespace: "test-namespace"}, &v1.ConfigMap{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		secret := &v1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-manifests-manager-utils-test-fake", Namespace: common.OperatorNamespace()}, secret)).NotTo(HaveOccurred())
+		Expect(string(secret.Data[utils.ManifestsCreateKey])).To(HavePrefix("---\napiVersion: v1\nkind: ConfigMap\n"))
+		Expect(string(secret.Data[utils.ManifestsCreateKey])).To(ContainSubstring("name: test-configmap\n"))
+		Expect(string(secret.Data[utils.ManifestsCreateKey])).To(ContainSubstring(common.OperatorManagedLabel))
+		Expect(secret.Data[utils.ManifestsDeleteKey]).To(BeEmpty())
+		Expect(secret.Labels).To(HaveKeyWithValue(common.OperatorManagedLabel, "true"))
+	})
+
+	It("exports the manifests of the components of the same type in separate secrets", func() {
+		utils.SetManifestExporter(utils.NewSecretManifestExporter(c))
+		defer utils.SetManifestExporter(nil)
+
+		newComponent := func(name string) *fakeComponent {
+			return &fakeComponent{
+				supportedOSType: rmeta.OSTypeLinux,
+				objs: []client.Object{&v1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace"},
+				}},
+			}
+		}
+		first, second := newComponent("first-configmap"), newComponent("second-configmap")
+		Expect(handler.CreateOrUpdateOrDelete(ctx, first, sm)).NotTo(HaveOccurred())
+		Expect(handler.CreateOrUpdateOrDelete(ctx, second, sm)).NotTo(HaveOccurred())
+		// Handling a component again updates its own secret.
+		Expect(handler.CreateOrUpdateOrDelete(ctx, first, sm)).NotTo(HaveOccurred())
+
+		// The components of another owner don't overwrite them either.
+		other := utils.NewComponentHandler(log, c, scheme, &operatorv1.Compliance{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}})
+		Expect(other.CreateOrUpdateOrDelete(ctx, newComponent("other-configmap"), sm)).NotTo(HaveOccurred())
+
+		for name, configMap := range map[string]string{
+			"tigera-manifests-manager-utils-test-fake":    "first-configmap",
+			"tigera-manifests-manager-utils-test-fake-2":  "second-configmap",
+			"tigera-manifests-compliance-utils-test-fake": "other-configmap",
+		} {
+			secret := &v1.Secret{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: name, Namespace: common.OperatorNamespace()}, secret)).NotTo(HaveOccurred())
+			Expect(string(secret.Data[utils.ManifestsCreateKey])).To(ContainSubstring("name: " + configMap + "\n"))
+		}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-manifests-manager-utils-test-fake-3", Namespace: common.OperatorNamespace()}, &v1.Secret{})).To(HaveOccurred())
+	})
+
+	It("reports the compliance findings of the rendered objects", func() {
+		utils.SetComplianceReporter(utils.NewConfigMapComplianceReporter(c))
+		defer utils.SetComplianceReporter(nil)
+
+		privileged := true
+		fc := &fakeComponent{
+			supportedOSType: rmeta.OSTypeLinux,
+			objs: []client.Object{&apps.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-daemonset", Namespace: "test-namespace"},
+				Spec: apps.DaemonSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "test", SecurityContext: &v1.SecurityContext{Privileged: &privileged}}},
+				}}},
+			}},
+		}
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "test-daemonset", Namespace: "test-namespace"}, &apps.DaemonSet{})).NotTo(HaveOccurred())
+
+		cm := &v1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-compliance-manager-utils-test-fake", Namespace: common.OperatorNamespace()}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Labels).To(HaveKeyWithValue(utils.ComplianceReportLabel, "true"))
+		Expect(cm.Data[utils.ComplianceFindingsKey]).To(MatchJSON(`[{
+			"check": "privileged-container", "severity": "High", "kind": "DaemonSet", "namespace": "test-namespace",
+			"name": "test-daemonset", "container": "test", "message": "the container runs privileged"
+		}]`))
+
+		// Once the object passes the checklist, the report is emptied.
+		privileged = false
+		Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-compliance-manager-utils-test-fake", Namespace: common.OperatorNamespace()}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Data[utils.ComplianceFindingsKey]).To(MatchJSON(`[]`))
+	})
+
+	It("reports the compliance findings of the components of the same type separately", func() {
+		utils.SetComplianceReporter(utils.NewConfigMapComplianceReporter(c))
+		defer utils.SetComplianceReporter(nil)
+
+		newComponent := func(privileged bool) *fakeComponent {
+			return &fakeComponent{
+				supportedOSType: rmeta.OSTypeLinux,
+				objs: []client.Object{&apps.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-daemonset", Namespace: "test-namespace"},
+					Spec: apps.DaemonSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+						Containers: []v1.Container{{Name: "test", SecurityContext: &v1.SecurityContext{Privileged: &privileged}}},
+					}}},
+				}},
+			}
+		}
+		// The hardened component doesn't clear the findings of the privileged one.
+		Expect(handler.CreateOrUpdateOrDelete(ctx, newComponent(true), sm)).NotTo(HaveOccurred())
+		Expect(handler.CreateOrUpdateOrDelete(ctx, newComponent(false), sm)).NotTo(HaveOccurred())
+
+		cm := &v1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-compliance-manager-utils-test-fake", Namespace: common.OperatorNamespace()}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Data[utils.ComplianceFindingsKey]).To(ContainSubstring(`"check":"privileged-container"`))
+		Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-compliance-manager-utils-test-fake-2", Namespace: common.OperatorNamespace()}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Data[utils.ComplianceFindingsKey]).To(MatchJSON(`[]`))
+	})
+
 	It("merges annotations and reconciles only operator added annotations", func() {
 		fc := &fakeComponent{
 			supportedOSType: rmeta.OSTypeLinux,
@@ -667,3 +954,29 @@ func (c *fakeComponent) Objects() ([]client.Object, []client.Object) {
 func (c *fakeComponent) SupportedOSType() rmeta.OSType {
 	return c.supportedOSType
 }
+
+// labelMutator is a render.Mutator setting a label on the ConfigMaps.
+type labelMutator struct {
+	name, key, value string
+	err              error
+}
+
+func (m *labelMutator) Name() string {
+	return m.name
+}
+
+func (m *labelMutator) Mutate(obj client.Object) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if _, ok := obj.(*v1.ConfigMap); !ok {
+		return false, nil
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[m.key] = m.value
+	obj.SetLabels(labels)
+	return true, nil
+}
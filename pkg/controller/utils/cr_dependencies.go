@@ -0,0 +1,268 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+// OperatorCR is an operator CR of the dependency graph of the CRs.
+type OperatorCR string
+
+const (
+	InstallationCR       OperatorCR = "Installation"
+	APIServerCR          OperatorCR = "APIServer"
+	LogStorageCR         OperatorCR = "LogStorage"
+	ComplianceCR         OperatorCR = "Compliance"
+	IntrusionDetectionCR OperatorCR = "IntrusionDetection"
+	LogCollectorCR       OperatorCR = "LogCollector"
+	ManagerCR            OperatorCR = "Manager"
+)
+
+// crDependencies is the dependency graph of the operator CRs: the CRs whose components must be ready before the
+// components of each CR are reconciled. It must be acyclic, which is checked when the package is initialized, so that
+// the controllers can't wait for each other.
+var crDependencies = map[OperatorCR][]OperatorCR{
+	InstallationCR:       nil,
+	APIServerCR:          {InstallationCR},
+	LogStorageCR:         {InstallationCR},
+	ComplianceCR:         {APIServerCR, LogStorageCR},
+	IntrusionDetectionCR: {APIServerCR, LogStorageCR},
+	LogCollectorCR:       {APIServerCR},
+	ManagerCR:            {APIServerCR, LogStorageCR},
+}
+
+// operatorCR reads the state of an operator CR.
+type operatorCR struct {
+	// optional CRs are not waited for when they don't exist, e.g. the LogStorage of a managed cluster.
+	optional bool
+
+	// state returns the state of the CR, which is ready when it is operatorv1.TigeraStatusReady.
+	state func(ctx context.Context, cli client.Client) (string, error)
+
+	// object is the type of the CR, which is watched by the controllers depending on it.
+	object client.Object
+}
+
+// stateOf returns the state of the CR of the given type and key.
+func stateOf(obj func() client.Object, key client.ObjectKey, state func(client.Object) string) func(context.Context, client.Client) (string, error) {
+	return func(ctx context.Context, cli client.Client) (string, error) {
+		o := obj()
+		if err := cli.Get(ctx, key, o); err != nil {
+			return "", err
+		}
+		return state(o), nil
+	}
+}
+
+var operatorCRs = map[OperatorCR]operatorCR{
+	InstallationCR: {
+		// The Installation is ready once its status reports the variant, see GetInstallation.
+		state: stateOf(func() client.Object { return &operatorv1.Installation{} }, DefaultInstanceKey, func(o client.Object) string {
+			if o.(*operatorv1.Installation).Status.Variant == "" {
+				return ""
+			}
+			return operatorv1.TigeraStatusReady
+		}),
+		object: &operatorv1.Installation{},
+	},
+	APIServerCR: {
+		state: func(ctx context.Context, cli client.Client) (string, error) {
+			instance, _, err := GetAPIServer(ctx, cli)
+			if err != nil {
+				return "", err
+			}
+			return instance.Status.State, nil
+		},
+		object: &operatorv1.APIServer{},
+	},
+	LogStorageCR: {
+		optional: true,
+		state: stateOf(func() client.Object { return &operatorv1.LogStorage{} }, DefaultTSEEInstanceKey, func(o client.Object) string {
+			return o.(*operatorv1.LogStorage).Status.State
+		}),
+		object: &operatorv1.LogStorage{},
+	},
+	ComplianceCR: {
+		optional: true,
+		state: stateOf(func() client.Object { return &operatorv1.Compliance{} }, DefaultTSEEInstanceKey, func(o client.Object) string {
+			return o.(*operatorv1.Compliance).Status.State
+		}),
+		object: &operatorv1.Compliance{},
+	},
+	IntrusionDetectionCR: {
+		optional: true,
+		state: stateOf(func() client.Object { return &operatorv1.IntrusionDetection{} }, DefaultTSEEInstanceKey, func(o client.Object) string {
+			return o.(*operatorv1.IntrusionDetection).Status.State
+		}),
+		object: &operatorv1.IntrusionDetection{},
+	},
+	LogCollectorCR: {
+		optional: true,
+		state: stateOf(func() client.Object { return &operatorv1.LogCollector{} }, DefaultTSEEInstanceKey, func(o client.Object) string {
+			return o.(*operatorv1.LogCollector).Status.State
+		}),
+		object: &operatorv1.LogCollector{},
+	},
+	ManagerCR: {
+		optional: true,
+		state: stateOf(func() client.Object { return &operatorv1.Manager{} }, DefaultTSEEInstanceKey, func(o client.Object) string {
+			return o.(*operatorv1.Manager).Status.State
+		}),
+		object: &operatorv1.Manager{},
+	},
+}
+
+// crOrder is the operator CRs in the order of their dependencies: each CR comes after the CRs it depends on.
+var crOrder []OperatorCR
+
+func init() {
+	var err error
+	if crOrder, err = dependencyOrder(crDependencies); err != nil {
+		panic(err)
+	}
+}
+
+// dependencyOrder returns the CRs of the dependency graph ordered so that each CR comes after the CRs it depends on,
+// or an error if the graph has a cycle.
+func dependencyOrder(deps map[OperatorCR][]OperatorCR) ([]OperatorCR, error) {
+	var crs []OperatorCR
+	for cr := range deps {
+		crs = append(crs, cr)
+	}
+	sort.Slice(crs, func(i, j int) bool { return crs[i] < crs[j] })
+
+	var order []OperatorCR
+	visited := map[OperatorCR]bool{}
+	var path []OperatorCR
+	var visit func(cr OperatorCR) error
+	visit = func(cr OperatorCR) error {
+		for i, p := range path {
+			if p == cr {
+				cycle := append(append([]OperatorCR{}, path[i:]...), cr)
+				return fmt.Errorf("the dependencies of the operator CRs have a cycle: %s", joinCRs(cycle, " -> "))
+			}
+		}
+		if visited[cr] {
+			return nil
+		}
+		path = append(path, cr)
+		for _, dep := range deps[cr] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		visited[cr] = true
+		order = append(order, cr)
+		return nil
+	}
+	for _, cr := range crs {
+		if err := visit(cr); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// dependencyPaths returns the path from the given CR to each of the CRs it depends on, directly or not.
+func dependencyPaths(cr OperatorCR) map[OperatorCR][]OperatorCR {
+	paths := map[OperatorCR][]OperatorCR{}
+	var walk func(path []OperatorCR)
+	walk = func(path []OperatorCR) {
+		for _, dep := range crDependencies[path[len(path)-1]] {
+			if _, ok := paths[dep]; ok {
+				continue
+			}
+			p := append(append([]OperatorCR{}, path...), dep)
+			paths[dep] = p
+			walk(p)
+		}
+	}
+	walk([]OperatorCR{cr})
+	return paths
+}
+
+// CRDependenciesReady is met once the CRs the given CR depends on, directly or not, are ready. The dependencies are
+// checked in the order of the dependency graph, so that the unmet dependency reported is the one which blocks the
+// others. The controller is expected to watch the CRs with AddCRDependencyWatches.
+func CRDependenciesReady(cr OperatorCR) Dependency {
+	paths := dependencyPaths(cr)
+	return func(ctx context.Context, cli client.Client, log logr.Logger) *UnmetDependency {
+		for _, dep := range crOrder {
+			path, ok := paths[dep]
+			if !ok {
+				continue
+			}
+			c := operatorCRs[dep]
+			state, err := c.state(ctx, cli)
+			if err != nil {
+				if kerrors.IsNotFound(err) {
+					if c.optional {
+						continue
+					}
+					return &UnmetDependency{
+						Reason:  fmt.Sprintf("Waiting for %s to be created", dep),
+						Message: fmt.Sprintf("Dependency %s not satisfied", joinCRs(path, " -> ")),
+					}
+				}
+				return &UnmetDependency{Reason: fmt.Sprintf("Error querying %s", dep), Message: err.Error(), Err: err}
+			}
+			if state != operatorv1.TigeraStatusReady {
+				log.V(2).Info("Dependency is not ready", "dependency", dep, "state", state)
+				return &UnmetDependency{
+					Reason:  fmt.Sprintf("Waiting for %s to be ready", dep),
+					Message: fmt.Sprintf("Dependency %s not satisfied", joinCRs(path, " -> ")),
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// AddCRDependencyWatches watches the CRs the given CR depends on, directly or not, so that the controller reconciles
+// when they become ready.
+func AddCRDependencyWatches(c controller.Controller, cr OperatorCR) error {
+	for _, dep := range crOrder {
+		if _, ok := dependencyPaths(cr)[dep]; !ok {
+			continue
+		}
+		obj := operatorCRs[dep].object.DeepCopyObject().(client.Object)
+		if err := c.Watch(&source.Kind{Type: obj}, &handler.EnqueueRequestForObject{}); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dep, err)
+		}
+	}
+	return nil
+}
+
+func joinCRs(crs []OperatorCR, sep string) string {
+	s := make([]string, len(crs))
+	for i, cr := range crs {
+		s[i] = string(cr)
+	}
+	return strings.Join(s, sep)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+)
+
+var _ = Describe("CR dependencies", func() {
+	var (
+		c   client.Client
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+		ctx = context.Background()
+	})
+
+	check := func(cr OperatorCR) *UnmetDependency {
+		return CRDependenciesReady(cr)(ctx, c, logf.Log.WithName("test"))
+	}
+
+	It("should order the CRs after the CRs they depend on", func() {
+		Expect(crOrder).To(HaveLen(len(crDependencies)))
+		position := map[OperatorCR]int{}
+		for i, cr := range crOrder {
+			position[cr] = i
+		}
+		for cr, deps := range crDependencies {
+			Expect(operatorCRs).To(HaveKey(cr))
+			for _, dep := range deps {
+				Expect(position[dep]).To(BeNumerically("<", position[cr]), "%s depends on %s", cr, dep)
+			}
+		}
+	})
+
+	It("should reject the cycles", func() {
+		_, err := dependencyOrder(map[OperatorCR][]OperatorCR{
+			InstallationCR: nil,
+			APIServerCR:    {InstallationCR, ManagerCR},
+			ManagerCR:      {APIServerCR},
+		})
+		Expect(err).To(MatchError("the dependencies of the operator CRs have a cycle: APIServer -> Manager -> APIServer"))
+	})
+
+	It("should report the dependency which blocks the others", func() {
+		Expect(check(ManagerCR)).To(Equal(&UnmetDependency{
+			Reason:  "Waiting for Installation to be created",
+			Message: "Dependency Manager -> APIServer -> Installation not satisfied",
+		}))
+
+		installation := &operatorv1.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		Expect(c.Create(ctx, installation)).NotTo(HaveOccurred())
+		Expect(check(ManagerCR).Reason).To(Equal("Waiting for Installation to be ready"))
+
+		installation.Status.Variant = operatorv1.TigeraSecureEnterprise
+		Expect(c.Update(ctx, installation)).NotTo(HaveOccurred())
+		Expect(check(ManagerCR)).To(Equal(&UnmetDependency{
+			Reason:  "Waiting for APIServer to be created",
+			Message: "Dependency Manager -> APIServer not satisfied",
+		}))
+
+		Expect(c.Create(ctx, &operatorv1.APIServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Status:     operatorv1.APIServerStatus{State: operatorv1.TigeraStatusReady},
+		})).NotTo(HaveOccurred())
+		// The LogStorage is optional.
+		Expect(check(ManagerCR)).To(BeNil())
+
+		logStorage := &operatorv1.LogStorage{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}}
+		Expect(c.Create(ctx, logStorage)).NotTo(HaveOccurred())
+		Expect(check(ManagerCR)).To(Equal(&UnmetDependency{
+			Reason:  "Waiting for LogStorage to be ready",
+			Message: "Dependency Manager -> LogStorage not satisfied",
+		}))
+		Expect(check(LogCollectorCR)).To(BeNil())
+
+		logStorage.Status.State = operatorv1.TigeraStatusReady
+		Expect(c.Update(ctx, logStorage)).NotTo(HaveOccurred())
+		Expect(check(ManagerCR)).To(BeNil())
+	})
+})
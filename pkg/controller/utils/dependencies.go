@@ -0,0 +1,147 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/controller/status"
+)
+
+// dependencyRequeueDelay is the delay before reconciling again when a dependency is unmet and no watch triggers a
+// reconcile once it is met.
+const dependencyRequeueDelay = 10 * time.Second
+
+// Dependency is a condition a controller waits for before reconciling its components. It returns nil when the
+// condition is met.
+type Dependency func(ctx context.Context, cli client.Client, log logr.Logger) *UnmetDependency
+
+// UnmetDependency describes a Dependency that is not met.
+type UnmetDependency struct {
+	// Reason and Message are reported in the degraded status of the controller.
+	Reason  string
+	Message string
+	// Result is returned by the reconcile.
+	Result reconcile.Result
+	// Err is returned by the reconcile, if set.
+	Err error
+}
+
+// CheckDependencies checks the given dependencies in order. The first unmet dependency is reported in the degraded
+// status of the controller, and true is returned with its result and error, so that the reconcile returns them:
+//
+//	if result, unmet, err := utils.CheckDependencies(ctx, r.client, reqLogger, r.status, deps...); unmet {
+//		return result, err
+//	}
+func CheckDependencies(ctx context.Context, cli client.Client, log logr.Logger, status status.StatusManager, deps ...Dependency) (reconcile.Result, bool, error) {
+	for _, dep := range deps {
+		if unmet := dep(ctx, cli, log); unmet != nil {
+			log.V(2).Info("Dependency is not met", "reason", unmet.Reason, "message", unmet.Message)
+			status.SetDegraded(unmet.Reason, unmet.Message)
+			return unmet.Result, true, unmet.Err
+		}
+	}
+	return reconcile.Result{}, false, nil
+}
+
+// APIServerReady is met once the Tigera API server is ready. The APIServer watch of the controller triggers a
+// reconcile when it becomes ready.
+func APIServerReady(ctx context.Context, cli client.Client, log logr.Logger) *UnmetDependency {
+	if !IsAPIServerReady(cli, log) {
+		return &UnmetDependency{Reason: "Waiting for Tigera API server to be ready"}
+	}
+	return nil
+}
+
+// LicenseAPIReady is met once the LicenseKey API is available, i.e. once the LicenseKey watch added with
+// WaitToAddLicenseKeyWatch marked the given flag as ready.
+func LicenseAPIReady(flag *ReadyFlag) Dependency {
+	return func(context.Context, client.Client, logr.Logger) *UnmetDependency {
+		if !flag.IsReady() {
+			return &UnmetDependency{
+				Reason: "Waiting for LicenseKeyAPI to be ready",
+				Result: reconcile.Result{RequeueAfter: dependencyRequeueDelay},
+			}
+		}
+		return nil
+	}
+}
+
+// LicenseKeyInstalled is met once the LicenseKey is installed, which is then stored in the given license. It is read
+// through the given cache, which may be nil.
+func LicenseKeyInstalled(cache *LicenseKeyCache, license *v3.LicenseKey) Dependency {
+	return func(ctx context.Context, cli client.Client, _ logr.Logger) *UnmetDependency {
+		l, err := cache.Get(ctx, cli)
+		if err != nil {
+			reason := "Error querying license"
+			if kerrors.IsNotFound(err) {
+				reason = "License not found"
+			}
+			return &UnmetDependency{
+				Reason:  reason,
+				Message: err.Error(),
+				Result:  reconcile.Result{RequeueAfter: dependencyRequeueDelay},
+			}
+		}
+		*license = l
+		return nil
+	}
+}
+
+// NamespaceExists is met once the namespace of the given component exists. The controller is expected to watch the
+// namespace.
+func NamespaceExists(name, component string) Dependency {
+	return func(ctx context.Context, cli client.Client, _ logr.Logger) *UnmetDependency {
+		if err := cli.Get(ctx, client.ObjectKey{Name: name}, &corev1.Namespace{}); err != nil {
+			if kerrors.IsNotFound(err) {
+				return &UnmetDependency{
+					Reason:  fmt.Sprintf("%s namespace does not exist", name),
+					Message: fmt.Sprintf("Dependency on %s not satisfied", name),
+					Err:     err,
+				}
+			}
+			return &UnmetDependency{Reason: fmt.Sprintf("Error querying %s", component), Message: err.Error(), Err: err}
+		}
+		return nil
+	}
+}
+
+// ComplianceReady is met once the Compliance is ready. The controller is expected to watch the Compliance.
+func ComplianceReady(ctx context.Context, cli client.Client, _ logr.Logger) *UnmetDependency {
+	compliance := &operatorv1.Compliance{}
+	if err := cli.Get(ctx, DefaultTSEEInstanceKey, compliance); err != nil {
+		if kerrors.IsNotFound(err) {
+			return &UnmetDependency{Reason: "Compliance not found", Message: err.Error(), Err: err}
+		}
+		return &UnmetDependency{Reason: "Error querying compliance", Message: err.Error(), Err: err}
+	}
+	if compliance.Status.State != operatorv1.TigeraStatusReady {
+		return &UnmetDependency{
+			Reason:  "Compliance is not ready",
+			Message: fmt.Sprintf("compliance status: %s", compliance.Status.State),
+		}
+	}
+	return nil
+}
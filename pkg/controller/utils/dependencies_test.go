@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/stretchr/testify/mock"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/status"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Dependency checks", func() {
+	var (
+		c          client.Client
+		ctx        context.Context
+		mockStatus *status.MockStatus
+		license    v3.LicenseKey
+		apiReady   *ReadyFlag
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+		ctx = context.Background()
+		mockStatus = &status.MockStatus{}
+		license = v3.LicenseKey{}
+		apiReady = &ReadyFlag{}
+
+		Expect(c.Create(ctx, &operatorv1.APIServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Status:     operatorv1.APIServerStatus{State: operatorv1.TigeraStatusReady},
+		})).NotTo(HaveOccurred())
+	})
+
+	check := func(deps ...Dependency) (reconcile.Result, bool, error) {
+		return CheckDependencies(ctx, c, logf.Log.WithName("test"), mockStatus, deps...)
+	}
+
+	It("should report the first unmet dependency", func() {
+		mockStatus.On("SetDegraded", "Waiting for LicenseKeyAPI to be ready", "").Return()
+		result, unmet, err := check(APIServerReady, LicenseAPIReady(apiReady), LicenseKeyInstalled(nil, &license))
+		Expect(unmet).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(dependencyRequeueDelay))
+		mockStatus.AssertExpectations(GinkgoT())
+		mockStatus.AssertNumberOfCalls(GinkgoT(), "SetDegraded", 1)
+	})
+
+	It("should report a missing license", func() {
+		apiReady.MarkAsReady()
+		mockStatus.On("SetDegraded", "License not found", mock.Anything).Return()
+		_, unmet, err := check(APIServerReady, LicenseAPIReady(apiReady), LicenseKeyInstalled(nil, &license))
+		Expect(unmet).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertExpectations(GinkgoT())
+	})
+
+	It("should return the license once every dependency is met", func() {
+		apiReady.MarkAsReady()
+		Expect(c.Create(ctx, &v3.LicenseKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Status:     v3.LicenseKeyStatus{Features: []string{"all"}},
+		})).NotTo(HaveOccurred())
+
+		_, unmet, err := check(APIServerReady, LicenseAPIReady(apiReady), LicenseKeyInstalled(nil, &license))
+		Expect(unmet).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(license.Status.Features).To(ConsistOf("all"))
+		mockStatus.AssertNotCalled(GinkgoT(), "SetDegraded", mock.Anything, mock.Anything)
+	})
+
+	It("should wait for the namespace and for compliance to be ready", func() {
+		mockStatus.On("SetDegraded", "tigera-prometheus namespace does not exist", "Dependency on tigera-prometheus not satisfied").Return()
+		_, unmet, err := check(NamespaceExists(common.TigeraPrometheusNamespace, "prometheus"))
+		Expect(unmet).To(BeTrue())
+		Expect(err).To(HaveOccurred())
+
+		Expect(c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: common.TigeraPrometheusNamespace}})).NotTo(HaveOccurred())
+		Expect(c.Create(ctx, &operatorv1.Compliance{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}})).NotTo(HaveOccurred())
+		mockStatus.On("SetDegraded", "Compliance is not ready", "compliance status: ").Return()
+		_, unmet, err = check(NamespaceExists(common.TigeraPrometheusNamespace, "prometheus"), ComplianceReady)
+		Expect(unmet).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertExpectations(GinkgoT())
+	})
+})
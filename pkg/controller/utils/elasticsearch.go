@@ -42,6 +42,10 @@ const (
 	DefaultMaxIndexSizeGi        = 30
 	ElasticConnRetries           = 10
 	ElasticConnRetryInterval     = "500ms"
+
+	// DataplaneEventsIndex is the prefix of the indices of the events of the VPP dataplane, which fluentd ships when
+	// the LogCollector collects them.
+	DataplaneEventsIndex = "tigera_secure_ee_dataplane"
 )
 
 type Policy struct {
@@ -116,6 +120,7 @@ type ElasticsearchClientCreator func(client client.Client, ctx context.Context,
 
 type ElasticClient interface {
 	SetILMPolicies(context.Context, *operatorv1.LogStorage) error
+	SetIndexTemplates(context.Context, *relasticsearch.ClusterConfig) error
 }
 
 type esClient struct {
@@ -166,19 +171,19 @@ func (es *esClient) SetILMPolicies(ctx context.Context, ls *operatorv1.LogStorag
 // listILMPolicies generates ILM policies based on disk space and retention in LogStorage
 // Allocate 70% of ES disk space to flows, dns and bgp logs [majorPctOfTotalDisk]
 // Allocate 90% of the 70% ES disk space to flow logs, 5% of the 70% ES disk space to each dns and bgp logs.
-// Allocate 10% of ES disk space to logs that are NOT flows, dns or bgp [minorPctOfTotalDisk]
+// Allocate 10% of ES disk space to logs that are NOT flows, dns, bgp or l7 [minorPctOfTotalDisk]
 // Equally distribute 10% of the ES disk space among these other log types
 func (es *esClient) listILMPolicies(ls *operatorv1.LogStorage) map[string]policyDetail {
 	totalEsStorage := getTotalEsDisk(ls)
 	majorPctOfTotalDisk := 0.7
 
 	// numOfIndicesWithMinorSpace is the number of time series indices created that are not flows, dns or bgp related.
-	// i.e., audit_ee, audit_kube, compliance_reports, benchmark_results, events, snapshots
-	numOfIndicesWithMinorSpace := 6
+	// i.e., audit_ee, audit_kube, compliance_reports, benchmark_results, events, snapshots, dataplane
+	numOfIndicesWithMinorSpace := 7
 	minorPctOfTotalDisk := 0.1
 	pctOfDisk := minorPctOfTotalDisk / float64(numOfIndicesWithMinorSpace)
 
-	// Retention is not set in LogStorage for bgp, dns, benchmark and events logs, set default values used by curator
+	// Retention is not set in LogStorage for bgp, dns, benchmark, events and dataplane logs, set default values used by curator
 	return map[string]policyDetail{
 		"tigera_secure_ee_flows": buildILMPolicy(totalEsStorage, majorPctOfTotalDisk, 0.85, int(*ls.Spec.Retention.Flows)),
 		"tigera_secure_ee_dns":   buildILMPolicy(totalEsStorage, majorPctOfTotalDisk, 0.05, 8),
@@ -191,6 +196,42 @@ func (es *esClient) listILMPolicies(ls *operatorv1.LogStorage) map[string]policy
 		"tigera_secure_ee_compliance_reports": buildILMPolicy(totalEsStorage, minorPctOfTotalDisk, pctOfDisk, int(*ls.Spec.Retention.ComplianceReports)),
 		"tigera_secure_ee_benchmark_results":  buildILMPolicy(totalEsStorage, minorPctOfTotalDisk, pctOfDisk, 91),
 		"tigera_secure_ee_events":             buildILMPolicy(totalEsStorage, minorPctOfTotalDisk, pctOfDisk, 91),
+		DataplaneEventsIndex:                  buildILMPolicy(totalEsStorage, minorPctOfTotalDisk, pctOfDisk, 8),
+	}
+}
+
+// SetIndexTemplates creates or updates the index templates of the indices which fluentd doesn't create templates for,
+// i.e. the events of the VPP dataplane, so that their indices are rolled over and deleted by their ILM policy.
+func (es *esClient) SetIndexTemplates(ctx context.Context, clusterConfig *relasticsearch.ClusterConfig) error {
+	_, err := es.client.IndexPutTemplate(DataplaneEventsIndex).BodyJson(dataplaneEventsTemplate(clusterConfig)).Do(ctx)
+	if err != nil {
+		log.Error(err, "Error applying index template")
+		return err
+	}
+	return nil
+}
+
+// dataplaneEventsTemplate returns the index template of the events of the VPP dataplane. The rollover alias follows
+// the naming of the indices written by fluentd, i.e. <index>.<cluster name>.
+func dataplaneEventsTemplate(clusterConfig *relasticsearch.ClusterConfig) map[string]interface{} {
+	alias := fmt.Sprintf("%s.%s.", DataplaneEventsIndex, clusterConfig.ClusterName())
+	return map[string]interface{}{
+		"index_patterns": []string{alias + "*"},
+		"settings": map[string]interface{}{
+			"number_of_shards":               clusterConfig.Shards(),
+			"number_of_replicas":             clusterConfig.Replicas(),
+			"index.lifecycle.name":           DataplaneEventsIndex + "_policy",
+			"index.lifecycle.rollover_alias": alias,
+		},
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"time":      map[string]interface{}{"type": "date"},
+				"host":      map[string]interface{}{"type": "keyword"},
+				"type":      map[string]interface{}{"type": "keyword"},
+				"interface": map[string]interface{}{"type": "keyword"},
+				"message":   map[string]interface{}{"type": "text"},
+			},
+		},
 	}
 }
 
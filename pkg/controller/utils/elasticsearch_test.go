@@ -30,6 +30,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 )
 
 const (
@@ -92,6 +94,10 @@ var _ = Describe("Elasticsearch tests", func() {
 			})
 			Expect(err).To(BeNil())
 		})
+		It("apply the index template of the dataplane events", func() {
+			clusterConfig := relasticsearch.NewClusterConfig("cluster", 1, 2, 5)
+			Expect(eClient.SetIndexTemplates(ctx, clusterConfig)).To(BeNil())
+		})
 	})
 })
 
@@ -132,6 +138,21 @@ func (t *testRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 	case "POST":
 	case "PUT":
 		switch req.URL.String() {
+		case baseURI + "/_template/" + DataplaneEventsIndex:
+			actualBody, err := ioutil.ReadAll(req.Body)
+			Expect(err).To(BeNil())
+
+			jsonFile, err := os.Open("test_files/03_put_template.json")
+			Expect(err).To(BeNil())
+			defer jsonFile.Close()
+			expectedBody, _ := ioutil.ReadAll(jsonFile)
+			Expect(actualBody).To(MatchJSON(expectedBody))
+
+			return &http.Response{
+				StatusCode: 200,
+				Request:    req,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+			}, nil
 		case baseURI + "/_ilm/policy/" + indexName + "_policy":
 			if newPolicies {
 				actualBody, err := ioutil.ReadAll(req.Body)
@@ -0,0 +1,149 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"strings"
+
+	apps "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta "k8s.io/api/batch/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tigera/operator/pkg/ptr"
+)
+
+const (
+	// hardeningVolumePrefix prefixes the emptyDir volumes mounted on the directories the hardened containers write to,
+	// whose root filesystem is read-only, e.g. hardening-tmp for /tmp.
+	hardeningVolumePrefix = "hardening"
+
+	// hardeningUser is the user of the hardened containers which don't set one, since their image may run as root.
+	hardeningUser = 10001
+)
+
+// hardeningWritableDirs are the directories the containers of the components write to, by container name, besides
+// /tmp which every hardened container gets.
+var hardeningWritableDirs = map[string][]string{
+	// The buffers of the logs not yet sent to their outputs.
+	"fluentd": {"/fluentd/buffer"},
+	// The cache and the pid file of nginx.
+	"tigera-manager": {"/var/cache/nginx", "/var/run"},
+}
+
+// hardenObject hardens the containers of the pod templates of obj, unless they run the dataplane. The pods managed by
+// the operators of Elasticsearch and Prometheus are left to them.
+func hardenObject(obj client.Object) {
+	var podSpec *v1.PodSpec
+	switch o := obj.(type) {
+	case *v1.PodTemplate:
+		podSpec = &o.Template.Spec
+	case *apps.Deployment:
+		podSpec = &o.Spec.Template.Spec
+	case *apps.DaemonSet:
+		podSpec = &o.Spec.Template.Spec
+	case *apps.StatefulSet:
+		podSpec = &o.Spec.Template.Spec
+	case *batchv1beta.CronJob:
+		podSpec = &o.Spec.JobTemplate.Spec.Template.Spec
+	case *batchv1.Job:
+		podSpec = &o.Spec.Template.Spec
+	default:
+		return
+	}
+	if runsDataplane(podSpec) {
+		return
+	}
+
+	volumes := map[string]bool{}
+	hostPaths := map[string]bool{}
+	for _, vol := range podSpec.Volumes {
+		volumes[vol.Name] = true
+		if vol.HostPath != nil {
+			hostPaths[vol.Name] = true
+		}
+	}
+	harden := func(c *v1.Container) {
+		if c.SecurityContext == nil {
+			c.SecurityContext = &v1.SecurityContext{}
+		}
+		sc := c.SecurityContext
+		sc.ReadOnlyRootFilesystem = ptr.BoolToPtr(true)
+		sc.AllowPrivilegeEscalation = ptr.BoolToPtr(false)
+		if sc.Capabilities == nil {
+			sc.Capabilities = &v1.Capabilities{}
+		}
+		// The capabilities the container adds are kept, since they are added after the others are dropped.
+		sc.Capabilities.Drop = []v1.Capability{"ALL"}
+
+		mounted := map[string]bool{}
+		hostPathMounted := false
+		for _, m := range c.VolumeMounts {
+			mounted[m.MountPath] = true
+			hostPathMounted = hostPathMounted || hostPaths[m.Name]
+		}
+		// The containers reading host paths, e.g. fluentd reading the logs of the host, keep their user since the
+		// files of the host belong to root.
+		if !hostPathMounted {
+			sc.RunAsNonRoot = ptr.BoolToPtr(true)
+			if sc.RunAsUser == nil && (podSpec.SecurityContext == nil || podSpec.SecurityContext.RunAsUser == nil) {
+				sc.RunAsUser = ptr.Int64ToPtr(hardeningUser)
+			}
+		}
+
+		for _, dir := range append([]string{"/tmp"}, hardeningWritableDirs[c.Name]...) {
+			if mounted[dir] {
+				continue
+			}
+			name := hardeningVolumePrefix + strings.ReplaceAll(dir, "/", "-")
+			c.VolumeMounts = append(c.VolumeMounts, v1.VolumeMount{Name: name, MountPath: dir})
+			if !volumes[name] {
+				podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+					Name:         name,
+					VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+				})
+				volumes[name] = true
+			}
+		}
+	}
+	for i := range podSpec.InitContainers {
+		harden(&podSpec.InitContainers[i])
+	}
+	for i := range podSpec.Containers {
+		harden(&podSpec.Containers[i])
+	}
+}
+
+// runsDataplane returns true if the pod runs in the host network, or has a container running privileged or as root,
+// as the pods of the dataplane do.
+func runsDataplane(podSpec *v1.PodSpec) bool {
+	if podSpec.HostNetwork {
+		return true
+	}
+	if podSpec.SecurityContext != nil && podSpec.SecurityContext.RunAsUser != nil && *podSpec.SecurityContext.RunAsUser == 0 {
+		return true
+	}
+	for _, c := range append(append([]v1.Container{}, podSpec.InitContainers...), podSpec.Containers...) {
+		sc := c.SecurityContext
+		if sc == nil {
+			continue
+		}
+		if (sc.Privileged != nil && *sc.Privileged) || (sc.RunAsUser != nil && *sc.RunAsUser == 0) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+)
+
+// licenseKeyCacheTTL bounds how long a cached LicenseKey is used, in case one of its events is missed.
+const licenseKeyCacheTTL = 5 * time.Minute
+
+// LicenseKeyCache holds the LicenseKey read by the controllers. The LicenseKey is not cached by the manager, so
+// without it every reconcile of the controllers depending on the license reads it from the API server.
+//
+// The cached LicenseKey is dropped on every event of the LicenseKey watches added with WaitToAddLicenseKeyWatch, so
+// the next read fetches it from the API server again. A nil LicenseKeyCache caches nothing.
+type LicenseKeyCache struct {
+	lock    sync.Mutex
+	license *v3.LicenseKey
+	fetched time.Time
+	// generation is bumped on every invalidation, so that a read started before an invalidation doesn't cache the
+	// LicenseKey it fetched.
+	generation uint64
+}
+
+// NewLicenseKeyCache returns an empty LicenseKeyCache.
+func NewLicenseKeyCache() *LicenseKeyCache {
+	return &LicenseKeyCache{}
+}
+
+// Get returns the cached LicenseKey, or fetches it with FetchLicenseKey if none is cached. Errors are not cached.
+func (c *LicenseKeyCache) Get(ctx context.Context, cli client.Client) (v3.LicenseKey, error) {
+	if c == nil {
+		return FetchLicenseKey(ctx, cli)
+	}
+
+	c.lock.Lock()
+	if c.license != nil && time.Since(c.fetched) < licenseKeyCacheTTL {
+		license := *c.license.DeepCopy()
+		c.lock.Unlock()
+		return license, nil
+	}
+	generation := c.generation
+	c.lock.Unlock()
+
+	license, err := FetchLicenseKey(ctx, cli)
+	if err != nil {
+		return license, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.generation == generation {
+		c.license = license.DeepCopy()
+		c.fetched = time.Now()
+	}
+	return license, nil
+}
+
+// Invalidate drops the cached LicenseKey.
+func (c *LicenseKeyCache) Invalidate() {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.license = nil
+	c.generation++
+}
+
+// licenseKeyEventHandler invalidates the LicenseKeyCache before enqueueing the request of every event, so that the
+// reconcile triggered by the event reads the new LicenseKey.
+type licenseKeyEventHandler struct {
+	handler.EnqueueRequestForObject
+	cache *LicenseKeyCache
+}
+
+func (h *licenseKeyEventHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.cache.Invalidate()
+	h.EnqueueRequestForObject.Create(e, q)
+}
+
+func (h *licenseKeyEventHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.cache.Invalidate()
+	h.EnqueueRequestForObject.Update(e, q)
+}
+
+func (h *licenseKeyEventHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.cache.Invalidate()
+	h.EnqueueRequestForObject.Delete(e, q)
+}
+
+func (h *licenseKeyEventHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.cache.Invalidate()
+	h.EnqueueRequestForObject.Generic(e, q)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"github.com/tigera/operator/pkg/apis"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("LicenseKeyCache", func() {
+	var (
+		c       client.Client
+		ctx     context.Context
+		license *v3.LicenseKey
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = fake.NewFakeClientWithScheme(scheme)
+		ctx = context.Background()
+
+		license = &v3.LicenseKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Status:     v3.LicenseKeyStatus{Features: []string{"all"}},
+		}
+		Expect(c.Create(ctx, license)).NotTo(HaveOccurred())
+	})
+
+	updateFeatures := func(features ...string) {
+		Expect(c.Get(ctx, DefaultInstanceKey, license)).NotTo(HaveOccurred())
+		license.Status.Features = features
+		Expect(c.Update(ctx, license)).NotTo(HaveOccurred())
+	}
+
+	It("should serve the cached license until a license event is handled", func() {
+		cache := NewLicenseKeyCache()
+		l, err := cache.Get(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l.Status.Features).To(ConsistOf("all"))
+
+		updateFeatures("egress-access-control")
+		l, err = cache.Get(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l.Status.Features).To(ConsistOf("all"))
+
+		h := &licenseKeyEventHandler{cache: cache}
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		defer q.ShutDown()
+		h.Update(event.UpdateEvent{ObjectOld: license, ObjectNew: license}, q)
+		Expect(q.Len()).To(Equal(1))
+
+		l, err = cache.Get(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l.Status.Features).To(ConsistOf("egress-access-control"))
+	})
+
+	It("should not cache a missing license", func() {
+		cache := NewLicenseKeyCache()
+		Expect(c.Delete(ctx, license)).NotTo(HaveOccurred())
+		_, err := cache.Get(ctx, c)
+		Expect(err).To(HaveOccurred())
+
+		license.ResourceVersion = ""
+		Expect(c.Create(ctx, license)).NotTo(HaveOccurred())
+		_, err = cache.Get(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should read the license every time without a cache", func() {
+		var cache *LicenseKeyCache
+		_, err := cache.Get(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+
+		updateFeatures("egress-access-control")
+		l, err := cache.Get(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l.Status.Features).To(ConsistOf("egress-access-control"))
+		cache.Invalidate()
+	})
+})
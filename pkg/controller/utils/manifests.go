@@ -0,0 +1,177 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const (
+	// ManifestsSecretPrefix prefixes the name of the Secrets of the operator namespace holding the manifests of the
+	// components, which are Secrets since the components render Secrets.
+	ManifestsSecretPrefix = "tigera-manifests-"
+
+	// ManifestsCreateKey and ManifestsDeleteKey are the keys of the manifests Secrets holding the objects to apply and
+	// the objects to delete, as multi-document YAML.
+	ManifestsCreateKey = "create.yaml"
+	ManifestsDeleteKey = "delete.yaml"
+)
+
+// ManifestExporter exports the manifests rendered by the components in the manifests only mode, in which the
+// operator doesn't apply them, e.g. for a GitOps pipeline to own the apply step.
+type ManifestExporter interface {
+	// Export exports the manifests of the given component: the YAML of the objects to apply, and of the objects to
+	// delete.
+	Export(ctx context.Context, component string, toCreate, toDelete []byte) error
+}
+
+var (
+	manifestExporterLock sync.RWMutex
+	manifestExporter     ManifestExporter
+)
+
+// SetManifestExporter switches the component handlers to the manifests only mode, in which they hand the rendered
+// objects to the given exporter instead of applying them. A nil exporter switches them back to applying the objects.
+func SetManifestExporter(e ManifestExporter) {
+	manifestExporterLock.Lock()
+	defer manifestExporterLock.Unlock()
+	manifestExporter = e
+}
+
+func getManifestExporter() ManifestExporter {
+	manifestExporterLock.RLock()
+	defer manifestExporterLock.RUnlock()
+	return manifestExporter
+}
+
+// NewSecretManifestExporter returns a ManifestExporter writing the manifests of each component in a Secret of the
+// operator namespace, named after the component.
+func NewSecretManifestExporter(cli client.Client) ManifestExporter {
+	return &secretManifestExporter{client: cli}
+}
+
+type secretManifestExporter struct {
+	client client.Client
+}
+
+func (e *secretManifestExporter) Export(ctx context.Context, component string, toCreate, toDelete []byte) error {
+	secret := &v1.Secret{}
+	key := types.NamespacedName{Name: ManifestsSecretPrefix + component, Namespace: common.OperatorNamespace()}
+	err := e.client.Get(ctx, key, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	data := map[string][]byte{ManifestsCreateKey: toCreate, ManifestsDeleteKey: toDelete}
+	if apierrors.IsNotFound(err) {
+		return e.client.Create(ctx, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    withOperatorManagedLabel(nil),
+			},
+			Data: data,
+		})
+	}
+	if reflect.DeepEqual(secret.Data, data) && secret.Labels[common.OperatorManagedLabel] == "true" {
+		return nil
+	}
+	secret.Data = data
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[common.OperatorManagedLabel] = "true"
+	return e.client.Update(ctx, secret)
+}
+
+// componentName returns the name of a component for its manifests and compliance report. The name is keyed by the
+// owner of the component, e.g. manager-render-manager for the managerComponent of the render package owned by the
+// Manager, since the controllers render components of the same type, e.g. passthroughs. The components of the same
+// type rendered by one handler are numbered in the order they're rendered, e.g. manager-render-passthrough-2.
+func (c componentHandler) componentName(component render.Component, objsToCreate, objsToDelete []client.Object) string {
+	owner := "unowned"
+	if c.cr != nil {
+		owner = typeName(c.cr)
+		if ns := c.cr.GetNamespace(); ns != "" {
+			owner += "-" + ns
+		}
+	} else if len(objsToCreate) > 0 {
+		// Without an owner, the components are told apart by the kind of the objects they render, e.g. the CRDs.
+		owner += "-" + typeName(objsToCreate[0])
+	} else if len(objsToDelete) > 0 {
+		owner += "-" + typeName(objsToDelete[0])
+	}
+
+	t := reflect.TypeOf(component)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name := owner + "-" + path.Base(t.PkgPath()) + "-" + strings.TrimSuffix(t.Name(), "Component")
+	name = strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+
+	// A component handled again, e.g. when a reconcile is retried with the same handler, keeps its number.
+	n := -1
+	for i, rendered := range c.names[name] {
+		if reflect.TypeOf(component).Comparable() && rendered == component {
+			n = i
+		}
+	}
+	if n < 0 {
+		n = len(c.names[name])
+		c.names[name] = append(c.names[name], component)
+	}
+	if n > 0 {
+		name += "-" + strconv.Itoa(n+1)
+	}
+	return name
+}
+
+// typeName returns the name of the type of the given object, e.g. manager for an operatorv1.Manager.
+func typeName(obj interface{}) string {
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return strings.ToLower(t.Name())
+}
+
+// marshalManifests returns the given objects as multi-document YAML.
+func marshalManifests(objs []client.Object) ([]byte, error) {
+	var manifests bytes.Buffer
+	for _, obj := range objs {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		manifests.WriteString("---\n")
+		manifests.Write(b)
+	}
+	return manifests.Bytes(), nil
+}
@@ -113,17 +113,52 @@ func OverrideInstallationSpec(cfg, override operatorv1.InstallationSpec) operato
 		inst.FlexVolumePath = override.FlexVolumePath
 	}
 
+	switch compareFields(inst.PolicySyncDriver, override.PolicySyncDriver) {
+	case BOnlySet, Different:
+		inst.PolicySyncDriver = override.PolicySyncDriver
+	}
+
+	switch compareFields(inst.KubeletVolumePluginPath, override.KubeletVolumePluginPath) {
+	case BOnlySet, Different:
+		inst.KubeletVolumePluginPath = override.KubeletVolumePluginPath
+	}
+
 	switch compareFields(inst.NodeUpdateStrategy, override.NodeUpdateStrategy) {
 	case BOnlySet, Different:
 		override.NodeUpdateStrategy.DeepCopyInto(&inst.NodeUpdateStrategy)
 	}
 
+	switch compareFields(inst.NodeCanaryRollout, override.NodeCanaryRollout) {
+	case BOnlySet, Different:
+		inst.NodeCanaryRollout = override.NodeCanaryRollout.DeepCopy()
+	}
+
+	switch compareFields(inst.DataplaneMigration, override.DataplaneMigration) {
+	case BOnlySet, Different:
+		inst.DataplaneMigration = override.DataplaneMigration.DeepCopy()
+	}
+
+	switch compareFields(inst.NodeShutdown, override.NodeShutdown) {
+	case BOnlySet, Different:
+		inst.NodeShutdown = override.NodeShutdown.DeepCopy()
+	}
+
+	switch compareFields(inst.DataplaneSync, override.DataplaneSync) {
+	case BOnlySet, Different:
+		inst.DataplaneSync = override.DataplaneSync.DeepCopy()
+	}
+
 	switch compareFields(inst.ComponentResources, override.ComponentResources) {
 	case BOnlySet, Different:
 		inst.ComponentResources = make([]operatorv1.ComponentResource, len(override.ComponentResources))
 		copy(inst.ComponentResources, override.ComponentResources)
 	}
 
+	switch compareFields(inst.NamespaceQuotas, override.NamespaceQuotas) {
+	case BOnlySet, Different:
+		inst.NamespaceQuotas = override.NamespaceQuotas
+	}
+
 	switch compareFields(inst.TyphaAffinity, override.TyphaAffinity) {
 	case BOnlySet, Different:
 		inst.TyphaAffinity = override.TyphaAffinity
@@ -141,6 +176,36 @@ func OverrideInstallationSpec(cfg, override operatorv1.InstallationSpec) operato
 		inst.NonPrivileged = override.NonPrivileged
 	}
 
+	switch compareFields(inst.Hardening, override.Hardening) {
+	case BOnlySet, Different:
+		inst.Hardening = override.Hardening
+	}
+
+	switch compareFields(inst.NodeInit, override.NodeInit) {
+	case BOnlySet, Different:
+		inst.NodeInit = override.NodeInit.DeepCopy()
+	}
+
+	switch compareFields(inst.KubeControllers, override.KubeControllers) {
+	case BOnlySet, Different:
+		inst.KubeControllers = override.KubeControllers.DeepCopy()
+	}
+
+	switch compareFields(inst.UsageReporting, override.UsageReporting) {
+	case BOnlySet, Different:
+		inst.UsageReporting = override.UsageReporting
+	}
+
+	switch compareFields(inst.KubernetesServiceEndpoint, override.KubernetesServiceEndpoint) {
+	case BOnlySet, Different:
+		inst.KubernetesServiceEndpoint = override.KubernetesServiceEndpoint.DeepCopy()
+	}
+
+	switch compareFields(inst.ConnectivityTest, override.ConnectivityTest) {
+	case BOnlySet, Different:
+		inst.ConnectivityTest = override.ConnectivityTest.DeepCopy()
+	}
+
 	return inst
 }
 
@@ -176,6 +241,11 @@ func mergeCNISpecs(cfg, override *operatorv1.CNISpec) *operatorv1.CNISpec {
 		out.IPAM = override.IPAM.DeepCopy()
 	}
 
+	switch compareFields(out.Chaining, override.Chaining) {
+	case BOnlySet, Different:
+		out.Chaining = override.Chaining
+	}
+
 	return out
 }
 
@@ -187,6 +257,29 @@ func mergeCalicoNetwork(cfg, override *operatorv1.CalicoNetworkSpec) *operatorv1
 		out.BGP = override.BGP
 	}
 
+	switch compareFields(out.RouteReflectors, override.RouteReflectors) {
+	case BOnlySet, Different:
+		out.RouteReflectors = override.RouteReflectors
+	}
+
+	switch compareFields(out.ServiceAdvertisement, override.ServiceAdvertisement) {
+	case BOnlySet, Different:
+		out.ServiceAdvertisement = override.ServiceAdvertisement
+	}
+
+	switch compareFields(out.BGPGracefulRestart, override.BGPGracefulRestart) {
+	case BOnlySet, Different:
+		out.BGPGracefulRestart = override.BGPGracefulRestart
+	}
+
+	switch compareFields(out.BGPPeeringTemplates, override.BGPPeeringTemplates) {
+	case BOnlySet, Different:
+		out.BGPPeeringTemplates = make([]operatorv1.BGPPeeringTemplate, len(override.BGPPeeringTemplates))
+		for i := range override.BGPPeeringTemplates {
+			override.BGPPeeringTemplates[i].DeepCopyInto(&out.BGPPeeringTemplates[i])
+		}
+	}
+
 	switch compareFields(out.IPPools, override.IPPools) {
 	case BOnlySet, Different:
 		out.IPPools = make([]operatorv1.IPPool, len(override.IPPools))
@@ -205,6 +298,27 @@ func mergeCalicoNetwork(cfg, override *operatorv1.CalicoNetworkSpec) *operatorv1
 		out.LinuxDataplane = override.LinuxDataplane
 	}
 
+	switch compareFields(out.VPPProfiles, override.VPPProfiles) {
+	case BOnlySet, Different:
+		out.VPPProfiles = make([]operatorv1.VPPProfile, len(override.VPPProfiles))
+		for i := range override.VPPProfiles {
+			override.VPPProfiles[i].DeepCopyInto(&out.VPPProfiles[i])
+		}
+	}
+
+	switch compareFields(out.VPPTunnels, override.VPPTunnels) {
+	case BOnlySet, Different:
+		out.VPPTunnels = make([]operatorv1.VPPTunnel, len(override.VPPTunnels))
+		for i := range override.VPPTunnels {
+			override.VPPTunnels[i].DeepCopyInto(&out.VPPTunnels[i])
+		}
+	}
+
+	switch compareFields(out.VPPNAT64, override.VPPNAT64) {
+	case BOnlySet, Different:
+		out.VPPNAT64 = override.VPPNAT64.DeepCopy()
+	}
+
 	switch compareFields(out.NodeAddressAutodetectionV4, override.NodeAddressAutodetectionV4) {
 	case BOnlySet, Different:
 		out.NodeAddressAutodetectionV4 = override.NodeAddressAutodetectionV4
@@ -229,5 +343,15 @@ func mergeCalicoNetwork(cfg, override *operatorv1.CalicoNetworkSpec) *operatorv1
 	case BOnlySet, Different:
 		out.ContainerIPForwarding = override.ContainerIPForwarding
 	}
+
+	switch compareFields(out.HostIPForwarding, override.HostIPForwarding) {
+	case BOnlySet, Different:
+		out.HostIPForwarding = override.HostIPForwarding
+	}
+
+	switch compareFields(out.AWSSecondaryIPs, override.AWSSecondaryIPs) {
+	case BOnlySet, Different:
+		out.AWSSecondaryIPs = override.AWSSecondaryIPs.DeepCopy()
+	}
 	return out
 }
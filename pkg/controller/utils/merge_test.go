@@ -407,6 +407,31 @@ var _ = Describe("Installation merge tests", func() {
 			Entry("Both set not matching", &_cipfE, &_cipfD, &_cipfD),
 		)
 
+		_hipfE := opv1.HostIPForwardingEnabled
+		_hipfD := opv1.HostIPForwardingDisabled
+		DescribeTable("merge HostIPForwarding", func(main, second, expect *opv1.HostIPForwardingType) {
+			m := opv1.InstallationSpec{}
+			s := opv1.InstallationSpec{}
+			if main != nil {
+				m.CalicoNetwork = &opv1.CalicoNetworkSpec{HostIPForwarding: main}
+			}
+			if second != nil {
+				s.CalicoNetwork = &opv1.CalicoNetworkSpec{HostIPForwarding: second}
+			}
+			inst := OverrideInstallationSpec(m, s)
+			if expect == nil {
+				Expect(inst.CalicoNetwork).To(BeNil())
+			} else {
+				Expect(*inst.CalicoNetwork.HostIPForwarding).To(Equal(*expect))
+			}
+		},
+			Entry("Both unset", nil, nil, nil),
+			Entry("Main only set", &_hipfE, nil, &_hipfE),
+			Entry("Second only set", nil, &_hipfD, &_hipfD),
+			Entry("Both set equal", &_hipfE, &_hipfE, &_hipfE),
+			Entry("Both set not matching", &_hipfE, &_hipfD, &_hipfD),
+		)
+
 		DescribeTable("merge ControlPlaneNodeSelector", func(main, second, expect map[string]string) {
 			m := opv1.InstallationSpec{}
 			s := opv1.InstallationSpec{}
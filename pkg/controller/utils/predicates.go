@@ -0,0 +1,64 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+// PrimaryResourceChangedPredicate filters out the updates of a primary resource that only change its status, so that the
+// reconciles don't trigger themselves. The changes of the spec bump the generation, while the changes of the
+// annotations and labels, which some controllers read as triggers, don't and are let through as well.
+func PrimaryResourceChangedPredicate() predicate.Predicate {
+	return predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{})
+}
+
+// ContentChangedPredicate filters out the updates of Secrets and ConfigMaps that leave their contents, labels and
+// annotations unchanged, e.g. resyncs or changes of their owner references or managed fields. The updates of other
+// objects are not filtered.
+type ContentChangedPredicate struct {
+	predicate.Funcs
+}
+
+func (ContentChangedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return true
+	}
+	oldHash, ok := contentHash(e.ObjectOld)
+	if !ok {
+		return true
+	}
+	newHash, _ := contentHash(e.ObjectNew)
+	return oldHash != newHash
+}
+
+// contentHash returns the hash of the contents, labels and annotations of the object if it is a Secret or a ConfigMap.
+func contentHash(obj client.Object) (string, bool) {
+	var content interface{}
+	switch o := obj.(type) {
+	case *v1.Secret:
+		content = []interface{}{o.Type, o.Data}
+	case *v1.ConfigMap:
+		content = []interface{}{o.Data, o.BinaryData}
+	default:
+		return "", false
+	}
+	return rmeta.AnnotationHash([]interface{}{obj.GetLabels(), obj.GetAnnotations(), content}), true
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+var _ = Describe("ContentChangedPredicate", func() {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "ns", ResourceVersion: "1", Labels: map[string]string{"a": "b"}},
+		Data:       map[string][]byte{"key": []byte("value")},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "configmap", Namespace: "ns", ResourceVersion: "1"},
+		Data:       map[string]string{"key": "value"},
+	}
+
+	DescribeTable("should only let the updates of the contents through", func(oldObj client.Object, update func(client.Object), expected bool) {
+		newObj := oldObj.DeepCopyObject().(client.Object)
+		newObj.SetResourceVersion("2")
+		update(newObj)
+		Expect(ContentChangedPredicate{}.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj})).To(Equal(expected))
+	},
+		Entry("secret resync", secret, func(client.Object) {}, false),
+		Entry("secret owner change", secret, func(o client.Object) {
+			o.SetOwnerReferences([]metav1.OwnerReference{{Name: "owner"}})
+		}, false),
+		Entry("secret data change", secret, func(o client.Object) {
+			o.(*corev1.Secret).Data["key"] = []byte("other")
+		}, true),
+		Entry("secret label change", secret, func(o client.Object) {
+			o.SetLabels(map[string]string{"a": "c"})
+		}, true),
+		Entry("configmap resync", configMap, func(client.Object) {}, false),
+		Entry("configmap data change", configMap, func(o client.Object) {
+			o.(*corev1.ConfigMap).Data["key"] = "other"
+		}, true),
+		Entry("other object", &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "ns"}}, func(client.Object) {}, true),
+	)
+})
+
+var _ = Describe("PrimaryResourceChangedPredicate", func() {
+	installation := &operatorv1.Installation{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", ResourceVersion: "1", Generation: 1},
+	}
+
+	DescribeTable("should ignore the status only updates", func(update func(*operatorv1.Installation), expected bool) {
+		newObj := installation.DeepCopy()
+		newObj.SetResourceVersion("2")
+		update(newObj)
+		Expect(PrimaryResourceChangedPredicate().Update(event.UpdateEvent{ObjectOld: installation, ObjectNew: newObj})).To(Equal(expected))
+	},
+		Entry("status update", func(o *operatorv1.Installation) { o.Status.MTU = 1410 }, false),
+		Entry("spec update", func(o *operatorv1.Installation) { o.Generation = 2 }, true),
+		Entry("annotation update", func(o *operatorv1.Installation) {
+			o.Annotations = map[string]string{"operator.tigera.io/trigger": "true"}
+		}, true),
+		Entry("label update", func(o *operatorv1.Installation) { o.Labels = map[string]string{"a": "b"} }, true),
+	)
+})
@@ -0,0 +1,199 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	// The depth of the work queue of each controller is reported by controller-runtime as workqueue_depth. These
+	// report the reconciles the controllers pulled from their work queues, which wait for a slot of the scheduler.
+	reconcileWaiting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tigera_operator_reconciles_waiting",
+		Help: "Number of reconciles of the controller waiting for a reconcile slot.",
+	}, []string{"controller"})
+	reconcileWaitSeconds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tigera_operator_reconcile_wait_seconds_total",
+		Help: "Total time the reconciles of the controller waited for a reconcile slot.",
+	}, []string{"controller"})
+	reconcileBusySeconds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tigera_operator_reconcile_busy_seconds_total",
+		Help: "Total time the controller held a reconcile slot.",
+	}, []string{"controller"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileWaiting, reconcileWaitSeconds, reconcileBusySeconds)
+}
+
+// DefaultReconcileSlots is the default number of slots of the scheduler. Each controller runs one reconcile at a
+// time, so a controller holds at most one slot and the slots only queue reconciles when more controllers than this
+// reconcile at once, e.g. on a change of the Installation which all of them watch.
+const DefaultReconcileSlots = 8
+
+var (
+	reconcileSchedulerLock sync.RWMutex
+	reconcileScheduler     *ReconcileScheduler
+)
+
+// SetReconcileScheduler makes the reconcilers returned by FairReconciler take turns on the slots of the given
+// scheduler. A nil scheduler lets them reconcile at once.
+func SetReconcileScheduler(s *ReconcileScheduler) {
+	reconcileSchedulerLock.Lock()
+	defer reconcileSchedulerLock.Unlock()
+	reconcileScheduler = s
+}
+
+func getReconcileScheduler() *ReconcileScheduler {
+	reconcileSchedulerLock.RLock()
+	defer reconcileSchedulerLock.RUnlock()
+	return reconcileScheduler
+}
+
+// FairReconciler returns a reconciler running the reconciles of r, the reconciler of the given controller, on the
+// slots of the scheduler set with SetReconcileScheduler, if any.
+func FairReconciler(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &fairReconciler{controller: controller, reconciler: r}
+}
+
+type fairReconciler struct {
+	controller string
+	reconciler reconcile.Reconciler
+}
+
+func (f *fairReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	s := getReconcileScheduler()
+	if s == nil {
+		return f.reconciler.Reconcile(ctx, request)
+	}
+	if err := s.Acquire(ctx, f.controller); err != nil {
+		return reconcile.Result{}, err
+	}
+	start := time.Now()
+	defer func() { s.Release(f.controller, time.Since(start)) }()
+	return f.reconciler.Reconcile(ctx, request)
+}
+
+// ReconcileScheduler shares a number of reconcile slots between the controllers, so that a controller reconciling
+// continuously, e.g. on the churn of its resources, doesn't starve the other controllers of the API server requests
+// they share. The slots are time-sliced between the controllers: a freed slot goes to the waiting controller which
+// held the slots the least time, and a controller which was idle starts from the time of the last controller given a
+// slot, so that it can't bank time while idle.
+type ReconcileScheduler struct {
+	lock  sync.Mutex
+	slots int
+	// busy is the total time each controller held the slots.
+	busy map[string]time.Duration
+	// floor is the busy time of the last controller given a slot.
+	floor time.Duration
+	// waiting are the reconciles of each controller waiting for a slot, in order.
+	waiting map[string][]chan struct{}
+}
+
+// NewReconcileScheduler returns a scheduler of the given number of slots.
+func NewReconcileScheduler(slots int) *ReconcileScheduler {
+	return &ReconcileScheduler{
+		slots:   slots,
+		busy:    map[string]time.Duration{},
+		waiting: map[string][]chan struct{}{},
+	}
+}
+
+// Acquire waits for a slot for a reconcile of the given controller. It returns the error of the context if it is done
+// first.
+func (s *ReconcileScheduler) Acquire(ctx context.Context, controller string) error {
+	s.lock.Lock()
+	if len(s.waiting[controller]) == 0 && s.busy[controller] < s.floor {
+		s.busy[controller] = s.floor
+	}
+	if s.slots > 0 && s.numWaiting() == 0 {
+		s.slots--
+		s.floor = s.busy[controller]
+		s.lock.Unlock()
+		return nil
+	}
+	granted := make(chan struct{})
+	s.waiting[controller] = append(s.waiting[controller], granted)
+	reconcileWaiting.WithLabelValues(controller).Inc()
+	s.lock.Unlock()
+
+	start := time.Now()
+	defer func() { reconcileWaitSeconds.WithLabelValues(controller).Add(time.Since(start).Seconds()) }()
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i, ch := range s.waiting[controller] {
+		if ch == granted {
+			s.waiting[controller] = append(s.waiting[controller][:i], s.waiting[controller][i+1:]...)
+			reconcileWaiting.WithLabelValues(controller).Dec()
+			return ctx.Err()
+		}
+	}
+	// The slot was granted along with the context being done, hand it over.
+	s.next()
+	return ctx.Err()
+}
+
+// Release frees the slot held for the given time by a reconcile of the given controller.
+func (s *ReconcileScheduler) Release(controller string, held time.Duration) {
+	reconcileBusySeconds.WithLabelValues(controller).Add(held.Seconds())
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.busy[controller] += held
+	s.next()
+}
+
+// next gives a freed slot to the waiting controller which held the slots the least time, or keeps it free if no
+// controller is waiting. It must be called with the lock held.
+func (s *ReconcileScheduler) next() {
+	var controller string
+	for c, waiting := range s.waiting {
+		if len(waiting) == 0 {
+			continue
+		}
+		if controller == "" || s.busy[c] < s.busy[controller] || (s.busy[c] == s.busy[controller] && c < controller) {
+			controller = c
+		}
+	}
+	if controller == "" {
+		s.slots++
+		return
+	}
+	granted := s.waiting[controller][0]
+	s.waiting[controller] = s.waiting[controller][1:]
+	reconcileWaiting.WithLabelValues(controller).Dec()
+	s.floor = s.busy[controller]
+	close(granted)
+}
+
+func (s *ReconcileScheduler) numWaiting() int {
+	n := 0
+	for _, waiting := range s.waiting {
+		n += len(waiting)
+	}
+	return n
+}
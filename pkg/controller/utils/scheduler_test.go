@@ -0,0 +1,166 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconcile scheduler", func() {
+	var s *ReconcileScheduler
+	var ctx context.Context
+
+	BeforeEach(func() {
+		s = NewReconcileScheduler(1)
+		ctx = context.Background()
+	})
+
+	waiting := func() int {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return s.numWaiting()
+	}
+
+	// acquire acquires a slot for the controller in the background, and sends the controller on granted once it has
+	// a slot.
+	acquire := func(controller string, granted chan<- string) {
+		n := waiting()
+		go func() {
+			defer GinkgoRecover()
+			Expect(s.Acquire(ctx, controller)).NotTo(HaveOccurred())
+			granted <- controller
+		}()
+		Eventually(waiting).Should(Equal(n + 1))
+	}
+
+	It("gives a freed slot to the waiting controller which held the slots the least time", func() {
+		Expect(s.Acquire(ctx, "noisy")).NotTo(HaveOccurred())
+		s.Release("noisy", 10*time.Second)
+		Expect(s.Acquire(ctx, "holder")).NotTo(HaveOccurred())
+
+		granted := make(chan string, 2)
+		acquire("noisy", granted)
+		acquire("quiet", granted)
+
+		s.Release("holder", time.Second)
+		Eventually(granted).Should(Receive(Equal("quiet")))
+		Consistently(granted).ShouldNot(Receive())
+
+		s.Release("quiet", time.Second)
+		Eventually(granted).Should(Receive(Equal("noisy")))
+		s.Release("noisy", time.Second)
+
+		// The slot is free again.
+		Expect(s.Acquire(ctx, "quiet")).NotTo(HaveOccurred())
+	})
+
+	It("doesn't let an idle controller bank time", func() {
+		Expect(s.Acquire(ctx, "noisy")).NotTo(HaveOccurred())
+		s.Release("noisy", 10*time.Second)
+		Expect(s.Acquire(ctx, "noisy")).NotTo(HaveOccurred())
+
+		// The idle controller starts from the time of the noisy controller when it was given the slot.
+		granted := make(chan string, 1)
+		acquire("idle", granted)
+		Expect(s.busy["idle"]).To(Equal(10 * time.Second))
+		s.Release("noisy", time.Second)
+		Eventually(granted).Should(Receive(Equal("idle")))
+	})
+
+	It("stops waiting when the context is done", func() {
+		Expect(s.Acquire(ctx, "holder")).NotTo(HaveOccurred())
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(s.Acquire(cctx, "other")).To(MatchError(context.Canceled))
+		Expect(waiting()).To(Equal(0))
+
+		s.Release("holder", time.Second)
+		Expect(s.Acquire(ctx, "other")).NotTo(HaveOccurred())
+	})
+
+	It("runs the reconciles of the controllers on the slots of the scheduler", func() {
+		SetReconcileScheduler(s)
+		defer SetReconcileScheduler(nil)
+
+		r := FairReconciler("test-controller", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			// The reconcile holds the only slot.
+			cctx, cancel := context.WithCancel(ctx)
+			cancel()
+			Expect(s.Acquire(cctx, "other")).To(MatchError(context.Canceled))
+			return reconcile.Result{}, nil
+		}))
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.busy).To(HaveKey("test-controller"))
+		Expect(s.Acquire(ctx, "other")).NotTo(HaveOccurred())
+	})
+	It("doesn't let a busy controller starve another with the default slots", func() {
+		s = NewReconcileScheduler(DefaultReconcileSlots)
+		SetReconcileScheduler(s)
+		defer SetReconcileScheduler(nil)
+
+		// The busy controller reconciles continuously on more workers than there are slots, so that its reconciles
+		// always wait for the slots.
+		var started int64
+		busy := FairReconciler("busy", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			atomic.AddInt64(&started, 1)
+			time.Sleep(time.Millisecond)
+			return reconcile.Result{}, nil
+		}))
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < 4*DefaultReconcileSlots; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					_, err := busy.Reconcile(ctx, reconcile.Request{})
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}()
+		}
+		defer wg.Wait()
+		defer close(stop)
+		Eventually(waiting).Should(BeNumerically(">", 0))
+
+		// The reconciles of the other controller get one of the next freed slots, instead of waiting behind the
+		// reconciles of the busy controller.
+		for i := 0; i < 5; i++ {
+			var waited int64
+			before := atomic.LoadInt64(&started)
+			other := FairReconciler("other", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				waited = atomic.LoadInt64(&started) - before
+				return reconcile.Result{}, nil
+			}))
+			_, err := other.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waited).To(BeNumerically("<=", 2*DefaultReconcileSlots))
+		}
+	})
+})
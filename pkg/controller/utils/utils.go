@@ -119,8 +119,28 @@ func AddServiceWatch(c controller.Controller, name, namespace string) error {
 	})
 }
 
-func WaitToAddLicenseKeyWatch(controller controller.Controller, client kubernetes.Interface, log logr.Logger, flag *ReadyFlag) {
-	WaitToAddResourceWatch(controller, client, log, flag, &v3.LicenseKey{TypeMeta: metav1.TypeMeta{Kind: v3.KindLicenseKey}})
+// AddKubernetesVersionWatch enqueues the request of the given key whenever the version of the Kubernetes API server
+// changes, so that the rendering depending on the version is re-evaluated after an upgrade of the cluster.
+func AddKubernetesVersionWatch(c controller.Controller, tracker *common.KubernetesVersionTracker, key client.ObjectKey) error {
+	if tracker == nil {
+		return nil
+	}
+	events := make(chan event.GenericEvent, 1)
+	tracker.OnChange(func() {
+		select {
+		case events <- event.GenericEvent{Object: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}}:
+		default:
+			// A reconcile is already pending.
+		}
+	})
+	return c.Watch(&source.Channel{Source: events}, &handler.EnqueueRequestForObject{})
+}
+
+// WaitToAddLicenseKeyWatch adds a watch on the LicenseKey once its API is available. The given cache is invalidated on
+// every LicenseKey event.
+func WaitToAddLicenseKeyWatch(controller controller.Controller, client kubernetes.Interface, log logr.Logger, flag *ReadyFlag, cache *LicenseKeyCache) {
+	waitToAddWatch(controller, client, log, flag, &v3.LicenseKey{TypeMeta: metav1.TypeMeta{Kind: v3.KindLicenseKey}},
+		&licenseKeyEventHandler{cache: cache})
 }
 
 // AddNamespacedWatch creates a watch on the given object. If a name and namespace are provided, then it will
@@ -151,7 +171,7 @@ func AddNamespacedWatch(c controller.Controller, obj client.Object, metaMatches
 			return e.Object.GetNamespace() == objMeta.GetNamespace()
 		},
 	}
-	return c.Watch(&source.Kind{Type: obj}, &handler.EnqueueRequestForObject{}, pred)
+	return c.Watch(&source.Kind{Type: obj}, &handler.EnqueueRequestForObject{}, pred, ContentChangedPredicate{})
 }
 
 func IsAPIServerReady(client client.Client, l logr.Logger) bool {
@@ -197,6 +217,19 @@ func GetLogCollector(ctx context.Context, cli client.Client) (*operatorv1.LogCol
 	return logCollector, nil
 }
 
+// GetVPPDataplane returns the VPPDataplane, or nil if it doesn't exist.
+func GetVPPDataplane(ctx context.Context, cli client.Client) (*operatorv1.VPPDataplane, error) {
+	vpp := &operatorv1.VPPDataplane{}
+	err := cli.Get(ctx, DefaultInstanceKey, vpp)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return vpp, nil
+}
+
 // FetchLicenseKey returns the license if it has been installed. It's useful
 // to prevent rollout of TSEE components that might require it.
 // It will return an error if the license is not installed/cannot be read
@@ -426,6 +459,10 @@ func StrToElasticLicenseType(license string, logger logr.Logger) render.Elastics
 // WaitToAddResourceWatch will check if projectcalico.org APIs are available and if so, it will add a watch for resource
 // The completion of this operation will be signaled on a ready channel
 func WaitToAddResourceWatch(controller controller.Controller, client kubernetes.Interface, log logr.Logger, flag *ReadyFlag, obj client.Object) {
+	waitToAddWatch(controller, client, log, flag, obj, &handler.EnqueueRequestForObject{})
+}
+
+func waitToAddWatch(controller controller.Controller, client kubernetes.Interface, log logr.Logger, flag *ReadyFlag, obj client.Object, h handler.EventHandler) {
 	maxDuration := 30 * time.Second
 	duration := 1 * time.Second
 	ticker := time.NewTicker(duration)
@@ -439,7 +476,7 @@ func WaitToAddResourceWatch(controller controller.Controller, client kubernetes.
 			}
 			ticker.Reset(duration)
 			if isResourceReady(client, obj.GetObjectKind().GroupVersionKind().Kind) {
-				err := controller.Watch(&source.Kind{Type: obj}, &handler.EnqueueRequestForObject{})
+				err := controller.Watch(&source.Kind{Type: obj}, h)
 				if err != nil {
 					log.Info("failed to watch %s resource: %v. Will retry to add watch", obj.GetObjectKind().GroupVersionKind().Kind, err)
 				} else {
@@ -183,6 +183,58 @@ var _ = Describe("Utils APIServer type tests", func() {
 	)
 })
 
+var _ = Describe("Component handler diff tests", func() {
+	It("should only report the fields the operator changes", func() {
+		current := &apps.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:          "test",
+				Namespace:     "test-ns",
+				Generation:    3,
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			},
+			Spec:   apps.DeploymentSpec{MinReadySeconds: 1},
+			Status: apps.DeploymentStatus{ReadyReplicas: 1},
+		}
+		desired := &apps.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+			Spec:       apps.DeploymentSpec{MinReadySeconds: 2},
+		}
+
+		d := objectDiff(current, desired)
+		Expect(d).To(ContainSubstring("MinReadySeconds"))
+		Expect(d).NotTo(ContainSubstring("ManagedFields"))
+		Expect(d).NotTo(ContainSubstring("Generation"))
+		Expect(d).NotTo(ContainSubstring("ReadyReplicas"))
+
+		// The current object is left untouched.
+		Expect(current.Status.ReadyReplicas).To(Equal(int32(1)))
+		Expect(objectDiff(desired, desired)).To(Equal("<no diffs>"))
+	})
+
+	It("should only report the keys of the Secret data", func() {
+		current := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+			Data:       map[string][]byte{"tls.crt": []byte("old-cert"), "tls.key": []byte("the-key"), "ca.crt": []byte("old-ca")},
+		}
+		desired := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+			Data:       map[string][]byte{"tls.crt": []byte("new-cert"), "tls.key": []byte("the-key"), "token": []byte("new-token")},
+		}
+
+		d := objectDiff(current, desired)
+		for _, k := range []string{"tls.crt", "ca.crt", "token"} {
+			Expect(d).To(ContainSubstring(k))
+		}
+		for _, v := range []string{"old-cert", "new-cert", "the-key", "old-ca", "new-token"} {
+			Expect(d).NotTo(ContainSubstring(v))
+		}
+
+		// The Secrets are left untouched.
+		Expect(current.Data["tls.crt"]).To(Equal([]byte("old-cert")))
+		Expect(desired.Data["tls.crt"]).To(Equal([]byte("new-cert")))
+	})
+})
+
 type fakeClient struct {
 	discovery discovery.DiscoveryInterface
 	kubernetes.Interface
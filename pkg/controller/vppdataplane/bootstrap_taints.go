@@ -0,0 +1,106 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vppdataplane
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// vppBootstrappedAnnotation is set on the nodes whose dataplane was ready once, so that they aren't tainted again when
+// their calico-vpp-node pod restarts.
+const vppBootstrappedAnnotation = "operator.tigera.io/vpp-dataplane-bootstrapped"
+
+// syncBootstrapTaints taints the nodes of the VPP dataplane, as selected by the given node selector, whose
+// calico-vpp-node pod was never ready, and removes the taint once it is. With a nil selector, e.g. when the VPPDataplane
+// is deleted, it only removes the taint, so that no node is left unschedulable.
+func syncBootstrapTaints(ctx context.Context, cli client.Client, selector labels.Selector) error {
+	nodes := &corev1.NodeList{}
+	if err := cli.List(ctx, nodes); err != nil {
+		return err
+	}
+	ready := map[string]bool{}
+	if selector != nil {
+		pods := &corev1.PodList{}
+		if err := cli.List(ctx, pods, client.InNamespace(common.CalicoNamespace), client.HasLabels{render.VPPNodePodLabel}); err != nil {
+			return err
+		}
+		for i := range pods.Items {
+			if podReady(&pods.Items[i]) {
+				ready[pods.Items[i].Spec.NodeName] = true
+			}
+		}
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		// The nodes are patched rather than updated, so that only the taints and the annotation are written. The
+		// optimistic lock keeps the taints set concurrently, since the patch replaces the whole list.
+		patchFrom := client.MergeFromWithOptions(node.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		vpp := selector != nil && selector.Matches(labels.Set(node.Labels))
+		tainted := bootstrapTainted(node)
+		_, bootstrapped := node.Annotations[vppBootstrappedAnnotation]
+
+		switch {
+		case vpp && !bootstrapped && !ready[node.Name]:
+			if tainted {
+				continue
+			}
+			log.Info("Tainting node until its VPP dataplane is ready", "node", node.Name)
+			node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{Key: common.VPPBootstrapTaintKey, Effect: corev1.TaintEffectNoSchedule})
+		case vpp && !bootstrapped:
+			log.Info("VPP dataplane of the node is ready", "node", node.Name)
+			if node.Annotations == nil {
+				node.Annotations = map[string]string{}
+			}
+			node.Annotations[vppBootstrappedAnnotation] = "true"
+			node.Spec.Taints = removeBootstrapTaint(node.Spec.Taints)
+		case tainted:
+			node.Spec.Taints = removeBootstrapTaint(node.Spec.Taints)
+		default:
+			continue
+		}
+		if err := cli.Patch(ctx, node, patchFrom); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bootstrapTainted returns whether the node is tainted until its VPP dataplane is ready.
+func bootstrapTainted(node *corev1.Node) bool {
+	for _, t := range node.Spec.Taints {
+		if t.Key == common.VPPBootstrapTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+func removeBootstrapTaint(taints []corev1.Taint) []corev1.Taint {
+	var kept []corev1.Taint
+	for _, t := range taints {
+		if t.Key != common.VPPBootstrapTaintKey {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vppdataplane
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// maxNodeStatuses is the maximum number of nodes listed in the status of the VPPDataplane, so that its size doesn't
+// grow with the cluster.
+const maxNodeStatuses = 100
+
+// vppNodes returns the metadata of the nodes of the VPP dataplane, in the order of their names. Only the metadata of
+// the nodes is cached, since their status changes with every heartbeat.
+func vppNodes(ctx context.Context, cli client.Client, installation *operatorv1.InstallationSpec) ([]metav1.PartialObjectMetadata, error) {
+	nodes := &metav1.PartialObjectMetadataList{TypeMeta: metav1.TypeMeta{Kind: "NodeList", APIVersion: "v1"}}
+	if err := cli.List(ctx, nodes, client.MatchingLabels(render.VPPNodeSelector(installation))); err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+	return nodes.Items, nil
+}
+
+// hugepagesStatus returns the readiness of the hugepages of the nodes of the VPP dataplane, as reported by the
+// readiness of the calico-vpp-hugepages pod of each node, or nil if the hugepages are not provisioned. The nodes whose
+// hugepages aren't ready come first, and at most maxNodeStatuses nodes are returned.
+func hugepagesStatus(ctx context.Context, cli client.Client, instance *operatorv1.VPPDataplane, installation *operatorv1.InstallationSpec) ([]operatorv1.NodeHugepagesStatus, error) {
+	h := instance.Spec.Hugepages
+	if h == nil {
+		return nil, nil
+	}
+
+	nodes, err := vppNodes(ctx, cli, installation)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(common.CalicoNamespace), client.MatchingLabels{"k8s-app": render.VPPHugepagesName}); err != nil {
+		return nil, err
+	}
+	podsByNode := map[string]*corev1.Pod{}
+	for i := range pods.Items {
+		podsByNode[pods.Items[i].Spec.NodeName] = &pods.Items[i]
+	}
+
+	size := operatorv1.VPPHugepageSize2Mi
+	if h.Size != nil {
+		size = *h.Size
+	}
+	st := []operatorv1.NodeHugepagesStatus{}
+	for _, node := range nodes {
+		ns := operatorv1.NodeHugepagesStatus{Node: node.Name}
+		pod, ok := podsByNode[node.Name]
+		switch {
+		case !ok:
+			ns.Message = fmt.Sprintf("waiting for the %s pod of the node", render.VPPHugepagesName)
+		case podReady(pod):
+			ns.Ready = true
+		default:
+			ns.Message = fmt.Sprintf("waiting for %d hugepages of %s to be reserved", h.Count, size)
+		}
+		st = append(st, ns)
+	}
+	sort.SliceStable(st, func(i, j int) bool { return !st[i].Ready && st[j].Ready })
+	if len(st) > maxNodeStatuses {
+		st = st[:maxNodeStatuses]
+	}
+	return st, nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
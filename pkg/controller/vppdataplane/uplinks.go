@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vppdataplane
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// uplinkStatus returns the migration status of the uplink interfaces of the nodes of the VPP dataplane, or nil if the
+// uplink interfaces are not set. The VPP manager moves the addresses and the routes of all the uplinks to VPP before
+// it starts VPP, so the uplinks of a node are migrated once its calico-vpp-node pod is ready. The nodes whose uplinks
+// aren't migrated come first, and at most maxNodeStatuses nodes are returned.
+func uplinkStatus(ctx context.Context, cli client.Client, instance *operatorv1.VPPDataplane, installation *operatorv1.InstallationSpec) ([]operatorv1.NodeUplinkStatus, error) {
+	if len(instance.Spec.UplinkInterfaces) == 0 {
+		return nil, nil
+	}
+
+	nodes, err := vppNodes(ctx, cli, installation)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(common.CalicoNamespace), client.HasLabels{render.VPPNodePodLabel}); err != nil {
+		return nil, err
+	}
+	podsByNode := map[string]*corev1.Pod{}
+	for i := range pods.Items {
+		podsByNode[pods.Items[i].Spec.NodeName] = &pods.Items[i]
+	}
+
+	var names []string
+	for _, u := range instance.Spec.UplinkInterfaces {
+		names = append(names, render.VPPUplinkName(u))
+	}
+	st := []operatorv1.NodeUplinkStatus{}
+	for _, node := range nodes {
+		us := operatorv1.NodeUplinkStatus{Node: node.Name}
+		pod, ok := podsByNode[node.Name]
+		switch {
+		case !ok:
+			us.Message = fmt.Sprintf("waiting for the %s pod of the node", render.VPPNodeName)
+		case podReady(pod):
+			us.Ready = true
+		default:
+			us.Message = fmt.Sprintf("waiting for the addresses and the routes of %s to be migrated to VPP", strings.Join(names, ", "))
+		}
+		st = append(st, us)
+	}
+	sort.SliceStable(st, func(i, j int) bool { return !st[i].Ready && st[j].Ready })
+	if len(st) > maxNodeStatuses {
+		st = st[:maxNodeStatuses]
+	}
+	return st, nil
+}
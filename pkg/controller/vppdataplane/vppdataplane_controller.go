@@ -0,0 +1,385 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vppdataplane
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/options"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var log = logf.Log.WithName("controller_vppdataplane")
+
+// Add creates a new VPPDataplane Controller and adds it to the Manager.
+// The Manager will set fields on the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, opts options.AddOptions) error {
+	return add(mgr, newReconciler(mgr, opts))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, opts options.AddOptions) *ReconcileVPPDataplane {
+	r := &ReconcileVPPDataplane{
+		client: mgr.GetClient(),
+		scheme: mgr.GetScheme(),
+		status: status.New(mgr.GetClient(), "vpp-dataplane", opts.KubernetesVersion),
+	}
+	r.status.Run(opts.ShutdownContext)
+	return r
+}
+
+// add adds watches for resources that are available at startup.
+func add(mgr manager.Manager, r *ReconcileVPPDataplane) error {
+	c, err := controller.New("vppdataplane-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("vppdataplane-controller", r)})
+	if err != nil {
+		return fmt.Errorf("Failed to create vppdataplane-controller: %w", err)
+	}
+
+	err = c.Watch(&source.Kind{Type: &operatorv1.VPPDataplane{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("vppdataplane-controller failed to watch primary resource: %w", err)
+	}
+
+	if err = utils.AddNetworkWatch(c); err != nil {
+		return fmt.Errorf("vppdataplane-controller failed to watch Installation resource: %w", err)
+	}
+
+	if err = imageset.AddImageSetWatch(c); err != nil {
+		return fmt.Errorf("vppdataplane-controller failed to watch ImageSet: %w", err)
+	}
+
+	// The labels of the nodes select the nodes of the VPP dataplane, and the bootstrap taint depends on their
+	// annotation. Only the metadata of the nodes is cached, and the heartbeats of the nodes aren't reconciled.
+	err = c.Watch(&source.Kind{Type: &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{Kind: "Node", APIVersion: "v1"},
+	}}, &handler.EnqueueRequestForObject{}, predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))
+	if err != nil {
+		return fmt.Errorf("vppdataplane-controller failed to watch the node resource: %w", err)
+	}
+
+	// The calico-vpp-hugepages pods are ready once the hugepages of their node are reserved, and the calico-vpp-node
+	// pods once the dataplane of their node is.
+	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForObject{}, predicate.NewPredicateFuncs(func(o client.Object) bool {
+		_, vppNode := o.GetLabels()[render.VPPNodePodLabel]
+		return o.GetNamespace() == common.CalicoNamespace && (o.GetLabels()["k8s-app"] == render.VPPHugepagesName || vppNode)
+	}))
+	if err != nil {
+		return fmt.Errorf("vppdataplane-controller failed to watch the %s and %s pods: %w", render.VPPHugepagesName, render.VPPNodeName, err)
+	}
+
+	// The calico-vpp-node pods are restarted when the configuration of the agent rendered by VPPProfiles changes.
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestForObject{}, predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetNamespace() == common.CalicoNamespace && agentConfigMap(o)
+	}))
+	if err != nil {
+		return fmt.Errorf("vppdataplane-controller failed to watch the ConfigMaps of the VPP agent: %w", err)
+	}
+
+	return nil
+}
+
+// agentConfigMap returns whether the object is one of the ConfigMaps rendered by VPPProfiles for the VPP agent.
+func agentConfigMap(o client.Object) bool {
+	if _, ok := o.GetLabels()[render.VPPProfileLabel]; ok {
+		return true
+	}
+	for _, name := range render.VPPAgentConfigMapNames {
+		if o.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Blank assignment to verify that ReconcileVPPDataplane implements reconcile.Reconciler.
+var _ reconcile.Reconciler = &ReconcileVPPDataplane{}
+
+// ReconcileVPPDataplane reconciles the VPPDataplane object.
+type ReconcileVPPDataplane struct {
+	client client.Client
+	scheme *runtime.Scheme
+	status status.StatusManager
+}
+
+// Reconcile renders the calico-vpp-node DaemonSets and the configuration of the VPP agent. The rendered objects are
+// owned by the VPPDataplane, so they are garbage collected with it.
+func (r *ReconcileVPPDataplane) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling VPPDataplane")
+
+	instance := &operatorv1.VPPDataplane{}
+	if err := r.client.Get(ctx, utils.DefaultInstanceKey, instance); err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("VPPDataplane object not found")
+			r.status.OnCRNotFound()
+			// The nodes tainted until their dataplane is ready are untainted along with the VPPDataplane.
+			return reconcile.Result{}, syncBootstrapTaints(ctx, r.client, nil)
+		}
+		r.status.SetDegraded("Error querying VPPDataplane", err.Error())
+		return reconcile.Result{}, err
+	}
+	r.status.OnCRFound()
+
+	variant, installation, err := utils.GetInstallation(ctx, r.client)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.status.SetDegraded("Installation not found", err.Error())
+			return reconcile.Result{}, nil
+		}
+		r.status.SetDegraded("Error querying installation", err.Error())
+		return reconcile.Result{}, err
+	}
+	if variant == "" {
+		r.status.SetDegraded("Waiting for Installation to be ready", "")
+		return reconcile.Result{}, nil
+	}
+	if installation.CalicoNetwork == nil || installation.CalicoNetwork.LinuxDataplane == nil ||
+		*installation.CalicoNetwork.LinuxDataplane != operatorv1.LinuxDataplaneVPP {
+		r.status.SetDegraded(fmt.Sprintf("VPPDataplane is only supported with the %s LinuxDataplane", operatorv1.LinuxDataplaneVPP), "")
+		return reconcile.Result{}, nil
+	}
+	if err = validateVPPDataplane(instance, installation); err != nil {
+		r.status.SetDegraded("Invalid VPPDataplane", err.Error())
+		return reconcile.Result{}, nil
+	}
+
+	cms := &corev1.ConfigMapList{}
+	if err = r.client.List(ctx, cms, client.InNamespace(common.CalicoNamespace)); err != nil {
+		r.status.SetDegraded("Error querying the ConfigMaps of the VPP agent", err.Error())
+		return reconcile.Result{}, err
+	}
+	var agentConfigMaps []corev1.ConfigMap
+	for _, cm := range cms.Items {
+		if agentConfigMap(&cm) {
+			agentConfigMaps = append(agentConfigMaps, cm)
+		}
+	}
+	profileDaemonSets := &appsv1.DaemonSetList{}
+	if err = r.client.List(ctx, profileDaemonSets, client.InNamespace(common.CalicoNamespace), client.HasLabels{render.VPPProfileLabel}); err != nil {
+		r.status.SetDegraded("Error querying the DaemonSets of the VPP profiles", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	component := render.VPPDataplane(&render.VPPDataplaneConfiguration{
+		Installation:      installation,
+		VPPDataplane:      instance,
+		AgentConfigMaps:   agentConfigMaps,
+		ProfileDaemonSets: profileDaemonSets.Items,
+	})
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
+		r.status.SetDegraded("Error with images from ImageSet", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	if err = handler.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
+		r.status.SetDegraded("Error creating / updating resource", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	var bootstrapTaint labels.Selector
+	if t := instance.Spec.BootstrapTaint; t != nil && *t == operatorv1.VPPBootstrapTaintEnabled {
+		bootstrapTaint = labels.SelectorFromSet(render.VPPNodeSelector(installation))
+	}
+	if err = syncBootstrapTaints(ctx, r.client, bootstrapTaint); err != nil {
+		r.status.SetDegraded("Error updating the bootstrap taints of the nodes", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	uplinks, err := uplinkStatus(ctx, r.client, instance, installation)
+	if err != nil {
+		r.status.SetDegraded("Error querying the uplink status of the nodes", err.Error())
+		return reconcile.Result{}, err
+	}
+	hugepages, err := hugepagesStatus(ctx, r.client, instance, installation)
+	if err != nil {
+		r.status.SetDegraded("Error querying the hugepages status of the nodes", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	// Clear the degraded bit if we've reached this far.
+	r.status.ClearDegraded()
+
+	// The status of the nodes is reported while the DaemonSets roll out, e.g. to tell the nodes missing hugepages.
+	instance.Status.Uplinks = uplinks
+	instance.Status.Hugepages = hugepages
+	if !r.status.IsAvailable() {
+		if err = r.client.Status().Update(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+		// Schedule a kick to check again in the near future, hopefully by then things will be available.
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// Everything is available - update the CRD status.
+	instance.Status.State = operatorv1.TigeraStatusReady
+	if err = r.client.Status().Update(ctx, instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// validateVPPRxMode checks that the rx mode of the uplink is supported by its driver, which is the one of the VPP
+// profile of each node, or of the VPPDataplane, when the uplink doesn't set one.
+func validateVPPRxMode(u operatorv1.VPPUplinkInterface, instance *operatorv1.VPPDataplane, installation *operatorv1.InstallationSpec) error {
+	if u.RxMode == nil {
+		return nil
+	}
+	if u.Driver != nil {
+		if !render.VPPRxModeSupported(*u.Driver, *u.RxMode) {
+			return fmt.Errorf("the %s rxMode is not supported by the %s driver", *u.RxMode, *u.Driver)
+		}
+		return nil
+	}
+	if d := instance.Spec.UplinkDriver; d != nil && !render.VPPRxModeSupported(*d, *u.RxMode) {
+		return fmt.Errorf("the %s rxMode is not supported by the %s uplinkDriver", *u.RxMode, *d)
+	}
+	for _, p := range installation.CalicoNetwork.VPPProfiles {
+		if d := p.UplinkDriver; d != nil && !render.VPPRxModeSupported(*d, *u.RxMode) {
+			return fmt.Errorf("the %s rxMode is not supported by the %s uplinkDriver of the VPP profile %s", *u.RxMode, *d, p.Name)
+		}
+	}
+	return nil
+}
+
+// validateVPPGuaranteedResources checks that the calico-vpp-node pods can be Guaranteed, with the given number of CPUs
+// for the vpp container. Their requests are set to their limits, so the memory limit of the vpp container and the CPU
+// and memory limits of the agent container must be set, and the requests which are set must equal the limits.
+func validateVPPGuaranteedResources(instance *operatorv1.VPPDataplane, cpus int64) error {
+	vpp, agent := instance.Spec.VPPResources, instance.Spec.AgentResources
+	if vpp == nil {
+		vpp = &corev1.ResourceRequirements{}
+	}
+	if l, ok := vpp.Limits[corev1.ResourceCPU]; ok && l.Cmp(*resource.NewQuantity(cpus, resource.DecimalSI)) != 0 {
+		return fmt.Errorf("the cpu limit %s of spec.vppResources should be %d, one for the main thread and one for each worker", &l, cpus)
+	}
+	if err := validateGuaranteedResources(*vpp, corev1.ResourceMemory); err != nil {
+		return fmt.Errorf("spec.vppResources %v", err)
+	}
+	if agent == nil {
+		agent = &corev1.ResourceRequirements{}
+	}
+	if err := validateGuaranteedResources(*agent, corev1.ResourceCPU, corev1.ResourceMemory); err != nil {
+		return fmt.Errorf("spec.agentResources %v", err)
+	}
+	return nil
+}
+
+// validateGuaranteedResources checks that the given limits are set, and that the requests equal the limits.
+func validateGuaranteedResources(r corev1.ResourceRequirements, names ...corev1.ResourceName) error {
+	for _, name := range names {
+		if _, ok := r.Limits[name]; !ok {
+			return fmt.Errorf("should set a %s limit", name)
+		}
+	}
+	for name, request := range r.Requests {
+		if l, ok := r.Limits[name]; !ok || l.Cmp(request) != 0 {
+			return fmt.Errorf("should request its %s limit", name)
+		}
+	}
+	return nil
+}
+
+// validateVPPDataplane checks the fields of the VPPDataplane which the CRD schema can't, and that the memory regions of
+// VPP, of the VPPDataplane and of the VPP profiles of the installation, fit within the memory limit of the vpp container.
+func validateVPPDataplane(instance *operatorv1.VPPDataplane, installation *operatorv1.InstallationSpec) error {
+	if instance.Spec.UplinkInterface != "" && len(instance.Spec.UplinkInterfaces) > 0 {
+		return fmt.Errorf("spec.uplinkInterface can't be set along with spec.uplinkInterfaces")
+	}
+	uplinks := map[string]bool{}
+	for i, u := range instance.Spec.UplinkInterfaces {
+		if (u.InterfaceName == "") == (u.PCIAddress == "") {
+			return fmt.Errorf("spec.uplinkInterfaces[%d]: exactly one of interfaceName and pciAddress must be set", i)
+		}
+		name := render.VPPUplinkName(u)
+		if uplinks[name] {
+			return fmt.Errorf("spec.uplinkInterfaces[%d]: %s is already an uplink", i, name)
+		}
+		uplinks[name] = true
+		if err := validateVPPRxMode(u, instance, installation); err != nil {
+			return fmt.Errorf("spec.uplinkInterfaces[%d]: %v", i, err)
+		}
+	}
+	for _, prefix := range instance.Spec.ServicePrefixes {
+		if _, _, err := net.ParseCIDR(prefix); err != nil {
+			return fmt.Errorf("spec.servicePrefixes: %q is not a valid CIDR", prefix)
+		}
+	}
+	if b := instance.Spec.Buffers; b != nil {
+		if b.BuffersPerNUMA != nil && *b.BuffersPerNUMA < 1 {
+			return fmt.Errorf("spec.buffers.buffersPerNUMA should be at least 1")
+		}
+		if b.DataSize != nil && (*b.DataSize < 512 || *b.DataSize > 65535) {
+			return fmt.Errorf("spec.buffers.dataSize should be between 512 and 65535")
+		}
+	}
+
+	var limit *resource.Quantity
+	if r := instance.Spec.VPPResources; r != nil {
+		if l, ok := r.Limits[corev1.ResourceMemory]; ok {
+			limit = &l
+		}
+	}
+	// The regions which aren't set take their VPP default size.
+	total, err := render.ValidateVPPMemory(instance.Spec.Memory)
+	if err != nil {
+		return fmt.Errorf("spec.memory.%v", err)
+	}
+	if limit != nil && total > limit.Value() {
+		return fmt.Errorf("spec.memory and the VPP defaults of its unset regions take %s, more than the memory limit %s of spec.vppResources",
+			resource.NewQuantity(total, resource.BinarySI), limit)
+	}
+	for i := range installation.CalicoNetwork.VPPProfiles {
+		p := &installation.CalicoNetwork.VPPProfiles[i]
+		if render.VPPCPUManagerPinning(p) {
+			if err := validateVPPGuaranteedResources(instance, render.VPPProfileCPUs(p)); err != nil {
+				return fmt.Errorf("the VPP profile %s pins the CPUs with the CPU manager: %v", p.Name, err)
+			}
+		}
+		if limit == nil {
+			continue
+		}
+		// The sizes of the profiles are validated along with the Installation.
+		if total, err := render.ValidateVPPMemory(p.Memory); err == nil && total > limit.Value() {
+			return fmt.Errorf("the memory of the VPP profile %s and the VPP defaults of its unset regions take %s, more than the memory limit %s of spec.vppResources",
+				p.Name, resource.NewQuantity(total, resource.BinarySI), limit)
+		}
+	}
+	return nil
+}
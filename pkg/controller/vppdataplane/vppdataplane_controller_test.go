@@ -0,0 +1,465 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vppdataplane
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("VPPDataplane controller tests", func() {
+	var cli client.Client
+	var ctx context.Context
+	var mockStatus *status.MockStatus
+	var r ReconcileVPPDataplane
+	var scheme *runtime.Scheme
+	var installation *operatorv1.Installation
+	var vpp *operatorv1.VPPDataplane
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(appsv1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(rbacv1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+		ctx = context.Background()
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		mockStatus = &status.MockStatus{}
+		mockStatus.On("AddDaemonsets", mock.Anything)
+		mockStatus.On("AddDeployments", mock.Anything)
+		mockStatus.On("AddStatefulSets", mock.Anything)
+		mockStatus.On("AddCronJobs", mock.Anything)
+		mockStatus.On("AddJobs", mock.Anything)
+		mockStatus.On("IsRolloutPaused").Return(false)
+		mockStatus.On("OnCRFound").Return()
+		mockStatus.On("OnCRNotFound").Return()
+		mockStatus.On("ClearDegraded").Return()
+		mockStatus.On("IsAvailable").Return(true)
+
+		r = ReconcileVPPDataplane{
+			client: cli,
+			scheme: scheme,
+			status: mockStatus,
+		}
+
+		vppDataplane := operatorv1.LinuxDataplaneVPP
+		installation = &operatorv1.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: operatorv1.InstallationSpec{
+				Variant:       operatorv1.Calico,
+				CalicoNetwork: &operatorv1.CalicoNetworkSpec{LinuxDataplane: &vppDataplane},
+			},
+			Status: operatorv1.InstallationStatus{Variant: operatorv1.Calico},
+		}
+		Expect(cli.Create(ctx, installation)).NotTo(HaveOccurred())
+
+		vpp = &operatorv1.VPPDataplane{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       operatorv1.VPPDataplaneSpec{UplinkInterface: "eth1"},
+		}
+		Expect(cli.Create(ctx, vpp)).NotTo(HaveOccurred())
+	})
+
+	It("should render the calico-vpp-node DaemonSet and its configuration", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.VPPNodeName, Namespace: common.CalicoNamespace}, &appsv1.DaemonSet{})).NotTo(HaveOccurred())
+		cm := &corev1.ConfigMap{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.VPPConfigConfigMapName, Namespace: common.CalicoNamespace}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Data).To(HaveKeyWithValue(render.VPPInterfaceKey, "eth1"))
+
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "default"}, vpp)).NotTo(HaveOccurred())
+		Expect(vpp.Status.State).To(Equal(operatorv1.TigeraStatusReady))
+	})
+
+	It("should reconcile the changes of the uplink interface", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "default"}, vpp)).NotTo(HaveOccurred())
+		vpp.Spec.UplinkInterface = "ens5"
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		cm := &corev1.ConfigMap{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.VPPConfigConfigMapName, Namespace: common.CalicoNamespace}, cm)).NotTo(HaveOccurred())
+		Expect(cm.Data).To(HaveKeyWithValue(render.VPPInterfaceKey, "ens5"))
+	})
+
+	It("should degrade without the VPP LinuxDataplane", func() {
+		bpf := operatorv1.LinuxDataplaneBPF
+		installation.Spec.CalicoNetwork.LinuxDataplane = &bpf
+		Expect(cli.Update(ctx, installation)).NotTo(HaveOccurred())
+		msg := fmt.Sprintf("VPPDataplane is only supported with the %s LinuxDataplane", operatorv1.LinuxDataplaneVPP)
+		mockStatus.On("SetDegraded", msg, "").Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", msg, "")
+
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.VPPNodeName, Namespace: common.CalicoNamespace}, &appsv1.DaemonSet{})).To(HaveOccurred())
+	})
+
+	It("should degrade with an invalid service prefix", func() {
+		vpp.Spec.ServicePrefixes = []string{"10.96.0.0"}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		mockStatus.On("SetDegraded", "Invalid VPPDataplane", `spec.servicePrefixes: "10.96.0.0" is not a valid CIDR`).Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid VPPDataplane", `spec.servicePrefixes: "10.96.0.0" is not a valid CIDR`)
+	})
+
+	It("should degrade with an invalid buffer data size", func() {
+		dataSize := int32(128)
+		vpp.Spec.Buffers = &operatorv1.VPPBuffers{DataSize: &dataSize}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		mockStatus.On("SetDegraded", "Invalid VPPDataplane", "spec.buffers.dataSize should be between 512 and 65535").Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid VPPDataplane", "spec.buffers.dataSize should be between 512 and 65535")
+	})
+
+	It("should degrade when the memory regions of VPP exceed the memory limit of the vpp container", func() {
+		mainHeap, statSeg := resource.MustParse("1Gi"), resource.MustParse("512Mi")
+		vpp.Spec.Memory = &operatorv1.VPPMemory{MainHeapSize: &mainHeap, StatsSegmentSize: &statSeg}
+		vpp.Spec.VPPResources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		msg := "spec.memory and the VPP defaults of its unset regions take 1552Mi, more than the memory limit 1Gi of spec.vppResources"
+		mockStatus.On("SetDegraded", "Invalid VPPDataplane", msg).Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid VPPDataplane", msg)
+	})
+
+	It("should count the VPP defaults of the unset memory regions against the memory limit of the vpp container", func() {
+		statSeg := resource.MustParse("64Mi")
+		vpp.Spec.Memory = &operatorv1.VPPMemory{StatsSegmentSize: &statSeg}
+		vpp.Spec.VPPResources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+		}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		msg := "spec.memory and the VPP defaults of its unset regions take 1104Mi, more than the memory limit 512Mi of spec.vppResources"
+		mockStatus.On("SetDegraded", "Invalid VPPDataplane", msg).Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid VPPDataplane", msg)
+	})
+
+	It("should degrade when the memory regions of a VPP profile exceed the memory limit of the vpp container", func() {
+		mainHeap := resource.MustParse("2Gi")
+		installation.Spec.CalicoNetwork.VPPProfiles = []operatorv1.VPPProfile{
+			{Name: "edge", Memory: &operatorv1.VPPMemory{MainHeapSize: &mainHeap}},
+		}
+		Expect(cli.Update(ctx, installation)).NotTo(HaveOccurred())
+		vpp.Spec.VPPResources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1536Mi")},
+		}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		msg := "the memory of the VPP profile edge and the VPP defaults of its unset regions take 2096Mi, more than the memory limit 1536Mi of spec.vppResources"
+		mockStatus.On("SetDegraded", "Invalid VPPDataplane", msg).Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid VPPDataplane", msg)
+	})
+
+	It("should degrade when the pods of a VPP profile pinning the CPUs with the CPU manager can't be Guaranteed", func() {
+		workers := int32(2)
+		installation.Spec.CalicoNetwork.VPPProfiles = []operatorv1.VPPProfile{
+			{Name: "rt", Workers: &workers, CPUPinning: &operatorv1.VPPCPUPinning{Mode: operatorv1.VPPCPUPinningCPUManager}},
+		}
+		Expect(cli.Update(ctx, installation)).NotTo(HaveOccurred())
+		vpp.Spec.VPPResources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+		}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		msg := "the VPP profile rt pins the CPUs with the CPU manager: the cpu limit 2 of spec.vppResources should be 3, one for the main thread and one for each worker"
+		mockStatus.On("SetDegraded", "Invalid VPPDataplane", msg).Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid VPPDataplane", msg)
+
+		vpp.Spec.VPPResources.Limits[corev1.ResourceCPU] = resource.MustParse("3")
+		vpp.Spec.AgentResources = &corev1.ResourceRequirements{
+			Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+		}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		msg = "the VPP profile rt pins the CPUs with the CPU manager: spec.agentResources should request its cpu limit"
+		mockStatus.On("SetDegraded", "Invalid VPPDataplane", msg).Return()
+
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid VPPDataplane", msg)
+	})
+
+	It("should degrade with an rx mode the driver of an uplink doesn't support", func() {
+		dpdk, interrupt := operatorv1.VPPUplinkDriverDPDK, operatorv1.VPPRxModeInterrupt
+		installation.Spec.CalicoNetwork.VPPProfiles = []operatorv1.VPPProfile{{Name: "dpdk", UplinkDriver: &dpdk}}
+		Expect(cli.Update(ctx, installation)).NotTo(HaveOccurred())
+		vpp.Spec.UplinkInterface = ""
+		vpp.Spec.UplinkInterfaces = []operatorv1.VPPUplinkInterface{{InterfaceName: "eth1", RxMode: &interrupt}}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		msg := "spec.uplinkInterfaces[0]: the Interrupt rxMode is not supported by the dpdk uplinkDriver of the VPP profile dpdk"
+		mockStatus.On("SetDegraded", "Invalid VPPDataplane", msg).Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid VPPDataplane", msg)
+	})
+
+	It("should degrade when the uplink interface is set along with the uplink interfaces", func() {
+		vpp.Spec.UplinkInterfaces = []operatorv1.VPPUplinkInterface{{InterfaceName: "eth2"}}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		msg := "spec.uplinkInterface can't be set along with spec.uplinkInterfaces"
+		mockStatus.On("SetDegraded", "Invalid VPPDataplane", msg).Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid VPPDataplane", msg)
+	})
+
+	It("should degrade with a duplicated uplink interface", func() {
+		vpp.Spec.UplinkInterface = ""
+		vpp.Spec.UplinkInterfaces = []operatorv1.VPPUplinkInterface{{InterfaceName: "eth1"}, {InterfaceName: "eth1"}}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		msg := "spec.uplinkInterfaces[1]: eth1 is already an uplink"
+		mockStatus.On("SetDegraded", "Invalid VPPDataplane", msg).Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Invalid VPPDataplane", msg)
+	})
+
+	It("should report the migration status of the uplink interfaces of the nodes", func() {
+		vpp.Spec.UplinkInterface = ""
+		vpp.Spec.UplinkInterfaces = []operatorv1.VPPUplinkInterface{{InterfaceName: "eth1"}, {PCIAddress: "0000:3b:00.1"}}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		for name, os := range map[string]string{"node-a": "linux", "node-b": "linux", "node-c": "linux", "node-d": "windows"} {
+			Expect(cli.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"kubernetes.io/os": os},
+			}})).NotTo(HaveOccurred())
+		}
+		for node, ready := range map[string]corev1.ConditionStatus{"node-a": corev1.ConditionTrue, "node-b": corev1.ConditionFalse} {
+			Expect(cli.Create(ctx, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      render.VPPNodeName + "-" + node,
+					Namespace: common.CalicoNamespace,
+					Labels:    map[string]string{"k8s-app": render.VPPNodeName, render.VPPNodePodLabel: "true"},
+				},
+				Spec:   corev1.PodSpec{NodeName: node},
+				Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+			})).NotTo(HaveOccurred())
+		}
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "default"}, vpp)).NotTo(HaveOccurred())
+		Expect(vpp.Status.Uplinks).To(Equal([]operatorv1.NodeUplinkStatus{
+			{Node: "node-b", Message: "waiting for the addresses and the routes of eth1, 0000:3b:00.1 to be migrated to VPP"},
+			{Node: "node-c", Message: "waiting for the calico-vpp-node pod of the node"},
+			{Node: "node-a", Ready: true},
+		}))
+	})
+
+	It("should taint the nodes of the VPP dataplane until their calico-vpp-node pod is ready", func() {
+		enabled := operatorv1.VPPBootstrapTaintEnabled
+		vpp.Spec.BootstrapTaint = &enabled
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		for _, name := range []string{"node-a", "node-b"} {
+			Expect(cli.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"kubernetes.io/os": "linux"},
+			}})).NotTo(HaveOccurred())
+		}
+		Expect(cli.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-windows",
+			Labels: map[string]string{"kubernetes.io/os": "windows"},
+		}})).NotTo(HaveOccurred())
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      render.VPPNodeName + "-a",
+				Namespace: common.CalicoNamespace,
+				Labels:    map[string]string{"k8s-app": render.VPPNodeName, render.VPPNodePodLabel: "true"},
+			},
+			Spec:   corev1.PodSpec{NodeName: "node-a"},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		}
+		Expect(cli.Create(ctx, pod)).NotTo(HaveOccurred())
+		taint := corev1.Taint{Key: common.VPPBootstrapTaintKey, Effect: corev1.TaintEffectNoSchedule}
+		taints := func(name string) []corev1.Taint {
+			node := &corev1.Node{}
+			Expect(cli.Get(ctx, types.NamespacedName{Name: name}, node)).NotTo(HaveOccurred())
+			return node.Spec.Taints
+		}
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taints("node-a")).To(BeEmpty())
+		Expect(taints("node-b")).To(Equal([]corev1.Taint{taint}))
+		Expect(taints("node-windows")).To(BeEmpty())
+
+		// The dataplane of node-b becomes ready, and the one of node-a restarts.
+		pod.Status.Conditions[0].Status = corev1.ConditionFalse
+		Expect(cli.Update(ctx, pod)).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      render.VPPNodeName + "-b",
+				Namespace: common.CalicoNamespace,
+				Labels:    map[string]string{"k8s-app": render.VPPNodeName, render.VPPNodePodLabel: "true"},
+			},
+			Spec:   corev1.PodSpec{NodeName: "node-b"},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		})).NotTo(HaveOccurred())
+
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taints("node-a")).To(BeEmpty())
+		Expect(taints("node-b")).To(BeEmpty())
+	})
+
+	It("should untaint the nodes when the bootstrap taint is disabled", func() {
+		taint := corev1.Taint{Key: common.VPPBootstrapTaintKey, Effect: corev1.TaintEffectNoSchedule}
+		other := corev1.Taint{Key: "dedicated", Value: "edge", Effect: corev1.TaintEffectNoSchedule}
+		Expect(cli.Create(ctx, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{common.LinuxDataplaneLabel: "VPP"}},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{taint, other}},
+		})).NotTo(HaveOccurred())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		node := &corev1.Node{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "node-a"}, node)).NotTo(HaveOccurred())
+		Expect(node.Spec.Taints).To(Equal([]corev1.Taint{other}))
+	})
+
+	It("should only taint the migrated nodes while the nodes are migrated from the BPF dataplane", func() {
+		installation.Spec.DataplaneMigration = &operatorv1.DataplaneMigration{}
+		Expect(cli.Update(ctx, installation)).NotTo(HaveOccurred())
+		enabled := operatorv1.VPPBootstrapTaintEnabled
+		vpp.Spec.BootstrapTaint = &enabled
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-migrated",
+			Labels: map[string]string{"kubernetes.io/os": "linux", common.LinuxDataplaneLabel: "VPP"},
+		}})).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-bpf",
+			Labels: map[string]string{"kubernetes.io/os": "linux"},
+		}})).NotTo(HaveOccurred())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		node := &corev1.Node{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "node-migrated"}, node)).NotTo(HaveOccurred())
+		Expect(node.Spec.Taints).To(Equal([]corev1.Taint{{Key: common.VPPBootstrapTaintKey, Effect: corev1.TaintEffectNoSchedule}}))
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "node-bpf"}, node)).NotTo(HaveOccurred())
+		Expect(node.Spec.Taints).To(BeEmpty())
+	})
+
+	It("should report the readiness of the hugepages of the nodes, even while the DaemonSets roll out", func() {
+		vpp.Spec.Hugepages = &operatorv1.VPPHugepages{Count: 512}
+		Expect(cli.Update(ctx, vpp)).NotTo(HaveOccurred())
+		for _, name := range []string{"node-a", "node-b", "node-c"} {
+			Expect(cli.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"kubernetes.io/os": "linux"},
+			}})).NotTo(HaveOccurred())
+		}
+		for node, ready := range map[string]corev1.ConditionStatus{"node-a": corev1.ConditionTrue, "node-b": corev1.ConditionFalse} {
+			Expect(cli.Create(ctx, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      render.VPPHugepagesName + "-" + node,
+					Namespace: common.CalicoNamespace,
+					Labels:    map[string]string{"k8s-app": render.VPPHugepagesName},
+				},
+				Spec:   corev1.PodSpec{NodeName: node},
+				Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+			})).NotTo(HaveOccurred())
+		}
+		mockStatus = &status.MockStatus{}
+		mockStatus.On("AddDaemonsets", mock.Anything)
+		mockStatus.On("AddDeployments", mock.Anything)
+		mockStatus.On("AddStatefulSets", mock.Anything)
+		mockStatus.On("AddCronJobs", mock.Anything)
+		mockStatus.On("AddJobs", mock.Anything)
+		mockStatus.On("IsRolloutPaused").Return(false)
+		mockStatus.On("OnCRFound").Return()
+		mockStatus.On("ClearDegraded").Return()
+		mockStatus.On("IsAvailable").Return(false)
+		r.status = mockStatus
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.VPPHugepagesName, Namespace: common.CalicoNamespace}, &appsv1.DaemonSet{})).NotTo(HaveOccurred())
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "default"}, vpp)).NotTo(HaveOccurred())
+		Expect(vpp.Status.State).To(BeEmpty())
+		// The nodes whose hugepages aren't ready come first.
+		Expect(vpp.Status.Hugepages).To(Equal([]operatorv1.NodeHugepagesStatus{
+			{Node: "node-b", Message: "waiting for 512 hugepages of 2Mi to be reserved"},
+			{Node: "node-c", Message: "waiting for the calico-vpp-hugepages pod of the node"},
+			{Node: "node-a", Ready: true},
+		}))
+	})
+
+	It("should bound the hugepages status of the nodes", func() {
+		vpp.Spec.Hugepages = &operatorv1.VPPHugepages{Count: 512}
+		for i := 0; i <= maxNodeStatuses; i++ {
+			Expect(cli.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("node-%03d", i),
+				Labels: map[string]string{"kubernetes.io/os": "linux"},
+			}})).NotTo(HaveOccurred())
+		}
+
+		st, err := hugepagesStatus(ctx, cli, vpp, &operatorv1.InstallationSpec{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st).To(HaveLen(maxNodeStatuses))
+	})
+})
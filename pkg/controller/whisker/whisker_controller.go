@@ -0,0 +1,325 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package whisker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/options"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+	"github.com/tigera/operator/pkg/dns"
+	"github.com/tigera/operator/pkg/render"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/tls"
+)
+
+var log = logf.Log.WithName("controller_whisker")
+
+// Add creates a new Whisker Controller and adds it to the Manager.
+// The Manager will set fields on the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, opts options.AddOptions) error {
+	return add(mgr, newReconciler(mgr, opts))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, opts options.AddOptions) *ReconcileWhisker {
+	r := &ReconcileWhisker{
+		client:        mgr.GetClient(),
+		scheme:        mgr.GetScheme(),
+		status:        status.New(mgr.GetClient(), "whisker", opts.KubernetesVersion),
+		clusterDomain: opts.ClusterDomain,
+	}
+	r.status.Run(opts.ShutdownContext)
+	return r
+}
+
+// add adds watches for resources that are available at startup.
+func add(mgr manager.Manager, r *ReconcileWhisker) error {
+	c, err := controller.New("whisker-controller", mgr, controller.Options{Reconciler: utils.FairReconciler("whisker-controller", r)})
+	if err != nil {
+		return fmt.Errorf("Failed to create whisker-controller: %w", err)
+	}
+
+	err = c.Watch(&source.Kind{Type: &operatorv1.Whisker{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("whisker-controller failed to watch primary resource: %w", err)
+	}
+
+	if err = utils.AddNetworkWatch(c); err != nil {
+		return fmt.Errorf("whisker-controller failed to watch Installation resource: %w", err)
+	}
+
+	if err = imageset.AddImageSetWatch(c); err != nil {
+		return fmt.Errorf("whisker-controller failed to watch ImageSet: %w", err)
+	}
+
+	// The address of the Goldmane Service is only known once it is allocated.
+	if err = utils.AddServiceWatch(c, render.GoldmaneName, common.CalicoNamespace); err != nil {
+		return fmt.Errorf("whisker-controller failed to watch Service %s: %w", render.GoldmaneName, err)
+	}
+
+	// The key pairs of Goldmane and the Whisker backend, the operator CA signing them and the Typha CA, which signs the
+	// certificate Felix reports the flows with.
+	for _, name := range []string{render.GoldmaneKeyPairSecret, render.WhiskerBackendKeyPairSecret, utils.OperatorCASecretName} {
+		if err = utils.AddSecretsWatch(c, name, common.OperatorNamespace()); err != nil {
+			return fmt.Errorf("whisker-controller failed to watch Secret %s: %w", name, err)
+		}
+	}
+	if err = utils.AddConfigMapWatch(c, render.TyphaCAConfigMapName, common.OperatorNamespace()); err != nil {
+		return fmt.Errorf("whisker-controller failed to watch ConfigMap %s: %w", render.TyphaCAConfigMapName, err)
+	}
+
+	err = c.Watch(&source.Kind{Type: &crdv1.FelixConfiguration{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("whisker-controller failed to watch FelixConfiguration resource: %w", err)
+	}
+
+	return nil
+}
+
+// Blank assignment to verify that ReconcileWhisker implements reconcile.Reconciler.
+var _ reconcile.Reconciler = &ReconcileWhisker{}
+
+// ReconcileWhisker reconciles the Whisker object.
+type ReconcileWhisker struct {
+	client        client.Client
+	scheme        *runtime.Scheme
+	status        status.StatusManager
+	clusterDomain string
+}
+
+// Reconcile renders Goldmane and Whisker, and points Felix at Goldmane. The rendered objects are owned by the Whisker,
+// so they are garbage collected with it, and Felix stops reporting the flows once it is deleted.
+func (r *ReconcileWhisker) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling Whisker")
+
+	instance := &operatorv1.Whisker{}
+	if err := r.client.Get(ctx, utils.DefaultInstanceKey, instance); err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("Whisker object not found")
+			if err = r.patchFelixGoldmaneServer(ctx, ""); err != nil {
+				reqLogger.Error(err, "Error patching felix configuration")
+			}
+			r.status.OnCRNotFound()
+			return reconcile.Result{}, nil
+		}
+		r.status.SetDegraded("Error querying Whisker", err.Error())
+		return reconcile.Result{}, err
+	}
+	r.status.OnCRFound()
+
+	variant, installation, err := utils.GetInstallation(ctx, r.client)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.status.SetDegraded("Installation not found", err.Error())
+			return reconcile.Result{}, nil
+		}
+		r.status.SetDegraded("Error querying installation", err.Error())
+		return reconcile.Result{}, err
+	}
+	if variant == "" {
+		r.status.SetDegraded("Waiting for Installation to be ready", "")
+		return reconcile.Result{}, nil
+	}
+	if variant != operatorv1.Calico {
+		r.status.SetDegraded(fmt.Sprintf("Whisker is only supported for variant %s", operatorv1.Calico), "")
+		return reconcile.Result{}, nil
+	}
+
+	// The address of the Goldmane Service is included in its certificate, since Felix connects to that address.
+	svc := &corev1.Service{}
+	if err = r.client.Get(ctx, types.NamespacedName{Name: render.GoldmaneName, Namespace: common.CalicoNamespace}, svc); err != nil && !errors.IsNotFound(err) {
+		r.status.SetDegraded("Error reading the Goldmane Service", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	ca, caSecret, err := r.getOperatorCA(ctx)
+	if err != nil {
+		r.status.SetDegraded("Error reading the operator CA", err.Error())
+		return reconcile.Result{}, err
+	}
+	typhaCA, err := getConfigMapData(ctx, r.client, render.TyphaCAConfigMapName, render.TyphaCABundleName)
+	if err != nil {
+		r.status.SetDegraded("Error reading the Typha CA", err.Error())
+		return reconcile.Result{}, err
+	}
+	if typhaCA == "" {
+		r.status.SetDegraded("Waiting for the Typha CA to be created", "")
+		return reconcile.Result{}, nil
+	}
+
+	goldmaneDNSNames := dns.GetServiceDNSNames(render.GoldmaneName, common.CalicoNamespace, r.clusterDomain)
+	if svc.Spec.ClusterIP != "" {
+		goldmaneDNSNames = append(goldmaneDNSNames, svc.Spec.ClusterIP)
+	}
+	goldmaneKeyPair, err := r.ensureKeyPair(ctx, ca, render.GoldmaneKeyPairSecret, nil, goldmaneDNSNames...)
+	if err != nil {
+		r.status.SetDegraded(fmt.Sprintf("Error ensuring the Goldmane TLS certificate %q", render.GoldmaneKeyPairSecret), err.Error())
+		return reconcile.Result{}, err
+	}
+	backendKeyPair, err := r.ensureKeyPair(ctx, ca, render.WhiskerBackendKeyPairSecret, []crypto.CertificateExtensionFunc{tls.SetClientAuth}, render.WhiskerName)
+	if err != nil {
+		r.status.SetDegraded(fmt.Sprintf("Error ensuring the Whisker backend TLS certificate %q", render.WhiskerBackendKeyPairSecret), err.Error())
+		return reconcile.Result{}, err
+	}
+
+	component := render.Whisker(&render.WhiskerConfiguration{
+		Installation:          installation,
+		Whisker:               instance,
+		ClusterDomain:         r.clusterDomain,
+		GoldmaneKeyPair:       goldmaneKeyPair,
+		WhiskerBackendKeyPair: backendKeyPair,
+		CABundle:              string(caSecret.Data[utils.OperatorCACertName]) + typhaCA,
+	})
+	if err = imageset.ApplyImageSet(ctx, r.client, variant, component); err != nil {
+		r.status.SetDegraded("Error with images from ImageSet", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	handler := utils.NewComponentHandler(log, r.client, r.scheme, instance)
+	handler.SetHardening(installation.Hardening)
+	if err = handler.CreateOrUpdateOrDelete(ctx, component, r.status); err != nil {
+		r.status.SetDegraded("Error creating / updating resource", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	// Felix runs on the host network, where the name of the Service cannot be resolved, so it is given its address. The
+	// Service is watched, so the certificate of Goldmane is issued for its address once it is allocated.
+	if svc.Spec.ClusterIP == "" {
+		r.status.SetDegraded("Waiting for the Goldmane Service to get an address", "")
+		return reconcile.Result{}, nil
+	}
+	server := net.JoinHostPort(svc.Spec.ClusterIP, fmt.Sprint(render.GoldmanePort))
+	if err = r.patchFelixGoldmaneServer(ctx, server); err != nil {
+		r.status.SetDegraded("Error patching felix configuration", err.Error())
+		return reconcile.Result{}, err
+	}
+
+	// Clear the degraded bit if we've reached this far.
+	r.status.ClearDegraded()
+
+	if !r.status.IsAvailable() {
+		// Schedule a kick to check again in the near future, hopefully by then things will be available.
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// Everything is available - update the CRD status.
+	instance.Status.State = operatorv1.TigeraStatusReady
+	if err = r.client.Status().Update(ctx, instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// getOperatorCA returns the CA the operator signs the certificates of Goldmane and the Whisker backend with, along with
+// the Secret holding it. A newly generated CA is stored right away, without an owner, so that it outlives the Whisker.
+func (r *ReconcileWhisker) getOperatorCA(ctx context.Context) (*crypto.CA, *corev1.Secret, error) {
+	ca, caSecret, err := utils.GetOrCreateOperatorCA(ctx, r.client)
+	if err != nil {
+		return nil, nil, err
+	}
+	if caSecret.ResourceVersion == "" {
+		hdler := utils.NewComponentHandler(log, r.client, r.scheme, nil)
+		if err := hdler.CreateOrUpdateOrDelete(ctx, render.NewPassthrough(caSecret), nil); err != nil {
+			return nil, nil, err
+		}
+	}
+	return ca, caSecret, nil
+}
+
+// ensureKeyPair returns the key pair of the given Secret of the operator namespace, issuing a new certificate signed by
+// the operator CA if the Secret doesn't exist or its certificate isn't valid for the given names. User-supplied Secrets
+// are used as is.
+func (r *ReconcileWhisker) ensureKeyPair(ctx context.Context, ca *crypto.CA, name string, cef []crypto.CertificateExtensionFunc, dnsNames ...string) (*corev1.Secret, error) {
+	secret, err := utils.GetSecret(ctx, r.client, name, common.OperatorNamespace())
+	if err != nil {
+		return nil, err
+	}
+	secret, operatorManaged, err := utils.EnsureCertificateSignedByCA(ca, name, secret, corev1.TLSPrivateKeyKey, corev1.TLSCertKey, rmeta.DefaultCertificateDuration, cef, dnsNames...)
+	if err != nil {
+		return nil, err
+	}
+	if operatorManaged && secret.ResourceVersion == "" {
+		hdler := utils.NewComponentHandler(log, r.client, r.scheme, nil)
+		if err := hdler.CreateOrUpdateOrDelete(ctx, render.NewPassthrough(secret), nil); err != nil {
+			return nil, err
+		}
+	}
+	return secret, nil
+}
+
+// getConfigMapData returns the value of the key of the given ConfigMap of the operator namespace, or an empty string
+// if the ConfigMap doesn't exist.
+func getConfigMapData(ctx context.Context, cli client.Client, name, key string) (string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: name, Namespace: common.OperatorNamespace()}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return cm.Data[key], nil
+}
+
+// patchFelixGoldmaneServer sets the Goldmane server of the default FelixConfiguration, or clears it if the server is
+// empty.
+func (r *ReconcileWhisker) patchFelixGoldmaneServer(ctx context.Context, server string) error {
+	fc := &crdv1.FelixConfiguration{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: "default"}, fc); err != nil {
+		if errors.IsNotFound(err) && server == "" {
+			return nil
+		}
+		return err
+	}
+
+	current := ""
+	if fc.Spec.FlowLogsGoldmaneServer != nil {
+		current = *fc.Spec.FlowLogsGoldmaneServer
+	}
+	if current == server {
+		return nil
+	}
+
+	patchFrom := client.MergeFrom(fc.DeepCopy())
+	if server == "" {
+		fc.Spec.FlowLogsGoldmaneServer = nil
+	} else {
+		fc.Spec.FlowLogsGoldmaneServer = &server
+	}
+	log.Info("Patching the Goldmane server of the FelixConfiguration", "server", server)
+	return r.client.Patch(ctx, fc, patchFrom)
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package whisker
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/dns"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("Whisker controller tests", func() {
+	var cli client.Client
+	var ctx context.Context
+	var mockStatus *status.MockStatus
+	var r ReconcileWhisker
+	var scheme *runtime.Scheme
+	var installation *operatorv1.Installation
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(appsv1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+		ctx = context.Background()
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		mockStatus = &status.MockStatus{}
+		mockStatus.On("AddDaemonsets", mock.Anything)
+		mockStatus.On("AddDeployments", mock.Anything)
+		mockStatus.On("AddStatefulSets", mock.Anything)
+		mockStatus.On("AddCronJobs", mock.Anything)
+		mockStatus.On("AddJobs", mock.Anything)
+		mockStatus.On("IsRolloutPaused").Return(false)
+		mockStatus.On("OnCRFound").Return()
+		mockStatus.On("OnCRNotFound").Return()
+		mockStatus.On("ClearDegraded").Return()
+		mockStatus.On("IsAvailable").Return(true)
+
+		r = ReconcileWhisker{
+			client:        cli,
+			scheme:        scheme,
+			status:        mockStatus,
+			clusterDomain: dns.DefaultClusterDomain,
+		}
+
+		installation = &operatorv1.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       operatorv1.InstallationSpec{Variant: operatorv1.Calico},
+			Status:     operatorv1.InstallationStatus{Variant: operatorv1.Calico},
+		}
+		Expect(cli.Create(ctx, installation)).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &crdv1.FelixConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &operatorv1.Whisker{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).NotTo(HaveOccurred())
+
+		Expect(cli.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: render.TyphaCAConfigMapName, Namespace: common.OperatorNamespace()},
+			Data:       map[string]string{render.TyphaCABundleName: "typha-ca\n"},
+		})).NotTo(HaveOccurred())
+
+		// The address of the Service is allocated by the API server.
+		Expect(cli.Create(ctx, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: render.GoldmaneName, Namespace: common.CalicoNamespace},
+			Spec:       corev1.ServiceSpec{ClusterIP: "10.96.0.20"},
+		})).NotTo(HaveOccurred())
+	})
+
+	It("should render Goldmane and Whisker and point Felix at Goldmane", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, name := range []string{render.GoldmaneName, render.WhiskerName} {
+			Expect(cli.Get(ctx, types.NamespacedName{Name: name, Namespace: common.CalicoNamespace}, &appsv1.Deployment{})).NotTo(HaveOccurred())
+		}
+
+		svc := &corev1.Service{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.GoldmaneName, Namespace: common.CalicoNamespace}, svc)).NotTo(HaveOccurred())
+		Expect(svc.Spec.ClusterIP).To(Equal("10.96.0.20"))
+
+		fc := &crdv1.FelixConfiguration{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "default"}, fc)).NotTo(HaveOccurred())
+		Expect(fc.Spec.FlowLogsGoldmaneServer).NotTo(BeNil())
+		Expect(*fc.Spec.FlowLogsGoldmaneServer).To(Equal(fmt.Sprintf("10.96.0.20:%d", render.GoldmanePort)))
+
+		whisker := &operatorv1.Whisker{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "default"}, whisker)).NotTo(HaveOccurred())
+		Expect(whisker.Status.State).To(Equal(operatorv1.TigeraStatusReady))
+	})
+
+	It("should issue the certificates of Goldmane and the Whisker backend with the operator CA", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		caSecret := &corev1.Secret{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: utils.OperatorCASecretName, Namespace: common.OperatorNamespace()}, caSecret)).NotTo(HaveOccurred())
+		ca, err := crypto.GetCAFromBytes(caSecret.Data[utils.OperatorCACertName], caSecret.Data[utils.OperatorCAKeyName])
+		Expect(err).NotTo(HaveOccurred())
+
+		// Felix connects to the address of the Service.
+		goldmane := &corev1.Secret{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.GoldmaneKeyPairSecret, Namespace: common.OperatorNamespace()}, goldmane)).NotTo(HaveOccurred())
+		names := append(dns.GetServiceDNSNames(render.GoldmaneName, common.CalicoNamespace, dns.DefaultClusterDomain), "10.96.0.20")
+		Expect(utils.SecretHasExpectedDNSNames(goldmane, corev1.TLSCertKey, names)).NotTo(HaveOccurred())
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.GoldmaneKeyPairSecret, Namespace: common.CalicoNamespace}, &corev1.Secret{})).NotTo(HaveOccurred())
+
+		backend := &corev1.Secret{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.WhiskerBackendKeyPairSecret, Namespace: common.OperatorNamespace()}, backend)).NotTo(HaveOccurred())
+		block, _ := pem.Decode(backend.Data[corev1.TLSCertKey])
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.CheckSignatureFrom(ca.Config.Certs[0])).NotTo(HaveOccurred())
+		Expect(cert.ExtKeyUsage).To(ContainElement(x509.ExtKeyUsageClientAuth))
+
+		// Goldmane trusts the operator CA and the Typha CA, which signs the certificate of Felix.
+		bundle := &corev1.ConfigMap{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "goldmane-ca-bundle", Namespace: common.CalicoNamespace}, bundle)).NotTo(HaveOccurred())
+		Expect(bundle.Data["ca-bundle.crt"]).To(Equal(string(caSecret.Data[utils.OperatorCACertName]) + "typha-ca\n"))
+
+		// The certificates are kept by the next reconciles.
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		kept := &corev1.Secret{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.GoldmaneKeyPairSecret, Namespace: common.OperatorNamespace()}, kept)).NotTo(HaveOccurred())
+		Expect(kept.Data).To(Equal(goldmane.Data))
+	})
+
+	It("should wait for the Typha CA", func() {
+		Expect(cli.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: render.TyphaCAConfigMapName, Namespace: common.OperatorNamespace()}})).NotTo(HaveOccurred())
+		mockStatus.On("SetDegraded", "Waiting for the Typha CA to be created", "").Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", "Waiting for the Typha CA to be created", "")
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.GoldmaneName, Namespace: common.CalicoNamespace}, &appsv1.Deployment{})).To(HaveOccurred())
+	})
+
+	It("should stop the flow reporting of Felix once the Whisker is deleted", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cli.Delete(ctx, &operatorv1.Whisker{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+
+		fc := &crdv1.FelixConfiguration{}
+		Expect(cli.Get(ctx, types.NamespacedName{Name: "default"}, fc)).NotTo(HaveOccurred())
+		Expect(fc.Spec.FlowLogsGoldmaneServer).To(BeNil())
+	})
+
+	It("should degrade for the Enterprise variant", func() {
+		installation.Status.Variant = operatorv1.TigeraSecureEnterprise
+		Expect(cli.Update(ctx, installation)).NotTo(HaveOccurred())
+		mockStatus.On("SetDegraded", fmt.Sprintf("Whisker is only supported for variant %s", operatorv1.Calico), "").Return()
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", fmt.Sprintf("Whisker is only supported for variant %s", operatorv1.Calico), "")
+
+		Expect(cli.Get(ctx, types.NamespacedName{Name: render.WhiskerName, Namespace: common.CalicoNamespace}, &appsv1.Deployment{})).To(HaveOccurred())
+	})
+})
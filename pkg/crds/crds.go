@@ -51,7 +51,7 @@ var (
 func init() {
 	yamlDelimRe = regexp.MustCompile(`\n---`)
 
-	calicoCRDNames := []string{"installation", "apiserver", "imageset", "tigerastatus"}
+	calicoCRDNames := []string{"installation", "apiserver", "imageset", "tigerastatus", "whisker", "vppdataplane", "dataplanerestart"}
 	calicoOprtrCRDsRe = regexp.MustCompile(fmt.Sprintf("(%s)", strings.Join(calicoCRDNames, "|")))
 }
 
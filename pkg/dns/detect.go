@@ -0,0 +1,131 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// kubernetesServiceName is the DNS name of the kubernetes service in the default namespace, which is always part of
+// the certificate served by the Kubernetes API server.
+const kubernetesServiceName = "kubernetes.default.svc"
+
+// DetectClusterDomain detects the cluster domain. It tries, in order:
+//   - the search domains of the given resolv.conf, which only hold the cluster domain when the operator pod is not host
+//     networked,
+//   - the clusterDomain of the kubelet configuration of a node, read through the configz endpoint of the node proxy,
+//   - the DNS names of the certificate served by the Kubernetes API server at the given address.
+//
+// An error is returned if none of them holds the cluster domain.
+func DetectClusterDomain(ctx context.Context, cs kubernetes.Interface, resolvConfPath, apiServerAddr string) (string, error) {
+	var errs []string
+
+	clusterDomain, err := GetClusterDomain(resolvConfPath)
+	if err == nil {
+		return clusterDomain, nil
+	}
+	errs = append(errs, err.Error())
+
+	clusterDomain, err = clusterDomainFromKubelet(ctx, cs)
+	if err == nil {
+		return clusterDomain, nil
+	}
+	errs = append(errs, err.Error())
+
+	clusterDomain, err = clusterDomainFromAPIServerCert(apiServerAddr)
+	if err == nil {
+		return clusterDomain, nil
+	}
+	errs = append(errs, err.Error())
+
+	return "", fmt.Errorf("failed to detect the cluster domain: %s", strings.Join(errs, "; "))
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes/proxy,verbs=get
+
+// clusterDomainFromKubelet reads the clusterDomain from the kubelet configuration of the first node.
+func clusterDomainFromKubelet(ctx context.Context, cs kubernetes.Interface) (string, error) {
+	nodes, err := cs.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return "", fmt.Errorf("failed to list nodes: %w", err)
+	}
+	if len(nodes.Items) == 0 {
+		return "", fmt.Errorf("failed to find a node to read the kubelet configuration from")
+	}
+
+	raw, err := cs.CoreV1().RESTClient().Get().
+		Resource("nodes").
+		Name(nodes.Items[0].Name).
+		SubResource("proxy", "configz").
+		DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the kubelet configuration of node %s: %w", nodes.Items[0].Name, err)
+	}
+
+	var configz struct {
+		KubeletConfig struct {
+			ClusterDomain string `json:"clusterDomain"`
+		} `json:"kubeletconfig"`
+	}
+	if err := json.Unmarshal(raw, &configz); err != nil {
+		return "", fmt.Errorf("failed to parse the kubelet configuration of node %s: %w", nodes.Items[0].Name, err)
+	}
+	if configz.KubeletConfig.ClusterDomain == "" {
+		return "", fmt.Errorf("the kubelet configuration of node %s has no cluster domain", nodes.Items[0].Name)
+	}
+	return strings.TrimSuffix(configz.KubeletConfig.ClusterDomain, "."), nil
+}
+
+// clusterDomainFromAPIServerCert reads the cluster domain from the certificate served by the Kubernetes API server.
+func clusterDomainFromAPIServerCert(addr string) (string, error) {
+	if addr == "" {
+		return "", fmt.Errorf("the address of the Kubernetes API server is unknown")
+	}
+
+	// Only the names in the certificate are read, nothing is sent over the connection, so it is not verified.
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to the Kubernetes API server at %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return "", fmt.Errorf("the Kubernetes API server at %s served no certificate", addr)
+	}
+	return ClusterDomainFromDNSNames(certs[0].DNSNames)
+}
+
+// ClusterDomainFromDNSNames returns the cluster domain from the DNS names of the certificate of the Kubernetes API
+// server, which include kubernetes.default.svc.<cluster-domain>.
+func ClusterDomainFromDNSNames(dnsNames []string) (string, error) {
+	for _, name := range dnsNames {
+		if strings.HasPrefix(name, kubernetesServiceName+".") {
+			return strings.TrimPrefix(name, kubernetesServiceName+"."), nil
+		}
+	}
+	return "", fmt.Errorf("failed to find %s.<cluster-domain> in the DNS names of the Kubernetes API server", kubernetesServiceName)
+}
@@ -53,4 +53,20 @@ var _ = Describe("Common Tests", func() {
 			Entry("default", "a", "b", "somedomain", []string{"a", "a.b", "a.b.svc", "a.b.svc.somedomain"}),
 		)
 	})
+
+	Context("Get the cluster domain from the DNS names of the Kubernetes API server", func() {
+		DescribeTable("Should find the cluster domain", func(dnsNames []string, expectedClusterDomain string) {
+			clusterDomain, err := dns.ClusterDomainFromDNSNames(dnsNames)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterDomain).To(Equal(expectedClusterDomain))
+		},
+			Entry("default", []string{"kubernetes", "kubernetes.default", "kubernetes.default.svc", "kubernetes.default.svc.cluster.local"}, dns.DefaultClusterDomain),
+			Entry("custom", []string{"kubernetes.default.svc", "kubernetes.default.svc.somedomain"}, "somedomain"),
+		)
+
+		It("Should throw an error when no DNS name holds the cluster domain", func() {
+			_, err := dns.ClusterDomainFromDNSNames([]string{"kubernetes", "kubernetes.default.svc"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -51,7 +51,6 @@ type AmazonCloudIntegrationConfiguration struct {
 	Installation           *operatorv1.InstallationSpec
 	Credentials            *AmazonCredential
 	PullSecrets            []*corev1.Secret
-	Openshift              bool
 }
 
 type amazonCloudIntegrationComponent struct {
@@ -16,23 +16,28 @@ package render
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/tigera/operator/pkg/render/common/podaffinity"
 	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
+	"github.com/tigera/operator/pkg/render/common/provider"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	apiregv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/k8sapi"
 	"github.com/tigera/operator/pkg/dns"
@@ -48,6 +53,16 @@ const (
 	APIServerSecretCertName = "apiserver.crt"
 )
 
+// Audit logging of the API server. Calico Enterprise only.
+const (
+	APIServerAuditPolicyKey         = "policy.yaml"
+	APIServerAuditWebhookSecretName = "tigera-audit-webhook"
+	APIServerAuditWebhookConfigKey  = "config"
+	auditPolicyHashAnnotation       = "hash.operator.tigera.io/audit-policy"
+	auditWebhookHashAnnotation      = "hash.operator.tigera.io/audit-webhook"
+	defaultAuditLogPath             = "/var/log/calico/audit/tsee-audit.log"
+)
+
 // The following functions are helpers for determining resource names based on
 // the configured product variant.
 func ProjectCalicoApiServerTLSSecretName(v operatorv1.ProductVariant) string {
@@ -57,6 +72,11 @@ func ProjectCalicoApiServerTLSSecretName(v operatorv1.ProductVariant) string {
 	return "tigera-apiserver-certs"
 }
 
+// APIServerCertManagerSecretName returns the name of the Secret cert-manager writes the API server certificate to.
+func APIServerCertManagerSecretName(v operatorv1.ProductVariant) string {
+	return ProjectCalicoApiServerTLSSecretName(v) + "-cert-manager"
+}
+
 func ProjectCalicoApiServerServiceName(v operatorv1.ProductVariant) string {
 	if v == operatorv1.Calico {
 		return "calico-api"
@@ -82,16 +102,17 @@ func APIServer(cfg *APIServerConfiguration) (Component, error) {
 
 	tlsSecrets := []*corev1.Secret{}
 	tlsHashAnnotations := make(map[string]string)
+	replicator := secret.NewReplicator()
 
 	if cfg.Installation.CertificateManagement == nil {
-		tlsHashAnnotations[TlsSecretHashAnnotation] = rmeta.AnnotationHash(cfg.TLSKeyPair.Data)
+		tlsSecrets = append(tlsSecrets, replicator.ReplicateAs(rmeta.APIServerNamespace(cfg.Installation.Variant), ProjectCalicoApiServerTLSSecretName(cfg.Installation.Variant), cfg.TLSKeyPair))
+	}
 
-		copy := cfg.TLSKeyPair.DeepCopy()
-		copy.ObjectMeta = metav1.ObjectMeta{
-			Name:      ProjectCalicoApiServerTLSSecretName(cfg.Installation.Variant),
-			Namespace: rmeta.APIServerNamespace(cfg.Installation.Variant),
-		}
-		tlsSecrets = append(tlsSecrets, copy)
+	if cfg.AuditPolicy != nil {
+		tlsHashAnnotations[auditPolicyHashAnnotation] = rmeta.AnnotationHash(cfg.AuditPolicy.Data)
+	}
+	if cfg.AuditWebhookSecret != nil {
+		tlsHashAnnotations[auditWebhookHashAnnotation] = rmeta.AnnotationHash(cfg.AuditWebhookSecret.Data)
 	}
 
 	if cfg.ManagementCluster != nil {
@@ -99,14 +120,14 @@ func APIServer(cfg *APIServerConfiguration) (Component, error) {
 			cfg.TunnelCASecret = voltronTunnelSecret()
 			tlsSecrets = append(tlsSecrets, cfg.TunnelCASecret)
 		}
-		tlsSecrets = append(tlsSecrets, secret.CopyToNamespace(rmeta.APIServerNamespace(cfg.Installation.Variant), cfg.TunnelCASecret)...)
-		tlsHashAnnotations[voltronTunnelHashAnnotation] = rmeta.AnnotationHash(cfg.TunnelCASecret.Data)
+		tlsSecrets = append(tlsSecrets, replicator.Replicate(rmeta.APIServerNamespace(cfg.Installation.Variant), cfg.TunnelCASecret)...)
 	}
 
 	return &apiServerComponent{
 		cfg:            cfg,
 		tlsSecrets:     tlsSecrets,
 		tlsAnnotations: tlsHashAnnotations,
+		replicator:     replicator,
 	}, nil
 }
 
@@ -120,15 +141,38 @@ type APIServerConfiguration struct {
 	AmazonCloudIntegration      *operatorv1.AmazonCloudIntegration
 	TLSKeyPair                  *corev1.Secret
 	PullSecrets                 []*corev1.Secret
-	Openshift                   bool
 	TunnelCASecret              *corev1.Secret
 	ClusterDomain               string
+	Audit                       *operatorv1.APIServerAudit
+	// AuditPolicy is the ConfigMap holding the audit policy configured by the user, if any.
+	AuditPolicy *corev1.ConfigMap
+	// AuditWebhookSecret is the Secret holding the kubeconfig file of the audit webhook backend, if any.
+	AuditWebhookSecret *corev1.Secret
+	// Replicas overrides the ControlPlaneReplicas of the Installation for the API server deployment.
+	Replicas    *int32
+	Autoscaling *operatorv1.APIServerAutoscaling
+	// DisabledResources are the resources of the projectcalico.org/v3 API group that are not served.
+	DisabledResources []operatorv1.APIServerResource
+	RequestHandling   *operatorv1.APIServerRequestHandling
+	// ComponentResources are the resource requirements of the API server and query server containers.
+	ComponentResources  []operatorv1.APIServerComponentResource
+	QueryServerLogLevel *operatorv1.QueryServerLogLevel
+	// TopologySpreadConstraints of the API server pods. Constraints without a label selector select the API server pods.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint
+	PodDisruptionBudget       *operatorv1.APIServerPodDisruptionBudget
+	// CABundle is the CA bundle of the APIService. When empty, the serving certificate in TLSKeyPair is used, as
+	// it is self-signed.
+	CABundle []byte
+	// KubernetesVersion is the version of the cluster. The PodSecurityPolicies are only rendered when the cluster
+	// supports them, which is assumed when the version is unknown.
+	KubernetesVersion *common.VersionInfo
 }
 
 type apiServerComponent struct {
 	cfg              *APIServerConfiguration
 	tlsSecrets       []*corev1.Secret
 	tlsAnnotations   map[string]string
+	replicator       *secret.Replicator
 	isManagement     bool
 	apiServerImage   string
 	queryServerImage string
@@ -199,7 +243,7 @@ func (c *apiServerComponent) Objects() ([]client.Object, []client.Object) {
 	globalObjects, objsToDelete = populateLists(globalObjects, objsToDelete, c.authReaderRoleBinding)
 	globalObjects, objsToDelete = populateLists(globalObjects, objsToDelete, c.webhookReaderClusterRole)
 	globalObjects, objsToDelete = populateLists(globalObjects, objsToDelete, c.webhookReaderClusterRoleBinding)
-	if !c.cfg.Openshift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() && c.cfg.KubernetesVersion.ProvidesPodSecurityPolicy() {
 		globalObjects, objsToDelete = populateLists(globalObjects, objsToDelete, c.apiServerPodSecurityPolicy)
 	}
 
@@ -207,7 +251,7 @@ func (c *apiServerComponent) Objects() ([]client.Object, []client.Object) {
 	// deleted, since they will be garbage collected on namespace deletion.
 	namespacedObjects := []client.Object{}
 	// Add in image pull secrets.
-	secrets := secret.CopyToNamespace(rmeta.APIServerNamespace(c.cfg.Installation.Variant), c.cfg.PullSecrets...)
+	secrets := c.replicator.Replicate(rmeta.APIServerNamespace(c.cfg.Installation.Variant), c.cfg.PullSecrets...)
 	namespacedObjects = append(namespacedObjects, secret.ToRuntimeObjects(secrets...)...)
 
 	namespacedObjects = append(namespacedObjects,
@@ -216,10 +260,26 @@ func (c *apiServerComponent) Objects() ([]client.Object, []client.Object) {
 		c.apiServerService(),
 	)
 
+	// Scale the deployment with a HorizontalPodAutoscaler if requested.
+	if c.cfg.Autoscaling != nil {
+		namespacedObjects = append(namespacedObjects, c.apiServerHorizontalPodAutoscaler())
+	} else {
+		objsToDelete = append(objsToDelete, c.apiServerHorizontalPodAutoscaler())
+	}
+	if c.cfg.PodDisruptionBudget != nil {
+		namespacedObjects = append(namespacedObjects, c.apiServerPodDisruptionBudget())
+	} else {
+		objsToDelete = append(objsToDelete, c.apiServerPodDisruptionBudget())
+	}
+
 	// Add in certificates for API server TLS.
 	if c.cfg.Installation.CertificateManagement == nil {
 		namespacedObjects = append(namespacedObjects, c.getTLSObjects()...)
-		globalObjects = append(globalObjects, c.apiServiceRegistration(c.cfg.TLSKeyPair.Data[APIServerSecretCertName]))
+		caBundle := c.cfg.CABundle
+		if len(caBundle) == 0 {
+			caBundle = c.cfg.TLSKeyPair.Data[APIServerSecretCertName]
+		}
+		globalObjects = append(globalObjects, c.apiServiceRegistration(caBundle))
 	} else {
 		namespacedObjects = append(namespacedObjects, c.apiServiceRegistration(c.cfg.Installation.CertificateManagement.CACert))
 		globalObjects = append(globalObjects, CSRClusterRoleBinding(csrRolebindingName(c.cfg.Installation.Variant), rmeta.APIServerNamespace(c.cfg.Installation.Variant)))
@@ -236,14 +296,35 @@ func (c *apiServerComponent) Objects() ([]client.Object, []client.Object) {
 		c.tigeraNetworkAdminClusterRole(),
 		c.tieredPolicyPassthruClusterRole(),
 		c.tieredPolicyPassthruClusterRolebinding(),
+	}
+	// The passthrough of UISettings is only needed while they are served.
+	uiSettingsPassthruObjects := []client.Object{
 		c.uiSettingsPassthruClusterRole(),
 		c.uiSettingsPassthruClusterRolebinding(),
 	}
+	if c.resourceDisabled("uisettings") {
+		objsToDelete = append(objsToDelete, uiSettingsPassthruObjects...)
+	} else {
+		globalEnterpriseObjects = append(globalEnterpriseObjects, uiSettingsPassthruObjects...)
+	}
 
 	// Namespaced enterprise-only objects.
 	namespacedEnterpriseObjects := []client.Object{
 		c.auditPolicyConfigMap(),
 	}
+	auditWebhookSecret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      APIServerAuditWebhookSecretName,
+			Namespace: rmeta.APIServerNamespace(operatorv1.TigeraSecureEnterprise),
+		},
+	}
+	if c.cfg.AuditWebhookSecret != nil {
+		auditWebhookSecret.Data = map[string][]byte{
+			APIServerAuditWebhookConfigKey: c.cfg.AuditWebhookSecret.Data[APIServerAuditWebhookConfigKey],
+		}
+		namespacedEnterpriseObjects = append(namespacedEnterpriseObjects, auditWebhookSecret)
+	}
 
 	// Global OSS-only objects.
 	globalCalicoObjects := []client.Object{
@@ -260,6 +341,10 @@ func (c *apiServerComponent) Objects() ([]client.Object, []client.Object) {
 		// Explicitly delete any global OSS objects.
 		// Namespaced objects will be handled by namespace deletion.
 		objsToDelete = append(objsToDelete, globalCalicoObjects...)
+
+		if c.cfg.AuditWebhookSecret == nil {
+			objsToDelete = append(objsToDelete, auditWebhookSecret)
+		}
 	} else {
 		// Create any Calico-only objects
 		globalObjects = append(globalObjects, globalCalicoObjects...)
@@ -276,6 +361,16 @@ func (c *apiServerComponent) Objects() ([]client.Object, []client.Object) {
 	return objsToCreate, objsToDelete
 }
 
+// resourceDisabled returns whether the given resource of the projectcalico.org/v3 API group is not served.
+func (c *apiServerComponent) resourceDisabled(resource operatorv1.APIServerResource) bool {
+	for _, r := range c.cfg.DisabledResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *apiServerComponent) Ready() bool {
 	return true
 }
@@ -306,6 +401,46 @@ func (c *apiServerComponent) apiServiceRegistration(cert []byte) *apiregv1.APISe
 	return s
 }
 
+// APIServerCertManagerCertificate creates the cert-manager Certificate of the API server serving certificate, issued
+// by the given issuer into the APIServerCertManagerSecretName Secret of the operator namespace. cert-manager is not a
+// dependency of the operator, so the Certificate is built as an unstructured object.
+//
+// Both Calico and Calico Enterprise, but different names.
+func APIServerCertManagerCertificate(v operatorv1.ProductVariant, issuer *operatorv1.CertManagerIssuerReference, clusterDomain string) *unstructured.Unstructured {
+	kind := issuer.Kind
+	if kind == "" {
+		kind = "Issuer"
+	}
+	group := issuer.Group
+	if group == "" {
+		group = "cert-manager.io"
+	}
+
+	var dnsNames []interface{}
+	for _, name := range dns.GetServiceDNSNames(ProjectCalicoApiServerServiceName(v), rmeta.APIServerNamespace(v), clusterDomain) {
+		dnsNames = append(dnsNames, name)
+	}
+
+	cert := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"secretName": APIServerCertManagerSecretName(v),
+			"commonName": ProjectCalicoApiServerServiceName(v),
+			"dnsNames":   dnsNames,
+			"usages":     []interface{}{"server auth"},
+			"issuerRef": map[string]interface{}{
+				"name":  issuer.Name,
+				"kind":  kind,
+				"group": group,
+			},
+		},
+	}}
+	cert.SetAPIVersion("cert-manager.io/v1")
+	cert.SetKind("Certificate")
+	cert.SetName(ProjectCalicoApiServerTLSSecretName(v))
+	cert.SetNamespace(common.OperatorNamespace())
+	return cert
+}
+
 // delegateAuthClusterRoleBinding creates a clusterrolebinding that allows the API server to delegate
 // authn/authz requests to main API server.
 //
@@ -475,7 +610,7 @@ func (c *apiServerComponent) calicoCustomResourcesClusterRole() *rbacv1.ClusterR
 			},
 		},
 	}
-	if !c.cfg.Openshift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		rules = append(rules, rbacv1.PolicyRule{
 			APIGroups:     []string{"policy"},
@@ -484,6 +619,14 @@ func (c *apiServerComponent) calicoCustomResourcesClusterRole() *rbacv1.ClusterR
 			ResourceNames: []string{"calico-apiserver"},
 		})
 	}
+	if rh := c.cfg.RequestHandling; rh != nil && rh.PriorityAndFairness != nil && *rh.PriorityAndFairness == operatorv1.PriorityAndFairnessEnabled {
+		// Priority and fairness classifies requests with the flow control configuration of the cluster.
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{"flowcontrol.apiserver.k8s.io"},
+			Resources: []string{"flowschemas", "prioritylevelconfigurations"},
+			Verbs:     []string{"get", "list", "watch"},
+		})
+	}
 
 	return &rbacv1.ClusterRole{
 		TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
@@ -749,15 +892,20 @@ func (c *apiServerComponent) apiServerService() *corev1.Service {
 	return s
 }
 
-// apiServer creates a deployment containing the API and query servers.
-func (c *apiServerComponent) apiServerDeployment() *appsv1.Deployment {
-	var name string
-	switch c.cfg.Installation.Variant {
+// apiServerDeploymentName returns the name of the API server deployment for the given variant.
+func apiServerDeploymentName(v operatorv1.ProductVariant) string {
+	switch v {
 	case operatorv1.TigeraSecureEnterprise:
-		name = "tigera-apiserver"
+		return "tigera-apiserver"
 	case operatorv1.Calico:
-		name = "calico-apiserver"
+		return "calico-apiserver"
 	}
+	return ""
+}
+
+// apiServer creates a deployment containing the API and query servers.
+func (c *apiServerComponent) apiServerDeployment() *appsv1.Deployment {
+	name := apiServerDeploymentName(c.cfg.Installation.Variant)
 
 	hostNetwork := c.hostNetwork()
 	dnsPolicy := corev1.DNSClusterFirst
@@ -789,7 +937,7 @@ func (c *apiServerComponent) apiServerDeployment() *appsv1.Deployment {
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: c.cfg.Installation.ControlPlaneReplicas,
+			Replicas: c.replicas(),
 			Strategy: appsv1.DeploymentStrategy{
 				Type: appsv1.RecreateDeploymentStrategyType,
 			},
@@ -821,24 +969,122 @@ func (c *apiServerComponent) apiServerDeployment() *appsv1.Deployment {
 		},
 	}
 
-	if c.cfg.Installation.ControlPlaneReplicas != nil && *c.cfg.Installation.ControlPlaneReplicas > 1 {
+	if c.multipleReplicas() {
 		d.Spec.Template.Spec.Affinity = podaffinity.NewPodAntiAffinity(name, rmeta.APIServerNamespace(c.cfg.Installation.Variant))
 	}
+	d.Spec.Template.Spec.TopologySpreadConstraints = c.topologySpreadConstraints()
 
 	if c.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
 		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, c.queryServerContainer())
 	}
 
+	// Restart the API server when any of the secrets copied into its namespace changes.
+	c.replicator.AnnotateConsumers(d)
+
 	return d
 }
 
+// replicas returns the replica count of the API server deployment. No count is set when the deployment is scaled by a
+// HorizontalPodAutoscaler, so that the count set by the autoscaler is retained.
+func (c *apiServerComponent) replicas() *int32 {
+	if c.cfg.Autoscaling != nil {
+		return nil
+	}
+	if c.cfg.Replicas != nil {
+		return c.cfg.Replicas
+	}
+	return c.cfg.Installation.ControlPlaneReplicas
+}
+
+// multipleReplicas returns whether the API server deployment may run more than one replica.
+func (c *apiServerComponent) multipleReplicas() bool {
+	if c.cfg.Autoscaling != nil {
+		return c.cfg.Autoscaling.MaxReplicas > 1
+	}
+	replicas := c.replicas()
+	return replicas != nil && *replicas > 1
+}
+
+// apiServerHorizontalPodAutoscaler creates the HorizontalPodAutoscaler that scales the API server deployment.
+//
+// Both Calico and Calico Enterprise, with the same name as the deployment.
+func (c *apiServerComponent) apiServerHorizontalPodAutoscaler() *autoscalingv1.HorizontalPodAutoscaler {
+	name := apiServerDeploymentName(c.cfg.Installation.Variant)
+	hpa := &autoscalingv1.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{Kind: "HorizontalPodAutoscaler", APIVersion: "autoscaling/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: rmeta.APIServerNamespace(c.cfg.Installation.Variant),
+		},
+	}
+	if c.cfg.Autoscaling == nil {
+		return hpa
+	}
+
+	targetCPU := int32(80)
+	if c.cfg.Autoscaling.TargetCPUUtilizationPercentage != nil {
+		targetCPU = *c.cfg.Autoscaling.TargetCPUUtilizationPercentage
+	}
+	hpa.Spec = autoscalingv1.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+			Kind:       "Deployment",
+			Name:       name,
+			APIVersion: "apps/v1",
+		},
+		MinReplicas:                    c.cfg.Autoscaling.MinReplicas,
+		MaxReplicas:                    c.cfg.Autoscaling.MaxReplicas,
+		TargetCPUUtilizationPercentage: &targetCPU,
+	}
+	return hpa
+}
+
+// apiServerPodDisruptionBudget creates the PodDisruptionBudget of the API server pods.
+//
+// Both Calico and Calico Enterprise, with the same name as the deployment.
+func (c *apiServerComponent) apiServerPodDisruptionBudget() *policyv1beta1.PodDisruptionBudget {
+	name := apiServerDeploymentName(c.cfg.Installation.Variant)
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{Kind: "PodDisruptionBudget", APIVersion: "policy/v1beta1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: rmeta.APIServerNamespace(c.cfg.Installation.Variant),
+		},
+	}
+	if c.cfg.PodDisruptionBudget == nil {
+		return pdb
+	}
+
+	pdb.Spec = policyv1beta1.PodDisruptionBudgetSpec{
+		Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": name}},
+		MinAvailable:   c.cfg.PodDisruptionBudget.MinAvailable,
+		MaxUnavailable: c.cfg.PodDisruptionBudget.MaxUnavailable,
+	}
+	if pdb.Spec.MinAvailable == nil && pdb.Spec.MaxUnavailable == nil {
+		maxUnavailable := intstr.FromInt(1)
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	}
+	return pdb
+}
+
+// topologySpreadConstraints returns the topology spread constraints of the API server pods.
+func (c *apiServerComponent) topologySpreadConstraints() []corev1.TopologySpreadConstraint {
+	var constraints []corev1.TopologySpreadConstraint
+	for _, tsc := range c.cfg.TopologySpreadConstraints {
+		if tsc.LabelSelector == nil {
+			tsc.LabelSelector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"k8s-app": apiServerDeploymentName(c.cfg.Installation.Variant)},
+			}
+		}
+		constraints = append(constraints, tsc)
+	}
+	return constraints
+}
+
 func (c *apiServerComponent) hostNetwork() bool {
 	hostNetwork := c.cfg.ForceHostNetwork
-	if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderEKS &&
-		c.cfg.Installation.CNI.Type == operatorv1.PluginCalico {
-		// Workaround the fact that webhooks don't work for non-host-networked pods
-		// when in this networking mode on EKS, because the control plane nodes don't run
-		// Calico.
+	if provider.For(c.cfg.Installation.KubernetesProvider).WebhooksRequireHostNetwork(c.cfg.Installation.CNI.Type) {
+		// Workaround the fact that webhooks don't work for non-host-networked pods when the control plane can't reach
+		// the pod network.
 		hostNetwork = true
 	}
 	return hostNetwork
@@ -849,9 +1095,14 @@ func (c *apiServerComponent) apiServerContainer() corev1.Container {
 	volumeMounts := []corev1.VolumeMount{}
 	if c.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
 		volumeMounts = append(volumeMounts,
-			corev1.VolumeMount{Name: "tigera-audit-logs", MountPath: "/var/log/calico/audit"},
+			corev1.VolumeMount{Name: "tigera-audit-logs", MountPath: filepath.Dir(c.auditLogPath())},
 			corev1.VolumeMount{Name: "tigera-audit-policy", MountPath: "/etc/tigera/audit"},
 		)
+		if c.cfg.AuditWebhookSecret != nil {
+			volumeMounts = append(volumeMounts,
+				corev1.VolumeMount{Name: APIServerAuditWebhookSecretName, MountPath: "/etc/tigera/audit-webhook", ReadOnly: true},
+			)
+		}
 	}
 
 	volumeMounts = append(volumeMounts,
@@ -868,11 +1119,8 @@ func (c *apiServerComponent) apiServerContainer() corev1.Container {
 		)
 	}
 
-	// On OpenShift apiserver needs privileged access to write audit logs to host path volume
-	isPrivileged := false
-	if c.cfg.Openshift {
-		isPrivileged = true
-	}
+	// The apiserver may need privileged access to write audit logs to host path volume
+	isPrivileged := provider.For(c.cfg.Installation.KubernetesProvider).PrivilegedHostPathWriters()
 
 	env := []corev1.EnvVar{
 		{Name: "DATASTORE_TYPE", Value: "kubernetes"},
@@ -893,10 +1141,11 @@ func (c *apiServerComponent) apiServerContainer() corev1.Container {
 	}
 
 	apiServer := corev1.Container{
-		Name:  name,
-		Image: c.apiServerImage,
-		Args:  c.startUpArgs(),
-		Env:   env,
+		Name:      name,
+		Image:     c.apiServerImage,
+		Args:      c.startUpArgs(),
+		Env:       env,
+		Resources: c.resourceRequirements(operatorv1.ComponentNameAPIServer),
 		// Needed for permissions to write to the audit log
 		SecurityContext: &corev1.SecurityContext{
 			Privileged: &isPrivileged,
@@ -928,6 +1177,11 @@ func (c *apiServerComponent) apiServerContainer() corev1.Container {
 		},
 	}
 
+	if c.hostNetwork() {
+		// Declare the port so that host networked replicas are not scheduled onto the same node.
+		apiServer.Ports = []corev1.ContainerPort{{Name: "apiserver", ContainerPort: apiServerPort, Protocol: corev1.ProtocolTCP}}
+	}
+
 	return apiServer
 }
 
@@ -939,8 +1193,19 @@ func (c *apiServerComponent) startUpArgs() []string {
 	if c.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
 		args = append(args,
 			"--audit-policy-file=/etc/tigera/audit/policy.conf",
-			"--audit-log-path=/var/log/calico/audit/tsee-audit.log",
+			fmt.Sprintf("--audit-log-path=%s", c.auditLogPath()),
 		)
+		args = append(args, c.auditArgs()...)
+	}
+
+	args = append(args, c.requestHandlingArgs()...)
+
+	if len(c.cfg.DisabledResources) > 0 {
+		var runtimeConfig []string
+		for _, r := range c.cfg.DisabledResources {
+			runtimeConfig = append(runtimeConfig, fmt.Sprintf("projectcalico.org/v3/%s=false", r))
+		}
+		args = append(args, fmt.Sprintf("--runtime-config=%s", strings.Join(runtimeConfig, ",")))
 	}
 
 	if c.cfg.ManagementCluster != nil {
@@ -953,11 +1218,47 @@ func (c *apiServerComponent) startUpArgs() []string {
 	return args
 }
 
+// resourceRequirements returns the resource requirements configured for the given container, if any.
+func (c *apiServerComponent) resourceRequirements(name operatorv1.APIServerComponentName) corev1.ResourceRequirements {
+	for _, cr := range c.cfg.ComponentResources {
+		if cr.ComponentName == name && cr.ResourceRequirements != nil {
+			return *cr.ResourceRequirements
+		}
+	}
+	return corev1.ResourceRequirements{}
+}
+
+// requestHandlingArgs returns the arguments limiting the requests served by the API server.
+func (c *apiServerComponent) requestHandlingArgs() []string {
+	rh := c.cfg.RequestHandling
+	if rh == nil {
+		return nil
+	}
+
+	var args []string
+	if rh.RequestTimeout != nil {
+		args = append(args, fmt.Sprintf("--request-timeout=%s", rh.RequestTimeout.Duration))
+	}
+	if rh.MaxRequestsInFlight != nil {
+		args = append(args, fmt.Sprintf("--max-requests-inflight=%d", *rh.MaxRequestsInFlight))
+	}
+	if rh.MaxMutatingRequestsInFlight != nil {
+		args = append(args, fmt.Sprintf("--max-mutating-requests-inflight=%d", *rh.MaxMutatingRequestsInFlight))
+	}
+	if rh.PriorityAndFairness != nil {
+		args = append(args, fmt.Sprintf("--enable-priority-and-fairness=%t", *rh.PriorityAndFairness == operatorv1.PriorityAndFairnessEnabled))
+	}
+	return args
+}
+
 // queryServerContainer creates the query server container.
 func (c *apiServerComponent) queryServerContainer() corev1.Container {
+	logLevel := "info"
+	if c.cfg.QueryServerLogLevel != nil {
+		logLevel = strings.ToLower(string(*c.cfg.QueryServerLogLevel))
+	}
 	env := []corev1.EnvVar{
-		// Set queryserver logging to "info"
-		{Name: "LOGLEVEL", Value: "info"},
+		{Name: "LOGLEVEL", Value: logLevel},
 		{Name: "DATASTORE_TYPE", Value: "kubernetes"},
 	}
 
@@ -969,9 +1270,10 @@ func (c *apiServerComponent) queryServerContainer() corev1.Container {
 	}
 
 	container := corev1.Container{
-		Name:  "tigera-queryserver",
-		Image: c.queryServerImage,
-		Env:   env,
+		Name:      "tigera-queryserver",
+		Image:     c.queryServerImage,
+		Env:       env,
+		Resources: c.resourceRequirements(operatorv1.ComponentNameQueryServer),
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
 				HTTPGet: &corev1.HTTPGetAction{
@@ -985,6 +1287,10 @@ func (c *apiServerComponent) queryServerContainer() corev1.Container {
 		},
 		SecurityContext: podsecuritycontext.NewBaseContext(),
 	}
+
+	if c.hostNetwork() {
+		container.Ports = []corev1.ContainerPort{{Name: "queryserver", ContainerPort: queryServerPort, Protocol: corev1.ProtocolTCP}}
+	}
 	return container
 }
 
@@ -998,7 +1304,7 @@ func (c *apiServerComponent) apiServerVolumes() []corev1.Volume {
 				Name: "tigera-audit-logs",
 				VolumeSource: corev1.VolumeSource{
 					HostPath: &corev1.HostPathVolumeSource{
-						Path: "/var/log/calico/audit",
+						Path: filepath.Dir(c.auditLogPath()),
 						Type: &hostPathType,
 					},
 				},
@@ -1019,6 +1325,17 @@ func (c *apiServerComponent) apiServerVolumes() []corev1.Volume {
 			},
 		)
 
+		if c.cfg.AuditWebhookSecret != nil {
+			volumes = append(volumes, corev1.Volume{
+				Name: APIServerAuditWebhookSecretName,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: APIServerAuditWebhookSecretName,
+					},
+				},
+			})
+		}
+
 		if c.cfg.ManagementCluster != nil {
 			volumes = append(volumes, corev1.Volume{
 				// Append volume for tunnel CA certificate
@@ -1159,7 +1476,7 @@ func (c *apiServerComponent) tigeraCustomResourcesClusterRole() *rbacv1.ClusterR
 			},
 		},
 	}
-	if !c.cfg.Openshift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		rules = append(rules, rbacv1.PolicyRule{
 			APIGroups:     []string{"policy"},
@@ -1628,6 +1945,11 @@ rules:
     - tiers
     - hostendpoints`
 
+	auditPolicy := defaultAuditPolicy
+	if c.cfg.AuditPolicy != nil {
+		auditPolicy = c.cfg.AuditPolicy.Data[APIServerAuditPolicyKey]
+	}
+
 	return &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{
@@ -1636,7 +1958,46 @@ rules:
 			Name:      "tigera-audit-policy",
 		},
 		Data: map[string]string{
-			"config": defaultAuditPolicy,
+			"config": auditPolicy,
 		},
 	}
 }
+
+// auditLogPath returns the path of the audit log file. Its directory is mounted from the host.
+//
+// Calico Enterprise only
+func (c *apiServerComponent) auditLogPath() string {
+	if c.cfg.Audit != nil && c.cfg.Audit.Log != nil && c.cfg.Audit.Log.Path != "" {
+		return c.cfg.Audit.Log.Path
+	}
+	return defaultAuditLogPath
+}
+
+// auditArgs returns the arguments configuring the rotation of the audit log file and the audit webhook backend.
+//
+// Calico Enterprise only
+func (c *apiServerComponent) auditArgs() []string {
+	if c.cfg.Audit == nil {
+		return nil
+	}
+
+	var args []string
+	if auditLog := c.cfg.Audit.Log; auditLog != nil {
+		if auditLog.MaxAge != nil {
+			args = append(args, fmt.Sprintf("--audit-log-maxage=%d", *auditLog.MaxAge))
+		}
+		if auditLog.MaxBackups != nil {
+			args = append(args, fmt.Sprintf("--audit-log-maxbackup=%d", *auditLog.MaxBackups))
+		}
+		if auditLog.MaxSize != nil {
+			args = append(args, fmt.Sprintf("--audit-log-maxsize=%d", *auditLog.MaxSize))
+		}
+	}
+	if c.cfg.AuditWebhookSecret != nil {
+		args = append(args, fmt.Sprintf("--audit-webhook-config-file=/etc/tigera/audit-webhook/%s", APIServerAuditWebhookConfigKey))
+		if webhook := c.cfg.Audit.Webhook; webhook != nil && webhook.Mode != nil {
+			args = append(args, fmt.Sprintf("--audit-webhook-mode=%s", *webhook.Mode))
+		}
+	}
+	return args
+}
@@ -28,10 +28,14 @@ import (
 	"github.com/onsi/gomega/gstruct"
 	"github.com/openshift/library-go/pkg/crypto"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	apiregv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -58,6 +62,7 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 	BeforeEach(func() {
 		instance = &operatorv1.InstallationSpec{
 			ControlPlaneReplicas: &replicas,
+			KubernetesProvider:   operatorv1.ProviderOpenShift,
 			Registry:             "testregistry.com/",
 			Variant:              operatorv1.TigeraSecureEnterprise,
 		}
@@ -78,7 +83,6 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 			K8SServiceEndpoint: k8sapi.ServiceEndpoint{},
 			Installation:       instance,
 			TLSKeyPair:         tlsKeyPair,
-			Openshift:          openshift,
 			ClusterDomain:      dns.DefaultClusterDomain,
 		}
 	})
@@ -558,6 +562,14 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 
 		deployment := deploymentResource.(*appsv1.Deployment)
 		rtest.ExpectK8sServiceEpEnvVars(deployment.Spec.Template.Spec, "k8shost", "1234")
+		Expect(deployment.Spec.Template.Spec.HostNetwork).To(BeTrue())
+		Expect(deployment.Spec.Template.Spec.DNSPolicy).To(Equal(corev1.DNSClusterFirstWithHostNet))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Ports).To(ConsistOf(
+			corev1.ContainerPort{Name: "apiserver", ContainerPort: 5443, Protocol: corev1.ProtocolTCP},
+		))
+		Expect(deployment.Spec.Template.Spec.Containers[1].Ports).To(ConsistOf(
+			corev1.ContainerPort{Name: "queryserver", ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+		))
 	})
 
 	It("should not set KUBERENETES_SERVICE_... variables if not host networked on Docker EE with proxy.local", func() {
@@ -761,6 +773,82 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 		Expect((dep.(*appsv1.Deployment)).Spec.Template.Spec.Containers[0].Args).To(ConsistOf(expectedArgs))
 	})
 
+	It("should render the audit configuration", func() {
+		maxAge, maxBackups, maxSize := int32(7), int32(3), int32(100)
+		mode := operatorv1.AuditWebhookModeBatch
+		cfg.Audit = &operatorv1.APIServerAudit{
+			PolicyConfigMapName: "my-audit-policy",
+			Log: &operatorv1.APIServerAuditLog{
+				Path:       "/var/log/audit/apiserver.log",
+				MaxAge:     &maxAge,
+				MaxBackups: &maxBackups,
+				MaxSize:    &maxSize,
+			},
+			Webhook: &operatorv1.APIServerAuditWebhook{ConfigSecretName: "my-audit-webhook", Mode: &mode},
+		}
+		cfg.AuditPolicy = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-audit-policy", Namespace: common.OperatorNamespace()},
+			Data:       map[string]string{render.APIServerAuditPolicyKey: "my-policy"},
+		}
+		cfg.AuditWebhookSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-audit-webhook", Namespace: common.OperatorNamespace()},
+			Data:       map[string][]byte{render.APIServerAuditWebhookConfigKey: []byte("my-kubeconfig")},
+		}
+
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, toDelete := component.Objects()
+		Expect(rtest.GetResource(toDelete, render.APIServerAuditWebhookSecretName, "tigera-system", "", "v1", "Secret")).To(BeNil())
+
+		policy := rtest.GetResource(resources, "tigera-audit-policy", "tigera-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(policy.Data).To(Equal(map[string]string{"config": "my-policy"}))
+		webhookSecret := rtest.GetResource(resources, render.APIServerAuditWebhookSecretName, "tigera-system", "", "v1", "Secret").(*corev1.Secret)
+		Expect(webhookSecret.Data).To(Equal(map[string][]byte{render.APIServerAuditWebhookConfigKey: []byte("my-kubeconfig")}))
+
+		d := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(d.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/audit-policy"))
+		Expect(d.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/audit-webhook"))
+		Expect(d.Spec.Template.Spec.Containers[0].Args).To(ContainElements(
+			"--audit-policy-file=/etc/tigera/audit/policy.conf",
+			"--audit-log-path=/var/log/audit/apiserver.log",
+			"--audit-log-maxage=7",
+			"--audit-log-maxbackup=3",
+			"--audit-log-maxsize=100",
+			"--audit-webhook-config-file=/etc/tigera/audit-webhook/config",
+			"--audit-webhook-mode=batch",
+		))
+		Expect(d.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElements(
+			corev1.VolumeMount{Name: "tigera-audit-logs", MountPath: "/var/log/audit"},
+			corev1.VolumeMount{Name: render.APIServerAuditWebhookSecretName, MountPath: "/etc/tigera/audit-webhook", ReadOnly: true},
+		))
+		Expect(d.Spec.Template.Spec.Volumes[0].Name).To(Equal("tigera-audit-logs"))
+		Expect(d.Spec.Template.Spec.Volumes[0].HostPath.Path).To(Equal("/var/log/audit"))
+	})
+
+	It("should restart the API server only when a copied secret changes", func() {
+		cfg.ManagementCluster = managementCluster
+		getAnnotations := func() map[string]string {
+			component, err := render.APIServer(cfg)
+			Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			resources, _ := component.Objects()
+			d := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+			return d.Spec.Template.Annotations
+		}
+
+		tlsKey := secret.HashAnnotationPrefix + render.ProjectCalicoApiServerTLSSecretName(instance.Variant)
+		tunnelKey := secret.HashAnnotationPrefix + render.VoltronTunnelSecretName
+		annotations := getAnnotations()
+		Expect(annotations).To(HaveKey(tlsKey))
+		Expect(annotations).To(HaveKey(tunnelKey))
+
+		cfg.TLSKeyPair.Data[render.APIServerSecretCertName] = []byte("new-cert")
+		updated := getAnnotations()
+		Expect(updated[tlsKey]).NotTo(Equal(annotations[tlsKey]))
+		Expect(updated[tunnelKey]).To(Equal(annotations[tunnelKey]))
+	})
+
 	It("should add an init container if certificate management is enabled", func() {
 		cfg.Installation.CertificateManagement = &operatorv1.CertificateManagement{SignerName: "a.b/c"}
 		component, err := render.APIServer(cfg)
@@ -839,6 +927,149 @@ var _ = Describe("API server rendering tests (Calico Enterprise)", func() {
 		Expect(deploy.Spec.Template.Spec.Affinity).NotTo(BeNil())
 		Expect(deploy.Spec.Template.Spec.Affinity).To(Equal(podaffinity.NewPodAntiAffinity("tigera-apiserver", "tigera-system")))
 	})
+
+	It("should not serve disabled resources", func() {
+		cfg.DisabledResources = []operatorv1.APIServerResource{"uisettingsgroups", "uisettings"}
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, toDelete := component.Objects()
+
+		deploy := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(deploy.Spec.Template.Spec.Containers[0].Args).To(ContainElement(
+			"--runtime-config=projectcalico.org/v3/uisettingsgroups=false,projectcalico.org/v3/uisettings=false",
+		))
+		Expect(rtest.GetResource(resources, "v3.projectcalico.org", "", "apiregistration.k8s.io", "v1", "APIService")).NotTo(BeNil())
+		Expect(rtest.GetResource(resources, "tigera-uisettings-passthrough", "", "rbac.authorization.k8s.io", "v1", "ClusterRole")).To(BeNil())
+		Expect(rtest.GetResource(toDelete, "tigera-uisettings-passthrough", "", "rbac.authorization.k8s.io", "v1", "ClusterRole")).NotTo(BeNil())
+		Expect(rtest.GetResource(toDelete, "tigera-uisettings-passthrough", "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding")).NotTo(BeNil())
+	})
+
+	It("should render the request handling configuration", func() {
+		maxInFlight, maxMutatingInFlight := int32(800), int32(100)
+		apf := operatorv1.PriorityAndFairnessEnabled
+		cfg.RequestHandling = &operatorv1.APIServerRequestHandling{
+			RequestTimeout:              &metav1.Duration{Duration: 2 * time.Minute},
+			MaxRequestsInFlight:         &maxInFlight,
+			MaxMutatingRequestsInFlight: &maxMutatingInFlight,
+			PriorityAndFairness:         &apf,
+		}
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, _ := component.Objects()
+
+		deploy := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(deploy.Spec.Template.Spec.Containers[0].Args).To(ContainElements(
+			"--request-timeout=2m0s",
+			"--max-requests-inflight=800",
+			"--max-mutating-requests-inflight=100",
+			"--enable-priority-and-fairness=true",
+		))
+
+		clusterRole := rtest.GetResource(resources, "calico-crds", "", "rbac.authorization.k8s.io", "v1", "ClusterRole").(*rbacv1.ClusterRole)
+		Expect(clusterRole.Rules).To(ContainElement(rbacv1.PolicyRule{
+			APIGroups: []string{"flowcontrol.apiserver.k8s.io"},
+			Resources: []string{"flowschemas", "prioritylevelconfigurations"},
+			Verbs:     []string{"get", "list", "watch"},
+		}))
+	})
+
+	It("should configure the API server and query server containers independently", func() {
+		queryServerResources := &corev1.ResourceRequirements{
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}
+		logLevel := operatorv1.QueryServerLogLevelDebug
+		cfg.ComponentResources = []operatorv1.APIServerComponentResource{
+			{ComponentName: operatorv1.ComponentNameQueryServer, ResourceRequirements: queryServerResources},
+		}
+		cfg.QueryServerLogLevel = &logLevel
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, _ := component.Objects()
+
+		deploy := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(deploy.Spec.Template.Spec.Containers[0].Resources).To(Equal(corev1.ResourceRequirements{}))
+		Expect(deploy.Spec.Template.Spec.Containers[1].Resources).To(Equal(*queryServerResources))
+		rtest.ExpectEnv(deploy.Spec.Template.Spec.Containers[1].Env, "LOGLEVEL", "debug")
+	})
+
+	It("should render topology spread constraints and a PodDisruptionBudget", func() {
+		cfg.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+		}
+		minAvailable := intstr.FromString("50%")
+		cfg.PodDisruptionBudget = &operatorv1.APIServerPodDisruptionBudget{MinAvailable: &minAvailable}
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, _ := component.Objects()
+
+		deploy := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(deploy.Spec.Template.Spec.TopologySpreadConstraints).To(ConsistOf(corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "tigera-apiserver"}},
+		}))
+
+		pdb := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "policy", "v1beta1", "PodDisruptionBudget").(*policyv1beta1.PodDisruptionBudget)
+		Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{"k8s-app": "tigera-apiserver"}))
+		Expect(*pdb.Spec.MinAvailable).To(Equal(minAvailable))
+		Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+	})
+
+	It("should use the CA bundle of the certificate issuer for the APIService", func() {
+		cfg.CABundle = []byte("ca-bundle")
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, _ := component.Objects()
+
+		apiService := rtest.GetResource(resources, "v3.projectcalico.org", "", "apiregistration.k8s.io", "v1", "APIService").(*apiregv1.APIService)
+		Expect(apiService.Spec.CABundle).To(Equal([]byte("ca-bundle")))
+	})
+
+	It("should render a cert-manager Certificate for the API server", func() {
+		cert := render.APIServerCertManagerCertificate(operatorv1.TigeraSecureEnterprise, &operatorv1.CertManagerIssuerReference{Name: "my-issuer", Kind: "ClusterIssuer"}, dns.DefaultClusterDomain)
+		Expect(cert.GetAPIVersion()).To(Equal("cert-manager.io/v1"))
+		Expect(cert.GetKind()).To(Equal("Certificate"))
+		Expect(cert.GetNamespace()).To(Equal(common.OperatorNamespace()))
+		Expect(cert.Object["spec"]).To(HaveKeyWithValue("secretName", render.APIServerCertManagerSecretName(operatorv1.TigeraSecureEnterprise)))
+		Expect(cert.Object["spec"]).To(HaveKeyWithValue("issuerRef", map[string]interface{}{
+			"name":  "my-issuer",
+			"kind":  "ClusterIssuer",
+			"group": "cert-manager.io",
+		}))
+		Expect(cert.Object["spec"]).To(HaveKeyWithValue("dnsNames", ContainElement("tigera-api.tigera-system.svc")))
+	})
+
+	It("should override the ControlPlaneReplicas with the replicas of the APIServer", func() {
+		var replicas int32 = 3
+		cfg.Replicas = &replicas
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, toDelete := component.Objects()
+
+		deploy := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(*deploy.Spec.Replicas).To(BeEquivalentTo(3))
+		Expect(rtest.GetResource(toDelete, "tigera-apiserver", "tigera-system", "autoscaling", "v1", "HorizontalPodAutoscaler")).NotTo(BeNil())
+	})
+
+	It("should render a HorizontalPodAutoscaler when autoscaling is enabled", func() {
+		var minReplicas int32 = 2
+		cfg.Autoscaling = &operatorv1.APIServerAutoscaling{MinReplicas: &minReplicas, MaxReplicas: 5}
+		component, err := render.APIServer(cfg)
+		Expect(err).To(BeNil(), "Expected APIServer to create successfully %s", err)
+		resources, _ := component.Objects()
+
+		deploy := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(deploy.Spec.Replicas).To(BeNil())
+		Expect(deploy.Spec.Template.Spec.Affinity).To(Equal(podaffinity.NewPodAntiAffinity("tigera-apiserver", "tigera-system")))
+
+		hpa := rtest.GetResource(resources, "tigera-apiserver", "tigera-system", "autoscaling", "v1", "HorizontalPodAutoscaler").(*autoscalingv1.HorizontalPodAutoscaler)
+		Expect(hpa.Spec.ScaleTargetRef).To(Equal(autoscalingv1.CrossVersionObjectReference{Kind: "Deployment", Name: "tigera-apiserver", APIVersion: "apps/v1"}))
+		Expect(*hpa.Spec.MinReplicas).To(BeEquivalentTo(2))
+		Expect(hpa.Spec.MaxReplicas).To(BeEquivalentTo(5))
+		Expect(*hpa.Spec.TargetCPUUtilizationPercentage).To(BeEquivalentTo(80))
+	})
 })
 
 func verifyAPIService(service *apiregv1.APIService, enterprise bool, clusterDomain string) {
@@ -1112,6 +1343,7 @@ var _ = Describe("API server rendering tests (Calico)", func() {
 	BeforeEach(func() {
 		instance = &operatorv1.InstallationSpec{
 			ControlPlaneReplicas: &replicas,
+			KubernetesProvider:   operatorv1.ProviderOpenShift,
 			Registry:             "testregistry.com/",
 			Variant:              operatorv1.Calico,
 		}
@@ -1132,7 +1364,6 @@ var _ = Describe("API server rendering tests (Calico)", func() {
 			K8SServiceEndpoint: k8sapi.ServiceEndpoint{},
 			Installation:       instance,
 			TLSKeyPair:         tlsKeyPair,
-			Openshift:          openshift,
 			ClusterDomain:      dns.DefaultClusterDomain,
 		}
 	})
@@ -28,6 +28,7 @@ import (
 	"github.com/tigera/operator/pkg/ptr"
 	"github.com/tigera/operator/pkg/render"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/provider"
 	"github.com/tigera/operator/pkg/render/common/secret"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -121,12 +122,12 @@ func (c *component) Objects() ([]client.Object, []client.Object) {
 	objs = append(objs, c.config.envoyConfigMap)
 	objs = append(objs, c.daemonset())
 
-	if c.config.Installation.KubernetesProvider == operatorv1.ProviderDockerEE {
+	p := provider.For(c.config.Installation.KubernetesProvider)
+	if p.ClusterAdminForPrivilegedPods() {
 		objs = append(objs, c.clusterAdminClusterRoleBinding())
 	}
 
-	// If we're running on openshift, we need to add in an SCC.
-	if c.config.Installation.KubernetesProvider == operatorv1.ProviderOpenShift {
+	if p.SecurityContextConstraints() {
 		objs = append(objs, c.securityContextConstraints())
 	}
 
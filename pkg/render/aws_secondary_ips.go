@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+// AWSIAMRoleAnnotation is set on the calico-node service account to the IAM role it assumes to manage the ENIs.
+const AWSIAMRoleAnnotation = "eks.amazonaws.com/role-arn"
+
+// AWSSecondaryIPPools renders the IP pools backed by the subnets of the VPC. The pools are never deleted by the
+// operator, since pods may still have addresses of a pool which is no longer in the installation.
+func AWSSecondaryIPPools(installation *operatorv1.InstallationSpec) Component {
+	return &awsSecondaryIPPoolsComponent{installation: installation}
+}
+
+type awsSecondaryIPPoolsComponent struct {
+	installation *operatorv1.InstallationSpec
+}
+
+func (c *awsSecondaryIPPoolsComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on an IPPool
+	return nil
+}
+
+func (c *awsSecondaryIPPoolsComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *awsSecondaryIPPoolsComponent) Objects() ([]client.Object, []client.Object) {
+	aws := awsSecondaryIPs(c.installation)
+	if aws == nil {
+		return nil, nil
+	}
+
+	var toCreate []client.Object
+	for _, p := range aws.SubnetPools {
+		pool := &crdv1.IPPool{
+			TypeMeta:   metav1.TypeMeta{Kind: crdv1.KindIPPool, APIVersion: "crd.projectcalico.org/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: p.Name},
+			Spec: crdv1.IPPoolSpec{
+				CIDR: p.CIDR,
+				// The addresses are routed by the VPC, so the traffic of the pods is neither encapsulated nor NATed.
+				VXLANMode:    crdv1.VXLANModeNever,
+				IPIPMode:     crdv1.IPIPModeNever,
+				NodeSelector: "all()",
+				AWSSubnetID:  p.SubnetID,
+			},
+		}
+		if p.BlockSize != nil {
+			pool.Spec.BlockSize = int(*p.BlockSize)
+		}
+		if p.NodeSelector != "" {
+			pool.Spec.NodeSelector = p.NodeSelector
+		}
+		toCreate = append(toCreate, pool)
+	}
+	return toCreate, nil
+}
+
+func (c *awsSecondaryIPPoolsComponent) Ready() bool {
+	return true
+}
+
+// awsSecondaryIPs returns the AWS secondary IPs configuration of the installation, or nil if they are not used.
+func awsSecondaryIPs(installation *operatorv1.InstallationSpec) *operatorv1.AWSSecondaryIPs {
+	if installation.CalicoNetwork == nil || installation.CalicoNetwork.AWSSecondaryIPs == nil {
+		return nil
+	}
+	aws := installation.CalicoNetwork.AWSSecondaryIPs
+	if aws.Mode != nil && *aws.Mode == operatorv1.AWSSecondaryIPDisabled {
+		return nil
+	}
+	return aws
+}
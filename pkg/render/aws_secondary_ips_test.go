@@ -0,0 +1,80 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("AWS secondary IP pools rendering tests", func() {
+	var installation *operatorv1.InstallationSpec
+	BeforeEach(func() {
+		blockSize := int32(28)
+		installation = &operatorv1.InstallationSpec{
+			Variant: operatorv1.TigeraSecureEnterprise,
+			CalicoNetwork: &operatorv1.CalicoNetworkSpec{
+				AWSSecondaryIPs: &operatorv1.AWSSecondaryIPs{
+					SubnetPools: []operatorv1.AWSSubnetPool{
+						{
+							Name:         "egress-a",
+							CIDR:         "10.0.16.0/24",
+							SubnetID:     "subnet-0123456789abcdef0",
+							BlockSize:    &blockSize,
+							NodeSelector: "topology.kubernetes.io/zone == 'us-west-2a'",
+						},
+						{Name: "egress-b", CIDR: "10.0.17.0/24", SubnetID: "subnet-0123456789abcdef1"},
+					},
+				},
+			},
+		}
+	})
+
+	It("should render an IP pool per subnet", func() {
+		component := render.AWSSecondaryIPPools(installation)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, toDelete := component.Objects()
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(2))
+
+		pool := rtest.GetResource(toCreate, "egress-a", "", "crd.projectcalico.org", "v1", "IPPool").(*crdv1.IPPool)
+		Expect(pool.Spec).To(Equal(crdv1.IPPoolSpec{
+			CIDR:         "10.0.16.0/24",
+			VXLANMode:    crdv1.VXLANModeNever,
+			IPIPMode:     crdv1.IPIPModeNever,
+			BlockSize:    28,
+			NodeSelector: "topology.kubernetes.io/zone == 'us-west-2a'",
+			AWSSubnetID:  "subnet-0123456789abcdef0",
+		}))
+
+		pool = rtest.GetResource(toCreate, "egress-b", "", "crd.projectcalico.org", "v1", "IPPool").(*crdv1.IPPool)
+		Expect(pool.Spec.NodeSelector).To(Equal("all()"))
+		Expect(pool.Spec.BlockSize).To(Equal(0))
+		Expect(pool.Spec.AWSSubnetID).To(Equal("subnet-0123456789abcdef1"))
+	})
+
+	It("should not render the IP pools when the AWS secondary IPs are disabled", func() {
+		disabled := operatorv1.AWSSecondaryIPDisabled
+		installation.CalicoNetwork.AWSSecondaryIPs.Mode = &disabled
+		toCreate, toDelete := render.AWSSecondaryIPPools(installation).Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(BeEmpty())
+	})
+})
@@ -0,0 +1,136 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"github.com/tigera/api/pkg/lib/numorstring"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tigera/operator/pkg/common"
+)
+
+const (
+	// BaselinePolicyTierName is the tier of the baseline policies which are evaluated before the policies of the user.
+	BaselinePolicyTierName = "platform"
+
+	baselinePolicyTierOrder  = 100.0
+	baselinePolicyOrderAllow = 100.0
+	baselinePolicyOrderPass  = 10000.0
+	defaultDenyPolicyName    = "default-deny"
+	defaultDenyPolicyOrder   = 10000.0
+	kubeSystemNamespace      = "kube-system"
+	kubeDNSSelector          = "k8s-app == 'kube-dns'"
+)
+
+// BaselinePolicy returns the baseline policy set: the platform tier with the policies allowing the traffic of
+// kube-system, DNS and the scraping of the metrics by Prometheus, and with defaultDeny a default deny of the
+// workloads of the user in the default tier. The tier is returned first, since the policies of a tier can only be
+// created once it exists.
+func BaselinePolicy(defaultDeny bool) []client.Object {
+	objs := []client.Object{
+		baselinePolicyTier(),
+		baselineGlobalNetworkPolicy("allow-kube-system", baselinePolicyOrderAllow,
+			fmt.Sprintf("projectcalico.org/namespace == '%s'", kubeSystemNamespace),
+			[]v3.Rule{{Action: v3.Allow}},
+			[]v3.Rule{{Action: v3.Allow}},
+		),
+		baselineGlobalNetworkPolicy("allow-kube-dns", baselinePolicyOrderAllow+1, "all()",
+			nil,
+			[]v3.Rule{kubeDNSRule("UDP"), kubeDNSRule("TCP")},
+		),
+		baselineGlobalNetworkPolicy("allow-metrics", baselinePolicyOrderAllow+2, "all()",
+			[]v3.Rule{{
+				Action: v3.Allow,
+				Source: v3.EntityRule{NamespaceSelector: fmt.Sprintf("projectcalico.org/name == '%s'", common.TigeraPrometheusNamespace)},
+			}},
+			nil,
+		),
+		// The traffic not allowed by the platform tier is evaluated by the policies of the next tiers.
+		baselineGlobalNetworkPolicy("pass", baselinePolicyOrderPass, "all()",
+			[]v3.Rule{{Action: v3.Pass}},
+			[]v3.Rule{{Action: v3.Pass}},
+		),
+	}
+	if defaultDeny {
+		objs = append(objs, defaultDenyPolicy())
+	}
+	return objs
+}
+
+func baselinePolicyTier() *v3.Tier {
+	order := baselinePolicyTierOrder
+	return &v3.Tier{
+		TypeMeta:   metav1.TypeMeta{Kind: "Tier", APIVersion: "projectcalico.org/v3"},
+		ObjectMeta: metav1.ObjectMeta{Name: BaselinePolicyTierName},
+		Spec:       v3.TierSpec{Order: &order},
+	}
+}
+
+// baselineGlobalNetworkPolicy returns a policy of the platform tier. A policy type is only set for the non-nil rules.
+func baselineGlobalNetworkPolicy(name string, order float64, selector string, ingress, egress []v3.Rule) *v3.GlobalNetworkPolicy {
+	var types []v3.PolicyType
+	if ingress != nil {
+		types = append(types, v3.PolicyTypeIngress)
+	}
+	if egress != nil {
+		types = append(types, v3.PolicyTypeEgress)
+	}
+	return &v3.GlobalNetworkPolicy{
+		TypeMeta:   metav1.TypeMeta{Kind: "GlobalNetworkPolicy", APIVersion: "projectcalico.org/v3"},
+		ObjectMeta: metav1.ObjectMeta{Name: BaselinePolicyTierName + "." + name},
+		Spec: v3.GlobalNetworkPolicySpec{
+			Tier:     BaselinePolicyTierName,
+			Order:    &order,
+			Selector: selector,
+			Types:    types,
+			Ingress:  ingress,
+			Egress:   egress,
+		},
+	}
+}
+
+func kubeDNSRule(protocol string) v3.Rule {
+	p := numorstring.ProtocolFromString(protocol)
+	return v3.Rule{
+		Action:   v3.Allow,
+		Protocol: &p,
+		Destination: v3.EntityRule{
+			Selector:          kubeDNSSelector,
+			NamespaceSelector: fmt.Sprintf("projectcalico.org/name == '%s'", kubeSystemNamespace),
+			Ports:             []numorstring.Port{numorstring.SinglePort(53)},
+		},
+	}
+}
+
+// defaultDenyPolicy returns the policy of the default tier denying the traffic of the workloads which no policy of
+// the user allows. The host endpoints, which have no namespace, and the namespaces of the platform are left out.
+func defaultDenyPolicy() *v3.GlobalNetworkPolicy {
+	order := defaultDenyPolicyOrder
+	return &v3.GlobalNetworkPolicy{
+		TypeMeta:   metav1.TypeMeta{Kind: "GlobalNetworkPolicy", APIVersion: "projectcalico.org/v3"},
+		ObjectMeta: metav1.ObjectMeta{Name: defaultDenyPolicyName},
+		Spec: v3.GlobalNetworkPolicySpec{
+			Tier:  "default",
+			Order: &order,
+			Selector: fmt.Sprintf("has(projectcalico.org/namespace) && projectcalico.org/namespace not in {'%s', '%s'} && !(projectcalico.org/namespace starts with 'tigera-')",
+				kubeSystemNamespace, common.CalicoNamespace),
+			Types: []v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+		},
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("Baseline policy rendering tests", func() {
+	It("should render the platform tier before its policies", func() {
+		objs := render.BaselinePolicy(true)
+		Expect(objs).To(HaveLen(6))
+		Expect(objs[0]).To(BeAssignableToTypeOf(&v3.Tier{}))
+		Expect(objs[0].GetName()).To(Equal(render.BaselinePolicyTierName))
+
+		for _, name := range []string{"platform.allow-kube-system", "platform.allow-kube-dns", "platform.allow-metrics", "platform.pass"} {
+			gnp := rtest.GetResource(objs, name, "", "projectcalico.org", "v3", "GlobalNetworkPolicy").(*v3.GlobalNetworkPolicy)
+			Expect(gnp.Spec.Tier).To(Equal(render.BaselinePolicyTierName))
+		}
+
+		pass := rtest.GetResource(objs, "platform.pass", "", "projectcalico.org", "v3", "GlobalNetworkPolicy").(*v3.GlobalNetworkPolicy)
+		Expect(pass.Spec.Ingress).To(Equal([]v3.Rule{{Action: v3.Pass}}))
+		Expect(pass.Spec.Egress).To(Equal([]v3.Rule{{Action: v3.Pass}}))
+
+		dns := rtest.GetResource(objs, "platform.allow-kube-dns", "", "projectcalico.org", "v3", "GlobalNetworkPolicy").(*v3.GlobalNetworkPolicy)
+		Expect(dns.Spec.Types).To(Equal([]v3.PolicyType{v3.PolicyTypeEgress}))
+		Expect(dns.Spec.Egress).To(HaveLen(2))
+	})
+
+	It("should deny the traffic of the workloads of the user in the default tier", func() {
+		gnp := rtest.GetResource(render.BaselinePolicy(true), "default-deny", "", "projectcalico.org", "v3", "GlobalNetworkPolicy").(*v3.GlobalNetworkPolicy)
+		Expect(gnp.Spec.Tier).To(Equal("default"))
+		Expect(gnp.Spec.Selector).To(Equal("has(projectcalico.org/namespace) && projectcalico.org/namespace not in {'kube-system', 'calico-system'} && !(projectcalico.org/namespace starts with 'tigera-')"))
+		Expect(gnp.Spec.Types).To(Equal([]v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress}))
+		Expect(gnp.Spec.Ingress).To(BeEmpty())
+		Expect(gnp.Spec.Egress).To(BeEmpty())
+	})
+
+	It("should only render the default deny when requested", func() {
+		objs := render.BaselinePolicy(false)
+		Expect(objs).To(HaveLen(5))
+		Expect(rtest.GetResource(objs, "default-deny", "", "projectcalico.org", "v3", "GlobalNetworkPolicy")).To(BeNil())
+	})
+})
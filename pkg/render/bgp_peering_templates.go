@@ -0,0 +1,124 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"crypto/sha1"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+// BGPPeeringTemplateLabel is set on the BGPPeers generated from a BGPPeeringTemplate, to the name of the template.
+const BGPPeeringTemplateLabel = "operator.tigera.io/bgp-peering-template"
+
+// BGPPeeringTemplates renders the BGPPeers generated for the nodes from the BGP peering templates of the installation.
+// The previously generated BGPPeers which are no longer needed are deleted.
+func BGPPeeringTemplates(cfg *BGPPeeringTemplatesConfiguration) Component {
+	return &bgpPeeringTemplatesComponent{cfg: cfg}
+}
+
+// BGPPeeringTemplatesConfiguration contains all the config information needed to render the component.
+type BGPPeeringTemplatesConfiguration struct {
+	Installation *operatorv1.InstallationSpec
+	Nodes        []corev1.Node
+
+	// GeneratedPeers are the BGPPeers carrying the BGPPeeringTemplateLabel in the cluster.
+	GeneratedPeers []crdv1.BGPPeer
+}
+
+type bgpPeeringTemplatesComponent struct {
+	cfg *BGPPeeringTemplatesConfiguration
+}
+
+func (c *bgpPeeringTemplatesComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on a BGPPeer
+	return nil
+}
+
+func (c *bgpPeeringTemplatesComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *bgpPeeringTemplatesComponent) Objects() ([]client.Object, []client.Object) {
+	var toCreate, toDelete []client.Object
+	desired := map[string]bool{}
+	for _, peer := range c.peers() {
+		desired[peer.Name] = true
+		toCreate = append(toCreate, peer)
+	}
+	for i := range c.cfg.GeneratedPeers {
+		if !desired[c.cfg.GeneratedPeers[i].Name] {
+			toDelete = append(toDelete, c.cfg.GeneratedPeers[i].DeepCopy())
+		}
+	}
+	return toCreate, toDelete
+}
+
+func (c *bgpPeeringTemplatesComponent) Ready() bool {
+	return true
+}
+
+// BGPPeeringTemplatePeerName returns the name of the BGPPeer generated from the given template for the given node and
+// index of its peers. The name is prefixed with the name of the template and suffixed with a hash of all three, since
+// the names of the templates and of the nodes may contain dashes and joining them could make two BGPPeers collide.
+func BGPPeeringTemplatePeerName(template, node string, i int) string {
+	h := sha1.Sum([]byte(fmt.Sprintf("%s\x00%s\x00%d", template, node, i)))
+	return fmt.Sprintf("%s-%x", template, h[:10])
+}
+
+// peers returns a BGPPeer for each node and each peer chosen for the node by the templates.
+func (c *bgpPeeringTemplatesComponent) peers() []*crdv1.BGPPeer {
+	if c.cfg.Installation.CalicoNetwork == nil {
+		return nil
+	}
+
+	var peers []*crdv1.BGPPeer
+	for _, template := range c.cfg.Installation.CalicoNetwork.BGPPeeringTemplates {
+		values := map[string][]operatorv1.BGPPeerDefinition{}
+		for _, v := range template.Values {
+			values[v.Value] = v.Peers
+		}
+
+		for _, node := range c.cfg.Nodes {
+			value, ok := node.Labels[template.NodeLabel]
+			if !ok {
+				continue
+			}
+			for i, def := range values[value] {
+				peers = append(peers, &crdv1.BGPPeer{
+					TypeMeta: metav1.TypeMeta{Kind: crdv1.KindBGPPeer, APIVersion: "crd.projectcalico.org/v1"},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   BGPPeeringTemplatePeerName(template.Name, node.Name, i),
+						Labels: map[string]string{BGPPeeringTemplateLabel: template.Name},
+					},
+					Spec: crdv1.BGPPeerSpec{
+						Node:           node.Name,
+						PeerIP:         def.PeerIP,
+						ASNumber:       def.ASNumber,
+						MaxRestartTime: bgpMaxRestartTime(c.cfg.Installation),
+					},
+				})
+			}
+		}
+	}
+	return peers
+}
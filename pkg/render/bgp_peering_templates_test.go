@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("BGP peering template rendering tests", func() {
+	var cfg *render.BGPPeeringTemplatesConfiguration
+	BeforeEach(func() {
+		cfg = &render.BGPPeeringTemplatesConfiguration{
+			Installation: &operatorv1.InstallationSpec{
+				CalicoNetwork: &operatorv1.CalicoNetworkSpec{
+					BGPPeeringTemplates: []operatorv1.BGPPeeringTemplate{{
+						Name:      "tor",
+						NodeLabel: "rack",
+						Values: []operatorv1.BGPPeeringTemplateValue{
+							{Value: "r1", Peers: []operatorv1.BGPPeerDefinition{{PeerIP: "10.0.1.1", ASNumber: 65001}, {PeerIP: "10.0.1.2", ASNumber: 65001}}},
+							{Value: "r2", Peers: []operatorv1.BGPPeerDefinition{{PeerIP: "10.0.2.1", ASNumber: 65002}}},
+						},
+					}},
+				},
+			},
+			Nodes: []corev1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"rack": "r1"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{"rack": "r2"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "node-c", Labels: map[string]string{"rack": "r3"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "node-d"}},
+			},
+		}
+	})
+
+	It("should render a BGPPeer for each node and peer of its rack", func() {
+		toCreate, toDelete := render.BGPPeeringTemplates(cfg).Objects()
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(3))
+		rtest.ExpectResource(toCreate[0], render.BGPPeeringTemplatePeerName("tor", "node-a", 0), "", "crd.projectcalico.org", "v1", "BGPPeer")
+		rtest.ExpectResource(toCreate[1], render.BGPPeeringTemplatePeerName("tor", "node-a", 1), "", "crd.projectcalico.org", "v1", "BGPPeer")
+		rtest.ExpectResource(toCreate[2], render.BGPPeeringTemplatePeerName("tor", "node-b", 0), "", "crd.projectcalico.org", "v1", "BGPPeer")
+
+		peer := toCreate[1].(*crdv1.BGPPeer)
+		Expect(peer.Labels).To(HaveKeyWithValue(render.BGPPeeringTemplateLabel, "tor"))
+		Expect(peer.Spec).To(Equal(crdv1.BGPPeerSpec{Node: "node-a", PeerIP: "10.0.1.2", ASNumber: 65001}))
+	})
+
+	It("should not generate the same name for the peers of different templates and nodes", func() {
+		Expect(render.BGPPeeringTemplatePeerName("tor", "a-b", 0)).NotTo(Equal(render.BGPPeeringTemplatePeerName("tor-a", "b", 0)))
+		Expect(render.BGPPeeringTemplatePeerName("tor", "node-1", 1)).NotTo(Equal(render.BGPPeeringTemplatePeerName("tor", "node", 11)))
+		Expect(render.BGPPeeringTemplatePeerName("tor", "node-a", 0)).To(HavePrefix("tor-"))
+	})
+
+	It("should set the graceful restart time of the generated BGPPeers", func() {
+		var maxRestartTime int32 = 300
+		cfg.Installation.CalicoNetwork.BGPGracefulRestart = &operatorv1.BGPGracefulRestart{MaxRestartTimeSeconds: &maxRestartTime}
+		toCreate, _ := render.BGPPeeringTemplates(cfg).Objects()
+		Expect(toCreate).To(HaveLen(3))
+		Expect(toCreate[0].(*crdv1.BGPPeer).Spec.MaxRestartTime.Duration).To(Equal(5 * time.Minute))
+	})
+
+	It("should delete the generated BGPPeers which are no longer needed", func() {
+		cfg.Nodes = cfg.Nodes[1:]
+		cfg.GeneratedPeers = []crdv1.BGPPeer{
+			{ObjectMeta: metav1.ObjectMeta{Name: render.BGPPeeringTemplatePeerName("tor", "node-a", 0)}},
+			{ObjectMeta: metav1.ObjectMeta{Name: render.BGPPeeringTemplatePeerName("tor", "node-b", 0)}},
+		}
+		toCreate, toDelete := render.BGPPeeringTemplates(cfg).Objects()
+		Expect(toCreate).To(HaveLen(1))
+		Expect(toDelete).To(HaveLen(1))
+		Expect(toDelete[0].GetName()).To(Equal(render.BGPPeeringTemplatePeerName("tor", "node-a", 0)))
+
+		cfg.Installation.CalicoNetwork.BGPPeeringTemplates = nil
+		toCreate, toDelete = render.BGPPeeringTemplates(cfg).Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(2))
+	})
+})
@@ -1,6 +1,46 @@
 package elasticsearch
 
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
 const (
 	PublicCertSecret   = "tigera-secure-es-gateway-http-certs-public"
 	InternalCertSecret = "tigera-secure-es-http-certs-public"
 )
+
+// TLSEnvVars returns the environment variables configuring the TLS of the HTTPS server of a component fronting
+// Elasticsearch. The Calico Enterprise images read the TLS 1.2 cipher suites from TLS_CIPHER_SUITES.
+func TLSEnvVars(settings *operatorv1.TLSSettings) []corev1.EnvVar {
+	if settings == nil || len(settings.CipherSuites) == 0 {
+		return nil
+	}
+	return []corev1.EnvVar{{Name: "TLS_CIPHER_SUITES", Value: strings.Join(settings.CipherSuites, ",")}}
+}
+
+// ValidateTLSSettings checks that the cipher suites are secure TLS 1.2 cipher suites.
+func ValidateTLSSettings(settings *operatorv1.TLSSettings) error {
+	if settings == nil || len(settings.CipherSuites) == 0 {
+		return nil
+	}
+	supported := map[string]bool{}
+	for _, cs := range tls.CipherSuites() {
+		for _, v := range cs.SupportedVersions {
+			if v == tls.VersionTLS12 {
+				supported[cs.Name] = true
+			}
+		}
+	}
+	for _, name := range settings.CipherSuites {
+		if !supported[name] {
+			return fmt.Errorf("%q is not a supported TLS 1.2 cipher suite", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+func init() {
+	register(operatorv1.ProviderAKS, aks{})
+}
+
+type aks struct {
+	kubernetes
+}
+
+func (aks) DecorateNamespace(ns *corev1.Namespace) {
+	ns.Labels["control-plane"] = "true"
+}
+
+// NodeAffinity keeps calico-node off the virtual kubelet nodes.
+func (aks) NodeAffinity() *corev1.Affinity {
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      "type",
+						Operator: corev1.NodeSelectorOpNotIn,
+						Values:   []string{"virtual-kubelet"},
+					}},
+				}},
+			},
+		},
+	}
+}
+
+func (aks) ClusterType() string {
+	return "aks"
+}
+
+// FelixEnv asks Felix to add the host IPs to the wireguard interfaces.
+func (aks) FelixEnv(operatorv1.ProductVariant) []corev1.EnvVar {
+	return []corev1.EnvVar{{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"}}
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+func init() {
+	register(operatorv1.ProviderDockerEE, dockerEE{})
+}
+
+type dockerEE struct {
+	kubernetes
+}
+
+// ClusterAdminForPrivilegedPods returns true since Docker EE only lets cluster admins run privileged pods and pods
+// with host path volumes. See https://docs.docker.com/ee/ucp/authorization/#secure-kubernetes-defaults
+func (dockerEE) ClusterAdminForPrivilegedPods() bool {
+	return true
+}
+
+func (dockerEE) NodenameFileOptional() bool {
+	return true
+}
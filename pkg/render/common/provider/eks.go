@@ -0,0 +1,47 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+func init() {
+	register(operatorv1.ProviderEKS, eks{})
+}
+
+type eks struct {
+	kubernetes
+}
+
+func (eks) ClusterType() string {
+	return "ecs"
+}
+
+// FelixEnv asks Felix to add the host IPs to the wireguard interfaces.
+func (eks) FelixEnv(operatorv1.ProductVariant) []corev1.EnvVar {
+	return []corev1.EnvVar{{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"}}
+}
+
+func (eks) DisableSourceDestinationCheck() bool {
+	return true
+}
+
+// WebhooksRequireHostNetwork returns true with the Calico CNI plugin, since the control plane nodes don't run Calico.
+func (eks) WebhooksRequireHostNetwork(cni operatorv1.CNIPluginType) bool {
+	return cni == operatorv1.PluginCalico
+}
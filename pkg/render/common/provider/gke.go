@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+func init() {
+	register(operatorv1.ProviderGKE, gke{})
+}
+
+type gke struct {
+	kubernetes
+}
+
+// CNIDirectories returns the directory of the CNI plugins of GKE, which is only used with CNI chaining when the GKE
+// CNI plugin is used.
+func (gke) CNIDirectories() (string, string) {
+	return "/etc/cni/net.d", "/home/kubernetes/bin"
+}
+
+// CriticalPodsQuota returns true since GKE automatically adds a resource quota constraining the scheduling of the
+// pods of the critical priority classes.
+func (gke) CriticalPodsQuota() bool {
+	return true
+}
+
+func (gke) ClusterType() string {
+	return "gke"
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/ptr"
+)
+
+func init() {
+	register(operatorv1.ProviderOpenShift, openShift{})
+}
+
+// openShift enforces its security context constraints instead of the pod security policies.
+type openShift struct {
+	kubernetes
+}
+
+func (openShift) PodSecurityPolicies() bool {
+	return false
+}
+
+func (openShift) SecurityContextConstraints() bool {
+	return true
+}
+
+func (openShift) PrivilegedHostLogReaders() bool {
+	return true
+}
+
+func (openShift) PrivilegedHostPathWriters() bool {
+	return true
+}
+
+func (openShift) PrivilegedPacketInspection() bool {
+	return true
+}
+
+// ElasticsearchRunAsUser runs Elasticsearch as non-root, since its image requires the root user to be allowed to
+// chroot, which OpenShift doesn't allow.
+func (openShift) ElasticsearchRunAsUser() *int64 {
+	return ptr.Int64ToPtr(1000)
+}
+
+// CNIDirectories returns the directories of Multus, which delegates to the CNI plugins.
+func (openShift) CNIDirectories() (string, string) {
+	return "/var/run/multus/cni/net.d", "/var/lib/cni/bin"
+}
+
+func (openShift) DecorateNamespace(ns *corev1.Namespace) {
+	ns.Labels["openshift.io/run-level"] = "0"
+	ns.Annotations["openshift.io/node-selector"] = ""
+}
+
+func (openShift) ClusterType() string {
+	return "openshift"
+}
+
+// FelixHealthPort returns a port other than the default one, which is already in use.
+func (openShift) FelixHealthPort() int {
+	return 9199
+}
+
+func (p openShift) FelixEnv(variant operatorv1.ProductVariant) []corev1.EnvVar {
+	env := []corev1.EnvVar{{Name: "FELIX_HEALTHPORT", Value: fmt.Sprint(p.FelixHealthPort())}}
+	if variant == operatorv1.TigeraSecureEnterprise {
+		// We also need to configure a non-default trusted DNS server, since there's no kube-dns.
+		env = append(env, corev1.EnvVar{Name: "FELIX_DNSTRUSTEDSERVERS", Value: "k8s-service:openshift-dns/dns-default"})
+	}
+	return env
+}
+
+func (openShift) NodeInitByDefault() bool {
+	return true
+}
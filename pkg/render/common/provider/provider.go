@@ -0,0 +1,187 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package provider holds the settings of the rendered objects which depend on the Kubernetes provider. Each provider
+// is in its own file, overriding the hooks of vanilla Kubernetes where it differs, so that adding a provider doesn't
+// take changing the components.
+package provider
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+// Provider is the hooks of a Kubernetes provider.
+type Provider interface {
+	// PodSecurityPolicies returns true if the components render their pod security policies, along with the RBAC to
+	// use them. The providers enforcing their own policies don't need them.
+	PodSecurityPolicies() bool
+
+	// SecurityContextConstraints returns true if the components render the security context constraints of OpenShift
+	// for their privileged pods.
+	SecurityContextConstraints() bool
+
+	// ClusterAdminForPrivilegedPods returns true if the service accounts of the pods which are privileged or mount
+	// host paths, or which start such pods, must be bound to the cluster-admin role.
+	ClusterAdminForPrivilegedPods() bool
+
+	// PrivilegedHostLogReaders returns true if the containers reading the logs of the hosts must be privileged.
+	PrivilegedHostLogReaders() bool
+
+	// PrivilegedHostPathWriters returns true if the containers writing to host path volumes, such as the audit logs and
+	// the compliance reports, must be privileged.
+	PrivilegedHostPathWriters() bool
+
+	// PrivilegedPacketInspection returns true if the containers inspecting the packets of the host network must be
+	// privileged.
+	PrivilegedPacketInspection() bool
+
+	// ExposedServiceType returns the type of the services reached from outside the cluster, such as the one of the
+	// manager and the one through which the managed clusters open their tunnel.
+	ExposedServiceType() corev1.ServiceType
+
+	// ElasticsearchRunAsUser returns the user the Elasticsearch containers run as, or nil to use the one of the image.
+	ElasticsearchRunAsUser() *int64
+
+	// CNIDirectories returns the host directories of the CNI network configuration and of the CNI plugins.
+	CNIDirectories() (netDir, binDir string)
+
+	// NodenameFileOptional returns true if the CNI plugin doesn't require the nodename file written by calico-node.
+	NodenameFileOptional() bool
+
+	// DecorateNamespace sets the labels and the annotations of the namespaces of the components.
+	DecorateNamespace(ns *corev1.Namespace)
+
+	// NodeAffinity returns the node affinity of the calico-node pods, or nil if they run on all the nodes.
+	NodeAffinity() *corev1.Affinity
+
+	// CriticalPodsQuota returns true if the priority classes of the critical pods are only admitted by a resource
+	// quota of their namespace.
+	CriticalPodsQuota() bool
+
+	// ClusterType returns the cluster type of Felix for the provider, which activates its special cases, or an empty
+	// string if it has none.
+	ClusterType() string
+
+	// FelixHealthPort returns the port of the health endpoint of Felix.
+	FelixHealthPort() int
+
+	// FelixEnv returns the environment variables configuring Felix for the provider.
+	FelixEnv(variant operatorv1.ProductVariant) []corev1.EnvVar
+
+	// DisableSourceDestinationCheck returns true if Felix disables the source/destination check of the instances of
+	// the nodes when it doesn't program the dataplane.
+	DisableSourceDestinationCheck() bool
+
+	// NodeInitByDefault returns true if the node-init init container prepares the hosts unless it is disabled.
+	NodeInitByDefault() bool
+
+	// WebhooksRequireHostNetwork returns true if the pods serving webhooks must be host networked with the given CNI
+	// plugin, since the control plane can't reach the pod network.
+	WebhooksRequireHostNetwork(cni operatorv1.CNIPluginType) bool
+}
+
+// providers are the providers with hooks differing from vanilla Kubernetes.
+var providers = map[operatorv1.Provider]Provider{}
+
+// register registers the hooks of a provider.
+func register(name operatorv1.Provider, p Provider) {
+	providers[name] = p
+}
+
+// For returns the hooks of the given provider, which are the ones of vanilla Kubernetes if it has none.
+func For(name operatorv1.Provider) Provider {
+	if p, ok := providers[name]; ok {
+		return p
+	}
+	return kubernetes{}
+}
+
+// kubernetes is vanilla Kubernetes, whose hooks are embedded by the providers.
+type kubernetes struct{}
+
+func (kubernetes) PodSecurityPolicies() bool {
+	return true
+}
+
+func (kubernetes) SecurityContextConstraints() bool {
+	return false
+}
+
+func (kubernetes) ClusterAdminForPrivilegedPods() bool {
+	return false
+}
+
+func (kubernetes) PrivilegedHostLogReaders() bool {
+	return false
+}
+
+func (kubernetes) PrivilegedHostPathWriters() bool {
+	return false
+}
+
+func (kubernetes) PrivilegedPacketInspection() bool {
+	return false
+}
+
+func (kubernetes) ExposedServiceType() corev1.ServiceType {
+	return corev1.ServiceTypeClusterIP
+}
+
+func (kubernetes) ElasticsearchRunAsUser() *int64 {
+	return nil
+}
+
+func (kubernetes) CNIDirectories() (string, string) {
+	return "/etc/cni/net.d", "/opt/cni/bin"
+}
+
+func (kubernetes) NodenameFileOptional() bool {
+	return false
+}
+
+func (kubernetes) DecorateNamespace(*corev1.Namespace) {}
+
+func (kubernetes) NodeAffinity() *corev1.Affinity {
+	return nil
+}
+
+func (kubernetes) CriticalPodsQuota() bool {
+	return false
+}
+
+func (kubernetes) ClusterType() string {
+	return ""
+}
+
+func (kubernetes) FelixHealthPort() int {
+	return 9099
+}
+
+func (kubernetes) FelixEnv(operatorv1.ProductVariant) []corev1.EnvVar {
+	return nil
+}
+
+func (kubernetes) DisableSourceDestinationCheck() bool {
+	return false
+}
+
+func (kubernetes) NodeInitByDefault() bool {
+	return false
+}
+
+func (kubernetes) WebhooksRequireHostNetwork(operatorv1.CNIPluginType) bool {
+	return false
+}
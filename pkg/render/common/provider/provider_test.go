@@ -0,0 +1,167 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/ptr"
+)
+
+// hooks are the values returned by the hooks of a provider.
+type hooks struct {
+	podSecurityPolicies           bool
+	securityContextConstraints    bool
+	clusterAdminForPrivilegedPods bool
+	privilegedHostLogReaders      bool
+	privilegedHostPathWriters     bool
+	privilegedPacketInspection    bool
+	exposedServiceType            corev1.ServiceType
+	elasticsearchRunAsUser        *int64
+	cniNetDir                     string
+	cniBinDir                     string
+	nodenameFileOptional          bool
+	namespaceLabels               map[string]string
+	namespaceAnnotations          map[string]string
+	nodeAffinity                  *corev1.Affinity
+	criticalPodsQuota             bool
+	clusterType                   string
+	felixHealthPort               int
+	felixEnvCalico                []corev1.EnvVar
+	felixEnvEnterprise            []corev1.EnvVar
+	disableSourceDestinationCheck bool
+	nodeInitByDefault             bool
+	webhooksHostNetworkCalico     bool
+	webhooksHostNetworkAmazonVPC  bool
+}
+
+func hooksOf(p Provider) hooks {
+	ns := &corev1.Namespace{}
+	ns.Labels = map[string]string{}
+	ns.Annotations = map[string]string{}
+	p.DecorateNamespace(ns)
+	netDir, binDir := p.CNIDirectories()
+	return hooks{
+		podSecurityPolicies:           p.PodSecurityPolicies(),
+		securityContextConstraints:    p.SecurityContextConstraints(),
+		clusterAdminForPrivilegedPods: p.ClusterAdminForPrivilegedPods(),
+		privilegedHostLogReaders:      p.PrivilegedHostLogReaders(),
+		privilegedHostPathWriters:     p.PrivilegedHostPathWriters(),
+		privilegedPacketInspection:    p.PrivilegedPacketInspection(),
+		exposedServiceType:            p.ExposedServiceType(),
+		elasticsearchRunAsUser:        p.ElasticsearchRunAsUser(),
+		cniNetDir:                     netDir,
+		cniBinDir:                     binDir,
+		nodenameFileOptional:          p.NodenameFileOptional(),
+		namespaceLabels:               ns.Labels,
+		namespaceAnnotations:          ns.Annotations,
+		nodeAffinity:                  p.NodeAffinity(),
+		criticalPodsQuota:             p.CriticalPodsQuota(),
+		clusterType:                   p.ClusterType(),
+		felixHealthPort:               p.FelixHealthPort(),
+		felixEnvCalico:                p.FelixEnv(operatorv1.Calico),
+		felixEnvEnterprise:            p.FelixEnv(operatorv1.TigeraSecureEnterprise),
+		disableSourceDestinationCheck: p.DisableSourceDestinationCheck(),
+		nodeInitByDefault:             p.NodeInitByDefault(),
+		webhooksHostNetworkCalico:     p.WebhooksRequireHostNetwork(operatorv1.PluginCalico),
+		webhooksHostNetworkAmazonVPC:  p.WebhooksRequireHostNetwork(operatorv1.PluginAmazonVPC),
+	}
+}
+
+var _ = Describe("Provider hooks", func() {
+	// The hooks of vanilla Kubernetes, which the entries override with the settings the components used to render
+	// for the provider.
+	vanilla := func() hooks {
+		return hooks{
+			podSecurityPolicies:  true,
+			exposedServiceType:   corev1.ServiceTypeClusterIP,
+			cniNetDir:            "/etc/cni/net.d",
+			cniBinDir:            "/opt/cni/bin",
+			namespaceLabels:      map[string]string{},
+			namespaceAnnotations: map[string]string{},
+			felixHealthPort:      9099,
+		}
+	}
+
+	DescribeTable("return the settings of the provider",
+		func(name operatorv1.Provider, override func(*hooks)) {
+			expected := vanilla()
+			override(&expected)
+			Expect(hooksOf(For(name))).To(Equal(expected))
+		},
+		Entry("Kubernetes", operatorv1.ProviderNone, func(*hooks) {}),
+		Entry("an unknown provider", operatorv1.Provider("Unknown"), func(*hooks) {}),
+		Entry("OpenShift", operatorv1.ProviderOpenShift, func(h *hooks) {
+			h.podSecurityPolicies = false
+			h.securityContextConstraints = true
+			h.privilegedHostLogReaders = true
+			h.privilegedHostPathWriters = true
+			h.privilegedPacketInspection = true
+			h.elasticsearchRunAsUser = ptr.Int64ToPtr(1000)
+			h.cniNetDir = "/var/run/multus/cni/net.d"
+			h.cniBinDir = "/var/lib/cni/bin"
+			h.namespaceLabels = map[string]string{"openshift.io/run-level": "0"}
+			h.namespaceAnnotations = map[string]string{"openshift.io/node-selector": ""}
+			h.clusterType = "openshift"
+			h.felixHealthPort = 9199
+			h.felixEnvCalico = []corev1.EnvVar{{Name: "FELIX_HEALTHPORT", Value: "9199"}}
+			h.felixEnvEnterprise = []corev1.EnvVar{
+				{Name: "FELIX_HEALTHPORT", Value: "9199"},
+				{Name: "FELIX_DNSTRUSTEDSERVERS", Value: "k8s-service:openshift-dns/dns-default"},
+			}
+			h.nodeInitByDefault = true
+		}),
+		Entry("EKS", operatorv1.ProviderEKS, func(h *hooks) {
+			h.clusterType = "ecs"
+			h.felixEnvCalico = []corev1.EnvVar{{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"}}
+			h.felixEnvEnterprise = []corev1.EnvVar{{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"}}
+			h.disableSourceDestinationCheck = true
+			h.webhooksHostNetworkCalico = true
+		}),
+		Entry("GKE", operatorv1.ProviderGKE, func(h *hooks) {
+			h.cniBinDir = "/home/kubernetes/bin"
+			h.criticalPodsQuota = true
+			h.clusterType = "gke"
+		}),
+		Entry("AKS", operatorv1.ProviderAKS, func(h *hooks) {
+			h.namespaceLabels = map[string]string{"control-plane": "true"}
+			h.nodeAffinity = &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key:      "type",
+								Operator: corev1.NodeSelectorOpNotIn,
+								Values:   []string{"virtual-kubelet"},
+							}},
+						}},
+					},
+				},
+			}
+			h.clusterType = "aks"
+			h.felixEnvCalico = []corev1.EnvVar{{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"}}
+			h.felixEnvEnterprise = []corev1.EnvVar{{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"}}
+		}),
+		Entry("Docker EE", operatorv1.ProviderDockerEE, func(h *hooks) {
+			h.clusterAdminForPrivilegedPods = true
+			h.nodenameFileOptional = true
+		}),
+	)
+})
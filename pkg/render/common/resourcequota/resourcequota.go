@@ -52,3 +52,53 @@ func ResourceQuotaForPriorityClassScope(name, namespace string, priorityClasses
 		},
 	}
 }
+
+// LimitRangeForResourceRequirements creates a LimitRange in a specified namespace which defaults both the requests
+// and the limits of the containers without any to the largest of the requests of the given resource requirements. The
+// limits of the requirements without requests count as their requests, as they do for the containers. It returns nil
+// if none of the requirements set requests or limits.
+func LimitRangeForResourceRequirements(name, namespace string, requirements []*corev1.ResourceRequirements) *corev1.LimitRange {
+	requests := corev1.ResourceList{}
+	for _, rr := range requirements {
+		if rr == nil {
+			continue
+		}
+		maxResources(requests, rr.Requests)
+		for name, q := range rr.Limits {
+			if _, ok := rr.Requests[name]; !ok {
+				maxResources(requests, corev1.ResourceList{name: q})
+			}
+		}
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	item := corev1.LimitRangeItem{
+		Type:           corev1.LimitTypeContainer,
+		DefaultRequest: requests,
+		Default:        requests.DeepCopy(),
+	}
+	return &corev1.LimitRange{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "LimitRange",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{item},
+		},
+	}
+}
+
+// maxResources sets each resource of dst to the largest of its quantity in dst and in src.
+func maxResources(dst, src corev1.ResourceList) {
+	for name, q := range src {
+		if cur, ok := dst[name]; !ok || q.Cmp(cur) > 0 {
+			dst[name] = q.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+// HashAnnotationPrefix is the prefix of the pod template annotations holding the hash of a replicated secret. The
+// name of the secret follows the prefix.
+const HashAnnotationPrefix = "hash.operator.tigera.io/"
+
+// replica is a copy of a source secret in the namespace of the workloads consuming it.
+type replica struct {
+	source      types.NamespacedName
+	destination *corev1.Secret
+}
+
+// Replicator tracks the secrets a component copies from their source namespace into the namespaces of the workloads
+// consuming them. The copies are rendered along with the component, and the pod template of every workload consuming
+// a copy is annotated with the hash of its contents, so that only the workloads consuming a changed secret are
+// restarted.
+type Replicator struct {
+	replicas []replica
+}
+
+// NewReplicator returns an empty Replicator.
+func NewReplicator() *Replicator {
+	return &Replicator{}
+}
+
+// Replicate copies the given secrets into the given namespace and returns the copies. Nil secrets are ignored, and a
+// secret already replicated into the namespace is replaced.
+func (r *Replicator) Replicate(ns string, secrets ...*corev1.Secret) []*corev1.Secret {
+	var copies []*corev1.Secret
+	for _, s := range secrets {
+		if s == nil {
+			continue
+		}
+		x := CopyToNamespace(ns, s)[0]
+		r.add(replica{
+			source:      types.NamespacedName{Name: s.Name, Namespace: s.Namespace},
+			destination: x,
+		})
+		copies = append(copies, x)
+	}
+	return copies
+}
+
+// ReplicateAs copies the given secret into the given namespace under the given name and returns the copy.
+func (r *Replicator) ReplicateAs(ns, name string, s *corev1.Secret) *corev1.Secret {
+	x := CopyToNamespace(ns, s)[0]
+	x.Name = name
+	r.add(replica{
+		source:      types.NamespacedName{Name: s.Name, Namespace: s.Namespace},
+		destination: x,
+	})
+	return x
+}
+
+func (r *Replicator) add(rep replica) {
+	for i, existing := range r.replicas {
+		if existing.destination.Name == rep.destination.Name && existing.destination.Namespace == rep.destination.Namespace {
+			r.replicas[i] = rep
+			return
+		}
+	}
+	r.replicas = append(r.replicas, rep)
+}
+
+// Sources returns the source of every replicated secret.
+func (r *Replicator) Sources() []types.NamespacedName {
+	var sources []types.NamespacedName
+	for _, rep := range r.replicas {
+		sources = append(sources, rep.source)
+	}
+	return sources
+}
+
+// Objects returns the copies of the replicated secrets.
+func (r *Replicator) Objects() []client.Object {
+	var objs []client.Object
+	for _, rep := range r.replicas {
+		objs = append(objs, rep.destination)
+	}
+	return objs
+}
+
+// AnnotateConsumers annotates the pod template of each of the given workloads with the hash of every replicated secret
+// in its namespace that it mounts or reads environment variables from. Objects that are not workloads are ignored.
+func (r *Replicator) AnnotateConsumers(objs ...client.Object) {
+	for _, obj := range objs {
+		template := podTemplate(obj)
+		if template == nil {
+			continue
+		}
+
+		consumed := consumedSecrets(&template.Spec)
+		for _, rep := range r.replicas {
+			if rep.destination.Namespace != obj.GetNamespace() || !consumed[rep.destination.Name] {
+				continue
+			}
+			// Copy the annotations since they may be shared with other objects of the component.
+			annotations := map[string]string{}
+			for k, v := range template.Annotations {
+				annotations[k] = v
+			}
+			annotations[HashAnnotationPrefix+rep.destination.Name] = rmeta.AnnotationHash(rep.destination.Data)
+			template.Annotations = annotations
+		}
+	}
+}
+
+// podTemplate returns the pod template of the given workload, or nil if the object is not a workload.
+func podTemplate(obj client.Object) *corev1.PodTemplateSpec {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return &o.Spec.Template
+	case *appsv1.DaemonSet:
+		return &o.Spec.Template
+	case *appsv1.StatefulSet:
+		return &o.Spec.Template
+	case *batchv1.Job:
+		return &o.Spec.Template
+	case *batchv1beta.CronJob:
+		return &o.Spec.JobTemplate.Spec.Template
+	}
+	return nil
+}
+
+// consumedSecrets returns the names of the secrets the pod mounts or reads environment variables from.
+func consumedSecrets(spec *corev1.PodSpec) map[string]bool {
+	names := map[string]bool{}
+	for _, v := range spec.Volumes {
+		if v.Secret != nil {
+			names[v.Secret.SecretName] = true
+		}
+		if v.Projected != nil {
+			for _, s := range v.Projected.Sources {
+				if s.Secret != nil {
+					names[s.Secret.Name] = true
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		for _, env := range c.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				names[env.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+		for _, envFrom := range c.EnvFrom {
+			if envFrom.SecretRef != nil {
+				names[envFrom.SecretRef.Name] = true
+			}
+		}
+	}
+	return names
+}
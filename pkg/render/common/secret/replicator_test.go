@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Secret replicator tests", func() {
+	var r *Replicator
+	var tlsSecret, caSecret *corev1.Secret
+
+	newDeployment := func(ns string, volumes ...corev1.Volume) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "consumer", Namespace: ns},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"existing": "annotation"}},
+					Spec:       corev1.PodSpec{Volumes: volumes},
+				},
+			},
+		}
+	}
+
+	secretVolume := func(name string) corev1.Volume {
+		return corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}},
+		}
+	}
+
+	BeforeEach(func() {
+		r = NewReplicator()
+		tlsSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "tigera-operator"},
+			Data:       map[string][]byte{"cert": []byte("cert")},
+		}
+		caSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "tigera-operator"},
+			Data:       map[string][]byte{"ca": []byte("ca")},
+		}
+	})
+
+	It("should copy the secrets into the destination namespace and track their source", func() {
+		copies := r.Replicate("dest", tlsSecret, nil, caSecret)
+		Expect(copies).To(HaveLen(2))
+		Expect(copies[0].Namespace).To(Equal("dest"))
+		Expect(copies[0].Data).To(Equal(tlsSecret.Data))
+		Expect(r.Objects()).To(HaveLen(2))
+		Expect(r.Sources()).To(ConsistOf(
+			types.NamespacedName{Name: "tls", Namespace: "tigera-operator"},
+			types.NamespacedName{Name: "ca", Namespace: "tigera-operator"},
+		))
+
+		renamed := r.ReplicateAs("dest", "renamed", tlsSecret)
+		Expect(renamed.Name).To(Equal("renamed"))
+		Expect(renamed.Namespace).To(Equal("dest"))
+		Expect(r.Objects()).To(HaveLen(3))
+	})
+
+	It("should replace a secret replicated twice into the same namespace", func() {
+		r.Replicate("dest", tlsSecret)
+		updated := tlsSecret.DeepCopy()
+		updated.Data["cert"] = []byte("new-cert")
+		r.Replicate("dest", updated)
+
+		Expect(r.Objects()).To(HaveLen(1))
+		Expect(r.Objects()[0].(*corev1.Secret).Data["cert"]).To(Equal([]byte("new-cert")))
+	})
+
+	It("should only annotate the workloads consuming a copy in their namespace", func() {
+		r.Replicate("dest", tlsSecret, caSecret)
+		consumer := newDeployment("dest", secretVolume("tls"))
+		consumer.Spec.Template.Spec.Containers = []corev1.Container{{
+			Name: "container",
+			Env:  []corev1.EnvVar{{Name: "CA", ValueFrom: GetEnvVarSource("ca", "ca", false)}},
+		}}
+		otherNamespace := newDeployment("other", secretVolume("tls"))
+		notConsuming := newDeployment("dest", secretVolume("unrelated"))
+		r.AnnotateConsumers(consumer, otherNamespace, notConsuming, &corev1.ConfigMap{})
+
+		Expect(consumer.Spec.Template.Annotations).To(HaveKeyWithValue("existing", "annotation"))
+		Expect(consumer.Spec.Template.Annotations).To(HaveKey(HashAnnotationPrefix + "tls"))
+		Expect(consumer.Spec.Template.Annotations).To(HaveKey(HashAnnotationPrefix + "ca"))
+		Expect(otherNamespace.Spec.Template.Annotations).To(Equal(map[string]string{"existing": "annotation"}))
+		Expect(notConsuming.Spec.Template.Annotations).To(Equal(map[string]string{"existing": "annotation"}))
+	})
+
+	It("should only change the hash of the changed secret", func() {
+		annotations := func() map[string]string {
+			r := NewReplicator()
+			r.Replicate("dest", tlsSecret, caSecret)
+			consumer := newDeployment("dest", secretVolume("tls"), secretVolume("ca"))
+			r.AnnotateConsumers(consumer)
+			return consumer.Spec.Template.Annotations
+		}
+
+		before := annotations()
+		tlsSecret.Data["cert"] = []byte("new-cert")
+		after := annotations()
+		Expect(after[HashAnnotationPrefix+"tls"]).NotTo(Equal(before[HashAnnotationPrefix+"tls"]))
+		Expect(after[HashAnnotationPrefix+"ca"]).To(Equal(before[HashAnnotationPrefix+"ca"]))
+	})
+})
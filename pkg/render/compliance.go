@@ -31,11 +31,13 @@ import (
 
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/render/common/authentication"
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
+	"github.com/tigera/operator/pkg/render/common/provider"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
 
@@ -78,12 +80,14 @@ type ComplianceConfiguration struct {
 	ComplianceServerCertSecret  *corev1.Secret
 	ESClusterConfig             *relasticsearch.ClusterConfig
 	PullSecrets                 []*corev1.Secret
-	Openshift                   bool
 	ManagementCluster           *operatorv1.ManagementCluster
 	ManagementClusterConnection *operatorv1.ManagementClusterConnection
 	KeyValidatorConfig          authentication.KeyValidatorConfig
 	ClusterDomain               string
 	HasNoLicense                bool
+	// KubernetesVersion is the version of the cluster. The PodSecurityPolicies are only rendered when the cluster
+	// supports them, which is assumed when the version is unknown.
+	KubernetesVersion *common.VersionInfo
 }
 
 type complianceComponent struct {
@@ -218,9 +222,9 @@ func (c *complianceComponent) Objects() ([]client.Object, []client.Object) {
 		}
 	}
 
-	if c.cfg.Openshift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).SecurityContextConstraints() {
 		complianceObjs = append(complianceObjs, c.complianceBenchmarkerSecurityContextConstraints())
-	} else {
+	} else if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() && c.cfg.KubernetesVersion.ProvidesPodSecurityPolicy() {
 		complianceObjs = append(complianceObjs,
 			c.complianceBenchmarkerPodSecurityPolicy(),
 			c.complianceControllerPodSecurityPolicy(),
@@ -229,9 +233,8 @@ func (c *complianceComponent) Objects() ([]client.Object, []client.Object) {
 			c.complianceSnapshotterPodSecurityPolicy())
 	}
 
-	// Need to grant cluster admin permissions in DockerEE to the controller since a pod starting pods with
-	// host path volumes requires cluster admin permissions.
-	if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderDockerEE {
+	// The controller starts pods with host path volumes, which may require cluster admin permissions.
+	if provider.For(c.cfg.Installation.KubernetesProvider).ClusterAdminForPrivilegedPods() {
 		complianceObjs = append(complianceObjs, c.complianceControllerClusterAdminClusterRoleBinding())
 	}
 
@@ -285,7 +288,7 @@ func (c *complianceComponent) complianceControllerRole() *rbacv1.Role {
 		},
 	}
 
-	if !c.cfg.Openshift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		rules = append(rules, rbacv1.PolicyRule{
 			APIGroups:     []string{"policy"},
@@ -457,7 +460,7 @@ func (c *complianceComponent) complianceReporterClusterRole() *rbacv1.ClusterRol
 		},
 	}
 
-	if !c.cfg.Openshift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		rules = append(rules, rbacv1.PolicyRule{
 			APIGroups:     []string{"policy"},
@@ -494,11 +497,8 @@ func (c *complianceComponent) complianceReporterClusterRoleBinding() *rbacv1.Clu
 
 func (c *complianceComponent) complianceReporterPodTemplate() *corev1.PodTemplate {
 	dirOrCreate := corev1.HostPathDirectoryOrCreate
-	privileged := false
-	// On OpenShift reporter needs privileged access to write compliance reports to host path volume
-	if c.cfg.Openshift {
-		privileged = true
-	}
+	// The reporter may need privileged access to write compliance reports to host path volume
+	privileged := provider.For(c.cfg.Installation.KubernetesProvider).PrivilegedHostPathWriters()
 
 	envVars := []corev1.EnvVar{
 		{Name: "LOG_LEVEL", Value: "warning"},
@@ -596,7 +596,7 @@ func (c *complianceComponent) complianceServerClusterRole() *rbacv1.ClusterRole
 		},
 	}
 
-	if !c.cfg.Openshift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		clusterRole.Rules = append(clusterRole.Rules, rbacv1.PolicyRule{
 			APIGroups:     []string{"policy"},
@@ -872,7 +872,7 @@ func (c *complianceComponent) complianceSnapshotterClusterRole() *rbacv1.Cluster
 		},
 	}
 
-	if !c.cfg.Openshift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{"policy"},
 			Resources:     []string{"podsecuritypolicies"},
@@ -987,7 +987,7 @@ func (c *complianceComponent) complianceBenchmarkerClusterRole() *rbacv1.Cluster
 		},
 	}
 
-	if !c.cfg.Openshift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{"policy"},
 			Resources:     []string{"podsecuritypolicies"},
@@ -45,7 +45,6 @@ var _ = Describe("compliance rendering tests", func() {
 			},
 			ComplianceServerCertSecret: complianceServerCertSecret,
 			ESClusterConfig:            relasticsearch.NewClusterConfig("cluster", 1, 1, 1),
-			Openshift:                  notOpenshift,
 			ClusterDomain:              clusterDomain,
 		}
 	})
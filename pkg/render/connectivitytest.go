@@ -0,0 +1,242 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/connectivitytest"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
+)
+
+const (
+	// ConnectivityTestName is the name of the Deployment, the Service and the Job of the connectivity test.
+	ConnectivityTestName = "calico-connectivity-test"
+	// ConnectivityTestRunAnnotation is set on the pods of the Job to the run of the test, so that the Job is
+	// recreated for each run.
+	ConnectivityTestRunAnnotation = "operator.tigera.io/connectivity-test-run"
+
+	connectivityTestPodsServiceName = "calico-connectivity-test-pods"
+	connectivityTestPort            = 8080
+	connectivityTestDefaultNodes    = 3
+	connectivityTestDeadlineSeconds = 300
+)
+
+// ConnectivityTestConfiguration contains all the config information needed to render the connectivity test.
+type ConnectivityTestConfiguration struct {
+	Installation  *operatorv1.InstallationSpec
+	ClusterDomain string
+	// Run identifies the running test. The objects of the test are deleted when it is empty.
+	Run string
+	// ServersReady is set once the pods of the test Deployment are available, and the Job of the test can start.
+	ServersReady bool
+	// Node is the node migrated to the VPP dataplane whose connectivity is verified, if any. The Job runs there,
+	// tolerating the taint of the migration.
+	Node string
+}
+
+// ConnectivityTest renders the dataplane connectivity test: a Deployment of servers spread over the nodes, their
+// Services, and the Job checking the connectivity to them. The operator image runs both the servers and the checks.
+func ConnectivityTest(cfg *ConnectivityTestConfiguration) Component {
+	return &connectivityTestComponent{cfg: cfg}
+}
+
+type connectivityTestComponent struct {
+	cfg   *ConnectivityTestConfiguration
+	image string
+}
+
+func (c *connectivityTestComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	reg := c.cfg.Installation.Registry
+	path := c.cfg.Installation.ImagePath
+	prefix := c.cfg.Installation.ImagePrefix
+	var err error
+	c.image, err = components.GetReference(components.ComponentOperatorInit, reg, path, prefix, is)
+	return err
+}
+
+func (c *connectivityTestComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeLinux
+}
+
+func (c *connectivityTestComponent) Objects() ([]client.Object, []client.Object) {
+	objs := []client.Object{
+		c.serviceAccount(),
+		c.deployment(),
+		c.service(ConnectivityTestName, false),
+		c.service(connectivityTestPodsServiceName, true),
+	}
+	if c.cfg.Run == "" {
+		return nil, append(objs, c.job())
+	}
+	if !c.cfg.ServersReady {
+		return objs, []client.Object{c.job()}
+	}
+	return append(objs, c.job()), nil
+}
+
+func (c *connectivityTestComponent) Ready() bool {
+	return true
+}
+
+// ConnectivityTestNodes returns the number of nodes over which the servers of the connectivity test are spread.
+func ConnectivityTestNodes(ct *operatorv1.ConnectivityTest) int32 {
+	if ct != nil && ct.Nodes != nil {
+		return *ct.Nodes
+	}
+	return connectivityTestDefaultNodes
+}
+
+func (c *connectivityTestComponent) serviceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: ConnectivityTestName, Namespace: common.CalicoNamespace},
+	}
+}
+
+func (c *connectivityTestComponent) labels() map[string]string {
+	return map[string]string{"k8s-app": ConnectivityTestName}
+}
+
+// deployment returns the servers of the test. They are spread over the nodes, so that the connectivity between the
+// nodes is checked.
+func (c *connectivityTestComponent) deployment() *appsv1.Deployment {
+	replicas := ConnectivityTestNodes(c.cfg.Installation.ConnectivityTest)
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: ConnectivityTestName, Namespace: common.CalicoNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: c.labels()},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: c.labels()},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: ConnectivityTestName,
+					ImagePullSecrets:   c.cfg.Installation.ImagePullSecrets,
+					Affinity: &corev1.Affinity{
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+								Weight: 100,
+								PodAffinityTerm: corev1.PodAffinityTerm{
+									LabelSelector: &metav1.LabelSelector{MatchLabels: c.labels()},
+									TopologyKey:   "kubernetes.io/hostname",
+								},
+							}},
+						},
+					},
+					Containers: []corev1.Container{{
+						Name:            "server",
+						Image:           c.image,
+						Args:            []string{"--connectivity-test-server"},
+						Env:             []corev1.EnvVar{{Name: connectivitytest.PortEnv, Value: fmt.Sprint(connectivityTestPort)}},
+						Ports:           []corev1.ContainerPort{{ContainerPort: connectivityTestPort, Protocol: corev1.ProtocolTCP}},
+						SecurityContext: podsecuritycontext.NewBaseContext(),
+						ReadinessProbe: &corev1.Probe{
+							Handler: corev1.Handler{
+								HTTPGet: &corev1.HTTPGetAction{Path: "/", Port: intstr.FromInt(connectivityTestPort)},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// service returns a Service of the servers. The headless Service resolves to the addresses of the servers.
+func (c *connectivityTestComponent) service(name string, headless bool) *corev1.Service {
+	svc := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.CalicoNamespace},
+		Spec: corev1.ServiceSpec{
+			Selector: c.labels(),
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       connectivityTestPort,
+				TargetPort: intstr.FromInt(connectivityTestPort),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+	if headless {
+		svc.Spec.ClusterIP = corev1.ClusterIPNone
+	}
+	return svc
+}
+
+// job returns the Job running the checks. It fails at the first failure of its pod, whose termination message
+// reports the failed checks.
+func (c *connectivityTestComponent) job() *batchv1.Job {
+	var backoffLimit int32
+	var deadline int64 = connectivityTestDeadlineSeconds
+	env := []corev1.EnvVar{
+		{Name: connectivitytest.ServiceEnv, Value: c.serviceDNSName(ConnectivityTestName)},
+		{Name: connectivitytest.PodsServiceEnv, Value: c.serviceDNSName(connectivityTestPodsServiceName)},
+		{Name: connectivitytest.PortEnv, Value: fmt.Sprint(connectivityTestPort)},
+	}
+	if ct := c.cfg.Installation.ConnectivityTest; ct != nil && ct.ExternalEndpoint != "" {
+		env = append(env, corev1.EnvVar{Name: connectivitytest.ExternalEndpointEnv, Value: ct.ExternalEndpoint})
+	}
+	var nodeSelector map[string]string
+	var tolerations []corev1.Toleration
+	if c.cfg.Node != "" {
+		nodeSelector = map[string]string{"kubernetes.io/hostname": c.cfg.Node}
+		tolerations = []corev1.Toleration{{Key: common.DataplaneMigrationTaintKey, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}}
+	}
+	return &batchv1.Job{
+		TypeMeta:   metav1.TypeMeta{Kind: "Job", APIVersion: "batch/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: ConnectivityTestName, Namespace: common.CalicoNamespace},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &deadline,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"job-name": ConnectivityTestName},
+					Annotations: map[string]string{ConnectivityTestRunAnnotation: c.cfg.Run},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: ConnectivityTestName,
+					ImagePullSecrets:   c.cfg.Installation.ImagePullSecrets,
+					NodeSelector:       nodeSelector,
+					Tolerations:        tolerations,
+					Containers: []corev1.Container{{
+						Name:                     "connectivity-test",
+						Image:                    c.image,
+						Args:                     []string{"--connectivity-test"},
+						Env:                      env,
+						TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						SecurityContext:          podsecuritycontext.NewBaseContext(),
+					}},
+				},
+			},
+		},
+	}
+}
+
+func (c *connectivityTestComponent) serviceDNSName(name string) string {
+	return fmt.Sprintf("%s.%s.svc.%s", name, common.CalicoNamespace, c.cfg.ClusterDomain)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/connectivitytest"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("Connectivity test rendering tests", func() {
+	var cfg *render.ConnectivityTestConfiguration
+
+	BeforeEach(func() {
+		cfg = &render.ConnectivityTestConfiguration{
+			Installation: &operatorv1.InstallationSpec{
+				ConnectivityTest: &operatorv1.ConnectivityTest{Trigger: "1"},
+			},
+			ClusterDomain: "cluster.local",
+			Run:           "1634284800",
+			ServersReady:  true,
+		}
+	})
+
+	It("should delete the objects of the test when it is not running", func() {
+		cfg.Run = ""
+		toCreate, toDelete := render.ConnectivityTest(cfg).Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(5))
+	})
+
+	It("should only start the job once the servers are ready", func() {
+		cfg.ServersReady = false
+		toCreate, toDelete := render.ConnectivityTest(cfg).Objects()
+		Expect(toCreate).To(HaveLen(4))
+		Expect(toDelete).To(HaveLen(1))
+		rtest.ExpectResource(toDelete[0], render.ConnectivityTestName, "calico-system", "batch", "v1", "Job")
+	})
+
+	It("should render the servers and the job", func() {
+		nodes := int32(5)
+		cfg.Installation.ConnectivityTest.Nodes = &nodes
+		cfg.Installation.ConnectivityTest.ExternalEndpoint = "example.com:443"
+		component := render.ConnectivityTest(cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, toDelete := component.Objects()
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(5))
+
+		d := rtest.GetResource(toCreate, render.ConnectivityTestName, "calico-system", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(*d.Spec.Replicas).To(Equal(int32(5)))
+		Expect(d.Spec.Template.Spec.Affinity.PodAntiAffinity).NotTo(BeNil())
+		Expect(d.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"--connectivity-test-server"}))
+
+		svc := rtest.GetResource(toCreate, "calico-connectivity-test-pods", "calico-system", "", "v1", "Service").(*corev1.Service)
+		Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+
+		job := rtest.GetResource(toCreate, render.ConnectivityTestName, "calico-system", "batch", "v1", "Job").(*batchv1.Job)
+		Expect(job.Spec.Template.Annotations).To(HaveKeyWithValue(render.ConnectivityTestRunAnnotation, "1634284800"))
+		Expect(*job.Spec.BackoffLimit).To(Equal(int32(0)))
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ConsistOf(
+			corev1.EnvVar{Name: connectivitytest.ServiceEnv, Value: "calico-connectivity-test.calico-system.svc.cluster.local"},
+			corev1.EnvVar{Name: connectivitytest.PodsServiceEnv, Value: "calico-connectivity-test-pods.calico-system.svc.cluster.local"},
+			corev1.EnvVar{Name: connectivitytest.PortEnv, Value: "8080"},
+			corev1.EnvVar{Name: connectivitytest.ExternalEndpointEnv, Value: "example.com:443"},
+		))
+		Expect(job.Spec.Template.Spec.NodeSelector).To(BeNil())
+		Expect(job.Spec.Template.Spec.Tolerations).To(BeNil())
+	})
+
+	It("should run the job on the node migrated to the VPP dataplane", func() {
+		cfg.Node = "node-0"
+		toCreate, _ := render.ConnectivityTest(cfg).Objects()
+
+		job := rtest.GetResource(toCreate, render.ConnectivityTestName, "calico-system", "batch", "v1", "Job").(*batchv1.Job)
+		Expect(job.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/hostname": "node-0"}))
+		Expect(job.Spec.Template.Spec.Tolerations).To(ConsistOf(corev1.Toleration{
+			Key:      common.DataplaneMigrationTaintKey,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		}))
+	})
+})
@@ -16,11 +16,13 @@ package render
 
 import (
 	"bytes"
+	"crypto/md5"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"fmt"
 	"math/big"
 	mrand "math/rand"
 	"strings"
@@ -35,10 +37,12 @@ import (
 
 // Voltron related constants.
 const (
-	VoltronDnsName      = "voltron"
-	VoltronKeySizeBits  = 2048
-	blockTypePrivateKey = "RSA PRIVATE KEY"
-	blockTypeCert       = "CERTIFICATE"
+	VoltronDnsName           = "voltron"
+	VoltronKeySizeBits       = 2048
+	blockTypePrivateKey      = "RSA PRIVATE KEY"
+	blockTypeECPrivateKey    = "EC PRIVATE KEY"
+	blockTypePKCS8PrivateKey = "PRIVATE KEY"
+	blockTypeCert            = "CERTIFICATE"
 )
 
 // Creates a secret that will store the CA needed to generated certificates
@@ -102,6 +106,146 @@ func createSelfSignedSecret(cn string, altNames []string) (string, string) {
 	return keyPem.String(), certPem.String()
 }
 
+// CreateVoltronTunnelCA creates the certificate and key of a new tunnel CA.
+func CreateVoltronTunnelCA() ([]byte, []byte) {
+	key, cert := createSelfSignedSecret("tigera-voltron", []string{VoltronDnsName})
+	return []byte(cert), []byte(key)
+}
+
+// TunnelSigningCA returns the certificate and key that sign the certificates of the managed clusters. While the tunnel
+// CA is being rotated, the cert field of the tunnel secret holds the previous CA followed by the new one, so that both
+// are trusted, while the key field keeps the key of the previous CA. The new CA then signs the certificates.
+func TunnelSigningCA(tunnelSecret *corev1.Secret) ([]byte, []byte) {
+	certs := tunnelSecret.Data[VoltronTunnelSecretCertName]
+	nextKey, rotating := tunnelSecret.Data[VoltronTunnelSecretNextKeyName]
+	if !rotating {
+		return certs, tunnelSecret.Data[VoltronTunnelSecretKeyName]
+	}
+
+	var last []byte
+	for rest := certs; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		last = pem.EncodeToMemory(block)
+	}
+	return last, nextKey
+}
+
+// CertificateExpiry returns the time after which the given certificate is no longer valid.
+func CertificateExpiry(certPem []byte) (time.Time, error) {
+	cert, err := parseCertificate(certPem)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// CreateManagedClusterCertificate creates the certificate and key used by the guardian of a managed cluster to
+// authenticate against Voltron. The certificate is signed by the tunnel CA and carries the name of the managed cluster
+// as common name.
+func CreateManagedClusterCertificate(caCertPem, caKeyPem []byte, clusterName string) ([]byte, []byte, error) {
+	caCert, err := parseCertificate(caCertPem)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the tunnel CA certificate: %w", err)
+	}
+	caKey, err := parsePrivateKey(caKeyPem)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the tunnel CA key: %w", err)
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, VoltronKeySizeBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	certTemplate := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: clusterName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(0, 0, crypto.DefaultCertificateLifetimeInDays),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, certTemplate, caCert, &privateKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keyPem bytes.Buffer
+	if err := pem.Encode(&keyPem, &pem.Block{Type: blockTypePrivateKey, Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}); err != nil {
+		return nil, nil, err
+	}
+	var certPem bytes.Buffer
+	if err := pem.Encode(&certPem, &pem.Block{Type: blockTypeCert, Bytes: cert}); err != nil {
+		return nil, nil, err
+	}
+	return certPem.Bytes(), keyPem.Bytes(), nil
+}
+
+// ManagedClusterCertificateFingerprint returns the fingerprint Voltron uses to identify the certificate of a managed
+// cluster.
+func ManagedClusterCertificateFingerprint(certPem []byte) (string, error) {
+	cert, err := parseCertificate(certPem)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", md5.Sum(cert.Raw)), nil
+}
+
+// IsSignedBy returns whether the given certificate is signed by the given CA certificate.
+func IsSignedBy(certPem, caCertPem []byte) bool {
+	cert, err := parseCertificate(certPem)
+	if err != nil {
+		return false
+	}
+	caCert, err := parseCertificate(caCertPem)
+	if err != nil {
+		return false
+	}
+	return cert.CheckSignatureFrom(caCert) == nil
+}
+
+// parseCertificate parses the first certificate of the given PEM data, which may hold a chain of certificates or other
+// blocks, e.g. the key of the certificate.
+func parseCertificate(certPem []byte) (*x509.Certificate, error) {
+	for rest := certPem; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM encoded certificate found")
+		}
+		if block.Type == blockTypeCert {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// parsePrivateKey parses the first private key of the given PEM data. PKCS#1 RSA keys, SEC 1 EC keys and PKCS#8 keys
+// are supported. Other blocks, e.g. the EC PARAMETERS block written by openssl, are skipped.
+func parsePrivateKey(keyPem []byte) (interface{}, error) {
+	for rest := keyPem; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM encoded key found")
+		}
+		switch block.Type {
+		case blockTypePrivateKey:
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case blockTypeECPrivateKey:
+			return x509.ParseECPrivateKey(block.Bytes)
+		case blockTypePKCS8PrivateKey:
+			return x509.ParsePKCS8PrivateKey(block.Bytes)
+		}
+	}
+}
+
 func template(cn string, altNames []string) *x509.Certificate {
 	return &x509.Certificate{
 		IsCA:                  true,
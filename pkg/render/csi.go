@@ -0,0 +1,303 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/ptr"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
+	"github.com/tigera/operator/pkg/render/common/provider"
+)
+
+const (
+	// CSIDriverName is the name of the CSI driver providing the policy sync API to the pods.
+	CSIDriverName = "csi.tigera.io"
+
+	CSIDaemonSetName = "csi-node-driver"
+
+	DefaultKubeletVolumePluginPath = "/var/lib/kubelet"
+)
+
+// CSI renders the csi.tigera.io CSI driver, which provides the policy sync API of calico-node to the pods in place of
+// the flexvolume driver. The driver is deleted when the Installation doesn't use it. The PodSecurityPolicy is only
+// rendered when the given version of the cluster supports it, which is assumed when the version is unknown.
+func CSI(installation *operatorv1.InstallationSpec, kubernetesVersion *common.VersionInfo) Component {
+	return &csiComponent{installation: installation, kubernetesVersion: kubernetesVersion}
+}
+
+type csiComponent struct {
+	installation      *operatorv1.InstallationSpec
+	kubernetesVersion *common.VersionInfo
+
+	csiImage          string
+	csiRegistrarImage string
+}
+
+func (c *csiComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	reg := c.installation.Registry
+	path := c.installation.ImagePath
+	prefix := c.installation.ImagePrefix
+	errMsgs := []string{}
+
+	var err error
+	c.csiImage, err = components.GetReference(components.ComponentCSI, reg, path, prefix, is)
+	if err != nil {
+		errMsgs = append(errMsgs, err.Error())
+	}
+
+	c.csiRegistrarImage, err = components.GetReference(components.ComponentCSINodeDriverRegistrar, reg, path, prefix, is)
+	if err != nil {
+		errMsgs = append(errMsgs, err.Error())
+	}
+
+	if len(errMsgs) != 0 {
+		return fmt.Errorf(strings.Join(errMsgs, ","))
+	}
+	return nil
+}
+
+func (c *csiComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeLinux
+}
+
+func (c *csiComponent) Objects() ([]client.Object, []client.Object) {
+	objs := []client.Object{
+		c.csiDriver(),
+		c.csiServiceAccount(),
+		c.csiDaemonset(),
+	}
+	if provider.For(c.installation.KubernetesProvider).PodSecurityPolicies() && c.kubernetesVersion.ProvidesPodSecurityPolicy() {
+		objs = append(objs, c.csiPodSecurityPolicy(), c.csiRole(), c.csiRoleBinding())
+	}
+
+	if !CSIDriverEnabled(c.installation) {
+		return nil, objs
+	}
+	return objs, nil
+}
+
+func (c *csiComponent) Ready() bool {
+	return true
+}
+
+// CSIDriverEnabled returns true if the Installation deploys the CSI driver providing the policy sync API.
+func CSIDriverEnabled(installation *operatorv1.InstallationSpec) bool {
+	return installation.PolicySyncDriver != nil &&
+		(*installation.PolicySyncDriver == operatorv1.PolicySyncDriverCSI ||
+			*installation.PolicySyncDriver == operatorv1.PolicySyncDriverBoth)
+}
+
+// FlexVolumeDriverEnabled returns true if the Installation installs the flexvolume driver providing the policy sync
+// API.
+func FlexVolumeDriverEnabled(installation *operatorv1.InstallationSpec) bool {
+	if installation.FlexVolumePath == "None" {
+		return false
+	}
+	return installation.PolicySyncDriver == nil || *installation.PolicySyncDriver != operatorv1.PolicySyncDriverCSI
+}
+
+func (c *csiComponent) kubeletDir() string {
+	if c.installation.KubeletVolumePluginPath != "" {
+		return c.installation.KubeletVolumePluginPath
+	}
+	return DefaultKubeletVolumePluginPath
+}
+
+func (c *csiComponent) csiDriver() *storagev1.CSIDriver {
+	return &storagev1.CSIDriver{
+		TypeMeta: metav1.TypeMeta{Kind: "CSIDriver", APIVersion: "storage.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: CSIDriverName,
+		},
+		Spec: storagev1.CSIDriverSpec{
+			// The driver identifies the workload from the pod info to serve its policy sync socket.
+			PodInfoOnMount: ptr.BoolToPtr(true),
+			AttachRequired: ptr.BoolToPtr(false),
+			VolumeLifecycleModes: []storagev1.VolumeLifecycleMode{
+				storagev1.VolumeLifecycleEphemeral,
+			},
+		},
+	}
+}
+
+func (c *csiComponent) csiServiceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CSIDaemonSetName,
+			Namespace: common.CalicoNamespace,
+		},
+	}
+}
+
+// csiRole allows the CSI driver to use its pod security policy.
+func (c *csiComponent) csiRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: CSIDaemonSetName,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{"policy"},
+				Resources:     []string{"podsecuritypolicies"},
+				Verbs:         []string{"use"},
+				ResourceNames: []string{CSIDaemonSetName},
+			},
+		},
+	}
+}
+
+func (c *csiComponent) csiRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: CSIDaemonSetName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     CSIDaemonSetName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      CSIDaemonSetName,
+				Namespace: common.CalicoNamespace,
+			},
+		},
+	}
+}
+
+func (c *csiComponent) csiPodSecurityPolicy() *policyv1beta1.PodSecurityPolicy {
+	psp := podsecuritypolicy.NewBasePolicy()
+	psp.GetObjectMeta().SetName(CSIDaemonSetName)
+	// The driver mounts the policy sync socket into the pod volumes, which requires bidirectional mount propagation.
+	psp.Spec.Privileged = true
+	psp.Spec.AllowPrivilegeEscalation = ptr.BoolToPtr(true)
+	psp.Spec.Volumes = append(psp.Spec.Volumes, policyv1beta1.HostPath)
+	psp.Spec.RunAsUser.Rule = policyv1beta1.RunAsUserStrategyRunAsAny
+	return psp
+}
+
+func (c *csiComponent) csiDaemonset() *appsv1.DaemonSet {
+	ds := &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CSIDaemonSetName,
+			Namespace: common.CalicoNamespace,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": CSIDaemonSetName}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"k8s-app": CSIDaemonSetName},
+				},
+				Spec: corev1.PodSpec{
+					Tolerations:        rmeta.TolerateAll,
+					ImagePullSecrets:   c.installation.ImagePullSecrets,
+					ServiceAccountName: CSIDaemonSetName,
+					NodeSelector:       map[string]string{"kubernetes.io/os": "linux"},
+					Containers:         []corev1.Container{c.csiContainer(), c.csiRegistrarContainer()},
+					Volumes:            c.csiVolumes(),
+				},
+			},
+		},
+	}
+	setNodeCriticalPod(&ds.Spec.Template)
+	return ds
+}
+
+func (c *csiComponent) csiContainer() corev1.Container {
+	bidirectional := corev1.MountPropagationBidirectional
+	return corev1.Container{
+		Name:  "calico-csi",
+		Image: c.csiImage,
+		Args: []string{
+			"--nodeid=$(KUBE_NODE_NAME)",
+			"--loglevel=$(LOG_LEVEL)",
+		},
+		Env: []corev1.EnvVar{
+			{Name: "LOG_LEVEL", Value: "warn"},
+			{
+				Name: "KUBE_NODE_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+				},
+			},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: ptr.BoolToPtr(true),
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			// The policy sync sockets of calico-node, under /var/run/nodeagent.
+			{Name: "varrun", MountPath: "/var/run"},
+			{Name: "socket-dir", MountPath: "/csi"},
+			{Name: "kubelet-dir", MountPath: c.kubeletDir(), MountPropagation: &bidirectional},
+		},
+	}
+}
+
+func (c *csiComponent) csiRegistrarContainer() corev1.Container {
+	return corev1.Container{
+		Name:  "csi-node-driver-registrar",
+		Image: c.csiRegistrarImage,
+		Args: []string{
+			"--v=5",
+			"--csi-address=$(ADDRESS)",
+			"--kubelet-registration-path=$(DRIVER_REG_SOCK_PATH)",
+		},
+		Env: []corev1.EnvVar{
+			{Name: "ADDRESS", Value: "/csi/csi.sock"},
+			{Name: "DRIVER_REG_SOCK_PATH", Value: filepath.Join(c.kubeletDir(), "plugins", CSIDriverName, "csi.sock")},
+			{
+				Name: "KUBE_NODE_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "socket-dir", MountPath: "/csi"},
+			{Name: "registration-dir", MountPath: "/registration"},
+		},
+	}
+}
+
+func (c *csiComponent) csiVolumes() []corev1.Volume {
+	dirOrCreate := corev1.HostPathDirectoryOrCreate
+	dirMustExist := corev1.HostPathDirectory
+	return []corev1.Volume{
+		{Name: "varrun", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run"}}},
+		{Name: "kubelet-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: c.kubeletDir(), Type: &dirMustExist}}},
+		{Name: "socket-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: filepath.Join(c.kubeletDir(), "plugins", CSIDriverName), Type: &dirOrCreate}}},
+		{Name: "registration-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: filepath.Join(c.kubeletDir(), "plugins_registry"), Type: &dirMustExist}}},
+	}
+}
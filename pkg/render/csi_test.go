@@ -0,0 +1,127 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("CSI rendering tests", func() {
+	var installation *operatorv1.InstallationSpec
+	BeforeEach(func() {
+		csi := operatorv1.PolicySyncDriverCSI
+		installation = &operatorv1.InstallationSpec{
+			PolicySyncDriver: &csi,
+			FlexVolumePath:   "None",
+		}
+	})
+
+	It("should render the CSI driver", func() {
+		component := render.CSI(installation, nil)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, toDelete := component.Objects()
+		Expect(toDelete).To(BeEmpty())
+
+		expectedResources := []struct {
+			name    string
+			ns      string
+			group   string
+			version string
+			kind    string
+		}{
+			{name: render.CSIDriverName, ns: "", group: "storage.k8s.io", version: "v1", kind: "CSIDriver"},
+			{name: render.CSIDaemonSetName, ns: common.CalicoNamespace, group: "", version: "v1", kind: "ServiceAccount"},
+			{name: render.CSIDaemonSetName, ns: common.CalicoNamespace, group: "apps", version: "v1", kind: "DaemonSet"},
+			{name: render.CSIDaemonSetName, ns: "", group: "policy", version: "v1beta1", kind: "PodSecurityPolicy"},
+			{name: render.CSIDaemonSetName, ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
+			{name: render.CSIDaemonSetName, ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
+		}
+		Expect(toCreate).To(HaveLen(len(expectedResources)))
+		for i, expectedRes := range expectedResources {
+			rtest.ExpectResource(toCreate[i], expectedRes.name, expectedRes.ns, expectedRes.group, expectedRes.version, expectedRes.kind)
+		}
+
+		driver := toCreate[0].(*storagev1.CSIDriver)
+		Expect(*driver.Spec.PodInfoOnMount).To(BeTrue())
+		Expect(driver.Spec.VolumeLifecycleModes).To(ConsistOf(storagev1.VolumeLifecycleEphemeral))
+
+		ds := toCreate[2].(*appsv1.DaemonSet)
+		csiContainer := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "calico-csi")
+		Expect(csiContainer).NotTo(BeNil())
+		Expect(csiContainer.Image).To(Equal(fmt.Sprintf("docker.io/%s:%s", components.ComponentCSI.Image, components.ComponentCSI.Version)))
+		registrar := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "csi-node-driver-registrar")
+		Expect(registrar).NotTo(BeNil())
+		rtest.ExpectEnv(registrar.Env, "DRIVER_REG_SOCK_PATH", "/var/lib/kubelet/plugins/csi.tigera.io/csi.sock")
+
+		dirMustExist := corev1.HostPathDirectory
+		Expect(ds.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name:         "registration-dir",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/lib/kubelet/plugins_registry", Type: &dirMustExist}},
+		}))
+	})
+
+	It("should not render the PodSecurityPolicy when the cluster doesn't support it", func() {
+		toCreate, _ := render.CSI(installation, &common.VersionInfo{Major: 1, Minor: 25}).Objects()
+		Expect(toCreate).To(HaveLen(3))
+		Expect(rtest.GetResource(toCreate, render.CSIDaemonSetName, "", "policy", "v1beta1", "PodSecurityPolicy")).To(BeNil())
+	})
+
+	It("should use the custom kubelet directory", func() {
+		installation.KubeletVolumePluginPath = "/var/data/kubelet"
+		component := render.CSI(installation, nil)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, _ := component.Objects()
+
+		ds := rtest.GetResource(toCreate, render.CSIDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		registrar := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "csi-node-driver-registrar")
+		rtest.ExpectEnv(registrar.Env, "DRIVER_REG_SOCK_PATH", "/var/data/kubelet/plugins/csi.tigera.io/csi.sock")
+	})
+
+	It("should delete the CSI driver when the flexvolume driver is used", func() {
+		installation.PolicySyncDriver = nil
+		installation.FlexVolumePath = "/usr/libexec/kubernetes/kubelet-plugins/volume/exec/"
+		toCreate, toDelete := render.CSI(installation, nil).Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(rtest.GetResource(toDelete, render.CSIDriverName, "", "storage.k8s.io", "v1", "CSIDriver")).NotTo(BeNil())
+	})
+
+	It("should select the policy sync drivers", func() {
+		Expect(render.CSIDriverEnabled(installation)).To(BeTrue())
+		Expect(render.FlexVolumeDriverEnabled(installation)).To(BeFalse())
+
+		both := operatorv1.PolicySyncDriverBoth
+		installation.PolicySyncDriver = &both
+		installation.FlexVolumePath = "/usr/libexec/kubernetes/kubelet-plugins/volume/exec/"
+		Expect(render.CSIDriverEnabled(installation)).To(BeTrue())
+		Expect(render.FlexVolumeDriverEnabled(installation)).To(BeTrue())
+
+		installation.PolicySyncDriver = nil
+		Expect(render.CSIDriverEnabled(installation)).To(BeFalse())
+		Expect(render.FlexVolumeDriverEnabled(installation)).To(BeTrue())
+	})
+})
@@ -64,7 +64,6 @@ func Dex(cfg *DexComponentConfiguration) Component {
 // DexComponentConfiguration contains all the config information needed to render the component.
 type DexComponentConfiguration struct {
 	PullSecrets   []*corev1.Secret
-	Openshift     bool
 	Installation  *operatorv1.InstallationSpec
 	DexConfig     DexConfig
 	ClusterDomain string
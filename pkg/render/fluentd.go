@@ -29,10 +29,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
+	"github.com/tigera/operator/pkg/render/common/provider"
 	"github.com/tigera/operator/pkg/render/common/resourcequota"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
@@ -42,6 +44,7 @@ const (
 	FluentdFilterConfigMapName               = "fluentd-filters"
 	FluentdFilterFlowName                    = "flow"
 	FluentdFilterDNSName                     = "dns"
+	FluentdFilterL7Name                      = "l7"
 	S3FluentdSecretName                      = "log-collector-s3-credentials"
 	S3KeyIdName                              = "key-id"
 	S3KeySecretName                          = "key-secret"
@@ -87,6 +90,7 @@ const (
 type FluentdFilters struct {
 	Flow string
 	DNS  string
+	L7   string
 }
 
 type S3Credential struct {
@@ -136,6 +140,9 @@ type FluentdConfiguration struct {
 	Installation    *operatorv1.InstallationSpec
 	ClusterDomain   string
 	OSType          rmeta.OSType
+	// KubernetesVersion is the version of the cluster. The PodSecurityPolicies are only rendered when the cluster
+	// supports them, which is assumed when the version is unknown.
+	KubernetesVersion *common.VersionInfo
 }
 
 type fluentdComponent struct {
@@ -195,6 +202,12 @@ func (c *fluentdComponent) livenessCmd() []string {
 	return []string{"sh", "-c", "/bin/liveness.sh"}
 }
 
+// podSecurityPolicies returns true if the PodSecurityPolicies are rendered, unless the provider enforces its own
+// policies or the cluster doesn't support them.
+func (c *fluentdComponent) podSecurityPolicies() bool {
+	return provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() && c.cfg.KubernetesVersion.ProvidesPodSecurityPolicy()
+}
+
 func (c *fluentdComponent) volumeHostPath() string {
 	if c.cfg.OSType == rmeta.OSTypeWindows {
 		return "c:/TigeraCalico"
@@ -219,11 +232,7 @@ func (c *fluentdComponent) Objects() ([]client.Object, []client.Object) {
 			c.cfg.Installation.KubernetesProvider))
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(LogCollectorNamespace, c.cfg.PullSecrets...)...)...)
 
-	if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderGKE {
-		// We do this only for GKE as other providers don't (yet?)
-		// automatically add resource quota that constrains whether
-		// components that are marked cluster or node critical
-		// can be scheduled.
+	if provider.For(c.cfg.Installation.KubernetesProvider).CriticalPodsQuota() {
 		objs = append(objs, c.fluentdResourceQuota())
 	}
 	if c.cfg.S3Credential != nil {
@@ -236,7 +245,7 @@ func (c *fluentdComponent) Objects() ([]client.Object, []client.Object) {
 		objs = append(objs, c.filtersConfigMap())
 	}
 	if c.cfg.EKSConfig != nil && c.cfg.OSType == rmeta.OSTypeLinux {
-		if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+		if c.podSecurityPolicies() {
 			objs = append(objs,
 				c.eksLogForwarderClusterRole(),
 				c.eksLogForwarderClusterRoleBinding(),
@@ -249,7 +258,7 @@ func (c *fluentdComponent) Objects() ([]client.Object, []client.Object) {
 
 	// Windows PSP does not support allowedHostPaths yet.
 	// See: https://github.com/kubernetes/kubernetes/issues/93165#issuecomment-693049808
-	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift && c.cfg.OSType == rmeta.OSTypeLinux {
+	if c.podSecurityPolicies() && c.cfg.OSType == rmeta.OSTypeLinux {
 		objs = append(objs,
 			c.fluentdClusterRole(),
 			c.fluentdClusterRoleBinding(),
@@ -303,6 +312,7 @@ func (c *fluentdComponent) filtersConfigMap() *corev1.ConfigMap {
 		Data: map[string]string{
 			FluentdFilterFlowName: c.cfg.Filters.Flow,
 			FluentdFilterDNSName:  c.cfg.Filters.DNS,
+			FluentdFilterL7Name:   c.cfg.Filters.L7,
 		},
 	}
 }
@@ -489,6 +499,14 @@ func (c *fluentdComponent) container() corev1.Container {
 					SubPath:   FluentdFilterDNSName,
 				})
 		}
+		if c.cfg.Filters.L7 != "" {
+			volumeMounts = append(volumeMounts,
+				corev1.VolumeMount{
+					Name:      "fluentd-filters",
+					MountPath: c.path("/etc/fluentd/l7-filters.conf"),
+					SubPath:   FluentdFilterL7Name,
+				})
+		}
 	}
 
 	if c.cfg.SplkCredential != nil && len(c.cfg.SplkCredential.Certificate) != 0 {
@@ -499,11 +517,8 @@ func (c *fluentdComponent) container() corev1.Container {
 			})
 	}
 
-	isPrivileged := false
-	//On OpenShift Fluentd needs privileged access to access logs on host path volume
-	if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderOpenShift {
-		isPrivileged = true
-	}
+	// Fluentd may need privileged access to read the logs on the host path volume.
+	isPrivileged := provider.For(c.cfg.Installation.KubernetesProvider).PrivilegedHostLogReaders()
 
 	return relasticsearch.ContainerDecorateENVVars(corev1.Container{
 		Name:            "fluentd",
@@ -648,6 +663,10 @@ func (c *fluentdComponent) envvars() []corev1.EnvVar {
 			envs = append(envs,
 				corev1.EnvVar{Name: "FLUENTD_DNS_FILTERS", Value: "true"})
 		}
+		if c.cfg.Filters.L7 != "" {
+			envs = append(envs,
+				corev1.EnvVar{Name: "FLUENTD_L7_FILTERS", Value: "true"})
+		}
 	}
 
 	envs = append(envs,
@@ -662,6 +681,15 @@ func (c *fluentdComponent) envvars() []corev1.EnvVar {
 		corev1.EnvVar{Name: "ELASTIC_BGP_INDEX_SHARDS", Value: strconv.Itoa(c.cfg.ESClusterConfig.Shards())},
 	)
 
+	// The events of the VPP dataplane are only logged on the Linux nodes.
+	if c.cfg.OSType == rmeta.OSTypeLinux && collectDataplaneEvents(c.cfg.Installation, c.cfg.LogCollector) {
+		envs = append(envs,
+			corev1.EnvVar{Name: "DATAPLANE_EVENT_LOG_FILE", Value: VPPEventLogFile},
+			corev1.EnvVar{Name: "ELASTIC_DATAPLANE_INDEX_REPLICAS", Value: strconv.Itoa(c.cfg.ESClusterConfig.Replicas())},
+			corev1.EnvVar{Name: "ELASTIC_DATAPLANE_INDEX_SHARDS", Value: strconv.Itoa(c.cfg.ESClusterConfig.Shards())},
+		)
+	}
+
 	return envs
 }
 
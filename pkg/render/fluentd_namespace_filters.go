@@ -0,0 +1,97 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+const (
+	// LogCollectionNamespaceLabel is set on the namespaces to Enabled to opt them in the collection of the flow and L7
+	// logs, or to Disabled to opt them out of it, depending on the namespace selection of the LogCollector.
+	LogCollectionNamespaceLabel    = "operator.tigera.io/log-collection"
+	LogCollectionNamespaceEnabled  = "Enabled"
+	LogCollectionNamespaceDisabled = "Disabled"
+)
+
+// LogCollectionNamespaceLabelValue returns the value of the LogCollectionNamespaceLabel selecting the namespaces of the
+// given selection, or an empty string if all the namespaces are collected.
+func LogCollectionNamespaceLabelValue(selection operatorv1.LogCollectionNamespaceSelection) string {
+	switch selection {
+	case operatorv1.LogCollectionOptIn:
+		return LogCollectionNamespaceEnabled
+	case operatorv1.LogCollectionOptOut:
+		return LogCollectionNamespaceDisabled
+	}
+	return ""
+}
+
+// AddNamespaceFilters appends to the filters the fluentd filters collecting the flow and L7 logs of the given
+// namespaces: with OptIn, the namespaces opted in, and with OptOut, the namespaces opted out. A log is kept if either
+// of its endpoints is in a collected namespace. The filters set by the user in the fluentd-filters ConfigMap are kept
+// and run first.
+func AddNamespaceFilters(filters *FluentdFilters, selection operatorv1.LogCollectionNamespaceSelection, namespaces []string) *FluentdFilters {
+	if selection != operatorv1.LogCollectionOptIn && selection != operatorv1.LogCollectionOptOut {
+		return filters
+	}
+	// Nothing is opted out.
+	if selection == operatorv1.LogCollectionOptOut && len(namespaces) == 0 {
+		return filters
+	}
+	if filters == nil {
+		filters = &FluentdFilters{}
+	}
+	optOut := selection == operatorv1.LogCollectionOptOut
+	filters.Flow = appendFilter(filters.Flow, namespaceFilter("flows", "source_namespace", "dest_namespace", namespaces, optOut))
+	filters.L7 = appendFilter(filters.L7, namespaceFilter("l7", "src_namespace", "dest_namespace", namespaces, optOut))
+	return filters
+}
+
+func appendFilter(filters, filter string) string {
+	if filters == "" {
+		return filter
+	}
+	return strings.TrimRight(filters, "\n") + "\n" + filter
+}
+
+// namespaceFilter returns a fluentd grep filter of the logs of the given tag. Without optOut, it keeps the logs with
+// either endpoint in the namespaces, and none when there are no namespaces. With optOut, it drops the logs with both
+// endpoints in the namespaces.
+func namespaceFilter(tag, sourceKey, destKey string, namespaces []string, optOut bool) string {
+	ns := append([]string{}, namespaces...)
+	sort.Strings(ns)
+	// The namespaces are DNS labels, which need no escaping. An empty group matches nothing.
+	pattern := "/(?!)/"
+	if len(ns) > 0 {
+		pattern = fmt.Sprintf("/^(%s)$/", strings.Join(ns, "|"))
+	}
+
+	group, directive := "or", "regexp"
+	if optOut {
+		group, directive = "and", "exclude"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by the operator from the namespace selection of the LogCollector.\n")
+	fmt.Fprintf(&b, "<filter %s>\n  @type grep\n  <%s>\n", tag, group)
+	for _, key := range []string{sourceKey, destKey} {
+		fmt.Fprintf(&b, "    <%s>\n      key %s\n      pattern %s\n    </%s>\n", directive, key, pattern, directive)
+	}
+	fmt.Fprintf(&b, "  </%s>\n</filter>\n", group)
+	return b.String()
+}
@@ -604,6 +604,57 @@ var _ = Describe("Tigera Secure Fluentd rendering tests", func() {
 		Expect(envs).ToNot(ContainElement(corev1.EnvVar{Name: "FLUENTD_DNS_FILTERS", Value: "true"}))
 	})
 
+	It("should collect the flow and L7 logs of the namespaces opted in", func() {
+		cfg.Filters = render.AddNamespaceFilters(&render.FluentdFilters{Flow: "flow-filter"}, operatorv1.LogCollectionOptIn, []string{"shop", "billing"})
+		Expect(cfg.Filters.Flow).To(HavePrefix("flow-filter\n"))
+		Expect(cfg.Filters.Flow).To(ContainSubstring("<or>"))
+		Expect(cfg.Filters.Flow).To(ContainSubstring("key source_namespace\n      pattern /^(billing|shop)$/"))
+		Expect(cfg.Filters.L7).To(ContainSubstring("<filter l7>"))
+		Expect(cfg.Filters.L7).To(ContainSubstring("key src_namespace\n      pattern /^(billing|shop)$/"))
+
+		resources, _ := render.Fluentd(cfg).Objects()
+		cm := rtest.GetResource(resources, render.FluentdFilterConfigMapName, render.LogCollectorNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).To(HaveKeyWithValue(render.FluentdFilterL7Name, cfg.Filters.L7))
+		ds := rtest.GetResource(resources, "fluentd-node", "tigera-fluentd", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		envs := ds.Spec.Template.Spec.Containers[0].Env
+		Expect(envs).To(ContainElement(corev1.EnvVar{Name: "FLUENTD_FLOW_FILTERS", Value: "true"}))
+		Expect(envs).To(ContainElement(corev1.EnvVar{Name: "FLUENTD_L7_FILTERS", Value: "true"}))
+	})
+
+	It("should drop the flow and L7 logs of the namespaces opted out", func() {
+		filters := render.AddNamespaceFilters(nil, operatorv1.LogCollectionOptOut, []string{"noisy"})
+		Expect(filters.Flow).To(ContainSubstring("<and>"))
+		Expect(filters.Flow).To(ContainSubstring("<exclude>\n      key dest_namespace\n      pattern /^(noisy)$/"))
+
+		// Nothing is dropped when no namespace is opted out, and nothing is generated when all the namespaces are collected.
+		Expect(render.AddNamespaceFilters(nil, operatorv1.LogCollectionOptOut, nil)).To(BeNil())
+		Expect(render.AddNamespaceFilters(nil, operatorv1.LogCollectionAllNamespaces, []string{"noisy"})).To(BeNil())
+		// No log is kept when no namespace is opted in.
+		Expect(render.AddNamespaceFilters(nil, operatorv1.LogCollectionOptIn, nil).Flow).To(ContainSubstring("pattern /(?!)/"))
+	})
+
+	It("should ship the events of the VPP dataplane", func() {
+		vpp := operatorv1.LinuxDataplaneVPP
+		enabled := operatorv1.CollectDataplaneEventsEnable
+		cfg.Installation.CalicoNetwork = &operatorv1.CalicoNetworkSpec{LinuxDataplane: &vpp}
+		cfg.LogCollector.Spec.CollectDataplaneEvents = &enabled
+
+		resources, _ := render.Fluentd(cfg).Objects()
+		ds := rtest.GetResource(resources, "fluentd-node", "tigera-fluentd", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		envs := ds.Spec.Template.Spec.Containers[0].Env
+		Expect(envs).To(ContainElement(corev1.EnvVar{Name: "DATAPLANE_EVENT_LOG_FILE", Value: "/var/log/calico/vpp/events.log"}))
+		Expect(envs).To(ContainElement(corev1.EnvVar{Name: "ELASTIC_DATAPLANE_INDEX_REPLICAS", Value: "1"}))
+		Expect(envs).To(ContainElement(corev1.EnvVar{Name: "ELASTIC_DATAPLANE_INDEX_SHARDS", Value: "1"}))
+
+		// The events are not collected with the other dataplanes.
+		cfg.Installation.CalicoNetwork = nil
+		resources, _ = render.Fluentd(cfg).Objects()
+		ds = rtest.GetResource(resources, "fluentd-node", "tigera-fluentd", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		for _, env := range ds.Spec.Template.Spec.Containers[0].Env {
+			Expect(env.Name).NotTo(Equal("DATAPLANE_EVENT_LOG_FILE"))
+		}
+	})
+
 	It("should render with EKS Cloudwatch Log", func() {
 		expectedResources := []struct {
 			name    string
@@ -17,6 +17,9 @@
 package render
 
 import (
+	"strconv"
+	"strings"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -27,6 +30,7 @@ import (
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/components"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/podaffinity"
 	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
@@ -42,6 +46,21 @@ const (
 	GuardianServiceName            = "tigera-guardian"
 	GuardianVolumeName             = "tigera-guardian-certs"
 	GuardianSecretName             = "tigera-managed-cluster-connection"
+
+	// Keys of the guardian secret holding the tunnel certificates.
+	GuardianSecretManagementClusterCertName = "management-cluster.crt"
+	GuardianSecretManagedClusterCertName    = "managed-cluster.crt"
+	GuardianSecretManagedClusterKeyName     = "managed-cluster.key"
+
+	// Guardian serves the metrics of the tunnel on this port and reports its health in the status of the
+	// ManagementClusterConnection at this interval.
+	GuardianMetricsPort          = 9094
+	guardianStatusReportInterval = "30s"
+
+	// The CA trusted for the connection through the proxy is copied into the guardian namespace under this name.
+	GuardianProxyCASecretName     = "tigera-guardian-proxy-ca"
+	GuardianProxyCAKey            = "ca.crt"
+	guardianProxyCAHashAnnotation = "hash.operator.tigera.io/guardian-proxy-ca"
 )
 
 func Guardian(cfg *GuardianConfiguration) Component {
@@ -53,12 +72,17 @@ func Guardian(cfg *GuardianConfiguration) Component {
 // GuardianConfiguration contains all the config information needed to render the component.
 type GuardianConfiguration struct {
 	URL                  string
+	TunnelKeepAlive      *operatorv1.TunnelKeepAlive
+	ReconnectBackoff     *operatorv1.TunnelReconnectBackoff
+	Deployment           *operatorv1.GuardianDeployment
+	Proxy                *operatorv1.GuardianProxy
+	ProxyCASecret        *corev1.Secret
 	PullSecrets          []*corev1.Secret
-	Openshift            bool
 	Installation         *operatorv1.InstallationSpec
 	TunnelSecret         *corev1.Secret
 	PacketCaptureSecret  *corev1.Secret
 	PrometheusCertSecret *corev1.Secret
+	ClusterDomain        string
 }
 
 type GuardianComponent struct {
@@ -95,20 +119,42 @@ func (c *GuardianComponent) Objects() ([]client.Object, []client.Object) {
 		// Add tigera-manager service account for impersonation
 		CreateNamespace(ManagerNamespace, c.cfg.Installation.KubernetesProvider),
 		managerServiceAccount(),
-		managerClusterRole(false, true, c.cfg.Openshift),
+		managerClusterRole(false, true, c.cfg.Installation.KubernetesProvider),
 		managerClusterRoleBinding(),
 	)
 	if c.cfg.PrometheusCertSecret != nil {
 		objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(GuardianNamespace, c.cfg.PrometheusCertSecret)...)...)
 	}
 
-	return objs, nil
+	var toDelete []client.Object
+	if c.cfg.ProxyCASecret != nil {
+		objs = append(objs, c.proxyCASecret())
+	} else {
+		toDelete = append(toDelete, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: GuardianProxyCASecretName, Namespace: GuardianNamespace}})
+	}
+
+	return objs, toDelete
 }
 
 func (c *GuardianComponent) Ready() bool {
 	return true
 }
 
+// proxyCASecret copies the CA trusted for the connection through the proxy under a fixed name, since it is named by the
+// user in the operator namespace.
+func (c *GuardianComponent) proxyCASecret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GuardianProxyCASecretName,
+			Namespace: GuardianNamespace,
+		},
+		Data: map[string][]byte{
+			GuardianProxyCAKey: c.cfg.ProxyCASecret.Data[GuardianProxyCAKey],
+		},
+	}
+}
+
 func (c *GuardianComponent) service() *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -120,6 +166,12 @@ func (c *GuardianComponent) service() *corev1.Service {
 				"k8s-app": GuardianName,
 			},
 			Ports: []corev1.ServicePort{
+				{
+					Name:       "metrics",
+					Port:       GuardianMetricsPort,
+					TargetPort: intstr.FromInt(GuardianMetricsPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
 				{
 					Name: "elasticsearch",
 					Port: 9200,
@@ -162,6 +214,17 @@ func (c *GuardianComponent) clusterRole() client.Object {
 				Resources: []string{"users", "groups", "serviceaccounts"},
 				Verbs:     []string{"impersonate"},
 			},
+			{
+				// Guardian reports the health of the tunnel in the status of the ManagementClusterConnection.
+				APIGroups: []string{"operator.tigera.io"},
+				Resources: []string{"managementclusterconnections"},
+				Verbs:     []string{"get"},
+			},
+			{
+				APIGroups: []string{"operator.tigera.io"},
+				Resources: []string{"managementclusterconnections/status"},
+				Verbs:     []string{"get", "update", "patch"},
+			},
 		},
 	}
 }
@@ -189,6 +252,24 @@ func (c *GuardianComponent) clusterRoleBinding() client.Object {
 
 func (c *GuardianComponent) deployment() client.Object {
 	var replicas int32 = 1
+	nodeSelector := c.cfg.Installation.ControlPlaneNodeSelector
+	tolerations := c.cfg.Installation.ControlPlaneTolerations
+	if d := c.cfg.Deployment; d != nil {
+		if d.Replicas != nil {
+			replicas = *d.Replicas
+		}
+		if d.NodeSelector != nil {
+			nodeSelector = d.NodeSelector
+		}
+		if d.Tolerations != nil {
+			tolerations = d.Tolerations
+		}
+	}
+
+	var affinity *corev1.Affinity
+	if replicas > 1 {
+		affinity = podaffinity.NewPodAntiAffinity(GuardianName, GuardianNamespace)
+	}
 
 	return &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
@@ -219,9 +300,10 @@ func (c *GuardianComponent) deployment() client.Object {
 					},
 				},
 				Spec: corev1.PodSpec{
-					NodeSelector:       c.cfg.Installation.ControlPlaneNodeSelector,
+					NodeSelector:       nodeSelector,
 					ServiceAccountName: GuardianServiceAccountName,
-					Tolerations:        append(c.cfg.Installation.ControlPlaneTolerations, rmeta.TolerateMaster, rmeta.TolerateCriticalAddonsOnly),
+					Tolerations:        append(append([]corev1.Toleration{}, tolerations...), rmeta.TolerateMaster, rmeta.TolerateCriticalAddonsOnly),
+					Affinity:           affinity,
 					ImagePullSecrets:   secret.GetReferenceList(c.cfg.PullSecrets),
 					Containers:         c.container(),
 					Volumes:            c.volumes(),
@@ -265,19 +347,31 @@ func (c *GuardianComponent) volumes() []corev1.Volume {
 			},
 		})
 	}
+	if c.cfg.ProxyCASecret != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: GuardianProxyCASecretName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: GuardianProxyCASecretName,
+				},
+			},
+		})
+	}
 	return volumes
 }
 
 func (c *GuardianComponent) container() []corev1.Container {
+	var resources corev1.ResourceRequirements
+	if c.cfg.Deployment != nil && c.cfg.Deployment.Resources != nil {
+		resources = *c.cfg.Deployment.Resources
+	}
+
 	return []corev1.Container{
 		{
-			Name:  GuardianDeploymentName,
-			Image: c.image,
-			Env: []corev1.EnvVar{
-				{Name: "GUARDIAN_PORT", Value: "9443"},
-				{Name: "GUARDIAN_LOGLEVEL", Value: "INFO"},
-				{Name: "GUARDIAN_VOLTRON_URL", Value: c.cfg.URL},
-			},
+			Name:         GuardianDeploymentName,
+			Image:        c.image,
+			Env:          c.env(),
+			Resources:    resources,
 			VolumeMounts: c.volumeMounts(),
 			LivenessProbe: &corev1.Probe{
 				Handler: corev1.Handler{
@@ -304,6 +398,40 @@ func (c *GuardianComponent) container() []corev1.Container {
 	}
 }
 
+func (c *GuardianComponent) env() []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "GUARDIAN_PORT", Value: "9443"},
+		{Name: "GUARDIAN_LOGLEVEL", Value: "INFO"},
+		{Name: "GUARDIAN_VOLTRON_URL", Value: c.cfg.URL},
+		{Name: "GUARDIAN_METRICS_PORT", Value: strconv.Itoa(GuardianMetricsPort)},
+		{Name: "GUARDIAN_STATUS_REPORT_INTERVAL", Value: guardianStatusReportInterval},
+	}
+
+	env = append(env, TunnelKeepAliveEnv("GUARDIAN_", c.cfg.TunnelKeepAlive)...)
+	if backoff := c.cfg.ReconnectBackoff; backoff != nil {
+		if backoff.InitialInterval != nil {
+			env = append(env, corev1.EnvVar{Name: "GUARDIAN_TUNNEL_RECONNECT_INITIAL_INTERVAL", Value: backoff.InitialInterval.Duration.String()})
+		}
+		if backoff.MaxInterval != nil {
+			env = append(env, corev1.EnvVar{Name: "GUARDIAN_TUNNEL_RECONNECT_MAX_INTERVAL", Value: backoff.MaxInterval.Duration.String()})
+		}
+	}
+
+	if c.cfg.Proxy != nil {
+		// In-cluster services and the Kubernetes API server must not be reached through the proxy.
+		noProxy := append([]string{"$(KUBERNETES_SERVICE_HOST)", ".svc", ".svc." + c.cfg.ClusterDomain}, c.cfg.Proxy.NoProxy...)
+		env = append(env,
+			corev1.EnvVar{Name: "HTTPS_PROXY", Value: c.cfg.Proxy.HTTPSProxy},
+			corev1.EnvVar{Name: "NO_PROXY", Value: strings.Join(noProxy, ",")},
+		)
+	}
+	if c.cfg.ProxyCASecret != nil {
+		// Trust the proxy CA in addition to the CAs of the image.
+		env = append(env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: "/etc/ssl/certs:/certs/proxy"})
+	}
+	return env
+}
+
 func (c *GuardianComponent) volumeMounts() []corev1.VolumeMount {
 	mounts := []corev1.VolumeMount{
 		{
@@ -320,6 +448,9 @@ func (c *GuardianComponent) volumeMounts() []corev1.VolumeMount {
 	if c.cfg.PrometheusCertSecret != nil {
 		mounts = append(mounts, corev1.VolumeMount{Name: PrometheusTLSSecretName, MountPath: "/certs/prometheus", ReadOnly: true})
 	}
+	if c.cfg.ProxyCASecret != nil {
+		mounts = append(mounts, corev1.VolumeMount{Name: GuardianProxyCASecretName, MountPath: "/certs/proxy", ReadOnly: true})
+	}
 
 	return mounts
 }
@@ -332,5 +463,8 @@ func (c *GuardianComponent) annotations() map[string]string {
 	if c.cfg.PrometheusCertSecret != nil {
 		annotations[prometheusTLSHashAnnotation] = rmeta.AnnotationHash(c.cfg.PrometheusCertSecret.Data)
 	}
+	if c.cfg.ProxyCASecret != nil {
+		annotations[guardianProxyCAHashAnnotation] = rmeta.AnnotationHash(c.cfg.ProxyCASecret.Data)
+	}
 	return annotations
 }
@@ -15,26 +15,31 @@
 package render_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/render"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/podaffinity"
 	rtest "github.com/tigera/operator/pkg/render/common/test"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 var _ = Describe("Rendering tests", func() {
 	var g render.Component
 	var resources []client.Object
 
-	var renderGuardian = func(i operatorv1.InstallationSpec) {
+	var renderGuardian = func(i operatorv1.InstallationSpec, mods ...func(*render.GuardianConfiguration)) {
 		addr := "127.0.0.1:1234"
 		secret := &corev1.Secret{
 			TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
@@ -70,6 +75,10 @@ var _ = Describe("Rendering tests", func() {
 			Installation:        &i,
 			TunnelSecret:        secret,
 			PacketCaptureSecret: packetCaptureSecret,
+			ClusterDomain:       dns.DefaultClusterDomain,
+		}
+		for _, mod := range mods {
+			mod(cfg)
 		}
 		g = render.Guardian(cfg)
 		Expect(g.ResolveImages(nil)).To(BeNil())
@@ -110,6 +119,12 @@ var _ = Describe("Rendering tests", func() {
 
 		deployment := rtest.GetResource(resources, render.GuardianDeploymentName, render.GuardianNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
 		Expect(deployment.Spec.Template.Spec.Containers[0].Image).Should(Equal("my-reg/tigera/guardian:" + components.ComponentGuardian.Version))
+		rtest.ExpectEnv(deployment.Spec.Template.Spec.Containers[0].Env, "GUARDIAN_METRICS_PORT", "9094")
+		rtest.ExpectEnv(deployment.Spec.Template.Spec.Containers[0].Env, "GUARDIAN_STATUS_REPORT_INTERVAL", "30s")
+
+		svc := rtest.GetResource(resources, render.GuardianServiceName, render.GuardianNamespace, "", "", "").(*corev1.Service)
+		Expect(svc.Spec.Ports[0].Name).To(Equal("metrics"))
+		Expect(svc.Spec.Ports[0].Port).To(Equal(int32(render.GuardianMetricsPort)))
 	})
 
 	It("should render controlPlaneTolerations", func() {
@@ -124,4 +139,70 @@ var _ = Describe("Rendering tests", func() {
 		deployment := rtest.GetResource(resources, render.GuardianDeploymentName, render.GuardianNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
 		Expect(deployment.Spec.Template.Spec.Tolerations).Should(ContainElements(t, rmeta.TolerateCriticalAddonsOnly, rmeta.TolerateMaster))
 	})
+
+	It("should connect through the configured proxy", func() {
+		renderGuardian(operatorv1.InstallationSpec{Registry: "my-reg/"}, func(cfg *render.GuardianConfiguration) {
+			cfg.Proxy = &operatorv1.GuardianProxy{HTTPSProxy: "http://proxy.example.com:3128", NoProxy: []string{"10.0.0.0/8"}}
+			cfg.ProxyCASecret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-proxy-ca", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{render.GuardianProxyCAKey: []byte("ca")},
+			}
+		})
+
+		secret := rtest.GetResource(resources, render.GuardianProxyCASecretName, render.GuardianNamespace, "", "v1", "Secret").(*corev1.Secret)
+		Expect(secret.Data).To(Equal(map[string][]byte{render.GuardianProxyCAKey: []byte("ca")}))
+
+		deployment := rtest.GetResource(resources, render.GuardianDeploymentName, render.GuardianNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		container := deployment.Spec.Template.Spec.Containers[0]
+		rtest.ExpectEnv(container.Env, "HTTPS_PROXY", "http://proxy.example.com:3128")
+		rtest.ExpectEnv(container.Env, "NO_PROXY", "$(KUBERNETES_SERVICE_HOST),.svc,.svc.cluster.local,10.0.0.0/8")
+		rtest.ExpectEnv(container.Env, "SSL_CERT_DIR", "/etc/ssl/certs:/certs/proxy")
+		Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: render.GuardianProxyCASecretName, MountPath: "/certs/proxy", ReadOnly: true}))
+	})
+
+	It("should render the tunnel keepalive and reconnect backoff settings", func() {
+		renderGuardian(operatorv1.InstallationSpec{Registry: "my-reg/"}, func(cfg *render.GuardianConfiguration) {
+			cfg.TunnelKeepAlive = &operatorv1.TunnelKeepAlive{
+				Interval: &metav1.Duration{Duration: 15 * time.Second},
+				Timeout:  &metav1.Duration{Duration: time.Minute},
+			}
+			cfg.ReconnectBackoff = &operatorv1.TunnelReconnectBackoff{MaxInterval: &metav1.Duration{Duration: 5 * time.Minute}}
+		})
+
+		deployment := rtest.GetResource(resources, render.GuardianDeploymentName, render.GuardianNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		rtest.ExpectEnv(env, "GUARDIAN_TUNNEL_KEEPALIVE_INTERVAL", "15s")
+		rtest.ExpectEnv(env, "GUARDIAN_TUNNEL_KEEPALIVE_TIMEOUT", "1m0s")
+		rtest.ExpectEnv(env, "GUARDIAN_TUNNEL_RECONNECT_MAX_INTERVAL", "5m0s")
+		for _, e := range env {
+			Expect(e.Name).NotTo(Equal("GUARDIAN_TUNNEL_TCP_KEEPALIVE_PERIOD"))
+			Expect(e.Name).NotTo(Equal("GUARDIAN_TUNNEL_RECONNECT_INITIAL_INTERVAL"))
+		}
+	})
+
+	It("should apply the guardian deployment overrides", func() {
+		var replicas int32 = 2
+		t := corev1.Toleration{Key: "edge", Operator: corev1.TolerationOpExists}
+		guardianResources := corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+		}
+		renderGuardian(operatorv1.InstallationSpec{
+			ControlPlaneNodeSelector: map[string]string{"control-plane": "true"},
+			ControlPlaneTolerations:  []corev1.Toleration{{Key: "foo", Operator: corev1.TolerationOpExists}},
+		}, func(cfg *render.GuardianConfiguration) {
+			cfg.Deployment = &operatorv1.GuardianDeployment{
+				Replicas:     &replicas,
+				Resources:    &guardianResources,
+				NodeSelector: map[string]string{"edge": "true"},
+				Tolerations:  []corev1.Toleration{t},
+			}
+		})
+
+		deployment := rtest.GetResource(resources, render.GuardianDeploymentName, render.GuardianNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(*deployment.Spec.Replicas).To(Equal(replicas))
+		Expect(deployment.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"edge": "true"}))
+		Expect(deployment.Spec.Template.Spec.Tolerations).To(ConsistOf(t, rmeta.TolerateCriticalAddonsOnly, rmeta.TolerateMaster))
+		Expect(deployment.Spec.Template.Spec.Affinity).To(Equal(podaffinity.NewPodAntiAffinity(render.GuardianName, render.GuardianNamespace)))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Resources).To(Equal(guardianResources))
+	})
 })
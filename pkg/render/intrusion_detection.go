@@ -29,11 +29,13 @@ import (
 
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	rkibana "github.com/tigera/operator/pkg/render/common/kibana"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
+	"github.com/tigera/operator/pkg/render/common/provider"
 	"github.com/tigera/operator/pkg/render/common/secret"
 	"github.com/tigera/operator/pkg/url"
 )
@@ -63,12 +65,14 @@ type IntrusionDetectionConfiguration struct {
 	Installation             *operatorv1.InstallationSpec
 	ESClusterConfig          *relasticsearch.ClusterConfig
 	PullSecrets              []*corev1.Secret
-	Openshift                bool
 	ClusterDomain            string
 	ESLicenseType            ElasticsearchLicenseType
 	ManagedCluster           bool
 	HasNoLicense             bool
 	ManagerInternalTLSSecret *corev1.Secret
+	// KubernetesVersion is the version of the cluster. The PodSecurityPolicies are only rendered when the cluster
+	// supports them, which is assumed when the version is unknown.
+	KubernetesVersion *common.VersionInfo
 }
 
 type intrusionDetectionComponent struct {
@@ -129,7 +133,7 @@ func (c *intrusionDetectionComponent) Objects() ([]client.Object, []client.Objec
 
 	objs = append(objs, c.globalAlertTemplates()...)
 
-	if !c.cfg.Openshift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() && c.cfg.KubernetesVersion.ProvidesPodSecurityPolicy() {
 		objs = append(objs,
 			c.intrusionDetectionPodSecurityPolicy(),
 			c.intrusionDetectionPSPClusterRole(),
@@ -499,9 +503,9 @@ func (c *intrusionDetectionComponent) intrusionDetectionControllerContainer() co
 			corev1.EnvVar{Name: "IDS_ENABLE_EVENT_FORWARDING", Value: "true"},
 		)
 		volumeMounts = append(volumeMounts, syslogEventsForwardingVolumeMount())
-		// On OpenShift, if we need the volume mount to hostpath volume for syslog forwarding,
-		// then ID controller needs privileged access to write event logs to that volume
-		if c.cfg.Openshift {
+		// If we need the volume mount to hostpath volume for syslog forwarding, then ID controller
+		// may need privileged access to write event logs to that volume
+		if provider.For(c.cfg.Installation.KubernetesProvider).PrivilegedHostPathWriters() {
 			privileged = true
 		}
 	}
@@ -53,7 +53,6 @@ var _ = Describe("Intrusion Detection rendering tests", func() {
 	})
 
 	It("should render all resources for a default configuration", func() {
-		cfg.Openshift = notOpenshift
 		cfg.ManagerInternalTLSSecret = &testutils.InternalManagerTLSSecret
 		component := render.IntrusionDetection(cfg)
 		resources, _ := component.Objects()
@@ -133,7 +132,6 @@ var _ = Describe("Intrusion Detection rendering tests", func() {
 				},
 			},
 		}
-		cfg.Openshift = notOpenshift
 
 		component := render.IntrusionDetection(cfg)
 		resources, _ := component.Objects()
@@ -208,7 +206,6 @@ var _ = Describe("Intrusion Detection rendering tests", func() {
 	})
 
 	It("should not render intrusion-detection-es-job-installer and should disable GlobalAlert controller when cluster is managed", func() {
-		cfg.Openshift = notOpenshift
 		cfg.ManagedCluster = managedCluster
 
 		component := render.IntrusionDetection(cfg)
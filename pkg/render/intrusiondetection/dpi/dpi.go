@@ -26,6 +26,7 @@ import (
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	"github.com/tigera/operator/pkg/render/common/meta"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/provider"
 	"github.com/tigera/operator/pkg/render/common/secret"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -52,7 +53,6 @@ type DPIConfig struct {
 	TyphaTLSSecret     *corev1.Secret
 	TyphaCAConfigMap   *corev1.ConfigMap
 	PullSecrets        []*corev1.Secret
-	Openshift          bool
 	HasNoLicense       bool
 	HasNoDPIResource   bool
 	ESSecrets          []*corev1.Secret
@@ -170,11 +170,8 @@ func (d *dpiComponent) dpiDaemonset() *appsv1.DaemonSet {
 }
 
 func (d *dpiComponent) dpiContainer() corev1.Container {
-	privileged := false
-	// On OpenShift Snort needs privileged access to access host network
-	if d.cfg.Openshift {
-		privileged = true
-	}
+	// Snort may need privileged access to access host network
+	privileged := provider.For(d.cfg.Installation.KubernetesProvider).PrivilegedPacketInspection()
 
 	dpiContainer := corev1.Container{
 		Name:         DeepPacketInspectionName,
@@ -364,7 +361,7 @@ func (d *dpiComponent) dpiClusterRole() *rbacv1.ClusterRole {
 			},
 		},
 	}
-	if d.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+	if provider.For(d.cfg.Installation.KubernetesProvider).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		role.Rules = append(role.Rules, rbacv1.PolicyRule{
 			APIGroups:     []string{"policy"},
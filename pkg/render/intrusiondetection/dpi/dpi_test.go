@@ -172,7 +172,6 @@ var _ = Describe("DPI rendering tests", func() {
 			TyphaTLSSecret:     typhaTLSSecret,
 			TyphaCAConfigMap:   typhaCAConfigMap,
 			PullSecrets:        pullSecrets,
-			Openshift:          false,
 			HasNoLicense:       false,
 			HasNoDPIResource:   false,
 			ESClusterConfig:    esConfigMap,
@@ -227,12 +226,11 @@ var _ = Describe("DPI rendering tests", func() {
 
 		component := dpi.DPI(&dpi.DPIConfig{
 			IntrusionDetection: ids2,
-			Installation:       &operatorv1.InstallationSpec{Registry: "testregistry.com/"},
+			Installation:       &operatorv1.InstallationSpec{Registry: "testregistry.com/", KubernetesProvider: operatorv1.ProviderOpenShift},
 			NodeTLSSecret:      nodeTLSSecret,
 			TyphaTLSSecret:     typhaTLSSecret,
 			TyphaCAConfigMap:   typhaCAConfigMap,
 			PullSecrets:        pullSecrets,
-			Openshift:          true,
 			HasNoLicense:       false,
 			HasNoDPIResource:   false,
 			ESClusterConfig:    esConfigMap,
@@ -278,7 +276,6 @@ var _ = Describe("DPI rendering tests", func() {
 			TyphaTLSSecret:     typhaTLSSecret,
 			TyphaCAConfigMap:   typhaCAConfigMap,
 			PullSecrets:        pullSecrets,
-			Openshift:          false,
 			HasNoLicense:       true,
 			HasNoDPIResource:   false,
 			ESClusterConfig:    esConfigMap,
@@ -316,7 +313,6 @@ var _ = Describe("DPI rendering tests", func() {
 			TyphaTLSSecret:     nil,
 			TyphaCAConfigMap:   nil,
 			PullSecrets:        pullSecrets,
-			Openshift:          false,
 			HasNoLicense:       false,
 			HasNoDPIResource:   true,
 			ESClusterConfig:    esConfigMap,
@@ -16,9 +16,11 @@ package kubecontrollers
 
 import (
 	"strings"
+	"time"
 
 	"github.com/tigera/operator/pkg/render"
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
+	"github.com/tigera/operator/pkg/render/common/provider"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/k8sapi"
@@ -73,6 +76,14 @@ type KubeControllersConfiguration struct {
 	ClusterDomain           string
 	MetricsPort             int
 
+	// KubernetesVersion is the version of the cluster. The PodSecurityPolicy is only rendered when the cluster
+	// supports it, which is assumed when the version is unknown.
+	KubernetesVersion *common.VersionInfo
+
+	// Configuration is the default KubeControllersConfiguration, if it exists. When the installation manages
+	// it, the settings that the installation doesn't cover are kept from it.
+	Configuration *crdv1.KubeControllersConfiguration
+
 	// Secrets - provided by the caller. Used to generate secrets in the destination
 	// namespace to be returned by the rendered. Expected that the calling code
 	// take care to pass the same secret on each reconcile where possible.
@@ -106,6 +117,12 @@ func NewCalicoKubeControllers(cfg *KubeControllersConfiguration) *kubeController
 		)
 		enabledControllers = append(enabledControllers, "service", "federatedservices")
 	}
+	if kc := cfg.Installation.KubeControllers; kc != nil && len(kc.EnabledControllers) > 0 {
+		enabledControllers = nil
+		for _, ctrl := range kc.EnabledControllers {
+			enabledControllers = append(enabledControllers, strings.ToLower(string(ctrl)))
+		}
+	}
 
 	return &kubeControllersComponent{
 		cfg:                              cfg,
@@ -116,6 +133,7 @@ func NewCalicoKubeControllers(cfg *KubeControllersConfiguration) *kubeController
 		kubeControllerConfigName:         "default",
 		kubeControllerMetricsName:        KubeControllerMetrics,
 		renderManagerInternalSecret:      cfg.ManagerInternalSecret != nil,
+		renderConfiguration:              cfg.Installation.KubeControllers != nil,
 		kubeControllersRules:             kubeControllerRolePolicyRules,
 		enabledControllers:               enabledControllers,
 	}
@@ -186,6 +204,7 @@ type kubeControllersComponent struct {
 	renderElasticsearchSecret          bool
 	renderManagerInternalSecret        bool
 	renderKubeControllersGatewaySecret bool
+	renderConfiguration                bool
 
 	kubeControllersRules []rbacv1.PolicyRule
 
@@ -232,10 +251,14 @@ func (c *kubeControllersComponent) Objects() ([]client.Object, []client.Object)
 			secret.CopyToNamespace(common.CalicoNamespace, c.cfg.KubeControllersGatewaySecret)...)...)
 	}
 
-	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() && c.cfg.KubernetesVersion.ProvidesPodSecurityPolicy() {
 		objectsToCreate = append(objectsToCreate, c.controllersPodSecurityPolicy())
 	}
 
+	if c.renderConfiguration {
+		objectsToCreate = append(objectsToCreate, c.controllersConfiguration())
+	}
+
 	if c.cfg.MetricsPort != 0 {
 		objectsToCreate = append(objectsToCreate, c.prometheusService())
 	} else {
@@ -296,7 +319,7 @@ func kubeControllersRoleCommonRules(cfg *KubeControllersConfiguration, kubeContr
 		},
 	}
 
-	if cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+	if provider.For(cfg.Installation.KubernetesProvider).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		rules = append(rules, rbacv1.PolicyRule{
 			APIGroups:     []string{"policy"},
@@ -518,6 +541,56 @@ func (c *kubeControllersComponent) controllersDeployment() *appsv1.Deployment {
 	return &d
 }
 
+// controllersConfiguration renders the KubeControllersConfiguration from the installation, so that changes made to it
+// by hand are reverted. The enabled controllers are set through the ENABLED_CONTROLLERS variable of the deployment,
+// which takes precedence over the configuration.
+func (c *kubeControllersComponent) controllersConfiguration() *crdv1.KubeControllersConfiguration {
+	var spec crdv1.KubeControllersConfigurationSpec
+	if c.cfg.Configuration != nil {
+		c.cfg.Configuration.Spec.DeepCopyInto(&spec)
+	}
+
+	spec.Controllers.Node = nil
+	for _, ctrl := range c.enabledControllers {
+		if ctrl == "node" {
+			spec.Controllers.Node = nodeControllerConfig(c.cfg.Installation.KubeControllers.Node)
+		}
+	}
+
+	return &crdv1.KubeControllersConfiguration{
+		TypeMeta:   metav1.TypeMeta{Kind: crdv1.KindKubeControllersConfiguration, APIVersion: "crd.projectcalico.org/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: c.kubeControllerConfigName},
+		Spec:       spec,
+	}
+}
+
+// nodeControllerConfig returns the configuration of the node controller, with the defaults of kube-controllers for
+// the settings that are not set.
+func nodeControllerConfig(node *operatorv1.KubeControllersNode) *crdv1.NodeControllerConfig {
+	cfg := &crdv1.NodeControllerConfig{
+		ReconcilerPeriod: &metav1.Duration{Duration: 5 * time.Minute},
+		SyncLabels:       string(operatorv1.NodeSyncLabelsEnabled),
+		HostEndpoint:     &crdv1.AutoHostEndpointConfig{AutoCreate: string(operatorv1.HostEndpointAutoCreateDisabled)},
+		LeakGracePeriod:  &metav1.Duration{Duration: 15 * time.Minute},
+	}
+	if node == nil {
+		return cfg
+	}
+	if node.ReconcilerPeriod != nil {
+		cfg.ReconcilerPeriod = node.ReconcilerPeriod.DeepCopy()
+	}
+	if node.SyncLabels != nil {
+		cfg.SyncLabels = string(*node.SyncLabels)
+	}
+	if node.HostEndpointAutoCreate != nil {
+		cfg.HostEndpoint.AutoCreate = string(*node.HostEndpointAutoCreate)
+	}
+	if node.LeakGracePeriod != nil {
+		cfg.LeakGracePeriod = node.LeakGracePeriod.DeepCopy()
+	}
+	return cfg
+}
+
 func (c *kubeControllersComponent) controllersRoleBinding() *rbacv1.ClusterRoleBinding {
 	return &rbacv1.ClusterRoleBinding{
 		TypeMeta: metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
@@ -16,6 +16,7 @@ package kubecontrollers_test
 
 import (
 	"fmt"
+	"time"
 
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	"github.com/tigera/operator/pkg/render/kubecontrollers"
@@ -30,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/k8sapi"
 	"github.com/tigera/operator/pkg/render"
@@ -39,6 +41,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("kube-controllers rendering tests", func() {
@@ -593,4 +596,56 @@ var _ = Describe("kube-controllers rendering tests", func() {
 		deployment := depResource.(*appsv1.Deployment)
 		rtest.ExpectNoK8sServiceEpEnvVars(deployment.Spec.Template.Spec)
 	})
+
+	It("should not render the KubeControllersConfiguration by default", func() {
+		component := kubecontrollers.NewCalicoKubeControllers(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+		Expect(rtest.GetResource(resources, "default", "", "crd.projectcalico.org", "v1", "KubeControllersConfiguration")).To(BeNil())
+	})
+
+	It("should render the KubeControllersConfiguration from the installation", func() {
+		metricsPort := 9095
+		cfg.Configuration = &crdv1.KubeControllersConfiguration{
+			Spec: crdv1.KubeControllersConfigurationSpec{
+				LogSeverityScreen:     "Debug",
+				PrometheusMetricsPort: &metricsPort,
+				Controllers: crdv1.ControllersConfig{
+					Node: &crdv1.NodeControllerConfig{SyncLabels: "Disabled"},
+				},
+			},
+		}
+		autoCreate := operatorv1.HostEndpointAutoCreateEnabled
+		instance.Variant = operatorv1.TigeraSecureEnterprise
+		instance.KubeControllers = &operatorv1.KubeControllers{
+			EnabledControllers: []operatorv1.KubeControllerType{operatorv1.KubeControllerNode, operatorv1.KubeControllerFederatedServices},
+			Node: &operatorv1.KubeControllersNode{
+				ReconcilerPeriod:       &metav1.Duration{Duration: time.Minute},
+				HostEndpointAutoCreate: &autoCreate,
+			},
+		}
+
+		component := kubecontrollers.NewCalicoKubeControllers(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		deployment := rtest.GetResource(resources, kubecontrollers.KubeController, common.CalicoNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		rtest.ExpectEnv(deployment.Spec.Template.Spec.Containers[0].Env, "ENABLED_CONTROLLERS", "node,federatedservices")
+
+		kcc := rtest.GetResource(resources, "default", "", "crd.projectcalico.org", "v1", "KubeControllersConfiguration").(*crdv1.KubeControllersConfiguration)
+		Expect(kcc.Spec.LogSeverityScreen).To(Equal("Debug"))
+		Expect(*kcc.Spec.PrometheusMetricsPort).To(Equal(9095))
+		Expect(kcc.Spec.Controllers.Node).To(Equal(&crdv1.NodeControllerConfig{
+			ReconcilerPeriod: &metav1.Duration{Duration: time.Minute},
+			SyncLabels:       "Enabled",
+			HostEndpoint:     &crdv1.AutoHostEndpointConfig{AutoCreate: "Enabled"},
+			LeakGracePeriod:  &metav1.Duration{Duration: 15 * time.Minute},
+		}))
+
+		// The configuration of the node controller is removed when it is not enabled.
+		instance.KubeControllers.EnabledControllers = []operatorv1.KubeControllerType{operatorv1.KubeControllerService}
+		resources, _ = kubecontrollers.NewCalicoKubeControllers(&cfg).Objects()
+		kcc = rtest.GetResource(resources, "default", "", "crd.projectcalico.org", "v1", "KubeControllersConfiguration").(*crdv1.KubeControllersConfiguration)
+		Expect(kcc.Spec.Controllers.Node).To(BeNil())
+	})
 })
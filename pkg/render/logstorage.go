@@ -15,6 +15,7 @@
 package render
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -47,6 +48,7 @@ import (
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
 	"github.com/tigera/operator/pkg/render/common/podaffinity"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
+	"github.com/tigera/operator/pkg/render/common/provider"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
 
@@ -207,6 +209,13 @@ type ElasticsearchConfiguration struct {
 	ClusterDomain               string
 	DexCfg                      DexRelyingPartyConfig
 	ElasticLicenseType          ElasticsearchLicenseType
+
+	// VolumeExpansion is set when the storage class of the Elasticsearch volumes allows their expansion.
+	VolumeExpansion bool
+
+	// KubernetesVersion is the version of the cluster. The PodSecurityPolicies are only rendered when the cluster
+	// supports them, which is assumed when the version is unknown.
+	KubernetesVersion *common.VersionInfo
 }
 
 type elasticsearchComponent struct {
@@ -299,13 +308,12 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 			es.eckOperatorServiceAccount(),
 		)
 		// This is needed for the operator to be able to set privileged mode for pods.
-		// https://docs.docker.com/ee/ucp/authorization/#secure-kubernetes-defaults
-		if es.cfg.Provider == operatorv1.ProviderDockerEE {
+		if provider.For(es.cfg.Provider).ClusterAdminForPrivilegedPods() {
 			toCreate = append(toCreate, es.eckOperatorClusterAdminClusterRoleBinding())
 		}
 
-		// Apply the pod security policies for all providers except OpenShift
-		if es.cfg.Provider != operatorv1.ProviderOpenShift {
+		// Apply the pod security policies unless the provider enforces its own policies.
+		if es.podSecurityPolicies() {
 			toCreate = append(toCreate,
 				es.eckOperatorPodSecurityPolicy(),
 				es.elasticsearchClusterRoleBinding(),
@@ -359,8 +367,8 @@ func (es *elasticsearchComponent) Objects() ([]client.Object, []client.Object) {
 			toCreate = append(toCreate, secret.ToRuntimeObjects(secret.CopyToNamespace(ElasticsearchNamespace, es.cfg.CuratorSecrets...)...)...)
 			toCreate = append(toCreate, es.esCuratorServiceAccount())
 
-			// If the provider is not OpenShift apply the pod security policy for the curator.
-			if es.cfg.Provider != operatorv1.ProviderOpenShift {
+			// Apply the pod security policy for the curator unless the provider enforces its own policies.
+			if es.podSecurityPolicies() {
 				toCreate = append(toCreate,
 					es.curatorClusterRole(),
 					es.curatorClusterRoleBinding(),
@@ -405,6 +413,12 @@ func (es *elasticsearchComponent) Ready() bool {
 	return true
 }
 
+// podSecurityPolicies returns true if the PodSecurityPolicies are rendered, unless the provider enforces its own
+// policies or the cluster doesn't support them.
+func (es elasticsearchComponent) podSecurityPolicies() bool {
+	return provider.For(es.cfg.Provider).PodSecurityPolicies() && es.cfg.KubernetesVersion.ProvidesPodSecurityPolicy()
+}
+
 func (es elasticsearchComponent) elasticsearchExternalService() *corev1.Service {
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
@@ -428,6 +442,12 @@ func (es elasticsearchComponent) elasticsearchServiceAccount() *corev1.ServiceAc
 	}
 }
 
+// ElasticsearchStorage returns the storage requested by the volume of each Elasticsearch node of the LogStorage.
+func ElasticsearchStorage(ls *operatorv1.LogStorage) resource.Quantity {
+	pvcTemplate := elasticsearchComponent{cfg: &ElasticsearchConfiguration{LogStorage: ls}}.pvcTemplate()
+	return *pvcTemplate.Spec.Resources.Requests.Storage()
+}
+
 // generate the PVC required for the Elasticsearch nodes
 func (es elasticsearchComponent) pvcTemplate() corev1.PersistentVolumeClaim {
 	pvcTemplate := corev1.PersistentVolumeClaim{
@@ -496,11 +516,10 @@ func (es elasticsearchComponent) podTemplate() corev1.PodTemplateSpec {
 		VolumeMounts: volumeMounts,
 	}
 
-	// For OpenShift, set the user to run as non-root specifically. This prevents issues with the elasticsearch
-	// image which requires that root users have permissions to run CHROOT which is not given in OpenShift.
-	if es.cfg.Provider == operatorv1.ProviderOpenShift {
+	// Some providers require a specific user, e.g. a non-root one on OpenShift.
+	if user := provider.For(es.cfg.Provider).ElasticsearchRunAsUser(); user != nil {
 		esContainer.SecurityContext = &corev1.SecurityContext{
-			RunAsUser: ptr.Int64ToPtr(1000),
+			RunAsUser: user,
 		}
 	}
 
@@ -854,10 +873,11 @@ func (es elasticsearchComponent) nodeSets() []esv1.NodeSet {
 		return nil
 	}
 
+	name := es.nodeSetBaseName(pvcTemplate)
 	var nodeSets []esv1.NodeSet
 	if nodeConfig.NodeSets == nil || len(nodeConfig.NodeSets) < 1 {
 		nodeSet := es.nodeSetTemplate(pvcTemplate)
-		nodeSet.Name = nodeSetName(pvcTemplate)
+		nodeSet.Name = name
 		nodeSet.Count = int32(nodeConfig.Count)
 		nodeSet.PodTemplate = es.podTemplate()
 
@@ -881,7 +901,7 @@ func (es elasticsearchComponent) nodeSets() []esv1.NodeSet {
 
 			nodeSet := es.nodeSetTemplate(pvcTemplate)
 			// Each NodeSet needs a unique name, so just add the index as a suffix
-			nodeSet.Name = fmt.Sprintf("%s-%d", nodeSetName(pvcTemplate), i)
+			nodeSet.Name = fmt.Sprintf("%s-%d", name, i)
 			nodeSet.Count = int32(numNodes)
 
 			podTemplate := es.podTemplate()
@@ -995,6 +1015,49 @@ func nodeSetName(pvcTemplate corev1.PersistentVolumeClaim) string {
 	return hex.EncodeToString(pvcTemplateHash.Sum(nil))
 }
 
+// nodeSetBaseName returns the name of the NodeSets, which is suffixed with their index when there are several. The
+// names of the current NodeSets are kept when their PVC template is unchanged, or when only its storage is increased
+// and the storage class allows volume expansion, in which case ECK expands the PVCs in place. Otherwise, the NodeSets
+// are renamed after the new PVC template, and ECK migrates the data to the new NodeSets before removing the old ones.
+func (es elasticsearchComponent) nodeSetBaseName(pvcTemplate corev1.PersistentVolumeClaim) string {
+	name := nodeSetName(pvcTemplate)
+	if es.cfg.Elasticsearch == nil || len(es.cfg.Elasticsearch.Spec.NodeSets) == 0 {
+		return name
+	}
+	current := es.cfg.Elasticsearch.Spec.NodeSets[0]
+	if len(current.VolumeClaimTemplates) != 1 || current.VolumeClaimTemplates[0].Name != pvcTemplate.Name ||
+		!bytes.Equal(pvcSpecWithoutStorage(current.VolumeClaimTemplates[0]), pvcSpecWithoutStorage(pvcTemplate)) {
+		return name
+	}
+
+	// Volumes can't be shrunk, which the LogStorage controller rejects, so the storage is either unchanged or
+	// increased.
+	if current.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().Cmp(*pvcTemplate.Spec.Resources.Requests.Storage()) < 0 && !es.cfg.VolumeExpansion {
+		return name
+	}
+	// The index suffix of the name, if any, is dropped. The names built from the PVC templates have no dashes.
+	return strings.SplitN(current.Name, "-", 2)[0]
+}
+
+// pvcSpecWithoutStorage returns the JSON of the spec of the PVC, without its storage requirements.
+func pvcSpecWithoutStorage(pvc corev1.PersistentVolumeClaim) []byte {
+	spec := pvc.Spec.DeepCopy()
+	delete(spec.Resources.Requests, corev1.ResourceStorage)
+	delete(spec.Resources.Limits, corev1.ResourceStorage)
+	if len(spec.Resources.Requests) == 0 {
+		spec.Resources.Requests = nil
+	}
+	if len(spec.Resources.Limits) == 0 {
+		spec.Resources.Limits = nil
+	}
+	b, err := json.Marshal(spec)
+	if err != nil {
+		log.V(5).Info("Failed to marshal the PVC template of an ElasticSearch NodeSet.", "err", err)
+		return nil
+	}
+	return b
+}
+
 func (es elasticsearchComponent) eckOperatorClusterRole() *rbacv1.ClusterRole {
 	rules := []rbacv1.PolicyRule{
 		{
@@ -1064,7 +1127,7 @@ func (es elasticsearchComponent) eckOperatorClusterRole() *rbacv1.ClusterRole {
 		},
 	}
 
-	if es.cfg.Provider != operatorv1.ProviderOpenShift {
+	if es.podSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		rules = append(rules, rbacv1.PolicyRule{
 			APIGroups:     []string{"policy"},
@@ -1270,6 +1333,12 @@ func (es elasticsearchComponent) kibanaCR() *kbv1.Kibana {
 		},
 	}
 
+	if usageReportingDisabled(es.cfg.Installation) {
+		config["telemetry.enabled"] = false
+		config["telemetry.optIn"] = false
+		config["telemetry.allowChangingOptInStatus"] = false
+	}
+
 	if es.supportsOIDC() {
 		config["xpack.security.authc.providers"] = []string{"oidc", "basic"}
 		config["xpack.security.authc.oidc.realm"] = "oidc1"
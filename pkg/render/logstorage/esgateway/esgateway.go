@@ -75,6 +75,7 @@ func EsGateway(c *Config) render.Component {
 		tlsAnnotations:  tlsAnnotations,
 		clusterDomain:   c.ClusterDomain,
 		esAdminUserName: c.EsAdminUserName,
+		spec:            c.ESGateway,
 	}
 }
 
@@ -87,6 +88,7 @@ type esGateway struct {
 	csrImage        string
 	esGatewayImage  string
 	esAdminUserName string
+	spec            *operatorv1.ESGatewaySpec
 }
 
 // Config contains all the config information needed to render the EsGateway component.
@@ -99,6 +101,8 @@ type Config struct {
 	EsInternalCertSecret       *corev1.Secret
 	ClusterDomain              string
 	EsAdminUserName            string
+	// ESGateway is the es-gateway configuration of the LogStorage, if any.
+	ESGateway *operatorv1.ESGatewaySpec
 }
 
 func (e *esGateway) ResolveImages(is *operatorv1.ImageSet) error {
@@ -203,6 +207,18 @@ func (e esGateway) esGatewayDeployment() *appsv1.Deployment {
 		}},
 	}
 
+	replicas := e.installation.ControlPlaneReplicas
+	resources := corev1.ResourceRequirements{}
+	if e.spec != nil {
+		if e.spec.Replicas != nil {
+			replicas = e.spec.Replicas
+		}
+		if e.spec.ResourceRequirements != nil {
+			resources = *e.spec.ResourceRequirements
+		}
+		envVars = append(envVars, relasticsearch.TLSEnvVars(e.spec.TLS)...)
+	}
+
 	certVolume := corev1.Volume{
 		Name: VolumeName,
 		VolumeSource: corev1.VolumeSource{
@@ -278,6 +294,7 @@ func (e esGateway) esGatewayDeployment() *appsv1.Deployment {
 					Image:        e.esGatewayImage,
 					Env:          envVars,
 					VolumeMounts: volumeMounts,
+					Resources:    resources,
 					ReadinessProbe: &corev1.Probe{
 						Handler: corev1.Handler{
 							HTTPGet: &corev1.HTTPGetAction{
@@ -294,7 +311,7 @@ func (e esGateway) esGatewayDeployment() *appsv1.Deployment {
 		},
 	}
 
-	if e.installation.ControlPlaneReplicas != nil && *e.installation.ControlPlaneReplicas > 1 {
+	if replicas != nil && *replicas > 1 {
 		podTemplate.Spec.Affinity = podaffinity.NewPodAntiAffinity(DeploymentName, render.ElasticsearchNamespace)
 	}
 
@@ -313,7 +330,7 @@ func (e esGateway) esGatewayDeployment() *appsv1.Deployment {
 			},
 			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": DeploymentName}},
 			Template: *podTemplate,
-			Replicas: e.installation.ControlPlaneReplicas,
+			Replicas: replicas,
 		},
 	}
 }
@@ -192,6 +192,18 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 					"cluster.max_shards_per_node": 10000,
 				}))
 			})
+			It("should disable the Kibana telemetry when usage reporting is disabled", func() {
+				createResources, _ := render.LogStorage(cfg).Objects()
+				kb := rtest.GetResource(createResources, render.KibanaName, render.KibanaNamespace, "kibana.k8s.elastic.co", "v1", "Kibana").(*kbv1.Kibana)
+				Expect(kb.Spec.Config.Data).NotTo(HaveKey("telemetry.enabled"))
+
+				disabled := operatorv1.UsageReportingDisabled
+				cfg.Installation.UsageReporting = &disabled
+				createResources, _ = render.LogStorage(cfg).Objects()
+				kb = rtest.GetResource(createResources, render.KibanaName, render.KibanaNamespace, "kibana.k8s.elastic.co", "v1", "Kibana").(*kbv1.Kibana)
+				Expect(kb.Spec.Config.Data).To(HaveKeyWithValue("telemetry.enabled", false))
+				Expect(kb.Spec.Config.Data).To(HaveKeyWithValue("telemetry.optIn", false))
+			})
 			It("should render an elasticsearchComponent and delete the Elasticsearch and Kibana ExternalService", func() {
 				expectedCreateResources := []resourceTestObj{
 					{render.ECKOperatorNamespace, "", &corev1.Namespace{}, nil},
@@ -437,6 +449,59 @@ var _ = Describe("Elasticsearch rendering tests", func() {
 				newNodeName := rtest.GetResource(updatedResources, "tigera-secure", "tigera-elasticsearch", "elasticsearch.k8s.elastic.co", "v1", "Elasticsearch").(*esv1.Elasticsearch).Spec.NodeSets[0].Name
 				Expect(newNodeName).NotTo(Equal(oldNodeSetName))
 			})
+
+			Context("Increasing the storage", func() {
+				var current *esv1.Elasticsearch
+
+				BeforeEach(func() {
+					cfg.LogStorage = &operatorv1.LogStorage{
+						ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+						Spec: operatorv1.LogStorageSpec{
+							Nodes: &operatorv1.Nodes{
+								Count:    2,
+								NodeSets: []operatorv1.NodeSet{{}, {}},
+								ResourceRequirements: &corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{"storage": resource.MustParse("10Gi")},
+								},
+							},
+						},
+					}
+					cfg.Elasticsearch = &esv1.Elasticsearch{}
+					createResources, _ := render.LogStorage(cfg).Objects()
+					// The rendered PVC templates share the fields of the LogStorage.
+					current = rtest.GetResource(createResources, "tigera-secure", "tigera-elasticsearch", "elasticsearch.k8s.elastic.co", "v1", "Elasticsearch").(*esv1.Elasticsearch).DeepCopy()
+					cfg.Elasticsearch = current.DeepCopy()
+					cfg.LogStorage.Spec.Nodes.ResourceRequirements.Requests["storage"] = resource.MustParse("20Gi")
+				})
+
+				nodeSets := func() []esv1.NodeSet {
+					resources, _ := render.LogStorage(cfg).Objects()
+					return rtest.GetResource(resources, "tigera-secure", "tigera-elasticsearch", "elasticsearch.k8s.elastic.co", "v1", "Elasticsearch").(*esv1.Elasticsearch).Spec.NodeSets
+				}
+
+				It("should expand the volumes of the current NodeSets when the storage class allows it", func() {
+					cfg.VolumeExpansion = true
+					expanded := nodeSets()
+					Expect(expanded).To(HaveLen(2))
+					Expect(expanded[0].Name).To(Equal(current.Spec.NodeSets[0].Name))
+					Expect(expanded[1].Name).To(Equal(current.Spec.NodeSets[1].Name))
+					Expect(expanded[0].VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().String()).To(Equal("20Gi"))
+
+					// The NodeSets keep their names once their volumes are expanded.
+					cfg.Elasticsearch.Spec.NodeSets = expanded
+					Expect(nodeSets()[0].Name).To(Equal(current.Spec.NodeSets[0].Name))
+				})
+
+				It("should replace the NodeSets when the storage class doesn't allow volume expansion", func() {
+					Expect(nodeSets()[0].Name).NotTo(Equal(current.Spec.NodeSets[0].Name))
+				})
+
+				It("should replace the NodeSets when the storage class changes", func() {
+					cfg.VolumeExpansion = true
+					cfg.LogStorage.Spec.StorageClassName = "other"
+					Expect(nodeSets()[0].Name).NotTo(Equal(current.Spec.NodeSets[0].Name))
+				})
+			})
 		})
 
 		It("should render DataNodeSelectors defined in the LogStorage CR", func() {
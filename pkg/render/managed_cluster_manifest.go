@@ -0,0 +1,148 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This renderer is responsible for the connection manifest of a managed cluster in a multicluster setup.
+package render
+
+import (
+	"bytes"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+const (
+	ManagedClusterManifestSecretPrefix = "tigera-managed-cluster-manifest-"
+	ManagedClusterManifestKey          = "manifest.yaml"
+
+	// ManagedClusterFingerprintAnnotation holds the fingerprint of the certificate Voltron accepts for a managed cluster.
+	ManagedClusterFingerprintAnnotation = "certs.tigera.io/active-fingerprint"
+
+	// ManagedClusterNextFingerprintAnnotation holds the fingerprint of the certificate signed by the new tunnel CA while
+	// the tunnel CA is being rotated. Voltron accepts it along with the active one, so that the managed cluster stays
+	// connected whether or not its regenerated manifest is applied. It becomes active once the rotation completes.
+	ManagedClusterNextFingerprintAnnotation = "certs.tigera.io/next-fingerprint"
+)
+
+// ManagedClusterManifestSecretName returns the name of the secret holding the connection manifest of the given managed
+// cluster.
+func ManagedClusterManifestSecretName(clusterName string) string {
+	return ManagedClusterManifestSecretPrefix + clusterName
+}
+
+// ManagedClusterManifestConfiguration contains all the config information needed to render the connection manifest of
+// a managed cluster.
+type ManagedClusterManifestConfiguration struct {
+	ClusterName string
+	// Namespace holds the manifest secret. This is the operator namespace, or the namespace of the tenant of the managed
+	// cluster.
+	Namespace             string
+	ManagementClusterAddr string
+	TunnelCACert          []byte
+	Certificate           []byte
+	Key                   []byte
+}
+
+// ManagedClusterManifest renders a secret in the configured namespace holding the manifest that connects a managed
+// cluster to this management cluster. The manifest contains the ManagementClusterConnection and the tunnel secret; the
+// operator of the managed cluster renders the guardian deployment and its RBAC from them once applied.
+func ManagedClusterManifest(cfg *ManagedClusterManifestConfiguration) (Component, error) {
+	manifest, err := managedClusterManifest(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &managedClusterManifestComponent{
+		cfg:      cfg,
+		manifest: manifest,
+	}, nil
+}
+
+type managedClusterManifestComponent struct {
+	cfg      *ManagedClusterManifestConfiguration
+	manifest []byte
+}
+
+func (c *managedClusterManifestComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	return nil
+}
+
+func (c *managedClusterManifestComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *managedClusterManifestComponent) Objects() ([]client.Object, []client.Object) {
+	return []client.Object{c.manifestSecret()}, nil
+}
+
+func (c *managedClusterManifestComponent) Ready() bool {
+	return true
+}
+
+// manifestSecret holds the manifest along with the certificate and key of the managed cluster, so that they can be
+// reused for as long as they are signed by the tunnel CA.
+func (c *managedClusterManifestComponent) manifestSecret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ManagedClusterManifestSecretName(c.cfg.ClusterName),
+			Namespace: c.cfg.Namespace,
+		},
+		Data: map[string][]byte{
+			ManagedClusterManifestKey:            c.manifest,
+			GuardianSecretManagedClusterCertName: c.cfg.Certificate,
+			GuardianSecretManagedClusterKeyName:  c.cfg.Key,
+		},
+	}
+}
+
+func managedClusterManifest(cfg *ManagedClusterManifestConfiguration) ([]byte, error) {
+	objs := []client.Object{
+		&operatorv1.ManagementClusterConnection{
+			TypeMeta:   metav1.TypeMeta{Kind: "ManagementClusterConnection", APIVersion: "operator.tigera.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec: operatorv1.ManagementClusterConnectionSpec{
+				ManagementClusterAddr: cfg.ManagementClusterAddr,
+			},
+		},
+		&corev1.Secret{
+			TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      GuardianSecretName,
+				Namespace: common.OperatorNamespace(),
+			},
+			Data: map[string][]byte{
+				GuardianSecretManagementClusterCertName: cfg.TunnelCACert,
+				GuardianSecretManagedClusterCertName:    cfg.Certificate,
+				GuardianSecretManagedClusterKeyName:     cfg.Key,
+			},
+		},
+	}
+
+	var manifest bytes.Buffer
+	for _, obj := range objs {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		manifest.WriteString("---\n")
+		manifest.Write(b)
+	}
+	return manifest.Bytes(), nil
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("Managed cluster manifest rendering tests", func() {
+	var caCert, caKey []byte
+
+	BeforeEach(func() {
+		// Any self-signed CA will do as tunnel CA.
+		ca := render.CreateDexTLSSecret("tigera-voltron")
+		caCert = ca.Data[corev1.TLSCertKey]
+		caKey = ca.Data[corev1.TLSPrivateKeyKey]
+	})
+
+	It("should create a managed cluster certificate signed by the tunnel CA", func() {
+		cert, key, err := render.CreateManagedClusterCertificate(caCert, caKey, "cluster-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key).NotTo(BeEmpty())
+		Expect(render.IsSignedBy(cert, caCert)).To(BeTrue())
+
+		block, _ := pem.Decode(cert)
+		Expect(block).NotTo(BeNil())
+		x509Cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(x509Cert.Subject.CommonName).To(Equal("cluster-a"))
+		Expect(x509Cert.ExtKeyUsage).To(ConsistOf(x509.ExtKeyUsageClientAuth))
+
+		otherCA := render.CreateDexTLSSecret("other-ca")
+		Expect(render.IsSignedBy(cert, otherCA.Data[corev1.TLSCertKey])).To(BeFalse())
+
+		fingerprint, err := render.ManagedClusterCertificateFingerprint(cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fingerprint).To(HaveLen(32))
+	})
+
+	DescribeTable("should accept tunnel CA keys in PKCS#8 and EC formats and certificate chains",
+		func(newKey func() (crypto.Signer, []byte), chained bool) {
+			priv, keyPem := newKey()
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "tigera-voltron"},
+				NotBefore:             time.Now(),
+				NotAfter:              time.Now().Add(time.Hour),
+				IsCA:                  true,
+				BasicConstraintsValid: true,
+				KeyUsage:              x509.KeyUsageCertSign,
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, priv.Public(), priv)
+			Expect(err).NotTo(HaveOccurred())
+			tunnelCACert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+			if chained {
+				tunnelCACert = append(tunnelCACert, caCert...)
+			}
+
+			cert, _, err := render.CreateManagedClusterCertificate(tunnelCACert, keyPem, "cluster-a")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(render.IsSignedBy(cert, tunnelCACert)).To(BeTrue())
+		},
+		Entry("SEC 1 EC key with EC parameters", func() (crypto.Signer, []byte) {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			der, err := x509.MarshalECPrivateKey(key)
+			Expect(err).NotTo(HaveOccurred())
+			params := pem.EncodeToMemory(&pem.Block{Type: "EC PARAMETERS", Bytes: []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}})
+			return key, append(params, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})...)
+		}, false),
+		Entry("PKCS#8 EC key", func() (crypto.Signer, []byte) {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			Expect(err).NotTo(HaveOccurred())
+			return key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		}, false),
+		Entry("PKCS#8 RSA key with a certificate chain", func() (crypto.Signer, []byte) {
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			Expect(err).NotTo(HaveOccurred())
+			return key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		}, true),
+	)
+
+	It("should render the manifest secret of a managed cluster", func() {
+		cert, key, err := render.CreateManagedClusterCertificate(caCert, caKey, "cluster-a")
+		Expect(err).NotTo(HaveOccurred())
+
+		component, err := render.ManagedClusterManifest(&render.ManagedClusterManifestConfiguration{
+			ClusterName:           "cluster-a",
+			Namespace:             common.OperatorNamespace(),
+			ManagementClusterAddr: "example.com:30449",
+			TunnelCACert:          caCert,
+			Certificate:           cert,
+			Key:                   key,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, toDelete := component.Objects()
+		Expect(toDelete).To(BeNil())
+		Expect(toCreate).To(HaveLen(1))
+
+		secretName := render.ManagedClusterManifestSecretName("cluster-a")
+		Expect(secretName).To(Equal("tigera-managed-cluster-manifest-cluster-a"))
+		rtest.ExpectResource(toCreate[0], secretName, common.OperatorNamespace(), "", "v1", "Secret")
+		secret := toCreate[0].(*corev1.Secret)
+		Expect(secret.Data[render.GuardianSecretManagedClusterCertName]).To(Equal(cert))
+		Expect(secret.Data[render.GuardianSecretManagedClusterKeyName]).To(Equal(key))
+
+		docs := strings.Split(strings.TrimPrefix(string(secret.Data[render.ManagedClusterManifestKey]), "---\n"), "---\n")
+		Expect(docs).To(HaveLen(2))
+
+		connection := &operatorv1.ManagementClusterConnection{}
+		Expect(yaml.Unmarshal([]byte(docs[0]), connection)).NotTo(HaveOccurred())
+		Expect(connection.Kind).To(Equal("ManagementClusterConnection"))
+		Expect(connection.Name).To(Equal("tigera-secure"))
+		Expect(connection.Spec.ManagementClusterAddr).To(Equal("example.com:30449"))
+
+		guardianSecret := &corev1.Secret{}
+		Expect(yaml.Unmarshal([]byte(docs[1]), guardianSecret)).NotTo(HaveOccurred())
+		Expect(guardianSecret.Name).To(Equal(render.GuardianSecretName))
+		Expect(guardianSecret.Namespace).To(Equal(common.OperatorNamespace()))
+		Expect(guardianSecret.Data).To(Equal(map[string][]byte{
+			render.GuardianSecretManagementClusterCertName: caCert,
+			render.GuardianSecretManagedClusterCertName:    cert,
+			render.GuardianSecretManagedClusterKeyName:     key,
+		}))
+	})
+})
@@ -34,6 +34,7 @@ import (
 	"github.com/tigera/operator/pkg/render/common/podaffinity"
 	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
+	"github.com/tigera/operator/pkg/render/common/provider"
 	"github.com/tigera/operator/pkg/render/common/secret"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -82,6 +83,18 @@ const (
 	voltronTunnelHashAnnotation = "hash.operator.tigera.io/voltron-tunnel"
 	defaultVoltronPort          = "9443"
 	defaultTunnelVoltronPort    = "9449"
+	voltronTunnelPort           = 9449
+
+	// During a rotation of the tunnel CA, the key of the new CA is stored next to the key of the previous one and the
+	// time the rotation started is recorded on the tunnel secret.
+	VoltronTunnelSecretNextKeyName         = "next-key"
+	VoltronTunnelRotationStartedAnnotation = "certs.tigera.io/tunnel-ca-rotation-started"
+
+	// Every ManagedClusterAccessRule grants the impersonate verb on the managedclusters it lists through a ClusterRole
+	// and ClusterRoleBinding named after the rule. Voltron checks this verb before impersonating a user into a managed
+	// cluster.
+	ManagedClusterAccessRBACPrefix = "tigera-managed-cluster-access-"
+	ManagedClusterAccessLabel      = "operator.tigera.io/managed-cluster-access"
 )
 
 func Manager(cfg *ManagerConfiguration) (Component, error) {
@@ -89,12 +102,10 @@ func Manager(cfg *ManagerConfiguration) (Component, error) {
 	tlsAnnotations := map[string]string{
 		KibanaTLSHashAnnotation: rmeta.SecretsAnnotationHash(cfg.KibanaSecrets...),
 	}
-	var tlsAnnotation string
+	replicator := secret.NewReplicator()
 	if cfg.Installation.CertificateManagement == nil {
-		tlsSecrets = append(tlsSecrets, secret.CopyToNamespace(ManagerNamespace, cfg.TLSKeyPair)...)
-		tlsAnnotation = rmeta.AnnotationHash(cfg.TLSKeyPair.Data)
+		tlsSecrets = append(tlsSecrets, replicator.Replicate(ManagerNamespace, cfg.TLSKeyPair)...)
 	}
-	tlsAnnotations[TlsSecretHashAnnotation] = tlsAnnotation
 
 	if cfg.KeyValidatorConfig != nil {
 		tlsSecrets = append(tlsSecrets, cfg.KeyValidatorConfig.RequiredSecrets(ManagerNamespace)...)
@@ -107,15 +118,13 @@ func Manager(cfg *ManagerConfiguration) (Component, error) {
 		// Copy tunnelSecret and internalTrafficSecret to TLS secrets
 		// tunnelSecret contains the ca cert to generate guardian certificates
 		// internalTrafficCert containts the cert used to communicated within the management K8S cluster
-		tlsSecrets = append(tlsSecrets, secret.CopyToNamespace(ManagerNamespace, cfg.TunnelSecret)...)
-		tlsSecrets = append(tlsSecrets, secret.CopyToNamespace(ManagerNamespace, cfg.InternalTrafficSecret)...)
-		tlsAnnotations[voltronTunnelHashAnnotation] = rmeta.AnnotationHash(cfg.TunnelSecret.Data)
-		tlsAnnotations[ManagerInternalTLSHashAnnotation] = rmeta.AnnotationHash(cfg.InternalTrafficSecret.Data)
+		tlsSecrets = append(tlsSecrets, replicator.Replicate(ManagerNamespace, cfg.TunnelSecret, cfg.InternalTrafficSecret)...)
 	}
 	return &managerComponent{
 		cfg:            cfg,
 		tlsSecrets:     tlsSecrets,
 		tlsAnnotations: tlsAnnotations,
+		replicator:     replicator,
 	}, nil
 }
 
@@ -130,7 +139,6 @@ type ManagerConfiguration struct {
 	ESClusterConfig               *relasticsearch.ClusterConfig
 	TLSKeyPair                    *corev1.Secret
 	PullSecrets                   []*corev1.Secret
-	Openshift                     bool
 	Installation                  *operatorv1.InstallationSpec
 	ManagementCluster             *operatorv1.ManagementCluster
 	TunnelSecret                  *corev1.Secret
@@ -138,12 +146,23 @@ type ManagerConfiguration struct {
 	ClusterDomain                 string
 	ESLicenseType                 ElasticsearchLicenseType
 	Replicas                      *int32
+	// ESProxy is the es-proxy configuration of the Manager, if any.
+	ESProxy *operatorv1.ESProxySpec
+	// TLS is the TLS configuration of the HTTPS server of voltron, if any.
+	TLS *operatorv1.TLSSettings
+	// ManagedClusterAccessRBAC holds the names of the managed cluster access RBAC found in the cluster, so that the RBAC
+	// of removed rules is deleted.
+	ManagedClusterAccessRBAC []string
+	// KubernetesVersion is the version of the cluster. The PodSecurityPolicies are only rendered when the cluster
+	// supports them, which is assumed when the version is unknown.
+	KubernetesVersion *common.VersionInfo
 }
 
 type managerComponent struct {
 	cfg            *ManagerConfiguration
 	tlsSecrets     []*corev1.Secret
 	tlsAnnotations map[string]string
+	replicator     *secret.Replicator
 	managerImage   string
 	proxyImage     string
 	esProxyImage   string
@@ -192,11 +211,11 @@ func (c *managerComponent) Objects() ([]client.Object, []client.Object) {
 	objs := []client.Object{
 		CreateNamespace(ManagerNamespace, c.cfg.Installation.KubernetesProvider),
 	}
-	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(ManagerNamespace, c.cfg.PullSecrets...)...)...)
+	objs = append(objs, secret.ToRuntimeObjects(c.replicator.Replicate(ManagerNamespace, c.cfg.PullSecrets...)...)...)
 
 	objs = append(objs,
 		managerServiceAccount(),
-		managerClusterRole(c.cfg.ManagementCluster != nil, false, c.cfg.Openshift),
+		managerClusterRole(c.cfg.ManagementCluster != nil, false, c.cfg.Installation.KubernetesProvider),
 		managerClusterRoleBinding(),
 	)
 	objs = append(objs, c.getTLSObjects()...)
@@ -204,30 +223,29 @@ func (c *managerComponent) Objects() ([]client.Object, []client.Object) {
 		c.managerService(),
 	)
 
-	// If we're running on openshift, we need to add in an SCC.
-	if c.cfg.Openshift {
+	accessRBAC, staleAccessRBAC := c.managedClusterAccessRBAC()
+	objs = append(objs, accessRBAC...)
+	toDelete := staleAccessRBAC
+
+	// If the provider enforces its security context constraints, we need to add in an SCC.
+	if provider.For(c.cfg.Installation.KubernetesProvider).SecurityContextConstraints() {
 		objs = append(objs, c.securityContextConstraints())
-	} else {
-		// If we're not running openshift, we need to add pod security policies.
+	} else if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() && c.cfg.KubernetesVersion.ProvidesPodSecurityPolicy() {
+		// Otherwise, we need to add pod security policies.
 		objs = append(objs, c.managerPodSecurityPolicy())
 	}
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(ManagerNamespace, c.cfg.ESSecrets...)...)...)
 	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(ManagerNamespace, c.cfg.KibanaSecrets...)...)...)
-	if c.cfg.ComplianceServerCertSecret != nil {
-		objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(ManagerNamespace, c.cfg.ComplianceServerCertSecret)...)...)
-	}
-	if c.cfg.PacketCaptureServerCertSecret != nil {
-		objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(ManagerNamespace, c.cfg.PacketCaptureServerCertSecret)...)...)
-	}
-	if c.cfg.PrometheusCertSecret != nil {
-		objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(ManagerNamespace, c.cfg.PrometheusCertSecret)...)...)
-	}
+	objs = append(objs, secret.ToRuntimeObjects(c.replicator.Replicate(ManagerNamespace,
+		c.cfg.ComplianceServerCertSecret,
+		c.cfg.PacketCaptureServerCertSecret,
+		c.cfg.PrometheusCertSecret,
+	)...)...)
 	objs = append(objs, c.managerDeployment())
 	if c.cfg.KeyValidatorConfig != nil {
 		objs = append(objs, configmap.ToRuntimeObjects(c.cfg.KeyValidatorConfig.RequiredConfigMaps(ManagerNamespace)...)...)
 	}
 
-	var toDelete []client.Object
 	if c.cfg.Installation.CertificateManagement != nil {
 		objs = append(objs, CSRClusterRoleBinding(ManagerServiceName, ManagerNamespace))
 		// If we want to use certificate management, we should clean up any existing secrets that have been created by the operator.
@@ -252,23 +270,6 @@ func (c *managerComponent) Ready() bool {
 // managerDeployment creates a deployment for the Tigera Secure manager component.
 func (c *managerComponent) managerDeployment() *appsv1.Deployment {
 	annotations := make(map[string]string)
-
-	if c.cfg.ComplianceServerCertSecret != nil {
-		annotations[complianceServerTLSHashAnnotation] = rmeta.AnnotationHash(c.cfg.ComplianceServerCertSecret.Data)
-	}
-
-	if c.cfg.PacketCaptureServerCertSecret != nil {
-		annotations[PacketCaptureTLSHashAnnotation] = rmeta.AnnotationHash(c.cfg.PacketCaptureServerCertSecret.Data)
-	}
-
-	if c.cfg.PrometheusCertSecret != nil {
-		annotations[prometheusTLSHashAnnotation] = rmeta.AnnotationHash(c.cfg.PrometheusCertSecret.Data)
-	}
-	// Add a hash of the Secret to ensure if it changes the manager will be
-	// redeployed.	The following secrets are annotated:
-	// manager-tls : cert used for tigera UI
-	// internal-manager-tls : cert used for internal communication within K8S cluster
-	// tigera-management-cluster-connection : cert used to generate guardian certificates
 	for k, v := range c.tlsAnnotations {
 		annotations[k] = v
 	}
@@ -336,6 +337,10 @@ func (c *managerComponent) managerDeployment() *appsv1.Deployment {
 			Template: *podTemplate,
 		},
 	}
+
+	// Restart the manager when any of the secrets copied into its namespace changes: the certificate of the UI, the
+	// internal certificate of the management cluster, the tunnel CA and the certificates of the servers it proxies.
+	c.replicator.AnnotateConsumers(d)
 	return d
 }
 
@@ -575,6 +580,17 @@ func (c *managerComponent) managerProxyContainer() corev1.Container {
 		env = append(env, corev1.EnvVar{Name: "VOLTRON_ENABLE_COMPLIANCE", Value: "false"})
 	}
 
+	if c.cfg.ManagementCluster != nil {
+		env = append(env, TunnelKeepAliveEnv("VOLTRON_", c.cfg.ManagementCluster.Spec.TunnelKeepAlive)...)
+	}
+
+	if c.cfg.ManagementCluster != nil && len(c.cfg.ManagementCluster.Spec.ManagedClusterAccess) != 0 {
+		env = append(env, corev1.EnvVar{Name: "VOLTRON_RESTRICT_MANAGED_CLUSTER_ACCESS", Value: "true"})
+	}
+
+	// Voltron terminates the TLS of the queries of the UI and es-proxy.
+	env = append(env, relasticsearch.TLSEnvVars(c.cfg.TLS)...)
+
 	return corev1.Container{
 		Name:            VoltronName,
 		Image:           c.proxyImage,
@@ -585,6 +601,26 @@ func (c *managerComponent) managerProxyContainer() corev1.Container {
 	}
 }
 
+// TunnelKeepAliveEnv returns the environment variables tuning the keepalive of the tunnel, for either Voltron or guardian
+// depending on the prefix.
+func TunnelKeepAliveEnv(prefix string, keepAlive *operatorv1.TunnelKeepAlive) []corev1.EnvVar {
+	if keepAlive == nil {
+		return nil
+	}
+
+	var env []corev1.EnvVar
+	if keepAlive.Interval != nil {
+		env = append(env, corev1.EnvVar{Name: prefix + "TUNNEL_KEEPALIVE_INTERVAL", Value: keepAlive.Interval.Duration.String()})
+	}
+	if keepAlive.Timeout != nil {
+		env = append(env, corev1.EnvVar{Name: prefix + "TUNNEL_KEEPALIVE_TIMEOUT", Value: keepAlive.Timeout.Duration.String()})
+	}
+	if keepAlive.TCPKeepAlivePeriod != nil {
+		env = append(env, corev1.EnvVar{Name: prefix + "TUNNEL_TCP_KEEPALIVE_PERIOD", Value: keepAlive.TCPKeepAlivePeriod.Duration.String()})
+	}
+	return env
+}
+
 func (c *managerComponent) volumeMountsForProxyManager() []corev1.VolumeMount {
 	var mounts = []corev1.VolumeMount{
 		{Name: ManagerTLSSecretName, MountPath: "/certs/https", ReadOnly: true},
@@ -634,6 +670,13 @@ func (c *managerComponent) managerEsProxyContainer() corev1.Container {
 		volumeMounts = append(volumeMounts, c.cfg.KeyValidatorConfig.RequiredVolumeMounts()...)
 	}
 
+	resources := corev1.ResourceRequirements{}
+	if c.cfg.ESProxy != nil {
+		if c.cfg.ESProxy.ResourceRequirements != nil {
+			resources = *c.cfg.ESProxy.ResourceRequirements
+		}
+	}
+
 	return corev1.Container{
 		Name:            "tigera-es-proxy",
 		Image:           c.esProxyImage,
@@ -641,6 +684,7 @@ func (c *managerComponent) managerEsProxyContainer() corev1.Container {
 		SecurityContext: podsecuritycontext.NewBaseContext(),
 		Env:             env,
 		VolumeMounts:    volumeMounts,
+		Resources:       resources,
 	}
 }
 
@@ -658,6 +702,7 @@ func (c *managerComponent) managerService() *corev1.Service {
 			Namespace: ManagerNamespace,
 		},
 		Spec: corev1.ServiceSpec{
+			Type: provider.For(c.cfg.Installation.KubernetesProvider).ExposedServiceType(),
 			Ports: []corev1.ServicePort{
 				{
 					Port:       managerPort,
@@ -681,7 +726,7 @@ func managerServiceAccount() *corev1.ServiceAccount {
 }
 
 // managerClusterRole returns a clusterrole that allows authn/authz review requests.
-func managerClusterRole(managementCluster, managedCluster, openshift bool) *rbacv1.ClusterRole {
+func managerClusterRole(managementCluster, managedCluster bool, p operatorv1.Provider) *rbacv1.ClusterRole {
 	cr := &rbacv1.ClusterRole{
 		TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
 		ObjectMeta: metav1.ObjectMeta{
@@ -776,7 +821,7 @@ func managerClusterRole(managementCluster, managedCluster, openshift bool) *rbac
 		)
 	}
 
-	if !openshift {
+	if provider.For(p).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		cr.Rules = append(cr.Rules,
 			rbacv1.PolicyRule{
@@ -812,6 +857,67 @@ func managerClusterRoleBinding() *rbacv1.ClusterRoleBinding {
 	}
 }
 
+// managedClusterAccessRBAC returns the ClusterRole and ClusterRoleBinding of every managed cluster access rule, along
+// with the RBAC of the rules that have been removed.
+func (c *managerComponent) managedClusterAccessRBAC() ([]client.Object, []client.Object) {
+	var objs []client.Object
+	rendered := map[string]bool{}
+	if c.cfg.ManagementCluster != nil {
+		for _, rule := range c.cfg.ManagementCluster.Spec.ManagedClusterAccess {
+			name := ManagedClusterAccessRBACPrefix + rule.Name
+			rendered[name] = true
+
+			objs = append(objs, &rbacv1.ClusterRole{
+				TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{ManagedClusterAccessLabel: "true"},
+				},
+				Rules: []rbacv1.PolicyRule{
+					{
+						APIGroups:     []string{"projectcalico.org"},
+						Resources:     []string{"managedclusters"},
+						ResourceNames: rule.ManagedClusters,
+						Verbs:         []string{"impersonate"},
+					},
+				},
+			})
+
+			var subjects []rbacv1.Subject
+			for _, user := range rule.Users {
+				subjects = append(subjects, rbacv1.Subject{Kind: "User", APIGroup: "rbac.authorization.k8s.io", Name: user})
+			}
+			for _, group := range rule.Groups {
+				subjects = append(subjects, rbacv1.Subject{Kind: "Group", APIGroup: "rbac.authorization.k8s.io", Name: group})
+			}
+			objs = append(objs, &rbacv1.ClusterRoleBinding{
+				TypeMeta: metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{ManagedClusterAccessLabel: "true"},
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: "rbac.authorization.k8s.io",
+					Kind:     "ClusterRole",
+					Name:     name,
+				},
+				Subjects: subjects,
+			})
+		}
+	}
+
+	var toDelete []client.Object
+	for _, name := range c.cfg.ManagedClusterAccessRBAC {
+		if !rendered[name] {
+			toDelete = append(toDelete,
+				&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: name}},
+				&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			)
+		}
+	}
+	return objs, toDelete
+}
+
 // TODO: Can we get rid of this and instead just bind to default ones?
 func (c *managerComponent) securityContextConstraints() *ocsv1.SecurityContextConstraints {
 	privilegeEscalation := false
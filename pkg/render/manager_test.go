@@ -15,12 +15,16 @@
 package render_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
@@ -520,6 +524,105 @@ var _ = Describe("Tigera Secure Manager rendering tests", func() {
 		Expect(deploy.Spec.Template.Spec.Affinity).To(Equal(podaffinity.NewPodAntiAffinity("tigera-manager", render.ManagerNamespace)))
 	})
 
+	It("should configure the keepalive of the tunnels of the managed clusters", func() {
+		managementCluster := &operatorv1.ManagementCluster{
+			Spec: operatorv1.ManagementClusterSpec{
+				TunnelKeepAlive: &operatorv1.TunnelKeepAlive{TCPKeepAlivePeriod: &metav1.Duration{Duration: 30 * time.Second}},
+			},
+		}
+
+		resources := renderObjects(false, managementCluster, &operatorv1.InstallationSpec{}, true)
+		managerSvc, ok := rtest.GetResource(resources, "tigera-manager", render.ManagerNamespace, "", "v1", "Service").(*corev1.Service)
+		Expect(ok).To(BeTrue())
+		Expect(managerSvc.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+
+		deploy, ok := rtest.GetResource(resources, "tigera-manager", render.ManagerNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(ok).To(BeTrue())
+		voltron := deploy.Spec.Template.Spec.Containers[2]
+		Expect(voltron.Name).To(Equal("tigera-voltron"))
+		rtest.ExpectEnv(voltron.Env, "VOLTRON_TUNNEL_TCP_KEEPALIVE_PERIOD", "30s")
+	})
+
+	It("should render the RBAC of the managed cluster access rules", func() {
+		managementCluster := &operatorv1.ManagementCluster{
+			Spec: operatorv1.ManagementClusterSpec{
+				ManagedClusterAccess: []operatorv1.ManagedClusterAccessRule{{
+					Name:            "team-a",
+					ManagedClusters: []string{"cluster-a"},
+					Users:           []string{"alice"},
+					Groups:          []string{"team-a"},
+				}},
+			},
+		}
+
+		resources := renderObjects(false, managementCluster, installation, true)
+		clusterRole, ok := rtest.GetResource(resources, "tigera-managed-cluster-access-team-a", "", "rbac.authorization.k8s.io", "v1", "ClusterRole").(*rbacv1.ClusterRole)
+		Expect(ok).To(BeTrue())
+		Expect(clusterRole.Rules).To(ConsistOf(rbacv1.PolicyRule{
+			APIGroups:     []string{"projectcalico.org"},
+			Resources:     []string{"managedclusters"},
+			ResourceNames: []string{"cluster-a"},
+			Verbs:         []string{"impersonate"},
+		}))
+
+		binding, ok := rtest.GetResource(resources, "tigera-managed-cluster-access-team-a", "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding").(*rbacv1.ClusterRoleBinding)
+		Expect(ok).To(BeTrue())
+		Expect(binding.Labels).To(HaveKey(render.ManagedClusterAccessLabel))
+		Expect(binding.Subjects).To(ConsistOf(
+			rbacv1.Subject{Kind: "User", APIGroup: "rbac.authorization.k8s.io", Name: "alice"},
+			rbacv1.Subject{Kind: "Group", APIGroup: "rbac.authorization.k8s.io", Name: "team-a"},
+		))
+
+		deploy, ok := rtest.GetResource(resources, "tigera-manager", render.ManagerNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(ok).To(BeTrue())
+		rtest.ExpectEnv(deploy.Spec.Template.Spec.Containers[2].Env, "VOLTRON_RESTRICT_MANAGED_CLUSTER_ACCESS", "true")
+	})
+
+	It("should apply the resources of es-proxy and the TLS settings of voltron", func() {
+		resources := &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		}
+		component, err := render.Manager(&render.ManagerConfiguration{
+			ESClusterConfig: relasticsearch.NewClusterConfig("clusterTestName", 1, 1, 1),
+			TLSKeyPair:      rtest.CreateCertSecret(render.ManagerTLSSecretName, common.OperatorNamespace()),
+			Installation:    installation,
+			ClusterDomain:   dns.DefaultClusterDomain,
+			ESLicenseType:   render.ElasticsearchLicenseTypeEnterpriseTrial,
+			ESProxy:         &operatorv1.ESProxySpec{ResourceRequirements: resources},
+			TLS:             &operatorv1.TLSSettings{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		objs, _ := component.Objects()
+
+		deploy, ok := rtest.GetResource(objs, "tigera-manager", render.ManagerNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(ok).To(BeTrue())
+		esProxy := rtest.GetContainer(deploy.Spec.Template.Spec.Containers, "tigera-es-proxy")
+		Expect(esProxy.Resources).To(Equal(*resources))
+		for _, env := range esProxy.Env {
+			Expect(env.Name).NotTo(Equal("TLS_CIPHER_SUITES"))
+		}
+		voltron := rtest.GetContainer(deploy.Spec.Template.Spec.Containers, render.VoltronName)
+		rtest.ExpectEnv(voltron.Env, "TLS_CIPHER_SUITES", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384")
+	})
+
+	It("should not render the PodSecurityPolicy when the cluster doesn't support them", func() {
+		component, err := render.Manager(&render.ManagerConfiguration{
+			ESClusterConfig:   relasticsearch.NewClusterConfig("clusterTestName", 1, 1, 1),
+			TLSKeyPair:        rtest.CreateCertSecret(render.ManagerTLSSecretName, common.OperatorNamespace()),
+			Installation:      installation,
+			ClusterDomain:     dns.DefaultClusterDomain,
+			ESLicenseType:     render.ElasticsearchLicenseTypeEnterpriseTrial,
+			KubernetesVersion: &common.VersionInfo{Major: 1, Minor: 25},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		objs, _ := component.Objects()
+
+		Expect(rtest.GetResource(objs, "tigera-manager", "", "policy", "v1beta1", "PodSecurityPolicy")).To(BeNil())
+		Expect(rtest.GetResource(objs, "tigera-manager", render.ManagerNamespace, "apps", "v1", "Deployment")).NotTo(BeNil())
+	})
+
 	It("should not render an user supplied manager TLS certificate", func() {
 
 		resources := renderObjects(false, nil, &operatorv1.InstallationSpec{}, true)
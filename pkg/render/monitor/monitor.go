@@ -17,12 +17,15 @@ package monitor
 import (
 	_ "embed"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/render/common/authentication"
 	"github.com/tigera/operator/pkg/render/common/configmap"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -51,6 +54,7 @@ const (
 	TigeraPrometheusObjectName  = "tigera-prometheus"
 	TigeraPrometheusSAName      = "prometheus"
 	TigeraPrometheusDPRate      = "tigera-prometheus-dp-rate"
+	VPPMetrics                  = "vpp-metrics"
 	TigeraPrometheusRole        = "tigera-prometheus-role"
 	TigeraPrometheusRoleBinding = "tigera-prometheus-role-binding"
 
@@ -65,31 +69,55 @@ const (
 	AlertmanagerConfigSecret = "alertmanager-calico-node-alertmanager"
 
 	prometheusServiceAccountName = "prometheus"
+
+	defaultPrometheusRetention = "24h"
+
+	// Keys of the remote write authentication secrets, see operatorv1.PrometheusRemoteWrite.
+	RemoteWriteUsernameKey = "username"
+	RemoteWritePasswordKey = "password"
+	RemoteWriteTokenKey    = "token"
+
+	prometheusSecretsMountPath = "/etc/prometheus/secrets"
+
+	// ThanosObjectStorageConfigKey is the key of the Thanos object storage configuration secret, see operatorv1.PrometheusThanos.
+	ThanosObjectStorageConfigKey = "objstore.yml"
+
+	prometheusReplicaLabelName = "prometheus_replica"
+
+	defaultScrapeInterval = "5s"
+	defaultScrapeTimeout  = "5s"
 )
 
 func Monitor(cfg *Config) render.Component {
 	var tlsSecrets []*corev1.Secret
 	var tlsHash string
+	replicator := secret.NewReplicator()
 	if cfg.Installation.CertificateManagement == nil {
-		tlsSecrets = []*corev1.Secret{secret.CopyToNamespace(common.TigeraPrometheusNamespace, cfg.TLSSecret)[0]}
-		tlsHash = rmeta.AnnotationHash(cfg.TLSSecret.Data)
+		tlsSecrets = replicator.Replicate(common.TigeraPrometheusNamespace, cfg.TLSSecret)
+		tlsHash = rmeta.AnnotationHash(tlsSecrets[0].Data)
 	}
 
 	return &monitorComponent{
 		cfg:        cfg,
 		tlsSecrets: tlsSecrets,
 		tlsHash:    tlsHash,
+		replicator: replicator,
 	}
 }
 
 // Config contains all the config information needed to render the Monitor component.
 type Config struct {
+	Monitor                  operatorv1.MonitorSpec
 	Installation             *operatorv1.InstallationSpec
 	PullSecrets              []*corev1.Secret
 	AlertmanagerConfigSecret *corev1.Secret
 	KeyValidatorConfig       authentication.KeyValidatorConfig
 	TLSSecret                *corev1.Secret
 	ClusterDomain            string
+	// RemoteWriteSecrets are the authentication secrets referenced by the remote write endpoints of the Monitor.
+	RemoteWriteSecrets []*corev1.Secret
+	// ThanosObjectStorageSecret is the object storage configuration secret used by the Thanos sidecar.
+	ThanosObjectStorageSecret *corev1.Secret
 }
 
 type monitorComponent struct {
@@ -98,8 +126,10 @@ type monitorComponent struct {
 	prometheusImage        string
 	prometheusServiceImage string
 	csrImage               string
+	thanosImage            string
 	tlsSecrets             []*corev1.Secret
 	tlsHash                string
+	replicator             *secret.Replicator
 }
 
 func (mc *monitorComponent) ResolveImages(is *operatorv1.ImageSet) error {
@@ -125,6 +155,13 @@ func (mc *monitorComponent) ResolveImages(is *operatorv1.ImageSet) error {
 		errMsgs = append(errMsgs, err.Error())
 	}
 
+	if mc.cfg.Monitor.Prometheus != nil && mc.cfg.Monitor.Prometheus.Thanos != nil {
+		mc.thanosImage, err = components.GetReference(components.ComponentThanos, reg, path, prefix, is)
+		if err != nil {
+			errMsgs = append(errMsgs, err.Error())
+		}
+	}
+
 	if mc.cfg.Installation.CertificateManagement != nil {
 		mc.csrImage, err = render.ResolveCSRInitImage(mc.cfg.Installation, is)
 		if err != nil {
@@ -154,8 +191,10 @@ func (mc *monitorComponent) Objects() ([]client.Object, []client.Object) {
 		mc.roleBinding(),
 	)
 
-	toCreate = append(toCreate, secret.ToRuntimeObjects(secret.CopyToNamespace(common.TigeraPrometheusNamespace, mc.cfg.PullSecrets...)...)...)
-	toCreate = append(toCreate, secret.ToRuntimeObjects(secret.CopyToNamespace(common.TigeraPrometheusNamespace, mc.cfg.AlertmanagerConfigSecret)...)...)
+	toCreate = append(toCreate, secret.ToRuntimeObjects(mc.replicator.Replicate(common.TigeraPrometheusNamespace, mc.cfg.PullSecrets...)...)...)
+	toCreate = append(toCreate, secret.ToRuntimeObjects(mc.replicator.Replicate(common.TigeraPrometheusNamespace, mc.cfg.AlertmanagerConfigSecret)...)...)
+	toCreate = append(toCreate, secret.ToRuntimeObjects(mc.replicator.Replicate(common.TigeraPrometheusNamespace, mc.cfg.RemoteWriteSecrets...)...)...)
+	toCreate = append(toCreate, secret.ToRuntimeObjects(mc.replicator.Replicate(common.TigeraPrometheusNamespace, mc.cfg.ThanosObjectStorageSecret)...)...)
 	if mc.cfg.Installation.CertificateManagement == nil {
 		toCreate = append(toCreate, secret.ToRuntimeObjects(mc.tlsSecrets...)...)
 	} else {
@@ -177,17 +216,30 @@ func (mc *monitorComponent) Objects() ([]client.Object, []client.Object) {
 		mc.clusterRoleBinding(),
 	)
 
-	if mc.cfg.KeyValidatorConfig != nil {
-		toCreate = append(toCreate, secret.ToRuntimeObjects(mc.cfg.KeyValidatorConfig.RequiredSecrets(common.TigeraPrometheusNamespace)...)...)
-		toCreate = append(toCreate, configmap.ToRuntimeObjects(mc.cfg.KeyValidatorConfig.RequiredConfigMaps(common.TigeraPrometheusNamespace)...)...)
-	}
-
 	// This is to delete a service that had been released in v3.8 with a typo in the name.
 	// TODO Remove the toDelete object after we drop support for v3.8.
 	toDelete := []client.Object{
 		mc.serviceMonitorElasicsearchToDelete(),
 	}
 
+	if mc.prometheusHAEnabled() {
+		toCreate = append(toCreate, mc.prometheusPodDisruptionBudget())
+	} else {
+		toDelete = append(toDelete, mc.prometheusPodDisruptionBudget())
+	}
+
+	// The pod monitor scraping the VPP metrics is only relevant to clusters running the VPP dataplane.
+	if mc.vppDataplaneEnabled() {
+		toCreate = append(toCreate, mc.podMonitorVPP())
+	} else {
+		toDelete = append(toDelete, mc.podMonitorVPP())
+	}
+
+	if mc.cfg.KeyValidatorConfig != nil {
+		toCreate = append(toCreate, secret.ToRuntimeObjects(mc.cfg.KeyValidatorConfig.RequiredSecrets(common.TigeraPrometheusNamespace)...)...)
+		toCreate = append(toCreate, configmap.ToRuntimeObjects(mc.cfg.KeyValidatorConfig.RequiredConfigMaps(common.TigeraPrometheusNamespace)...)...)
+	}
+
 	return toCreate, toDelete
 }
 
@@ -340,7 +392,7 @@ func (mc *monitorComponent) prometheus() *monitoringv1.Prometheus {
 		volumes = append(volumes, mc.cfg.KeyValidatorConfig.RequiredVolumes()...)
 	}
 
-	return &monitoringv1.Prometheus{
+	prometheus := &monitoringv1.Prometheus{
 		TypeMeta: metav1.TypeMeta{Kind: monitoringv1.PrometheusesKind, APIVersion: MonitoringAPIVersion},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      CalicoNodePrometheus,
@@ -389,8 +441,15 @@ func (mc *monitorComponent) prometheus() *monitoringv1.Prometheus {
 			ServiceMonitorSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "network-operators"}},
 			PodMonitorSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"team": "network-operators"}},
 			Version:                components.ComponentPrometheus.Version,
-			Retention:              "24h",
-			Resources:              corev1.ResourceRequirements{Requests: corev1.ResourceList{"memory": resource.MustParse("400Mi")}},
+			Retention:              mc.prometheusRetention(),
+			RetentionSize:          mc.prometheusRetentionSize(),
+			Storage:                mc.prometheusStorage(),
+			Resources:              mc.prometheusResources(),
+			RemoteWrite:            mc.prometheusRemoteWrite(),
+			Secrets:                mc.prometheusSecrets(),
+			Thanos:                 mc.prometheusThanos(),
+			Replicas:               mc.prometheusReplicas(),
+			ExternalLabels:         mc.prometheusExternalLabels(),
 			RuleSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
 				"prometheus": CalicoNodePrometheus,
 				"role":       "tigera-prometheus-rules",
@@ -409,6 +468,197 @@ func (mc *monitorComponent) prometheus() *monitoringv1.Prometheus {
 			},
 		},
 	}
+
+	if mc.prometheusHAEnabled() {
+		replicaLabelName := prometheusReplicaLabelName
+		prometheus.Spec.ReplicaExternalLabelName = &replicaLabelName
+		prometheus.Spec.Affinity = &corev1.Affinity{
+			PodAntiAffinity: &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+					{
+						Weight: 100,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"prometheus": CalicoNodePrometheus},
+							},
+							Namespaces:  []string{common.TigeraPrometheusNamespace},
+							TopologyKey: "kubernetes.io/hostname",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	return prometheus
+}
+
+func (mc *monitorComponent) prometheusReplicas() *int32 {
+	if mc.cfg.Monitor.Prometheus != nil {
+		return mc.cfg.Monitor.Prometheus.Replicas
+	}
+	return nil
+}
+
+func (mc *monitorComponent) prometheusHAEnabled() bool {
+	replicas := mc.prometheusReplicas()
+	return replicas != nil && *replicas > 1
+}
+
+func (mc *monitorComponent) prometheusExternalLabels() map[string]string {
+	if mc.cfg.Monitor.Prometheus != nil {
+		return mc.cfg.Monitor.Prometheus.ExternalLabels
+	}
+	return nil
+}
+
+// prometheusPodDisruptionBudget makes sure that at most one of the Prometheus replicas is evicted at a time.
+func (mc *monitorComponent) prometheusPodDisruptionBudget() *policyv1beta1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt(1)
+	return &policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{Kind: "PodDisruptionBudget", APIVersion: "policy/v1beta1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CalicoNodePrometheus,
+			Namespace: common.TigeraPrometheusNamespace,
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"prometheus": CalicoNodePrometheus},
+			},
+		},
+	}
+}
+
+func (mc *monitorComponent) prometheusRetention() string {
+	if mc.cfg.Monitor.Prometheus != nil && mc.cfg.Monitor.Prometheus.Retention != "" {
+		return mc.cfg.Monitor.Prometheus.Retention
+	}
+	return defaultPrometheusRetention
+}
+
+func (mc *monitorComponent) prometheusRetentionSize() string {
+	if mc.cfg.Monitor.Prometheus != nil {
+		return mc.cfg.Monitor.Prometheus.RetentionSize
+	}
+	return ""
+}
+
+// prometheusStorage returns the storage spec for Prometheus. When no storage is configured in the Monitor CR, nil is
+// returned and the prometheus-operator falls back to an emptyDir volume.
+func (mc *monitorComponent) prometheusStorage() *monitoringv1.StorageSpec {
+	if mc.cfg.Monitor.Prometheus == nil || mc.cfg.Monitor.Prometheus.Storage == nil {
+		return nil
+	}
+	storage := mc.cfg.Monitor.Prometheus.Storage
+
+	var storageClassName *string
+	if storage.StorageClassName != "" {
+		storageClassName = &storage.StorageClassName
+	}
+
+	return &monitoringv1.StorageSpec{
+		VolumeClaimTemplate: monitoringv1.EmbeddedPersistentVolumeClaim{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: storageClassName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: storage.Size},
+				},
+			},
+		},
+	}
+}
+
+func (mc *monitorComponent) prometheusResources() corev1.ResourceRequirements {
+	if mc.cfg.Monitor.Prometheus != nil && mc.cfg.Monitor.Prometheus.Resources != nil {
+		return *mc.cfg.Monitor.Prometheus.Resources
+	}
+	return corev1.ResourceRequirements{Requests: corev1.ResourceList{"memory": resource.MustParse("400Mi")}}
+}
+
+// prometheusRemoteWrite returns the remote write endpoints for Prometheus. Authentication secrets with a token are
+// mounted into the Prometheus pod and used as bearer token file, otherwise basic authentication is configured.
+func (mc *monitorComponent) prometheusRemoteWrite() []monitoringv1.RemoteWriteSpec {
+	if mc.cfg.Monitor.Prometheus == nil {
+		return nil
+	}
+
+	var remoteWrite []monitoringv1.RemoteWriteSpec
+	for _, rw := range mc.cfg.Monitor.Prometheus.RemoteWrite {
+		spec := monitoringv1.RemoteWriteSpec{
+			URL:                 rw.URL,
+			Name:                rw.Name,
+			WriteRelabelConfigs: relabelConfigs(rw.WriteRelabelConfigs),
+			QueueConfig:         queueConfig(rw.QueueConfig),
+		}
+
+		if s := mc.remoteWriteSecret(rw.AuthSecretName); s != nil {
+			if _, ok := s.Data[RemoteWriteTokenKey]; ok {
+				spec.BearerTokenFile = fmt.Sprintf("%s/%s/%s", prometheusSecretsMountPath, s.Name, RemoteWriteTokenKey)
+			} else {
+				spec.BasicAuth = &monitoringv1.BasicAuth{
+					Username: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: s.Name},
+						Key:                  RemoteWriteUsernameKey,
+					},
+					Password: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: s.Name},
+						Key:                  RemoteWritePasswordKey,
+					},
+				}
+			}
+		}
+		remoteWrite = append(remoteWrite, spec)
+	}
+	return remoteWrite
+}
+
+// prometheusSecrets returns the names of the secrets that are mounted into the Prometheus pod.
+func (mc *monitorComponent) prometheusSecrets() []string {
+	var names []string
+	for _, s := range mc.cfg.RemoteWriteSecrets {
+		if _, ok := s.Data[RemoteWriteTokenKey]; ok {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// prometheusThanos returns the Thanos sidecar spec for Prometheus, or nil when no sidecar is configured in the Monitor.
+// The sidecar only uploads blocks to object storage when an object storage configuration secret is provided.
+func (mc *monitorComponent) prometheusThanos() *monitoringv1.ThanosSpec {
+	if mc.cfg.Monitor.Prometheus == nil || mc.cfg.Monitor.Prometheus.Thanos == nil {
+		return nil
+	}
+
+	version := components.ComponentThanos.Version
+	thanos := &monitoringv1.ThanosSpec{
+		Image:   &mc.thanosImage,
+		Version: &version,
+	}
+	if mc.cfg.Monitor.Prometheus.Thanos.Resources != nil {
+		thanos.Resources = *mc.cfg.Monitor.Prometheus.Thanos.Resources
+	}
+	if mc.cfg.ThanosObjectStorageSecret != nil {
+		thanos.ObjectStorageConfig = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: mc.cfg.ThanosObjectStorageSecret.Name},
+			Key:                  ThanosObjectStorageConfigKey,
+		}
+	}
+	return thanos
+}
+
+func (mc *monitorComponent) remoteWriteSecret(name string) *corev1.Secret {
+	if name == "" {
+		return nil
+	}
+	for _, s := range mc.cfg.RemoteWriteSecrets {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
 }
 
 func (mc *monitorComponent) prometheusServiceAccount() *corev1.ServiceAccount {
@@ -505,6 +755,7 @@ func (mc *monitorComponent) prometheusHTTPAPIService() *corev1.Service {
 }
 
 func (mc *monitorComponent) podMonitor() *monitoringv1.PodMonitor {
+	interval, timeout, relabelConfigs := mc.scrapeConfig(operatorv1.ScrapeTargetFluentdMetrics)
 	return &monitoringv1.PodMonitor{
 		TypeMeta: metav1.TypeMeta{Kind: monitoringv1.PodMonitorsKind, APIVersion: MonitoringAPIVersion},
 		ObjectMeta: metav1.ObjectMeta{
@@ -517,10 +768,11 @@ func (mc *monitorComponent) podMonitor() *monitoringv1.PodMonitor {
 			NamespaceSelector: monitoringv1.NamespaceSelector{MatchNames: []string{"tigera-fluentd"}},
 			PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
 				{
-					HonorLabels:   true,
-					Interval:      "5s",
-					Port:          "metrics-port",
-					ScrapeTimeout: "5s",
+					HonorLabels:          true,
+					Interval:             interval,
+					Port:                 "metrics-port",
+					ScrapeTimeout:        timeout,
+					MetricRelabelConfigs: relabelConfigs,
 				},
 			},
 		},
@@ -559,7 +811,40 @@ func (mc *monitorComponent) prometheusRule() *monitoringv1.PrometheusRule {
 	}
 }
 
+func (mc *monitorComponent) vppDataplaneEnabled() bool {
+	return mc.cfg.Installation.CalicoNetwork != nil &&
+		mc.cfg.Installation.CalicoNetwork.LinuxDataplane != nil &&
+		*mc.cfg.Installation.CalicoNetwork.LinuxDataplane == operatorv1.LinuxDataplaneVPP
+}
+
+func (mc *monitorComponent) podMonitorVPP() *monitoringv1.PodMonitor {
+	interval, timeout, relabelConfigs := mc.scrapeConfig(operatorv1.ScrapeTargetVPP)
+	return &monitoringv1.PodMonitor{
+		TypeMeta: metav1.TypeMeta{Kind: monitoringv1.PodMonitorsKind, APIVersion: MonitoringAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      VPPMetrics,
+			Namespace: common.TigeraPrometheusNamespace,
+			Labels:    map[string]string{"team": "network-operators"},
+		},
+		Spec: monitoringv1.PodMonitorSpec{
+			// The pods of the calico-vpp-node DaemonSets of the VPP profiles have a k8s-app label of their own.
+			Selector:          metav1.LabelSelector{MatchLabels: map[string]string{render.VPPNodePodLabel: "true"}},
+			NamespaceSelector: monitoringv1.NamespaceSelector{MatchNames: []string{common.CalicoNamespace}},
+			PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
+				{
+					HonorLabels:          true,
+					Interval:             interval,
+					Port:                 render.VPPMetricsPortName,
+					ScrapeTimeout:        timeout,
+					MetricRelabelConfigs: relabelConfigs,
+				},
+			},
+		},
+	}
+}
+
 func (mc *monitorComponent) serviceMonitorCalicoNode() *monitoringv1.ServiceMonitor {
+	interval, timeout, relabelConfigs := mc.scrapeConfig(operatorv1.ScrapeTargetCalicoNode)
 	return &monitoringv1.ServiceMonitor{
 		TypeMeta: metav1.TypeMeta{Kind: monitoringv1.ServiceMonitorsKind, APIVersion: MonitoringAPIVersion},
 		ObjectMeta: metav1.ObjectMeta{
@@ -572,16 +857,18 @@ func (mc *monitorComponent) serviceMonitorCalicoNode() *monitoringv1.ServiceMoni
 			NamespaceSelector: monitoringv1.NamespaceSelector{MatchNames: []string{"calico-system"}},
 			Endpoints: []monitoringv1.Endpoint{
 				{
-					HonorLabels:   true,
-					Interval:      "5s",
-					Port:          "calico-metrics-port",
-					ScrapeTimeout: "5s",
+					HonorLabels:          true,
+					Interval:             interval,
+					Port:                 "calico-metrics-port",
+					ScrapeTimeout:        timeout,
+					MetricRelabelConfigs: relabelConfigs,
 				},
 				{
-					HonorLabels:   true,
-					Interval:      "5s",
-					Port:          "calico-bgp-metrics-port",
-					ScrapeTimeout: "5s",
+					HonorLabels:          true,
+					Interval:             interval,
+					Port:                 "calico-bgp-metrics-port",
+					ScrapeTimeout:        timeout,
+					MetricRelabelConfigs: relabelConfigs,
 				},
 			},
 		},
@@ -589,6 +876,7 @@ func (mc *monitorComponent) serviceMonitorCalicoNode() *monitoringv1.ServiceMoni
 }
 
 func (mc *monitorComponent) serviceMonitorElasticsearch() *monitoringv1.ServiceMonitor {
+	interval, timeout, relabelConfigs := mc.scrapeConfig(operatorv1.ScrapeTargetElasticsearchMetrics)
 	return &monitoringv1.ServiceMonitor{
 		TypeMeta: metav1.TypeMeta{Kind: monitoringv1.ServiceMonitorsKind, APIVersion: MonitoringAPIVersion},
 		ObjectMeta: metav1.ObjectMeta{
@@ -601,16 +889,160 @@ func (mc *monitorComponent) serviceMonitorElasticsearch() *monitoringv1.ServiceM
 			NamespaceSelector: monitoringv1.NamespaceSelector{MatchNames: []string{"tigera-elasticsearch"}},
 			Endpoints: []monitoringv1.Endpoint{
 				{
-					HonorLabels:   true,
-					Interval:      "5s",
-					Port:          "metrics-port",
-					ScrapeTimeout: "5s",
+					HonorLabels:          true,
+					Interval:             interval,
+					Port:                 "metrics-port",
+					ScrapeTimeout:        timeout,
+					MetricRelabelConfigs: relabelConfigs,
 				},
 			},
 		},
 	}
 }
 
+// scrapeConfig returns the scrape interval, scrape timeout and metric relabel configs of the given target, with the
+// overrides of the Monitor applied on top of the defaults.
+func (mc *monitorComponent) scrapeConfig(name operatorv1.ScrapeTargetName) (string, string, []*monitoringv1.RelabelConfig) {
+	interval, timeout := defaultScrapeInterval, defaultScrapeTimeout
+	var metricRelabelConfigs []*monitoringv1.RelabelConfig
+
+	for _, target := range mc.cfg.Monitor.ScrapeTargets {
+		if target.Name != name {
+			continue
+		}
+		if target.Interval != "" {
+			interval = target.Interval
+			// The scrape timeout must not exceed the interval.
+			if d, err := ParseDuration(interval); err == nil && d < 5*time.Second {
+				timeout = interval
+			}
+		}
+		if target.ScrapeTimeout != "" {
+			timeout = target.ScrapeTimeout
+		}
+		rcs := relabelConfigs(target.MetricRelabelConfigs)
+		for i := range rcs {
+			metricRelabelConfigs = append(metricRelabelConfigs, &rcs[i])
+		}
+	}
+	return interval, timeout, metricRelabelConfigs
+}
+
+// relabelConfigs converts the relabel configurations of the Monitor to the relabel configurations of the Prometheus
+// operator.
+func relabelConfigs(rcs []operatorv1.RelabelConfig) []monitoringv1.RelabelConfig {
+	var relabelConfigs []monitoringv1.RelabelConfig
+	for _, rc := range rcs {
+		relabelConfigs = append(relabelConfigs, monitoringv1.RelabelConfig{
+			SourceLabels: rc.SourceLabels,
+			Separator:    rc.Separator,
+			TargetLabel:  rc.TargetLabel,
+			Regex:        rc.Regex,
+			Modulus:      rc.Modulus,
+			Replacement:  rc.Replacement,
+			Action:       string(rc.Action),
+		})
+	}
+	return relabelConfigs
+}
+
+// queueConfig converts the remote write queue configuration of the Monitor to the queue configuration of the
+// Prometheus operator.
+func queueConfig(qc *operatorv1.RemoteWriteQueueConfig) *monitoringv1.QueueConfig {
+	if qc == nil {
+		return nil
+	}
+	return &monitoringv1.QueueConfig{
+		Capacity:          int(qc.Capacity),
+		MinShards:         int(qc.MinShards),
+		MaxShards:         int(qc.MaxShards),
+		MaxSamplesPerSend: int(qc.MaxSamplesPerSend),
+		BatchSendDeadline: qc.BatchSendDeadline,
+		MaxRetries:        int(qc.MaxRetries),
+		MinBackoff:        qc.MinBackoff,
+		MaxBackoff:        qc.MaxBackoff,
+	}
+}
+
+// ValidateScrapeTargets checks that the scrape timeout of every target, as rendered, doesn't exceed its interval, which
+// Prometheus rejects.
+func ValidateScrapeTargets(targets []operatorv1.ScrapeTarget) error {
+	mc := &monitorComponent{cfg: &Config{Monitor: operatorv1.MonitorSpec{ScrapeTargets: targets}}}
+	for _, target := range targets {
+		interval, timeout, _ := mc.scrapeConfig(target.Name)
+		i, err := ParseDuration(interval)
+		if err != nil {
+			return fmt.Errorf("invalid interval of the scrape target %s: %w", target.Name, err)
+		}
+		t, err := ParseDuration(timeout)
+		if err != nil {
+			return fmt.Errorf("invalid scrapeTimeout of the scrape target %s: %w", target.Name, err)
+		}
+		if t > i {
+			return fmt.Errorf("the scrapeTimeout %s of the scrape target %s is greater than its interval %s", timeout, target.Name, interval)
+		}
+	}
+	return nil
+}
+
+// prometheusDurationUnits are the units of the durations of Prometheus, in the order they appear in a duration.
+var prometheusDurationUnits = []struct {
+	unit string
+	d    time.Duration
+}{
+	{"y", 365 * 24 * time.Hour},
+	{"w", 7 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+}
+
+// ParseDuration parses a duration in the format of Prometheus, e.g. 1d12h or 30s, which unlike the format of
+// time.ParseDuration supports days, weeks and years, but no fractions.
+func ParseDuration(s string) (time.Duration, error) {
+	switch s {
+	case "":
+		return 0, fmt.Errorf("empty duration")
+	case "0":
+		return 0, nil
+	}
+	rest := s
+	var d time.Duration
+	next := 0
+	for rest != "" {
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		n, err := strconv.ParseInt(rest[:i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		rest = rest[i:]
+		found := false
+		for next < len(prometheusDurationUnits) {
+			u := prometheusDurationUnits[next]
+			next++
+			// "m" is a prefix of "ms".
+			if strings.HasPrefix(rest, u.unit) && !(u.unit == "m" && strings.HasPrefix(rest, "ms")) {
+				d += time.Duration(n) * u.d
+				rest = rest[len(u.unit):]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+	return d, nil
+}
+
 // This is to delete a service that had been released in v3.8 with a typo in the name.
 // TODO Remove this object after we drop support for v3.8.
 func (mc *monitorComponent) serviceMonitorElasicsearchToDelete() *monitoringv1.ServiceMonitor {
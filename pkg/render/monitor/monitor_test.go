@@ -16,12 +16,15 @@ package monitor_test
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/tigera/operator/pkg/dns"
 	"github.com/tigera/operator/pkg/render"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -105,10 +108,11 @@ var _ = Describe("monitor rendering tests", func() {
 			rtest.ExpectResource(obj, expectedRes.name, expectedRes.ns, expectedRes.group, expectedRes.version, expectedRes.kind)
 		}
 
-		Expect(toDelete).To(HaveLen(1))
+		Expect(toDelete).To(HaveLen(3))
 
-		obj := toDelete[0]
-		rtest.ExpectResource(obj, "elasticearch-metrics", common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.ServiceMonitorsKind)
+		rtest.ExpectResource(toDelete[0], "elasticearch-metrics", common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.ServiceMonitorsKind)
+		rtest.ExpectResource(toDelete[1], monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "policy", "v1beta1", "PodDisruptionBudget")
+		rtest.ExpectResource(toDelete[2], monitor.VPPMetrics, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PodMonitorsKind)
 	})
 
 	It("Should render Prometheus resource Specs correctly", func() {
@@ -150,6 +154,8 @@ var _ = Describe("monitor rendering tests", func() {
 		Expect(prometheusObj.Spec.PodMonitorSelector.MatchLabels["team"]).To(Equal("network-operators"))
 		Expect(prometheusObj.Spec.Version).To(Equal(prometheusCom.Version))
 		Expect(prometheusObj.Spec.Retention).To(Equal("24h"))
+		Expect(prometheusObj.Spec.RetentionSize).To(BeEmpty())
+		Expect(prometheusObj.Spec.Storage).To(BeNil())
 		Expect(prometheusObj.Spec.Resources.Requests.Memory().Equal(resource.MustParse("400Mi"))).To(BeTrue())
 		Expect(prometheusObj.Spec.RuleSelector.MatchLabels["prometheus"]).To(Equal("calico-node-prometheus"))
 		Expect(prometheusObj.Spec.RuleSelector.MatchLabels["role"]).To(Equal("tigera-prometheus-rules"))
@@ -312,6 +318,344 @@ var _ = Describe("monitor rendering tests", func() {
 		Expect(rolebindingObj.Subjects[0].Namespace).To(Equal(common.OperatorNamespace()))
 	})
 
+	It("Should render Prometheus with the retention, storage and resources configured in the Monitor", func() {
+		storageClassName := "prometheus-storage"
+		cfg.Monitor = operatorv1.MonitorSpec{
+			Prometheus: &operatorv1.Prometheus{
+				Retention:     "7d",
+				RetentionSize: "50GB",
+				Storage: &operatorv1.PrometheusStorage{
+					StorageClassName: storageClassName,
+					Size:             resource.MustParse("64Gi"),
+				},
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{"memory": resource.MustParse("4Gi")},
+					Limits:   corev1.ResourceList{"memory": resource.MustParse("8Gi")},
+				},
+			},
+		}
+		component := monitor.Monitor(cfg)
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, _ := component.Objects()
+
+		prometheusObj, ok := rtest.GetResource(toCreate, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PrometheusesKind).(*monitoringv1.Prometheus)
+		Expect(ok).To(BeTrue())
+		Expect(prometheusObj.Spec.Retention).To(Equal("7d"))
+		Expect(prometheusObj.Spec.RetentionSize).To(Equal("50GB"))
+		Expect(prometheusObj.Spec.Resources.Requests.Memory().Equal(resource.MustParse("4Gi"))).To(BeTrue())
+		Expect(prometheusObj.Spec.Resources.Limits.Memory().Equal(resource.MustParse("8Gi"))).To(BeTrue())
+
+		Expect(prometheusObj.Spec.Storage).NotTo(BeNil())
+		pvcSpec := prometheusObj.Spec.Storage.VolumeClaimTemplate.Spec
+		Expect(pvcSpec.StorageClassName).To(Equal(&storageClassName))
+		Expect(pvcSpec.AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+		Expect(pvcSpec.Resources.Requests.Storage().Equal(resource.MustParse("64Gi"))).To(BeTrue())
+	})
+
+	It("Should render Prometheus with the default retention and resources when only storage is configured", func() {
+		cfg.Monitor = operatorv1.MonitorSpec{
+			Prometheus: &operatorv1.Prometheus{
+				Storage: &operatorv1.PrometheusStorage{Size: resource.MustParse("10Gi")},
+			},
+		}
+		component := monitor.Monitor(cfg)
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, _ := component.Objects()
+
+		prometheusObj, ok := rtest.GetResource(toCreate, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PrometheusesKind).(*monitoringv1.Prometheus)
+		Expect(ok).To(BeTrue())
+		Expect(prometheusObj.Spec.Retention).To(Equal("24h"))
+		Expect(prometheusObj.Spec.RetentionSize).To(BeEmpty())
+		Expect(prometheusObj.Spec.Resources.Requests.Memory().Equal(resource.MustParse("400Mi"))).To(BeTrue())
+		Expect(prometheusObj.Spec.Storage.VolumeClaimTemplate.Spec.StorageClassName).To(BeNil())
+	})
+
+	It("Should render Prometheus with the remote write endpoints configured in the Monitor", func() {
+		relabelConfigs := []operatorv1.RelabelConfig{
+			{
+				SourceLabels: []string{"__name__"},
+				Regex:        "felix_.*",
+				Action:       operatorv1.RelabelActionKeep,
+			},
+		}
+		cfg.Monitor = operatorv1.MonitorSpec{
+			Prometheus: &operatorv1.Prometheus{
+				RemoteWrite: []operatorv1.PrometheusRemoteWrite{
+					{
+						URL:                 "https://mimir.example.org/api/v1/push",
+						Name:                "mimir",
+						AuthSecretName:      "mimir-auth",
+						WriteRelabelConfigs: relabelConfigs,
+						QueueConfig:         &operatorv1.RemoteWriteQueueConfig{Capacity: 5000, BatchSendDeadline: "10s"},
+					},
+					{
+						URL:            "https://cortex.example.org/api/v1/push",
+						AuthSecretName: "cortex-auth",
+					},
+					{
+						URL: "http://thanos-receive.example.org/api/v1/receive",
+					},
+				},
+			},
+		}
+		cfg.RemoteWriteSecrets = []*corev1.Secret{
+			{
+				TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "mimir-auth", Namespace: common.OperatorNamespace()},
+				Data: map[string][]byte{
+					monitor.RemoteWriteUsernameKey: []byte("user"),
+					monitor.RemoteWritePasswordKey: []byte("pass"),
+				},
+			},
+			{
+				TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "cortex-auth", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{monitor.RemoteWriteTokenKey: []byte("token")},
+			},
+		}
+		component := monitor.Monitor(cfg)
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, _ := component.Objects()
+
+		// The authentication secrets are copied to the Prometheus namespace.
+		rtest.ExpectResource(rtest.GetResource(toCreate, "mimir-auth", common.TigeraPrometheusNamespace, "", "v1", "Secret"), "mimir-auth", common.TigeraPrometheusNamespace, "", "v1", "Secret")
+		rtest.ExpectResource(rtest.GetResource(toCreate, "cortex-auth", common.TigeraPrometheusNamespace, "", "v1", "Secret"), "cortex-auth", common.TigeraPrometheusNamespace, "", "v1", "Secret")
+
+		prometheusObj, ok := rtest.GetResource(toCreate, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PrometheusesKind).(*monitoringv1.Prometheus)
+		Expect(ok).To(BeTrue())
+		Expect(prometheusObj.Spec.RemoteWrite).To(HaveLen(3))
+		Expect(prometheusObj.Spec.Secrets).To(ConsistOf("cortex-auth"))
+
+		mimir := prometheusObj.Spec.RemoteWrite[0]
+		Expect(mimir.URL).To(Equal("https://mimir.example.org/api/v1/push"))
+		Expect(mimir.Name).To(Equal("mimir"))
+		Expect(mimir.WriteRelabelConfigs).To(Equal([]monitoringv1.RelabelConfig{
+			{
+				SourceLabels: []string{"__name__"},
+				Regex:        "felix_.*",
+				Action:       "keep",
+			},
+		}))
+		Expect(mimir.QueueConfig).To(Equal(&monitoringv1.QueueConfig{Capacity: 5000, BatchSendDeadline: "10s"}))
+		Expect(mimir.BearerTokenFile).To(BeEmpty())
+		Expect(mimir.BasicAuth).NotTo(BeNil())
+		Expect(mimir.BasicAuth.Username.Name).To(Equal("mimir-auth"))
+		Expect(mimir.BasicAuth.Username.Key).To(Equal(monitor.RemoteWriteUsernameKey))
+		Expect(mimir.BasicAuth.Password.Name).To(Equal("mimir-auth"))
+		Expect(mimir.BasicAuth.Password.Key).To(Equal(monitor.RemoteWritePasswordKey))
+
+		cortex := prometheusObj.Spec.RemoteWrite[1]
+		Expect(cortex.BasicAuth).To(BeNil())
+		Expect(cortex.BearerTokenFile).To(Equal("/etc/prometheus/secrets/cortex-auth/token"))
+
+		thanos := prometheusObj.Spec.RemoteWrite[2]
+		Expect(thanos.BasicAuth).To(BeNil())
+		Expect(thanos.BearerTokenFile).To(BeEmpty())
+	})
+
+	It("Should render Prometheus with a Thanos sidecar uploading to object storage", func() {
+		cfg.Monitor = operatorv1.MonitorSpec{
+			Prometheus: &operatorv1.Prometheus{
+				Thanos: &operatorv1.PrometheusThanos{
+					ObjectStorageConfigSecretName: "thanos-objstore",
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{"memory": resource.MustParse("256Mi")},
+					},
+				},
+			},
+		}
+		cfg.ThanosObjectStorageSecret = &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "thanos-objstore", Namespace: common.OperatorNamespace()},
+			Data:       map[string][]byte{monitor.ThanosObjectStorageConfigKey: []byte("type: S3")},
+		}
+		component := monitor.Monitor(cfg)
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, _ := component.Objects()
+
+		rtest.ExpectResource(rtest.GetResource(toCreate, "thanos-objstore", common.TigeraPrometheusNamespace, "", "v1", "Secret"), "thanos-objstore", common.TigeraPrometheusNamespace, "", "v1", "Secret")
+
+		prometheusObj, ok := rtest.GetResource(toCreate, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PrometheusesKind).(*monitoringv1.Prometheus)
+		Expect(ok).To(BeTrue())
+		Expect(prometheusObj.Spec.Thanos).NotTo(BeNil())
+		Expect(*prometheusObj.Spec.Thanos.Image).To(Equal(fmt.Sprintf("quay.io/%s:%s", components.ComponentThanos.Image, components.ComponentThanos.Version)))
+		Expect(*prometheusObj.Spec.Thanos.Version).To(Equal(components.ComponentThanos.Version))
+		Expect(prometheusObj.Spec.Thanos.Resources.Requests.Memory().Equal(resource.MustParse("256Mi"))).To(BeTrue())
+		Expect(prometheusObj.Spec.Thanos.ObjectStorageConfig).To(Equal(&corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "thanos-objstore"},
+			Key:                  monitor.ThanosObjectStorageConfigKey,
+		}))
+	})
+
+	It("Should render Prometheus without a Thanos sidecar by default", func() {
+		component := monitor.Monitor(cfg)
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, _ := component.Objects()
+
+		prometheusObj, ok := rtest.GetResource(toCreate, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PrometheusesKind).(*monitoringv1.Prometheus)
+		Expect(ok).To(BeTrue())
+		Expect(prometheusObj.Spec.Thanos).To(BeNil())
+	})
+
+	It("Should render highly available Prometheus replicas", func() {
+		replicas := int32(2)
+		cfg.Monitor = operatorv1.MonitorSpec{
+			Prometheus: &operatorv1.Prometheus{
+				Replicas:       &replicas,
+				ExternalLabels: map[string]string{"cluster": "cluster-a"},
+			},
+		}
+		component := monitor.Monitor(cfg)
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, toDelete := component.Objects()
+
+		Expect(rtest.GetResource(toDelete, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "policy", "v1beta1", "PodDisruptionBudget")).To(BeNil())
+		pdbObj, ok := rtest.GetResource(toCreate, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "policy", "v1beta1", "PodDisruptionBudget").(*policyv1beta1.PodDisruptionBudget)
+		Expect(ok).To(BeTrue())
+		Expect(pdbObj.Spec.MaxUnavailable.IntValue()).To(Equal(1))
+		Expect(pdbObj.Spec.Selector.MatchLabels).To(Equal(map[string]string{"prometheus": monitor.CalicoNodePrometheus}))
+
+		prometheusObj, ok := rtest.GetResource(toCreate, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PrometheusesKind).(*monitoringv1.Prometheus)
+		Expect(ok).To(BeTrue())
+		Expect(*prometheusObj.Spec.Replicas).To(Equal(replicas))
+		Expect(*prometheusObj.Spec.ReplicaExternalLabelName).To(Equal("prometheus_replica"))
+		Expect(prometheusObj.Spec.ExternalLabels).To(Equal(map[string]string{"cluster": "cluster-a"}))
+		Expect(prometheusObj.Spec.Affinity).NotTo(BeNil())
+		terms := prometheusObj.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].PodAffinityTerm.LabelSelector.MatchLabels).To(Equal(map[string]string{"prometheus": monitor.CalicoNodePrometheus}))
+		Expect(terms[0].PodAffinityTerm.TopologyKey).To(Equal("kubernetes.io/hostname"))
+	})
+
+	It("Should render a single Prometheus replica by default", func() {
+		component := monitor.Monitor(cfg)
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, _ := component.Objects()
+
+		Expect(rtest.GetResource(toCreate, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "policy", "v1beta1", "PodDisruptionBudget")).To(BeNil())
+		prometheusObj, ok := rtest.GetResource(toCreate, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PrometheusesKind).(*monitoringv1.Prometheus)
+		Expect(ok).To(BeTrue())
+		Expect(prometheusObj.Spec.Replicas).To(BeNil())
+		Expect(prometheusObj.Spec.ReplicaExternalLabelName).To(BeNil())
+		Expect(prometheusObj.Spec.Affinity).To(BeNil())
+	})
+
+	It("Should render the scrape overrides configured in the Monitor", func() {
+		relabelConfigs := []operatorv1.RelabelConfig{
+			{
+				SourceLabels: []string{"__name__"},
+				Regex:        "felix_bpf_.*",
+				Action:       operatorv1.RelabelActionDrop,
+			},
+		}
+		cfg.Monitor = operatorv1.MonitorSpec{
+			ScrapeTargets: []operatorv1.ScrapeTarget{
+				{
+					Name:                 operatorv1.ScrapeTargetCalicoNode,
+					Interval:             "30s",
+					MetricRelabelConfigs: relabelConfigs,
+				},
+				{
+					Name:     operatorv1.ScrapeTargetFluentdMetrics,
+					Interval: "2s",
+				},
+				{
+					Name:          operatorv1.ScrapeTargetElasticsearchMetrics,
+					Interval:      "1m",
+					ScrapeTimeout: "20s",
+				},
+			},
+		}
+		component := monitor.Monitor(cfg)
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, _ := component.Objects()
+
+		calicoNodeObj, ok := rtest.GetResource(toCreate, monitor.CalicoNodeMonitor, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.ServiceMonitorsKind).(*monitoringv1.ServiceMonitor)
+		Expect(ok).To(BeTrue())
+		Expect(calicoNodeObj.Spec.Endpoints).To(HaveLen(2))
+		for _, endpoint := range calicoNodeObj.Spec.Endpoints {
+			Expect(endpoint.Interval).To(Equal("30s"))
+			Expect(endpoint.ScrapeTimeout).To(Equal("5s"))
+			Expect(endpoint.MetricRelabelConfigs).To(Equal([]*monitoringv1.RelabelConfig{
+				{
+					SourceLabels: []string{"__name__"},
+					Regex:        "felix_bpf_.*",
+					Action:       "drop",
+				},
+			}))
+		}
+
+		// The scrape timeout is capped to the interval.
+		fluentdObj, ok := rtest.GetResource(toCreate, monitor.FluentdMetrics, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PodMonitorsKind).(*monitoringv1.PodMonitor)
+		Expect(ok).To(BeTrue())
+		Expect(fluentdObj.Spec.PodMetricsEndpoints[0].Interval).To(Equal("2s"))
+		Expect(fluentdObj.Spec.PodMetricsEndpoints[0].ScrapeTimeout).To(Equal("2s"))
+		Expect(fluentdObj.Spec.PodMetricsEndpoints[0].MetricRelabelConfigs).To(BeNil())
+
+		esObj, ok := rtest.GetResource(toCreate, monitor.ElasticsearchMetrics, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.ServiceMonitorsKind).(*monitoringv1.ServiceMonitor)
+		Expect(ok).To(BeTrue())
+		Expect(esObj.Spec.Endpoints[0].Interval).To(Equal("1m"))
+		Expect(esObj.Spec.Endpoints[0].ScrapeTimeout).To(Equal("20s"))
+	})
+
+	It("Should reject a scrape timeout greater than the interval", func() {
+		Expect(monitor.ValidateScrapeTargets([]operatorv1.ScrapeTarget{
+			{Name: operatorv1.ScrapeTargetCalicoNode, Interval: "1d", ScrapeTimeout: "1m"},
+			{Name: operatorv1.ScrapeTargetFluentdMetrics, Interval: "2s"},
+			{Name: operatorv1.ScrapeTargetVPP, ScrapeTimeout: "5s"},
+		})).To(Succeed())
+
+		Expect(monitor.ValidateScrapeTargets([]operatorv1.ScrapeTarget{
+			{Name: operatorv1.ScrapeTargetCalicoNode, Interval: "30s", ScrapeTimeout: "1m"},
+		})).To(MatchError("the scrapeTimeout 1m of the scrape target CalicoNode is greater than its interval 30s"))
+
+		// The default interval applies when only the timeout is set.
+		Expect(monitor.ValidateScrapeTargets([]operatorv1.ScrapeTarget{
+			{Name: operatorv1.ScrapeTargetVPP, ScrapeTimeout: "10s"},
+		})).To(MatchError("the scrapeTimeout 10s of the scrape target VPP is greater than its interval 5s"))
+	})
+
+	DescribeTable("Should parse the durations of Prometheus",
+		func(s string, expected time.Duration, valid bool) {
+			d, err := monitor.ParseDuration(s)
+			if !valid {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(d).To(Equal(expected))
+		},
+		Entry("zero", "0", time.Duration(0), true),
+		Entry("milliseconds", "500ms", 500*time.Millisecond, true),
+		Entry("minutes and milliseconds", "1m500ms", time.Minute+500*time.Millisecond, true),
+		Entry("days and hours", "1d12h", 36*time.Hour, true),
+		Entry("weeks", "2w", 14*24*time.Hour, true),
+		Entry("empty", "", time.Duration(0), false),
+		Entry("units out of order", "1s1m", time.Duration(0), false),
+		Entry("fraction", "1.5s", time.Duration(0), false),
+		Entry("no unit", "10", time.Duration(0), false),
+	)
+
+	Context("VPP dataplane", func() {
+		BeforeEach(func() {
+			vpp := operatorv1.LinuxDataplaneVPP
+			cfg.Installation.CalicoNetwork = &operatorv1.CalicoNetworkSpec{LinuxDataplane: &vpp}
+		})
+
+		It("Should render the pod monitor scraping the VPP metrics", func() {
+			component := monitor.Monitor(cfg)
+			Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+			toCreate, toDelete := component.Objects()
+
+			Expect(toDelete).To(HaveLen(2))
+			podMonitor, ok := rtest.GetResource(toCreate, monitor.VPPMetrics, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PodMonitorsKind).(*monitoringv1.PodMonitor)
+			Expect(ok).To(BeTrue())
+			Expect(podMonitor.Spec.Selector.MatchLabels).To(Equal(map[string]string{render.VPPNodePodLabel: "true"}))
+			Expect(podMonitor.Spec.NamespaceSelector.MatchNames).To(Equal([]string{common.CalicoNamespace}))
+			Expect(podMonitor.Spec.PodMetricsEndpoints[0].Port).To(Equal(render.VPPMetricsPortName))
+		})
+	})
+
 	It("Should render Prometheus resources when Dex is enabled", func() {
 		authentication := &operatorv1.Authentication{
 			Spec: operatorv1.AuthenticationSpec{
@@ -390,7 +734,7 @@ var _ = Describe("monitor rendering tests", func() {
 			rtest.ExpectResource(obj, expectedRes.name, expectedRes.ns, expectedRes.group, expectedRes.version, expectedRes.kind)
 		}
 
-		Expect(toDelete).To(HaveLen(1))
+		Expect(toDelete).To(HaveLen(3))
 
 		// Prometheus
 		prometheusObj, ok := rtest.GetResource(toCreate, monitor.CalicoNodePrometheus, common.TigeraPrometheusNamespace, "monitoring.coreos.com", "v1", monitoringv1.PrometheusesKind).(*monitoringv1.Prometheus)
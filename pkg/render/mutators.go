@@ -0,0 +1,92 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MutatedByAnnotation is set on the rendered objects changed by the mutators, to the comma separated names of the
+// mutators which changed them.
+const MutatedByAnnotation = "operator.tigera.io/mutated-by"
+
+// Mutator mutates the objects rendered by the components before they are applied, e.g. to inject a sidecar or to add
+// labels. Mutators are registered with RegisterMutator by the builds of the operator of the platform teams, typically
+// from an init function of a package imported by the main package. The objects are mutated every time they are
+// rendered, so the reconciles apply the mutated objects rather than reverting the mutations.
+type Mutator interface {
+	// Name is the name of the mutator, which is recorded in the MutatedByAnnotation of the objects it changes.
+	Name() string
+
+	// Mutate mutates the given object in place, and returns true if it changed it. It must change the object the
+	// same way every time it is rendered.
+	Mutate(obj client.Object) (bool, error)
+}
+
+var (
+	mutatorsLock sync.RWMutex
+	mutators     []Mutator
+)
+
+// RegisterMutator registers a mutator of the rendered objects. The mutators run in the order they are registered.
+// It returns a function unregistering the mutator.
+func RegisterMutator(m Mutator) func() {
+	mutatorsLock.Lock()
+	defer mutatorsLock.Unlock()
+	mutators = append(mutators, m)
+
+	return func() {
+		mutatorsLock.Lock()
+		defer mutatorsLock.Unlock()
+		for i := range mutators {
+			if mutators[i] == m {
+				mutators = append(mutators[:i:i], mutators[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// MutateObject runs the registered mutators on a rendered object, and records the mutators which changed it in its
+// MutatedByAnnotation.
+func MutateObject(obj client.Object) error {
+	mutatorsLock.RLock()
+	defer mutatorsLock.RUnlock()
+
+	var names []string
+	for _, m := range mutators {
+		changed, err := m.Mutate(obj)
+		if err != nil {
+			return fmt.Errorf("mutator %s failed to mutate %s %s/%s: %w", m.Name(),
+				obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+		if changed {
+			names = append(names, m.Name())
+		}
+	}
+	if len(names) > 0 {
+		annotations := map[string]string{}
+		for k, v := range obj.GetAnnotations() {
+			annotations[k] = v
+		}
+		annotations[MutatedByAnnotation] = strings.Join(names, ",")
+		obj.SetAnnotations(annotations)
+	}
+	return nil
+}
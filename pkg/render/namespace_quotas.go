@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/provider"
+	"github.com/tigera/operator/pkg/render/common/resourcequota"
+)
+
+// CalicoLimitRangeName is the name of the LimitRange of the calico-system namespace.
+const CalicoLimitRangeName = "calico-limits"
+
+// CalicoNamespaceQuotas renders the ResourceQuota and the LimitRange of the calico-system namespace, or deletes them
+// when they are disabled. The ResourceQuota of the critical pods is always rendered on GKE, which limits them by
+// default.
+func CalicoNamespaceQuotas(installation *operatorv1.InstallationSpec) Component {
+	return &calicoNamespaceQuotasComponent{installation: installation}
+}
+
+type calicoNamespaceQuotasComponent struct {
+	installation *operatorv1.InstallationSpec
+}
+
+func (c *calicoNamespaceQuotasComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on a ResourceQuota or a LimitRange
+	return nil
+}
+
+func (c *calicoNamespaceQuotasComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *calicoNamespaceQuotasComponent) Objects() ([]client.Object, []client.Object) {
+	var toCreate, toDelete []client.Object
+	enabled := c.installation.NamespaceQuotas != nil && *c.installation.NamespaceQuotas == operatorv1.NamespaceQuotasEnabled
+
+	criticalPriorityClasses := []string{NodePriorityClassName, ClusterPriorityClassName}
+	quota := resourcequota.ResourceQuotaForPriorityClassScope(resourcequota.CalicoCriticalResourceQuotaName,
+		common.CalicoNamespace, criticalPriorityClasses)
+	if enabled || provider.For(c.installation.KubernetesProvider).CriticalPodsQuota() {
+		toCreate = append(toCreate, quota)
+	} else {
+		toDelete = append(toDelete, quota)
+	}
+
+	var limitRange *corev1.LimitRange
+	if enabled {
+		var requirements []*corev1.ResourceRequirements
+		for _, cr := range c.installation.ComponentResources {
+			requirements = append(requirements, cr.ResourceRequirements)
+		}
+		limitRange = resourcequota.LimitRangeForResourceRequirements(CalicoLimitRangeName, common.CalicoNamespace, requirements)
+	}
+	if limitRange != nil {
+		toCreate = append(toCreate, limitRange)
+	} else {
+		toDelete = append(toDelete, &corev1.LimitRange{
+			TypeMeta:   metav1.TypeMeta{Kind: "LimitRange", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: CalicoLimitRangeName, Namespace: common.CalicoNamespace},
+		})
+	}
+	return toCreate, toDelete
+}
+
+func (c *calicoNamespaceQuotasComponent) Ready() bool {
+	return true
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("Calico namespace quotas rendering tests", func() {
+	var installation *operatorv1.InstallationSpec
+	BeforeEach(func() {
+		enabled := operatorv1.NamespaceQuotasEnabled
+		installation = &operatorv1.InstallationSpec{
+			NamespaceQuotas: &enabled,
+			ComponentResources: []operatorv1.ComponentResource{
+				{
+					ComponentName: operatorv1.ComponentNameNode,
+					ResourceRequirements: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+						Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+				{
+					ComponentName: operatorv1.ComponentNameTypha,
+					ResourceRequirements: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+					},
+				},
+			},
+		}
+	})
+
+	It("should render the resource quota and the limit range", func() {
+		toCreate, toDelete := render.CalicoNamespaceQuotas(installation).Objects()
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(2))
+
+		rq := rtest.GetResource(toCreate, "calico-critical-pods", common.CalicoNamespace, "", "v1", "ResourceQuota").(*corev1.ResourceQuota)
+		Expect(rq.Spec.ScopeSelector.MatchExpressions[0].Values).To(ConsistOf(render.NodePriorityClassName, render.ClusterPriorityClassName))
+
+		lr := rtest.GetResource(toCreate, render.CalicoLimitRangeName, common.CalicoNamespace, "", "v1", "LimitRange").(*corev1.LimitRange)
+		Expect(lr.Spec.Limits).To(HaveLen(1))
+		item := lr.Spec.Limits[0]
+		Expect(item.Type).To(Equal(corev1.LimitTypeContainer))
+		Expect(item.DefaultRequest.Cpu().String()).To(Equal("250m"))
+		Expect(item.DefaultRequest.Memory().String()).To(Equal("128Mi"))
+		Expect(item.Default.Cpu().String()).To(Equal("250m"))
+		Expect(item.Default.Memory().String()).To(Equal("128Mi"))
+	})
+
+	It("should default the requests of the limit range to the limits of the components without requests", func() {
+		installation.ComponentResources = []operatorv1.ComponentResource{{
+			ComponentName: operatorv1.ComponentNameKubeControllers,
+			ResourceRequirements: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		}}
+		toCreate, _ := render.CalicoNamespaceQuotas(installation).Objects()
+
+		lr := rtest.GetResource(toCreate, render.CalicoLimitRangeName, common.CalicoNamespace, "", "v1", "LimitRange").(*corev1.LimitRange)
+		Expect(lr.Spec.Limits[0].DefaultRequest.Memory().String()).To(Equal("512Mi"))
+		Expect(lr.Spec.Limits[0].Default.Memory().String()).To(Equal("512Mi"))
+	})
+
+	It("should not render the limit range without component resources", func() {
+		installation.ComponentResources = nil
+		toCreate, toDelete := render.CalicoNamespaceQuotas(installation).Objects()
+		Expect(toCreate).To(HaveLen(1))
+		Expect(toDelete).To(HaveLen(1))
+		rtest.ExpectResource(toDelete[0], render.CalicoLimitRangeName, common.CalicoNamespace, "", "v1", "LimitRange")
+	})
+
+	It("should delete the resource quota and the limit range when disabled, except for the resource quota on GKE", func() {
+		installation.NamespaceQuotas = nil
+		toCreate, toDelete := render.CalicoNamespaceQuotas(installation).Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(2))
+		Expect(rtest.GetResource(toDelete, "calico-critical-pods", common.CalicoNamespace, "", "v1", "ResourceQuota")).NotTo(BeNil())
+		Expect(rtest.GetResource(toDelete, render.CalicoLimitRangeName, common.CalicoNamespace, "", "v1", "LimitRange")).NotTo(BeNil())
+
+		installation.KubernetesProvider = operatorv1.ProviderGKE
+		toCreate, toDelete = render.CalicoNamespaceQuotas(installation).Objects()
+		Expect(toCreate).To(HaveLen(1))
+		rtest.ExpectResource(toCreate[0], "calico-critical-pods", common.CalicoNamespace, "", "v1", "ResourceQuota")
+		Expect(toDelete).To(HaveLen(1))
+		rtest.ExpectResource(toDelete[0], render.CalicoLimitRangeName, common.CalicoNamespace, "", "v1", "LimitRange")
+	})
+})
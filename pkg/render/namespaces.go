@@ -16,6 +16,7 @@ package render
 
 import (
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/provider"
 	"github.com/tigera/operator/pkg/render/common/secret"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -69,7 +70,7 @@ func (c *namespaceComponent) Ready() bool {
 	return true
 }
 
-func CreateNamespace(name string, provider operatorv1.Provider) *corev1.Namespace {
+func CreateNamespace(name string, p operatorv1.Provider) *corev1.Namespace {
 	ns := &corev1.Namespace{
 		TypeMeta: metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{
@@ -79,12 +80,6 @@ func CreateNamespace(name string, provider operatorv1.Provider) *corev1.Namespac
 		},
 	}
 
-	switch provider {
-	case operatorv1.ProviderOpenShift:
-		ns.Labels["openshift.io/run-level"] = "0"
-		ns.Annotations["openshift.io/node-selector"] = ""
-	case operatorv1.ProviderAKS:
-		ns.Labels["control-plane"] = "true"
-	}
+	provider.For(p).DecorateNamespace(ns)
 	return ns
 }
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+// NetworkSetLabel is set on the GlobalNetworkSets rendered from the network sets of the APIServer, so that the ones
+// which are no longer declared can be found and deleted.
+const NetworkSetLabel = "operator.tigera.io/network-set"
+
+// NetworkSets renders the given network sets as GlobalNetworkSets. The existing GlobalNetworkSets with the given names
+// which are not declared anymore are deleted.
+func NetworkSets(sets []operatorv1.NetworkSet, existing []string) Component {
+	return &networkSetsComponent{sets: sets, existing: existing}
+}
+
+type networkSetsComponent struct {
+	sets     []operatorv1.NetworkSet
+	existing []string
+}
+
+func (c *networkSetsComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on a GlobalNetworkSet
+	return nil
+}
+
+func (c *networkSetsComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *networkSetsComponent) Objects() ([]client.Object, []client.Object) {
+	declared := map[string]bool{}
+	var toCreate []client.Object
+	for _, s := range c.sets {
+		declared[s.Name] = true
+		labels := map[string]string{}
+		for k, v := range s.Labels {
+			labels[k] = v
+		}
+		labels[NetworkSetLabel] = "true"
+		toCreate = append(toCreate, &v3.GlobalNetworkSet{
+			TypeMeta:   metav1.TypeMeta{Kind: "GlobalNetworkSet", APIVersion: "projectcalico.org/v3"},
+			ObjectMeta: metav1.ObjectMeta{Name: s.Name, Labels: labels},
+			Spec:       v3.GlobalNetworkSetSpec{Nets: s.Nets},
+		})
+	}
+
+	var toDelete []client.Object
+	for _, name := range c.existing {
+		if !declared[name] {
+			toDelete = append(toDelete, &v3.GlobalNetworkSet{
+				TypeMeta:   metav1.TypeMeta{Kind: "GlobalNetworkSet", APIVersion: "projectcalico.org/v3"},
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+			})
+		}
+	}
+	return toCreate, toDelete
+}
+
+func (c *networkSetsComponent) Ready() bool {
+	return true
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("Network sets rendering tests", func() {
+	sets := []operatorv1.NetworkSet{
+		{Name: "corporate", Labels: map[string]string{"role": "corporate"}, Nets: []string{"10.0.0.0/8", "192.168.0.0/16"}},
+		{Name: "proxies", Nets: []string{"172.16.1.10/32"}},
+	}
+
+	It("should render a GlobalNetworkSet per network set", func() {
+		component := render.NetworkSets(sets, nil)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, toDelete := component.Objects()
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(2))
+
+		gns := rtest.GetResource(toCreate, "corporate", "", "projectcalico.org", "v3", "GlobalNetworkSet").(*v3.GlobalNetworkSet)
+		Expect(gns.Labels).To(Equal(map[string]string{"role": "corporate", render.NetworkSetLabel: "true"}))
+		Expect(gns.Spec.Nets).To(Equal([]string{"10.0.0.0/8", "192.168.0.0/16"}))
+
+		gns = rtest.GetResource(toCreate, "proxies", "", "projectcalico.org", "v3", "GlobalNetworkSet").(*v3.GlobalNetworkSet)
+		Expect(gns.Labels).To(Equal(map[string]string{render.NetworkSetLabel: "true"}))
+	})
+
+	It("should delete the GlobalNetworkSets which are not declared anymore", func() {
+		toCreate, toDelete := render.NetworkSets(sets, []string{"corporate", "old"}).Objects()
+		Expect(toCreate).To(HaveLen(2))
+		Expect(toDelete).To(HaveLen(1))
+		rtest.ExpectResource(toDelete[0], "old", "", "projectcalico.org", "v3", "GlobalNetworkSet")
+	})
+})
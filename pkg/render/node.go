@@ -20,6 +20,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tigera/operator/pkg/ptr"
 
@@ -40,6 +41,7 @@ import (
 	"github.com/tigera/operator/pkg/render/common/configmap"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
+	"github.com/tigera/operator/pkg/render/common/provider"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
 
@@ -55,10 +57,24 @@ const (
 	BGPLayoutVolumeName               = "bgp-layout"
 	BGPLayoutPath                     = "/etc/calico/early-networking.yaml"
 	K8sSvcEndpointConfigMapName       = "kubernetes-services-endpoint"
+	FelixMetricsServiceName           = "calico-felix-metrics"
 	nodeTerminationGracePeriodSeconds = 5
+
+	// The number of 2MB hugepages reserved by node-init for VPP.
+	nodeInitVPPHugepages = "512"
+
+	// The name of the chained CNI config, which takes precedence over the config installed along with the provider
+	// CNI plugin.
+	chainedCNIConfName = "05-calico-chained.conflist"
 )
 
 var (
+	// The capabilities of the calico-node container when running as non-privileged.
+	nodeNonPrivilegedCapabilities = []corev1.Capability{"NET_RAW", "NET_ADMIN", "NET_BIND_SERVICE"}
+
+	// The kernel modules loaded by node-init for the uplink drivers of VPP.
+	nodeInitVPPKernelModules = []string{"vfio-pci", "uio_pci_generic"}
+
 	// The port used by calico/node to report Calico Enterprise BGP metrics.
 	// This is currently not intended to be user configurable.
 	nodeBGPReporterPort int32 = 9900
@@ -84,6 +100,9 @@ type NodeConfiguration struct {
 	ClusterDomain string
 
 	// Optional fields.
+	// KubernetesVersion is the version of the cluster. The PodSecurityPolicy is only rendered when the cluster
+	// supports it, which is assumed when the version is unknown.
+	KubernetesVersion       *common.VersionInfo
 	AmazonCloudIntegration  *operatorv1.AmazonCloudIntegration
 	LogCollector            *operatorv1.LogCollector
 	MigrateNamespaces       bool
@@ -91,6 +110,10 @@ type NodeConfiguration struct {
 	BirdTemplates           map[string]string
 	NodeReporterMetricsPort int
 
+	// VPPHugepagesReserved is true when the VPPDataplane reserves the hugepages of the VPP nodes, in which case
+	// node-init doesn't.
+	VPPHugepagesReserved bool
+
 	// BGPLayouts is returned by the rendering code after modifying its namespace
 	// so that it can be deployed into the cluster.
 	// TODO: The controller should pass the contents, the renderer should build its own
@@ -186,6 +209,13 @@ func (c *nodeComponent) Objects() ([]client.Object, []client.Object) {
 		objsToCreate = append(objsToCreate, c.nodeMetricsService())
 	}
 
+	// Expose the felix prometheus metrics when they are enabled.
+	if c.cfg.Installation.NodeMetricsPort != nil {
+		objsToCreate = append(objsToCreate, c.felixMetricsService())
+	} else {
+		objsToDelete = append(objsToDelete, c.felixMetricsService())
+	}
+
 	cniConfig := c.nodeCNIConfigMap()
 	if cniConfig != nil {
 		objsToCreate = append(objsToCreate, cniConfig)
@@ -195,11 +225,11 @@ func (c *nodeComponent) Objects() ([]client.Object, []client.Object) {
 		objsToCreate = append(objsToCreate, btcm)
 	}
 
-	if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderDockerEE {
+	if c.provider().ClusterAdminForPrivilegedPods() {
 		objsToCreate = append(objsToCreate, c.clusterAdminClusterRoleBinding())
 	}
 
-	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+	if c.provider().PodSecurityPolicies() && c.cfg.KubernetesVersion.ProvidesPodSecurityPolicy() {
 		objsToCreate = append(objsToCreate, c.nodePodSecurityPolicy())
 	}
 
@@ -219,13 +249,18 @@ func (c *nodeComponent) Ready() bool {
 
 // nodeServiceAccount creates the node's service account.
 func (c *nodeComponent) nodeServiceAccount() *corev1.ServiceAccount {
-	return &corev1.ServiceAccount{
+	sa := &corev1.ServiceAccount{
 		TypeMeta: metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "calico-node",
 			Namespace: common.CalicoNamespace,
 		},
 	}
+	// Felix manages the ENIs of the node with the IAM role of the service account, when one is given.
+	if aws := awsSecondaryIPs(c.cfg.Installation); aws != nil && aws.IAMRoleARN != "" {
+		sa.Annotations = map[string]string{AWSIAMRoleAnnotation: aws.IAMRoleARN}
+	}
+	return sa
 }
 
 // nodeRoleBinding creates a clusterrolebinding giving the node service account the required permissions to operate.
@@ -430,7 +465,7 @@ func (c *nodeComponent) nodeRole() *rbacv1.ClusterRole {
 		}
 		role.Rules = append(role.Rules, extraRules...)
 	}
-	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+	if c.provider().PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		role.Rules = append(role.Rules, rbacv1.PolicyRule{
 			APIGroups:     []string{"policy"},
@@ -446,6 +481,9 @@ func (c *nodeComponent) nodeRole() *rbacv1.ClusterRole {
 // Returns nil if no configmap is needed.
 func (c *nodeComponent) nodeCNIConfigMap() *corev1.ConfigMap {
 	if c.cfg.Installation.CNI.Type != operatorv1.PluginCalico {
+		if c.cniChainingEnabled() {
+			return c.chainedCNIConfigMap()
+		}
 		// If calico cni is not being used, then no cni configmap is needed.
 		return nil
 	}
@@ -458,11 +496,7 @@ func (c *nodeComponent) nodeCNIConfigMap() *corev1.ConfigMap {
 	}
 
 	// Determine per-provider settings.
-	nodenameFileOptional := false
-	switch c.cfg.Installation.KubernetesProvider {
-	case operatorv1.ProviderDockerEE:
-		nodenameFileOptional = true
-	}
+	nodenameFileOptional := c.provider().NodenameFileOptional()
 
 	// Pull out other settings.
 	ipForward := false
@@ -540,6 +574,81 @@ func (c *nodeComponent) nodeCNIConfigMap() *corev1.ConfigMap {
 	}
 }
 
+// chainedCNIConfigMap returns a config map containing a CNI network config which chains the provider CNI plugin with
+// the bandwidth and portmap plugins. The IPAM of the provider plugin is used, Calico only enforces policy.
+func (c *nodeComponent) chainedCNIConfigMap() *corev1.ConfigMap {
+	var mtu int32
+	if m := getMTU(c.cfg.Installation); m != nil {
+		mtu = *m
+	}
+
+	var providerPlugin string
+	switch c.cfg.Installation.CNI.Type {
+	case operatorv1.PluginAmazonVPC:
+		if mtu == 0 {
+			mtu = 9001
+		}
+		providerPlugin = fmt.Sprintf(`{
+      "name": "aws-cni",
+      "type": "aws-cni",
+      "vethPrefix": "eni",
+      "mtu": "%d",
+      "pluginLogFile": "/var/log/aws-routed-eni/plugin.log",
+      "pluginLogLevel": "Info"
+    }`, mtu)
+	case operatorv1.PluginAzureVNET:
+		providerPlugin = `{
+      "type": "azure-vnet",
+      "mode": "transparent",
+      "ipam": {"type": "azure-vnet-ipam"}
+    }`
+	case operatorv1.PluginGKE:
+		if mtu == 0 {
+			mtu = 1460
+		}
+		providerPlugin = fmt.Sprintf(`{
+      "type": "ptp",
+      "mtu": %d,
+      "ipam": {
+        "type": "host-local",
+        "subnet": "usePodCidr",
+        "routes": [{"dst": "0.0.0.0/0"}]
+      }
+    }`, mtu)
+	}
+
+	// Determine portmap configuration to use.
+	var portmap string = ""
+	if cn := c.cfg.Installation.CalicoNetwork; cn != nil && cn.HostPorts != nil && *cn.HostPorts == operatorv1.HostPortsEnabled {
+		portmap = `,
+    {"type": "portmap", "snat": true, "capabilities": {"portMappings": true}}`
+	}
+
+	var config = fmt.Sprintf(`{
+  "name": "k8s-pod-network",
+  "cniVersion": "0.3.1",
+  "plugins": [
+    %s,
+    {
+      "type": "bandwidth",
+      "capabilities": {"bandwidth": true}
+    }%s
+  ]
+}`, providerPlugin, portmap)
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cni-config",
+			Namespace: common.CalicoNamespace,
+			Labels:    map[string]string{},
+		},
+		Data: map[string]string{
+			"config": config,
+		},
+	}
+}
+
 func (c *nodeComponent) getCalicoIPAM() string {
 	// Determine what address families to enable.
 	var assign_ipv4 string
@@ -559,8 +668,19 @@ func (c *nodeComponent) getCalicoIPAM() string {
 	)
 }
 
+// buildHostLocalIPAM returns the host-local IPAM configuration which assigns addresses from the pod CIDRs of the node,
+// for each address family with an IP pool.
 func buildHostLocalIPAM(cns *operatorv1.CalicoNetworkSpec) string {
-	return `{ "type": "host-local", "subnet": "usePodCidr"}`
+	v4pool := GetIPv4Pool(cns.IPPools)
+	v6pool := GetIPv6Pool(cns.IPPools)
+	switch {
+	case v4pool != nil && v6pool != nil:
+		return `{ "type": "host-local", "ranges": [[{"subnet": "usePodCidr"}], [{"subnet": "usePodCidrIPv6"}]]}`
+	case v6pool != nil:
+		return `{ "type": "host-local", "subnet": "usePodCidrIPv6"}`
+	default:
+		return `{ "type": "host-local", "subnet": "usePodCidr"}`
+	}
 }
 
 func (c *nodeComponent) birdTemplateConfigMap() *corev1.ConfigMap {
@@ -610,7 +730,17 @@ func (c *nodeComponent) clusterAdminClusterRoleBinding() *rbacv1.ClusterRoleBind
 // nodeDaemonset creates the node daemonset.
 func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.DaemonSet {
 	var terminationGracePeriod int64 = nodeTerminationGracePeriodSeconds
+	if p := nodeTerminationGracePeriod(c.cfg.Installation); p != nil {
+		terminationGracePeriod = *p
+	}
+	var minReadySeconds int32
+	if gr := bgpGracefulRestart(c.cfg.Installation); gr != nil && gr.ConvergenceSeconds != nil {
+		minReadySeconds = *gr.ConvergenceSeconds
+	}
 	var initContainers []corev1.Container
+	if c.nodeInitEnabled() {
+		initContainers = append(initContainers, c.nodeInitContainer())
+	}
 
 	annotations := make(map[string]string)
 	if len(c.cfg.BirdTemplates) != 0 {
@@ -651,7 +781,7 @@ func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.Daemo
 		annotations[bgpLayoutHashAnnotation] = rmeta.AnnotationHash(c.cfg.BGPLayouts.Data)
 	}
 
-	if c.cfg.Installation.FlexVolumePath != "None" {
+	if FlexVolumeDriverEnabled(c.cfg.Installation) {
 		initContainers = append(initContainers, c.flexVolumeContainer())
 	}
 
@@ -663,22 +793,7 @@ func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.Daemo
 		initContainers = append(initContainers, c.hostPathInitContainer())
 	}
 
-	var affinity *corev1.Affinity
-	if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderAKS {
-		affinity = &corev1.Affinity{
-			NodeAffinity: &corev1.NodeAffinity{
-				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
-					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
-						MatchExpressions: []corev1.NodeSelectorRequirement{{
-							Key:      "type",
-							Operator: corev1.NodeSelectorOpNotIn,
-							Values:   []string{"virtual-kubelet"},
-						}},
-					}},
-				},
-			},
-		}
-	}
+	affinity := c.provider().NodeAffinity()
 
 	// Determine the name to use for the calico/node daemonset. For mixed-mode, we run the enterprise DaemonSet
 	// with its own name so as to not conflict.
@@ -709,12 +824,15 @@ func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.Daemo
 					Volumes:                       c.nodeVolumes(),
 				},
 			},
-			UpdateStrategy: c.cfg.Installation.NodeUpdateStrategy,
+			UpdateStrategy:  c.nodeUpdateStrategy(),
+			MinReadySeconds: minReadySeconds,
 		},
 	}
 
-	if c.cfg.Installation.CNI.Type == operatorv1.PluginCalico {
+	if c.installCNI() {
 		ds.Spec.Template.Spec.InitContainers = append(ds.Spec.Template.Spec.InitContainers, c.cniContainer())
+	} else if c.cleanupChainedCNIConfig() {
+		ds.Spec.Template.Spec.InitContainers = append(ds.Spec.Template.Spec.InitContainers, c.cniCleanupContainer())
 	}
 
 	if c.collectProcessPathEnabled() {
@@ -728,23 +846,15 @@ func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.Daemo
 	return &ds
 }
 
+// provider returns the hooks of the Kubernetes provider of the installation.
+func (c *nodeComponent) provider() provider.Provider {
+	return provider.For(c.cfg.Installation.KubernetesProvider)
+}
+
 // cniDirectories returns the binary and network config directories for the configured platform.
 func (c *nodeComponent) cniDirectories() (string, string, string) {
-	var cniBinDir, cniNetDir, cniLogDir string
-	switch c.cfg.Installation.KubernetesProvider {
-	case operatorv1.ProviderOpenShift:
-		cniNetDir = "/var/run/multus/cni/net.d"
-		cniBinDir = "/var/lib/cni/bin"
-	case operatorv1.ProviderGKE:
-		// Used if we're installing a CNI plugin. If using the GKE plugin, these are not necessary.
-		cniBinDir = "/home/kubernetes/bin"
-		cniNetDir = "/etc/cni/net.d"
-	default:
-		// Default locations to match vanilla Kubernetes.
-		cniBinDir = "/opt/cni/bin"
-		cniNetDir = "/etc/cni/net.d"
-	}
-	cniLogDir = "/var/log/calico/cni"
+	cniNetDir, cniBinDir := c.provider().CNIDirectories()
+	cniLogDir := "/var/log/calico/cni"
 	return cniNetDir, cniBinDir, cniLogDir
 }
 
@@ -804,13 +914,17 @@ func (c *nodeComponent) nodeVolumes() []corev1.Volume {
 	}
 
 	// If needed for this configuration, then include the CNI volumes.
-	if c.cfg.Installation.CNI.Type == operatorv1.PluginCalico {
+	if c.installCNI() {
 		// Determine directories to use for CNI artifacts based on the provider.
 		cniNetDir, cniBinDir, cniLogDir := c.cniDirectories()
 		volumes = append(volumes, corev1.Volume{Name: "cni-bin-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: cniBinDir}}})
 		volumes = append(volumes, corev1.Volume{Name: "cni-net-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: cniNetDir}}})
 		volumes = append(volumes, corev1.Volume{Name: "cni-log-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: cniLogDir}}})
 	}
+	if c.cleanupChainedCNIConfig() {
+		cniNetDir, _, _ := c.cniDirectories()
+		volumes = append(volumes, corev1.Volume{Name: "cni-net-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: cniNetDir}}})
+	}
 
 	// Override with Tigera-specific config.
 	if c.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
@@ -823,7 +937,7 @@ func (c *nodeComponent) nodeVolumes() []corev1.Volume {
 	}
 
 	// Create and append flexvolume
-	if c.cfg.Installation.FlexVolumePath != "None" {
+	if FlexVolumeDriverEnabled(c.cfg.Installation) {
 		volumes = append(volumes, corev1.Volume{
 			Name: "flexvol-driver-host",
 			VolumeSource: corev1.VolumeSource{
@@ -862,6 +976,16 @@ func (c *nodeComponent) nodeVolumes() []corev1.Volume {
 	return volumes
 }
 
+// nodeUpdateStrategy returns the update strategy of the calico-node daemonset. The pods are deleted by the operator
+// when canary rollouts are enabled, so that the canary nodes are updated first, and while the nodes are migrated to
+// the VPP dataplane, so that they are updated one at a time.
+func (c *nodeComponent) nodeUpdateStrategy() appsv1.DaemonSetUpdateStrategy {
+	if c.cfg.Installation.NodeCanaryRollout != nil || c.cfg.Installation.DataplaneMigration != nil {
+		return appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
+	}
+	return c.cfg.Installation.NodeUpdateStrategy
+}
+
 func (c *nodeComponent) bpfDataplaneEnabled() bool {
 	return c.cfg.Installation.CalicoNetwork != nil &&
 		c.cfg.Installation.CalicoNetwork.LinuxDataplane != nil &&
@@ -874,12 +998,42 @@ func (c *nodeComponent) vppDataplaneEnabled() bool {
 		*c.cfg.Installation.CalicoNetwork.LinuxDataplane == operatorv1.LinuxDataplaneVPP
 }
 
+// cniChainingEnabled returns true if the provider CNI plugin is chained with the plugins installed by Calico.
+func (c *nodeComponent) cniChainingEnabled() bool {
+	return c.cfg.Installation.CNI.Type != operatorv1.PluginCalico &&
+		c.cfg.Installation.CNI.Chaining != nil &&
+		*c.cfg.Installation.CNI.Chaining == operatorv1.CNIChainingEnabled
+}
+
+// cleanupChainedCNIConfig returns true if the chained CNI config, installed while chaining was enabled, must be removed
+// from the CNI config directory of the hosts.
+func (c *nodeComponent) cleanupChainedCNIConfig() bool {
+	return c.cfg.Installation.CNI.Type != operatorv1.PluginCalico && !c.cniChainingEnabled()
+}
+
+// installCNI returns true if the CNI plugins and network config are installed by the install-cni init container.
+func (c *nodeComponent) installCNI() bool {
+	return c.cfg.Installation.CNI.Type == operatorv1.PluginCalico || c.cniChainingEnabled()
+}
+
 func (c *nodeComponent) collectProcessPathEnabled() bool {
 	return c.cfg.LogCollector != nil &&
 		c.cfg.LogCollector.Spec.CollectProcessPath != nil &&
 		*c.cfg.LogCollector.Spec.CollectProcessPath == operatorv1.CollectProcessPathEnable
 }
 
+// cniCleanupContainer creates the node's init container that removes the chained CNI config. Once chaining is disabled,
+// the config would otherwise be left on the hosts and keep taking precedence over the config of the provider CNI plugin.
+func (c *nodeComponent) cniCleanupContainer() corev1.Container {
+	return corev1.Container{
+		Name:            "cni-cleanup",
+		Image:           c.nodeImage,
+		Command:         []string{"rm", "-f", "/host/etc/cni/net.d/" + chainedCNIConfName},
+		VolumeMounts:    []corev1.VolumeMount{{MountPath: "/host/etc/cni/net.d", Name: "cni-net-dir"}},
+		SecurityContext: nonPrivilegedInitSecurityContext(),
+	}
+}
+
 // cniContainer creates the node's init container that installs CNI.
 func (c *nodeComponent) cniContainer() corev1.Container {
 	// Determine environment to pass to the CNI init container.
@@ -889,15 +1043,19 @@ func (c *nodeComponent) cniContainer() corev1.Container {
 		{MountPath: "/host/etc/cni/net.d", Name: "cni-net-dir"},
 	}
 
+	sc := &corev1.SecurityContext{Privileged: ptr.BoolToPtr(true)}
+	if c.runAsNonPrivileged() {
+		// Installing the CNI binaries and config only needs root access to the host directories.
+		sc = nonPrivilegedInitSecurityContext()
+	}
+
 	return corev1.Container{
-		Name:         "install-cni",
-		Image:        c.cniImage,
-		Command:      []string{"/opt/cni/bin/install"},
-		Env:          cniEnv,
-		VolumeMounts: cniVolumeMounts,
-		SecurityContext: &corev1.SecurityContext{
-			Privileged: ptr.BoolToPtr(true),
-		},
+		Name:            "install-cni",
+		Image:           c.cniImage,
+		Command:         []string{"/opt/cni/bin/install"},
+		Env:             cniEnv,
+		VolumeMounts:    cniVolumeMounts,
+		SecurityContext: sc,
 	}
 }
 
@@ -908,13 +1066,16 @@ func (c *nodeComponent) flexVolumeContainer() corev1.Container {
 		{MountPath: "/host/driver", Name: "flexvol-driver-host"},
 	}
 
+	sc := &corev1.SecurityContext{Privileged: ptr.BoolToPtr(true)}
+	if c.runAsNonPrivileged() {
+		sc = nonPrivilegedInitSecurityContext()
+	}
+
 	return corev1.Container{
-		Name:         "flexvol-driver",
-		Image:        c.flexvolImage,
-		VolumeMounts: flexVolumeMounts,
-		SecurityContext: &corev1.SecurityContext{
-			Privileged: ptr.BoolToPtr(true),
-		},
+		Name:            "flexvol-driver",
+		Image:           c.flexvolImage,
+		VolumeMounts:    flexVolumeMounts,
+		SecurityContext: sc,
 	}
 }
 
@@ -945,17 +1106,88 @@ func (c *nodeComponent) bpffsInitContainer() corev1.Container {
 	}
 }
 
+// nodeInitEnabled returns true if the node-init init container prepares the hosts. It runs by default on OpenShift,
+// unless calico-node runs as non-privileged since it needs to write the kernel parameters of the hosts.
+func (c *nodeComponent) nodeInitEnabled() bool {
+	if ni := c.cfg.Installation.NodeInit; ni != nil && ni.State != nil {
+		return *ni.State == operatorv1.NodeInitEnabled
+	}
+	return c.provider().NodeInitByDefault() && !c.runAsNonPrivileged()
+}
+
+// nodeInitSysctls returns the kernel parameters set by node-init: the ones needed by the dataplane, overridden and
+// extended by the ones of the installation. The hugepages of VPP are left to the VPPDataplane when it reserves them.
+func (c *nodeComponent) nodeInitSysctls() []operatorv1.Sysctl {
+	sysctls := []operatorv1.Sysctl{{Key: "net.ipv4.ip_forward", Value: "1"}}
+	if cn := c.cfg.Installation.CalicoNetwork; cn != nil && GetIPv6Pool(cn.IPPools) != nil {
+		sysctls = append(sysctls, operatorv1.Sysctl{Key: "net.ipv6.conf.all.forwarding", Value: "1"})
+	}
+	if c.vppDataplaneEnabled() && !c.cfg.VPPHugepagesReserved {
+		sysctls = append(sysctls, operatorv1.Sysctl{Key: "vm.nr_hugepages", Value: nodeInitVPPHugepages})
+	}
+
+	if c.cfg.Installation.NodeInit == nil {
+		return sysctls
+	}
+	for _, override := range c.cfg.Installation.NodeInit.Sysctls {
+		found := false
+		for i := range sysctls {
+			if sysctls[i].Key == override.Key {
+				sysctls[i].Value = override.Value
+				found = true
+			}
+		}
+		if !found {
+			sysctls = append(sysctls, override)
+		}
+	}
+	return sysctls
+}
+
+// nodeInitContainer creates the init container that prepares the hosts before calico-node starts. The RHCOS nodes
+// of OpenShift can't be prepared by hand, so it sets the kernel parameters and, with the VPP dataplane, loads the
+// kernel modules of the uplink drivers.
+func (c *nodeComponent) nodeInitContainer() corev1.Container {
+	script := []string{"set -e"}
+	for _, s := range c.nodeInitSysctls() {
+		script = append(script, fmt.Sprintf("echo '%s' > /proc/sys/%s", s.Value, strings.ReplaceAll(s.Key, ".", "/")))
+	}
+	if c.vppDataplaneEnabled() {
+		for _, m := range nodeInitVPPKernelModules {
+			script = append(script, fmt.Sprintf("modprobe %s", m))
+		}
+	}
+
+	return corev1.Container{
+		Name:  "node-init",
+		Image: c.nodeImage,
+		VolumeMounts: []corev1.VolumeMount{
+			{MountPath: "/lib/modules", Name: "lib-modules", ReadOnly: true},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: ptr.BoolToPtr(true),
+		},
+		Command: []string{"sh", "-c", strings.Join(script, "\n")},
+	}
+}
+
 // cniEnvvars creates the CNI container's envvars.
 func (c *nodeComponent) cniEnvvars() []corev1.EnvVar {
-	if c.cfg.Installation.CNI.Type != operatorv1.PluginCalico {
+	if !c.installCNI() {
 		return []corev1.EnvVar{}
 	}
 
 	// Determine directories to use for CNI artifacts based on the provider.
 	cniNetDir, _, _ := c.cniDirectories()
 
+	// The chained config must take precedence over the config installed along with the provider CNI plugin.
+	cniConfName := "10-calico.conflist"
+	if c.cniChainingEnabled() {
+		cniConfName = chainedCNIConfName
+	}
+
 	envVars := []corev1.EnvVar{
-		{Name: "CNI_CONF_NAME", Value: "10-calico.conflist"},
+		{Name: "CNI_CONF_NAME", Value: cniConfName},
 		{Name: "SLEEP", Value: "false"},
 		{Name: "CNI_NET_DIR", Value: cniNetDir},
 		{
@@ -993,14 +1225,12 @@ func (c *nodeComponent) nodeContainer() corev1.Container {
 			// Set the user as our chosen user (999)
 			RunAsUser: &uid,
 			// Set the group to be the root user group since all container users should be a member
-			RunAsGroup: &guid,
-			Privileged: ptr.BoolToPtr(false),
+			RunAsGroup:               &guid,
+			Privileged:               ptr.BoolToPtr(false),
+			AllowPrivilegeEscalation: ptr.BoolToPtr(false),
 			Capabilities: &corev1.Capabilities{
-				Add: []corev1.Capability{
-					corev1.Capability("NET_RAW"),
-					corev1.Capability("NET_ADMIN"),
-					corev1.Capability("NET_BIND_SERVICE"),
-				},
+				Add:  nodeNonPrivilegedCapabilities,
+				Drop: []corev1.Capability{"ALL"},
 			},
 		}
 	}
@@ -1104,15 +1334,8 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 
 	// Note: Felix now activates certain special-case logic based on the provider in the cluster type; avoid changing
 	// these unless you also update Felix's parsing logic.
-	switch c.cfg.Installation.KubernetesProvider {
-	case operatorv1.ProviderOpenShift:
-		clusterType = clusterType + ",openshift"
-	case operatorv1.ProviderEKS:
-		clusterType = clusterType + ",ecs"
-	case operatorv1.ProviderGKE:
-		clusterType = clusterType + ",gke"
-	case operatorv1.ProviderAKS:
-		clusterType = clusterType + ",aks"
+	if t := c.provider().ClusterType(); t != "" {
+		clusterType = clusterType + "," + t
 	}
 
 	if bgpEnabled(c.cfg.Installation) {
@@ -1188,6 +1411,14 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_BPFEXTTOSERVICECONNMARK", Value: "0x80"})
 	}
 
+	if usageReportingDisabled(c.cfg.Installation) {
+		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_USAGEREPORTINGENABLED", Value: "false"})
+	}
+
+	if cn := c.cfg.Installation.CalicoNetwork; cn != nil && cn.HostIPForwarding != nil {
+		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_IPFORWARDING", Value: string(*cn.HostIPForwarding)})
+	}
+
 	// If there are no IP pools specified, then configure no default IP pools.
 	if c.cfg.Installation.CalicoNetwork == nil || len(c.cfg.Installation.CalicoNetwork.IPPools) == 0 {
 		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "NO_DEFAULT_POOLS", Value: "true"})
@@ -1256,7 +1487,7 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 			Name:  "FELIX_XDPENABLED",
 			Value: "false",
 		})
-		if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderEKS {
+		if c.provider().DisableSourceDestinationCheck() {
 			nodeEnv = append(nodeEnv, corev1.EnvVar{
 				Name:  "FELIX_AWSSRCDSTCHECK",
 				Value: "Disable",
@@ -1376,22 +1607,22 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 		nodeEnv = append(nodeEnv, extraNodeEnv...)
 	}
 
-	// Configure provider specific environment variables here.
-	switch c.cfg.Installation.KubernetesProvider {
-	case operatorv1.ProviderOpenShift:
-		// For Openshift, we need special configuration since our default port is already in use.
-		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_HEALTHPORT", Value: "9199"})
-		if c.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
-			// We also need to configure a non-default trusted DNS server, since there's no kube-dns.
-			nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_DNSTRUSTEDSERVERS", Value: "k8s-service:openshift-dns/dns-default"})
+	if ds := c.cfg.Installation.DataplaneSync; ds != nil {
+		// Felix reads its refresh intervals in seconds.
+		if ds.RouteRefreshIntervalSeconds != nil {
+			nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_ROUTEREFRESHINTERVAL", Value: fmt.Sprintf("%d", *ds.RouteRefreshIntervalSeconds)})
+		}
+		if ds.IptablesRefreshIntervalSeconds != nil {
+			nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_IPTABLESREFRESHINTERVAL", Value: fmt.Sprintf("%d", *ds.IptablesRefreshIntervalSeconds)})
+		}
+		if ds.IpsetsRefreshIntervalSeconds != nil {
+			nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_IPSETSREFRESHINTERVAL", Value: fmt.Sprintf("%d", *ds.IpsetsRefreshIntervalSeconds)})
 		}
-	// For AKS and EKS/CalicoCNI, we must explicitly ask felix to add host IP's to wireguard ifaces
-	case operatorv1.ProviderAKS:
-		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"})
-	case operatorv1.ProviderEKS:
-		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"})
 	}
 
+	// Configure provider specific environment variables here.
+	nodeEnv = append(nodeEnv, c.provider().FelixEnv(c.cfg.Installation.Variant)...)
+
 	switch c.cfg.Installation.CNI.Type {
 	case operatorv1.PluginAmazonVPC:
 		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_INTERFACEPREFIX", Value: "eni"})
@@ -1434,9 +1665,14 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 	return nodeEnv
 }
 
-// nodeLifecycle creates the node's postStart and preStop hooks.
+// nodeLifecycle creates the node's postStart and preStop hooks. When a drain is configured, the preStop hook drains
+// the node before shutting calico-node down.
 func (c *nodeComponent) nodeLifecycle() *corev1.Lifecycle {
 	preStopCmd := []string{"/bin/calico-node", "-shutdown"}
+	if script := nodeDrainScript(c.cfg.Installation); script != nil {
+		script = append(script, "exec "+strings.Join(preStopCmd, " "))
+		preStopCmd = []string{"/bin/sh", "-c", strings.Join(script, "; ")}
+	}
 	lc := &corev1.Lifecycle{
 		PreStop: &corev1.Handler{Exec: &corev1.ExecAction{Command: preStopCmd}},
 	}
@@ -1445,8 +1681,10 @@ func (c *nodeComponent) nodeLifecycle() *corev1.Lifecycle {
 
 // nodeLivenessReadinessProbes creates the node's liveness and readiness probes.
 func (c *nodeComponent) nodeLivenessReadinessProbes() (*corev1.Probe, *corev1.Probe) {
-	// Determine liveness and readiness configuration for node.
-	livenessPort := intstr.FromInt(9099)
+	// Determine liveness and readiness configuration for node. The BIRD readiness check fails until the BGP sessions
+	// are established and the graceful restart of BIRD is over, so a restarted node is only ready once its routes
+	// converged.
+	livenessPort := intstr.FromInt(c.provider().FelixHealthPort())
 	readinessCmd := []string{"/bin/calico-node", "-bird-ready", "-felix-ready"}
 
 	// Want to check for BGP metrics server if this is enterprise
@@ -1459,11 +1697,6 @@ func (c *nodeComponent) nodeLivenessReadinessProbes() (*corev1.Probe, *corev1.Pr
 		readinessCmd = []string{"/bin/calico-node", "-felix-ready"}
 	}
 
-	// For Openshift, we need a different port since our default port is already in use.
-	if c.cfg.Installation.KubernetesProvider == operatorv1.ProviderOpenShift {
-		livenessPort = intstr.FromInt(9199)
-	}
-
 	lp := &corev1.Probe{
 		Handler: corev1.Handler{
 			HTTPGet: &corev1.HTTPGetAction{
@@ -1517,11 +1750,44 @@ func (c *nodeComponent) nodeMetricsService() *corev1.Service {
 	}
 }
 
+// felixMetricsService creates a Service exposing the felix prometheus metrics served by calico/node on the
+// NodeMetricsPort of the installation.
+func (c *nodeComponent) felixMetricsService() *corev1.Service {
+	svc := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      FelixMetricsServiceName,
+			Namespace: common.CalicoNamespace,
+			Labels:    map[string]string{"k8s-app": "calico-node"},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"k8s-app": "calico-node"},
+			Type:     corev1.ServiceTypeClusterIP,
+		},
+	}
+	if port := c.cfg.Installation.NodeMetricsPort; port != nil {
+		svc.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:       "felix-metrics-port",
+				Port:       *port,
+				TargetPort: intstr.FromInt(int(*port)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		}
+	}
+	return svc
+}
+
 func (c *nodeComponent) nodePodSecurityPolicy() *policyv1beta1.PodSecurityPolicy {
 	psp := podsecuritypolicy.NewBasePolicy()
 	psp.GetObjectMeta().SetName(common.NodeDaemonSetName)
-	psp.Spec.Privileged = true
-	psp.Spec.AllowPrivilegeEscalation = ptr.BoolToPtr(true)
+	if c.runAsNonPrivileged() {
+		// Only allow the capabilities added by the calico-node containers.
+		psp.Spec.AllowedCapabilities = append(append([]corev1.Capability{}, nodeNonPrivilegedCapabilities...), "CHOWN", "FOWNER")
+	} else {
+		psp.Spec.Privileged = true
+		psp.Spec.AllowPrivilegeEscalation = ptr.BoolToPtr(true)
+	}
 	psp.Spec.Volumes = append(psp.Spec.Volumes, policyv1beta1.HostPath)
 	psp.Spec.HostNetwork = true
 	// CollectProcessPath feature in logCollectorSpec requires access to hostPID
@@ -1564,12 +1830,33 @@ func (c *nodeComponent) hostPathInitContainer() corev1.Container {
 		},
 		VolumeMounts: mounts,
 		SecurityContext: &corev1.SecurityContext{
-			RunAsUser: &rootUID,
+			RunAsUser:                &rootUID,
+			Privileged:               ptr.BoolToPtr(false),
+			AllowPrivilegeEscalation: ptr.BoolToPtr(false),
+			Capabilities: &corev1.Capabilities{
+				// Changing the owner of the hostPath directories is all this container does.
+				Add:  []corev1.Capability{"CHOWN", "FOWNER"},
+				Drop: []corev1.Capability{"ALL"},
+			},
 		},
 		Command: []string{"sh", "-c", "calico-node -hostpath-init"},
 	}
 }
 
+// nonPrivilegedInitSecurityContext returns the security context of the init containers that only copy files to the
+// host when running as non-privileged. They run as root, without any capability.
+func nonPrivilegedInitSecurityContext() *corev1.SecurityContext {
+	rootUID := int64(0)
+	return &corev1.SecurityContext{
+		RunAsUser:                &rootUID,
+		Privileged:               ptr.BoolToPtr(false),
+		AllowPrivilegeEscalation: ptr.BoolToPtr(false),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
 // runAsNonPrivileged checks to ensure that all of the proper installation values are set for running
 // Calico as non-privileged.
 func (c *nodeComponent) runAsNonPrivileged() bool {
@@ -1640,6 +1927,54 @@ func bgpEnabled(instance *operatorv1.InstallationSpec) bool {
 		*instance.CalicoNetwork.BGP == operatorv1.BGPEnabled
 }
 
+// bgpGracefulRestart returns the BGP graceful restart configuration of the Installation if there is one, nil otherwise.
+func bgpGracefulRestart(instance *operatorv1.InstallationSpec) *operatorv1.BGPGracefulRestart {
+	if instance.CalicoNetwork == nil {
+		return nil
+	}
+	return instance.CalicoNetwork.BGPGracefulRestart
+}
+
+// nodeTerminationGracePeriod returns the time given to the pods running the BGP daemon of a node to shut down, or nil
+// to use the default. The NodeShutdown takes precedence over the BGP graceful restart.
+func nodeTerminationGracePeriod(instance *operatorv1.InstallationSpec) *int64 {
+	if ns := instance.NodeShutdown; ns != nil && ns.TerminationGracePeriodSeconds != nil {
+		return ns.TerminationGracePeriodSeconds
+	}
+	if gr := bgpGracefulRestart(instance); gr != nil {
+		return gr.ShutdownGracePeriodSeconds
+	}
+	return nil
+}
+
+// nodeDrainScript returns the shell commands draining the node before the BGP daemon of the node shuts down, or nil if
+// no drain is configured.
+func nodeDrainScript(instance *operatorv1.InstallationSpec) []string {
+	ns := instance.NodeShutdown
+	if ns == nil {
+		return nil
+	}
+	var script []string
+	if ns.DrainSeconds != nil && *ns.DrainSeconds > 0 {
+		script = append(script, fmt.Sprintf("sleep %d", *ns.DrainSeconds))
+	}
+	if ns.DrainCommand != "" {
+		// The command runs in a subshell, so that it can't skip the shutdown of the BGP daemon.
+		script = append(script, "("+ns.DrainCommand+")")
+	}
+	return script
+}
+
+// bgpMaxRestartTime returns the graceful restart time of the BGP sessions configured by the operator, or nil to use
+// the default of the BGP daemon.
+func bgpMaxRestartTime(instance *operatorv1.InstallationSpec) *metav1.Duration {
+	gr := bgpGracefulRestart(instance)
+	if gr == nil || gr.MaxRestartTimeSeconds == nil {
+		return nil
+	}
+	return &metav1.Duration{Duration: time.Duration(*gr.MaxRestartTimeSeconds) * time.Second}
+}
+
 // getMTU returns the MTU configured in the Installation if there is one, nil otherwise.
 func getMTU(instance *operatorv1.InstallationSpec) *int32 {
 	var mtu *int32
@@ -27,6 +27,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
@@ -334,6 +335,13 @@ var _ = Describe("Node rendering tests", func() {
 		verifyProbesAndLifecycle(ds, false, false)
 	})
 
+	It("should not render the PodSecurityPolicy when the cluster doesn't support it", func() {
+		cfg.KubernetesVersion = &common.VersionInfo{Major: 1, Minor: 25}
+		resources, _ := render.Node(&cfg).Objects()
+		Expect(rtest.GetResource(resources, common.NodeDaemonSetName, "", "policy", "v1beta1", "PodSecurityPolicy")).To(BeNil())
+		Expect(rtest.GetResource(resources, common.NodeDaemonSetName, common.CalicoNamespace, "apps", "v1", "DaemonSet")).ToNot(BeNil())
+	})
+
 	It("should render node correctly for BPF dataplane", func() {
 		expectedResources := []struct {
 			name    string
@@ -803,11 +811,37 @@ var _ = Describe("Node rendering tests", func() {
 		Expect(nodeContainer.SecurityContext.Capabilities.Add[0]).To(Equal(corev1.Capability("NET_RAW")))
 		Expect(nodeContainer.SecurityContext.Capabilities.Add[1]).To(Equal(corev1.Capability("NET_ADMIN")))
 		Expect(nodeContainer.SecurityContext.Capabilities.Add[2]).To(Equal(corev1.Capability("NET_BIND_SERVICE")))
+		Expect(nodeContainer.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+		Expect(*nodeContainer.SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
 
 		// hostpath init container should have the correct env and security context.
 		hostPathContainer := rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "hostpath-init")
 		rtest.ExpectEnv(hostPathContainer.Env, "NODE_USER_ID", "999")
 		Expect(*hostPathContainer.SecurityContext.RunAsUser).To(Equal(int64(0)))
+		Expect(*hostPathContainer.SecurityContext.Privileged).To(BeFalse())
+		Expect(hostPathContainer.SecurityContext.Capabilities.Add).To(ConsistOf(corev1.Capability("CHOWN"), corev1.Capability("FOWNER")))
+
+		// The init containers copying files to the host run as root without privileges.
+		for _, name := range []string{"install-cni", "flexvol-driver"} {
+			initContainer := rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, name)
+			Expect(initContainer).ToNot(BeNil())
+			Expect(*initContainer.SecurityContext.RunAsUser).To(Equal(int64(0)))
+			Expect(*initContainer.SecurityContext.Privileged).To(BeFalse())
+			Expect(*initContainer.SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+			Expect(initContainer.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+		}
+
+		// The PodSecurityPolicy doesn't allow privileged containers.
+		psp := rtest.GetResource(resources, common.NodeDaemonSetName, "", "policy", "v1beta1", "PodSecurityPolicy").(*policyv1beta1.PodSecurityPolicy)
+		Expect(psp.Spec.Privileged).To(BeFalse())
+		Expect(*psp.Spec.AllowPrivilegeEscalation).To(BeFalse())
+		Expect(psp.Spec.AllowedCapabilities).To(ConsistOf(
+			corev1.Capability("NET_RAW"),
+			corev1.Capability("NET_ADMIN"),
+			corev1.Capability("NET_BIND_SERVICE"),
+			corev1.Capability("CHOWN"),
+			corev1.Capability("FOWNER"),
+		))
 
 		// Verify hostpath init container volume mounts.
 		expectedHostPathInitVolumeMounts := []corev1.VolumeMount{
@@ -1161,7 +1195,7 @@ var _ = Describe("Node rendering tests", func() {
 		Expect(cniContainer).To(BeNil())
 
 		// Validate correct number of init containers.
-		Expect(len(ds.Spec.Template.Spec.InitContainers)).To(Equal(1))
+		Expect(len(ds.Spec.Template.Spec.InitContainers)).To(Equal(2))
 
 		// Verify the Flex volume container image.
 		Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "flexvol-driver").Image).To(Equal(fmt.Sprintf("docker.io/%s:%s", components.ComponentFlexVolume.Image, components.ComponentFlexVolume.Version)))
@@ -1258,6 +1292,7 @@ var _ = Describe("Node rendering tests", func() {
 				},
 			},
 			{Name: "flexvol-driver-host", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/usr/libexec/kubernetes/kubelet-plugins/volume/exec/nodeagent~uds", Type: &dirOrCreate}}},
+			{Name: "cni-net-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/cni/net.d"}}},
 		}
 		Expect(ds.Spec.Template.Spec.Volumes).To(ConsistOf(expectedVols))
 
@@ -1311,7 +1346,7 @@ var _ = Describe("Node rendering tests", func() {
 			cniContainer := rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "install-cni")
 			Expect(cniContainer).To(BeNil())
 			// Validate correct number of init containers.
-			Expect(len(ds.Spec.Template.Spec.InitContainers)).To(Equal(1))
+			Expect(len(ds.Spec.Template.Spec.InitContainers)).To(Equal(2))
 
 			// Verify env
 			expectedEnvs = append(expectedEnvs,
@@ -1606,7 +1641,7 @@ var _ = Describe("Node rendering tests", func() {
 		Expect(cniContainer).To(BeNil())
 
 		// Validate correct number of init containers.
-		Expect(len(ds.Spec.Template.Spec.InitContainers)).To(Equal(1))
+		Expect(len(ds.Spec.Template.Spec.InitContainers)).To(Equal(2))
 
 		// Verify the Flex volume container image.
 		Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "flexvol-driver").Image).To(Equal(fmt.Sprintf("docker.io/%s:%s", components.ComponentFlexVolume.Image, components.ComponentFlexVolume.Version)))
@@ -1703,6 +1738,7 @@ var _ = Describe("Node rendering tests", func() {
 				},
 			},
 			{Name: "flexvol-driver-host", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/usr/libexec/kubernetes/kubelet-plugins/volume/exec/nodeagent~uds", Type: &dirOrCreate}}},
+			{Name: "cni-net-dir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/cni/net.d"}}},
 		}
 		Expect(ds.Spec.Template.Spec.Volumes).To(ConsistOf(expectedVols))
 
@@ -2322,6 +2358,11 @@ var _ = Describe("Node rendering tests", func() {
 		ds := dsResource.(*appsv1.DaemonSet)
 		Expect(ds.Spec.Template.Spec.Containers[0].Env).ToNot(ContainElement(notExpectedEnvVar))
 
+		// The felix metrics Service is removed.
+		Expect(rtest.GetResource(resources, render.FelixMetricsServiceName, common.CalicoNamespace, "", "v1", "Service")).To(BeNil())
+		_, toDelete := component.Objects()
+		Expect(rtest.GetResource(toDelete, render.FelixMetricsServiceName, common.CalicoNamespace, "", "v1", "Service")).NotTo(BeNil())
+
 		// It should have the reporter port, though.
 		expected := corev1.EnvVar{Name: "FELIX_PROMETHEUSREPORTERPORT"}
 		Expect(ds.Spec.Template.Spec.Containers[0].Env).ToNot(ContainElement(expected))
@@ -2334,7 +2375,7 @@ var _ = Describe("Node rendering tests", func() {
 		component := render.Node(&cfg)
 		Expect(component.ResolveImages(nil)).To(BeNil())
 		resources, _ := component.Objects()
-		Expect(len(resources)).To(Equal(defaultNumExpectedResources + 1))
+		Expect(len(resources)).To(Equal(defaultNumExpectedResources + 2))
 
 		dsResource := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet")
 		Expect(dsResource).ToNot(BeNil())
@@ -2352,6 +2393,41 @@ var _ = Describe("Node rendering tests", func() {
 		// Assert we set annotations properly.
 		Expect(ds.Spec.Template.Annotations["prometheus.io/scrape"]).To(Equal("true"))
 		Expect(ds.Spec.Template.Annotations["prometheus.io/port"]).To(Equal("1234"))
+
+		// The felix metrics are exposed through a Service.
+		svcResource := rtest.GetResource(resources, render.FelixMetricsServiceName, common.CalicoNamespace, "", "v1", "Service")
+		Expect(svcResource).ToNot(BeNil())
+		svc := svcResource.(*corev1.Service)
+		Expect(svc.Spec.Selector).To(Equal(map[string]string{"k8s-app": "calico-node"}))
+		Expect(svc.Spec.Ports).To(ConsistOf(corev1.ServicePort{
+			Name:       "felix-metrics-port",
+			Port:       1234,
+			TargetPort: intstr.FromInt(1234),
+			Protocol:   corev1.ProtocolTCP,
+		}))
+	})
+
+	It("should set the refresh intervals of felix if DataplaneSync is set", func() {
+		routeRefresh := int32(30)
+		iptablesRefresh := int32(0)
+		defaultInstance.DataplaneSync = &operatorv1.DataplaneSync{
+			RouteRefreshIntervalSeconds:    &routeRefresh,
+			IptablesRefreshIntervalSeconds: &iptablesRefresh,
+		}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		dsResource := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet")
+		Expect(dsResource).ToNot(BeNil())
+		ds := dsResource.(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: "FELIX_ROUTEREFRESHINTERVAL", Value: "30"},
+			corev1.EnvVar{Name: "FELIX_IPTABLESREFRESHINTERVAL", Value: "0"},
+		))
+		for _, env := range ds.Spec.Template.Spec.Containers[0].Env {
+			Expect(env.Name).NotTo(Equal("FELIX_IPSETSREFRESHINTERVAL"))
+		}
 	})
 
 	It("should not render a FlexVolume container if FlexVolumePath is set to None", func() {
@@ -2368,6 +2444,26 @@ var _ = Describe("Node rendering tests", func() {
 		Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "flexvol-driver")).To(BeNil())
 	})
 
+	It("should only render the FlexVolume container while the flexvolume policy sync driver is used", func() {
+		defaultInstance.FlexVolumePath = "/usr/libexec/kubernetes/kubelet-plugins/volume/exec/"
+		both := operatorv1.PolicySyncDriverBoth
+		defaultInstance.PolicySyncDriver = &both
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "flexvol-driver")).ToNot(BeNil())
+
+		csi := operatorv1.PolicySyncDriverCSI
+		defaultInstance.PolicySyncDriver = &csi
+		resources, _ = component.Objects()
+		ds = rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "flexvol-driver")).To(BeNil())
+		for _, v := range ds.Spec.Template.Spec.Volumes {
+			Expect(v.Name).NotTo(Equal("flexvol-driver-host"))
+		}
+	})
+
 	It("should render MaxUnavailable if a custom value was set", func() {
 		two := intstr.FromInt(2)
 		defaultInstance.NodeUpdateStrategy.RollingUpdate.MaxUnavailable = &two
@@ -2384,6 +2480,73 @@ var _ = Describe("Node rendering tests", func() {
 		Expect(ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable).To(Equal(&two))
 	})
 
+	It("should let the operator delete the pods when canary rollouts are enabled", func() {
+		defaultInstance.NodeCanaryRollout = &operatorv1.NodeCanaryRollout{}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		dsResource := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet")
+		Expect(dsResource).ToNot(BeNil())
+		ds := dsResource.(*appsv1.DaemonSet)
+		Expect(ds.Spec.UpdateStrategy).To(Equal(appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}))
+	})
+
+	It("should let the operator delete the pods when the nodes are migrated to VPP", func() {
+		defaultInstance.DataplaneMigration = &operatorv1.DataplaneMigration{}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.UpdateStrategy).To(Equal(appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}))
+	})
+
+	It("should apply the BGP graceful restart timings", func() {
+		var shutdown int64 = 30
+		var convergence int32 = 20
+		defaultInstance.CalicoNetwork.BGPGracefulRestart = &operatorv1.BGPGracefulRestart{
+			ShutdownGracePeriodSeconds: &shutdown,
+			ConvergenceSeconds:         &convergence,
+		}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		dsResource := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet")
+		Expect(dsResource).ToNot(BeNil())
+		ds := dsResource.(*appsv1.DaemonSet)
+		Expect(*ds.Spec.Template.Spec.TerminationGracePeriodSeconds).To(BeEquivalentTo(30))
+		Expect(ds.Spec.MinReadySeconds).To(BeEquivalentTo(20))
+		node := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "calico-node")
+		Expect(node.ReadinessProbe.Exec.Command).To(ContainElement("-bird-ready"))
+	})
+
+	It("should drain the node before shutting calico-node down", func() {
+		var grace int64 = 60
+		var drain int32 = 45
+		defaultInstance.NodeShutdown = &operatorv1.NodeShutdown{
+			TerminationGracePeriodSeconds: &grace,
+			DrainSeconds:                  &drain,
+			DrainCommand:                  "/usr/local/bin/withdraw-routes",
+		}
+		// The grace period of the node shutdown takes precedence over the one of the BGP graceful restart.
+		var shutdown int64 = 30
+		defaultInstance.CalicoNetwork.BGPGracefulRestart = &operatorv1.BGPGracefulRestart{ShutdownGracePeriodSeconds: &shutdown}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		dsResource := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet")
+		Expect(dsResource).ToNot(BeNil())
+		ds := dsResource.(*appsv1.DaemonSet)
+		Expect(*ds.Spec.Template.Spec.TerminationGracePeriodSeconds).To(BeEquivalentTo(60))
+		node := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "calico-node")
+		Expect(node.Lifecycle.PreStop.Exec.Command).To(Equal([]string{
+			"/bin/sh", "-c", "sleep 45; (/usr/local/bin/withdraw-routes); exec /bin/calico-node -shutdown",
+		}))
+	})
+
 	It("should render cni config without portmap when HostPorts disabled", func() {
 		expectedResources := []struct {
 			name    string
@@ -2573,6 +2736,173 @@ var _ = Describe("Node rendering tests", func() {
 }`))
 	})
 
+	It("should render dual-stack cni config with host-local", func() {
+		defaultInstance.CNI.IPAM.Type = operatorv1.IPAMPluginHostLocal
+		defaultInstance.CalicoNetwork.IPPools = append(defaultInstance.CalicoNetwork.IPPools, operatorv1.IPPool{CIDR: "fd00::/64"})
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		cniCm := rtest.GetResource(resources, "cni-config", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cniCm.Data["config"]).To(ContainSubstring(`"ipam": { "type": "host-local", "ranges": [[{"subnet": "usePodCidr"}], [{"subnet": "usePodCidrIPv6"}]]}`))
+	})
+
+	It("should render a chained cni config when CNI chaining is enabled with the AmazonVPC CNI plugin", func() {
+		chaining := operatorv1.CNIChainingEnabled
+		hpe := operatorv1.HostPortsEnabled
+		cfg.Installation = &operatorv1.InstallationSpec{
+			KubernetesProvider: operatorv1.ProviderEKS,
+			CNI: &operatorv1.CNISpec{
+				Type:     operatorv1.PluginAmazonVPC,
+				IPAM:     &operatorv1.IPAMSpec{Type: operatorv1.IPAMPluginAmazonVPC},
+				Chaining: &chaining,
+			},
+			CalicoNetwork:  &operatorv1.CalicoNetworkSpec{HostPorts: &hpe},
+			FlexVolumePath: "None",
+		}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		cniCmResource := rtest.GetResource(resources, "cni-config", "calico-system", "", "v1", "ConfigMap")
+		Expect(cniCmResource).ToNot(BeNil())
+		cniCm := cniCmResource.(*corev1.ConfigMap)
+		Expect(cniCm.Data["config"]).To(MatchJSON(`{
+  "name": "k8s-pod-network",
+  "cniVersion": "0.3.1",
+  "plugins": [
+    {
+      "name": "aws-cni",
+      "type": "aws-cni",
+      "vethPrefix": "eni",
+      "mtu": "9001",
+      "pluginLogFile": "/var/log/aws-routed-eni/plugin.log",
+      "pluginLogLevel": "Info"
+    },
+    {"type": "bandwidth", "capabilities": {"bandwidth": true}},
+    {"type": "portmap", "snat": true, "capabilities": {"portMappings": true}}
+  ]
+}`))
+
+		// The chained config is installed by the install-cni init container, ahead of the AmazonVPC config.
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		installCNI := rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "install-cni")
+		Expect(installCNI).ToNot(BeNil())
+		rtest.ExpectEnv(installCNI.Env, "CNI_CONF_NAME", "05-calico-chained.conflist")
+
+		// Calico still doesn't manage the CNI credentials nor route the pod traffic.
+		rtest.ExpectEnv(ds.Spec.Template.Spec.Containers[0].Env, "CALICO_MANAGE_CNI", "false")
+		rtest.ExpectEnv(ds.Spec.Template.Spec.Containers[0].Env, "CALICO_NETWORKING_BACKEND", "none")
+		Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "cni-cleanup")).To(BeNil())
+	})
+
+	It("should remove the chained cni config when CNI chaining is disabled", func() {
+		chaining := operatorv1.CNIChainingDisabled
+		cfg.Installation = &operatorv1.InstallationSpec{
+			KubernetesProvider: operatorv1.ProviderEKS,
+			CNI: &operatorv1.CNISpec{
+				Type:     operatorv1.PluginAmazonVPC,
+				IPAM:     &operatorv1.IPAMSpec{Type: operatorv1.IPAMPluginAmazonVPC},
+				Chaining: &chaining,
+			},
+			FlexVolumePath: "None",
+		}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+		Expect(rtest.GetResource(resources, "cni-config", "calico-system", "", "v1", "ConfigMap")).To(BeNil())
+
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "install-cni")).To(BeNil())
+		cleanup := rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "cni-cleanup")
+		Expect(cleanup).ToNot(BeNil())
+		Expect(cleanup.Command).To(Equal([]string{"rm", "-f", "/host/etc/cni/net.d/05-calico-chained.conflist"}))
+		Expect(cleanup.VolumeMounts).To(ConsistOf(corev1.VolumeMount{MountPath: "/host/etc/cni/net.d", Name: "cni-net-dir"}))
+		Expect(ds.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name:         "cni-net-dir",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/cni/net.d"}},
+		}))
+	})
+
+	It("should annotate the service account with the IAM role of the AWS secondary IPs", func() {
+		defaultInstance.Variant = operatorv1.TigeraSecureEnterprise
+		defaultInstance.CalicoNetwork.AWSSecondaryIPs = &operatorv1.AWSSecondaryIPs{
+			IAMRoleARN: "arn:aws:iam::123456789012:role/calico-node",
+		}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		sa := rtest.GetResource(resources, "calico-node", common.CalicoNamespace, "", "v1", "ServiceAccount").(*corev1.ServiceAccount)
+		Expect(sa.Annotations).To(HaveKeyWithValue(render.AWSIAMRoleAnnotation, "arn:aws:iam::123456789012:role/calico-node"))
+	})
+
+	It("should render the node-init init container on OpenShift", func() {
+		defaultInstance.KubernetesProvider = operatorv1.ProviderOpenShift
+		defaultInstance.FlexVolumePath = "/etc/kubernetes/kubelet-plugins/volume/exec/"
+		vpp := operatorv1.LinuxDataplaneVPP
+		defaultInstance.CalicoNetwork.LinuxDataplane = &vpp
+		defaultInstance.NodeInit = &operatorv1.NodeInit{
+			Sysctls: []operatorv1.Sysctl{
+				{Key: "vm.nr_hugepages", Value: "1024"},
+				{Key: "net.core.rmem_max", Value: "16777216"},
+			},
+		}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.InitContainers[0].Name).To(Equal("node-init"))
+		nodeInit := ds.Spec.Template.Spec.InitContainers[0]
+		Expect(*nodeInit.SecurityContext.Privileged).To(BeTrue())
+		Expect(nodeInit.Command).To(Equal([]string{"sh", "-c", strings.Join([]string{
+			"set -e",
+			"echo '1' > /proc/sys/net/ipv4/ip_forward",
+			"echo '1024' > /proc/sys/vm/nr_hugepages",
+			"echo '16777216' > /proc/sys/net/core/rmem_max",
+			"modprobe vfio-pci",
+			"modprobe uio_pci_generic",
+		}, "\n")}))
+	})
+
+	It("should not reserve the hugepages in node-init when the VPPDataplane reserves them", func() {
+		defaultInstance.KubernetesProvider = operatorv1.ProviderOpenShift
+		defaultInstance.FlexVolumePath = "/etc/kubernetes/kubelet-plugins/volume/exec/"
+		vpp := operatorv1.LinuxDataplaneVPP
+		defaultInstance.CalicoNetwork.LinuxDataplane = &vpp
+		cfg.VPPHugepagesReserved = true
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		nodeInit := ds.Spec.Template.Spec.InitContainers[0]
+		Expect(nodeInit.Name).To(Equal("node-init"))
+		Expect(nodeInit.Command[2]).NotTo(ContainSubstring("nr_hugepages"))
+
+		cfg.VPPHugepagesReserved = false
+		component = render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ = component.Objects()
+
+		ds = rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.InitContainers[0].Command[2]).To(ContainSubstring("echo '512' > /proc/sys/vm/nr_hugepages"))
+	})
+
+	It("should not render the node-init init container when it is disabled", func() {
+		defaultInstance.KubernetesProvider = operatorv1.ProviderOpenShift
+		defaultInstance.FlexVolumePath = "/etc/kubernetes/kubelet-plugins/volume/exec/"
+		disabled := operatorv1.NodeInitDisabled
+		defaultInstance.NodeInit = &operatorv1.NodeInit{State: &disabled}
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "node-init")).To(BeNil())
+	})
+
 	It("should render cni config with k8s endpoint", func() {
 		k8sServiceEp.Host = "k8shost"
 		k8sServiceEp.Port = "1234"
@@ -2670,6 +3000,28 @@ var _ = Describe("Node rendering tests", func() {
 		}
 	})
 
+	It("should disable the felix usage reporting when usage reporting is disabled", func() {
+		disabled := operatorv1.UsageReportingDisabled
+		cfg.Installation.UsageReporting = &disabled
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		rtest.ExpectEnv(rtest.GetContainer(ds.Spec.Template.Spec.Containers, "calico-node").Env, "FELIX_USAGEREPORTINGENABLED", "false")
+	})
+
+	It("should configure the host ip forwarding", func() {
+		disabled := operatorv1.HostIPForwardingDisabled
+		cfg.Installation.CalicoNetwork.HostIPForwarding = &disabled
+		component := render.Node(&cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		resources, _ := component.Objects()
+
+		ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		rtest.ExpectEnv(rtest.GetContainer(ds.Spec.Template.Spec.Containers, "calico-node").Env, "FELIX_IPFORWARDING", "Disabled")
+	})
+
 	It("should render resourcerequirements", func() {
 		rr := &corev1.ResourceRequirements{
 			Requests: corev1.ResourceList{
@@ -54,7 +54,6 @@ const (
 // PacketCaptureApiConfiguration contains all the config information needed to render the component.
 type PacketCaptureApiConfiguration struct {
 	PullSecrets        []*corev1.Secret
-	Openshift          bool
 	Installation       *operatorv1.InstallationSpec
 	KeyValidatorConfig authentication.KeyValidatorConfig
 	ServerCertSecret   *corev1.Secret
@@ -17,6 +17,8 @@ package render
 import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
 )
 
 var (
@@ -44,3 +46,8 @@ func setNodeCriticalPod(t *corev1.PodTemplateSpec) {
 func SetClusterCriticalPod(t *corev1.PodTemplateSpec) {
 	t.Spec.PriorityClassName = ClusterPriorityClassName
 }
+
+// usageReportingDisabled returns true if the installation opts out of the usage reporting of the components.
+func usageReportingDisabled(installation *operatorv1.InstallationSpec) bool {
+	return installation.UsageReporting != nil && *installation.UsageReporting == operatorv1.UsageReportingDisabled
+}
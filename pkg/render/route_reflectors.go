@@ -0,0 +1,204 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/secret"
+)
+
+const (
+	// RouteReflectorLabel is set on the nodes the operator selected as route reflectors.
+	RouteReflectorLabel = "operator.tigera.io/route-reflector"
+
+	// RouteReflectorClusterIDAnnotation is the annotation on a node setting the route reflector cluster ID of the
+	// Calico node.
+	RouteReflectorClusterIDAnnotation = "projectcalico.org/RouteReflectorClusterID"
+
+	// RouteReflectorBGPPeerName is the name of the BGPPeer peering every node with the route reflectors.
+	RouteReflectorBGPPeerName = "calico-route-reflectors"
+
+	// RouteReflectorPasswordRoleName is the name of the Role and RoleBinding allowing calico-node to read the BGP
+	// password of the route reflectors.
+	RouteReflectorPasswordRoleName = "calico-node-bgp-password"
+
+	// RouteReflectorPasswordLabel is set on the copies of the BGP password Secret in calico-system, so that the copy of
+	// a Secret the route reflectors no longer reference is deleted.
+	RouteReflectorPasswordLabel = "operator.tigera.io/bgp-password"
+
+	routeReflectorPasswordHashAnnotation = "hash.operator.tigera.io/bgp-password"
+
+	DefaultRouteReflectorClusterID = "244.0.0.1"
+)
+
+// RouteReflectors renders the BGPPeer peering the nodes with the in-cluster route reflectors, along with the copy of
+// the BGP password Secret and the RBAC allowing calico-node to read it. These are deleted when no route reflectors are
+// configured.
+func RouteReflectors(cfg *RouteReflectorsConfiguration) Component {
+	return &routeReflectorsComponent{cfg: cfg}
+}
+
+// RouteReflectorsConfiguration contains all the config information needed to render the component.
+type RouteReflectorsConfiguration struct {
+	Installation *operatorv1.InstallationSpec
+
+	// PasswordSecret is the Secret holding the BGP password referenced by the route reflectors, in the operator
+	// namespace.
+	PasswordSecret *corev1.Secret
+
+	// PasswordCopies are the copies of the BGP password Secrets in calico-system, i.e. the Secrets labeled with
+	// RouteReflectorPasswordLabel. The ones which aren't the copy of PasswordSecret are deleted.
+	PasswordCopies []corev1.Secret
+}
+
+type routeReflectorsComponent struct {
+	cfg *RouteReflectorsConfiguration
+}
+
+func (c *routeReflectorsComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on a BGPPeer
+	return nil
+}
+
+func (c *routeReflectorsComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *routeReflectorsComponent) Objects() ([]client.Object, []client.Object) {
+	rr := c.routeReflectors()
+	if rr == nil {
+		return nil, append([]client.Object{c.bgpPeer(), c.passwordRole(), c.passwordRoleBinding()}, c.stalePasswordCopies()...)
+	}
+	if rr.Password == nil || c.cfg.PasswordSecret == nil {
+		return []client.Object{c.bgpPeer()}, append([]client.Object{c.passwordRole(), c.passwordRoleBinding()}, c.stalePasswordCopies()...)
+	}
+
+	// The copy is rendered from the current contents of the Secret, so that it follows the rotations of the password.
+	password := secret.CopyToNamespace(common.CalicoNamespace, c.cfg.PasswordSecret)[0]
+	password.Labels = map[string]string{RouteReflectorPasswordLabel: "true"}
+	objs := []client.Object{c.bgpPeer(), c.passwordRole(), c.passwordRoleBinding(), password}
+	return objs, c.stalePasswordCopies()
+}
+
+func (c *routeReflectorsComponent) Ready() bool {
+	return true
+}
+
+func (c *routeReflectorsComponent) routeReflectors() *operatorv1.RouteReflectors {
+	if c.cfg.Installation.CalicoNetwork == nil {
+		return nil
+	}
+	return c.cfg.Installation.CalicoNetwork.RouteReflectors
+}
+
+// stalePasswordCopies returns the copies of the BGP password Secrets which aren't the copy of the Secret referenced by
+// the route reflectors, e.g. after the route reflectors were switched to another Secret.
+func (c *routeReflectorsComponent) stalePasswordCopies() []client.Object {
+	var current string
+	if rr := c.routeReflectors(); rr != nil && rr.Password != nil && c.cfg.PasswordSecret != nil {
+		current = c.cfg.PasswordSecret.Name
+	}
+	var stale []client.Object
+	for i := range c.cfg.PasswordCopies {
+		s := &c.cfg.PasswordCopies[i]
+		if s.Name == current {
+			continue
+		}
+		stale = append(stale, &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: s.Name, Namespace: s.Namespace},
+		})
+	}
+	return stale
+}
+
+// bgpPeer returns the BGPPeer peering every node with the route reflectors. The route reflectors themselves peer with
+// each other through the same resource.
+func (c *routeReflectorsComponent) bgpPeer() *crdv1.BGPPeer {
+	peer := &crdv1.BGPPeer{
+		TypeMeta: metav1.TypeMeta{Kind: crdv1.KindBGPPeer, APIVersion: "crd.projectcalico.org/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: RouteReflectorBGPPeerName,
+		},
+		Spec: crdv1.BGPPeerSpec{
+			NodeSelector:   "all()",
+			PeerSelector:   fmt.Sprintf("%s == 'true'", RouteReflectorLabel),
+			MaxRestartTime: bgpMaxRestartTime(c.cfg.Installation),
+		},
+	}
+
+	if rr := c.routeReflectors(); rr != nil && rr.Password != nil && c.cfg.PasswordSecret != nil {
+		peer.Spec.Password = &crdv1.BGPPassword{SecretKeyRef: rr.Password.DeepCopy()}
+		// Changing the password changes the peer too, so that the sessions are re-established with the new password.
+		peer.Annotations = map[string]string{
+			routeReflectorPasswordHashAnnotation: rmeta.AnnotationHash(c.cfg.PasswordSecret.Data[rr.Password.Key]),
+		}
+	}
+	return peer
+}
+
+// passwordRole allows calico-node to read the BGP password Secret, which is the only Secret it may read.
+func (c *routeReflectorsComponent) passwordRole() *rbacv1.Role {
+	role := &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{Kind: "Role", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RouteReflectorPasswordRoleName,
+			Namespace: common.CalicoNamespace,
+		},
+	}
+	if rr := c.routeReflectors(); rr != nil && rr.Password != nil {
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"secrets"},
+				ResourceNames: []string{rr.Password.Name},
+				Verbs:         []string{"get", "list", "watch"},
+			},
+		}
+	}
+	return role
+}
+
+func (c *routeReflectorsComponent) passwordRoleBinding() *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{Kind: "RoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RouteReflectorPasswordRoleName,
+			Namespace: common.CalicoNamespace,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     RouteReflectorPasswordRoleName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      "calico-node",
+				Namespace: common.CalicoNamespace,
+			},
+		},
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("Route reflector rendering tests", func() {
+	var installation *operatorv1.InstallationSpec
+	var cfg *render.RouteReflectorsConfiguration
+	BeforeEach(func() {
+		bgp := operatorv1.BGPEnabled
+		installation = &operatorv1.InstallationSpec{
+			CalicoNetwork: &operatorv1.CalicoNetworkSpec{
+				BGP:             &bgp,
+				RouteReflectors: &operatorv1.RouteReflectors{},
+			},
+		}
+		cfg = &render.RouteReflectorsConfiguration{Installation: installation}
+	})
+
+	It("should render a BGPPeer peering every node with the route reflectors", func() {
+		toCreate, toDelete := render.RouteReflectors(cfg).Objects()
+		Expect(toCreate).To(HaveLen(1))
+		rtest.ExpectResource(toCreate[0], render.RouteReflectorBGPPeerName, "", "crd.projectcalico.org", "v1", "BGPPeer")
+		Expect(toDelete).To(HaveLen(2))
+
+		peer := toCreate[0].(*crdv1.BGPPeer)
+		Expect(peer.Spec.NodeSelector).To(Equal("all()"))
+		Expect(peer.Spec.PeerSelector).To(Equal("operator.tigera.io/route-reflector == 'true'"))
+		Expect(peer.Spec.Password).To(BeNil())
+	})
+
+	It("should distribute the BGP password to calico-node", func() {
+		installation.CalicoNetwork.RouteReflectors.Password = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "bgp-password"},
+			Key:                  "password",
+		}
+		cfg.PasswordSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "bgp-password", Namespace: common.OperatorNamespace()},
+			Data:       map[string][]byte{"password": []byte("secret")},
+		}
+		toCreate, toDelete := render.RouteReflectors(cfg).Objects()
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(4))
+		rtest.ExpectResource(toCreate[1], render.RouteReflectorPasswordRoleName, common.CalicoNamespace, "rbac.authorization.k8s.io", "v1", "Role")
+		rtest.ExpectResource(toCreate[2], render.RouteReflectorPasswordRoleName, common.CalicoNamespace, "rbac.authorization.k8s.io", "v1", "RoleBinding")
+		Expect(toCreate[3].GetName()).To(Equal("bgp-password"))
+		Expect(toCreate[3].GetNamespace()).To(Equal(common.CalicoNamespace))
+		Expect(toCreate[3].GetLabels()).To(HaveKeyWithValue(render.RouteReflectorPasswordLabel, "true"))
+
+		peer := toCreate[0].(*crdv1.BGPPeer)
+		Expect(peer.Spec.Password.SecretKeyRef.Name).To(Equal("bgp-password"))
+		Expect(peer.Spec.Password.SecretKeyRef.Key).To(Equal("password"))
+		role := toCreate[1].(*rbacv1.Role)
+		Expect(role.Rules).To(ConsistOf(rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: []string{"bgp-password"},
+			Verbs:         []string{"get", "list", "watch"},
+		}))
+
+		// A new password changes the BGPPeer so that the sessions are re-established.
+		hash := peer.Annotations
+		cfg.PasswordSecret.Data["password"] = []byte("rotated")
+		toCreate, _ = render.RouteReflectors(cfg).Objects()
+		Expect(toCreate[0].GetAnnotations()).NotTo(Equal(hash))
+	})
+
+	It("should delete the copies of the BGP password Secrets which are no longer referenced", func() {
+		installation.CalicoNetwork.RouteReflectors.Password = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "bgp-password-v2"},
+			Key:                  "password",
+		}
+		cfg.PasswordSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "bgp-password-v2", Namespace: common.OperatorNamespace()},
+			Data:       map[string][]byte{"password": []byte("secret")},
+		}
+		cfg.PasswordCopies = []corev1.Secret{
+			{ObjectMeta: metav1.ObjectMeta{Name: "bgp-password", Namespace: common.CalicoNamespace}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "bgp-password-v2", Namespace: common.CalicoNamespace}},
+		}
+		toCreate, toDelete := render.RouteReflectors(cfg).Objects()
+		Expect(toCreate).To(HaveLen(4))
+		Expect(toDelete).To(HaveLen(1))
+		rtest.ExpectResource(toDelete[0], "bgp-password", common.CalicoNamespace, "", "v1", "Secret")
+
+		installation.CalicoNetwork.RouteReflectors = nil
+		_, toDelete = render.RouteReflectors(cfg).Objects()
+		Expect(toDelete).To(HaveLen(5))
+	})
+
+	It("should delete the BGPPeer and the password RBAC when no route reflectors are configured", func() {
+		installation.CalicoNetwork.RouteReflectors = nil
+		toCreate, toDelete := render.RouteReflectors(cfg).Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(3))
+		rtest.ExpectResource(toDelete[0], render.RouteReflectorBGPPeerName, "", "crd.projectcalico.org", "v1", "BGPPeer")
+	})
+})
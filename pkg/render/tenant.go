@@ -0,0 +1,308 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This renderer is responsible for the Voltron instances of the tenants of a multi-tenant management cluster.
+package render
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/components"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
+	"github.com/tigera/operator/pkg/render/common/secret"
+)
+
+const (
+	// TenantResourceName is the only supported name of a Tenant within its namespace.
+	TenantResourceName = "default"
+
+	// TenantNamespaceLabel assigns a ManagedCluster to the tenant of the given namespace. The tunnel of the managed
+	// cluster is then accepted by the Voltron instance of that tenant only.
+	TenantNamespaceLabel = "tenant.operator.tigera.io/namespace"
+
+	TenantVoltronServiceAccountName     = VoltronName
+	TenantVoltronServiceName            = VoltronName
+	TenantVoltronClusterRoleName        = "tigera-voltron-tenant"
+	TenantVoltronClusterRoleBindingName = "tigera-voltron-tenants"
+	tenantVoltronTLSHashAnnotation      = "hash.operator.tigera.io/voltron-tls"
+)
+
+// TenantVoltronConfiguration contains all the config information needed to render the Voltron instance of a tenant.
+type TenantVoltronConfiguration struct {
+	Tenant            *operatorv1.Tenant
+	ManagementCluster *operatorv1.ManagementCluster
+	// TunnelSecret is the tunnel CA of the tenant. A new one is generated when nil.
+	TunnelSecret *corev1.Secret
+	TLSKeyPair   *corev1.Secret
+	PullSecrets  []*corev1.Secret
+	Installation *operatorv1.InstallationSpec
+}
+
+// TenantVoltron renders the Voltron instance of a tenant in the namespace of the Tenant. Each tenant has its own tunnel
+// CA, so that the managed clusters of one tenant cannot connect to the Voltron instance of another.
+func TenantVoltron(cfg *TenantVoltronConfiguration) Component {
+	var tunnelSecrets []*corev1.Secret
+	if cfg.TunnelSecret == nil {
+		cfg.TunnelSecret = voltronTunnelSecret()
+		cfg.TunnelSecret.Namespace = cfg.Tenant.Namespace
+		tunnelSecrets = append(tunnelSecrets, cfg.TunnelSecret)
+	}
+	return &tenantVoltronComponent{
+		cfg:           cfg,
+		tunnelSecrets: tunnelSecrets,
+	}
+}
+
+type tenantVoltronComponent struct {
+	cfg           *TenantVoltronConfiguration
+	tunnelSecrets []*corev1.Secret
+	proxyImage    string
+}
+
+func (c *tenantVoltronComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	reg := c.cfg.Installation.Registry
+	path := c.cfg.Installation.ImagePath
+	prefix := c.cfg.Installation.ImagePrefix
+	var err error
+	c.proxyImage, err = components.GetReference(components.ComponentManagerProxy, reg, path, prefix, is)
+	return err
+}
+
+func (c *tenantVoltronComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeLinux
+}
+
+func (c *tenantVoltronComponent) Objects() ([]client.Object, []client.Object) {
+	namespace := c.cfg.Tenant.Namespace
+
+	objs := secret.ToRuntimeObjects(secret.CopyToNamespace(namespace, c.cfg.PullSecrets...)...)
+	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(namespace, c.cfg.TLSKeyPair)...)...)
+	objs = append(objs, secret.ToRuntimeObjects(c.tunnelSecrets...)...)
+	objs = append(objs,
+		c.serviceAccount(),
+		c.deployment(),
+		c.service(),
+	)
+	return objs, nil
+}
+
+func (c *tenantVoltronComponent) Ready() bool {
+	return true
+}
+
+func (c *tenantVoltronComponent) serviceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: TenantVoltronServiceAccountName, Namespace: c.cfg.Tenant.Namespace},
+	}
+}
+
+func (c *tenantVoltronComponent) deployment() *appsv1.Deployment {
+	namespace := c.cfg.Tenant.Namespace
+	labels := map[string]string{"k8s-app": VoltronName}
+
+	env := []corev1.EnvVar{
+		{Name: "VOLTRON_PORT", Value: defaultVoltronPort},
+		{Name: "VOLTRON_LOGLEVEL", Value: "Info"},
+		{Name: "VOLTRON_ENABLE_MULTI_CLUSTER_MANAGEMENT", Value: "true"},
+		{Name: "VOLTRON_TUNNEL_PORT", Value: defaultTunnelVoltronPort},
+		{Name: "VOLTRON_ENABLE_COMPLIANCE", Value: "false"},
+		{Name: "VOLTRON_TENANT_ID", Value: c.cfg.Tenant.Spec.ID},
+		// Voltron only accepts the tunnels of the managed clusters that carry the tenant namespace label.
+		{Name: "VOLTRON_TENANT_NAMESPACE", Value: namespace},
+	}
+	if c.cfg.ManagementCluster != nil {
+		env = append(env, TunnelKeepAliveEnv("VOLTRON_", c.cfg.ManagementCluster.Spec.TunnelKeepAlive)...)
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      VoltronName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      VoltronName,
+					Namespace: namespace,
+					Labels:    labels,
+					Annotations: map[string]string{
+						voltronTunnelHashAnnotation:    rmeta.AnnotationHash(c.cfg.TunnelSecret.Data),
+						tenantVoltronTLSHashAnnotation: rmeta.AnnotationHash(c.cfg.TLSKeyPair.Data),
+					},
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector:       c.cfg.Installation.ControlPlaneNodeSelector,
+					ServiceAccountName: TenantVoltronServiceAccountName,
+					Tolerations:        append(c.cfg.Installation.ControlPlaneTolerations, rmeta.TolerateMaster, rmeta.TolerateCriticalAddonsOnly),
+					ImagePullSecrets:   secret.GetReferenceList(c.cfg.PullSecrets),
+					Containers: []corev1.Container{
+						{
+							Name:  VoltronName,
+							Image: c.proxyImage,
+							Env:   env,
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: ManagerTLSSecretName, MountPath: "/certs/https", ReadOnly: true},
+								{Name: VoltronTunnelSecretName, MountPath: "/certs/tunnel", ReadOnly: true},
+							},
+							LivenessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path:   "/voltron/api/health",
+										Port:   intstr.FromInt(managerPort),
+										Scheme: corev1.URISchemeHTTPS,
+									},
+								},
+								InitialDelaySeconds: 90,
+								PeriodSeconds:       10,
+							},
+							SecurityContext: podsecuritycontext.NewBaseContext(),
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: ManagerTLSSecretName,
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: c.cfg.TLSKeyPair.Name},
+							},
+						},
+						{
+							Name: VoltronTunnelSecretName,
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: c.cfg.TunnelSecret.Name},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (c *tenantVoltronComponent) service() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TenantVoltronServiceName,
+			Namespace: c.cfg.Tenant.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "https",
+					Port:       managerPort,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(managerPort),
+				},
+				{
+					Name:       "tunnel",
+					Port:       voltronTunnelPort,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(voltronTunnelPort),
+				},
+			},
+			Selector: map[string]string{"k8s-app": VoltronName},
+		},
+	}
+}
+
+// TenantVoltronRBAC renders the cluster-wide permissions of the Voltron instances of all tenants. They are bound through
+// a single ClusterRoleBinding, so that the permissions of a deleted tenant are revoked on the next reconcile. Unlike the
+// Voltron instance of the manager, the Voltron instances of the tenants cannot impersonate users on the management
+// cluster.
+func TenantVoltronRBAC(tenants []operatorv1.Tenant) Component {
+	return &tenantVoltronRBACComponent{tenants: tenants}
+}
+
+type tenantVoltronRBACComponent struct {
+	tenants []operatorv1.Tenant
+}
+
+func (c *tenantVoltronRBACComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	return nil
+}
+
+func (c *tenantVoltronRBACComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *tenantVoltronRBACComponent) Objects() ([]client.Object, []client.Object) {
+	objs := []client.Object{c.clusterRole(), c.clusterRoleBinding()}
+	if len(c.tenants) == 0 {
+		return nil, objs
+	}
+	return objs, nil
+}
+
+func (c *tenantVoltronRBACComponent) Ready() bool {
+	return true
+}
+
+func (c *tenantVoltronRBACComponent) clusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: TenantVoltronClusterRoleName},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"authorization.k8s.io"},
+				Resources: []string{"subjectaccessreviews"},
+				Verbs:     []string{"create"},
+			},
+			{
+				APIGroups: []string{"authentication.k8s.io"},
+				Resources: []string{"tokenreviews"},
+				Verbs:     []string{"create"},
+			},
+			{
+				APIGroups: []string{"projectcalico.org"},
+				Resources: []string{"managedclusters"},
+				Verbs:     []string{"list", "get", "watch"},
+			},
+		},
+	}
+}
+
+func (c *tenantVoltronRBACComponent) clusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	var subjects []rbacv1.Subject
+	for _, tenant := range c.tenants {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:      "ServiceAccount",
+			Name:      TenantVoltronServiceAccountName,
+			Namespace: tenant.Namespace,
+		})
+	}
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: TenantVoltronClusterRoleBindingName},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     TenantVoltronClusterRoleName,
+		},
+		Subjects: subjects,
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("Tenant rendering tests", func() {
+	var cfg *render.TenantVoltronConfiguration
+
+	BeforeEach(func() {
+		cfg = &render.TenantVoltronConfiguration{
+			Tenant: &operatorv1.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: render.TenantResourceName, Namespace: "tenant-a"},
+				Spec:       operatorv1.TenantSpec{ID: "a"},
+			},
+			ManagementCluster: &operatorv1.ManagementCluster{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}},
+			TLSKeyPair:        rtest.CreateCertSecret(render.ManagerTLSSecretName, common.OperatorNamespace()),
+			Installation:      &operatorv1.InstallationSpec{Registry: "my-reg/"},
+		}
+	})
+
+	It("should render the Voltron instance of a tenant in the tenant namespace", func() {
+		component := render.TenantVoltron(cfg)
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, toDelete := component.Objects()
+		Expect(toDelete).To(BeNil())
+
+		expectedResources := []struct {
+			name    string
+			ns      string
+			group   string
+			version string
+			kind    string
+		}{
+			{name: render.ManagerTLSSecretName, ns: "tenant-a", group: "", version: "v1", kind: "Secret"},
+			{name: render.VoltronTunnelSecretName, ns: "tenant-a", group: "", version: "v1", kind: "Secret"},
+			{name: render.TenantVoltronServiceAccountName, ns: "tenant-a", group: "", version: "v1", kind: "ServiceAccount"},
+			{name: render.VoltronName, ns: "tenant-a", group: "apps", version: "v1", kind: "Deployment"},
+			{name: render.TenantVoltronServiceName, ns: "tenant-a", group: "", version: "v1", kind: "Service"},
+		}
+		Expect(toCreate).To(HaveLen(len(expectedResources)))
+		for i, expectedRes := range expectedResources {
+			rtest.ExpectResource(toCreate[i], expectedRes.name, expectedRes.ns, expectedRes.group, expectedRes.version, expectedRes.kind)
+		}
+
+		tunnelSecret := toCreate[1].(*corev1.Secret)
+		Expect(tunnelSecret.Data).To(HaveKey(render.VoltronTunnelSecretCertName))
+		Expect(tunnelSecret.Data).To(HaveKey(render.VoltronTunnelSecretKeyName))
+
+		deployment := toCreate[3].(*appsv1.Deployment)
+		Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal(render.TenantVoltronServiceAccountName))
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal("my-reg/tigera/voltron:" + components.ComponentManagerProxy.Version))
+		rtest.ExpectEnv(container.Env, "VOLTRON_TENANT_ID", "a")
+		rtest.ExpectEnv(container.Env, "VOLTRON_TENANT_NAMESPACE", "tenant-a")
+		rtest.ExpectEnv(container.Env, "VOLTRON_ENABLE_MULTI_CLUSTER_MANAGEMENT", "true")
+	})
+
+	It("should not regenerate the tunnel CA of a tenant", func() {
+		cfg.TunnelSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: render.VoltronTunnelSecretName, Namespace: "tenant-a"},
+			Data:       map[string][]byte{render.VoltronTunnelSecretCertName: []byte("cert"), render.VoltronTunnelSecretKeyName: []byte("key")},
+		}
+		component := render.TenantVoltron(cfg)
+		Expect(component.ResolveImages(nil)).NotTo(HaveOccurred())
+		toCreate, _ := component.Objects()
+
+		Expect(rtest.GetResource(toCreate, render.VoltronTunnelSecretName, "tenant-a", "", "v1", "Secret")).To(BeNil())
+		deployment := rtest.GetResource(toCreate, render.VoltronName, "tenant-a", "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: render.VoltronTunnelSecretName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: render.VoltronTunnelSecretName},
+			},
+		}))
+	})
+
+	It("should bind the permissions of all tenants and revoke them once no tenant is left", func() {
+		tenants := []operatorv1.Tenant{
+			{ObjectMeta: metav1.ObjectMeta{Name: render.TenantResourceName, Namespace: "tenant-a"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: render.TenantResourceName, Namespace: "tenant-b"}},
+		}
+		toCreate, toDelete := render.TenantVoltronRBAC(tenants).Objects()
+		Expect(toDelete).To(BeNil())
+		Expect(toCreate).To(HaveLen(2))
+
+		clusterRole := rtest.GetResource(toCreate, render.TenantVoltronClusterRoleName, "", "rbac.authorization.k8s.io", "v1", "ClusterRole").(*rbacv1.ClusterRole)
+		for _, rule := range clusterRole.Rules {
+			Expect(rule.Verbs).NotTo(ContainElement("impersonate"))
+		}
+		binding := rtest.GetResource(toCreate, render.TenantVoltronClusterRoleBindingName, "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding").(*rbacv1.ClusterRoleBinding)
+		Expect(binding.Subjects).To(ConsistOf(
+			rbacv1.Subject{Kind: "ServiceAccount", Name: render.TenantVoltronServiceAccountName, Namespace: "tenant-a"},
+			rbacv1.Subject{Kind: "ServiceAccount", Name: render.TenantVoltronServiceAccountName, Namespace: "tenant-b"},
+		))
+
+		toCreate, toDelete = render.TenantVoltronRBAC(nil).Objects()
+		Expect(toCreate).To(BeNil())
+		Expect(toDelete).To(HaveLen(2))
+	})
+})
@@ -36,6 +36,7 @@ import (
 	"github.com/tigera/operator/pkg/dns"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
 	"github.com/tigera/operator/pkg/render/common/podsecuritypolicy"
+	"github.com/tigera/operator/pkg/render/common/provider"
 	"github.com/tigera/operator/pkg/render/common/secret"
 )
 
@@ -48,6 +49,7 @@ const (
 	TyphaPort               int32 = 5473
 	TyphaCAHashAnnotation         = "hash.operator.tigera.io/typha-ca"
 	TyphaCertHashAnnotation       = "hash.operator.tigera.io/typha-cert"
+	TyphaMetricsServiceName       = "calico-typha-metrics"
 )
 
 var (
@@ -65,6 +67,9 @@ type TyphaConfiguration struct {
 	AmazonCloudIntegration *operatorv1.AmazonCloudIntegration
 	MigrateNamespaces      bool
 	ClusterDomain          string
+	// KubernetesVersion is the version of the cluster. The PodSecurityPolicy is only rendered when the cluster
+	// supports it, which is assumed when the version is unknown.
+	KubernetesVersion *common.VersionInfo
 }
 
 // Typha creates the typha daemonset and other resources for the daemonset to operate normally.
@@ -126,7 +131,7 @@ func (c *typhaComponent) Objects() ([]client.Object, []client.Object) {
 		objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(common.CalicoNamespace, c.cfg.TLS.TyphaSecret)...)...)
 	}
 
-	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() && c.cfg.KubernetesVersion.ProvidesPodSecurityPolicy() {
 		objs = append(objs, c.typhaPodSecurityPolicy())
 	}
 
@@ -134,10 +139,19 @@ func (c *typhaComponent) Objects() ([]client.Object, []client.Object) {
 		objs = append(objs, CSRClusterRoleBinding("calico-typha", common.CalicoNamespace))
 	}
 
+	var objsToDelete []client.Object
+
+	// Expose the typha prometheus metrics when they are enabled.
+	if c.cfg.Installation.TyphaMetricsPort != nil {
+		objs = append(objs, c.typhaMetricsService())
+	} else {
+		objsToDelete = append(objsToDelete, c.typhaMetricsService())
+	}
+
 	// Add deployment last, as it may depend on the creation of previous objects in the list.
 	objs = append(objs, c.typhaDeployment())
 
-	return objs, nil
+	return objs, objsToDelete
 }
 
 func (c *typhaComponent) typhaPodDisruptionBudget() *policyv1beta1.PodDisruptionBudget {
@@ -349,7 +363,7 @@ func (c *typhaComponent) typhaRole() *rbacv1.ClusterRole {
 		}
 		role.Rules = append(role.Rules, extraRules...)
 	}
-	if c.cfg.Installation.KubernetesProvider != operatorv1.ProviderOpenShift {
+	if provider.For(c.cfg.Installation.KubernetesProvider).PodSecurityPolicies() {
 		// Allow access to the pod security policy in case this is enforced on the cluster
 		role.Rules = append(role.Rules, rbacv1.PolicyRule{APIGroups: []string{"policy"},
 			Resources:     []string{"podsecuritypolicies"},
@@ -638,6 +652,38 @@ func (c *typhaComponent) typhaService() *corev1.Service {
 	}
 }
 
+// typhaMetricsService creates a Service exposing the prometheus metrics served by calico/typha on the
+// TyphaMetricsPort of the installation.
+func (c *typhaComponent) typhaMetricsService() *corev1.Service {
+	svc := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TyphaMetricsServiceName,
+			Namespace: common.CalicoNamespace,
+			Labels: map[string]string{
+				AppLabelName: TyphaK8sAppName,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				AppLabelName: TyphaK8sAppName,
+			},
+			Type: corev1.ServiceTypeClusterIP,
+		},
+	}
+	if port := c.cfg.Installation.TyphaMetricsPort; port != nil {
+		svc.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:       "typha-metrics-port",
+				Port:       *port,
+				TargetPort: intstr.FromInt(int(*port)),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		}
+	}
+	return svc
+}
+
 func (c *typhaComponent) typhaPodSecurityPolicy() *policyv1beta1.PodSecurityPolicy {
 	psp := podsecuritypolicy.NewBasePolicy()
 	psp.GetObjectMeta().SetName(common.TyphaDeploymentName)
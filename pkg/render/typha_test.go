@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
@@ -329,6 +330,11 @@ var _ = Describe("Typha rendering tests", func() {
 		notExpectedEnvVar := corev1.EnvVar{Name: "TYPHA_PROMETHEUSMETRICSENABLED"}
 		d := dResource.(*appsv1.Deployment)
 		Expect(d.Spec.Template.Spec.Containers[0].Env).ToNot(ContainElement(notExpectedEnvVar))
+
+		// The typha metrics Service is removed.
+		Expect(rtest.GetResource(resources, render.TyphaMetricsServiceName, common.CalicoNamespace, "", "v1", "Service")).To(BeNil())
+		_, toDelete := component.Objects()
+		Expect(rtest.GetResource(toDelete, render.TyphaMetricsServiceName, common.CalicoNamespace, "", "v1", "Service")).NotTo(BeNil())
 	})
 
 	It("should set TYPHA_PROMETHEUSMETRICSPORT with a custom value if TyphaMetricsPort is set", func() {
@@ -351,5 +357,17 @@ var _ = Describe("Typha rendering tests", func() {
 		// Assert we set annotations properly.
 		Expect(d.Spec.Template.Annotations["prometheus.io/scrape"]).To(Equal("true"))
 		Expect(d.Spec.Template.Annotations["prometheus.io/port"]).To(Equal("1234"))
+
+		// The typha metrics are exposed through a Service.
+		svcResource := rtest.GetResource(resources, render.TyphaMetricsServiceName, common.CalicoNamespace, "", "v1", "Service")
+		Expect(svcResource).ToNot(BeNil())
+		svc := svcResource.(*corev1.Service)
+		Expect(svc.Spec.Selector).To(Equal(map[string]string{"k8s-app": "calico-typha"}))
+		Expect(svc.Spec.Ports).To(ConsistOf(corev1.ServicePort{
+			Name:       "typha-metrics-port",
+			Port:       1234,
+			TargetPort: intstr.FromInt(1234),
+			Protocol:   corev1.ProtocolTCP,
+		}))
 	})
 })
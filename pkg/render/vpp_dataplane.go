@@ -0,0 +1,742 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+const (
+	// VPPNodeName is the name of the DaemonSet of the nodes without a VPP profile, and of the ServiceAccount, the
+	// ClusterRole and the Role of the VPP dataplane.
+	VPPNodeName = "calico-vpp-node"
+
+	// VPPNodePodLabel is set on the pods of all the calico-vpp-node DaemonSets, the default one and the ones of the
+	// VPP profiles, which have a k8s-app label of their own.
+	VPPNodePodLabel = "operator.tigera.io/vpp-node"
+
+	// VPPMetricsPortName is the name of the port of the VPP agent serving the Prometheus metrics of VPP, which the
+	// VPP PodMonitor scrapes.
+	VPPMetricsPortName = "metrics-port"
+	VPPMetricsPort     = 8888
+
+	// VPPHugepagesName is the name of the DaemonSet reserving the hugepages of the nodes of the VPP dataplane, and the
+	// k8s-app label of its pods, which are ready once the hugepages of their node are reserved.
+	VPPHugepagesName = "calico-vpp-hugepages"
+
+	// VPPConfigConfigMapName is the name of the ConfigMap holding the configuration of the VPP agent set by the
+	// VPPDataplane, which is loaded with envFrom by the agent on every node.
+	VPPConfigConfigMapName = "calico-vpp-config"
+
+	// VPPInterfaceKey, VPPInterfacesKey, VPPServicePrefixKey, VPPLogLevelKey and VPPGSOKey are the keys of the
+	// ConfigMap of the VPPDataplane, named after the environment variables of the VPP agent. VPPInterfacesKey holds
+	// the uplink interfaces, in JSON.
+	VPPInterfaceKey     = "CALICOVPP_INTERFACE"
+	VPPInterfacesKey    = "CALICOVPP_INTERFACES"
+	VPPServicePrefixKey = "SERVICE_PREFIX"
+	VPPLogLevelKey      = "CALICOVPP_LOG_LEVEL"
+	VPPGSOKey           = "CALICOVPP_DEBUG_ENABLE_GSO"
+
+	// DefaultVPPServicePrefix is the default service CIDR of kubeadm.
+	DefaultVPPServicePrefix = "10.96.0.0/12"
+
+	vppContainerName      = "vpp"
+	vppAgentContainerName = "agent"
+
+	vppConfigHashAnnotation = "hash.operator.tigera.io/vpp-config"
+)
+
+// VPPAgentConfigMapNames are the ConfigMaps rendered by VPPProfiles which configure the VPP agent on every node. They
+// only exist with the matching Installation settings.
+var VPPAgentConfigMapNames = []string{VPPTunnelsConfigMapName, VPPNAT64ConfigMapName, VPPEventLogConfigMapName}
+
+// vppDriverKernelModules are the kernel modules the uplink drivers need on the nodes: the drivers running the PCI
+// devices from userspace need vfio-pci, and the rdma driver the verbs of the RDMA devices.
+var vppDriverKernelModules = map[operatorv1.VPPUplinkDriver]string{
+	operatorv1.VPPUplinkDriverAVF:     "vfio-pci",
+	operatorv1.VPPUplinkDriverDPDK:    "vfio-pci",
+	operatorv1.VPPUplinkDriverRDMA:    "ib_uverbs",
+	operatorv1.VPPUplinkDriverVirtio:  "vfio-pci",
+	operatorv1.VPPUplinkDriverVMXNET3: "vfio-pci",
+}
+
+// vppPollingOnlyDrivers are the uplink drivers which don't support the interrupts, VPP polling their interfaces.
+var vppPollingOnlyDrivers = map[operatorv1.VPPUplinkDriver]bool{
+	operatorv1.VPPUplinkDriverDPDK: true,
+}
+
+// The capabilities of the containers of the VPP dataplane when running as non-privileged. VPP and the agent manage the
+// interfaces and the network namespaces of the pods, VPP locks its hugepages in memory and raises the priority of its
+// workers, and the agent runs the BGP daemon of the node.
+var (
+	vppNonPrivilegedCapabilities      = []corev1.Capability{"NET_ADMIN", "NET_RAW", "SYS_ADMIN", "IPC_LOCK", "SYS_NICE", "SYS_RESOURCE"}
+	vppAgentNonPrivilegedCapabilities = []corev1.Capability{"NET_ADMIN", "NET_RAW", "NET_BIND_SERVICE", "SYS_ADMIN"}
+)
+
+// vppHugepagesSysfsDir is the sysfs directory of the hugepages.
+const vppHugepagesSysfsDir = "/sys/kernel/mm/hugepages"
+
+// vppHugepageDirs are the directories of the hugepages of each size in sysfs.
+var vppHugepageDirs = map[operatorv1.VPPHugepageSize]string{
+	operatorv1.VPPHugepageSize2Mi: "hugepages-2048kB",
+	operatorv1.VPPHugepageSize1Gi: "hugepages-1048576kB",
+}
+
+// vppUplinkInterface is an uplink interface as read by the VPP agent.
+type vppUplinkInterface struct {
+	InterfaceName string `json:"interfaceName,omitempty"`
+	PCIAddress    string `json:"pciAddress,omitempty"`
+	VPPDriver     string `json:"vppDriver,omitempty"`
+	RxMode        string `json:"rxMode,omitempty"`
+}
+
+// VPPUplinkName returns the interface name or the PCI address identifying the given uplink interface.
+func VPPUplinkName(u operatorv1.VPPUplinkInterface) string {
+	if u.InterfaceName != "" {
+		return u.InterfaceName
+	}
+	return u.PCIAddress
+}
+
+// VPPCPUManagerPinning returns whether the VPP profile pins the threads of VPP to the CPUs the CPU manager assigns to
+// the vpp container.
+func VPPCPUManagerPinning(p *operatorv1.VPPProfile) bool {
+	return p.CPUPinning != nil && p.CPUPinning.Mode == operatorv1.VPPCPUPinningCPUManager
+}
+
+// VPPProfileCPUs returns the number of CPUs of VPP on the nodes of the profile: one for the main thread, and one for
+// each worker.
+func VPPProfileCPUs(p *operatorv1.VPPProfile) int64 {
+	if p.Workers == nil {
+		return 1
+	}
+	return int64(*p.Workers) + 1
+}
+
+// guaranteeVPPCPUs makes the pods Guaranteed, the vpp container requesting the given number of CPUs, since the static
+// policy of the CPU manager only assigns exclusive CPUs to the containers of the Guaranteed pods requesting whole
+// CPUs. The requests of the containers are set to their limits, and the init containers, which are accounted for as
+// well, get the resources of the vpp container.
+func guaranteeVPPCPUs(spec *corev1.PodSpec, cpus int64) {
+	vpp := guaranteedResources(spec.Containers[0].Resources)
+	cpu := *resource.NewQuantity(cpus, resource.DecimalSI)
+	vpp.Limits[corev1.ResourceCPU], vpp.Requests[corev1.ResourceCPU] = cpu, cpu
+	spec.Containers[0].Resources = vpp
+	for i := 1; i < len(spec.Containers); i++ {
+		spec.Containers[i].Resources = guaranteedResources(spec.Containers[i].Resources)
+	}
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].Resources = *vpp.DeepCopy()
+	}
+}
+
+// guaranteedResources returns the given resources with their requests set to their limits.
+func guaranteedResources(r corev1.ResourceRequirements) corev1.ResourceRequirements {
+	limits := corev1.ResourceList{}
+	for k, v := range r.Limits {
+		limits[k] = v
+	}
+	return corev1.ResourceRequirements{Limits: limits, Requests: limits.DeepCopy()}
+}
+
+// vppConfigJSON returns the JSON of a configuration of the VPP agent, which is made of strings and numbers only, and
+// so always marshals.
+func vppConfigJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+// VPPRxModeSupported returns whether the given uplink driver supports the given rx mode.
+func VPPRxModeSupported(driver operatorv1.VPPUplinkDriver, mode operatorv1.VPPRxMode) bool {
+	return mode == operatorv1.VPPRxModePolling || !vppPollingOnlyDrivers[driver]
+}
+
+// VPPNodeDaemonSetName returns the name of the calico-vpp-node DaemonSet of the nodes of the given VPP profile, and the
+// k8s-app label of its pods.
+func VPPNodeDaemonSetName(profile string) string {
+	return VPPNodeName + "-" + profile
+}
+
+// VPPNodeSelector returns the node selector of the nodes of the VPP dataplane: the Linux nodes or, while the
+// Installation migrates the nodes from the BPF dataplane, the nodes already labeled for the VPP dataplane.
+func VPPNodeSelector(install *operatorv1.InstallationSpec) map[string]string {
+	selector := map[string]string{"kubernetes.io/os": "linux"}
+	if install.DataplaneMigration != nil {
+		selector[common.LinuxDataplaneLabel] = string(operatorv1.LinuxDataplaneVPP)
+	}
+	return selector
+}
+
+// VPPDataplaneConfiguration contains all the config information needed to render the VPP dataplane.
+type VPPDataplaneConfiguration struct {
+	Installation *operatorv1.InstallationSpec
+	VPPDataplane *operatorv1.VPPDataplane
+
+	// AgentConfigMaps are the ConfigMaps rendered by VPPProfiles in the calico-system namespace: the ones of
+	// VPPAgentConfigMapNames and the ones of the VPP profiles. Their data is hashed into the pods, so that they restart
+	// when it changes.
+	AgentConfigMaps []corev1.ConfigMap
+
+	// ProfileDaemonSets are the DaemonSets carrying the VPPProfileLabel in the calico-system namespace.
+	ProfileDaemonSets []appsv1.DaemonSet
+}
+
+// VPPDataplane renders the calico-vpp-node DaemonSets, which run VPP and the VPP agent on the nodes of the VPP
+// dataplane, with the configuration of the agent set by the VPPDataplane. The nodes of each VPP profile of the
+// Installation get a DaemonSet of their own, named after the profile, which also loads the VPP configuration rendered
+// for the profile by VPPProfiles. The DaemonSets of the profiles which were removed are deleted.
+func VPPDataplane(cfg *VPPDataplaneConfiguration) Component {
+	return &vppDataplaneComponent{cfg: cfg}
+}
+
+type vppDataplaneComponent struct {
+	cfg        *VPPDataplaneConfiguration
+	vppImage   string
+	agentImage string
+}
+
+func (c *vppDataplaneComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	reg := c.cfg.Installation.Registry
+	path := c.cfg.Installation.ImagePath
+	prefix := c.cfg.Installation.ImagePrefix
+	var err error
+	if c.vppImage, err = components.GetReference(components.ComponentCalicoVPP, reg, path, prefix, is); err != nil {
+		return err
+	}
+	c.agentImage, err = components.GetReference(components.ComponentCalicoVPPAgent, reg, path, prefix, is)
+	return err
+}
+
+func (c *vppDataplaneComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeLinux
+}
+
+func (c *vppDataplaneComponent) Objects() ([]client.Object, []client.Object) {
+	objs := []client.Object{
+		c.serviceAccount(),
+		c.clusterRole(),
+		c.clusterRoleBinding(),
+		c.role(),
+		c.roleBinding(),
+		c.configMap(),
+		c.daemonSet(nil),
+	}
+	var toDelete []client.Object
+	desired := map[string]bool{}
+	for _, p := range c.profiles() {
+		ds := c.daemonSet(p)
+		desired[ds.Name] = true
+		objs = append(objs, ds)
+	}
+	for i := range c.cfg.ProfileDaemonSets {
+		if !desired[c.cfg.ProfileDaemonSets[i].Name] {
+			toDelete = append(toDelete, c.cfg.ProfileDaemonSets[i].DeepCopy())
+		}
+	}
+
+	if c.cfg.VPPDataplane.Spec.Hugepages == nil {
+		toDelete = append(toDelete, &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: VPPHugepagesName, Namespace: common.CalicoNamespace}})
+	} else {
+		objs = append(objs, c.hugepagesDaemonSet())
+	}
+	return objs, toDelete
+}
+
+func (c *vppDataplaneComponent) Ready() bool {
+	return true
+}
+
+// configMap returns the configuration of the VPP agent, with the defaults of the VPPDataplane filled in.
+func (c *vppDataplaneComponent) configMap() *corev1.ConfigMap {
+	spec := c.cfg.VPPDataplane.Spec
+	prefixes := spec.ServicePrefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{DefaultVPPServicePrefix}
+	}
+	logLevel := operatorv1.VPPLogLevelInfo
+	if spec.LogLevel != nil {
+		logLevel = *spec.LogLevel
+	}
+	gso := spec.GSO == nil || *spec.GSO == operatorv1.VPPGSOEnabled
+
+	data := map[string]string{
+		VPPServicePrefixKey: strings.Join(prefixes, ","),
+		VPPLogLevelKey:      strings.ToLower(string(logLevel)),
+		VPPGSOKey:           fmt.Sprint(gso),
+	}
+	// Without an uplink interface, the agent takes over the interface of the default route.
+	if spec.UplinkInterface != "" {
+		data[VPPInterfaceKey] = spec.UplinkInterface
+	}
+	if len(spec.UplinkInterfaces) > 0 {
+		var uplinks []vppUplinkInterface
+		for _, u := range spec.UplinkInterfaces {
+			uplink := vppUplinkInterface{InterfaceName: u.InterfaceName, PCIAddress: u.PCIAddress}
+			if u.Driver != nil {
+				uplink.VPPDriver = string(*u.Driver)
+			}
+			if u.RxMode != nil {
+				uplink.RxMode = strings.ToLower(string(*u.RxMode))
+			}
+			uplinks = append(uplinks, uplink)
+		}
+		data[VPPInterfacesKey] = vppConfigJSON(map[string][]vppUplinkInterface{"uplinkInterfaces": uplinks})
+	}
+	// The nodes without a VPP profile use the default driver, buffers and memory, with the startup configuration
+	// loading the plugin of the driver. The ConfigMap of the profile of a node takes precedence.
+	if spec.UplinkDriver != nil {
+		data[VPPDriverKey] = string(*spec.UplinkDriver)
+	}
+	if spec.UplinkDriver != nil || spec.Buffers != nil || spec.Memory != nil {
+		p := &operatorv1.VPPProfile{UplinkDriver: spec.UplinkDriver, Memory: spec.Memory}
+		if spec.Buffers != nil {
+			p.BuffersPerNUMA = spec.Buffers.BuffersPerNUMA
+			p.BufferDataSize = spec.Buffers.DataSize
+		}
+		data[VPPConfigTemplateKey] = vppStartupConfig(p)
+	}
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: VPPConfigConfigMapName, Namespace: common.CalicoNamespace},
+		Data:       data,
+	}
+}
+
+func (c *vppDataplaneComponent) serviceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: VPPNodeName, Namespace: common.CalicoNamespace},
+	}
+}
+
+// clusterRole allows the VPP agent to watch the resources it programs in VPP, and to report the NAT64 status on its
+// node.
+func (c *vppDataplaneComponent) clusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: VPPNodeName},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods", "services", "endpoints", "namespaces"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"nodes"},
+				Verbs:     []string{"get", "list", "watch", "patch"},
+			},
+			{
+				APIGroups: []string{"crd.projectcalico.org"},
+				Resources: []string{
+					"bgpconfigurations",
+					"bgppeers",
+					"blockaffinities",
+					"felixconfigurations",
+					"ipamblocks",
+					"ippools",
+					"networkpolicies",
+					"globalnetworkpolicies",
+					"networksets",
+					"globalnetworksets",
+					"hostendpoints",
+					"nodes",
+				},
+				Verbs: []string{"get", "list", "watch"},
+			},
+		},
+	}
+}
+
+func (c *vppDataplaneComponent) clusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: VPPNodeName},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     VPPNodeName,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      "ServiceAccount",
+			Name:      VPPNodeName,
+			Namespace: common.CalicoNamespace,
+		}},
+	}
+}
+
+// role allows the VPP agent to read the ConfigMaps of the calico-system namespace.
+func (c *vppDataplaneComponent) role() *rbacv1.Role {
+	return &rbacv1.Role{
+		TypeMeta:   metav1.TypeMeta{Kind: "Role", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: VPPNodeName, Namespace: common.CalicoNamespace},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     []string{"get", "list", "watch"},
+		}},
+	}
+}
+
+func (c *vppDataplaneComponent) roleBinding() *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta:   metav1.TypeMeta{Kind: "RoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: VPPNodeName, Namespace: common.CalicoNamespace},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     VPPNodeName,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      "ServiceAccount",
+			Name:      VPPNodeName,
+			Namespace: common.CalicoNamespace,
+		}},
+	}
+}
+
+// profiles returns the VPP profiles of the Installation.
+func (c *vppDataplaneComponent) profiles() []*operatorv1.VPPProfile {
+	var profiles []*operatorv1.VPPProfile
+	if cn := c.cfg.Installation.CalicoNetwork; cn != nil {
+		for i := range cn.VPPProfiles {
+			profiles = append(profiles, &cn.VPPProfiles[i])
+		}
+	}
+	return profiles
+}
+
+// configHash returns the hash of the configuration the VPP agent loads with envFrom: the ConfigMap of the VPPDataplane,
+// the ConfigMaps of VPPAgentConfigMapNames and, with a profile, the ConfigMap of the profile.
+func (c *vppDataplaneComponent) configHash(envFrom []corev1.EnvFromSource) string {
+	cms := map[string]map[string]string{VPPConfigConfigMapName: c.configMap().Data}
+	for i := range c.cfg.AgentConfigMaps {
+		cm := &c.cfg.AgentConfigMaps[i]
+		cms[cm.Name] = cm.Data
+	}
+	var data []map[string]string
+	for _, e := range envFrom {
+		data = append(data, cms[e.ConfigMapRef.Name])
+	}
+	return rmeta.AnnotationHash(data)
+}
+
+// daemonSet runs VPP and the VPP agent on the nodes of the VPP dataplane with the given VPP profile or, with a nil
+// profile, on the nodes without a VPP profile of the Installation. It tolerates the taint of the nodes being migrated,
+// so that VPP is running before the migration of the node completes.
+func (c *vppDataplaneComponent) daemonSet(profile *operatorv1.VPPProfile) *appsv1.DaemonSet {
+	hostPathDirectory := corev1.HostPathDirectoryOrCreate
+	bidirectional := corev1.MountPropagationBidirectional
+
+	// The ConfigMaps rendered by VPPProfiles only exist with the matching Installation settings.
+	optional := true
+	var envFrom []corev1.EnvFromSource
+	for _, name := range append([]string{VPPConfigConfigMapName}, VPPAgentConfigMapNames...) {
+		envFrom = append(envFrom, corev1.EnvFromSource{
+			ConfigMapRef: &corev1.ConfigMapEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Optional:             &optional,
+			},
+		})
+	}
+
+	name := VPPNodeName
+	labels := map[string]string{"k8s-app": VPPNodeName}
+	nodeSelector := VPPNodeSelector(c.cfg.Installation)
+	var affinity *corev1.Affinity
+	if profile != nil {
+		// The ConfigMap of the profile comes last, so that it takes precedence over the ConfigMap of the VPPDataplane.
+		envFrom = append(envFrom, corev1.EnvFromSource{
+			ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: VPPProfileConfigMapName(profile.Name)}},
+		})
+		name = VPPNodeDaemonSetName(profile.Name)
+		labels = map[string]string{"k8s-app": name, VPPProfileLabel: profile.Name}
+		nodeSelector[VPPProfileLabel] = profile.Name
+	} else if profiles := c.profiles(); len(profiles) > 0 {
+		// The nodes labeled with a profile which was removed are back to the defaults.
+		var names []string
+		for _, p := range profiles {
+			names = append(names, p.Name)
+		}
+		affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: VPPProfileLabel, Operator: corev1.NodeSelectorOpNotIn, Values: names}},
+				}},
+			},
+		}}
+	}
+	// The pods of all the calico-vpp-node DaemonSets carry the VPPNodePodLabel.
+	podLabels := map[string]string{VPPNodePodLabel: "true"}
+	for k, v := range labels {
+		podLabels[k] = v
+	}
+
+	nodeName := corev1.EnvVar{
+		Name:      "NODENAME",
+		ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}},
+	}
+	vppSocket := corev1.VolumeMount{Name: "vpp-rundir", MountPath: "/var/run/vpp"}
+
+	ds := &appsv1.DaemonSet{
+		TypeMeta:   metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.CalicoNamespace, Labels: labels},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": name}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      podLabels,
+					Annotations: map[string]string{vppConfigHashAnnotation: c.configHash(envFrom)},
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector:       nodeSelector,
+					Affinity:           affinity,
+					Tolerations:        rmeta.TolerateAll,
+					ImagePullSecrets:   c.cfg.Installation.ImagePullSecrets,
+					ServiceAccountName: VPPNodeName,
+					HostNetwork:        true,
+					HostPID:            true,
+					DNSPolicy:          corev1.DNSClusterFirstWithHostNet,
+					Containers: []corev1.Container{
+						{
+							Name:            vppContainerName,
+							Image:           c.vppImage,
+							Env:             []corev1.EnvVar{nodeName},
+							EnvFrom:         envFrom,
+							SecurityContext: c.securityContext(vppNonPrivilegedCapabilities...),
+							VolumeMounts: []corev1.VolumeMount{
+								vppSocket,
+								{Name: "devices", MountPath: "/dev"},
+								{Name: "hostsys", MountPath: "/sys"},
+								{Name: "netns", MountPath: "/run/netns", MountPropagation: &bidirectional},
+							},
+						},
+						{
+							Name:            vppAgentContainerName,
+							Image:           c.agentImage,
+							Env:             []corev1.EnvVar{nodeName},
+							EnvFrom:         envFrom,
+							SecurityContext: c.securityContext(vppAgentNonPrivilegedCapabilities...),
+							Ports: []corev1.ContainerPort{
+								{Name: VPPMetricsPortName, ContainerPort: VPPMetricsPort, Protocol: corev1.ProtocolTCP},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								vppSocket,
+								{Name: "var-run-calico", MountPath: "/var/run/calico"},
+								{Name: "vpp-logs", MountPath: "/var/log/calico/vpp"},
+								{Name: "netns", MountPath: "/run/netns", MountPropagation: &bidirectional},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{Name: "vpp-rundir", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/vpp", Type: &hostPathDirectory}}},
+						{Name: "devices", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/dev"}}},
+						{Name: "hostsys", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/sys"}}},
+						{Name: "netns", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/run/netns", Type: &hostPathDirectory}}},
+						{Name: "var-run-calico", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/calico"}}},
+						{Name: "vpp-logs", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log/calico/vpp", Type: &hostPathDirectory}}},
+					},
+				},
+			},
+		},
+	}
+	if modules := c.kernelModules(profile); len(modules) > 0 {
+		ds.Spec.Template.Spec.InitContainers = append(ds.Spec.Template.Spec.InitContainers, corev1.Container{
+			Name:            "load-kernel-modules",
+			Image:           c.vppImage,
+			Command:         append([]string{"modprobe", "-a"}, modules...),
+			SecurityContext: c.securityContext("SYS_MODULE"),
+			VolumeMounts:    []corev1.VolumeMount{{Name: "lib-modules", MountPath: "/lib/modules", ReadOnly: true}},
+		})
+		ds.Spec.Template.Spec.Volumes = append(ds.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name:         "lib-modules",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/lib/modules"}},
+		})
+	}
+	// VPP fails to start without its hugepages, so it waits for calico-vpp-hugepages to reserve them.
+	if c.cfg.VPPDataplane.Spec.Hugepages != nil {
+		ds.Spec.Template.Spec.InitContainers = append(ds.Spec.Template.Spec.InitContainers, corev1.Container{
+			Name:    "wait-for-hugepages",
+			Image:   c.vppImage,
+			Command: []string{"sh", "-c", fmt.Sprintf("until %s; do sleep 5; done", c.hugepagesReserved())},
+		})
+	}
+	if r := c.cfg.VPPDataplane.Spec.VPPResources; r != nil {
+		ds.Spec.Template.Spec.Containers[0].Resources = *r
+	}
+	if r := c.cfg.VPPDataplane.Spec.AgentResources; r != nil {
+		ds.Spec.Template.Spec.Containers[1].Resources = *r
+	}
+	if profile != nil && VPPCPUManagerPinning(profile) {
+		guaranteeVPPCPUs(&ds.Spec.Template.Spec, VPPProfileCPUs(profile))
+	}
+	// The agent runs the BGP daemon of the node in place of calico-node, so it holds the graceful restart of the node.
+	if bgpEnabled(c.cfg.Installation) {
+		ds.Spec.Template.Spec.Containers[1].ReadinessProbe = &corev1.Probe{
+			Handler:        corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"sh", "-c", vppBGPEstablished}}},
+			TimeoutSeconds: 5,
+			PeriodSeconds:  10,
+		}
+	}
+	// The agent withdraws the routes of the node when it shuts down, so it is drained like calico-node.
+	ds.Spec.Template.Spec.TerminationGracePeriodSeconds = nodeTerminationGracePeriod(c.cfg.Installation)
+	if script := nodeDrainScript(c.cfg.Installation); script != nil {
+		ds.Spec.Template.Spec.Containers[1].Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"/bin/sh", "-c", strings.Join(script, "; ")}}},
+		}
+	}
+	if gr := bgpGracefulRestart(c.cfg.Installation); gr != nil && gr.ConvergenceSeconds != nil {
+		ds.Spec.MinReadySeconds = *gr.ConvergenceSeconds
+	}
+	setNodeCriticalPod(&ds.Spec.Template)
+	return ds
+}
+
+// vppBGPEstablished succeeds once all the BGP sessions of the agent are established, so that a restarted node is only
+// ready once it has its routes back. It fails while gobgp fails or prints nothing, e.g. before the BGP daemon is up.
+const vppBGPEstablished = `out=$(gobgp neighbor) || exit 1; [ -n "$out" ] && echo "$out" | awk 'NR > 1 && $4 != "Establ" { down++ } END { exit down > 0 }'`
+
+// hugepagesDaemonSet reserves the hugepages of the VPPDataplane on the nodes of the VPP dataplane. Its pods are ready
+// once the hugepages of their node are reserved, which may take a while when the memory of the node is fragmented.
+func (c *vppDataplaneComponent) hugepagesDaemonSet() *appsv1.DaemonSet {
+	labels := map[string]string{"k8s-app": VPPHugepagesName}
+	h := c.cfg.VPPDataplane.Spec.Hugepages
+	reserved := c.hugepagesReserved()
+
+	ds := &appsv1.DaemonSet{
+		TypeMeta:   metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: VPPHugepagesName, Namespace: common.CalicoNamespace, Labels: labels},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					NodeSelector:       VPPNodeSelector(c.cfg.Installation),
+					Tolerations:        rmeta.TolerateAll,
+					ImagePullSecrets:   c.cfg.Installation.ImagePullSecrets,
+					ServiceAccountName: VPPNodeName,
+					HostNetwork:        true,
+					// The hugepages are reserved again as long as there aren't enough of them, since the reservation
+					// fails while the memory of the node is fragmented, and since the hugepages can be released, e.g.
+					// by the vm.nr_hugepages sysctl of node-init. The hugepages already reserved are kept when there
+					// are enough of them.
+					Containers: []corev1.Container{{
+						Name:  "hugepages",
+						Image: c.vppImage,
+						Command: []string{"sh", "-c", fmt.Sprintf(
+							"while true; do %s || echo %d > %s; sleep 10; done", reserved, h.Count, c.hugepagesPath(),
+						)},
+						SecurityContext: c.securityContext(),
+						ReadinessProbe: &corev1.Probe{
+							Handler:       corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"sh", "-c", reserved}}},
+							PeriodSeconds: 10,
+						},
+					}},
+				},
+			},
+		},
+	}
+	if c.runAsNonPrivileged() {
+		// The sysfs of non-privileged containers is read-only, so the hugepages are reserved through the sysfs of the
+		// host.
+		ds.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "hugepages", MountPath: vppHugepagesSysfsDir}}
+		ds.Spec.Template.Spec.Volumes = []corev1.Volume{
+			{Name: "hugepages", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: vppHugepagesSysfsDir}}},
+		}
+	}
+	setNodeCriticalPod(&ds.Spec.Template)
+	return ds
+}
+
+// runAsNonPrivileged returns whether the Installation runs Calico as non-privileged.
+func (c *vppDataplaneComponent) runAsNonPrivileged() bool {
+	return c.cfg.Installation.NonPrivileged != nil && *c.cfg.Installation.NonPrivileged == operatorv1.NonPrivilegedEnabled
+}
+
+// securityContext returns the security context of a container of the VPP dataplane. The containers run privileged,
+// unless the Installation runs Calico as non-privileged, in which case they only get the given capabilities.
+func (c *vppDataplaneComponent) securityContext(capabilities ...corev1.Capability) *corev1.SecurityContext {
+	if !c.runAsNonPrivileged() {
+		privileged := true
+		return &corev1.SecurityContext{Privileged: &privileged}
+	}
+	privileged, escalation := false, false
+	return &corev1.SecurityContext{
+		Privileged:               &privileged,
+		AllowPrivilegeEscalation: &escalation,
+		Capabilities: &corev1.Capabilities{
+			Add:  capabilities,
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
+// hugepagesPath returns the sysfs file of the number of hugepages of the size of the VPPDataplane.
+func (c *vppDataplaneComponent) hugepagesPath() string {
+	size := operatorv1.VPPHugepageSize2Mi
+	if s := c.cfg.VPPDataplane.Spec.Hugepages.Size; s != nil {
+		size = *s
+	}
+	return fmt.Sprintf("%s/%s/nr_hugepages", vppHugepagesSysfsDir, vppHugepageDirs[size])
+}
+
+// hugepagesReserved returns a shell condition which holds when the hugepages of the VPPDataplane are reserved.
+func (c *vppDataplaneComponent) hugepagesReserved() string {
+	return fmt.Sprintf(`[ "$(cat %s)" -ge %d ]`, c.hugepagesPath(), c.cfg.VPPDataplane.Spec.Hugepages.Count)
+}
+
+// kernelModules returns the kernel modules of the uplink drivers of the nodes with the given VPP profile or, with a nil
+// profile, of the nodes without a VPP profile: the uplink driver of the profile, or else the default driver of the
+// VPPDataplane, and the drivers of the uplink interfaces.
+func (c *vppDataplaneComponent) kernelModules(profile *operatorv1.VPPProfile) []string {
+	drivers := []*operatorv1.VPPUplinkDriver{c.cfg.VPPDataplane.Spec.UplinkDriver}
+	if profile != nil && profile.UplinkDriver != nil {
+		drivers[0] = profile.UplinkDriver
+	}
+	for _, u := range c.cfg.VPPDataplane.Spec.UplinkInterfaces {
+		drivers = append(drivers, u.Driver)
+	}
+	loaded := map[string]bool{}
+	var modules []string
+	for _, d := range drivers {
+		if d == nil {
+			continue
+		}
+		if m, ok := vppDriverKernelModules[*d]; ok && !loaded[m] {
+			modules = append(modules, m)
+			loaded[m] = true
+		}
+	}
+	sort.Strings(modules)
+	return modules
+}
@@ -0,0 +1,490 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("VPP dataplane rendering tests", func() {
+	var cfg *render.VPPDataplaneConfiguration
+	BeforeEach(func() {
+		cfg = &render.VPPDataplaneConfiguration{
+			Installation: &operatorv1.InstallationSpec{Variant: operatorv1.Calico, Registry: "test-reg/"},
+			VPPDataplane: &operatorv1.VPPDataplane{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		}
+	})
+
+	It("should render the calico-vpp-node DaemonSet with the default configuration", func() {
+		component := render.VPPDataplane(cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, toDelete := component.Objects()
+		Expect(toDelete).To(HaveLen(1))
+		rtest.ExpectResource(toDelete[0], render.VPPHugepagesName, common.CalicoNamespace, "apps", "v1", "DaemonSet")
+
+		expectedResources := []struct {
+			name    string
+			ns      string
+			group   string
+			version string
+			kind    string
+		}{
+			{name: render.VPPNodeName, ns: common.CalicoNamespace, group: "", version: "v1", kind: "ServiceAccount"},
+			{name: render.VPPNodeName, ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRole"},
+			{name: render.VPPNodeName, ns: "", group: "rbac.authorization.k8s.io", version: "v1", kind: "ClusterRoleBinding"},
+			{name: render.VPPNodeName, ns: common.CalicoNamespace, group: "rbac.authorization.k8s.io", version: "v1", kind: "Role"},
+			{name: render.VPPNodeName, ns: common.CalicoNamespace, group: "rbac.authorization.k8s.io", version: "v1", kind: "RoleBinding"},
+			{name: render.VPPConfigConfigMapName, ns: common.CalicoNamespace, group: "", version: "v1", kind: "ConfigMap"},
+			{name: render.VPPNodeName, ns: common.CalicoNamespace, group: "apps", version: "v1", kind: "DaemonSet"},
+		}
+		Expect(toCreate).To(HaveLen(len(expectedResources)))
+		for i, expectedRes := range expectedResources {
+			rtest.ExpectResource(toCreate[i], expectedRes.name, expectedRes.ns, expectedRes.group, expectedRes.version, expectedRes.kind)
+		}
+
+		cm := rtest.GetResource(toCreate, render.VPPConfigConfigMapName, common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).To(Equal(map[string]string{
+			render.VPPServicePrefixKey: render.DefaultVPPServicePrefix,
+			render.VPPLogLevelKey:      "info",
+			render.VPPGSOKey:           "true",
+		}))
+
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux"}))
+		Expect(ds.Spec.Template.Spec.Affinity).To(BeNil())
+		Expect(ds.Spec.Template.Labels).To(HaveKeyWithValue(render.VPPNodePodLabel, "true"))
+		Expect(ds.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/vpp-config"))
+		Expect(ds.Spec.Template.Spec.HostNetwork).To(BeTrue())
+		Expect(ds.Spec.Template.Spec.InitContainers).To(BeEmpty())
+		Expect(ds.Spec.Template.Spec.Containers).To(HaveLen(2))
+		vpp := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "vpp")
+		Expect(vpp.Image).To(Equal(fmt.Sprintf("test-reg/%s:%s", components.ComponentCalicoVPP.Image, components.ComponentCalicoVPP.Version)))
+		agent := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "agent")
+		Expect(agent.Image).To(Equal(fmt.Sprintf("test-reg/%s:%s", components.ComponentCalicoVPPAgent.Image, components.ComponentCalicoVPPAgent.Version)))
+		Expect(agent.Ports).To(Equal([]corev1.ContainerPort{{Name: render.VPPMetricsPortName, ContainerPort: render.VPPMetricsPort, Protocol: corev1.ProtocolTCP}}))
+
+		var envFrom []string
+		for _, e := range agent.EnvFrom {
+			envFrom = append(envFrom, e.ConfigMapRef.Name)
+		}
+		Expect(envFrom).To(ConsistOf(
+			render.VPPConfigConfigMapName,
+			render.VPPTunnelsConfigMapName,
+			render.VPPNAT64ConfigMapName,
+			render.VPPEventLogConfigMapName,
+		))
+	})
+
+	It("should only run on the migrated nodes while the nodes are migrated from the BPF dataplane", func() {
+		cfg.Installation.DataplaneMigration = &operatorv1.DataplaneMigration{}
+		cfg.VPPDataplane.Spec.Hugepages = &operatorv1.VPPHugepages{Count: 512}
+		toCreate, _ := render.VPPDataplane(cfg).Objects()
+
+		selector := map[string]string{"kubernetes.io/os": "linux", common.LinuxDataplaneLabel: "VPP"}
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(selector))
+		hp := rtest.GetResource(toCreate, render.VPPHugepagesName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(hp.Spec.Template.Spec.NodeSelector).To(Equal(selector))
+	})
+
+	It("should render a DaemonSet for the nodes of each VPP profile and delete the ones of the removed profiles", func() {
+		cfg.Installation.CalicoNetwork = &operatorv1.CalicoNetworkSpec{
+			VPPProfiles: []operatorv1.VPPProfile{{Name: "gateway"}, {Name: "edge"}},
+		}
+		cfg.ProfileDaemonSets = []appsv1.DaemonSet{
+			{ObjectMeta: metav1.ObjectMeta{Name: render.VPPNodeDaemonSetName("edge"), Namespace: common.CalicoNamespace}},
+			{ObjectMeta: metav1.ObjectMeta{Name: render.VPPNodeDaemonSetName("legacy"), Namespace: common.CalicoNamespace}},
+		}
+		toCreate, toDelete := render.VPPDataplane(cfg).Objects()
+		Expect(toDelete).To(HaveLen(2))
+		Expect(toDelete[0].GetName()).To(Equal(render.VPPNodeDaemonSetName("legacy")))
+
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: render.VPPProfileLabel, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"gateway", "edge"}}},
+		}}))
+
+		gw := rtest.GetResource(toCreate, "calico-vpp-node-gateway", common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(gw.Labels).To(HaveKeyWithValue(render.VPPProfileLabel, "gateway"))
+		Expect(gw.Spec.Selector.MatchLabels).To(Equal(map[string]string{"k8s-app": "calico-vpp-node-gateway"}))
+		Expect(gw.Spec.Template.Labels).To(HaveKeyWithValue(render.VPPNodePodLabel, "true"))
+		Expect(gw.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux", render.VPPProfileLabel: "gateway"}))
+		agent := rtest.GetContainer(gw.Spec.Template.Spec.Containers, "agent")
+		last := agent.EnvFrom[len(agent.EnvFrom)-1]
+		Expect(last.ConfigMapRef.Name).To(Equal(render.VPPProfileConfigMapName("gateway")))
+		Expect(last.ConfigMapRef.Optional).To(BeNil())
+		Expect(rtest.GetResource(toCreate, "calico-vpp-node-edge", common.CalicoNamespace, "apps", "v1", "DaemonSet")).NotTo(BeNil())
+	})
+
+	It("should restart the pods of the profiles whose configuration changes", func() {
+		cfg.Installation.CalicoNetwork = &operatorv1.CalicoNetworkSpec{
+			VPPProfiles: []operatorv1.VPPProfile{{Name: "gateway"}, {Name: "edge"}},
+		}
+		hashes := func() map[string]string {
+			toCreate, _ := render.VPPDataplane(cfg).Objects()
+			h := map[string]string{}
+			for _, name := range []string{render.VPPNodeName, "calico-vpp-node-gateway", "calico-vpp-node-edge"} {
+				ds := rtest.GetResource(toCreate, name, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+				h[name] = ds.Spec.Template.Annotations["hash.operator.tigera.io/vpp-config"]
+			}
+			return h
+		}
+		before := hashes()
+		cfg.AgentConfigMaps = []corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Name: render.VPPProfileConfigMapName("gateway"), Namespace: common.CalicoNamespace},
+			Data:       map[string]string{render.VPPDriverKey: "dpdk"},
+		}}
+		after := hashes()
+		Expect(after[render.VPPNodeName]).To(Equal(before[render.VPPNodeName]))
+		Expect(after["calico-vpp-node-edge"]).To(Equal(before["calico-vpp-node-edge"]))
+		Expect(after["calico-vpp-node-gateway"]).NotTo(Equal(before["calico-vpp-node-gateway"]))
+
+		cfg.VPPDataplane.Spec.UplinkInterface = "eth1"
+		Expect(hashes()[render.VPPNodeName]).NotTo(Equal(after[render.VPPNodeName]))
+	})
+
+	It("should render the uplink interface, the service prefixes and the dataplane options", func() {
+		debug := operatorv1.VPPLogLevelDebug
+		gso := operatorv1.VPPGSODisabled
+		cfg.VPPDataplane.Spec = operatorv1.VPPDataplaneSpec{
+			UplinkInterface: "eth1",
+			ServicePrefixes: []string{"10.96.0.0/16", "fd00:96::/112"},
+			LogLevel:        &debug,
+			GSO:             &gso,
+		}
+		toCreate, _ := render.VPPDataplane(cfg).Objects()
+
+		cm := rtest.GetResource(toCreate, render.VPPConfigConfigMapName, common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).To(Equal(map[string]string{
+			render.VPPInterfaceKey:     "eth1",
+			render.VPPServicePrefixKey: "10.96.0.0/16,fd00:96::/112",
+			render.VPPLogLevelKey:      "debug",
+			render.VPPGSOKey:           "false",
+		}))
+	})
+
+	It("should render the default uplink driver and load the kernel modules of the drivers of each profile", func() {
+		dpdk := operatorv1.VPPUplinkDriverDPDK
+		rdma := operatorv1.VPPUplinkDriverRDMA
+		afPacket := operatorv1.VPPUplinkDriverAFPacket
+		cfg.VPPDataplane.Spec.UplinkDriver = &dpdk
+		cfg.Installation.CalicoNetwork = &operatorv1.CalicoNetworkSpec{
+			VPPProfiles: []operatorv1.VPPProfile{
+				{Name: "mellanox", UplinkDriver: &rdma},
+				{Name: "virtual", UplinkDriver: &afPacket},
+			},
+		}
+		component := render.VPPDataplane(cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, _ := component.Objects()
+
+		cm := rtest.GetResource(toCreate, render.VPPConfigConfigMapName, common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).To(HaveKeyWithValue(render.VPPDriverKey, "dpdk"))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("plugin dpdk_plugin.so { enable }"))
+
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+		init := ds.Spec.Template.Spec.InitContainers[0]
+		Expect(init.Image).To(Equal(fmt.Sprintf("test-reg/%s:%s", components.ComponentCalicoVPP.Image, components.ComponentCalicoVPP.Version)))
+		Expect(init.Command).To(Equal([]string{"modprobe", "-a", "vfio-pci"}))
+		Expect(init.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "lib-modules", MountPath: "/lib/modules", ReadOnly: true}))
+
+		// The nodes of a profile only load the modules of its driver.
+		ds = rtest.GetResource(toCreate, render.VPPNodeDaemonSetName("mellanox"), common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+		Expect(ds.Spec.Template.Spec.InitContainers[0].Command).To(Equal([]string{"modprobe", "-a", "ib_uverbs"}))
+		ds = rtest.GetResource(toCreate, render.VPPNodeDaemonSetName("virtual"), common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.InitContainers).To(BeEmpty())
+	})
+
+	It("should render the buffers in the startup configuration of the nodes without a VPP profile", func() {
+		buffers, dataSize := int32(65536), int32(9216)
+		cfg.VPPDataplane.Spec.Buffers = &operatorv1.VPPBuffers{BuffersPerNUMA: &buffers, DataSize: &dataSize}
+		toCreate, _ := render.VPPDataplane(cfg).Objects()
+
+		cm := rtest.GetResource(toCreate, render.VPPConfigConfigMapName, common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).NotTo(HaveKey(render.VPPDriverKey))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("buffers {\n  buffers-per-numa 65536\n  default data-size 9216\n}\n"))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("plugin dpdk_plugin.so { disable }"))
+	})
+
+	It("should render the memory regions in the startup configuration and the resources of the vpp container", func() {
+		mainHeap, statSeg, apiSeg := resource.MustParse("2Gi"), resource.MustParse("768Mi"), resource.MustParse("65537")
+		cfg.VPPDataplane.Spec.Memory = &operatorv1.VPPMemory{MainHeapSize: &mainHeap, StatsSegmentSize: &statSeg, APISegmentSize: &apiSeg}
+		resources := corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi"), "hugepages-2Mi": resource.MustParse("512Mi")},
+		}
+		cfg.VPPDataplane.Spec.VPPResources = &resources
+		toCreate, _ := render.VPPDataplane(cfg).Objects()
+
+		cm := rtest.GetResource(toCreate, render.VPPConfigConfigMapName, common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("memory {\n  main-heap-size 2G\n}\n"))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("statseg {\n  size 768M\n}\n"))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("api-segment {\n  api-size 64K\n}\n"))
+
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		vpp := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "vpp")
+		Expect(vpp.Resources).To(Equal(resources))
+		Expect(rtest.GetContainer(ds.Spec.Template.Spec.Containers, "agent").Resources).To(Equal(corev1.ResourceRequirements{}))
+	})
+
+	It("should make the pods of the VPP profiles pinning the CPUs with the CPU manager Guaranteed", func() {
+		workers, dpdk := int32(3), operatorv1.VPPUplinkDriverDPDK
+		cfg.Installation.CalicoNetwork = &operatorv1.CalicoNetworkSpec{
+			VPPProfiles: []operatorv1.VPPProfile{{
+				Name:         "rt",
+				UplinkDriver: &dpdk,
+				Workers:      &workers,
+				CPUPinning:   &operatorv1.VPPCPUPinning{Mode: operatorv1.VPPCPUPinningCPUManager},
+			}},
+		}
+		cfg.VPPDataplane.Spec.VPPResources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+		}
+		cfg.VPPDataplane.Spec.AgentResources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+		}
+		toCreate, _ := render.VPPDataplane(cfg).Objects()
+
+		ds := rtest.GetResource(toCreate, render.VPPNodeDaemonSetName("rt"), common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		vppResources := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "vpp").Resources
+		Expect(vppResources.Limits.Cpu().Value()).To(Equal(int64(4)))
+		Expect(vppResources.Limits[corev1.ResourceMemory]).To(Equal(resource.MustParse("4Gi")))
+		Expect(vppResources.Requests).To(Equal(vppResources.Limits))
+		Expect(rtest.GetContainer(ds.Spec.Template.Spec.Containers, "agent").Resources.Requests).To(Equal(cfg.VPPDataplane.Spec.AgentResources.Limits))
+		Expect(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "load-kernel-modules").Resources).To(Equal(vppResources))
+
+		// The DaemonSet of the nodes without a profile isn't pinned, and the resources of the VPPDataplane aren't modified.
+		ds = rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(rtest.GetContainer(ds.Spec.Template.Spec.Containers, "vpp").Resources).To(Equal(*cfg.VPPDataplane.Spec.VPPResources))
+		Expect(cfg.VPPDataplane.Spec.VPPResources.Requests).To(BeNil())
+	})
+
+	It("should render the uplink interfaces and load the kernel modules of their drivers", func() {
+		rdma, adaptive := operatorv1.VPPUplinkDriverRDMA, operatorv1.VPPRxModeAdaptive
+		cfg.VPPDataplane.Spec.UplinkInterfaces = []operatorv1.VPPUplinkInterface{
+			{InterfaceName: "eth1"},
+			{PCIAddress: "0000:3b:00.1", Driver: &rdma, RxMode: &adaptive},
+		}
+		component := render.VPPDataplane(cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, _ := component.Objects()
+
+		cm := rtest.GetResource(toCreate, render.VPPConfigConfigMapName, common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).NotTo(HaveKey(render.VPPInterfaceKey))
+		Expect(cm.Data[render.VPPInterfacesKey]).To(MatchJSON(`{"uplinkInterfaces": [
+			{"interfaceName": "eth1"},
+			{"pciAddress": "0000:3b:00.1", "vppDriver": "rdma", "rxMode": "adaptive"}
+		]}`))
+
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+		Expect(ds.Spec.Template.Spec.InitContainers[0].Command).To(Equal([]string{"modprobe", "-a", "ib_uverbs"}))
+	})
+
+	It("should apply the BGP graceful restart timings and wait for the BGP sessions of the agent", func() {
+		var shutdown int64 = 30
+		var convergence int32 = 20
+		bgp := operatorv1.BGPEnabled
+		cfg.Installation.CalicoNetwork = &operatorv1.CalicoNetworkSpec{
+			BGP: &bgp,
+			BGPGracefulRestart: &operatorv1.BGPGracefulRestart{
+				ShutdownGracePeriodSeconds: &shutdown,
+				ConvergenceSeconds:         &convergence,
+			},
+		}
+		component := render.VPPDataplane(cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, _ := component.Objects()
+
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(*ds.Spec.Template.Spec.TerminationGracePeriodSeconds).To(BeEquivalentTo(30))
+		Expect(ds.Spec.MinReadySeconds).To(BeEquivalentTo(20))
+		agent := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "agent")
+		Expect(agent.ReadinessProbe.Exec.Command).To(HaveLen(3))
+		Expect(agent.ReadinessProbe.Exec.Command[:2]).To(Equal([]string{"sh", "-c"}))
+	})
+
+	Context("the readiness probe of the agent", func() {
+		var probe string
+		var binDir string
+
+		// gobgp fakes the gobgp CLI of the agent with a script printing the given output and exiting with the given
+		// status.
+		gobgp := func(output string, status int) {
+			script := fmt.Sprintf("#!/bin/sh\nprintf '%%s' '%s'\nexit %d\n", output, status)
+			Expect(ioutil.WriteFile(filepath.Join(binDir, "gobgp"), []byte(script), 0755)).To(Succeed())
+		}
+
+		runProbe := func() error {
+			cmd := exec.Command("sh", "-c", probe)
+			cmd.Env = []string{"PATH=" + binDir + ":/usr/bin:/bin"}
+			return cmd.Run()
+		}
+
+		BeforeEach(func() {
+			bgp := operatorv1.BGPEnabled
+			cfg.Installation.CalicoNetwork = &operatorv1.CalicoNetworkSpec{BGP: &bgp}
+			component := render.VPPDataplane(cfg)
+			Expect(component.ResolveImages(nil)).To(BeNil())
+			toCreate, _ := component.Objects()
+			ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+			probe = rtest.GetContainer(ds.Spec.Template.Spec.Containers, "agent").ReadinessProbe.Exec.Command[2]
+
+			var err error
+			binDir, err = ioutil.TempDir("", "gobgp")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(binDir)).To(Succeed())
+		})
+
+		It("should succeed once all the BGP sessions are established", func() {
+			gobgp("Peer AS Up/Down State |#Received Accepted\n10.0.0.2 65000 00:01:02 Establ | 0 0\n", 0)
+			Expect(runProbe()).To(Succeed())
+		})
+
+		It("should fail while a BGP session is not established", func() {
+			gobgp("Peer AS Up/Down State |#Received Accepted\n10.0.0.2 65000 00:01:02 Establ | 0 0\n10.0.0.3 65000 never Active | 0 0\n", 0)
+			Expect(runProbe()).To(HaveOccurred())
+		})
+
+		It("should fail when gobgp fails", func() {
+			gobgp("", 1)
+			Expect(runProbe()).To(HaveOccurred())
+		})
+
+		It("should fail when gobgp prints nothing", func() {
+			gobgp("", 0)
+			Expect(runProbe()).To(HaveOccurred())
+		})
+
+		It("should fail when gobgp is missing", func() {
+			Expect(runProbe()).To(HaveOccurred())
+		})
+	})
+
+	It("should drain the agent and give it the shutdown grace period of the NodeShutdown", func() {
+		var shutdown int64 = 30
+		var terminationGracePeriod int64 = 60
+		var drain int32 = 15
+		bgp := operatorv1.BGPEnabled
+		cfg.Installation.CalicoNetwork = &operatorv1.CalicoNetworkSpec{
+			BGP:                &bgp,
+			BGPGracefulRestart: &operatorv1.BGPGracefulRestart{ShutdownGracePeriodSeconds: &shutdown},
+		}
+		cfg.Installation.NodeShutdown = &operatorv1.NodeShutdown{
+			TerminationGracePeriodSeconds: &terminationGracePeriod,
+			DrainSeconds:                  &drain,
+			DrainCommand:                  "gobgp global rib del -a ipv4 10.0.0.0/24",
+		}
+		component := render.VPPDataplane(cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, _ := component.Objects()
+
+		// The NodeShutdown takes precedence over the BGP graceful restart, as on calico-node.
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(*ds.Spec.Template.Spec.TerminationGracePeriodSeconds).To(BeEquivalentTo(60))
+		agent := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "agent")
+		Expect(agent.Lifecycle).NotTo(BeNil())
+		Expect(agent.Lifecycle.PreStop.Exec.Command).To(Equal([]string{"/bin/sh", "-c",
+			"sleep 15; (gobgp global rib del -a ipv4 10.0.0.0/24)"}))
+		vpp := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "vpp")
+		Expect(vpp.Lifecycle).To(BeNil())
+	})
+
+	It("should not drain the agent when no drain is configured", func() {
+		component := render.VPPDataplane(cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, _ := component.Objects()
+
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(ds.Spec.Template.Spec.TerminationGracePeriodSeconds).To(BeNil())
+		agent := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "agent")
+		Expect(agent.Lifecycle).To(BeNil())
+	})
+
+	It("should reserve the hugepages and wait for them before starting VPP", func() {
+		size := operatorv1.VPPHugepageSize1Gi
+		cfg.VPPDataplane.Spec.Hugepages = &operatorv1.VPPHugepages{Count: 4, Size: &size}
+		component := render.VPPDataplane(cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, toDelete := component.Objects()
+		Expect(toDelete).To(BeEmpty())
+
+		reserved := `[ "$(cat /sys/kernel/mm/hugepages/hugepages-1048576kB/nr_hugepages)" -ge 4 ]`
+		hp := rtest.GetResource(toCreate, render.VPPHugepagesName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		Expect(hp.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux"}))
+		Expect(hp.Spec.Template.Spec.InitContainers).To(BeEmpty())
+		Expect(hp.Spec.Template.Spec.Containers[0].Command).To(Equal([]string{"sh", "-c",
+			"while true; do " + reserved + " || echo 4 > /sys/kernel/mm/hugepages/hugepages-1048576kB/nr_hugepages; sleep 10; done"}))
+		Expect(*hp.Spec.Template.Spec.Containers[0].SecurityContext.Privileged).To(BeTrue())
+		Expect(hp.Spec.Template.Spec.Containers[0].ReadinessProbe.Exec.Command).To(Equal([]string{"sh", "-c", reserved}))
+
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		wait := rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "wait-for-hugepages")
+		Expect(wait).NotTo(BeNil())
+		Expect(wait.Command).To(Equal([]string{"sh", "-c", "until " + reserved + "; do sleep 5; done"}))
+	})
+
+	It("should only add the capabilities the containers need when running as non-privileged", func() {
+		nonPrivileged := operatorv1.NonPrivilegedEnabled
+		cfg.Installation.NonPrivileged = &nonPrivileged
+		driver := operatorv1.VPPUplinkDriverDPDK
+		cfg.VPPDataplane.Spec.UplinkDriver = &driver
+		cfg.VPPDataplane.Spec.Hugepages = &operatorv1.VPPHugepages{Count: 512}
+		toCreate, _ := render.VPPDataplane(cfg).Objects()
+
+		expectCapabilities := func(c *corev1.Container, capabilities ...corev1.Capability) {
+			Expect(c).NotTo(BeNil())
+			Expect(*c.SecurityContext.Privileged).To(BeFalse())
+			Expect(*c.SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+			Expect(c.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+			Expect(c.SecurityContext.Capabilities.Add).To(ConsistOf(capabilities))
+		}
+		ds := rtest.GetResource(toCreate, render.VPPNodeName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		expectCapabilities(rtest.GetContainer(ds.Spec.Template.Spec.Containers, "vpp"),
+			"NET_ADMIN", "NET_RAW", "SYS_ADMIN", "IPC_LOCK", "SYS_NICE", "SYS_RESOURCE")
+		expectCapabilities(rtest.GetContainer(ds.Spec.Template.Spec.Containers, "agent"),
+			"NET_ADMIN", "NET_RAW", "NET_BIND_SERVICE", "SYS_ADMIN")
+		expectCapabilities(rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, "load-kernel-modules"), "SYS_MODULE")
+
+		// The hugepages are reserved through the sysfs of the host.
+		hp := rtest.GetResource(toCreate, render.VPPHugepagesName, common.CalicoNamespace, "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+		expectCapabilities(&hp.Spec.Template.Spec.Containers[0])
+		Expect(hp.Spec.Template.Spec.Containers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{{Name: "hugepages", MountPath: "/sys/kernel/mm/hugepages"}}))
+		Expect(hp.Spec.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(hp.Spec.Template.Spec.Volumes[0].HostPath.Path).To(Equal("/sys/kernel/mm/hugepages"))
+	})
+})
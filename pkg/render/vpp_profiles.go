@@ -0,0 +1,490 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+)
+
+const (
+	// VPPProfileLabel is set on the nodes to the name of their VPP profile, and on the ConfigMap of each profile.
+	VPPProfileLabel = "operator.tigera.io/vpp-profile"
+
+	// VPPDriverKey and VPPConfigTemplateKey are the keys of the ConfigMap of a profile holding the uplink driver and
+	// the VPP startup configuration. They are named after the environment variables of the VPP agent, so that the
+	// ConfigMap can be loaded with envFrom.
+	VPPDriverKey         = "CALICOVPP_NATIVE_DRIVER"
+	VPPConfigTemplateKey = "CALICOVPP_CONFIG_TEMPLATE"
+
+	// VPPRxModeKey is the key of the ConfigMap of a profile holding the rx mode of the interfaces of VPP, which is
+	// adaptive for the profiles sleeping when idle. The rx mode of an uplink in the VPPDataplane takes precedence.
+	VPPRxModeKey = "CALICOVPP_RX_MODE"
+
+	// VPPCryptoEngineKey is the key of the ConfigMap of a profile holding the crypto handler the VPP agent selects
+	// for IPsec and WireGuard.
+	VPPCryptoEngineKey = "CALICOVPP_CRYPTO_ENGINE"
+
+	// VPPQATNodeLabel is set to "true" by the Node Feature Discovery on the nodes with Intel QuickAssist devices. The
+	// profiles with the QAT crypto engine only match these nodes.
+	VPPQATNodeLabel = "intel.feature.node.kubernetes.io/qat"
+
+	// VPPTunnelsConfigMapName is the name of the ConfigMap holding the VPP tunnels of the installation, which is
+	// loaded with envFrom by the VPP agent on every node.
+	VPPTunnelsConfigMapName = "calico-vpp-tunnels"
+
+	// VPPTunnelsKey holds the tunnels and the CIDRs of the IP pools they encapsulate, in JSON.
+	VPPTunnelsKey = "CALICOVPP_TUNNELS"
+
+	// VPPNAT64ConfigMapName is the name of the ConfigMap holding the NAT64 configuration of the VPP agent, which is
+	// loaded with envFrom by the agent on every node. VPPNAT64PrefixKey holds the NAT64 prefix.
+	VPPNAT64ConfigMapName = "calico-vpp-nat64"
+	VPPNAT64PrefixKey     = "CALICOVPP_NAT64_PREFIX"
+
+	// VPPNAT64StatusAnnotation is set on the nodes by the VPP agent to "Ready" once it translates the traffic of the
+	// NAT64 prefix, or to the reason why it doesn't.
+	VPPNAT64StatusAnnotation = "projectcalico.org/vpp-nat64-status"
+	VPPNAT64StatusReady      = "Ready"
+
+	// VPPEventLogConfigMapName is the name of the ConfigMap holding the event log configuration of the VPP agent,
+	// which is loaded with envFrom by the agent on every node. VPPEventLogFileKey holds the file the agent writes the
+	// events of the dataplane to, which fluentd ships when the LogCollector collects the dataplane events.
+	VPPEventLogConfigMapName = "calico-vpp-event-log"
+	VPPEventLogFileKey       = "CALICOVPP_EVENT_LOG_FILE"
+	VPPEventLogFile          = "/var/log/calico/vpp/events.log"
+
+	// DefaultVPPNAT64Prefix is the well-known prefix of RFC 6052.
+	DefaultVPPNAT64Prefix = "64:ff9b::/96"
+
+	vppProfileConfigMapPrefix = "calico-vpp-profile-"
+)
+
+// vppTunnel is a VPP tunnel as read by the VPP agent.
+type vppTunnel struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Port     int32    `json:"port"`
+	Overhead int32    `json:"overhead"`
+	CIDRs    []string `json:"cidrs"`
+}
+
+// VPPProfileConfigMapName returns the name of the ConfigMap holding the configuration of the given VPP profile.
+func VPPProfileConfigMapName(profile string) string {
+	return vppProfileConfigMapPrefix + profile
+}
+
+// VPPProfiles renders a ConfigMap with the VPP configuration of each VPP profile of the installation. The ConfigMaps of
+// the profiles which were removed are deleted. With the VPP dataplane, it also renders the tunnels, the NAT64 and the
+// event log configuration of the VPP agent.
+func VPPProfiles(cfg *VPPProfilesConfiguration) Component {
+	return &vppProfilesComponent{cfg: cfg}
+}
+
+// VPPProfilesConfiguration contains all the config information needed to render the component.
+type VPPProfilesConfiguration struct {
+	Installation *operatorv1.InstallationSpec
+
+	// ProfileConfigMaps are the ConfigMaps carrying the VPPProfileLabel in the calico-system namespace.
+	ProfileConfigMaps []corev1.ConfigMap
+
+	// LogCollector is the LogCollector of the cluster, if any.
+	LogCollector *operatorv1.LogCollector
+
+	// StartupConfigOverrides are the validated overrides of the VPP startup configuration of the profiles, by the
+	// name of the profile.
+	StartupConfigOverrides map[string]string
+}
+
+type vppProfilesComponent struct {
+	cfg *VPPProfilesConfiguration
+}
+
+func (c *vppProfilesComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	// No images on a ConfigMap
+	return nil
+}
+
+func (c *vppProfilesComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeLinux
+}
+
+func (c *vppProfilesComponent) Objects() ([]client.Object, []client.Object) {
+	var toCreate, toDelete []client.Object
+	desired := map[string]bool{}
+	if c.cfg.Installation.CalicoNetwork != nil {
+		for i := range c.cfg.Installation.CalicoNetwork.VPPProfiles {
+			p := &c.cfg.Installation.CalicoNetwork.VPPProfiles[i]
+			cm := vppProfileConfigMap(p, c.cfg.StartupConfigOverrides[p.Name])
+			desired[cm.Name] = true
+			toCreate = append(toCreate, cm)
+		}
+	}
+	for i := range c.cfg.ProfileConfigMaps {
+		if !desired[c.cfg.ProfileConfigMaps[i].Name] {
+			toDelete = append(toDelete, c.cfg.ProfileConfigMaps[i].DeepCopy())
+		}
+	}
+
+	if cm := c.tunnelsConfigMap(); cm != nil {
+		toCreate = append(toCreate, cm)
+	} else {
+		toDelete = append(toDelete, &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: VPPTunnelsConfigMapName, Namespace: common.CalicoNamespace},
+		})
+	}
+
+	if cm := c.nat64ConfigMap(); cm != nil {
+		toCreate = append(toCreate, cm)
+	} else {
+		toDelete = append(toDelete, &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: VPPNAT64ConfigMapName, Namespace: common.CalicoNamespace},
+		})
+	}
+
+	if collectDataplaneEvents(c.cfg.Installation, c.cfg.LogCollector) {
+		toCreate = append(toCreate, &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      VPPEventLogConfigMapName,
+				Namespace: common.CalicoNamespace,
+			},
+			Data: map[string]string{VPPEventLogFileKey: VPPEventLogFile},
+		})
+	} else {
+		toDelete = append(toDelete, &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: VPPEventLogConfigMapName, Namespace: common.CalicoNamespace},
+		})
+	}
+	return toCreate, toDelete
+}
+
+func (c *vppProfilesComponent) Ready() bool {
+	return true
+}
+
+// nat64ConfigMap returns the ConfigMap with the NAT64 configuration of the VPP agent, or nil if the VPP dataplane is
+// not enabled or NAT64 is not configured.
+func (c *vppProfilesComponent) nat64ConfigMap() *corev1.ConfigMap {
+	cn := c.cfg.Installation.CalicoNetwork
+	if cn == nil || cn.LinuxDataplane == nil || *cn.LinuxDataplane != operatorv1.LinuxDataplaneVPP || cn.VPPNAT64 == nil {
+		return nil
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      VPPNAT64ConfigMapName,
+			Namespace: common.CalicoNamespace,
+		},
+		Data: map[string]string{VPPNAT64PrefixKey: cn.VPPNAT64.Prefix},
+	}
+}
+
+// tunnelsConfigMap returns the ConfigMap with the VPP tunnels and the IP pools they encapsulate, or nil if the VPP
+// dataplane is not enabled or no tunnel is declared. The Installation validation ensures that the MTU leaves room for
+// the overhead of the tunnels in use.
+func (c *vppProfilesComponent) tunnelsConfigMap() *corev1.ConfigMap {
+	cn := c.cfg.Installation.CalicoNetwork
+	if cn == nil || cn.LinuxDataplane == nil || *cn.LinuxDataplane != operatorv1.LinuxDataplaneVPP || len(cn.VPPTunnels) == 0 {
+		return nil
+	}
+	var tunnels []vppTunnel
+	for _, t := range cn.VPPTunnels {
+		tunnel := vppTunnel{Name: t.Name, Type: string(t.Type), CIDRs: []string{}}
+		if t.Port != nil {
+			tunnel.Port = *t.Port
+		}
+		if t.Overhead != nil {
+			tunnel.Overhead = *t.Overhead
+		}
+		for _, pool := range cn.IPPools {
+			if pool.VPPTunnel == t.Name {
+				tunnel.CIDRs = append(tunnel.CIDRs, pool.CIDR)
+			}
+		}
+		tunnels = append(tunnels, tunnel)
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      VPPTunnelsConfigMapName,
+			Namespace: common.CalicoNamespace,
+		},
+		Data: map[string]string{VPPTunnelsKey: vppConfigJSON(tunnels)},
+	}
+}
+
+func vppProfileConfigMap(p *operatorv1.VPPProfile, override string) *corev1.ConfigMap {
+	conf := vppStartupConfig(p)
+	if override != "" {
+		conf = mergeVPPStartupConfig(conf, override)
+	}
+	data := map[string]string{VPPConfigTemplateKey: conf}
+	if p.UplinkDriver != nil {
+		data[VPPDriverKey] = string(*p.UplinkDriver)
+	}
+	if sleepWhenIdle(p) {
+		data[VPPRxModeKey] = "adaptive"
+	}
+	if p.CryptoEngine != nil {
+		if e, ok := vppCryptoEngines[*p.CryptoEngine]; ok {
+			data[VPPCryptoEngineKey] = e.handler
+		}
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      VPPProfileConfigMapName(p.Name),
+			Namespace: common.CalicoNamespace,
+			Labels:    map[string]string{VPPProfileLabel: p.Name},
+		},
+		Data: data,
+	}
+}
+
+const vppDPDKPlugin = "dpdk_plugin.so"
+
+// vppDataplanePlugins are the plugins the Calico plugin depends on to program the services, the policies and the
+// NAT of the pods. They are built as plugins and are not loaded when the plugins are disabled by default.
+var vppDataplanePlugins = []string{"calico_plugin.so", "cnat_plugin.so", "acl_plugin.so", "nat_plugin.so", "capo_plugin.so"}
+
+// vppDriverPlugins are the plugins of the uplink drivers which are not built into VPP.
+var vppDriverPlugins = map[operatorv1.VPPUplinkDriver]string{
+	operatorv1.VPPUplinkDriverAFXDP:   "af_xdp_plugin.so",
+	operatorv1.VPPUplinkDriverAVF:     "avf_plugin.so",
+	operatorv1.VPPUplinkDriverDPDK:    vppDPDKPlugin,
+	operatorv1.VPPUplinkDriverRDMA:    "rdma_plugin.so",
+	operatorv1.VPPUplinkDriverVMXNET3: "vmxnet3_plugin.so",
+}
+
+// vppCryptoEngines are the crypto handlers of VPP for the crypto engines, and the plugins providing them.
+var vppCryptoEngines = map[operatorv1.VPPCryptoEngine]struct{ handler, plugin string }{
+	operatorv1.VPPCryptoEngineNative:  {"native", "crypto_native_plugin.so"},
+	operatorv1.VPPCryptoEngineIPsecMB: {"ipsecmb", "crypto_ipsecmb_plugin.so"},
+	operatorv1.VPPCryptoEngineQAT:     {"dpdk_cryptodev", vppDPDKPlugin},
+	operatorv1.VPPCryptoEngineAsync:   {"sw_scheduler", "crypto_sw_scheduler_plugin.so"},
+}
+
+// collectDataplaneEvents returns true if the VPP agent logs the events of the dataplane for fluentd to ship them.
+func collectDataplaneEvents(install *operatorv1.InstallationSpec, lc *operatorv1.LogCollector) bool {
+	cn := install.CalicoNetwork
+	return cn != nil && cn.LinuxDataplane != nil && *cn.LinuxDataplane == operatorv1.LinuxDataplaneVPP &&
+		lc != nil && lc.Spec.CollectDataplaneEvents != nil && *lc.Spec.CollectDataplaneEvents == operatorv1.CollectDataplaneEventsEnable
+}
+
+// sleepWhenIdle returns true if VPP sleeps when idle on the nodes of the given profile.
+func sleepWhenIdle(p *operatorv1.VPPProfile) bool {
+	return p.PowerProfile != nil && *p.PowerProfile == operatorv1.VPPPowerProfileSleepWhenIdle
+}
+
+// vppStartupConfig returns the VPP startup configuration of the given profile. Unless it is explicitly enabled, the
+// DPDK plugin is only loaded when the uplink uses the DPDK driver, since it takes over the PCI devices it finds
+// otherwise.
+func vppStartupConfig(p *operatorv1.VPPProfile) string {
+	var b strings.Builder
+	b.WriteString(`unix {
+  nodaemon
+  full-coredump
+  cli-listen /var/run/vpp/cli.sock
+  pidfile /run/vpp/vpp.pid
+  exec /etc/vpp/startup.exec
+`)
+	if sleepWhenIdle(p) {
+		// The main thread sleeps between its polls instead of spinning.
+		b.WriteString("  poll-sleep-usec 100\n")
+	}
+	b.WriteString(`}
+api-trace { on }
+socksvr {
+  socket-name /var/run/vpp/vpp-api.sock
+}
+`)
+	if lines := vppCPUConfig(p); len(lines) > 0 {
+		fmt.Fprintf(&b, "cpu {\n  %s\n}\n", strings.Join(lines, "\n  "))
+	}
+	var buffers []string
+	if p.BuffersPerNUMA != nil {
+		buffers = append(buffers, fmt.Sprintf("buffers-per-numa %d", *p.BuffersPerNUMA))
+	}
+	if p.BufferDataSize != nil {
+		buffers = append(buffers, fmt.Sprintf("default data-size %d", *p.BufferDataSize))
+	}
+	if len(buffers) > 0 {
+		fmt.Fprintf(&b, "buffers {\n  %s\n}\n", strings.Join(buffers, "\n  "))
+	}
+	if m := p.Memory; m != nil {
+		if m.MainHeapSize != nil {
+			fmt.Fprintf(&b, "memory {\n  main-heap-size %s\n}\n", vppMemorySize(*m.MainHeapSize))
+		}
+		if m.StatsSegmentSize != nil {
+			fmt.Fprintf(&b, "statseg {\n  size %s\n}\n", vppMemorySize(*m.StatsSegmentSize))
+		}
+		if m.APISegmentSize != nil {
+			fmt.Fprintf(&b, "api-segment {\n  api-size %s\n}\n", vppMemorySize(*m.APISegmentSize))
+		}
+	}
+	if d := p.DPDK; d != nil {
+		// With dev directives, DPDK only probes the listed devices.
+		var lines []string
+		for _, dev := range d.AllowedPCIDevices {
+			lines = append(lines, "dev "+dev)
+		}
+		for _, dev := range d.DeniedPCIDevices {
+			lines = append(lines, "blocklist "+dev)
+		}
+		if d.LogLevel != nil {
+			lines = append(lines, "log-level "+string(*d.LogLevel))
+		}
+		lines = append(lines, d.ExtraArgs...)
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "dpdk {\n  %s\n}\n", strings.Join(lines, "\n  "))
+		}
+	}
+	b.WriteString("plugins {\n")
+	plugins := p.Plugins
+	if plugins == nil {
+		plugins = &operatorv1.VPPPlugins{}
+	}
+	if plugins.Default != nil && *plugins.Default == operatorv1.VPPPluginDefaultDisabled {
+		b.WriteString("  plugin default { disable }\n")
+	} else {
+		b.WriteString("  plugin default { enable }\n")
+	}
+	// A plugin is only listed once, the required and the enabled plugins taking precedence.
+	listed := map[string]bool{}
+	for _, plugin := range append(VPPRequiredPlugins(p), plugins.Enabled...) {
+		if !listed[plugin] {
+			fmt.Fprintf(&b, "  plugin %s { enable }\n", plugin)
+			listed[plugin] = true
+		}
+	}
+	disabled := plugins.Disabled
+	if !listed[vppDPDKPlugin] {
+		disabled = append([]string{vppDPDKPlugin}, disabled...)
+	}
+	for _, plugin := range disabled {
+		if !listed[plugin] {
+			fmt.Fprintf(&b, "  plugin %s { disable }\n", plugin)
+			listed[plugin] = true
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// vppCPUConfig returns the directives of the cpu section of the VPP startup configuration of the profile.
+func vppCPUConfig(p *operatorv1.VPPProfile) []string {
+	var lines []string
+	pin := p.CPUPinning
+	switch {
+	case VPPCPUManagerPinning(p):
+		// The cores are numbered in the CPU set of the container, the exclusive CPUs assigned by the CPU manager, the
+		// main thread running on the first CPU.
+		lines = append(lines, "relative", "main-core 0")
+		if p.Workers != nil && *p.Workers == 1 {
+			lines = append(lines, "corelist-workers 1")
+		} else if p.Workers != nil && *p.Workers > 1 {
+			lines = append(lines, fmt.Sprintf("corelist-workers 1-%d", *p.Workers))
+		}
+		return lines
+	case pin != nil && pin.Mode == operatorv1.VPPCPUPinningManual:
+		if pin.MainCore != nil {
+			lines = append(lines, fmt.Sprintf("main-core %d", *pin.MainCore))
+		}
+		if pin.WorkerCores != "" {
+			return append(lines, "corelist-workers "+pin.WorkerCores)
+		}
+	}
+	if p.Workers != nil {
+		lines = append(lines, fmt.Sprintf("workers %d", *p.Workers))
+	}
+	return lines
+}
+
+// vppMemorySize returns the given size in the notation of the VPP startup configuration, rounded down to a multiple of
+// 1Ki.
+func vppMemorySize(q resource.Quantity) string {
+	kib := q.Value() / 1024
+	switch {
+	case kib%(1024*1024) == 0:
+		return fmt.Sprintf("%dG", kib/(1024*1024))
+	case kib%1024 == 0:
+		return fmt.Sprintf("%dM", kib/1024)
+	}
+	return fmt.Sprintf("%dK", kib)
+}
+
+// The sizes of the memory regions VPP allocates when they aren't set.
+var (
+	vppDefaultMainHeapSize     = resource.MustParse("1Gi")
+	vppDefaultStatsSegmentSize = resource.MustParse("32Mi")
+	vppDefaultAPISegmentSize   = resource.MustParse("16Mi")
+)
+
+// ValidateVPPMemory validates the sizes of the memory regions of VPP, which must be at least 1Ki, and returns their
+// total, counting the VPP default size of the regions which aren't set. The memory may be nil.
+func ValidateVPPMemory(m *operatorv1.VPPMemory) (int64, error) {
+	if m == nil {
+		m = &operatorv1.VPPMemory{}
+	}
+	var total int64
+	for _, region := range []struct {
+		field string
+		size  *resource.Quantity
+		def   resource.Quantity
+	}{
+		{"mainHeapSize", m.MainHeapSize, vppDefaultMainHeapSize},
+		{"statsSegmentSize", m.StatsSegmentSize, vppDefaultStatsSegmentSize},
+		{"apiSegmentSize", m.APISegmentSize, vppDefaultAPISegmentSize},
+	} {
+		if region.size == nil {
+			total += region.def.Value()
+			continue
+		}
+		if region.size.Value() < 1024 {
+			return 0, fmt.Errorf("%s should be at least 1Ki", region.field)
+		}
+		total += region.size.Value()
+	}
+	return total, nil
+}
+
+// VPPRequiredPlugins returns the plugins VPP must load on the nodes of the given profile: the plugin of Calico and the
+// ones it depends on, the plugin of the uplink driver, if it isn't built into VPP, and the plugin of the crypto engine.
+func VPPRequiredPlugins(p *operatorv1.VPPProfile) []string {
+	plugins := append([]string{}, vppDataplanePlugins...)
+	if p.UplinkDriver != nil {
+		if plugin, ok := vppDriverPlugins[*p.UplinkDriver]; ok {
+			plugins = append(plugins, plugin)
+		}
+	}
+	if p.CryptoEngine != nil {
+		if e, ok := vppCryptoEngines[*p.CryptoEngine]; ok && e.plugin != plugins[len(plugins)-1] {
+			plugins = append(plugins, e.plugin)
+		}
+	}
+	return plugins
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("VPP profiles rendering tests", func() {
+	var cfg *render.VPPProfilesConfiguration
+
+	BeforeEach(func() {
+		dpdk := operatorv1.VPPUplinkDriverDPDK
+		workers := int32(4)
+		buffers := int32(131072)
+		dataSize := int32(9216)
+		sleepWhenIdle := operatorv1.VPPPowerProfileSleepWhenIdle
+		cfg = &render.VPPProfilesConfiguration{
+			Installation: &operatorv1.InstallationSpec{
+				CalicoNetwork: &operatorv1.CalicoNetworkSpec{
+					VPPProfiles: []operatorv1.VPPProfile{
+						{
+							Name:           "dpdk",
+							NodeSelector:   map[string]string{"nic": "mlx5"},
+							UplinkDriver:   &dpdk,
+							Workers:        &workers,
+							BuffersPerNUMA: &buffers,
+							BufferDataSize: &dataSize,
+						},
+						{Name: "default", PowerProfile: &sleepWhenIdle},
+					},
+				},
+			},
+		}
+	})
+
+	It("should render a ConfigMap per profile", func() {
+		component := render.VPPProfiles(cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, toDelete := component.Objects()
+		Expect(toCreate).To(HaveLen(2))
+
+		// The tunnels, NAT64 and the event log are only rendered with the VPP dataplane.
+		Expect(toDelete).To(HaveLen(3))
+		rtest.ExpectResource(toDelete[0], render.VPPTunnelsConfigMapName, "calico-system", "", "v1", "ConfigMap")
+		rtest.ExpectResource(toDelete[1], render.VPPNAT64ConfigMapName, "calico-system", "", "v1", "ConfigMap")
+		rtest.ExpectResource(toDelete[2], render.VPPEventLogConfigMapName, "calico-system", "", "v1", "ConfigMap")
+
+		cm := rtest.GetResource(toCreate, "calico-vpp-profile-dpdk", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Labels).To(HaveKeyWithValue(render.VPPProfileLabel, "dpdk"))
+		Expect(cm.Data).To(HaveKeyWithValue(render.VPPDriverKey, "dpdk"))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("cpu {\n  workers 4\n}\n"))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("buffers {\n  buffers-per-numa 131072\n  default data-size 9216\n}\n"))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).NotTo(ContainSubstring("dpdk_plugin.so { disable }"))
+		Expect(cm.Data).NotTo(HaveKey(render.VPPRxModeKey))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).NotTo(ContainSubstring("poll-sleep-usec"))
+
+		cm = rtest.GetResource(toCreate, "calico-vpp-profile-default", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).NotTo(HaveKey(render.VPPDriverKey))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).NotTo(ContainSubstring("cpu {"))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("dpdk_plugin.so { disable }"))
+
+		// The default profile trades throughput for power.
+		Expect(cm.Data).To(HaveKeyWithValue(render.VPPRxModeKey, "adaptive"))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("  exec /etc/vpp/startup.exec\n  poll-sleep-usec 100\n}\n"))
+	})
+
+	It("should delete the ConfigMaps of the removed profiles", func() {
+		cfg.ProfileConfigMaps = []corev1.ConfigMap{
+			{ObjectMeta: metav1.ObjectMeta{Name: "calico-vpp-profile-dpdk", Namespace: "calico-system"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "calico-vpp-profile-virtio", Namespace: "calico-system"}},
+		}
+		toCreate, toDelete := render.VPPProfiles(cfg).Objects()
+		Expect(toCreate).To(HaveLen(2))
+		Expect(toDelete).To(HaveLen(4))
+		Expect(toDelete[0].GetName()).To(Equal("calico-vpp-profile-virtio"))
+	})
+
+	It("should render the DPDK configuration of a profile", func() {
+		debug := operatorv1.VPPDPDKLogLevelDebug
+		cfg.Installation.CalicoNetwork.VPPProfiles[0].DPDK = &operatorv1.VPPDPDK{
+			AllowedPCIDevices: []string{"0000:00:08.0", "0000:00:09.0"},
+			ExtraArgs:         []string{"socket-mem 1024,1024"},
+			LogLevel:          &debug,
+		}
+		cfg.Installation.CalicoNetwork.VPPProfiles[1].DPDK = &operatorv1.VPPDPDK{}
+		toCreate, _ := render.VPPProfiles(cfg).Objects()
+
+		cm := rtest.GetResource(toCreate, "calico-vpp-profile-dpdk", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring(
+			"dpdk {\n  dev 0000:00:08.0\n  dev 0000:00:09.0\n  log-level debug\n  socket-mem 1024,1024\n}\n"))
+
+		cm = rtest.GetResource(toCreate, "calico-vpp-profile-default", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data[render.VPPConfigTemplateKey]).NotTo(ContainSubstring("dpdk {"))
+	})
+
+	It("should load the plugin of the crypto engine of a profile", func() {
+		ipsecmb := operatorv1.VPPCryptoEngineIPsecMB
+		qat := operatorv1.VPPCryptoEngineQAT
+		cfg.Installation.CalicoNetwork.VPPProfiles[0].CryptoEngine = &qat
+		cfg.Installation.CalicoNetwork.VPPProfiles[1].CryptoEngine = &ipsecmb
+		toCreate, _ := render.VPPProfiles(cfg).Objects()
+
+		// QAT is driven by the DPDK plugin, which the dpdk uplink driver already loads.
+		cm := rtest.GetResource(toCreate, "calico-vpp-profile-dpdk", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).To(HaveKeyWithValue(render.VPPCryptoEngineKey, "dpdk_cryptodev"))
+		Expect(render.VPPRequiredPlugins(&cfg.Installation.CalicoNetwork.VPPProfiles[0])).To(Equal([]string{
+			"calico_plugin.so", "cnat_plugin.so", "acl_plugin.so", "nat_plugin.so", "capo_plugin.so", "dpdk_plugin.so",
+		}))
+
+		cm = rtest.GetResource(toCreate, "calico-vpp-profile-default", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).To(HaveKeyWithValue(render.VPPCryptoEngineKey, "ipsecmb"))
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("  plugin crypto_ipsecmb_plugin.so { enable }\n"))
+	})
+
+	It("should configure the event log of the VPP agent when the LogCollector collects the dataplane events", func() {
+		enabled := operatorv1.CollectDataplaneEventsEnable
+		cfg.LogCollector = &operatorv1.LogCollector{Spec: operatorv1.LogCollectorSpec{CollectDataplaneEvents: &enabled}}
+		toCreate, _ := render.VPPProfiles(cfg).Objects()
+		Expect(toCreate).To(HaveLen(2))
+
+		vpp := operatorv1.LinuxDataplaneVPP
+		cfg.Installation.CalicoNetwork.LinuxDataplane = &vpp
+		toCreate, _ = render.VPPProfiles(cfg).Objects()
+		cm := rtest.GetResource(toCreate, render.VPPEventLogConfigMapName, "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).To(Equal(map[string]string{render.VPPEventLogFileKey: "/var/log/calico/vpp/events.log"}))
+	})
+
+	It("should render the VPP tunnels and the CIDRs of the pools they encapsulate", func() {
+		vpp := operatorv1.LinuxDataplaneVPP
+		gtpuPort, gtpuOverhead := int32(2152), int32(36)
+		genevePort, geneveOverhead := int32(6081), int32(50)
+		cfg.Installation.CalicoNetwork.LinuxDataplane = &vpp
+		cfg.Installation.CalicoNetwork.IPPools = []operatorv1.IPPool{
+			{CIDR: "192.168.0.0/16", VPPTunnel: "gtpu"},
+			{CIDR: "fd00::/64"},
+		}
+		cfg.Installation.CalicoNetwork.VPPTunnels = []operatorv1.VPPTunnel{
+			{Name: "gtpu", Type: operatorv1.VPPTunnelGTPU, Port: &gtpuPort, Overhead: &gtpuOverhead},
+			{Name: "geneve", Type: operatorv1.VPPTunnelGENEVE, Port: &genevePort, Overhead: &geneveOverhead},
+		}
+		toCreate, _ := render.VPPProfiles(cfg).Objects()
+
+		cm := rtest.GetResource(toCreate, render.VPPTunnelsConfigMapName, "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data[render.VPPTunnelsKey]).To(MatchJSON(`[
+			{"name": "gtpu", "type": "GTPU", "port": 2152, "overhead": 36, "cidrs": ["192.168.0.0/16"]},
+			{"name": "geneve", "type": "GENEVE", "port": 6081, "overhead": 50, "cidrs": []}
+		]`))
+	})
+
+	It("should render the NAT64 prefix of the VPP agent", func() {
+		vpp := operatorv1.LinuxDataplaneVPP
+		cfg.Installation.CalicoNetwork.LinuxDataplane = &vpp
+		cfg.Installation.CalicoNetwork.VPPNAT64 = &operatorv1.VPPNAT64{Prefix: "64:ff9b::/96"}
+		toCreate, _ := render.VPPProfiles(cfg).Objects()
+
+		cm := rtest.GetResource(toCreate, render.VPPNAT64ConfigMapName, "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data).To(Equal(map[string]string{render.VPPNAT64PrefixKey: "64:ff9b::/96"}))
+	})
+
+	It("should render the plugins of a profile", func() {
+		disabled := operatorv1.VPPPluginDefaultDisabled
+		avf := operatorv1.VPPUplinkDriverAVF
+		cfg.Installation.CalicoNetwork.VPPProfiles[1].UplinkDriver = &avf
+		cfg.Installation.CalicoNetwork.VPPProfiles[1].Plugins = &operatorv1.VPPPlugins{
+			Default:  &disabled,
+			Enabled:  []string{"crypto_native_plugin.so", "calico_plugin.so"},
+			Disabled: []string{"nat_plugin.so", "lb_plugin.so"},
+		}
+		toCreate, _ := render.VPPProfiles(cfg).Objects()
+
+		cm := rtest.GetResource(toCreate, "calico-vpp-profile-default", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(HaveSuffix(`plugins {
+  plugin default { disable }
+  plugin calico_plugin.so { enable }
+  plugin cnat_plugin.so { enable }
+  plugin acl_plugin.so { enable }
+  plugin nat_plugin.so { enable }
+  plugin capo_plugin.so { enable }
+  plugin avf_plugin.so { enable }
+  plugin crypto_native_plugin.so { enable }
+  plugin dpdk_plugin.so { disable }
+  plugin lb_plugin.so { disable }
+}
+`))
+	})
+
+	It("should pin the threads of VPP to the cores of the profile", func() {
+		mainCore := int32(1)
+		profiles := cfg.Installation.CalicoNetwork.VPPProfiles
+		profiles[0].CPUPinning = &operatorv1.VPPCPUPinning{Mode: operatorv1.VPPCPUPinningCPUManager}
+		profiles[1].CPUPinning = &operatorv1.VPPCPUPinning{Mode: operatorv1.VPPCPUPinningManual, MainCore: &mainCore, WorkerCores: "2-5,8"}
+		toCreate, _ := render.VPPProfiles(cfg).Objects()
+
+		cm := rtest.GetResource(toCreate, "calico-vpp-profile-dpdk", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("cpu {\n  relative\n  main-core 0\n  corelist-workers 1-4\n}\n"))
+		cm = rtest.GetResource(toCreate, "calico-vpp-profile-default", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(ContainSubstring("cpu {\n  main-core 1\n  corelist-workers 2-5,8\n}\n"))
+	})
+
+	It("should merge the override of the startup configuration of a profile", func() {
+		cfg.StartupConfigOverrides = map[string]string{"dpdk": `cpu {
+  workers 8
+  main-core 1
+}
+dpdk { dev 0000:00:08.0 { num-rx-queues 4 } }
+`}
+		toCreate, _ := render.VPPProfiles(cfg).Objects()
+
+		cm := rtest.GetResource(toCreate, "calico-vpp-profile-dpdk", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		conf := cm.Data[render.VPPConfigTemplateKey]
+		Expect(conf).To(ContainSubstring("cpu {\n  workers 8\n  main-core 1\n}\n"))
+		Expect(conf).To(ContainSubstring("  exec /etc/vpp/startup.exec\n"))
+		Expect(conf).To(HaveSuffix("dpdk {\n  dev 0000:00:08.0 { num-rx-queues 4 }\n}\n"))
+
+		// The other profiles keep the generated configuration.
+		cm = rtest.GetResource(toCreate, "calico-vpp-profile-default", "calico-system", "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(cm.Data[render.VPPConfigTemplateKey]).To(HavePrefix("unix {\n  nodaemon\n"))
+	})
+
+	It("should reject the overrides of the directives the dataplane relies on", func() {
+		Expect(render.ValidateVPPStartupConfigOverride("buffers {\n  buffers-per-numa 65536\n}\n")).NotTo(HaveOccurred())
+		Expect(render.ValidateVPPStartupConfigOverride("unix { cli-listen 0.0.0.0:5002 }")).To(MatchError("the cli-listen directive of the unix section can't be overridden"))
+		Expect(render.ValidateVPPStartupConfigOverride("socksvr { default }")).To(MatchError("the socksvr section can't be overridden"))
+		Expect(render.ValidateVPPStartupConfigOverride("plugins { plugin nat_plugin.so { enable } }")).To(MatchError("the plugins section can't be overridden"))
+		Expect(render.ValidateVPPStartupConfigOverride("cpu { workers 2 ")).To(MatchError("section cpu is not closed"))
+		Expect(render.ValidateVPPStartupConfigOverride("workers 2")).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,197 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// vppDeniedSections are the sections of the VPP startup configuration which can't be overridden: the VPP agent talks
+// to VPP through the API socket, and the plugins are selected with the plugins of the profile.
+var vppDeniedSections = map[string]bool{
+	"socksvr": true,
+	"plugins": true,
+}
+
+// vppDeniedDirectives are the directives of the unix section which can't be overridden: they run arbitrary commands,
+// or move the sockets and files the VPP agent relies on.
+var vppDeniedDirectives = map[string]bool{
+	"exec":           true,
+	"startup-config": true,
+	"cli-listen":     true,
+	"pidfile":        true,
+	"interactive":    true,
+}
+
+// vppConfigSection is a top level section of the VPP startup configuration, e.g. unix { ... }.
+type vppConfigSection struct {
+	name       string
+	directives []vppConfigDirective
+}
+
+// vppConfigDirective is a directive of a section. The key of a directive is its first word, or the header of its
+// block for the directives with a block, e.g. "dev 0000:00:08.0" for dev 0000:00:08.0 { num-rx-queues 2 }.
+type vppConfigDirective struct {
+	key  string
+	text string
+}
+
+// ValidateVPPStartupConfigOverride validates an override of the VPP startup configuration: it must parse, and must
+// not contain any of the directives the dataplane relies on.
+func ValidateVPPStartupConfigOverride(override string) error {
+	sections, err := parseVPPStartupConfig(override)
+	if err != nil {
+		return err
+	}
+	for _, s := range sections {
+		if vppDeniedSections[s.name] {
+			return fmt.Errorf("the %s section can't be overridden", s.name)
+		}
+		if s.name != "unix" {
+			continue
+		}
+		for _, d := range s.directives {
+			if vppDeniedDirectives[d.key] {
+				return fmt.Errorf("the %s directive of the unix section can't be overridden", d.key)
+			}
+		}
+	}
+	return nil
+}
+
+// mergeVPPStartupConfig merges the override into the generated VPP startup configuration. The directives of the
+// override replace the directives with the same key in the same section, and the other directives and sections are
+// appended. The override must have been validated.
+func mergeVPPStartupConfig(generated, override string) string {
+	sections, err := parseVPPStartupConfig(generated)
+	if err != nil {
+		// The generated configuration always parses.
+		panic(err)
+	}
+	overrides, err := parseVPPStartupConfig(override)
+	if err != nil {
+		return generated
+	}
+
+	for _, o := range overrides {
+		i := 0
+		for i < len(sections) && sections[i].name != o.name {
+			i++
+		}
+		if i == len(sections) {
+			sections = append(sections, o)
+			continue
+		}
+		for _, d := range o.directives {
+			j := 0
+			for j < len(sections[i].directives) && sections[i].directives[j].key != d.key {
+				j++
+			}
+			if j == len(sections[i].directives) {
+				sections[i].directives = append(sections[i].directives, d)
+			} else {
+				sections[i].directives[j] = d
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, s := range sections {
+		fmt.Fprintf(&b, "%s {\n", s.name)
+		for _, d := range s.directives {
+			fmt.Fprintf(&b, "  %s\n", d.text)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// parseVPPStartupConfig parses the sections of a VPP startup configuration.
+func parseVPPStartupConfig(conf string) ([]vppConfigSection, error) {
+	var sections []vppConfigSection
+	rest := strings.TrimSpace(conf)
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			return nil, fmt.Errorf("expected a section, found %q", rest)
+		}
+		name := strings.TrimSpace(rest[:open])
+		if len(strings.Fields(name)) != 1 || strings.ContainsRune(name, '}') {
+			return nil, fmt.Errorf("invalid section name %q", name)
+		}
+		end := matchingBrace(rest, open)
+		if end < 0 {
+			return nil, fmt.Errorf("section %s is not closed", name)
+		}
+		directives, err := parseVPPDirectives(rest[open+1 : end])
+		if err != nil {
+			return nil, fmt.Errorf("section %s: %w", name, err)
+		}
+		sections = append(sections, vppConfigSection{name: name, directives: directives})
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+	return sections, nil
+}
+
+// parseVPPDirectives parses the directives of the body of a section. A directive ends with its line, or with its
+// block.
+func parseVPPDirectives(body string) ([]vppConfigDirective, error) {
+	var directives []vppConfigDirective
+	rest := strings.TrimSpace(body)
+	for rest != "" {
+		eol := strings.IndexByte(rest, '\n')
+		if eol < 0 {
+			eol = len(rest)
+		}
+		if open := strings.IndexByte(rest[:eol], '{'); open >= 0 {
+			end := matchingBrace(rest, open)
+			if end < 0 {
+				return nil, fmt.Errorf("block %q is not closed", strings.TrimSpace(rest[:open]))
+			}
+			key := strings.Join(strings.Fields(rest[:open]), " ")
+			if key == "" {
+				return nil, fmt.Errorf("block without a name")
+			}
+			directives = append(directives, vppConfigDirective{key: key, text: strings.TrimSpace(rest[:end+1])})
+			rest = strings.TrimSpace(rest[end+1:])
+			continue
+		}
+		line := strings.TrimSpace(rest[:eol])
+		if strings.ContainsRune(line, '}') {
+			return nil, fmt.Errorf("unexpected } in %q", line)
+		}
+		directives = append(directives, vppConfigDirective{key: strings.Fields(line)[0], text: line})
+		rest = strings.TrimSpace(rest[eol:])
+	}
+	return directives, nil
+}
+
+// matchingBrace returns the index of the brace closing the brace at the given index, or -1 if it isn't closed.
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/podsecuritycontext"
+	"github.com/tigera/operator/pkg/render/common/secret"
+)
+
+const (
+	// GoldmaneName is the name of the Deployment, the Service and the ServiceAccount of Goldmane.
+	GoldmaneName = "goldmane"
+	// GoldmanePort is the port on which Goldmane receives the flows of Felix and serves them to Whisker.
+	GoldmanePort = 7443
+	// WhiskerName is the name of the Deployment, the Service and the ServiceAccount of Whisker.
+	WhiskerName = "whisker"
+	// WhiskerPort is the port of the Whisker UI.
+	WhiskerPort = 8081
+
+	// GoldmaneKeyPairSecret is the name of the Secret with the serving certificate of Goldmane, signed by the operator CA.
+	GoldmaneKeyPairSecret = "goldmane-key-pair"
+	// WhiskerBackendKeyPairSecret is the name of the Secret with the client certificate of the Whisker backend, signed by
+	// the operator CA.
+	WhiskerBackendKeyPairSecret = "whisker-backend-key-pair"
+
+	whiskerBackendName = "whisker-backend"
+	whiskerBackendPort = 3002
+
+	// The CA bundle trusted by Goldmane and the Whisker backend: the operator CA, which signs their certificates, and
+	// the Typha CA, which signs the client certificate Felix reports the flows with.
+	goldmaneCABundleName  = "goldmane-ca-bundle"
+	goldmaneCABundleKey   = "ca-bundle.crt"
+	goldmaneCABundleMount = "/etc/pki/goldmane"
+
+	goldmaneKeyPairHash       = "hash.operator.tigera.io/goldmane-key-pair"
+	whiskerBackendKeyPairHash = "hash.operator.tigera.io/whisker-backend-key-pair"
+	goldmaneCABundleHash      = "hash.operator.tigera.io/goldmane-ca-bundle"
+)
+
+// WhiskerConfiguration contains all the config information needed to render Goldmane and Whisker.
+type WhiskerConfiguration struct {
+	Installation  *operatorv1.InstallationSpec
+	Whisker       *operatorv1.Whisker
+	ClusterDomain string
+
+	// The key pairs of Goldmane and the Whisker backend, in the operator namespace, and the CA bundle they trust.
+	GoldmaneKeyPair       *corev1.Secret
+	WhiskerBackendKeyPair *corev1.Secret
+	CABundle              string
+}
+
+// Whisker renders the flow visualization of the Calico variant: Goldmane, which aggregates the flows reported by
+// Felix, and the Whisker UI with its backend, which queries Goldmane. Both are only exposed inside the cluster, and
+// Goldmane only accepts the connections of the clients presenting a certificate signed by a CA of the bundle.
+func Whisker(cfg *WhiskerConfiguration) Component {
+	return &whiskerComponent{cfg: cfg}
+}
+
+type whiskerComponent struct {
+	cfg                 *WhiskerConfiguration
+	goldmaneImage       string
+	whiskerImage        string
+	whiskerBackendImage string
+}
+
+func (c *whiskerComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	reg := c.cfg.Installation.Registry
+	path := c.cfg.Installation.ImagePath
+	prefix := c.cfg.Installation.ImagePrefix
+	var err error
+	if c.goldmaneImage, err = components.GetReference(components.ComponentCalicoGoldmane, reg, path, prefix, is); err != nil {
+		return err
+	}
+	if c.whiskerImage, err = components.GetReference(components.ComponentCalicoWhisker, reg, path, prefix, is); err != nil {
+		return err
+	}
+	c.whiskerBackendImage, err = components.GetReference(components.ComponentCalicoWhiskerBackend, reg, path, prefix, is)
+	return err
+}
+
+func (c *whiskerComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeLinux
+}
+
+func (c *whiskerComponent) Objects() ([]client.Object, []client.Object) {
+	objs := []client.Object{c.caBundle()}
+	objs = append(objs, secret.ToRuntimeObjects(secret.CopyToNamespace(common.CalicoNamespace, c.cfg.GoldmaneKeyPair, c.cfg.WhiskerBackendKeyPair)...)...)
+	return append(objs,
+		whiskerServiceAccount(GoldmaneName),
+		c.goldmaneDeployment(),
+		whiskerService(GoldmaneName, GoldmanePort),
+		whiskerServiceAccount(WhiskerName),
+		c.whiskerDeployment(),
+		whiskerService(WhiskerName, WhiskerPort),
+	), nil
+}
+
+func (c *whiskerComponent) Ready() bool {
+	return true
+}
+
+func (c *whiskerComponent) caBundle() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: goldmaneCABundleName, Namespace: common.CalicoNamespace},
+		Data:       map[string]string{goldmaneCABundleKey: c.cfg.CABundle},
+	}
+}
+
+func (c *whiskerComponent) goldmaneDeployment() *appsv1.Deployment {
+	d := c.deployment(GoldmaneName, corev1.Container{
+		Name:  GoldmaneName,
+		Image: c.goldmaneImage,
+		Env: []corev1.EnvVar{
+			{Name: "LOG_LEVEL", Value: "INFO"},
+			{Name: "PORT", Value: fmt.Sprint(GoldmanePort)},
+			{Name: "SERVER_CERT_PATH", Value: whiskerKeyPairPath(GoldmaneKeyPairSecret, corev1.TLSCertKey)},
+			{Name: "SERVER_KEY_PATH", Value: whiskerKeyPairPath(GoldmaneKeyPairSecret, corev1.TLSPrivateKeyKey)},
+			{Name: "CA_CERT_PATH", Value: goldmaneCABundleMount + "/" + goldmaneCABundleKey},
+		},
+		Ports:           []corev1.ContainerPort{{ContainerPort: GoldmanePort, Protocol: corev1.ProtocolTCP}},
+		SecurityContext: podsecuritycontext.NewBaseContext(),
+		VolumeMounts:    []corev1.VolumeMount{whiskerKeyPairVolumeMount(GoldmaneKeyPairSecret), whiskerCABundleVolumeMount()},
+	})
+	d.Spec.Template.Annotations = map[string]string{
+		goldmaneKeyPairHash:  rmeta.AnnotationHash(c.cfg.GoldmaneKeyPair.Data),
+		goldmaneCABundleHash: rmeta.AnnotationHash(c.cfg.CABundle),
+	}
+	d.Spec.Template.Spec.Volumes = []corev1.Volume{whiskerKeyPairVolume(GoldmaneKeyPairSecret), whiskerCABundleVolume()}
+	return d
+}
+
+// whiskerDeployment returns the UI and its backend. The backend is reached by the UI through localhost.
+func (c *whiskerComponent) whiskerDeployment() *appsv1.Deployment {
+	notifications := operatorv1.WhiskerNotificationsEnabled
+	if n := c.cfg.Whisker.Spec.Notifications; n != nil {
+		notifications = *n
+	}
+	goldmane := fmt.Sprintf("%s.%s.svc.%s:%d", GoldmaneName, common.CalicoNamespace, c.cfg.ClusterDomain, GoldmanePort)
+	d := c.deployment(WhiskerName,
+		corev1.Container{
+			Name:  WhiskerName,
+			Image: c.whiskerImage,
+			Env: []corev1.EnvVar{
+				{Name: "LOG_LEVEL", Value: "INFO"},
+				{Name: "CALICO_VERSION", Value: components.CalicoRelease},
+				{Name: "NOTIFICATIONS", Value: string(notifications)},
+			},
+			Ports:           []corev1.ContainerPort{{ContainerPort: WhiskerPort, Protocol: corev1.ProtocolTCP}},
+			SecurityContext: podsecuritycontext.NewBaseContext(),
+		},
+		corev1.Container{
+			Name:  whiskerBackendName,
+			Image: c.whiskerBackendImage,
+			Env: []corev1.EnvVar{
+				{Name: "LOG_LEVEL", Value: "INFO"},
+				{Name: "PORT", Value: fmt.Sprint(whiskerBackendPort)},
+				{Name: "GOLDMANE_HOST", Value: goldmane},
+				{Name: "TLS_CERT_PATH", Value: whiskerKeyPairPath(WhiskerBackendKeyPairSecret, corev1.TLSCertKey)},
+				{Name: "TLS_KEY_PATH", Value: whiskerKeyPairPath(WhiskerBackendKeyPairSecret, corev1.TLSPrivateKeyKey)},
+				{Name: "CA_CERT_PATH", Value: goldmaneCABundleMount + "/" + goldmaneCABundleKey},
+			},
+			SecurityContext: podsecuritycontext.NewBaseContext(),
+			VolumeMounts:    []corev1.VolumeMount{whiskerKeyPairVolumeMount(WhiskerBackendKeyPairSecret), whiskerCABundleVolumeMount()},
+		},
+	)
+	d.Spec.Template.Annotations = map[string]string{
+		whiskerBackendKeyPairHash: rmeta.AnnotationHash(c.cfg.WhiskerBackendKeyPair.Data),
+		goldmaneCABundleHash:      rmeta.AnnotationHash(c.cfg.CABundle),
+	}
+	d.Spec.Template.Spec.Volumes = []corev1.Volume{whiskerKeyPairVolume(WhiskerBackendKeyPairSecret), whiskerCABundleVolume()}
+	return d
+}
+
+func (c *whiskerComponent) deployment(name string, containers ...corev1.Container) *appsv1.Deployment {
+	var replicas int32 = 1
+	labels := map[string]string{"k8s-app": name}
+	// Copy the tolerations of the Installation, so that appending doesn't modify its backing array.
+	tolerations := append(append([]corev1.Toleration{}, c.cfg.Installation.ControlPlaneTolerations...), rmeta.TolerateMaster, rmeta.TolerateCriticalAddonsOnly)
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.CalicoNamespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					NodeSelector:       c.cfg.Installation.ControlPlaneNodeSelector,
+					Tolerations:        tolerations,
+					ImagePullSecrets:   c.cfg.Installation.ImagePullSecrets,
+					ServiceAccountName: name,
+					Containers:         containers,
+				},
+			},
+		},
+	}
+}
+
+// whiskerKeyPairPath returns the path of a key of the given key pair Secret in the containers mounting it.
+func whiskerKeyPairPath(secretName, key string) string {
+	return fmt.Sprintf("/%s/%s", secretName, key)
+}
+
+func whiskerKeyPairVolume(secretName string) corev1.Volume {
+	return corev1.Volume{
+		Name:         secretName,
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secretName}},
+	}
+}
+
+func whiskerKeyPairVolumeMount(secretName string) corev1.VolumeMount {
+	return corev1.VolumeMount{Name: secretName, MountPath: "/" + secretName, ReadOnly: true}
+}
+
+func whiskerCABundleVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: goldmaneCABundleName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: goldmaneCABundleName}},
+		},
+	}
+}
+
+func whiskerCABundleVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{Name: goldmaneCABundleName, MountPath: goldmaneCABundleMount, ReadOnly: true}
+}
+
+func whiskerServiceAccount(name string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.CalicoNamespace},
+	}
+}
+
+func whiskerService(name string, port int) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.CalicoNamespace},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"k8s-app": name},
+			Type:     corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{{
+				Name:       name,
+				Port:       int32(port),
+				TargetPort: intstr.FromInt(port),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/components"
+	"github.com/tigera/operator/pkg/dns"
+	"github.com/tigera/operator/pkg/render"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("Whisker rendering tests", func() {
+	var cfg *render.WhiskerConfiguration
+	BeforeEach(func() {
+		cfg = &render.WhiskerConfiguration{
+			Installation:  &operatorv1.InstallationSpec{Variant: operatorv1.Calico, Registry: "test-reg/"},
+			Whisker:       &operatorv1.Whisker{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			ClusterDomain: dns.DefaultClusterDomain,
+			GoldmaneKeyPair: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: render.GoldmaneKeyPairSecret, Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{corev1.TLSCertKey: []byte("goldmane-cert"), corev1.TLSPrivateKeyKey: []byte("goldmane-key")},
+			},
+			WhiskerBackendKeyPair: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: render.WhiskerBackendKeyPairSecret, Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{corev1.TLSCertKey: []byte("backend-cert"), corev1.TLSPrivateKeyKey: []byte("backend-key")},
+			},
+			CABundle: "ca-bundle",
+		}
+	})
+
+	It("should render Goldmane and Whisker", func() {
+		component := render.Whisker(cfg)
+		Expect(component.ResolveImages(nil)).To(BeNil())
+		toCreate, toDelete := component.Objects()
+		Expect(toDelete).To(BeEmpty())
+
+		expectedResources := []struct {
+			name    string
+			ns      string
+			group   string
+			version string
+			kind    string
+		}{
+			{name: "goldmane-ca-bundle", ns: common.CalicoNamespace, group: "", version: "v1", kind: "ConfigMap"},
+			{name: render.GoldmaneKeyPairSecret, ns: common.CalicoNamespace, group: "", version: "v1", kind: "Secret"},
+			{name: render.WhiskerBackendKeyPairSecret, ns: common.CalicoNamespace, group: "", version: "v1", kind: "Secret"},
+			{name: render.GoldmaneName, ns: common.CalicoNamespace, group: "", version: "v1", kind: "ServiceAccount"},
+			{name: render.GoldmaneName, ns: common.CalicoNamespace, group: "apps", version: "v1", kind: "Deployment"},
+			{name: render.GoldmaneName, ns: common.CalicoNamespace, group: "", version: "v1", kind: "Service"},
+			{name: render.WhiskerName, ns: common.CalicoNamespace, group: "", version: "v1", kind: "ServiceAccount"},
+			{name: render.WhiskerName, ns: common.CalicoNamespace, group: "apps", version: "v1", kind: "Deployment"},
+			{name: render.WhiskerName, ns: common.CalicoNamespace, group: "", version: "v1", kind: "Service"},
+		}
+		Expect(toCreate).To(HaveLen(len(expectedResources)))
+		for i, expectedRes := range expectedResources {
+			rtest.ExpectResource(toCreate[i], expectedRes.name, expectedRes.ns, expectedRes.group, expectedRes.version, expectedRes.kind)
+		}
+
+		goldmane := rtest.GetResource(toCreate, render.GoldmaneName, common.CalicoNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(goldmane.Spec.Template.Spec.Containers).To(HaveLen(1))
+		Expect(goldmane.Spec.Template.Spec.Containers[0].Image).To(Equal(
+			fmt.Sprintf("test-reg/%s:%s", components.ComponentCalicoGoldmane.Image, components.ComponentCalicoGoldmane.Version)))
+
+		whisker := rtest.GetResource(toCreate, render.WhiskerName, common.CalicoNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(whisker.Spec.Template.Spec.Containers).To(HaveLen(2))
+		ui := rtest.GetContainer(whisker.Spec.Template.Spec.Containers, "whisker")
+		Expect(ui.Env).To(ContainElement(corev1.EnvVar{Name: "NOTIFICATIONS", Value: "Enabled"}))
+		backend := rtest.GetContainer(whisker.Spec.Template.Spec.Containers, "whisker-backend")
+		Expect(backend.Env).To(ContainElement(corev1.EnvVar{
+			Name:  "GOLDMANE_HOST",
+			Value: fmt.Sprintf("goldmane.calico-system.svc.%s:%d", dns.DefaultClusterDomain, render.GoldmanePort),
+		}))
+
+		svc := rtest.GetResource(toCreate, render.WhiskerName, common.CalicoNamespace, "", "v1", "Service").(*corev1.Service)
+		Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect(svc.Spec.Ports[0].Port).To(Equal(int32(render.WhiskerPort)))
+	})
+
+	It("should serve Goldmane with TLS and authenticate its clients", func() {
+		toCreate, _ := render.Whisker(cfg).Objects()
+
+		goldmane := rtest.GetResource(toCreate, render.GoldmaneName, common.CalicoNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(goldmane.Spec.Template.Spec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: "SERVER_CERT_PATH", Value: "/goldmane-key-pair/tls.crt"},
+			corev1.EnvVar{Name: "SERVER_KEY_PATH", Value: "/goldmane-key-pair/tls.key"},
+			corev1.EnvVar{Name: "CA_CERT_PATH", Value: "/etc/pki/goldmane/ca-bundle.crt"},
+		))
+		Expect(goldmane.Spec.Template.Spec.Volumes).To(HaveLen(2))
+		Expect(goldmane.Spec.Template.Spec.Volumes[0].Secret.SecretName).To(Equal(render.GoldmaneKeyPairSecret))
+		Expect(goldmane.Spec.Template.Spec.Volumes[1].ConfigMap.Name).To(Equal("goldmane-ca-bundle"))
+		Expect(goldmane.Spec.Template.Annotations).To(HaveKey("hash.operator.tigera.io/goldmane-key-pair"))
+
+		whisker := rtest.GetResource(toCreate, render.WhiskerName, common.CalicoNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		backend := rtest.GetContainer(whisker.Spec.Template.Spec.Containers, "whisker-backend")
+		Expect(backend.Env).To(ContainElements(
+			corev1.EnvVar{Name: "TLS_CERT_PATH", Value: "/whisker-backend-key-pair/tls.crt"},
+			corev1.EnvVar{Name: "TLS_KEY_PATH", Value: "/whisker-backend-key-pair/tls.key"},
+			corev1.EnvVar{Name: "CA_CERT_PATH", Value: "/etc/pki/goldmane/ca-bundle.crt"},
+		))
+		Expect(whisker.Spec.Template.Spec.Volumes[0].Secret.SecretName).To(Equal(render.WhiskerBackendKeyPairSecret))
+
+		bundle := rtest.GetResource(toCreate, "goldmane-ca-bundle", common.CalicoNamespace, "", "v1", "ConfigMap").(*corev1.ConfigMap)
+		Expect(bundle.Data).To(Equal(map[string]string{"ca-bundle.crt": "ca-bundle"}))
+	})
+
+	It("should not modify the control plane tolerations of the Installation", func() {
+		tolerations := make([]corev1.Toleration, 1, 4)
+		tolerations[0] = corev1.Toleration{Key: "foo", Operator: corev1.TolerationOpExists}
+		cfg.Installation.ControlPlaneTolerations = tolerations
+		toCreate, _ := render.Whisker(cfg).Objects()
+
+		goldmane := rtest.GetResource(toCreate, render.GoldmaneName, common.CalicoNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		goldmane.Spec.Template.Spec.Tolerations[1].Key = "modified"
+		whisker := rtest.GetResource(toCreate, render.WhiskerName, common.CalicoNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		Expect(whisker.Spec.Template.Spec.Tolerations[1]).To(Equal(rmeta.TolerateMaster))
+		Expect(tolerations[:2][1]).To(Equal(corev1.Toleration{}))
+	})
+
+	It("should disable the notifications of the UI", func() {
+		disabled := operatorv1.WhiskerNotificationsDisabled
+		cfg.Whisker.Spec.Notifications = &disabled
+		toCreate, _ := render.Whisker(cfg).Objects()
+
+		whisker := rtest.GetResource(toCreate, render.WhiskerName, common.CalicoNamespace, "apps", "v1", "Deployment").(*appsv1.Deployment)
+		ui := rtest.GetContainer(whisker.Spec.Template.Spec.Containers, "whisker")
+		Expect(ui.Env).To(ContainElement(corev1.EnvVar{Name: "NOTIFICATIONS", Value: "Disabled"}))
+	})
+})
@@ -0,0 +1,235 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package statusreport implements the status command of the operator, which prints the status of the installation,
+// of its components and of the dataplane of each node, for a quick triage.
+package statusreport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// nodeStatus is the dataplane status of a node. The restarts are the restarts of the containers of both its
+// calico-node and calico-vpp-node pods.
+type nodeStatus struct {
+	pod        string
+	vppPod     string
+	restarts   int32
+	encryption string
+	nat64      string
+}
+
+// Print prints the status of the installation, of the components reported in the TigeraStatuses, and of the dataplane
+// of each node.
+func Print(ctx context.Context, cli client.Client, w io.Writer) error {
+	installation := &operatorv1.Installation{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "default"}, installation); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		fmt.Fprintln(w, "Installation: not found")
+		installation = nil
+	} else {
+		printInstallation(w, installation)
+	}
+
+	statuses := &operatorv1.TigeraStatusList{}
+	if err := cli.List(ctx, statuses); err != nil {
+		return err
+	}
+	sort.Slice(statuses.Items, func(i, j int) bool { return statuses.Items[i].Name < statuses.Items[j].Name })
+	fmt.Fprintln(w)
+	if err := printComponents(w, statuses.Items); err != nil {
+		return err
+	}
+
+	nodes, err := nodeStatuses(ctx, cli, installation, statuses.Items)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	return printNodes(w, nodes)
+}
+
+func printInstallation(w io.Writer, installation *operatorv1.Installation) {
+	st := installation.Status
+	fmt.Fprintf(w, "Installation: %s, operator %s, revision %d, MTU %d\n", valueOrUnknown(string(st.Variant)),
+		valueOrUnknown(st.OperatorVersion), st.Revision, st.MTU)
+	if st.ImageSet != "" {
+		fmt.Fprintf(w, "ImageSet: %s\n", st.ImageSet)
+	}
+	if t := st.ConnectivityTest; t != nil {
+		msg := string(t.State)
+		if t.Message != "" {
+			msg += ": " + t.Message
+		}
+		fmt.Fprintf(w, "Connectivity test: %s\n", msg)
+	}
+}
+
+func printComponents(w io.Writer, statuses []operatorv1.TigeraStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "COMPONENT\tAVAILABLE\tPROGRESSING\tDEGRADED\tSINCE\tREASON")
+	for _, ts := range statuses {
+		conditions := map[operatorv1.StatusConditionType]operatorv1.TigeraStatusCondition{}
+		for _, c := range ts.Status.Conditions {
+			conditions[c.Type] = c
+		}
+		available := conditions[operatorv1.ComponentAvailable]
+		progressing := conditions[operatorv1.ComponentProgressing]
+		degraded := conditions[operatorv1.ComponentDegraded]
+
+		// The reason of a degraded component takes precedence over the reason it is progressing.
+		reason := ""
+		for _, c := range []operatorv1.TigeraStatusCondition{progressing, degraded} {
+			if c.Status == operatorv1.ConditionTrue && (c.Reason != "" || c.Message != "") {
+				reason = strings.TrimSuffix(c.Reason+": "+c.Message, ": ")
+			}
+		}
+		since := "-"
+		if !available.LastTransitionTime.IsZero() {
+			since = available.LastTransitionTime.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", ts.Name, conditionStatus(available), conditionStatus(progressing),
+			conditionStatus(degraded), since, reason)
+	}
+	return tw.Flush()
+}
+
+// nodeStatuses returns the dataplane status of the nodes running calico-node or calico-vpp-node, along with the
+// encryption and the NAT64 status of the nodes they are reported for, by the name of the node.
+func nodeStatuses(ctx context.Context, cli client.Client, installation *operatorv1.Installation, statuses []operatorv1.TigeraStatus) (map[string]*nodeStatus, error) {
+	nodes := map[string]*nodeStatus{}
+	node := func(name string) *nodeStatus {
+		if nodes[name] == nil {
+			nodes[name] = &nodeStatus{pod: "-", vppPod: "-", encryption: "-", nat64: "-"}
+		}
+		return nodes[name]
+	}
+
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(common.CalicoNamespace), client.MatchingLabels{"k8s-app": common.NodeDaemonSetName}); err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		n := node(pod.Spec.NodeName)
+		n.pod = podStatus(&pod)
+		n.restarts += podRestarts(&pod)
+	}
+
+	// The pods of all the calico-vpp-node DaemonSets, including the ones of the VPP profiles, carry the
+	// VPPNodePodLabel.
+	vppPods := &corev1.PodList{}
+	if err := cli.List(ctx, vppPods, client.InNamespace(common.CalicoNamespace), client.HasLabels{render.VPPNodePodLabel}); err != nil {
+		return nil, err
+	}
+	for _, pod := range vppPods.Items {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		n := node(pod.Spec.NodeName)
+		n.vppPod = podStatus(&pod)
+		n.restarts += podRestarts(&pod)
+	}
+
+	for _, ts := range statuses {
+		if ts.Status.Encryption == nil {
+			continue
+		}
+		for _, e := range ts.Status.Encryption.Nodes {
+			if e.Error != "" {
+				node(e.Name).encryption = "Error: " + e.Error
+				continue
+			}
+			node(e.Name).encryption = fmt.Sprintf("%s, %d peers, %d unencrypted", ts.Status.Encryption.Type, e.EstablishedPeers, e.UnencryptedPeers)
+		}
+	}
+
+	if installation != nil {
+		for _, s := range installation.Status.NAT64 {
+			if s.Ready {
+				node(s.Name).nat64 = "Ready"
+			} else {
+				node(s.Name).nat64 = strings.TrimSuffix("NotReady: "+s.Message, ": ")
+			}
+		}
+	}
+	return nodes, nil
+}
+
+func printNodes(w io.Writer, nodes map[string]*nodeStatus) error {
+	var names []string
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tCALICO-NODE\tCALICO-VPP-NODE\tRESTARTS\tENCRYPTION\tNAT64")
+	for _, name := range names {
+		n := nodes[name]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n", name, n.pod, n.vppPod, n.restarts, n.encryption, n.nat64)
+	}
+	return tw.Flush()
+}
+
+// podStatus returns Ready if the pod is ready, or its phase otherwise.
+func podStatus(pod *corev1.Pod) string {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			return "Ready"
+		}
+	}
+	if pod.Status.Phase == corev1.PodRunning {
+		return "NotReady"
+	}
+	return valueOrUnknown(string(pod.Status.Phase))
+}
+
+// podRestarts returns the number of restarts of the containers of the pod.
+func podRestarts(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, cs := range pod.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	return restarts
+}
+
+func conditionStatus(c operatorv1.TigeraStatusCondition) string {
+	return valueOrUnknown(string(c.Status))
+}
+
+func valueOrUnknown(v string) string {
+	if v == "" {
+		return "Unknown"
+	}
+	return v
+}
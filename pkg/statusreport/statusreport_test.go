@@ -0,0 +1,127 @@
+// Copyright (c) 2021 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statusreport_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/statusreport"
+)
+
+var _ = Describe("Status report", func() {
+	var (
+		cli client.Client
+		ctx context.Context
+	)
+
+	// fields returns the fields of the line of the output starting with the given name.
+	fields := func(out, name string) []string {
+		for _, line := range strings.Split(out, "\n") {
+			if f := strings.Fields(line); len(f) > 0 && f[0] == name {
+				return f
+			}
+		}
+		return nil
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		cli = fake.NewClientBuilder().WithScheme(scheme).Build()
+		ctx = context.Background()
+	})
+
+	It("should report a missing Installation", func() {
+		var out bytes.Buffer
+		Expect(statusreport.Print(ctx, cli, &out)).NotTo(HaveOccurred())
+		Expect(out.String()).To(HavePrefix("Installation: not found\n"))
+		Expect(out.String()).To(ContainSubstring("COMPONENT"))
+		Expect(out.String()).To(ContainSubstring("NODE"))
+	})
+
+	It("should report the components and the dataplane of the nodes", func() {
+		Expect(cli.Create(ctx, &operatorv1.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Status: operatorv1.InstallationStatus{
+				Variant:         operatorv1.Calico,
+				OperatorVersion: "v1.23.0",
+				Revision:        3,
+				MTU:             1450,
+				NAT64: []operatorv1.NodeNAT64Status{
+					{Name: "node-a", Ready: true},
+					{Name: "node-b", Message: "no route"},
+				},
+			},
+		})).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &operatorv1.TigeraStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: "calico"},
+			Status: operatorv1.TigeraStatusStatus{
+				Conditions: []operatorv1.TigeraStatusCondition{
+					{Type: operatorv1.ComponentAvailable, Status: operatorv1.ConditionFalse},
+					{Type: operatorv1.ComponentProgressing, Status: operatorv1.ConditionTrue, Reason: "Rolling"},
+					{Type: operatorv1.ComponentDegraded, Status: operatorv1.ConditionTrue, Reason: "ResourceNotReady", Message: "calico-node is crashing"},
+				},
+				Encryption: &operatorv1.EncryptionStatus{
+					Type: operatorv1.EncryptionWireGuard,
+					Nodes: []operatorv1.NodeEncryptionStatus{
+						{Name: "node-a", EstablishedPeers: 1},
+					},
+				},
+			},
+		})).NotTo(HaveOccurred())
+		Expect(cli.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-node-a", Namespace: common.CalicoNamespace, Labels: map[string]string{"k8s-app": common.NodeDaemonSetName}},
+			Spec:       corev1.PodSpec{NodeName: "node-a"},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "calico-node", RestartCount: 2}},
+			},
+		})).NotTo(HaveOccurred())
+
+		Expect(cli.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "calico-vpp-node-b", Namespace: common.CalicoNamespace, Labels: map[string]string{render.VPPNodePodLabel: "true"}},
+			Spec:       corev1.PodSpec{NodeName: "node-b"},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "vpp", RestartCount: 1}, {Name: "agent", RestartCount: 3}},
+			},
+		})).NotTo(HaveOccurred())
+
+		var out bytes.Buffer
+		Expect(statusreport.Print(ctx, cli, &out)).NotTo(HaveOccurred())
+		Expect(out.String()).To(HavePrefix("Installation: Calico, operator v1.23.0, revision 3, MTU 1450\n"))
+		Expect(out.String()).To(ContainSubstring("ResourceNotReady: calico-node is crashing"))
+		Expect(fields(out.String(), "calico")[:5]).To(Equal([]string{"calico", "False", "True", "True", "-"}))
+		Expect(fields(out.String(), "node-a")).To(Equal([]string{"node-a", "Ready", "-", "2", "WireGuard,", "1", "peers,", "0", "unencrypted", "Ready"}))
+		Expect(fields(out.String(), "node-b")).To(Equal([]string{"node-b", "-", "NotReady", "4", "-", "NotReady:", "no", "route"}))
+	})
+})